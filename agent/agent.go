@@ -53,6 +53,7 @@ type Agent struct {
 	smartManager              *SmartManager                                         // Manages SMART data
 	systemdManager            *systemdManager                                       // Manages systemd services
 	dataCleanupJobs           *dataCleanupJobManager                                // 数据清理后台任务
+	dataCleanupEnabled        bool                                                  // DATA_CLEANUP_ENABLED：允许未携带确认标记的清理请求
 }
 
 // NewAgent creates a new agent with the given data directory for persisting data.
@@ -130,6 +131,7 @@ func NewAgent(dataDir ...string) (agent *Agent, err error) {
 	// initialize handler registry
 	agent.handlerRegistry = NewHandlerRegistry()
 	agent.dataCleanupJobs = newDataCleanupJobManager()
+	agent.dataCleanupEnabled = dataCleanupEnabledFromEnv()
 
 	// initialize disk info
 	agent.initializeDiskInfo()
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
@@ -117,10 +118,27 @@ func (client *WebSocketClient) getOptions() *gws.ClientOption {
 			"X-Token":    []string{client.token},
 			"X-Aether":   []string{aether.Version},
 		},
+		// offer permessage-deflate; the hub decides whether to accept it
+		PermessageDeflate: gws.PermessageDeflate{
+			Enabled:               wsCompressionEnabled(),
+			ServerContextTakeover: true,
+			ClientContextTakeover: true,
+		},
 	}
 	return client.options
 }
 
+// wsCompressionEnabled reports whether the agent offers WebSocket compression.
+// Set WS_COMPRESSION=false to disable it.
+func wsCompressionEnabled() bool {
+	value, ok := GetEnv("WS_COMPRESSION")
+	if !ok {
+		return true
+	}
+	enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+	return err != nil || enabled
+}
+
 // Connect establishes a WebSocket connection to the hub.
 // It closes any existing connection before attempting to reconnect.
 func (client *WebSocketClient) Connect() (err error) {
@@ -358,6 +376,16 @@ func (client *WebSocketClient) sendResponse(data any, requestID *uint32) error {
 			response.DockerComposeProjects = v
 		case *dockermodel.DaemonConfig:
 			response.DockerConfig = v
+		case *dockermodel.ContainerDiff:
+			response.DockerContainerDiff = v
+		case *dockermodel.ComposeLogs:
+			response.DockerComposeLogs = v
+		case *dockermodel.ImagePruneReport:
+			response.DockerImagePrune = v
+		case *dockermodel.ContainerStats:
+			response.DockerContainerStats = v
+		case *dockermodel.ExecResult:
+			response.DockerContainerExec = v
 		case []repo.Source:
 			response.RepoSources = v
 		case *common.DockerDataCleanupList:
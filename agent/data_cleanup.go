@@ -4,6 +4,7 @@ package agent
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -16,6 +17,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"aether/internal/common"
@@ -33,8 +35,20 @@ const (
 	dataCleanupActionTimeout      = 30 * time.Minute
 	dataCleanupScanCount          = 500
 	dataCleanupMinioProgressBatch = 5000
+	// dataCleanupMinioConcurrency is the default number of prefixes cleaned in parallel.
+	dataCleanupMinioConcurrency = 4
+	// dataCleanupMatchCountTimeout bounds read-only match counting. It stays below the
+	// hub's list timeout so a partial count can still be returned on large datasets.
+	dataCleanupMatchCountTimeout = 15 * time.Second
 )
 
+// dataCleanupRedactedValue replaces credentials in data cleanup errors.
+const dataCleanupRedactedValue = common.RedactedValue
+
+// errDataCleanupNotConfirmed is returned when a destructive cleanup request arrives without
+// an explicit confirmation and the agent was not started with DATA_CLEANUP_ENABLED=true.
+var errDataCleanupNotConfirmed = errors.New("data cleanup refused: request is not confirmed and DATA_CLEANUP_ENABLED is not set on the agent")
+
 type dataCleanupIndexItem struct {
 	Index string `json:"index"`
 }
@@ -45,19 +59,84 @@ type dataCleanupDeleteResponse struct {
 	Error    json.RawMessage `json:"error"`
 }
 
+type dataCleanupCountResponse struct {
+	Count int64 `json:"count"`
+}
+
+type dataCleanupAcknowledgedResponse struct {
+	Acknowledged bool `json:"acknowledged"`
+}
+
+// dataCleanupEnabledFromEnv reads DATA_CLEANUP_ENABLED. When "true", the agent performs
+// deletes even if the request lacks Confirm; otherwise (the default safe mode) every
+// destructive cleanup request must carry Confirm=true. Listing and counting are unaffected.
+func dataCleanupEnabledFromEnv() bool {
+	value, _ := GetEnv("DATA_CLEANUP_ENABLED")
+	enabled, _ := strconv.ParseBool(strings.TrimSpace(value))
+	return enabled
+}
+
+// requireDataCleanupConfirm enforces the agent-side safe mode for destructive cleanup requests.
+func (a *Agent) requireDataCleanupConfirm(module string, confirm bool) error {
+	if confirm || a.dataCleanupEnabled {
+		return nil
+	}
+	slog.Warn("data cleanup refused without confirmation", "module", module)
+	return formatDataCleanupError("data cleanup not confirmed", errDataCleanupNotConfirmed, map[string]any{"module": module})
+}
+
+// formatDataCleanupError wraps err with its context and fields. Credentials are masked in both the
+// error text and the fields, so the result is safe to log and to return to the hub.
 func formatDataCleanupError(context string, err error, fields map[string]any) error {
 	return fmt.Errorf(
-		"%s | errType=%T | err=%v | fields=%v | stack=%s",
+		"%s | errType=%T | err=%s | fields=%v | stack=%s",
 		context,
 		err,
-		err,
-		fields,
+		redactDataCleanupText(fmt.Sprint(err)),
+		sanitizeDataCleanupFields(fields),
 		string(debug.Stack()),
 	)
 }
 
+// redactDataCleanupText masks credentials with the same rules the hub applies to its logs.
+func redactDataCleanupText(text string) string {
+	return common.RedactText(text)
+}
+
+// sanitizeDataCleanupFields returns a copy of fields with credentials masked. A value under a
+// credential key is replaced outright; other composite values such as a whole request struct are
+// rendered with their field names so the credential fields inside them can be masked too.
+func sanitizeDataCleanupFields(fields map[string]any) map[string]any {
+	sanitized := make(map[string]any, len(fields))
+	for key, value := range fields {
+		if common.IsRedactKey(key) {
+			sanitized[key] = dataCleanupRedactedValue
+			continue
+		}
+		switch v := value.(type) {
+		case nil, bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+			sanitized[key] = v
+		case string:
+			sanitized[key] = redactDataCleanupText(v)
+		case error:
+			sanitized[key] = redactDataCleanupText(v.Error())
+		default:
+			sanitized[key] = redactDataCleanupText(fmt.Sprintf("%+v", v))
+		}
+	}
+	return sanitized
+}
+
 func encodeDataCleanupJobStatusDetail(snapshot dataCleanupJobSnapshot) (string, error) {
-	detail := common.DataCleanupJobStatusDetail{
+	encoded, err := json.Marshal(dataCleanupJobStatusDetail(snapshot))
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func dataCleanupJobStatusDetail(snapshot dataCleanupJobSnapshot) common.DataCleanupJobStatusDetail {
+	return common.DataCleanupJobStatusDetail{
 		JobID:   snapshot.JobID,
 		Module:  snapshot.Module,
 		Status:  snapshot.Status,
@@ -66,12 +145,11 @@ func encodeDataCleanupJobStatusDetail(snapshot dataCleanupJobSnapshot) (string,
 		Total:   snapshot.Total,
 		Seq:     snapshot.Seq,
 		Error:   snapshot.Error,
+
+		Skipped:     snapshot.Skipped,
+		Dropped:     snapshot.Dropped,
+		ItemDeleted: snapshot.ItemDeleted,
 	}
-	encoded, err := json.Marshal(detail)
-	if err != nil {
-		return "", err
-	}
-	return string(encoded), nil
 }
 
 func requireHostPort(host string, port int, fields map[string]any) (string, error) {
@@ -85,6 +163,18 @@ func requireHostPort(host string, port int, fields map[string]any) (string, erro
 	return net.JoinHostPort(trimmed, strconv.Itoa(port)), nil
 }
 
+// dataCleanupTLSConfig returns the client TLS config for a data source, or nil for a plain connection.
+func dataCleanupTLSConfig(opts common.DataCleanupTLS, host string) *tls.Config {
+	if !opts.UseTLS {
+		return nil
+	}
+	return &tls.Config{
+		ServerName:         strings.TrimSpace(host),
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+}
+
 func newMySQLConfig(
 	req common.DataCleanupMySQLDatabasesRequest,
 	dbName string,
@@ -104,14 +194,17 @@ func newMySQLConfig(
 	cfg.Timeout = timeout
 	cfg.ReadTimeout = timeout
 	cfg.WriteTimeout = timeout
+	cfg.TLS = dataCleanupTLSConfig(req.TLS, req.Host)
 	return cfg, nil
 }
 
 func openMySQL(ctx context.Context, cfg *mysql.Config) (*sql.DB, error) {
-	db, err := sql.Open("mysql", cfg.FormatDSN())
+	// the connector keeps cfg.TLS, which a DSN round trip would drop
+	connector, err := mysql.NewConnector(cfg)
 	if err != nil {
 		return nil, formatDataCleanupError("open mysql failed", err, map[string]any{"addr": cfg.Addr, "db": cfg.DBName})
 	}
+	db := sql.OpenDB(connector)
 	db.SetMaxOpenConns(2)
 	db.SetMaxIdleConns(2)
 	db.SetConnMaxLifetime(5 * time.Minute)
@@ -123,7 +216,7 @@ func openMySQL(ctx context.Context, cfg *mysql.Config) (*sql.DB, error) {
 }
 
 func listMySQLDatabases(ctx context.Context, req common.DataCleanupMySQLDatabasesRequest) ([]string, error) {
-	cfg, err := newMySQLConfig(req, "", dataCleanupListTimeout)
+	cfg, err := newMySQLConfig(req, "", common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +255,8 @@ func listMySQLTables(ctx context.Context, req common.DataCleanupMySQLTablesReque
 		Port:     req.Port,
 		Username: req.Username,
 		Password: req.Password,
-	}, req.Database, dataCleanupListTimeout)
+		TLS:      req.TLS,
+	}, req.Database, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -210,13 +304,59 @@ func escapeMySQLIdentifier(name string) (string, error) {
 	return fmt.Sprintf("`%s`", escaped), nil
 }
 
+// mysqlTableConditions validates the per-table conditions and indexes them by table name.
+func mysqlTableConditions(conditions []common.DataCleanupMySQLCondition) (map[string]common.DataCleanupMySQLCondition, error) {
+	indexed := make(map[string]common.DataCleanupMySQLCondition, len(conditions))
+	for _, condition := range conditions {
+		if err := condition.Validate(); err != nil {
+			return nil, formatDataCleanupError("invalid mysql condition", err, map[string]any{"table": condition.Table})
+		}
+		table := strings.TrimSpace(condition.Table)
+		if _, ok := indexed[table]; ok {
+			return nil, formatDataCleanupError("invalid mysql condition", errors.New("duplicate condition"), map[string]any{"table": table})
+		}
+		indexed[table] = condition
+	}
+	return indexed, nil
+}
+
+// mysqlTableQuery appends the table's condition to statement as a WHERE clause and returns the
+// values to bind to its placeholders.
+func mysqlTableQuery(statement string, conditions map[string]common.DataCleanupMySQLCondition, table string) (string, []any) {
+	condition, ok := conditions[strings.TrimSpace(table)]
+	if !ok {
+		return statement, nil
+	}
+	args := make([]any, 0, len(condition.Args))
+	for _, arg := range condition.Args {
+		args = append(args, arg)
+	}
+	return fmt.Sprintf("%s WHERE %s", statement, strings.TrimSpace(condition.Where)), args
+}
+
+// deleteMySQLTables empties the requested tables with foreign key checks disabled. The default delete
+// mode runs DELETE FROM in one transaction, restricted to matching rows for tables with a condition;
+// truncate mode runs TRUNCATE TABLE, which MySQL commits implicitly per table, and reports 0 deleted
+// rows since the count is unknown.
 func deleteMySQLTables(ctx context.Context, req common.DataCleanupMySQLDeleteTablesRequest) (int64, error) {
+	mode, err := common.NormalizeDataCleanupMySQLMode(req.Mode)
+	if err != nil {
+		return 0, formatDataCleanupError("invalid mysql cleanup mode", err, map[string]any{"mode": req.Mode})
+	}
+	conditions, err := mysqlTableConditions(req.Conditions)
+	if err != nil {
+		return 0, err
+	}
+	if mode == common.DataCleanupMySQLModeTruncate && len(conditions) > 0 {
+		return 0, formatDataCleanupError("invalid mysql cleanup mode", errors.New("conditions require delete mode"), map[string]any{"mode": mode})
+	}
 	cfg, err := newMySQLConfig(common.DataCleanupMySQLDatabasesRequest{
 		Host:     req.Host,
 		Port:     req.Port,
 		Username: req.Username,
 		Password: req.Password,
-	}, req.Database, dataCleanupActionTimeout)
+		TLS:      req.TLS,
+	}, req.Database, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	if err != nil {
 		return 0, err
 	}
@@ -232,6 +372,10 @@ func deleteMySQLTables(ctx context.Context, req common.DataCleanupMySQLDeleteTab
 	}
 	defer db.Close()
 
+	if mode == common.DataCleanupMySQLModeTruncate {
+		return 0, truncateMySQLTables(ctx, db, cfg, req.Tables)
+	}
+
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, formatDataCleanupError("begin mysql transaction failed", err, map[string]any{"addr": cfg.Addr, "db": cfg.DBName})
@@ -250,7 +394,8 @@ func deleteMySQLTables(ctx context.Context, req common.DataCleanupMySQLDeleteTab
 		if err != nil {
 			return 0, err
 		}
-		result, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", escaped))
+		query, args := mysqlTableQuery("DELETE FROM "+escaped, conditions, table)
+		result, err := tx.ExecContext(ctx, query, args...)
 		if err != nil {
 			return 0, formatDataCleanupError("delete mysql table failed", err, map[string]any{"addr": cfg.Addr, "db": cfg.DBName, "table": table})
 		}
@@ -267,16 +412,85 @@ func deleteMySQLTables(ctx context.Context, req common.DataCleanupMySQLDeleteTab
 	return deleted, nil
 }
 
+// truncateMySQLTables runs TRUNCATE TABLE for each table on a single connection, so the session-level
+// foreign key setting applies to every statement. The pool is closed by the caller afterwards.
+func truncateMySQLTables(ctx context.Context, db *sql.DB, cfg *mysql.Config, tables []string) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return formatDataCleanupError("open mysql connection failed", err, map[string]any{"addr": cfg.Addr, "db": cfg.DBName})
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS=0"); err != nil {
+		return formatDataCleanupError("disable foreign key checks failed", err, map[string]any{"addr": cfg.Addr, "db": cfg.DBName})
+	}
+	for _, table := range tables {
+		escaped, err := escapeMySQLIdentifier(table)
+		if err != nil {
+			return err
+		}
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("TRUNCATE TABLE %s", escaped)); err != nil {
+			return formatDataCleanupError("truncate mysql table failed", err, map[string]any{"addr": cfg.Addr, "db": cfg.DBName, "table": table})
+		}
+	}
+	return nil
+}
+
+// countMySQLTables counts the rows deleteMySQLTables would delete, without modifying the tables.
+func countMySQLTables(ctx context.Context, req common.DataCleanupMySQLDeleteTablesRequest) (int64, error) {
+	conditions, err := mysqlTableConditions(req.Conditions)
+	if err != nil {
+		return 0, err
+	}
+	cfg, err := newMySQLConfig(common.DataCleanupMySQLDatabasesRequest{
+		Host:     req.Host,
+		Port:     req.Port,
+		Username: req.Username,
+		Password: req.Password,
+		TLS:      req.TLS,
+	}, req.Database, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
+	if err != nil {
+		return 0, err
+	}
+	if strings.TrimSpace(req.Database) == "" {
+		return 0, formatDataCleanupError("database is required", errors.New("database is required"), map[string]any{"addr": cfg.Addr})
+	}
+	if len(req.Tables) == 0 {
+		return 0, formatDataCleanupError("tables are required", errors.New("tables are required"), map[string]any{"addr": cfg.Addr, "db": cfg.DBName})
+	}
+	db, err := openMySQL(ctx, cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	var matched int64
+	for _, table := range req.Tables {
+		escaped, err := escapeMySQLIdentifier(table)
+		if err != nil {
+			return 0, err
+		}
+		query, args := mysqlTableQuery("SELECT COUNT(*) FROM "+escaped, conditions, table)
+		var rows int64
+		if err := db.QueryRowContext(ctx, query, args...).Scan(&rows); err != nil {
+			return 0, formatDataCleanupError("count mysql table failed", err, map[string]any{"addr": cfg.Addr, "db": cfg.DBName, "table": table})
+		}
+		matched += rows
+	}
+	return matched, nil
+}
+
 func newRedisClient(req common.DataCleanupRedisDatabasesRequest, db int) (*redis.Client, error) {
 	addr, err := requireHostPort(req.Host, req.Port, map[string]any{"host": req.Host, "port": req.Port})
 	if err != nil {
 		return nil, err
 	}
 	opts := &redis.Options{
-		Addr:     addr,
-		Username: strings.TrimSpace(req.Username),
-		Password: req.Password,
-		DB:       db,
+		Addr:      addr,
+		Username:  strings.TrimSpace(req.Username),
+		Password:  req.Password,
+		DB:        db,
+		TLSConfig: dataCleanupTLSConfig(req.TLS, req.Host),
 	}
 	return redis.NewClient(opts), nil
 }
@@ -398,39 +612,58 @@ func listRedisDatabases(ctx context.Context, req common.DataCleanupRedisDatabase
 	return dbs, nil
 }
 
-func cleanupRedis(ctx context.Context, req common.DataCleanupRedisCleanupRequest) (int64, error) {
+// cleanupRedis deletes the keys matching req.Patterns and returns the number deleted and the
+// number of matching keys req.Filter left in place.
+func cleanupRedis(ctx context.Context, req common.DataCleanupRedisCleanupRequest) (int64, int64, error) {
 	if len(req.Patterns) == 0 {
-		return 0, formatDataCleanupError("redis patterns required", errors.New("patterns are required"), map[string]any{"host": req.Host, "port": req.Port})
+		return 0, 0, formatDataCleanupError("redis patterns required", errors.New("patterns are required"), map[string]any{"host": req.Host, "port": req.Port})
 	}
+	return scanRedisPatterns(ctx, req, true)
+}
+
+// scanRedisPatterns scans the keys matching req.Patterns, drops those failing req.Filter and
+// deletes the rest when remove is set. It returns the number of keys deleted (or that would be)
+// and the number skipped by the filter.
+func scanRedisPatterns(ctx context.Context, req common.DataCleanupRedisCleanupRequest, remove bool) (int64, int64, error) {
 	client, err := newRedisClient(common.DataCleanupRedisDatabasesRequest{
 		Host:     req.Host,
 		Port:     req.Port,
 		Username: req.Username,
 		Password: req.Password,
+		TLS:      req.TLS,
 	}, req.DB)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 	defer client.Close()
 
 	if err := client.Ping(ctx).Err(); err != nil {
-		return 0, formatDataCleanupError("ping redis failed", err, map[string]any{"host": req.Host, "port": req.Port, "db": req.DB})
+		return 0, 0, formatDataCleanupError("ping redis failed", err, map[string]any{"host": req.Host, "port": req.Port, "db": req.DB})
 	}
 
-	var deleted int64
+	var affected, skipped int64
 	for _, pattern := range req.Patterns {
 		cursor := uint64(0)
 		for {
 			keys, nextCursor, err := client.Scan(ctx, cursor, pattern, dataCleanupScanCount).Result()
 			if err != nil {
-				return deleted, formatDataCleanupError("redis scan failed", err, map[string]any{"host": req.Host, "port": req.Port, "db": req.DB, "pattern": pattern})
+				return affected, skipped, formatDataCleanupError("redis scan failed", err, map[string]any{"host": req.Host, "port": req.Port, "db": req.DB, "pattern": pattern})
+			}
+			keys, filtered, err := filterRedisKeys(ctx, client, keys, req.Filter)
+			if err != nil {
+				return affected, skipped, formatDataCleanupError("redis filter check failed", err, map[string]any{"host": req.Host, "port": req.Port, "db": req.DB, "pattern": pattern})
 			}
+			skipped += filtered
 			if len(keys) > 0 {
-				count, err := client.Del(ctx, keys...).Result()
-				if err != nil {
-					return deleted, formatDataCleanupError("redis delete failed", err, map[string]any{"host": req.Host, "port": req.Port, "db": req.DB, "pattern": pattern})
+				if remove {
+					count, err := client.Del(ctx, keys...).Result()
+					if err != nil {
+						return affected, skipped, formatDataCleanupError("redis delete failed", err, map[string]any{"host": req.Host, "port": req.Port, "db": req.DB, "pattern": pattern})
+					}
+					affected += count
+				} else {
+					affected += int64(len(keys))
 				}
-				deleted += count
 			}
 			if nextCursor == 0 {
 				break
@@ -439,7 +672,121 @@ func cleanupRedis(ctx context.Context, req common.DataCleanupRedisCleanupRequest
 		}
 	}
 
-	return deleted, nil
+	return affected, skipped, nil
+}
+
+// filterRedisKeys returns the keys passing filter and the number that did not, reading TYPE and,
+// when a TTL bound is set, PTTL of the batch in one pipeline. Keys gone since the scan are dropped
+// without being counted. Without an active filter keys are returned unchanged.
+func filterRedisKeys(ctx context.Context, client *redis.Client, keys []string, filter common.DataCleanupRedisFilter) ([]string, int64, error) {
+	if !filter.Active() || len(keys) == 0 {
+		return keys, 0, nil
+	}
+	checkTTL := filter.TTLBelowSec > 0 || filter.TTLAboveSec > 0
+	pipe := client.Pipeline()
+	typeCmds := make([]*redis.StatusCmd, len(keys))
+	ttlCmds := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		typeCmds[i] = pipe.Type(ctx, key)
+		if checkTTL {
+			ttlCmds[i] = pipe.PTTL(ctx, key)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	kept := make([]string, 0, len(keys))
+	var skipped int64
+	for i, key := range keys {
+		keyType := typeCmds[i].Val()
+		if keyType == "none" {
+			continue
+		}
+		var ttl time.Duration
+		if checkTTL {
+			// PTTL reports -1 for keys without an expiry, which Matches reads as no expiry
+			ttl = ttlCmds[i].Val()
+		}
+		if filter.Matches(keyType, ttl) {
+			kept = append(kept, key)
+		} else {
+			skipped++
+		}
+	}
+	return kept, skipped, nil
+}
+
+// countRedisPattern scans keys matching pattern without deleting them.
+// When ctx expires mid-scan the partial count is returned with truncated=true.
+func countRedisPattern(ctx context.Context, req common.DataCleanupRedisMatchCountRequest) (matched int64, truncated bool, err error) {
+	pattern := strings.TrimSpace(req.Pattern)
+	if pattern == "" {
+		return 0, false, formatDataCleanupError("redis pattern required", errors.New("pattern is required"), map[string]any{"host": req.Host, "port": req.Port, "db": req.DB})
+	}
+	client, err := newRedisClient(common.DataCleanupRedisDatabasesRequest{
+		Host:     req.Host,
+		Port:     req.Port,
+		Username: req.Username,
+		Password: req.Password,
+		TLS:      req.TLS,
+	}, req.DB)
+	if err != nil {
+		return 0, false, err
+	}
+	defer client.Close()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return 0, false, formatDataCleanupError("ping redis failed", err, map[string]any{"host": req.Host, "port": req.Port, "db": req.DB})
+	}
+
+	cursor := uint64(0)
+	for {
+		keys, nextCursor, err := client.Scan(ctx, cursor, pattern, dataCleanupScanCount).Result()
+		if err != nil {
+			if ctx.Err() != nil {
+				return matched, true, nil
+			}
+			return matched, false, formatDataCleanupError("redis scan failed", err, map[string]any{"host": req.Host, "port": req.Port, "db": req.DB, "pattern": pattern})
+		}
+		matched += int64(len(keys))
+		if nextCursor == 0 {
+			return matched, false, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// countRedisPatterns counts the keys cleanupRedis would delete and those its filter would skip.
+// Unlike countRedisPattern a scan cut short by ctx is an error, since a partial count would
+// understate the cleanup.
+func countRedisPatterns(ctx context.Context, req common.DataCleanupRedisCleanupRequest) (int64, int64, error) {
+	if len(req.Patterns) == 0 {
+		return 0, 0, formatDataCleanupError("redis patterns required", errors.New("patterns are required"), map[string]any{"host": req.Host, "port": req.Port})
+	}
+	if req.Filter.Active() {
+		return scanRedisPatterns(ctx, req, false)
+	}
+	var matched int64
+	for _, pattern := range req.Patterns {
+		count, truncated, err := countRedisPattern(ctx, common.DataCleanupRedisMatchCountRequest{
+			Host:     req.Host,
+			Port:     req.Port,
+			Username: req.Username,
+			Password: req.Password,
+			DB:       req.DB,
+			Pattern:  pattern,
+			TLS:      req.TLS,
+		})
+		if err != nil {
+			return matched, 0, err
+		}
+		if truncated {
+			return matched, 0, formatDataCleanupError("redis scan timed out", ctx.Err(), map[string]any{"host": req.Host, "port": req.Port, "db": req.DB, "pattern": pattern})
+		}
+		matched += count
+	}
+	return matched, 0, nil
 }
 
 func newMinioClient(req common.DataCleanupMinioBucketsRequest) (*minio.Client, error) {
@@ -448,10 +795,19 @@ func newMinioClient(req common.DataCleanupMinioBucketsRequest) (*minio.Client, e
 		return nil, err
 	}
 	accessKey := strings.TrimSpace(req.AccessKey)
-	client, err := minio.New(addr, &minio.Options{
+	opts := &minio.Options{
 		Creds:  credentials.NewStaticV4(accessKey, req.SecretKey, ""),
-		Secure: false,
-	})
+		Secure: req.TLS.UseTLS,
+	}
+	if req.TLS.UseTLS && req.TLS.InsecureSkipVerify {
+		transport, err := minio.DefaultTransport(true)
+		if err != nil {
+			return nil, formatDataCleanupError("init minio transport failed", err, map[string]any{"addr": addr})
+		}
+		transport.TLSClientConfig = dataCleanupTLSConfig(req.TLS, req.Host)
+		opts.Transport = transport
+	}
+	client, err := minio.New(addr, opts)
 	if err != nil {
 		return nil, formatDataCleanupError("init minio client failed", err, map[string]any{"addr": addr})
 	}
@@ -486,6 +842,7 @@ func listMinioPrefixes(ctx context.Context, req common.DataCleanupMinioPrefixesR
 		Port:      req.Port,
 		AccessKey: req.AccessKey,
 		SecretKey: req.SecretKey,
+		TLS:       req.TLS,
 	})
 	if err != nil {
 		return nil, err
@@ -641,6 +998,70 @@ func cleanupMinioPrefixWithProgress(
 	return deleted, nil
 }
 
+// countMinioPrefix lists objects under prefix without deleting them, using the same
+// prefix normalization as cleanup. When ctx expires the partial count is returned with truncated=true.
+func countMinioPrefix(ctx context.Context, req common.DataCleanupMinioMatchCountRequest) (matched int64, truncated bool, err error) {
+	if strings.TrimSpace(req.Bucket) == "" {
+		return 0, false, formatDataCleanupError("bucket is required", errors.New("bucket is required"), map[string]any{"host": req.Host, "port": req.Port})
+	}
+	target := normalizeMinioPrefix(req.Prefix)
+	if target == "" {
+		return 0, false, formatDataCleanupError("minio prefix is required", errors.New("prefix is required"), map[string]any{"bucket": req.Bucket})
+	}
+	client, err := newMinioClient(common.DataCleanupMinioBucketsRequest{
+		Host:      req.Host,
+		Port:      req.Port,
+		AccessKey: req.AccessKey,
+		SecretKey: req.SecretKey,
+		TLS:       req.TLS,
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	opts := minio.ListObjectsOptions{Prefix: target, Recursive: true}
+	for object := range client.ListObjects(ctx, req.Bucket, opts) {
+		if object.Err != nil {
+			if ctx.Err() != nil {
+				return matched, true, nil
+			}
+			return matched, false, formatDataCleanupError("list minio objects failed", object.Err, map[string]any{"bucket": req.Bucket, "prefix": target})
+		}
+		matched++
+	}
+	if ctx.Err() != nil {
+		return matched, true, nil
+	}
+	return matched, false, nil
+}
+
+// countMinioPrefixes counts the objects cleanupMinio would delete. A listing cut short by ctx is an error.
+func countMinioPrefixes(ctx context.Context, req common.DataCleanupMinioCleanupRequest) (int64, error) {
+	if len(req.Prefixes) == 0 {
+		return 0, formatDataCleanupError("minio prefixes required", errors.New("prefixes are required"), map[string]any{"bucket": req.Bucket})
+	}
+	var matched int64
+	for _, prefix := range req.Prefixes {
+		count, truncated, err := countMinioPrefix(ctx, common.DataCleanupMinioMatchCountRequest{
+			Host:      req.Host,
+			Port:      req.Port,
+			AccessKey: req.AccessKey,
+			SecretKey: req.SecretKey,
+			Bucket:    req.Bucket,
+			Prefix:    prefix,
+			TLS:       req.TLS,
+		})
+		if err != nil {
+			return matched, err
+		}
+		if truncated {
+			return matched, formatDataCleanupError("list minio objects timed out", ctx.Err(), map[string]any{"bucket": req.Bucket, "prefix": prefix})
+		}
+		matched += count
+	}
+	return matched, nil
+}
+
 func cleanupMinio(ctx context.Context, req common.DataCleanupMinioCleanupRequest) (int64, error) {
 	if strings.TrimSpace(req.Bucket) == "" {
 		return 0, formatDataCleanupError("bucket is required", errors.New("bucket is required"), map[string]any{"host": req.Host, "port": req.Port})
@@ -653,27 +1074,93 @@ func cleanupMinio(ctx context.Context, req common.DataCleanupMinioCleanupRequest
 		Port:      req.Port,
 		AccessKey: req.AccessKey,
 		SecretKey: req.SecretKey,
+		TLS:       req.TLS,
 	})
 	if err != nil {
 		return 0, err
 	}
 
-	var deleted int64
-	for _, prefix := range req.Prefixes {
-		count, err := cleanupMinioPrefix(ctx, client, req.Bucket, prefix)
-		if err != nil {
-			return deleted, err
+	return runMinioPrefixes(ctx, req.Prefixes, req.Concurrency, func(ctx context.Context, prefix string) (int64, error) {
+		return cleanupMinioPrefix(ctx, client, req.Bucket, prefix)
+	})
+}
+
+// minioPrefixWorkers returns the number of workers for a prefix list: the requested concurrency,
+// or the default when unset, capped by the limit and the number of prefixes.
+func minioPrefixWorkers(concurrency, prefixes int) int {
+	if concurrency <= 0 {
+		concurrency = dataCleanupMinioConcurrency
+	}
+	concurrency = min(concurrency, common.DataCleanupMinioMaxConcurrency, prefixes)
+	return max(concurrency, 1)
+}
+
+// runMinioPrefixes runs clean for each prefix on a bounded pool of workers and returns the sum of
+// the counts, including partial counts of prefixes that failed. The first error cancels the
+// context passed to the other workers, no further prefixes are started, and that error is returned.
+func runMinioPrefixes(ctx context.Context, prefixes []string, concurrency int, clean func(ctx context.Context, prefix string) (int64, error)) (int64, error) {
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		total    int64
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	work := make(chan string)
+	for range minioPrefixWorkers(concurrency, len(prefixes)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for prefix := range work {
+				// the feeder may still hand out a prefix after cancellation
+				if workerCtx.Err() != nil {
+					continue
+				}
+				count, err := clean(workerCtx, prefix)
+				mu.Lock()
+				total += count
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, prefix := range prefixes {
+		select {
+		case work <- prefix:
+		case <-workerCtx.Done():
+			break feed
 		}
-		deleted += count
 	}
-	return deleted, nil
+	close(work)
+	wg.Wait()
+
+	if firstErr != nil {
+		return total, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return total, formatDataCleanupError("minio cleanup cancelled", err, map[string]any{})
+	}
+	return total, nil
 }
 
-func newHTTPClient(timeout time.Duration) *http.Client {
-	return &http.Client{Timeout: timeout}
+func newHTTPClient(timeout time.Duration, opts common.DataCleanupTLS) *http.Client {
+	if !opts.UseTLS || !opts.InsecureSkipVerify {
+		return &http.Client{Timeout: timeout}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12}
+	return &http.Client{Timeout: timeout, Transport: transport}
 }
 
-func buildHTTPURL(host string, port int, path string) (string, error) {
+// buildHTTPURL builds an Elasticsearch endpoint URL, using https when opts.UseTLS is set.
+func buildHTTPURL(host string, port int, opts common.DataCleanupTLS, path string) (string, error) {
 	addr, err := requireHostPort(host, port, map[string]any{"host": host, "port": port})
 	if err != nil {
 		return "", err
@@ -686,16 +1173,19 @@ func buildHTTPURL(host string, port int, path string) (string, error) {
 		Host:   addr,
 		Path:   path,
 	}
+	if opts.UseTLS {
+		u.Scheme = "https"
+	}
 	return u.String(), nil
 }
 
 func listESIndices(ctx context.Context, req common.DataCleanupESIndicesRequest) ([]string, error) {
-	endpoint, err := buildHTTPURL(req.Host, req.Port, "/_cat/indices")
+	endpoint, err := buildHTTPURL(req.Host, req.Port, req.TLS, "/_cat/indices")
 	if err != nil {
 		return nil, err
 	}
 	queryURL := endpoint + "?format=json"
-	httpClient := newHTTPClient(dataCleanupListTimeout)
+	httpClient := newHTTPClient(common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout), req.TLS)
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, queryURL, nil)
 	if err != nil {
@@ -731,11 +1221,21 @@ func listESIndices(ctx context.Context, req common.DataCleanupESIndicesRequest)
 	return indices, nil
 }
 
+// cleanupESIndices empties the indices of req. In delete_by_query mode (the default) it returns
+// the number of documents deleted; drop_index mode deletes the indices and reports 0 deleted.
 func cleanupESIndices(ctx context.Context, req common.DataCleanupESCleanupRequest) (int64, error) {
 	if len(req.Indices) == 0 {
 		return 0, formatDataCleanupError("es indices required", errors.New("indices are required"), map[string]any{"host": req.Host, "port": req.Port})
 	}
-	httpClient := newHTTPClient(dataCleanupActionTimeout)
+	mode, err := common.NormalizeDataCleanupESMode(req.Mode)
+	if err != nil {
+		return 0, formatDataCleanupError("invalid es cleanup mode", err, map[string]any{"host": req.Host, "port": req.Port})
+	}
+	if mode == common.DataCleanupESModeDropIndex {
+		_, err := dropESIndices(ctx, req)
+		return 0, err
+	}
+	httpClient := newHTTPClient(common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout), req.TLS)
 	var deleted int64
 
 	for _, index := range req.Indices {
@@ -743,7 +1243,7 @@ func cleanupESIndices(ctx context.Context, req common.DataCleanupESCleanupReques
 		if escaped == "" {
 			return deleted, formatDataCleanupError("es index required", errors.New("index is required"), map[string]any{"host": req.Host, "port": req.Port})
 		}
-		endpoint, err := buildHTTPURL(req.Host, req.Port, "/"+escaped+"/_delete_by_query")
+		endpoint, err := buildHTTPURL(req.Host, req.Port, req.TLS, "/"+escaped+"/_delete_by_query")
 		if err != nil {
 			return deleted, err
 		}
@@ -787,6 +1287,193 @@ func cleanupESIndices(ctx context.Context, req common.DataCleanupESCleanupReques
 	return deleted, nil
 }
 
+// dropESIndices deletes each index of req with DELETE /{index} and returns the indices that were
+// dropped. An index that does not exist (404) is skipped rather than treated as an error.
+func dropESIndices(ctx context.Context, req common.DataCleanupESCleanupRequest) ([]string, error) {
+	httpClient := newHTTPClient(common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout), req.TLS)
+	dropped := make([]string, 0, len(req.Indices))
+
+	for _, index := range req.Indices {
+		index = strings.TrimSpace(index)
+		escaped := url.PathEscape(index)
+		if escaped == "" {
+			return dropped, formatDataCleanupError("es index required", errors.New("index is required"), map[string]any{"host": req.Host, "port": req.Port})
+		}
+		endpoint, err := buildHTTPURL(req.Host, req.Port, req.TLS, "/"+escaped)
+		if err != nil {
+			return dropped, err
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+		if err != nil {
+			return dropped, formatDataCleanupError("build es drop index request failed", err, map[string]any{"endpoint": endpoint})
+		}
+		if strings.TrimSpace(req.Username) != "" || strings.TrimSpace(req.Password) != "" {
+			request.SetBasicAuth(req.Username, req.Password)
+		}
+
+		resp, err := httpClient.Do(request)
+		if err != nil {
+			return dropped, formatDataCleanupError("request es drop index failed", err, map[string]any{"endpoint": endpoint})
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			_ = resp.Body.Close()
+			continue
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			_ = resp.Body.Close()
+			return dropped, formatDataCleanupError("es drop index response error", errors.New(string(body)), map[string]any{"status": resp.StatusCode, "endpoint": endpoint})
+		}
+
+		var payload dataCleanupAcknowledgedResponse
+		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+			_ = resp.Body.Close()
+			return dropped, formatDataCleanupError("decode es drop index response failed", err, map[string]any{"endpoint": endpoint})
+		}
+		_ = resp.Body.Close()
+		if !payload.Acknowledged {
+			return dropped, formatDataCleanupError("es drop index not acknowledged", errors.New("index deletion was not acknowledged"), map[string]any{"endpoint": endpoint})
+		}
+		dropped = append(dropped, index)
+	}
+	return dropped, nil
+}
+
+// countESIndices counts the documents cleanupESIndices would delete, using the _count API.
+func countESIndices(ctx context.Context, req common.DataCleanupESCleanupRequest) (int64, error) {
+	if len(req.Indices) == 0 {
+		return 0, formatDataCleanupError("es indices required", errors.New("indices are required"), map[string]any{"host": req.Host, "port": req.Port})
+	}
+	httpClient := newHTTPClient(common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout), req.TLS)
+	var matched int64
+
+	for _, index := range req.Indices {
+		escaped := url.PathEscape(strings.TrimSpace(index))
+		if escaped == "" {
+			return matched, formatDataCleanupError("es index required", errors.New("index is required"), map[string]any{"host": req.Host, "port": req.Port})
+		}
+		endpoint, err := buildHTTPURL(req.Host, req.Port, req.TLS, "/"+escaped+"/_count")
+		if err != nil {
+			return matched, err
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return matched, formatDataCleanupError("build es count request failed", err, map[string]any{"endpoint": endpoint})
+		}
+		if strings.TrimSpace(req.Username) != "" || strings.TrimSpace(req.Password) != "" {
+			request.SetBasicAuth(req.Username, req.Password)
+		}
+
+		resp, err := httpClient.Do(request)
+		if err != nil {
+			return matched, formatDataCleanupError("request es count failed", err, map[string]any{"endpoint": endpoint})
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			_ = resp.Body.Close()
+			return matched, formatDataCleanupError("es count response error", errors.New(string(body)), map[string]any{"status": resp.StatusCode, "endpoint": endpoint})
+		}
+
+		var payload dataCleanupCountResponse
+		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+			_ = resp.Body.Close()
+			return matched, formatDataCleanupError("decode es count response failed", err, map[string]any{"endpoint": endpoint})
+		}
+		_ = resp.Body.Close()
+		matched += payload.Count
+	}
+	return matched, nil
+}
+
+// pingDataCleanupModule connects and authenticates to the module of req without listing or
+// changing anything.
+func pingDataCleanupModule(ctx context.Context, req common.DataCleanupPingRequest) error {
+	fields := map[string]any{"module": req.Module, "host": req.Host, "port": req.Port}
+	switch req.Module {
+	case "mysql":
+		cfg, err := newMySQLConfig(common.DataCleanupMySQLDatabasesRequest{
+			Host:     req.Host,
+			Port:     req.Port,
+			Username: req.Username,
+			Password: req.Password,
+			TLS:      req.TLS,
+		}, "", common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
+		if err != nil {
+			return err
+		}
+		// openMySQL already pings the server
+		db, err := openMySQL(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		return db.Close()
+	case "redis":
+		client, err := newRedisClient(common.DataCleanupRedisDatabasesRequest{
+			Host:     req.Host,
+			Port:     req.Port,
+			Username: req.Username,
+			Password: req.Password,
+			TLS:      req.TLS,
+		}, 0)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		if err := client.Ping(ctx).Err(); err != nil {
+			return formatDataCleanupError("ping redis failed", err, fields)
+		}
+		return nil
+	case "minio":
+		client, err := newMinioClient(common.DataCleanupMinioBucketsRequest{
+			Host:      req.Host,
+			Port:      req.Port,
+			AccessKey: req.Username,
+			SecretKey: req.Password,
+			TLS:       req.TLS,
+		})
+		if err != nil {
+			return err
+		}
+		// S3 has no ping; listing buckets is the cheapest call that checks the credentials
+		if _, err := client.ListBuckets(ctx); err != nil {
+			return formatDataCleanupError("ping minio failed", err, fields)
+		}
+		return nil
+	case "es":
+		return pingES(ctx, req)
+	default:
+		return formatDataCleanupError("invalid data cleanup module", errors.New("module must be mysql, redis, minio or es"), fields)
+	}
+}
+
+// pingES requests the cluster root endpoint, which any authenticated user may read.
+func pingES(ctx context.Context, req common.DataCleanupPingRequest) error {
+	endpoint, err := buildHTTPURL(req.Host, req.Port, req.TLS, "/")
+	if err != nil {
+		return err
+	}
+	httpClient := newHTTPClient(common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout), req.TLS)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return formatDataCleanupError("build es ping request failed", err, map[string]any{"endpoint": endpoint})
+	}
+	if strings.TrimSpace(req.Username) != "" || strings.TrimSpace(req.Password) != "" {
+		request.SetBasicAuth(req.Username, req.Password)
+	}
+
+	resp, err := httpClient.Do(request)
+	if err != nil {
+		return formatDataCleanupError("ping es failed", err, map[string]any{"endpoint": endpoint})
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return formatDataCleanupError("es ping response error", errors.New(string(body)), map[string]any{"status": resp.StatusCode, "endpoint": endpoint})
+	}
+	return nil
+}
+
 type DataCleanupMySQLDatabasesHandler struct{}
 
 func (h *DataCleanupMySQLDatabasesHandler) Handle(hctx *HandlerContext) error {
@@ -794,7 +1481,7 @@ func (h *DataCleanupMySQLDatabasesHandler) Handle(hctx *HandlerContext) error {
 	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
 		return formatDataCleanupError("decode mysql databases request failed", err, map[string]any{})
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	defer cancel()
 
 	items, err := listMySQLDatabases(ctx, req)
@@ -812,7 +1499,7 @@ func (h *DataCleanupMySQLTablesHandler) Handle(hctx *HandlerContext) error {
 	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
 		return formatDataCleanupError("decode mysql tables request failed", err, map[string]any{})
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	defer cancel()
 
 	items, err := listMySQLTables(ctx, req)
@@ -830,14 +1517,30 @@ func (h *DataCleanupMySQLDeleteTablesHandler) Handle(hctx *HandlerContext) error
 	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
 		return formatDataCleanupError("decode mysql delete request failed", err, map[string]any{})
 	}
+	if _, err := common.NormalizeDataCleanupMySQLMode(req.Mode); err != nil {
+		return formatDataCleanupError("invalid mysql cleanup mode", err, map[string]any{"host": req.Host, "port": req.Port, "db": req.Database})
+	}
+	if _, err := mysqlTableConditions(req.Conditions); err != nil {
+		return err
+	}
+	// a dry run only counts matches and deletes nothing, so it needs no confirmation
+	if !req.DryRun {
+		if err := hctx.Agent.requireDataCleanupConfirm("mysql", req.Confirm); err != nil {
+			return err
+		}
+	}
+	cleanup := deleteMySQLTables
+	if req.DryRun {
+		cleanup = countMySQLTables
+	}
 	jobID := strings.TrimSpace(req.JobID)
 	if jobID != "" {
 		if len(req.Tables) == 0 {
 			return formatDataCleanupError("mysql tables required", errors.New("tables are required"), map[string]any{"host": req.Host, "port": req.Port, "db": req.Database})
 		}
 
-		snapshot, err := hctx.Agent.dataCleanupJobs.Start(jobID, "mysql", len(req.Tables), dataCleanupActionTimeout, func(ctx context.Context, job *dataCleanupJob) error {
-			slog.Info("mysql cleanup job start", "jobId", jobID, "host", req.Host, "port", req.Port, "db", req.Database, "tables", len(req.Tables))
+		snapshot, err := hctx.Agent.dataCleanupJobs.Start(jobID, "mysql", len(req.Tables), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout), func(ctx context.Context, job *dataCleanupJob) error {
+			slog.Info("mysql cleanup job start", "jobId", jobID, "host", req.Host, "port", req.Port, "db", req.Database, "tables", len(req.Tables), "mode", req.Mode, "dryRun", req.DryRun)
 			var totalDeleted int64
 
 			for _, table := range req.Tables {
@@ -851,7 +1554,7 @@ func (h *DataCleanupMySQLDeleteTablesHandler) Handle(hctx *HandlerContext) error
 				perReq.Tables = []string{table}
 				perReq.JobID = ""
 
-				deleted, err := deleteMySQLTables(ctx, perReq)
+				deleted, err := cleanup(ctx, perReq)
 				if err != nil {
 					slog.Error("mysql cleanup failed", "err", err, "jobId", jobID, "host", req.Host, "port", req.Port, "db", req.Database, "table", table)
 					return err
@@ -873,11 +1576,11 @@ func (h *DataCleanupMySQLDeleteTablesHandler) Handle(hctx *HandlerContext) error
 		return hctx.SendResponse(&common.DockerDataCleanupResult{Deleted: snapshot.Deleted, Detail: detail}, hctx.RequestID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupActionTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	defer cancel()
 
-	slog.Info("mysql cleanup start", "host", req.Host, "port", req.Port, "db", req.Database, "tables", len(req.Tables))
-	deleted, err := deleteMySQLTables(ctx, req)
+	slog.Info("mysql cleanup start", "host", req.Host, "port", req.Port, "db", req.Database, "tables", len(req.Tables), "mode", req.Mode, "dryRun", req.DryRun)
+	deleted, err := cleanup(ctx, req)
 	if err != nil {
 		slog.Error("mysql cleanup failed", "err", err, "host", req.Host, "port", req.Port, "db", req.Database)
 		return err
@@ -893,7 +1596,7 @@ func (h *DataCleanupRedisDatabasesHandler) Handle(hctx *HandlerContext) error {
 	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
 		return formatDataCleanupError("decode redis databases request failed", err, map[string]any{})
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	defer cancel()
 
 	items, err := listRedisDatabases(ctx, req)
@@ -911,15 +1614,29 @@ func (h *DataCleanupRedisCleanupHandler) Handle(hctx *HandlerContext) error {
 	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
 		return formatDataCleanupError("decode redis cleanup request failed", err, map[string]any{})
 	}
+	if !req.DryRun {
+		if err := hctx.Agent.requireDataCleanupConfirm("redis", req.Confirm); err != nil {
+			return err
+		}
+	}
+	filter, err := req.Filter.Normalize()
+	if err != nil {
+		return formatDataCleanupError("invalid redis filter", err, map[string]any{"host": req.Host, "port": req.Port, "db": req.DB})
+	}
+	req.Filter = filter
+	cleanup := cleanupRedis
+	if req.DryRun {
+		cleanup = countRedisPatterns
+	}
 	jobID := strings.TrimSpace(req.JobID)
 	if jobID != "" {
 		if len(req.Patterns) == 0 {
 			return formatDataCleanupError("redis patterns required", errors.New("patterns are required"), map[string]any{"host": req.Host, "port": req.Port, "db": req.DB})
 		}
 
-		snapshot, err := hctx.Agent.dataCleanupJobs.Start(jobID, "redis", len(req.Patterns), dataCleanupActionTimeout, func(ctx context.Context, job *dataCleanupJob) error {
-			slog.Info("redis cleanup job start", "jobId", jobID, "host", req.Host, "port", req.Port, "db", req.DB, "patterns", len(req.Patterns))
-			var totalDeleted int64
+		snapshot, err := hctx.Agent.dataCleanupJobs.Start(jobID, "redis", len(req.Patterns), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout), func(ctx context.Context, job *dataCleanupJob) error {
+			slog.Info("redis cleanup job start", "jobId", jobID, "host", req.Host, "port", req.Port, "db", req.DB, "patterns", len(req.Patterns), "filter", req.Filter, "dryRun", req.DryRun)
+			var totalDeleted, totalSkipped int64
 
 			for _, pattern := range req.Patterns {
 				pattern = strings.TrimSpace(pattern)
@@ -932,16 +1649,18 @@ func (h *DataCleanupRedisCleanupHandler) Handle(hctx *HandlerContext) error {
 				perReq.Patterns = []string{pattern}
 				perReq.JobID = ""
 
-				deleted, err := cleanupRedis(ctx, perReq)
+				deleted, skipped, err := cleanup(ctx, perReq)
+				job.addSkipped(skipped)
 				if err != nil {
 					slog.Error("redis cleanup failed", "err", err, "jobId", jobID, "host", req.Host, "port", req.Port, "db", req.DB, "pattern", pattern)
 					return err
 				}
 				totalDeleted += deleted
+				totalSkipped += skipped
 				job.markItemDoneWithDeleted(deleted)
 			}
 
-			slog.Info("redis cleanup job done", "jobId", jobID, "host", req.Host, "port", req.Port, "db", req.DB, "deleted", totalDeleted)
+			slog.Info("redis cleanup job done", "jobId", jobID, "host", req.Host, "port", req.Port, "db", req.DB, "deleted", totalDeleted, "skipped", totalSkipped)
 			return nil
 		})
 		if err != nil {
@@ -954,17 +1673,17 @@ func (h *DataCleanupRedisCleanupHandler) Handle(hctx *HandlerContext) error {
 		return hctx.SendResponse(&common.DockerDataCleanupResult{Deleted: snapshot.Deleted, Detail: detail}, hctx.RequestID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupActionTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	defer cancel()
 
-	slog.Info("redis cleanup start", "host", req.Host, "port", req.Port, "db", req.DB, "patterns", len(req.Patterns))
-	deleted, err := cleanupRedis(ctx, req)
+	slog.Info("redis cleanup start", "host", req.Host, "port", req.Port, "db", req.DB, "patterns", len(req.Patterns), "filter", req.Filter, "dryRun", req.DryRun)
+	deleted, skipped, err := cleanup(ctx, req)
 	if err != nil {
 		slog.Error("redis cleanup failed", "err", err, "host", req.Host, "port", req.Port, "db", req.DB)
 		return err
 	}
-	slog.Info("redis cleanup done", "host", req.Host, "port", req.Port, "db", req.DB, "deleted", deleted)
-	return hctx.SendResponse(&common.DockerDataCleanupResult{Deleted: deleted}, hctx.RequestID)
+	slog.Info("redis cleanup done", "host", req.Host, "port", req.Port, "db", req.DB, "deleted", deleted, "skipped", skipped)
+	return hctx.SendResponse(&common.DockerDataCleanupResult{Deleted: deleted, Skipped: skipped}, hctx.RequestID)
 }
 
 type DataCleanupMinioBucketsHandler struct{}
@@ -974,7 +1693,7 @@ func (h *DataCleanupMinioBucketsHandler) Handle(hctx *HandlerContext) error {
 	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
 		return formatDataCleanupError("decode minio buckets request failed", err, map[string]any{})
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	defer cancel()
 
 	items, err := listMinioBuckets(ctx, req)
@@ -992,7 +1711,7 @@ func (h *DataCleanupMinioPrefixesHandler) Handle(hctx *HandlerContext) error {
 	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
 		return formatDataCleanupError("decode minio prefixes request failed", err, map[string]any{})
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	defer cancel()
 
 	items, err := listMinioPrefixes(ctx, req)
@@ -1010,6 +1729,15 @@ func (h *DataCleanupMinioCleanupHandler) Handle(hctx *HandlerContext) error {
 	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
 		return formatDataCleanupError("decode minio cleanup request failed", err, map[string]any{})
 	}
+	if !req.DryRun {
+		if err := hctx.Agent.requireDataCleanupConfirm("minio", req.Confirm); err != nil {
+			return err
+		}
+	}
+	cleanup := cleanupMinio
+	if req.DryRun {
+		cleanup = countMinioPrefixes
+	}
 	jobID := strings.TrimSpace(req.JobID)
 	if jobID != "" {
 		if strings.TrimSpace(req.Bucket) == "" {
@@ -1019,37 +1747,57 @@ func (h *DataCleanupMinioCleanupHandler) Handle(hctx *HandlerContext) error {
 			return formatDataCleanupError("minio prefixes required", errors.New("prefixes are required"), map[string]any{"bucket": req.Bucket})
 		}
 
-		snapshot, err := hctx.Agent.dataCleanupJobs.Start(jobID, "minio", len(req.Prefixes), dataCleanupActionTimeout, func(ctx context.Context, job *dataCleanupJob) error {
-			slog.Info("minio cleanup job start", "jobId", jobID, "host", req.Host, "port", req.Port, "bucket", req.Bucket, "prefixes", len(req.Prefixes))
+		snapshot, err := hctx.Agent.dataCleanupJobs.Start(jobID, "minio", len(req.Prefixes), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout), func(ctx context.Context, job *dataCleanupJob) error {
+			slog.Info("minio cleanup job start", "jobId", jobID, "host", req.Host, "port", req.Port, "bucket", req.Bucket, "prefixes", len(req.Prefixes), "concurrency", minioPrefixWorkers(req.Concurrency, len(req.Prefixes)), "dryRun", req.DryRun)
 
 			client, err := newMinioClient(common.DataCleanupMinioBucketsRequest{
 				Host:      req.Host,
 				Port:      req.Port,
 				AccessKey: req.AccessKey,
 				SecretKey: req.SecretKey,
+				TLS:       req.TLS,
 			})
 			if err != nil {
 				slog.Error("minio cleanup failed", "err", err, "jobId", jobID, "host", req.Host, "port", req.Port, "bucket", req.Bucket)
 				return err
 			}
 
-			var totalDeleted int64
+			prefixes := make([]string, 0, len(req.Prefixes))
 			for _, prefix := range req.Prefixes {
 				prefix = strings.TrimSpace(prefix)
 				if prefix == "" {
 					return formatDataCleanupError("minio prefix is required", errors.New("prefix is required"), map[string]any{"bucket": req.Bucket})
 				}
+				prefixes = append(prefixes, prefix)
+			}
+
+			// prefixes are cleaned in parallel; the job counters are safe for concurrent updates
+			totalDeleted, err := runMinioPrefixes(ctx, prefixes, req.Concurrency, func(ctx context.Context, prefix string) (int64, error) {
 				job.setCurrent(prefix)
+				if req.DryRun {
+					perReq := req
+					perReq.Prefixes = []string{prefix}
+					perReq.JobID = ""
+
+					count, err := countMinioPrefixes(ctx, perReq)
+					if err != nil {
+						slog.Error("minio cleanup failed", "err", err, "jobId", jobID, "host", req.Host, "port", req.Port, "bucket", req.Bucket, "prefix", prefix)
+						return count, err
+					}
+					job.markItemDoneWithDeleted(count)
+					return count, nil
+				}
 
-				count, err := cleanupMinioPrefixWithProgress(ctx, client, req.Bucket, prefix, func(batch int64) {
-					job.addDeleted(batch)
-				})
-				totalDeleted += count
+				count, err := cleanupMinioPrefixWithProgress(ctx, client, req.Bucket, prefix, job.addDeleted)
 				if err != nil {
 					slog.Error("minio cleanup failed", "err", err, "jobId", jobID, "host", req.Host, "port", req.Port, "bucket", req.Bucket, "prefix", prefix)
-					return err
+					return count, err
 				}
-				job.markItemDone()
+				job.markReportedItemDone(count)
+				return count, nil
+			})
+			if err != nil {
+				return err
 			}
 
 			slog.Info("minio cleanup job done", "jobId", jobID, "host", req.Host, "port", req.Port, "bucket", req.Bucket, "deleted", totalDeleted)
@@ -1065,11 +1813,11 @@ func (h *DataCleanupMinioCleanupHandler) Handle(hctx *HandlerContext) error {
 		return hctx.SendResponse(&common.DockerDataCleanupResult{Deleted: snapshot.Deleted, Detail: detail}, hctx.RequestID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupActionTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	defer cancel()
 
-	slog.Info("minio cleanup start", "host", req.Host, "port", req.Port, "bucket", req.Bucket, "prefixes", len(req.Prefixes))
-	deleted, err := cleanupMinio(ctx, req)
+	slog.Info("minio cleanup start", "host", req.Host, "port", req.Port, "bucket", req.Bucket, "prefixes", len(req.Prefixes), "dryRun", req.DryRun)
+	deleted, err := cleanup(ctx, req)
 	if err != nil {
 		slog.Error("minio cleanup failed", "err", err, "host", req.Host, "port", req.Port, "bucket", req.Bucket)
 		return err
@@ -1085,7 +1833,7 @@ func (h *DataCleanupESIndicesHandler) Handle(hctx *HandlerContext) error {
 	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
 		return formatDataCleanupError("decode es indices request failed", err, map[string]any{})
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	defer cancel()
 
 	items, err := listESIndices(ctx, req)
@@ -1103,14 +1851,29 @@ func (h *DataCleanupESCleanupHandler) Handle(hctx *HandlerContext) error {
 	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
 		return formatDataCleanupError("decode es cleanup request failed", err, map[string]any{})
 	}
+	if !req.DryRun {
+		if err := hctx.Agent.requireDataCleanupConfirm("es", req.Confirm); err != nil {
+			return err
+		}
+	}
+	mode, err := common.NormalizeDataCleanupESMode(req.Mode)
+	if err != nil {
+		return formatDataCleanupError("invalid es cleanup mode", err, map[string]any{"host": req.Host, "port": req.Port})
+	}
+	cleanup := cleanupESIndices
+	if req.DryRun {
+		cleanup = countESIndices
+	}
+	// a dry run counts the documents in either mode
+	dropIndex := mode == common.DataCleanupESModeDropIndex && !req.DryRun
 	jobID := strings.TrimSpace(req.JobID)
 	if jobID != "" {
 		if len(req.Indices) == 0 {
 			return formatDataCleanupError("es indices required", errors.New("indices are required"), map[string]any{"host": req.Host, "port": req.Port})
 		}
 
-		snapshot, err := hctx.Agent.dataCleanupJobs.Start(jobID, "es", len(req.Indices), dataCleanupActionTimeout, func(ctx context.Context, job *dataCleanupJob) error {
-			slog.Info("es cleanup job start", "jobId", jobID, "host", req.Host, "port", req.Port, "indices", len(req.Indices))
+		snapshot, err := hctx.Agent.dataCleanupJobs.Start(jobID, "es", len(req.Indices), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout), func(ctx context.Context, job *dataCleanupJob) error {
+			slog.Info("es cleanup job start", "jobId", jobID, "host", req.Host, "port", req.Port, "indices", len(req.Indices), "mode", mode, "dryRun", req.DryRun)
 			var totalDeleted int64
 
 			for _, index := range req.Indices {
@@ -1124,7 +1887,23 @@ func (h *DataCleanupESCleanupHandler) Handle(hctx *HandlerContext) error {
 				perReq.Indices = []string{index}
 				perReq.JobID = ""
 
-				deleted, err := cleanupESIndices(ctx, perReq)
+				if dropIndex {
+					dropped, err := dropESIndices(ctx, perReq)
+					if err != nil {
+						slog.Error("es cleanup failed", "err", err, "jobId", jobID, "host", req.Host, "port", req.Port, "index", index)
+						return err
+					}
+					if len(dropped) == 0 {
+						slog.Info("es index not found, nothing to drop", "jobId", jobID, "index", index)
+					}
+					for _, name := range dropped {
+						job.addDropped(name)
+					}
+					job.markItemDone()
+					continue
+				}
+
+				deleted, err := cleanup(ctx, perReq)
 				if err != nil {
 					slog.Error("es cleanup failed", "err", err, "jobId", jobID, "host", req.Host, "port", req.Port, "index", index)
 					return err
@@ -1146,11 +1925,11 @@ func (h *DataCleanupESCleanupHandler) Handle(hctx *HandlerContext) error {
 		return hctx.SendResponse(&common.DockerDataCleanupResult{Deleted: snapshot.Deleted, Detail: detail}, hctx.RequestID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupActionTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	defer cancel()
 
-	slog.Info("es cleanup start", "host", req.Host, "port", req.Port, "indices", len(req.Indices))
-	deleted, err := cleanupESIndices(ctx, req)
+	slog.Info("es cleanup start", "host", req.Host, "port", req.Port, "indices", len(req.Indices), "mode", mode, "dryRun", req.DryRun)
+	deleted, err := cleanup(ctx, req)
 	if err != nil {
 		slog.Error("es cleanup failed", "err", err, "host", req.Host, "port", req.Port)
 		return err
@@ -1180,3 +1959,94 @@ func (h *DataCleanupJobStatusHandler) Handle(hctx *HandlerContext) error {
 	}
 	return hctx.SendResponse(&common.DockerDataCleanupResult{Deleted: snapshot.Deleted, Detail: detail}, hctx.RequestID)
 }
+
+type DataCleanupJobListHandler struct{}
+
+func (h *DataCleanupJobListHandler) Handle(hctx *HandlerContext) error {
+	snapshots := hctx.Agent.dataCleanupJobs.List()
+	list := common.DataCleanupJobListDetail{Jobs: make([]common.DataCleanupJobStatusDetail, 0, len(snapshots))}
+	for _, snapshot := range snapshots {
+		list.Jobs = append(list.Jobs, dataCleanupJobStatusDetail(snapshot))
+	}
+	encoded, err := json.Marshal(list)
+	if err != nil {
+		return formatDataCleanupError("encode data cleanup job list failed", err, map[string]any{})
+	}
+	return hctx.SendResponse(&common.DockerDataCleanupResult{Detail: string(encoded)}, hctx.RequestID)
+}
+
+type DataCleanupJobCancelHandler struct{}
+
+func (h *DataCleanupJobCancelHandler) Handle(hctx *HandlerContext) error {
+	var req common.DataCleanupJobCancelRequest
+	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
+		return formatDataCleanupError("decode data cleanup job cancel request failed", err, map[string]any{})
+	}
+	jobID := strings.TrimSpace(req.JobID)
+	if jobID == "" {
+		return formatDataCleanupError("jobId is required", errors.New("jobId is required"), map[string]any{})
+	}
+	snapshot, err := hctx.Agent.dataCleanupJobs.Cancel(jobID)
+	if err != nil {
+		return formatDataCleanupError("data cleanup job not found", err, map[string]any{"jobId": jobID})
+	}
+	slog.Info("data cleanup job cancel requested", "jobId", jobID, "module", snapshot.Module, "deleted", snapshot.Deleted)
+	detail, err := encodeDataCleanupJobStatusDetail(snapshot)
+	if err != nil {
+		return formatDataCleanupError("encode data cleanup job status failed", err, map[string]any{"jobId": jobID})
+	}
+	return hctx.SendResponse(&common.DockerDataCleanupResult{Deleted: snapshot.Deleted, Detail: detail}, hctx.RequestID)
+}
+
+type DataCleanupPingHandler struct{}
+
+func (h *DataCleanupPingHandler) Handle(hctx *HandlerContext) error {
+	var req common.DataCleanupPingRequest
+	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
+		return formatDataCleanupError("decode data cleanup ping request failed", err, map[string]any{})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
+	defer cancel()
+
+	if err := pingDataCleanupModule(ctx, req); err != nil {
+		slog.Warn("data cleanup ping failed", "err", err, "module", req.Module, "host", req.Host, "port", req.Port)
+		return err
+	}
+	return hctx.SendResponse(&common.DockerDataCleanupResult{Detail: "ok"}, hctx.RequestID)
+}
+
+type DataCleanupRedisMatchCountHandler struct{}
+
+func (h *DataCleanupRedisMatchCountHandler) Handle(hctx *HandlerContext) error {
+	var req common.DataCleanupRedisMatchCountRequest
+	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
+		return formatDataCleanupError("decode redis match count request failed", err, map[string]any{})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupMatchCountTimeout)
+	defer cancel()
+
+	matched, truncated, err := countRedisPattern(ctx, req)
+	if err != nil {
+		slog.Error("redis match count failed", "err", err, "host", req.Host, "port", req.Port, "db", req.DB, "pattern", req.Pattern)
+		return err
+	}
+	return hctx.SendResponse(&common.DockerDataCleanupResult{Matched: matched, Truncated: truncated}, hctx.RequestID)
+}
+
+type DataCleanupMinioMatchCountHandler struct{}
+
+func (h *DataCleanupMinioMatchCountHandler) Handle(hctx *HandlerContext) error {
+	var req common.DataCleanupMinioMatchCountRequest
+	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
+		return formatDataCleanupError("decode minio match count request failed", err, map[string]any{})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dataCleanupMatchCountTimeout)
+	defer cancel()
+
+	matched, truncated, err := countMinioPrefix(ctx, req)
+	if err != nil {
+		slog.Error("minio match count failed", "err", err, "host", req.Host, "port", req.Port, "bucket", req.Bucket, "prefix", req.Prefix)
+		return err
+	}
+	return hctx.SendResponse(&common.DockerDataCleanupResult{Matched: matched, Truncated: truncated}, hctx.RequestID)
+}
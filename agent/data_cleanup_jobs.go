@@ -3,6 +3,8 @@ package agent
 import (
 	"context"
 	"errors"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 )
@@ -15,6 +17,8 @@ const (
 	dataCleanupJobTTL = time.Hour
 )
 
+var errDataCleanupJobCancelled = errors.New("job cancelled")
+
 type dataCleanupJobSnapshot struct {
 	JobID   string
 	Module  string
@@ -25,6 +29,10 @@ type dataCleanupJobSnapshot struct {
 	Deleted int64
 	Seq     uint64
 	Error   string
+
+	Skipped     int64
+	Dropped     []string
+	ItemDeleted int64
 }
 
 type dataCleanupJob struct {
@@ -41,6 +49,14 @@ type dataCleanupJob struct {
 	err       string
 	updatedAt time.Time
 	expiresAt time.Time
+	// skipped counts matching entries left in place by a filter
+	skipped int64
+	// dropped lists the whole indices a job deleted
+	dropped []string
+	// itemDeleted is the part of deleted reported by items that are not done yet
+	itemDeleted int64
+	// cancelled is set when the hub cancels the job, so its error reads as a cancellation
+	cancelled bool
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -59,6 +75,10 @@ func (j *dataCleanupJob) snapshot() dataCleanupJobSnapshot {
 		Deleted: j.deleted,
 		Seq:     j.seq,
 		Error:   j.err,
+
+		Skipped:     j.skipped,
+		Dropped:     slices.Clone(j.dropped),
+		ItemDeleted: j.itemDeleted,
 	}
 }
 
@@ -75,6 +95,8 @@ func (j *dataCleanupJob) setCurrent(current string) {
 	j.mu.Unlock()
 }
 
+// addDeleted reports a batch deleted by an item that is still running. The item must finish
+// with markReportedItemDone so the batch stops counting as in progress.
 func (j *dataCleanupJob) addDeleted(delta int64) {
 	if delta <= 0 {
 		return
@@ -82,6 +104,7 @@ func (j *dataCleanupJob) addDeleted(delta int64) {
 	now := time.Now()
 	j.mu.Lock()
 	j.deleted += delta
+	j.itemDeleted += delta
 	j.touchLocked(now)
 	j.mu.Unlock()
 }
@@ -94,6 +117,35 @@ func (j *dataCleanupJob) markItemDone() {
 	j.mu.Unlock()
 }
 
+func (j *dataCleanupJob) addSkipped(delta int64) {
+	if delta <= 0 {
+		return
+	}
+	now := time.Now()
+	j.mu.Lock()
+	j.skipped += delta
+	j.touchLocked(now)
+	j.mu.Unlock()
+}
+
+func (j *dataCleanupJob) addDropped(name string) {
+	now := time.Now()
+	j.mu.Lock()
+	j.dropped = append(j.dropped, name)
+	j.touchLocked(now)
+	j.mu.Unlock()
+}
+
+// markReportedItemDone marks an item done whose deletions were already reported via addDeleted.
+func (j *dataCleanupJob) markReportedItemDone(reported int64) {
+	now := time.Now()
+	j.mu.Lock()
+	j.itemDeleted = max(j.itemDeleted-reported, 0)
+	j.done++
+	j.touchLocked(now)
+	j.mu.Unlock()
+}
+
 func (j *dataCleanupJob) markItemDoneWithDeleted(delta int64) {
 	now := time.Now()
 	j.mu.Lock()
@@ -113,6 +165,9 @@ func (j *dataCleanupJob) finalize(err error) {
 	if err != nil {
 		j.status = dataCleanupJobStatusFailed
 		j.err = err.Error()
+		if j.cancelled {
+			j.err = errDataCleanupJobCancelled.Error()
+		}
 	} else {
 		j.status = dataCleanupJobStatusSuccess
 		j.err = ""
@@ -184,6 +239,40 @@ func (m *dataCleanupJobManager) Snapshot(jobID string) (dataCleanupJobSnapshot,
 	return job.snapshot(), nil
 }
 
+// List returns snapshots of all retained jobs, sorted by job id.
+func (m *dataCleanupJobManager) List() []dataCleanupJobSnapshot {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshots := make([]dataCleanupJobSnapshot, 0, len(m.jobs))
+	for id, job := range m.jobs {
+		if job.expired(now) {
+			delete(m.jobs, id)
+			continue
+		}
+		snapshots = append(snapshots, job.snapshot())
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].JobID < snapshots[j].JobID })
+	return snapshots
+}
+
+// Cancel stops a running job and returns its snapshot. The job keeps the deleted count reached so
+// far and finishes as failed once its work function returns; cancelling a finished job is a no-op.
+func (m *dataCleanupJobManager) Cancel(jobID string) (dataCleanupJobSnapshot, error) {
+	job, ok := m.get(jobID)
+	if !ok {
+		return dataCleanupJobSnapshot{}, errors.New("job not found")
+	}
+	job.mu.Lock()
+	if job.status == dataCleanupJobStatusRunning {
+		job.cancelled = true
+	}
+	job.mu.Unlock()
+	job.cancel()
+	return job.snapshot(), nil
+}
+
 func (m *dataCleanupJobManager) Start(
 	jobID string,
 	module string,
@@ -0,0 +1,99 @@
+//go:build testing
+// +build testing
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataCleanupJobManagerList(t *testing.T) {
+	m := &dataCleanupJobManager{jobs: make(map[string]*dataCleanupJob)}
+
+	release := make(chan struct{})
+	_, err := m.Start("run2:redis", "redis", 3, time.Minute, func(ctx context.Context, job *dataCleanupJob) error {
+		<-release
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = m.Start("run1:mysql", "mysql", 1, time.Minute, func(ctx context.Context, job *dataCleanupJob) error {
+		return errors.New("boom")
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		snapshot, err := m.Snapshot("run1:mysql")
+		return err == nil && snapshot.Status == dataCleanupJobStatusFailed
+	}, time.Second, 10*time.Millisecond)
+
+	// an expired finished job is dropped from the list
+	m.jobs["run0:es"] = &dataCleanupJob{jobID: "run0:es", module: "es", status: dataCleanupJobStatusSuccess, expiresAt: time.Now().Add(-time.Minute)}
+
+	list := m.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, "run1:mysql", list[0].JobID)
+	assert.Equal(t, dataCleanupJobStatusFailed, list[0].Status)
+	assert.Equal(t, "boom", list[0].Error)
+	assert.Equal(t, "run2:redis", list[1].JobID)
+	assert.Equal(t, dataCleanupJobStatusRunning, list[1].Status)
+	assert.NotContains(t, m.jobs, "run0:es")
+
+	close(release)
+}
+
+func TestDataCleanupJobManagerCancel(t *testing.T) {
+	m := &dataCleanupJobManager{jobs: make(map[string]*dataCleanupJob)}
+	started := make(chan struct{})
+	_, err := m.Start("run:minio", "minio", 3, time.Minute, func(ctx context.Context, job *dataCleanupJob) error {
+		job.markItemDoneWithDeleted(40)
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.NoError(t, err)
+	<-started
+
+	snapshot, err := m.Cancel("run:minio")
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), snapshot.Deleted)
+
+	require.Eventually(t, func() bool {
+		snapshot, err = m.Snapshot("run:minio")
+		return err == nil && snapshot.Status != dataCleanupJobStatusRunning
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, dataCleanupJobStatusFailed, snapshot.Status)
+	assert.Equal(t, errDataCleanupJobCancelled.Error(), snapshot.Error)
+	assert.Equal(t, int64(40), snapshot.Deleted)
+
+	_, err = m.Cancel("missing")
+	assert.Error(t, err)
+}
+
+func TestDataCleanupJobItemDeleted(t *testing.T) {
+	job := &dataCleanupJob{status: dataCleanupJobStatusRunning, total: 2}
+
+	job.addDeleted(5000)
+	job.addDeleted(5000)
+	job.addDeleted(300)
+	snapshot := job.snapshot()
+	assert.Equal(t, int64(10300), snapshot.Deleted)
+	assert.Equal(t, int64(10300), snapshot.ItemDeleted)
+	assert.Equal(t, 0, snapshot.Done)
+
+	// the first of two concurrent items finishes after reporting 10000
+	job.markReportedItemDone(10000)
+	snapshot = job.snapshot()
+	assert.Equal(t, int64(10300), snapshot.Deleted)
+	assert.Equal(t, int64(300), snapshot.ItemDeleted)
+	assert.Equal(t, 1, snapshot.Done)
+
+	job.markReportedItemDone(300)
+	snapshot = job.snapshot()
+	assert.Equal(t, int64(0), snapshot.ItemDeleted)
+	assert.Equal(t, 2, snapshot.Done)
+}
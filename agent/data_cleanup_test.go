@@ -0,0 +1,397 @@
+//go:build testing
+// +build testing
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aether/internal/common"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountESIndices(t *testing.T) {
+	counts := map[string]string{
+		"/logs-a/_count": `{"count":3}`,
+		"/logs-b/_count": `{"count":4}`,
+	}
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		body, ok := counts[r.URL.Path]
+		if !ok {
+			http.Error(w, `{"error":"index_not_found_exception"}`, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	host, portText, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portText)
+	require.NoError(t, err)
+	req := common.DataCleanupESCleanupRequest{Host: host, Port: port, Indices: []string{"logs-a", " logs-b "}}
+
+	matched, err := countESIndices(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), matched)
+	assert.Equal(t, []string{http.MethodGet, http.MethodGet}, methods)
+
+	req.Indices = []string{"logs-a", "missing"}
+	matched, err = countESIndices(context.Background(), req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "es count response error")
+	assert.Equal(t, int64(3), matched)
+
+	_, err = countESIndices(context.Background(), common.DataCleanupESCleanupRequest{Host: host, Port: port})
+	require.Error(t, err)
+}
+
+func TestDropESIndices(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		switch r.URL.Path {
+		case "/logs-a":
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		case "/logs-b":
+			_, _ = w.Write([]byte(`{"acknowledged":false}`))
+		default:
+			http.Error(w, `{"error":"index_not_found_exception"}`, http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	host, portText, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portText)
+	require.NoError(t, err)
+	req := common.DataCleanupESCleanupRequest{Host: host, Port: port, Indices: []string{" logs-a ", "missing"}}
+
+	dropped, err := dropESIndices(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"logs-a"}, dropped)
+	assert.Equal(t, []string{http.MethodDelete, http.MethodDelete}, methods)
+
+	req.Indices = []string{"logs-a", "logs-b"}
+	dropped, err = dropESIndices(context.Background(), req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not acknowledged")
+	assert.Equal(t, []string{"logs-a"}, dropped)
+
+	_, err = common.NormalizeDataCleanupESMode("truncate")
+	require.Error(t, err)
+	for input, want := range map[string]string{"": "delete_by_query", " DROP_INDEX ": "drop_index"} {
+		mode, err := common.NormalizeDataCleanupESMode(input)
+		require.NoError(t, err)
+		assert.Equal(t, want, mode)
+	}
+}
+
+func TestPingDataCleanupModuleES(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "elastic" || pass != "secret" {
+			http.Error(w, `{"error":"security_exception"}`, http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "/", r.URL.Path)
+		_, _ = w.Write([]byte(`{"version":{"number":"8.13.0"}}`))
+	}))
+	defer server.Close()
+
+	host, portText, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portText)
+	require.NoError(t, err)
+	req := common.DataCleanupPingRequest{Module: "es", Host: host, Port: port, Username: "elastic", Password: "secret"}
+
+	require.NoError(t, pingDataCleanupModule(context.Background(), req))
+
+	req.Password = "wrong"
+	err = pingDataCleanupModule(context.Background(), req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "es ping response error")
+
+	req.Module = "mongo"
+	err = pingDataCleanupModule(context.Background(), req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid data cleanup module")
+}
+
+func TestFormatDataCleanupErrorMasksCredentials(t *testing.T) {
+	err := formatDataCleanupError("ping mysql failed", errors.New("dial failed: password=hunter2"), map[string]any{
+		"host":     "db",
+		"port":     3306,
+		"password": "hunter2",
+		"req": common.DataCleanupMySQLDatabasesRequest{
+			Host:     "db",
+			Port:     3306,
+			Username: "root",
+			Password: "hunter2",
+		},
+		"minio": &common.DataCleanupMinioCleanupRequest{
+			Host:      "minio",
+			AccessKey: "admin",
+			SecretKey: "minio-secret",
+			Bucket:    "logs",
+		},
+	})
+	message := err.Error()
+	assert.NotContains(t, message, "hunter2")
+	assert.NotContains(t, message, "minio-secret")
+	assert.Contains(t, message, "host:db")
+	assert.Contains(t, message, "Username:root")
+	assert.Contains(t, message, "Bucket:logs")
+	assert.Contains(t, message, "password="+dataCleanupRedactedValue)
+
+	// the agent masks the same fields as the hub's log redactor
+	err = formatDataCleanupError("ping es failed", errors.New("401: Authorization: Bearer abc.def token=t0ken"), map[string]any{
+		"api_key": "k3y",
+	})
+	message = err.Error()
+	assert.NotContains(t, message, "abc.def")
+	assert.NotContains(t, message, "t0ken")
+	assert.NotContains(t, message, "k3y")
+	assert.Contains(t, message, "token="+dataCleanupRedactedValue)
+}
+
+func TestDeleteMySQLTablesRejectsUnknownMode(t *testing.T) {
+	_, err := deleteMySQLTables(context.Background(), common.DataCleanupMySQLDeleteTablesRequest{
+		Host:     "127.0.0.1",
+		Port:     3306,
+		Database: "app",
+		Tables:   []string{"events"},
+		Mode:     "drop",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid mysql cleanup mode")
+
+	for input, want := range map[string]string{"": "delete", " Delete ": "delete", "TRUNCATE": "truncate"} {
+		mode, err := common.NormalizeDataCleanupMySQLMode(input)
+		require.NoError(t, err)
+		assert.Equal(t, want, mode)
+	}
+}
+
+func TestMySQLTableConditions(t *testing.T) {
+	conditions, err := mysqlTableConditions([]common.DataCleanupMySQLCondition{
+		{Table: " events ", Where: "created_at < ? AND kind = ?", Args: []string{"2024-01-01", "audit"}},
+	})
+	require.NoError(t, err)
+
+	query, args := mysqlTableQuery("DELETE FROM `events`", conditions, "events")
+	assert.Equal(t, "DELETE FROM `events` WHERE created_at < ? AND kind = ?", query)
+	assert.Equal(t, []any{"2024-01-01", "audit"}, args)
+
+	query, args = mysqlTableQuery("DELETE FROM `users`", conditions, "users")
+	assert.Equal(t, "DELETE FROM `users`", query)
+	assert.Empty(t, args)
+
+	for _, condition := range []common.DataCleanupMySQLCondition{
+		{Table: "events", Where: "id > 0; DROP TABLE users"},
+		{Table: "events", Where: "id > 0 -- comment"},
+		{Table: "events", Where: "name = 'x'"},
+		{Table: "events", Where: "created_at < ?"},
+		{Table: "events", Where: " "},
+		{Where: "id > ?", Args: []string{"1"}},
+	} {
+		_, err := mysqlTableConditions([]common.DataCleanupMySQLCondition{condition})
+		assert.Error(t, err, condition.Where)
+	}
+
+	_, err = mysqlTableConditions([]common.DataCleanupMySQLCondition{
+		{Table: "events", Where: "id > ?", Args: []string{"1"}},
+		{Table: "events", Where: "id < ?", Args: []string{"9"}},
+	})
+	assert.Error(t, err)
+
+	_, err = deleteMySQLTables(context.Background(), common.DataCleanupMySQLDeleteTablesRequest{
+		Host:       "127.0.0.1",
+		Port:       3306,
+		Database:   "app",
+		Tables:     []string{"events"},
+		Mode:       common.DataCleanupMySQLModeTruncate,
+		Conditions: []common.DataCleanupMySQLCondition{{Table: "events", Where: "id > ?", Args: []string{"1"}}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conditions require delete mode")
+}
+
+func TestDataCleanupTLSOptions(t *testing.T) {
+	assert.Nil(t, dataCleanupTLSConfig(common.DataCleanupTLS{InsecureSkipVerify: true}, "db.local"))
+
+	cfg := dataCleanupTLSConfig(common.DataCleanupTLS{UseTLS: true}, " db.local ")
+	require.NotNil(t, cfg)
+	assert.Equal(t, "db.local", cfg.ServerName)
+	assert.False(t, cfg.InsecureSkipVerify)
+
+	endpoint, err := buildHTTPURL("es.local", 9200, common.DataCleanupTLS{}, "/_count")
+	require.NoError(t, err)
+	assert.Equal(t, "http://es.local:9200/_count", endpoint)
+	endpoint, err = buildHTTPURL("es.local", 9200, common.DataCleanupTLS{UseTLS: true}, "/_count")
+	require.NoError(t, err)
+	assert.Equal(t, "https://es.local:9200/_count", endpoint)
+
+	mysqlCfg, err := newMySQLConfig(common.DataCleanupMySQLDatabasesRequest{
+		Host: "db.local",
+		Port: 3306,
+		TLS:  common.DataCleanupTLS{UseTLS: true, InsecureSkipVerify: true},
+	}, "app", time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, mysqlCfg.TLS)
+	assert.True(t, mysqlCfg.TLS.InsecureSkipVerify)
+
+	client, err := newRedisClient(common.DataCleanupRedisDatabasesRequest{Host: "cache.local", Port: 6379}, 0)
+	require.NoError(t, err)
+	assert.Nil(t, client.Options().TLSConfig)
+	_ = client.Close()
+}
+
+func TestRunMinioPrefixes(t *testing.T) {
+	prefixes := []string{"a/", "b/", "c/", "d/", "e/", "f/"}
+
+	var mu sync.Mutex
+	active, peak := 0, 0
+	total, err := runMinioPrefixes(context.Background(), prefixes, 3, func(ctx context.Context, prefix string) (int64, error) {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return int64(len(prefix)) * 10, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), total)
+	assert.LessOrEqual(t, peak, 3)
+
+	failure := errors.New("remove failed")
+	var started atomic.Int32
+	running := make(chan struct{})
+	total, err = runMinioPrefixes(context.Background(), prefixes, 2, func(ctx context.Context, prefix string) (int64, error) {
+		started.Add(1)
+		if prefix == "a/" {
+			<-running
+			return 5, failure
+		}
+		close(running)
+		<-ctx.Done()
+		return 1, ctx.Err()
+	})
+	require.ErrorIs(t, err, failure)
+	assert.Equal(t, int64(6), total)
+	assert.Equal(t, int32(2), started.Load(), "no prefix starts after a failure")
+
+	assert.Equal(t, dataCleanupMinioConcurrency, minioPrefixWorkers(0, 10))
+	assert.Equal(t, 2, minioPrefixWorkers(8, 2))
+	assert.Equal(t, common.DataCleanupMinioMaxConcurrency, minioPrefixWorkers(100, 100))
+}
+
+func TestDataCleanupRedisFilter(t *testing.T) {
+	filter, err := common.DataCleanupRedisFilter{Type: " Hash ", TTLAboveSec: 60, TTLBelowSec: 3600}.Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, "hash", filter.Type)
+	assert.True(t, filter.Active())
+	assert.False(t, common.DataCleanupRedisFilter{}.Active())
+
+	assert.True(t, filter.Matches("hash", 10*time.Minute))
+	assert.False(t, filter.Matches("string", 10*time.Minute), "other type")
+	assert.False(t, filter.Matches("hash", 30*time.Second), "ttl not above the lower bound")
+	assert.False(t, filter.Matches("hash", 2*time.Hour), "ttl not below the upper bound")
+	assert.False(t, filter.Matches("hash", -1), "no expiry never passes an upper bound")
+
+	above := common.DataCleanupRedisFilter{TTLAboveSec: 60}
+	assert.True(t, above.Matches("string", -1), "no expiry passes a lower bound")
+	assert.False(t, above.Matches("string", time.Minute))
+
+	for _, invalid := range []common.DataCleanupRedisFilter{
+		{Type: "json"},
+		{TTLBelowSec: -1},
+		{TTLAboveSec: 600, TTLBelowSec: 600},
+	} {
+		_, err := invalid.Normalize()
+		assert.Error(t, err, invalid)
+	}
+
+	keys := []string{"a", "b"}
+	kept, skipped, err := filterRedisKeys(context.Background(), nil, keys, common.DataCleanupRedisFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, keys, kept, "no filter passes keys through without querying redis")
+	assert.Zero(t, skipped)
+}
+
+func TestDataCleanupHandlersRequireConfirm(t *testing.T) {
+	// each request carries a job id but no targets, so a request that passes the confirmation
+	// check fails right after it without touching a backend
+	handlers := []struct {
+		module  string
+		handler RequestHandler
+		request func(confirm bool) any
+	}{
+		{"mysql", &DataCleanupMySQLDeleteTablesHandler{}, func(confirm bool) any {
+			return common.DataCleanupMySQLDeleteTablesRequest{JobID: "job", Confirm: confirm}
+		}},
+		{"redis", &DataCleanupRedisCleanupHandler{}, func(confirm bool) any {
+			return common.DataCleanupRedisCleanupRequest{JobID: "job", Confirm: confirm}
+		}},
+		{"minio", &DataCleanupMinioCleanupHandler{}, func(confirm bool) any {
+			return common.DataCleanupMinioCleanupRequest{JobID: "job", Confirm: confirm}
+		}},
+		{"es", &DataCleanupESCleanupHandler{}, func(confirm bool) any {
+			return common.DataCleanupESCleanupRequest{JobID: "job", Confirm: confirm}
+		}},
+	}
+	handle := func(t *testing.T, agent *Agent, handler RequestHandler, request any) error {
+		data, err := cbor.Marshal(request)
+		require.NoError(t, err)
+		return handler.Handle(&HandlerContext{
+			Agent:   agent,
+			Request: &common.HubRequest[cbor.RawMessage]{Data: data},
+			SendResponse: func(any, *uint32) error {
+				return nil
+			},
+		})
+	}
+
+	for _, test := range handlers {
+		t.Run(test.module, func(t *testing.T) {
+			safe := &Agent{dataCleanupJobs: newDataCleanupJobManager()}
+			err := handle(t, safe, test.handler, test.request(false))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), errDataCleanupNotConfirmed.Error(), "safe mode refuses an unconfirmed request")
+
+			err = handle(t, safe, test.handler, test.request(true))
+			require.Error(t, err, "the empty request still fails after the check")
+			assert.NotContains(t, err.Error(), errDataCleanupNotConfirmed.Error(), "a confirmed request passes the check")
+
+			enabled := &Agent{dataCleanupJobs: newDataCleanupJobManager(), dataCleanupEnabled: true}
+			err = handle(t, enabled, test.handler, test.request(false))
+			require.Error(t, err)
+			assert.NotContains(t, err.Error(), errDataCleanupNotConfirmed.Error(), "DATA_CLEANUP_ENABLED accepts unconfirmed requests")
+		})
+	}
+}
+
+func TestDataCleanupEnabledFromEnv(t *testing.T) {
+	t.Setenv("DATA_CLEANUP_ENABLED", "")
+	assert.False(t, dataCleanupEnabledFromEnv(), "safe mode is the default")
+	t.Setenv("DATA_CLEANUP_ENABLED", "true")
+	assert.True(t, dataCleanupEnabledFromEnv())
+	t.Setenv("AETHER_AGENT_DATA_CLEANUP_ENABLED", "false")
+	assert.False(t, dataCleanupEnabledFromEnv(), "the prefixed variable takes precedence")
+}
@@ -951,6 +951,13 @@ func (dm *dockerManager) getLogs(ctx context.Context, containerID string) (strin
 }
 
 func decodeDockerLogStream(reader io.Reader, builder *strings.Builder) error {
+	_, err := decodeDockerLogStreamLimit(reader, builder, maxTotalLogSize)
+	return err
+}
+
+// decodeDockerLogStreamLimit decodes a multiplexed log stream into builder, keeping at most limit
+// bytes. It reports whether frames were dropped because of the limit.
+func decodeDockerLogStreamLimit(reader io.Reader, builder *strings.Builder, limit int) (bool, error) {
 	const headerSize = 8
 	var header [headerSize]byte
 	totalBytesRead := 0
@@ -958,9 +965,9 @@ func decodeDockerLogStream(reader io.Reader, builder *strings.Builder) error {
 	for {
 		if _, err := io.ReadFull(reader, header[:]); err != nil {
 			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
-				return nil
+				return false, nil
 			}
-			return err
+			return false, err
 		}
 
 		frameLen := binary.BigEndian.Uint32(header[4:])
@@ -970,23 +977,23 @@ func decodeDockerLogStream(reader io.Reader, builder *strings.Builder) error {
 
 		// Prevent memory exhaustion from excessively large frames
 		if frameLen > maxLogFrameSize {
-			return fmt.Errorf("log frame size (%d) exceeds maximum (%d)", frameLen, maxLogFrameSize)
+			return false, fmt.Errorf("log frame size (%d) exceeds maximum (%d)", frameLen, maxLogFrameSize)
 		}
 
 		// Check if reading this frame would exceed total log size limit
-		if totalBytesRead+int(frameLen) > maxTotalLogSize {
+		if totalBytesRead+int(frameLen) > limit {
 			// Read and discard remaining data to avoid blocking
 			_, _ = io.CopyN(io.Discard, reader, int64(frameLen))
-			slog.Debug("Truncating logs: limit reached", "read", totalBytesRead, "limit", maxTotalLogSize)
-			return nil
+			slog.Debug("Truncating logs: limit reached", "read", totalBytesRead, "limit", limit)
+			return true, nil
 		}
 
 		n, err := io.CopyN(builder, reader, int64(frameLen))
 		if err != nil {
 			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
-				return nil
+				return false, nil
 			}
-			return err
+			return false, err
 		}
 		totalBytesRead += int(n)
 	}
@@ -0,0 +1,145 @@
+// docker_sdk_compose_logs.go 实现编排项目的合并日志读取。
+// 按 compose 项目标签找到全部容器，读取 (Since, Until] 时间窗内的日志并按时间合并，供 hub 轮询实现实时日志流。
+// 每个服务单次最多返回 Tail 行、MaxBytesPerService 字节，超出部分丢弃并在结果中标记。
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"aether/internal/common"
+	dockermodel "aether/internal/entities/docker"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+)
+
+const (
+	composeLogsDefaultTail            = 100
+	composeLogsMaxTail                = 1000
+	composeLogsDefaultBytesPerService = 256 * 1024
+	composeLogsMaxBytesPerService     = 1024 * 1024
+)
+
+// GetComposeLogs 返回编排项目全部容器在时间窗内的日志，按时间升序合并。
+func (dm *dockerSDKManager) GetComposeLogs(req common.DockerComposeLogsRequest) (*dockermodel.ComposeLogs, error) {
+	if err := dm.ensureAvailable(); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("compose project name is required")
+	}
+	tail := req.Tail
+	if tail <= 0 {
+		tail = composeLogsDefaultTail
+	}
+	tail = min(tail, composeLogsMaxTail)
+	maxBytes := req.MaxBytesPerService
+	if maxBytes <= 0 {
+		maxBytes = composeLogsDefaultBytesPerService
+	}
+	maxBytes = min(maxBytes, composeLogsMaxBytesPerService)
+	until := req.Until
+	if until <= 0 {
+		until = time.Now().UnixNano()
+	}
+
+	ctx, cancel := dm.newTimeoutContext()
+	defer cancel()
+	args := filters.NewArgs(filters.Arg("label", composeProjectLabel+"="+name))
+	containers, err := dm.client.ContainerList(ctx, container.ListOptions{All: true, Filters: args})
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("compose project %s has no containers", name)
+	}
+
+	result := &dockermodel.ComposeLogs{
+		Lines:  make([]dockermodel.ComposeLogLine, 0),
+		Cursor: until,
+	}
+	for _, item := range containers {
+		containerName := item.ID
+		if len(item.Names) > 0 {
+			containerName = strings.TrimPrefix(item.Names[0], "/")
+		}
+		service := strings.TrimSpace(item.Labels[composeServiceLabel])
+		if service == "" {
+			service = containerName
+		}
+		options := container.LogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Timestamps: true,
+			Tail:       strconv.Itoa(tail),
+			Until:      formatDockerLogTimestamp(until),
+		}
+		if req.Since > 0 {
+			options.Since = formatDockerLogTimestamp(req.Since)
+		}
+		reader, err := dm.client.ContainerLogs(ctx, item.ID, options)
+		if err != nil {
+			return nil, fmt.Errorf("read logs of %s failed: %w", containerName, err)
+		}
+		var builder strings.Builder
+		truncated, err := decodeDockerLogStreamLimit(reader, &builder, maxBytes)
+		_ = reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read logs of %s failed: %w", containerName, err)
+		}
+		if truncated && !slices.Contains(result.Truncated, service) {
+			result.Truncated = append(result.Truncated, service)
+		}
+		result.Lines = append(result.Lines, parseComposeLogLines(service, containerName, builder.String(), req.Since, until)...)
+	}
+	sort.SliceStable(result.Lines, func(i, j int) bool {
+		return result.Lines[i].Timestamp < result.Lines[j].Timestamp
+	})
+	return result, nil
+}
+
+// formatDockerLogTimestamp 将 unix 纳秒格式化为 Docker 日志接口接受的 "秒.纳秒"。
+func formatDockerLogTimestamp(nanos int64) string {
+	return fmt.Sprintf("%d.%09d", nanos/int64(time.Second), nanos%int64(time.Second))
+}
+
+// parseComposeLogLines 解析带时间戳前缀的日志，丢弃时间窗 (since, until] 之外的行（Docker 的 since 含边界）。
+// 无法解析时间戳的行沿用上一行的时间。
+func parseComposeLogLines(service, containerName, raw string, since, until int64) []dockermodel.ComposeLogLine {
+	if strings.Contains(raw, "\x1b") {
+		raw = ansiEscapePattern.ReplaceAllString(raw, "")
+	}
+	lines := make([]dockermodel.ComposeLogLine, 0)
+	last := since
+	for text := range strings.SplitSeq(raw, "\n") {
+		text = strings.TrimRight(text, "\r")
+		if text == "" {
+			continue
+		}
+		timestamp := last
+		if prefix, rest, ok := strings.Cut(text, " "); ok {
+			if parsed, err := time.Parse(time.RFC3339Nano, prefix); err == nil {
+				timestamp = parsed.UnixNano()
+				text = rest
+			}
+		}
+		last = timestamp
+		if (since > 0 && timestamp <= since) || timestamp > until {
+			continue
+		}
+		lines = append(lines, dockermodel.ComposeLogLine{
+			Service:   service,
+			Container: containerName,
+			Timestamp: timestamp,
+			Line:      text,
+		})
+	}
+	return lines
+}
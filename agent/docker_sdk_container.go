@@ -1,5 +1,5 @@
 // docker_sdk_container.go 实现容器相关的 Docker SDK 操作。
-// 包括容器列表、详情、日志与启停操作。
+// 包括容器列表、详情、日志、实时资源读数、文件系统变更与启停操作。
 package agent
 
 import (
@@ -20,6 +20,13 @@ const (
 	composeServiceLabel     = "com.docker.compose.service"
 )
 
+const (
+	// containerDiffDefaultLimit 为未指定上限时返回的最大变更数。
+	containerDiffDefaultLimit = 1000
+	// containerDiffMaxLimit 限制单次返回的变更数，避免大量变更撑爆响应。
+	containerDiffMaxLimit = 10000
+)
+
 func (dm *dockerSDKManager) ListContainers(all bool) ([]dockermodel.Container, error) {
 	if err := dm.ensureAvailable(); err != nil {
 		return nil, err
@@ -96,6 +103,117 @@ func (dm *dockerSDKManager) GetContainerInfo(containerID string) ([]byte, error)
 	return json.Marshal(info)
 }
 
+// GetContainerStats 返回容器当前的 CPU、内存与网络读数。
+// 非流式请求时 daemon 会间隔采样两次并填充 precpu_stats，单次请求即可计算 CPU 使用率。
+func (dm *dockerSDKManager) GetContainerStats(containerID string) (*dockermodel.ContainerStats, error) {
+	if err := dm.ensureAvailable(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(containerID) == "" {
+		return nil, errors.New("container id is required")
+	}
+	ctx, cancel := dm.newOperateTimeoutContext()
+	defer cancel()
+
+	reader, err := dm.client.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Body.Close()
+
+	var stats container.StatsResponse
+	if err := json.NewDecoder(reader.Body).Decode(&stats); err != nil {
+		return nil, err
+	}
+	return buildContainerStats(stats, reader.OSType == "windows"), nil
+}
+
+// buildContainerStats 计算单次读数：CPU 为两次采样间占宿主机 CPU 的百分比，内存按定期采集的口径扣除缓存。
+func buildContainerStats(stats container.StatsResponse, isWindows bool) *dockermodel.ContainerStats {
+	result := &dockermodel.ContainerStats{
+		ID:       stats.ID,
+		Name:     strings.TrimPrefix(stats.Name, "/"),
+		MemLimit: stats.MemoryStats.Limit,
+		Read:     stats.Read,
+	}
+	cpuDelta := stats.CPUStats.CPUUsage.TotalUsage - stats.PreCPUStats.CPUUsage.TotalUsage
+	if isWindows {
+		// Windows 以 100ns 为单位计量 CPU 时间
+		intervals := uint64(stats.Read.Sub(stats.PreRead).Nanoseconds()) / 100 * uint64(stats.NumProcs)
+		if intervals > 0 && stats.CPUStats.CPUUsage.TotalUsage >= stats.PreCPUStats.CPUUsage.TotalUsage {
+			result.Cpu = float64(cpuDelta) / float64(intervals) * 100
+		}
+		result.MemUsed = stats.MemoryStats.PrivateWorkingSet
+	} else {
+		systemDelta := stats.CPUStats.SystemUsage - stats.PreCPUStats.SystemUsage
+		if stats.PreCPUStats.SystemUsage > 0 && stats.CPUStats.SystemUsage > stats.PreCPUStats.SystemUsage &&
+			stats.CPUStats.CPUUsage.TotalUsage >= stats.PreCPUStats.CPUUsage.TotalUsage {
+			result.Cpu = float64(cpuDelta) / float64(systemDelta) * 100
+		}
+		cache := stats.MemoryStats.Stats["inactive_file"]
+		if cache == 0 {
+			cache = stats.MemoryStats.Stats["cache"]
+		}
+		if stats.MemoryStats.Usage > cache {
+			result.MemUsed = stats.MemoryStats.Usage - cache
+		}
+	}
+	for _, network := range stats.Networks {
+		result.NetSent += network.TxBytes
+		result.NetRecv += network.RxBytes
+	}
+	return result
+}
+
+// GetContainerDiff 返回容器文件系统相对镜像的变更，最多 limit 项。
+func (dm *dockerSDKManager) GetContainerDiff(containerID string, limit int) (*dockermodel.ContainerDiff, error) {
+	if err := dm.ensureAvailable(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(containerID) == "" {
+		return nil, errors.New("container id is required")
+	}
+	ctx, cancel := dm.newTimeoutContext()
+	defer cancel()
+
+	changes, err := dm.client.ContainerDiff(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	return buildContainerDiff(changes, limit), nil
+}
+
+// buildContainerDiff 转换变更列表并按 limit 截断，limit 非正时使用默认值，超过上限时取上限。
+func buildContainerDiff(changes []container.FilesystemChange, limit int) *dockermodel.ContainerDiff {
+	if limit <= 0 {
+		limit = containerDiffDefaultLimit
+	}
+	limit = min(limit, containerDiffMaxLimit)
+	diff := &dockermodel.ContainerDiff{
+		Changes:   make([]dockermodel.ContainerChange, 0, min(len(changes), limit)),
+		Total:     len(changes),
+		Truncated: len(changes) > limit,
+	}
+	for _, change := range changes[:min(len(changes), limit)] {
+		diff.Changes = append(diff.Changes, dockermodel.ContainerChange{
+			Path: change.Path,
+			Kind: containerChangeKind(change.Kind),
+		})
+	}
+	return diff
+}
+
+func containerChangeKind(kind container.ChangeType) string {
+	switch kind {
+	case container.ChangeAdd:
+		return "added"
+	case container.ChangeDelete:
+		return "deleted"
+	default:
+		return "modified"
+	}
+}
+
 func (dm *dockerSDKManager) GetContainerLogs(containerID string) (string, error) {
 	if err := dm.ensureAvailable(); err != nil {
 		return "", err
@@ -0,0 +1,81 @@
+// docker_sdk_exec.go 实现在容器内执行单次命令。
+// 不分配 TTY、不接收输入，合并捕获 stdout/stderr（超过上限的部分丢弃并标记），命令结束或超时后返回退出码。
+package agent
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"aether/internal/common"
+	dockermodel "aether/internal/entities/docker"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+const (
+	// containerExecTimeout 限制单次命令的执行时长。
+	containerExecTimeout = 30 * time.Second
+	// containerExecMaxOutput 限制捕获的输出字节数。
+	containerExecMaxOutput = 256 * 1024
+)
+
+// ExecInContainer 在容器内执行命令并返回合并的输出与退出码。
+func (dm *dockerSDKManager) ExecInContainer(req common.ContainerExecRequest) (*dockermodel.ExecResult, error) {
+	if err := dm.ensureAvailable(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.ContainerID) == "" {
+		return nil, errors.New("container id is required")
+	}
+	if len(req.Cmd) == 0 || strings.TrimSpace(req.Cmd[0]) == "" {
+		return nil, errors.New("command is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), containerExecTimeout)
+	defer cancel()
+
+	created, err := dm.client.ContainerExecCreate(ctx, req.ContainerID, container.ExecOptions{
+		Cmd:          req.Cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	attached, err := dm.client.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer attached.Close()
+	// 读取劫持连接不受 ctx 约束，用连接截止时间实现超时
+	if deadline, ok := ctx.Deadline(); ok && attached.Conn != nil {
+		_ = attached.Conn.SetReadDeadline(deadline)
+	}
+
+	var output strings.Builder
+	truncated, readErr := decodeDockerLogStreamLimit(attached.Reader, &output, containerExecMaxOutput)
+	result := &dockermodel.ExecResult{
+		Output:    output.String(),
+		ExitCode:  -1,
+		Truncated: truncated,
+	}
+	if readErr != nil {
+		var netErr net.Error
+		if ctx.Err() != nil || (errors.As(readErr, &netErr) && netErr.Timeout()) {
+			result.TimedOut = true
+			return result, nil
+		}
+		return nil, readErr
+	}
+
+	inspect, err := dm.client.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !inspect.Running {
+		result.ExitCode = inspect.ExitCode
+	}
+	return result, nil
+}
@@ -1,5 +1,5 @@
 // docker_sdk_image.go 实现镜像相关的 Docker SDK 操作。
-// 包括镜像列表、拉取、推送与删除。
+// 包括镜像列表、拉取、推送、删除、清理悬空镜像与镜像仓库登录校验。
 package agent
 
 import (
@@ -11,6 +11,7 @@ import (
 	"aether/internal/common"
 	dockermodel "aether/internal/entities/docker"
 
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/registry"
 )
@@ -113,6 +114,53 @@ func (dm *dockerSDKManager) RemoveImage(imageID string, force bool) error {
 	return err
 }
 
+// PruneImages 删除悬空镜像（无标签且未被容器使用），返回被删除的镜像与回收的空间。
+func (dm *dockerSDKManager) PruneImages() (*dockermodel.ImagePruneReport, error) {
+	if err := dm.ensureAvailable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := dm.newOperateTimeoutContext()
+	defer cancel()
+
+	report, err := dm.client.ImagesPrune(ctx, filters.NewArgs(filters.Arg("dangling", "true")))
+	if err != nil {
+		return nil, err
+	}
+	return buildImagePruneReport(report), nil
+}
+
+// buildImagePruneReport 转换清理结果，仅保留实际删除的镜像 ID（忽略仅移除标签的条目）。
+func buildImagePruneReport(report image.PruneReport) *dockermodel.ImagePruneReport {
+	result := &dockermodel.ImagePruneReport{
+		ImagesDeleted:  make([]string, 0, len(report.ImagesDeleted)),
+		SpaceReclaimed: report.SpaceReclaimed,
+	}
+	for _, item := range report.ImagesDeleted {
+		if item.Deleted != "" {
+			result.ImagesDeleted = append(result.ImagesDeleted, item.Deleted)
+		}
+	}
+	return result
+}
+
+// RegistryLogin 使用给定凭据登录镜像仓库以校验凭据，返回 daemon 的登录状态信息，不保存凭据。
+func (dm *dockerSDKManager) RegistryLogin(auth *registry.AuthConfig) (string, error) {
+	if err := dm.ensureAvailable(); err != nil {
+		return "", err
+	}
+	if auth == nil || strings.TrimSpace(auth.ServerAddress) == "" {
+		return "", errors.New("registry server is required")
+	}
+	ctx, cancel := dm.newOperateTimeoutContext()
+	defer cancel()
+
+	result, err := dm.client.RegistryLogin(ctx, *auth)
+	if err != nil {
+		return "", err
+	}
+	return result.Status, nil
+}
+
 // readLimitedStream 读取 Docker 返回的日志流，并限制最大读取长度。
 func readLimitedStream(reader io.Reader, limit int64) (string, error) {
 	if limit <= 0 {
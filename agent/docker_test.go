@@ -13,6 +13,8 @@ import (
 
 	"aether/agent/deltatracker"
 	"aether/internal/entities/container"
+	dockercontainer "github.com/docker/docker/api/types/container"
+	dockerimage "github.com/docker/docker/api/types/image"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -1235,3 +1237,145 @@ func TestAnsiEscapePattern(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildContainerDiff(t *testing.T) {
+	changes := []dockercontainer.FilesystemChange{
+		{Kind: dockercontainer.ChangeModify, Path: "/etc"},
+		{Kind: dockercontainer.ChangeAdd, Path: "/etc/app.conf"},
+		{Kind: dockercontainer.ChangeDelete, Path: "/tmp/old"},
+	}
+
+	diff := buildContainerDiff(changes, 0)
+	assert.Equal(t, 3, diff.Total)
+	assert.False(t, diff.Truncated)
+	require.Len(t, diff.Changes, 3)
+	assert.Equal(t, "modified", diff.Changes[0].Kind)
+	assert.Equal(t, "added", diff.Changes[1].Kind)
+	assert.Equal(t, "/etc/app.conf", diff.Changes[1].Path)
+	assert.Equal(t, "deleted", diff.Changes[2].Kind)
+
+	diff = buildContainerDiff(changes, 2)
+	assert.Equal(t, 3, diff.Total)
+	assert.True(t, diff.Truncated)
+	assert.Len(t, diff.Changes, 2)
+
+	many := make([]dockercontainer.FilesystemChange, containerDiffMaxLimit+1)
+	diff = buildContainerDiff(many, containerDiffMaxLimit*2)
+	assert.True(t, diff.Truncated)
+	assert.Len(t, diff.Changes, containerDiffMaxLimit)
+}
+
+func TestDecodeDockerLogStreamLimit(t *testing.T) {
+	frame := func(text string) []byte {
+		size := len(text)
+		return append([]byte{0x01, 0x00, 0x00, 0x00, byte(size >> 24), byte(size >> 16), byte(size >> 8), byte(size)}, text...)
+	}
+	input := append(append(frame("first\n"), frame("second\n")...), frame("third\n")...)
+
+	var builder strings.Builder
+	truncated, err := decodeDockerLogStreamLimit(bytes.NewReader(input), &builder, 13)
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Equal(t, "first\nsecond\n", builder.String())
+
+	builder.Reset()
+	truncated, err = decodeDockerLogStreamLimit(bytes.NewReader(input), &builder, 1024)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	assert.Equal(t, "first\nsecond\nthird\n", builder.String())
+}
+
+func TestParseComposeLogLines(t *testing.T) {
+	base := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) string {
+		return base.Add(offset).Format(time.RFC3339Nano)
+	}
+	raw := at(time.Second) + " boundary\n" +
+		at(2*time.Second) + " \x1b[32mstarted\x1b[0m\r\n" +
+		"continuation without timestamp\n" +
+		"\n" +
+		at(5*time.Second) + " after until\n"
+
+	since := base.Add(time.Second).UnixNano()
+	until := base.Add(3 * time.Second).UnixNano()
+	lines := parseComposeLogLines("web", "app-web-1", raw, since, until)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "web", lines[0].Service)
+	assert.Equal(t, "app-web-1", lines[0].Container)
+	assert.Equal(t, "started", lines[0].Line)
+	assert.Equal(t, base.Add(2*time.Second).UnixNano(), lines[0].Timestamp)
+	assert.Equal(t, "continuation without timestamp", lines[1].Line)
+	assert.Equal(t, lines[0].Timestamp, lines[1].Timestamp)
+
+	// since 为 0 时返回 until 之前的全部行
+	lines = parseComposeLogLines("web", "app-web-1", raw, 0, until)
+	assert.Len(t, lines, 3)
+
+	assert.Equal(t, "1760515200.000000005", formatDockerLogTimestamp(1760515200000000005))
+}
+
+func TestBuildImagePruneReport(t *testing.T) {
+	report := buildImagePruneReport(dockerimage.PruneReport{
+		ImagesDeleted: []dockerimage.DeleteResponse{
+			{Untagged: "app@sha256:abc"},
+			{Deleted: "sha256:111"},
+			{Deleted: "sha256:222"},
+		},
+		SpaceReclaimed: 4096,
+	})
+	assert.Equal(t, []string{"sha256:111", "sha256:222"}, report.ImagesDeleted)
+	assert.Equal(t, uint64(4096), report.SpaceReclaimed)
+
+	empty := buildImagePruneReport(dockerimage.PruneReport{})
+	assert.NotNil(t, empty.ImagesDeleted)
+	assert.Empty(t, empty.ImagesDeleted)
+}
+
+func TestBuildContainerStats(t *testing.T) {
+	read := time.Date(2026, 10, 15, 12, 0, 1, 0, time.UTC)
+	stats := dockercontainer.StatsResponse{
+		Name:    "/web",
+		ID:      "abc123",
+		Read:    read,
+		PreRead: read.Add(-time.Second),
+		CPUStats: dockercontainer.CPUStats{
+			CPUUsage:    dockercontainer.CPUUsage{TotalUsage: 300},
+			SystemUsage: 2000,
+		},
+		PreCPUStats: dockercontainer.CPUStats{
+			CPUUsage:    dockercontainer.CPUUsage{TotalUsage: 100},
+			SystemUsage: 1000,
+		},
+		MemoryStats: dockercontainer.MemoryStats{
+			Usage: 1000,
+			Limit: 4000,
+			Stats: map[string]uint64{"inactive_file": 200},
+		},
+		Networks: map[string]dockercontainer.NetworkStats{
+			"eth0": {RxBytes: 10, TxBytes: 20},
+			"eth1": {RxBytes: 1, TxBytes: 2},
+		},
+	}
+
+	result := buildContainerStats(stats, false)
+	assert.Equal(t, "web", result.Name)
+	assert.Equal(t, "abc123", result.ID)
+	assert.InDelta(t, 20.0, result.Cpu, 0.001)
+	assert.Equal(t, uint64(800), result.MemUsed)
+	assert.Equal(t, uint64(4000), result.MemLimit)
+	assert.Equal(t, uint64(22), result.NetSent)
+	assert.Equal(t, uint64(11), result.NetRecv)
+	assert.Equal(t, read, result.Read)
+
+	// first sample without a previous reading reports no CPU
+	stats.PreCPUStats = dockercontainer.CPUStats{}
+	assert.Zero(t, buildContainerStats(stats, false).Cpu)
+
+	stats.NumProcs = 1
+	stats.PreCPUStats.CPUUsage.TotalUsage = 0
+	stats.CPUStats.CPUUsage.TotalUsage = 5_000_000 // half of one second in 100ns units
+	stats.MemoryStats.PrivateWorkingSet = 1234
+	windows := buildContainerStats(stats, true)
+	assert.InDelta(t, 50.0, windows.Cpu, 0.001)
+	assert.Equal(t, uint64(1234), windows.MemUsed)
+}
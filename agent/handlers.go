@@ -55,6 +55,9 @@ func NewHandlerRegistry() *HandlerRegistry {
 	registry.Register(common.CheckFingerprint, &CheckFingerprintHandler{})
 	registry.Register(common.GetContainerLogs, &GetContainerLogsHandler{})
 	registry.Register(common.GetContainerInfo, &GetContainerInfoHandler{})
+	registry.Register(common.GetContainerDiff, &GetContainerDiffHandler{})
+	registry.Register(common.GetContainerStats, &GetContainerStatsHandler{})
+	registry.Register(common.ExecInContainer, &ExecInContainerHandler{})
 	registry.Register(common.OperateContainer, &OperateContainerHandler{})
 	registry.Register(common.GetDockerOverview, &GetDockerOverviewHandler{})
 	registry.Register(common.ListDockerContainers, &ListDockerContainersHandler{})
@@ -62,6 +65,8 @@ func NewHandlerRegistry() *HandlerRegistry {
 	registry.Register(common.PullDockerImage, &PullDockerImageHandler{})
 	registry.Register(common.PushDockerImage, &PushDockerImageHandler{})
 	registry.Register(common.RemoveDockerImage, &RemoveDockerImageHandler{})
+	registry.Register(common.PruneDockerImages, &PruneDockerImagesHandler{})
+	registry.Register(common.DockerRegistryLogin, &DockerRegistryLoginHandler{})
 	registry.Register(common.ListDockerNetworks, &ListDockerNetworksHandler{})
 	registry.Register(common.CreateDockerNetwork, &CreateDockerNetworkHandler{})
 	registry.Register(common.RemoveDockerNetwork, &RemoveDockerNetworkHandler{})
@@ -69,6 +74,7 @@ func NewHandlerRegistry() *HandlerRegistry {
 	registry.Register(common.CreateDockerVolume, &CreateDockerVolumeHandler{})
 	registry.Register(common.RemoveDockerVolume, &RemoveDockerVolumeHandler{})
 	registry.Register(common.ListDockerComposeProjects, &ListDockerComposeProjectsHandler{})
+	registry.Register(common.GetDockerComposeLogs, &GetDockerComposeLogsHandler{})
 	registry.Register(common.CreateDockerComposeProject, &CreateDockerComposeProjectHandler{})
 	registry.Register(common.UpdateDockerComposeProject, &UpdateDockerComposeProjectHandler{})
 	registry.Register(common.OperateDockerComposeProject, &OperateDockerComposeProjectHandler{})
@@ -89,6 +95,11 @@ func NewHandlerRegistry() *HandlerRegistry {
 	registry.Register(common.DataCleanupESIndices, &DataCleanupESIndicesHandler{})
 	registry.Register(common.DataCleanupESCleanup, &DataCleanupESCleanupHandler{})
 	registry.Register(common.DataCleanupJobStatus, &DataCleanupJobStatusHandler{})
+	registry.Register(common.DataCleanupRedisMatchCount, &DataCleanupRedisMatchCountHandler{})
+	registry.Register(common.DataCleanupMinioMatchCount, &DataCleanupMinioMatchCountHandler{})
+	registry.Register(common.DataCleanupJobList, &DataCleanupJobListHandler{})
+	registry.Register(common.DataCleanupJobCancel, &DataCleanupJobCancelHandler{})
+	registry.Register(common.DataCleanupPing, &DataCleanupPingHandler{})
 
 	return registry
 }
@@ -213,6 +224,80 @@ func (h *GetContainerInfoHandler) Handle(hctx *HandlerContext) error {
 	return hctx.SendResponse(string(info), hctx.RequestID)
 }
 
+// //////////////////////////////////////////////////////////////////////////
+// //////////////////////////////////////////////////////////////////////////
+// GetContainerDiffHandler handles container filesystem change requests
+type GetContainerDiffHandler struct{}
+
+func (h *GetContainerDiffHandler) Handle(hctx *HandlerContext) error {
+	sdk, err := hctx.Agent.getDockerSDK()
+	if err != nil {
+		return err
+	}
+
+	var req common.ContainerDiffRequest
+	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
+		return err
+	}
+
+	diff, err := sdk.GetContainerDiff(req.ContainerID, req.Limit)
+	if err != nil {
+		return err
+	}
+
+	return hctx.SendResponse(diff, hctx.RequestID)
+}
+
+// //////////////////////////////////////////////////////////////////////////
+// //////////////////////////////////////////////////////////////////////////
+// GetContainerStatsHandler handles live container stats requests
+type GetContainerStatsHandler struct{}
+
+func (h *GetContainerStatsHandler) Handle(hctx *HandlerContext) error {
+	sdk, err := hctx.Agent.getDockerSDK()
+	if err != nil {
+		return err
+	}
+
+	var req common.ContainerStatsRequest
+	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
+		return err
+	}
+
+	stats, err := sdk.GetContainerStats(req.ContainerID)
+	if err != nil {
+		return err
+	}
+
+	return hctx.SendResponse(stats, hctx.RequestID)
+}
+
+// //////////////////////////////////////////////////////////////////////////
+// //////////////////////////////////////////////////////////////////////////
+// ExecInContainerHandler handles single-shot container command requests
+type ExecInContainerHandler struct{}
+
+func (h *ExecInContainerHandler) Handle(hctx *HandlerContext) error {
+	sdk, err := hctx.Agent.getDockerSDK()
+	if err != nil {
+		return err
+	}
+
+	var req common.ContainerExecRequest
+	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
+		return err
+	}
+
+	operationStart := time.Now()
+	result, err := sdk.ExecInContainer(req)
+	if err != nil {
+		slog.Error("Container exec failed", "container", req.ContainerID, "durationMs", time.Since(operationStart).Milliseconds(), "err", err)
+		return err
+	}
+	slog.Info("Container exec done", "container", req.ContainerID, "exitCode", result.ExitCode, "timedOut", result.TimedOut, "durationMs", time.Since(operationStart).Milliseconds())
+	return hctx.SendResponse(result, hctx.RequestID)
+}
+
 // //////////////////////////////////////////////////////////////////////////
 // //////////////////////////////////////////////////////////////////////////
 // OperateContainerHandler handles start/stop/restart/kill/pause/unpause
@@ -367,6 +452,47 @@ func (h *RemoveDockerImageHandler) Handle(hctx *HandlerContext) error {
 	return hctx.SendResponse("ok", hctx.RequestID)
 }
 
+// PruneDockerImagesHandler handles dangling Docker image prune requests
+type PruneDockerImagesHandler struct{}
+
+func (h *PruneDockerImagesHandler) Handle(hctx *HandlerContext) error {
+	sdk, err := hctx.Agent.getDockerSDK()
+	if err != nil {
+		return err
+	}
+	operationStart := time.Now()
+	slog.Info("Prune images start")
+	report, err := sdk.PruneImages()
+	if err != nil {
+		slog.Error("Prune images failed", "durationMs", time.Since(operationStart).Milliseconds(), "err", err)
+		return err
+	}
+	slog.Info("Prune images done", "deleted", len(report.ImagesDeleted), "spaceReclaimed", report.SpaceReclaimed, "durationMs", time.Since(operationStart).Milliseconds())
+	return hctx.SendResponse(report, hctx.RequestID)
+}
+
+// DockerRegistryLoginHandler handles Docker registry credential checks
+type DockerRegistryLoginHandler struct{}
+
+func (h *DockerRegistryLoginHandler) Handle(hctx *HandlerContext) error {
+	sdk, err := hctx.Agent.getDockerSDK()
+	if err != nil {
+		return err
+	}
+	var req common.DockerRegistryLoginRequest
+	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
+		return err
+	}
+	operationStart := time.Now()
+	status, err := sdk.RegistryLogin(buildAuthConfig(&req.Registry))
+	if err != nil {
+		slog.Warn("Registry login failed", "server", req.Registry.Server, "durationMs", time.Since(operationStart).Milliseconds(), "err", err)
+		return err
+	}
+	slog.Info("Registry login done", "server", req.Registry.Server, "durationMs", time.Since(operationStart).Milliseconds())
+	return hctx.SendResponse(status, hctx.RequestID)
+}
+
 // ListDockerNetworksHandler handles Docker network list requests
 type ListDockerNetworksHandler struct{}
 
@@ -511,6 +637,28 @@ func (h *ListDockerComposeProjectsHandler) Handle(hctx *HandlerContext) error {
 	return hctx.SendResponse(projects, hctx.RequestID)
 }
 
+// GetDockerComposeLogsHandler handles interleaved compose project log requests
+type GetDockerComposeLogsHandler struct{}
+
+func (h *GetDockerComposeLogsHandler) Handle(hctx *HandlerContext) error {
+	sdk, err := hctx.Agent.getDockerSDK()
+	if err != nil {
+		return err
+	}
+
+	var req common.DockerComposeLogsRequest
+	if err := cbor.Unmarshal(hctx.Request.Data, &req); err != nil {
+		return err
+	}
+
+	logs, err := sdk.GetComposeLogs(req)
+	if err != nil {
+		return err
+	}
+
+	return hctx.SendResponse(logs, hctx.RequestID)
+}
+
 // CreateDockerComposeProjectHandler handles compose project creation
 type CreateDockerComposeProjectHandler struct{}
 
@@ -194,6 +194,16 @@ func (a *Agent) handleSSHRequest(w io.Writer, req *common.HubRequest[cbor.RawMes
 			response.DockerComposeProjects = v
 		case *dockermodel.DaemonConfig:
 			response.DockerConfig = v
+		case *dockermodel.ContainerDiff:
+			response.DockerContainerDiff = v
+		case *dockermodel.ComposeLogs:
+			response.DockerComposeLogs = v
+		case *dockermodel.ImagePruneReport:
+			response.DockerImagePrune = v
+		case *dockermodel.ContainerStats:
+			response.DockerContainerStats = v
+		case *dockermodel.ExecResult:
+			response.DockerContainerExec = v
 		case []repo.Source:
 			response.RepoSources = v
 		case *common.DockerDataCleanupList:
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces credential values in logs and error details.
+const RedactedValue = "******"
+
+// DefaultRedactFields are the field names whose values are always masked (case-insensitive).
+// Prefixed names such as mysql_password or x-auth-token match as well.
+var DefaultRedactFields = []string{
+	"authorization",
+	"cookie",
+	"password",
+	"passwd",
+	"secret",
+	"secret_key",
+	"secretkey",
+	"token",
+	"api_key",
+	"apikey",
+	"api-key",
+}
+
+// RedactFieldPattern matches key: value, key=value and "key":"value" pairs whose key contains one
+// of fields, including the Field:value form %+v prints for structs and maps. The value may carry
+// a Bearer/Basic prefix. The key, separator and prefix are captured as ${1}.
+func RedactFieldPattern(fields []string) *regexp.Regexp {
+	names := make([]string, 0, len(fields))
+	for _, name := range fields {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, regexp.QuoteMeta(name))
+		}
+	}
+	return regexp.MustCompile(`(?i)("?[\w-]*(?:` + strings.Join(names, "|") + `)"?\s*[:=]\s*"?(?:(?:bearer|basic)\s+)?)[^"\s,;&}\]]+`)
+}
+
+// RedactCredentialPattern matches credentials without a field name, such as "Bearer eyJ..." in an
+// error message. The scheme is captured as ${1}.
+var RedactCredentialPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`)
+
+// defaultRedactPattern is RedactFieldPattern of DefaultRedactFields.
+var defaultRedactPattern = RedactFieldPattern(DefaultRedactFields)
+
+// RedactText masks the values of DefaultRedactFields and bare credentials in text.
+func RedactText(text string) string {
+	text = defaultRedactPattern.ReplaceAllString(text, "${1}"+RedactedValue)
+	return RedactCredentialPattern.ReplaceAllString(text, "${1} "+RedactedValue)
+}
+
+// IsRedactKey reports whether key names one of DefaultRedactFields.
+func IsRedactKey(key string) bool {
+	return defaultRedactPattern.MatchString(key + "=x")
+}
@@ -1,6 +1,11 @@
 package common
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"aether/internal/entities/docker"
 	"aether/internal/entities/repo"
 	"aether/internal/entities/smart"
@@ -87,6 +92,28 @@ const (
 	DataCleanupESCleanup
 	// Query data cleanup job status
 	DataCleanupJobStatus
+	// Count Redis keys matching a single pattern (read-only)
+	DataCleanupRedisMatchCount
+	// Count MinIO objects under a single prefix (read-only)
+	DataCleanupMinioMatchCount
+	// List data cleanup jobs still retained by the agent
+	DataCleanupJobList
+	// Request container filesystem changes (docker diff)
+	GetContainerDiff
+	// Request interleaved recent logs of all services in a compose project
+	GetDockerComposeLogs
+	// Prune dangling Docker images
+	PruneDockerImages
+	// Check Docker registry credentials by logging in
+	DockerRegistryLogin
+	// Request a live stats reading of one container
+	GetContainerStats
+	// Run a single command in a container and capture its output
+	ExecInContainer
+	// Cancel a running data cleanup job
+	DataCleanupJobCancel
+	// Check connectivity and credentials of a data cleanup module
+	DataCleanupPing
 	// Add new actions here...
 )
 
@@ -116,6 +143,11 @@ type AgentResponse struct {
 	RepoSources           []repo.Source              `cbor:"14,keyasint,omitempty,omitzero"`
 	DataCleanupList       *DockerDataCleanupList     `cbor:"15,keyasint,omitempty,omitzero"`
 	DataCleanupResult     *DockerDataCleanupResult   `cbor:"16,keyasint,omitempty,omitzero"`
+	DockerContainerDiff   *docker.ContainerDiff      `cbor:"17,keyasint,omitempty,omitzero"`
+	DockerComposeLogs     *docker.ComposeLogs        `cbor:"18,keyasint,omitempty,omitzero"`
+	DockerImagePrune      *docker.ImagePruneReport   `cbor:"19,keyasint,omitempty,omitzero"`
+	DockerContainerStats  *docker.ContainerStats     `cbor:"20,keyasint,omitempty,omitzero"`
+	DockerContainerExec   *docker.ExecResult         `cbor:"21,keyasint,omitempty,omitzero"`
 	// Logs        *LogsPayload         `cbor:"4,keyasint,omitempty,omitzero"`
 	// RawBytes    []byte               `cbor:"4,keyasint,omitempty,omitzero"`
 }
@@ -146,6 +178,23 @@ type ContainerInfoRequest struct {
 	ContainerID string `cbor:"0,keyasint"`
 }
 
+// ContainerDiffRequest requests filesystem changes of a container. Limit caps the number of
+// returned changes; zero uses the agent default.
+type ContainerDiffRequest struct {
+	ContainerID string `cbor:"0,keyasint"`
+	Limit       int    `cbor:"1,keyasint,omitempty"`
+}
+
+type ContainerStatsRequest struct {
+	ContainerID string `cbor:"0,keyasint"`
+}
+
+// ContainerExecRequest runs Cmd once in a container without a TTY or stdin.
+type ContainerExecRequest struct {
+	ContainerID string   `cbor:"0,keyasint"`
+	Cmd         []string `cbor:"1,keyasint"`
+}
+
 type ContainerOperateRequest struct {
 	ContainerID string `cbor:"0,keyasint"`
 	Operation   string `cbor:"1,keyasint"`
@@ -183,6 +232,15 @@ type DockerImageRemoveRequest struct {
 	Force   bool   `cbor:"1,keyasint,omitempty"`
 }
 
+// DockerRegistryLoginRequest asks the agent to authenticate against a registry without pulling
+// or storing anything.
+type DockerRegistryLoginRequest struct {
+	Registry DockerRegistryAuth `cbor:"0,keyasint"`
+}
+
+// DockerImagePruneRequest requests removal of dangling images (untagged and not used by any container).
+type DockerImagePruneRequest struct{}
+
 type DockerNetworkCreateRequest struct {
 	Name       string            `cbor:"0,keyasint"`
 	Driver     string            `cbor:"1,keyasint,omitempty"`
@@ -234,6 +292,18 @@ type DockerComposeProjectDeleteRequest struct {
 	RemoveFile bool   `cbor:"1,keyasint,omitempty"`
 }
 
+// DockerComposeLogsRequest requests the logs of every container in a compose project written in
+// (Since, Until], both as unix nanoseconds. Since zero returns the last Tail lines of each service;
+// Until zero means now. Tail and MaxBytesPerService bound what each service contributes to one
+// response; zero uses the agent defaults.
+type DockerComposeLogsRequest struct {
+	Name               string `cbor:"0,keyasint"`
+	Since              int64  `cbor:"1,keyasint,omitempty"`
+	Until              int64  `cbor:"2,keyasint,omitempty"`
+	Tail               int    `cbor:"3,keyasint,omitempty"`
+	MaxBytesPerService int    `cbor:"4,keyasint,omitempty"`
+}
+
 type DockerConfigRequest struct{}
 
 type DockerConfigUpdateRequest struct {
@@ -260,97 +330,328 @@ type DockerDataCleanupList struct {
 }
 
 type DockerDataCleanupResult struct {
-	Deleted int64  `cbor:"0,keyasint,omitempty"`
-	Detail  string `cbor:"1,keyasint,omitempty"`
+	Deleted   int64  `cbor:"0,keyasint,omitempty"`
+	Detail    string `cbor:"1,keyasint,omitempty"`
+	Matched   int64  `cbor:"2,keyasint,omitempty"`
+	Truncated bool   `cbor:"3,keyasint,omitempty"` // match count stopped early at the scan time bound
+	Skipped   int64  `cbor:"4,keyasint,omitempty"` // matching keys left in place by a Redis filter
+}
+
+// DataCleanupTLS selects an encrypted connection to a data cleanup source. The zero value keeps
+// the plain connection.
+type DataCleanupTLS struct {
+	UseTLS bool `json:"useTLS,omitempty" cbor:"0,keyasint,omitempty"`
+	// InsecureSkipVerify accepts any server certificate, e.g. a self-signed one
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty" cbor:"1,keyasint,omitempty"`
 }
 
 type DataCleanupMySQLDatabasesRequest struct {
-	Host     string `cbor:"0,keyasint"`
-	Port     int    `cbor:"1,keyasint"`
-	Username string `cbor:"2,keyasint,omitempty"`
-	Password string `cbor:"3,keyasint,omitempty"`
+	Host       string         `cbor:"0,keyasint"`
+	Port       int            `cbor:"1,keyasint"`
+	Username   string         `cbor:"2,keyasint,omitempty"`
+	Password   string         `cbor:"3,keyasint,omitempty"`
+	TimeoutSec int            `cbor:"4,keyasint,omitempty"`
+	TLS        DataCleanupTLS `cbor:"5,keyasint,omitempty"`
 }
 
 type DataCleanupMySQLTablesRequest struct {
-	Host     string `cbor:"0,keyasint"`
-	Port     int    `cbor:"1,keyasint"`
-	Username string `cbor:"2,keyasint,omitempty"`
-	Password string `cbor:"3,keyasint,omitempty"`
-	Database string `cbor:"4,keyasint"`
+	Host       string         `cbor:"0,keyasint"`
+	Port       int            `cbor:"1,keyasint"`
+	Username   string         `cbor:"2,keyasint,omitempty"`
+	Password   string         `cbor:"3,keyasint,omitempty"`
+	Database   string         `cbor:"4,keyasint"`
+	TimeoutSec int            `cbor:"5,keyasint,omitempty"`
+	TLS        DataCleanupTLS `cbor:"6,keyasint,omitempty"`
 }
 
 type DataCleanupMySQLDeleteTablesRequest struct {
-	Host     string   `cbor:"0,keyasint"`
-	Port     int      `cbor:"1,keyasint"`
-	Username string   `cbor:"2,keyasint,omitempty"`
-	Password string   `cbor:"3,keyasint,omitempty"`
-	Database string   `cbor:"4,keyasint"`
-	Tables   []string `cbor:"5,keyasint,omitempty"`
-	JobID    string   `cbor:"6,keyasint,omitempty"`
+	Host       string   `cbor:"0,keyasint"`
+	Port       int      `cbor:"1,keyasint"`
+	Username   string   `cbor:"2,keyasint,omitempty"`
+	Password   string   `cbor:"3,keyasint,omitempty"`
+	Database   string   `cbor:"4,keyasint"`
+	Tables     []string `cbor:"5,keyasint,omitempty"`
+	JobID      string   `cbor:"6,keyasint,omitempty"`
+	TimeoutSec int      `cbor:"7,keyasint,omitempty"`
+	// Confirm must be true for the agent to delete data unless DATA_CLEANUP_ENABLED=true is set on the agent.
+	Confirm bool `cbor:"8,keyasint,omitempty"`
+	// DryRun counts the rows that would be deleted without deleting them; Confirm is not required.
+	// The count is reported in place of the deleted count.
+	DryRun bool `cbor:"9,keyasint,omitempty"`
+	// Mode selects DataCleanupMySQLModeDelete (the default) or DataCleanupMySQLModeTruncate.
+	Mode string `cbor:"10,keyasint,omitempty"`
+	// Conditions restrict the delete of individual tables to matching rows. Tables without a
+	// condition are emptied. Conditions are only allowed in delete mode.
+	Conditions []DataCleanupMySQLCondition `cbor:"11,keyasint,omitempty"`
+	TLS        DataCleanupTLS              `cbor:"12,keyasint,omitempty"`
+}
+
+// DataCleanupMySQLCondition is a WHERE clause applied to one table, e.g. "created_at < ?".
+// Values are never written into Where; each ? placeholder is bound to the matching entry of Args.
+type DataCleanupMySQLCondition struct {
+	Table string   `cbor:"0,keyasint"`
+	Where string   `cbor:"1,keyasint"`
+	Args  []string `cbor:"2,keyasint,omitempty"`
+}
+
+// Validate rejects conditions that could carry more than one expression: statement separators,
+// comments and quoted literals are not allowed, and the placeholders must match the bound args.
+func (c DataCleanupMySQLCondition) Validate() error {
+	if strings.TrimSpace(c.Table) == "" {
+		return fmt.Errorf("mysql condition table is required")
+	}
+	where := strings.TrimSpace(c.Where)
+	if where == "" {
+		return fmt.Errorf("mysql condition for table %q is empty", c.Table)
+	}
+	for _, token := range []string{";", "--", "#", "/*", "*/", "'", "\"", "\\"} {
+		if strings.Contains(where, token) {
+			return fmt.Errorf("mysql condition for table %q must not contain %q", c.Table, token)
+		}
+	}
+	if placeholders := strings.Count(where, "?"); placeholders != len(c.Args) {
+		return fmt.Errorf("mysql condition for table %q has %d placeholders but %d args", c.Table, placeholders, len(c.Args))
+	}
+	return nil
+}
+
+const (
+	// DataCleanupMySQLModeDelete removes rows with DELETE FROM inside one transaction.
+	DataCleanupMySQLModeDelete = "delete"
+	// DataCleanupMySQLModeTruncate empties tables with TRUNCATE TABLE, which also resets
+	// auto-increment counters. It cannot be rolled back and reports no affected row count.
+	DataCleanupMySQLModeTruncate = "truncate"
+)
+
+// NormalizeDataCleanupMySQLMode validates a MySQL cleanup mode, mapping empty to the delete mode.
+func NormalizeDataCleanupMySQLMode(mode string) (string, error) {
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case "", DataCleanupMySQLModeDelete:
+		return DataCleanupMySQLModeDelete, nil
+	case DataCleanupMySQLModeTruncate:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid mysql cleanup mode %q", mode)
+	}
+}
+
+// DataCleanupRedisFilter narrows a Redis cleanup to some of the keys matching its patterns. Zero
+// fields disable their check. Keys without an expiry count as having an infinite TTL: they never
+// pass TTLBelowSec and always pass TTLAboveSec.
+type DataCleanupRedisFilter struct {
+	// Type is a Redis key type: string, list, set, zset, hash or stream
+	Type string `json:"type,omitempty" cbor:"0,keyasint,omitempty"`
+	// TTLBelowSec keeps only keys that expire in less than this many seconds
+	TTLBelowSec int64 `json:"ttlBelowSec,omitempty" cbor:"1,keyasint,omitempty"`
+	// TTLAboveSec keeps only keys that expire in more than this many seconds
+	TTLAboveSec int64 `json:"ttlAboveSec,omitempty" cbor:"2,keyasint,omitempty"`
+}
+
+var dataCleanupRedisKeyTypes = []string{"string", "list", "set", "zset", "hash", "stream"}
+
+// Active reports whether the filter checks anything.
+func (f DataCleanupRedisFilter) Active() bool {
+	return f.Type != "" || f.TTLBelowSec > 0 || f.TTLAboveSec > 0
+}
+
+// Normalize validates the filter and returns it with the type trimmed and lowercased.
+func (f DataCleanupRedisFilter) Normalize() (DataCleanupRedisFilter, error) {
+	f.Type = strings.ToLower(strings.TrimSpace(f.Type))
+	if f.Type != "" && !slices.Contains(dataCleanupRedisKeyTypes, f.Type) {
+		return f, fmt.Errorf("invalid redis key type %q", f.Type)
+	}
+	if f.TTLBelowSec < 0 || f.TTLAboveSec < 0 {
+		return f, fmt.Errorf("redis ttl filter must not be negative")
+	}
+	if f.TTLBelowSec > 0 && f.TTLAboveSec >= f.TTLBelowSec {
+		return f, fmt.Errorf("redis ttl filter matches no key: ttlAboveSec must be less than ttlBelowSec")
+	}
+	return f, nil
+}
+
+// Matches reports whether a key of keyType with the remaining ttl passes the filter. A negative
+// ttl means the key has no expiry.
+func (f DataCleanupRedisFilter) Matches(keyType string, ttl time.Duration) bool {
+	if f.Type != "" && !strings.EqualFold(keyType, f.Type) {
+		return false
+	}
+	persistent := ttl < 0
+	if f.TTLBelowSec > 0 && (persistent || ttl >= time.Duration(f.TTLBelowSec)*time.Second) {
+		return false
+	}
+	if f.TTLAboveSec > 0 && !persistent && ttl <= time.Duration(f.TTLAboveSec)*time.Second {
+		return false
+	}
+	return true
 }
 
 type DataCleanupRedisDatabasesRequest struct {
-	Host     string `cbor:"0,keyasint"`
-	Port     int    `cbor:"1,keyasint"`
-	Username string `cbor:"2,keyasint,omitempty"`
-	Password string `cbor:"3,keyasint,omitempty"`
+	Host       string         `cbor:"0,keyasint"`
+	Port       int            `cbor:"1,keyasint"`
+	Username   string         `cbor:"2,keyasint,omitempty"`
+	Password   string         `cbor:"3,keyasint,omitempty"`
+	TimeoutSec int            `cbor:"4,keyasint,omitempty"`
+	TLS        DataCleanupTLS `cbor:"5,keyasint,omitempty"`
 }
 
 type DataCleanupRedisCleanupRequest struct {
-	Host     string   `cbor:"0,keyasint"`
-	Port     int      `cbor:"1,keyasint"`
-	Username string   `cbor:"2,keyasint,omitempty"`
-	Password string   `cbor:"3,keyasint,omitempty"`
-	DB       int      `cbor:"4,keyasint"`
-	Patterns []string `cbor:"5,keyasint,omitempty"`
-	JobID    string   `cbor:"6,keyasint,omitempty"`
+	Host       string         `cbor:"0,keyasint"`
+	Port       int            `cbor:"1,keyasint"`
+	Username   string         `cbor:"2,keyasint,omitempty"`
+	Password   string         `cbor:"3,keyasint,omitempty"`
+	DB         int            `cbor:"4,keyasint"`
+	Patterns   []string       `cbor:"5,keyasint,omitempty"`
+	JobID      string         `cbor:"6,keyasint,omitempty"`
+	TimeoutSec int            `cbor:"7,keyasint,omitempty"`
+	Confirm    bool           `cbor:"8,keyasint,omitempty"`
+	DryRun     bool           `cbor:"9,keyasint,omitempty"`
+	TLS        DataCleanupTLS `cbor:"10,keyasint,omitempty"`
+	// Filter leaves matching keys of other types or TTLs in place; the zero value deletes all matches
+	Filter DataCleanupRedisFilter `cbor:"11,keyasint,omitempty"`
 }
 
 type DataCleanupMinioBucketsRequest struct {
-	Host      string `cbor:"0,keyasint"`
-	Port      int    `cbor:"1,keyasint"`
-	AccessKey string `cbor:"2,keyasint"`
-	SecretKey string `cbor:"3,keyasint,omitempty"`
+	Host       string         `cbor:"0,keyasint"`
+	Port       int            `cbor:"1,keyasint"`
+	AccessKey  string         `cbor:"2,keyasint"`
+	SecretKey  string         `cbor:"3,keyasint,omitempty"`
+	TimeoutSec int            `cbor:"4,keyasint,omitempty"`
+	TLS        DataCleanupTLS `cbor:"5,keyasint,omitempty"`
 }
 
 type DataCleanupMinioPrefixesRequest struct {
-	Host      string `cbor:"0,keyasint"`
-	Port      int    `cbor:"1,keyasint"`
-	AccessKey string `cbor:"2,keyasint"`
-	SecretKey string `cbor:"3,keyasint,omitempty"`
-	Bucket    string `cbor:"4,keyasint"`
+	Host       string         `cbor:"0,keyasint"`
+	Port       int            `cbor:"1,keyasint"`
+	AccessKey  string         `cbor:"2,keyasint"`
+	SecretKey  string         `cbor:"3,keyasint,omitempty"`
+	Bucket     string         `cbor:"4,keyasint"`
+	TimeoutSec int            `cbor:"5,keyasint,omitempty"`
+	TLS        DataCleanupTLS `cbor:"6,keyasint,omitempty"`
 }
 
 type DataCleanupMinioCleanupRequest struct {
-	Host      string   `cbor:"0,keyasint"`
-	Port      int      `cbor:"1,keyasint"`
-	AccessKey string   `cbor:"2,keyasint"`
-	SecretKey string   `cbor:"3,keyasint,omitempty"`
-	Bucket    string   `cbor:"4,keyasint"`
-	Prefixes  []string `cbor:"5,keyasint,omitempty"`
-	JobID     string   `cbor:"6,keyasint,omitempty"`
-}
+	Host       string         `cbor:"0,keyasint"`
+	Port       int            `cbor:"1,keyasint"`
+	AccessKey  string         `cbor:"2,keyasint"`
+	SecretKey  string         `cbor:"3,keyasint,omitempty"`
+	Bucket     string         `cbor:"4,keyasint"`
+	Prefixes   []string       `cbor:"5,keyasint,omitempty"`
+	JobID      string         `cbor:"6,keyasint,omitempty"`
+	TimeoutSec int            `cbor:"7,keyasint,omitempty"`
+	Confirm    bool           `cbor:"8,keyasint,omitempty"`
+	DryRun     bool           `cbor:"9,keyasint,omitempty"`
+	TLS        DataCleanupTLS `cbor:"10,keyasint,omitempty"`
+	// Concurrency is the number of prefixes cleaned in parallel, up to DataCleanupMinioMaxConcurrency.
+	// Zero uses the agent default.
+	Concurrency int `cbor:"11,keyasint,omitempty"`
+}
+
+// DataCleanupMinioMaxConcurrency caps the number of prefixes a MinIO cleanup job cleans in parallel.
+const DataCleanupMinioMaxConcurrency = 16
 
 type DataCleanupESIndicesRequest struct {
-	Host     string `cbor:"0,keyasint"`
-	Port     int    `cbor:"1,keyasint"`
-	Username string `cbor:"2,keyasint,omitempty"`
-	Password string `cbor:"3,keyasint,omitempty"`
+	Host       string         `cbor:"0,keyasint"`
+	Port       int            `cbor:"1,keyasint"`
+	Username   string         `cbor:"2,keyasint,omitempty"`
+	Password   string         `cbor:"3,keyasint,omitempty"`
+	TimeoutSec int            `cbor:"4,keyasint,omitempty"`
+	TLS        DataCleanupTLS `cbor:"5,keyasint,omitempty"`
 }
 
 type DataCleanupESCleanupRequest struct {
-	Host     string   `cbor:"0,keyasint"`
-	Port     int      `cbor:"1,keyasint"`
-	Username string   `cbor:"2,keyasint,omitempty"`
-	Password string   `cbor:"3,keyasint,omitempty"`
-	Indices  []string `cbor:"4,keyasint,omitempty"`
-	JobID    string   `cbor:"5,keyasint,omitempty"`
+	Host       string         `cbor:"0,keyasint"`
+	Port       int            `cbor:"1,keyasint"`
+	Username   string         `cbor:"2,keyasint,omitempty"`
+	Password   string         `cbor:"3,keyasint,omitempty"`
+	Indices    []string       `cbor:"4,keyasint,omitempty"`
+	JobID      string         `cbor:"5,keyasint,omitempty"`
+	TimeoutSec int            `cbor:"6,keyasint,omitempty"`
+	Confirm    bool           `cbor:"7,keyasint,omitempty"`
+	DryRun     bool           `cbor:"8,keyasint,omitempty"`
+	TLS        DataCleanupTLS `cbor:"9,keyasint,omitempty"`
+	Mode       string         `cbor:"10,keyasint,omitempty"`
+}
+
+const (
+	// DataCleanupESModeDeleteByQuery removes every document with _delete_by_query and keeps the index.
+	DataCleanupESModeDeleteByQuery = "delete_by_query"
+	// DataCleanupESModeDropIndex deletes the whole index, including its mappings and settings.
+	// A missing index is not an error, and no document count is reported.
+	DataCleanupESModeDropIndex = "drop_index"
+)
+
+// NormalizeDataCleanupESMode validates an ES cleanup mode, mapping empty to delete-by-query.
+func NormalizeDataCleanupESMode(mode string) (string, error) {
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case "", DataCleanupESModeDeleteByQuery:
+		return DataCleanupESModeDeleteByQuery, nil
+	case DataCleanupESModeDropIndex:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid es cleanup mode %q", mode)
+	}
+}
+
+// DataCleanupTimeout converts a per-module timeout in seconds into a duration,
+// falling back to the given default when the value is unset.
+func DataCleanupTimeout(seconds int, fallback time.Duration) time.Duration {
+	if seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+type DataCleanupRedisMatchCountRequest struct {
+	Host     string         `cbor:"0,keyasint"`
+	Port     int            `cbor:"1,keyasint"`
+	Username string         `cbor:"2,keyasint,omitempty"`
+	Password string         `cbor:"3,keyasint,omitempty"`
+	DB       int            `cbor:"4,keyasint"`
+	Pattern  string         `cbor:"5,keyasint"`
+	TLS      DataCleanupTLS `cbor:"6,keyasint,omitempty"`
+}
+
+type DataCleanupMinioMatchCountRequest struct {
+	Host      string         `cbor:"0,keyasint"`
+	Port      int            `cbor:"1,keyasint"`
+	AccessKey string         `cbor:"2,keyasint"`
+	SecretKey string         `cbor:"3,keyasint,omitempty"`
+	Bucket    string         `cbor:"4,keyasint"`
+	Prefix    string         `cbor:"5,keyasint"`
+	TLS       DataCleanupTLS `cbor:"6,keyasint,omitempty"`
 }
 
 type DataCleanupJobStatusRequest struct {
 	JobID string `cbor:"0,keyasint"`
 }
 
+type DataCleanupJobListRequest struct{}
+
+// DataCleanupJobCancelRequest stops a running cleanup job. The response carries the job status
+// detail and the amount deleted so far, like DataCleanupJobStatus.
+type DataCleanupJobCancelRequest struct {
+	JobID string `cbor:"0,keyasint"`
+}
+
+// DataCleanupPingRequest asks the agent to connect and authenticate to one data cleanup module
+// without listing or changing anything. For MinIO, Username and Password carry the access key and
+// secret key.
+type DataCleanupPingRequest struct {
+	Module     string         `cbor:"0,keyasint"`
+	Host       string         `cbor:"1,keyasint"`
+	Port       int            `cbor:"2,keyasint"`
+	Username   string         `cbor:"3,keyasint,omitempty"`
+	Password   string         `cbor:"4,keyasint,omitempty"`
+	TimeoutSec int            `cbor:"5,keyasint,omitempty"`
+	TLS        DataCleanupTLS `cbor:"6,keyasint,omitempty"`
+}
+
+// DataCleanupJobListDetail is serialized as JSON into DockerDataCleanupResult.Detail.
+// It lists running jobs and finished jobs that have not expired yet.
+type DataCleanupJobListDetail struct {
+	Jobs []DataCleanupJobStatusDetail `json:"jobs"`
+}
+
 // DataCleanupJobStatusDetail is serialized as JSON into DockerDataCleanupResult.Detail
 // to avoid expanding the AgentResponse schema for incremental status reporting.
 type DataCleanupJobStatusDetail struct {
@@ -362,4 +663,11 @@ type DataCleanupJobStatusDetail struct {
 	Total   int    `json:"total"`
 	Seq     uint64 `json:"seq"`
 	Error   string `json:"error,omitempty"`
+	// Skipped is the number of matching keys a Redis filter left in place
+	Skipped int64 `json:"skipped,omitempty"`
+	// Dropped lists the ES indices deleted in drop_index mode; missing indices are not listed
+	Dropped []string `json:"dropped,omitempty"`
+	// ItemDeleted is the amount deleted by items that are still running, so progress can advance
+	// within a long item such as a large MinIO prefix. Only modules that report batches set it.
+	ItemDeleted int64 `json:"itemDeleted,omitempty"`
 }
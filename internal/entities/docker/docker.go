@@ -2,6 +2,8 @@
 // 该模块只描述数据，不包含具体的 Docker 操作逻辑。
 package docker
 
+import "time"
+
 // Overview 描述 Docker 引擎的概览信息。
 type Overview struct {
 	ServerVersion     string `json:"serverVersion" cbor:"0,keyasint"`
@@ -119,3 +121,62 @@ type DaemonConfig struct {
 	Content string `json:"content" cbor:"1,keyasint"`
 	Exists  bool   `json:"exists" cbor:"2,keyasint"`
 }
+
+// ContainerChange 描述容器文件系统相对镜像的一项变更。
+type ContainerChange struct {
+	Path string `json:"path" cbor:"0,keyasint"`
+	// Kind 为 added、modified 或 deleted。
+	Kind string `json:"kind" cbor:"1,keyasint"`
+}
+
+// ContainerDiff 描述容器文件系统变更列表，变更过多时仅返回前 Limit 项并标记 Truncated。
+type ContainerDiff struct {
+	Changes   []ContainerChange `json:"changes" cbor:"0,keyasint"`
+	Total     int               `json:"total" cbor:"1,keyasint"`
+	Truncated bool              `json:"truncated" cbor:"2,keyasint"`
+}
+
+// ComposeLogLine 为合并日志中的一行，Timestamp 为 Docker 记录的写入时间（unix 纳秒）。
+type ComposeLogLine struct {
+	Service   string `json:"service" cbor:"0,keyasint"`
+	Container string `json:"container" cbor:"1,keyasint"`
+	Timestamp int64  `json:"timestamp" cbor:"2,keyasint"`
+	Line      string `json:"line" cbor:"3,keyasint"`
+}
+
+// ComposeLogs 为 compose 项目各服务按时间合并的日志。Cursor 为本次读取的截止时间，作为下一次请求的 Since；
+// Truncated 列出本次超出单服务上限、丢弃了部分日志的服务。
+type ComposeLogs struct {
+	Lines     []ComposeLogLine `json:"lines" cbor:"0,keyasint"`
+	Cursor    int64            `json:"cursor" cbor:"1,keyasint"`
+	Truncated []string         `json:"truncated,omitempty" cbor:"2,keyasint,omitempty"`
+}
+
+// ContainerStats 为单个容器的一次实时资源读数。Cpu 为占宿主机 CPU 的百分比，与定期采集的容器统计口径一致；
+// 网络收发为容器启动以来的累计字节数。
+type ContainerStats struct {
+	ID       string    `json:"id" cbor:"0,keyasint"`
+	Name     string    `json:"name" cbor:"1,keyasint"`
+	Cpu      float64   `json:"cpu" cbor:"2,keyasint"`
+	MemUsed  uint64    `json:"memUsed" cbor:"3,keyasint"`
+	MemLimit uint64    `json:"memLimit" cbor:"4,keyasint"`
+	NetSent  uint64    `json:"netSent" cbor:"5,keyasint"`
+	NetRecv  uint64    `json:"netRecv" cbor:"6,keyasint"`
+	Read     time.Time `json:"read" cbor:"7,keyasint"`
+}
+
+// ExecResult 为容器内单次命令的执行结果。Output 合并 stdout 与 stderr，超过上限时截断并标记 Truncated；
+// 命令超时（TimedOut）或输出被截断时命令可能仍在运行，此时 ExitCode 为 -1。
+type ExecResult struct {
+	Output    string `json:"output" cbor:"0,keyasint"`
+	ExitCode  int    `json:"exitCode" cbor:"1,keyasint"`
+	Truncated bool   `json:"truncated" cbor:"2,keyasint"`
+	TimedOut  bool   `json:"timedOut" cbor:"3,keyasint"`
+}
+
+// ImagePruneReport 描述清理悬空镜像的结果。
+type ImagePruneReport struct {
+	// ImagesDeleted 为被删除的镜像 ID。
+	ImagesDeleted  []string `json:"imagesDeleted" cbor:"0,keyasint"`
+	SpaceReclaimed uint64   `json:"spaceReclaimed" cbor:"1,keyasint"`
+}
@@ -81,7 +81,7 @@ func (acr *agentConnectRequest) agentConnect() (err error) {
 	}
 
 	// Upgrade connection to WebSocket
-	conn, err := ws.GetUpgrader().Upgrade(acr.res, acr.req)
+	conn, err := ws.Upgrade(acr.res, acr.req)
 	if err != nil {
 		return acr.sendResponseError(acr.res, http.StatusInternalServerError, "WebSocket upgrade failed")
 	}
@@ -0,0 +1,79 @@
+// Package hub 统一 API 错误响应结构。
+// 错误响应同时包含人类可读的 error 与稳定的 code，前端应依据 code 分支处理，error 仅用于展示。
+package hub
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// Error codes returned in the `code` field of hub API error responses.
+// The set is stable; new codes may be added but existing ones are not renamed.
+//
+//	VALIDATION_FAILED    400  malformed body or invalid parameters
+//	SSRF_BLOCKED         400  the target address is rejected by the API test SSRF filter
+//	FORBIDDEN            403  missing role or no access to the target system
+//	SYSTEM_NOT_FOUND     400/404  the system does not exist or is not connected
+//	NOT_FOUND            404  any other missing record
+//	RUN_IN_PROGRESS      409  a run for the same target is already executing
+//	CONFLICT             409  any other state conflict
+//	PRECONDITION_FAILED  412  server-side prerequisites are not met (e.g. signing keys)
+//	INTERNAL_ERROR       500  unexpected hub-side failure
+//	UPSTREAM_FAILED      502  the agent or an upstream service returned an error
+//	SERVICE_UNAVAILABLE  503  the feature is not initialized or configured
+const (
+	errCodeValidationFailed   = "VALIDATION_FAILED"
+	errCodeSSRFBlocked        = "SSRF_BLOCKED"
+	errCodeForbidden          = "FORBIDDEN"
+	errCodeSystemNotFound     = "SYSTEM_NOT_FOUND"
+	errCodeNotFound           = "NOT_FOUND"
+	errCodeRunInProgress      = "RUN_IN_PROGRESS"
+	errCodeConflict           = "CONFLICT"
+	errCodePreconditionFailed = "PRECONDITION_FAILED"
+	errCodeInternal           = "INTERNAL_ERROR"
+	errCodeUpstreamFailed     = "UPSTREAM_FAILED"
+	errCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+)
+
+type apiErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// errorCodeForStatus maps an HTTP status to its default error code.
+func errorCodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return errCodeValidationFailed
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return errCodeForbidden
+	case http.StatusNotFound:
+		return errCodeNotFound
+	case http.StatusConflict:
+		return errCodeConflict
+	case http.StatusPreconditionFailed:
+		return errCodePreconditionFailed
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return errCodeUpstreamFailed
+	case http.StatusServiceUnavailable:
+		return errCodeServiceUnavailable
+	default:
+		return errCodeInternal
+	}
+}
+
+// respondError writes an error response whose code is derived from the status.
+// Messages produced by errSystemNotFound are reported as SYSTEM_NOT_FOUND.
+func respondError(e *core.RequestEvent, status int, message string) error {
+	code := errorCodeForStatus(status)
+	if message == errSystemNotFound.Error() {
+		code = errCodeSystemNotFound
+	}
+	return respondErrorWithCode(e, status, code, message)
+}
+
+// respondErrorWithCode writes an error response with an explicit code.
+func respondErrorWithCode(e *core.RequestEvent, status int, code string, message string) error {
+	return e.JSON(status, apiErrorResponse{Error: message, Code: code})
+}
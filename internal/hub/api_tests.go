@@ -4,25 +4,37 @@ package hub
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
 	"net/url"
+	"regexp"
 	"runtime/debug"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
-	"sync/atomic"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"aether/internal/alerts"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/cron"
 	"github.com/pocketbase/pocketbase/tools/types"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -42,6 +54,18 @@ const (
 	apiTestMaxTimeoutMs                      = 120000
 	apiTestMaxScheduleMinutes                = 1440
 	apiTestMaxAlertThreshold                 = 100
+	apiTestMaxAssertionBodyBytes       int64 = 1 << 20
+	// apiTestMaxSnippetBytesHardCap 为合集 snippet_bytes 与用例 response_snippet_bytes 覆盖值的全局硬上限
+	apiTestMaxSnippetBytesHardCap int64 = 64 << 10
+	apiTestMaxCanaryIterations          = 50
+)
+
+// 写入记录的错误与响应摘要字段长度上限（字符），与迁移 202610170400_api_tests_text_limits 中字段的 Max 一致。
+const (
+	apiTestMaxStoredErrorChars   = 16 << 10
+	apiTestMaxStoredSnippetChars = 2 * int(apiTestMaxSnippetBytesHardCap)
+	// apiTestMaxMatchExcerptChars 为断言失败信息中引用响应内容的长度上限
+	apiTestMaxMatchExcerptChars = 200
 )
 
 type apiTestRunSource string
@@ -55,34 +79,224 @@ type apiTestKeyValue struct {
 	Key     string `json:"key"`
 	Value   string `json:"value"`
 	Enabled bool   `json:"enabled"`
+	// Secret 标记敏感值，在请求预览等展示场景中脱敏
+	Secret bool `json:"secret,omitempty"`
 }
 
 type apiTestRunCaseRequest struct {
 	CaseId string `json:"caseId"`
 }
 
+// apiTestPreviewRequest 为请求预览参数：提供 case 时按未保存的用例草稿预览，否则预览 caseId 对应的已保存用例。
+type apiTestPreviewRequest struct {
+	CaseId string            `json:"caseId"`
+	Case   *apiTestDraftCase `json:"case"`
+}
+
+// apiTestDraftCase 为预览用的用例草稿，只包含影响请求组装的字段；Collection 为所属合集 ID。
+type apiTestDraftCase struct {
+	Collection     string            `json:"collection"`
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Headers        []apiTestKeyValue `json:"headers"`
+	Params         []apiTestKeyValue `json:"params"`
+	BodyType       string            `json:"body_type"`
+	Body           string            `json:"body"`
+	ResolveIP      string            `json:"resolve_ip"`
+	ForwardedFor   string            `json:"forwarded_for"`
+	ForwardedProto string            `json:"forwarded_proto"`
+	RealIP         string            `json:"real_ip"`
+	// AutoContentLength 为 true 时按请求体长度设置 Content-Length。
+	AutoContentLength bool `json:"auto_content_length"`
+}
+
+type apiTestPreviewResponse struct {
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	Headers     map[string]string `json:"headers"`
+	Params      map[string]string `json:"params"`
+	Body        string            `json:"body"`
+	BodyBytes   int               `json:"bodyBytes"`
+	TargetError string            `json:"targetError,omitempty"`
+}
+
+type apiTestCanaryRequest struct {
+	CaseId     string `json:"caseId"`
+	Iterations int    `json:"iterations"`
+}
+
+type apiTestCanaryResponse struct {
+	CaseId        string   `json:"caseId"`
+	Name          string   `json:"name"`
+	Iterations    int      `json:"iterations"`
+	Success       int      `json:"success"`
+	Failed        int      `json:"failed"`
+	SuccessRate   float64  `json:"successRate"`
+	MinDurationMs int      `json:"minDurationMs"`
+	MaxDurationMs int      `json:"maxDurationMs"`
+	AvgDurationMs float64  `json:"avgDurationMs"`
+	DurationsMs   []int    `json:"durationsMs"`
+	Errors        []string `json:"errors"`
+	Cancelled     bool     `json:"cancelled,omitempty"`
+}
+
+type apiTestEffectiveAssertions struct {
+	ExpectedStatus     int                      `json:"expectedStatus"`
+	MonotonicPath      string                   `json:"monotonicPath,omitempty"`
+	MinResponseBytes   int                      `json:"minResponseBytes,omitempty"`
+	MaxResponseBytes   int                      `json:"maxResponseBytes,omitempty"`
+	NotContains        string                   `json:"notContains,omitempty"`
+	NotContainsRegex   bool                     `json:"notContainsRegex,omitempty"`
+	BodyContains       string                   `json:"expectedBodyContains,omitempty"`
+	JSONPath           string                   `json:"expectedJsonPath,omitempty"`
+	JSONValue          string                   `json:"expectedJsonValue,omitempty"`
+	TLSMinVersion      string                   `json:"tlsMinVersion,omitempty"`
+	TLSCiphers         []string                 `json:"tlsCiphers,omitempty"`
+	ExpectedBody       string                   `json:"expectedBody,omitempty"`
+	ExpectedBodyMode   string                   `json:"expectedBodyMode,omitempty"`
+	ExpectedBodyIgnore []string                 `json:"expectedBodyIgnore,omitempty"`
+	StatusBranches     []apiTestStatusBranch    `json:"statusBranches,omitempty"`
+	ExpectedCookies    []apiTestCookieAssertion `json:"expectedCookies,omitempty"`
+	FingerprintMode    string                   `json:"fingerprintMode,omitempty"`
+	FingerprintPaths   []string                 `json:"fingerprintPaths,omitempty"`
+	ExpectedLocation   string                   `json:"expectedLocation,omitempty"`
+	LocationRegex      bool                     `json:"expectedLocationRegex,omitempty"`
+	MaxLatencyMs       int                      `json:"maxLatencyMs,omitempty"`
+	// Templates 为引用的断言模板；上方的数值限制已按合并规则取值
+	Templates []apiTestAssertionTemplate `json:"templates,omitempty"`
+}
+
+// apiTestStatusBranch 为按状态码选择的断言分支。Status 支持精确状态码（200）、
+// 通配（2xx）与闭区间（200-299）；分支内断言与用例级同名断言语义一致。
+type apiTestStatusBranch struct {
+	Status             string   `json:"status"`
+	NotContains        string   `json:"not_contains,omitempty"`
+	NotContainsRegex   bool     `json:"not_contains_regex,omitempty"`
+	ExpectedBody       string   `json:"expected_body,omitempty"`
+	ExpectedBodyMode   string   `json:"expected_body_mode,omitempty"`
+	ExpectedBodyIgnore []string `json:"expected_body_ignore,omitempty"`
+	MinResponseBytes   int      `json:"min_response_bytes,omitempty"`
+	MaxResponseBytes   int      `json:"max_response_bytes,omitempty"`
+}
+
+type apiTestEffectiveSchedule struct {
+	GlobalEnabled   bool   `json:"globalEnabled"`
+	Enabled         bool   `json:"enabled"`
+	Cron            string `json:"cron,omitempty"`
+	IntervalMinutes int    `json:"intervalMinutes,omitempty"`
+	// Source 表示生效的定时配置来源：case / collection / global
+	Source string `json:"source"`
+}
+
+type apiTestEffectiveAlert struct {
+	Enabled   bool   `json:"enabled"`
+	OnRecover bool   `json:"onRecover"`
+	Mode      string `json:"mode"`
+	Threshold int    `json:"threshold"`
+	// SuccessRate 与 WindowMinutes 仅在 success_rate 模式下返回
+	SuccessRate   float64 `json:"successRate,omitempty"`
+	WindowMinutes int     `json:"windowMinutes,omitempty"`
+}
+
+type apiTestEffectiveConfigResponse struct {
+	CaseId       string `json:"caseId"`
+	CollectionId string `json:"collectionId"`
+	Name         string `json:"name"`
+	ProbeType    string `json:"probeType"`
+	HTTPProtocol string `json:"httpProtocol,omitempty"`
+	// FollowRedirects 为实际是否跟随重定向，配置了 Location 断言时不跟随
+	FollowRedirects bool                       `json:"followRedirects"`
+	Request         *apiTestPreviewResponse    `json:"request,omitempty"`
+	RequestError    string                     `json:"requestError,omitempty"`
+	ResolveIP       string                     `json:"resolveIp,omitempty"`
+	ProxyURL        string                     `json:"proxyUrl,omitempty"`
+	TimeoutMs       int                        `json:"timeoutMs"`
+	SnippetBytes    int64                      `json:"snippetBytes"`
+	RetryCount      int                        `json:"retryCount,omitempty"`
+	RetryDelayMs    int                        `json:"retryDelayMs,omitempty"`
+	TotalTimeoutMs  int                        `json:"totalTimeoutMs,omitempty"`
+	Assertions      apiTestEffectiveAssertions `json:"assertions"`
+	Schedule        apiTestEffectiveSchedule   `json:"schedule"`
+	Alert           apiTestEffectiveAlert      `json:"alert"`
+}
+
 type apiTestRunCollectionRequest struct {
 	CollectionId string `json:"collectionId"`
 }
 
+type apiTestBulkTagRequest struct {
+	Action       string   `json:"action"`
+	Tag          string   `json:"tag"`
+	CaseIds      []string `json:"caseIds"`
+	CollectionId string   `json:"collectionId"`
+}
+
+type apiTestBulkTagResponse struct {
+	Matched int `json:"matched"`
+	Changed int `json:"changed"`
+}
+
+type apiTestBulkMoveRequest struct {
+	CaseIds      []string `json:"caseIds"`
+	CollectionId string   `json:"collectionId"`
+}
+
+// apiTestMoveConflict 描述移动后与目标合集内同名的用例（ConflictingCaseId 为目标合集中或同批移动的另一用例）。
+type apiTestMoveConflict struct {
+	CaseId            string `json:"caseId"`
+	Name              string `json:"name"`
+	ConflictingCaseId string `json:"conflictingCaseId"`
+}
+
+type apiTestBulkMoveResponse struct {
+	Moved     int                   `json:"moved"`
+	Unchanged int                   `json:"unchanged"`
+	Conflicts []apiTestMoveConflict `json:"conflicts,omitempty"`
+}
+
+var errApiTestMoveConflict = errors.New("目标合集存在同名用例")
+
+// apiTestReorderRequest 为排序请求：CollectionId 为空时 Ids 为全部合集，否则为该合集内的全部用例。
+type apiTestReorderRequest struct {
+	CollectionId string   `json:"collectionId"`
+	Ids          []string `json:"ids"`
+}
+
+type apiTestReorderResponse struct {
+	Updated int `json:"updated"`
+}
+
+var errApiTestReorderScope = errors.New("排序列表与范围内的记录不一致")
+
 type apiTestScheduleUpdateRequest struct {
-	Enabled              *bool `json:"enabled"`
-	IntervalMinutes      *int  `json:"intervalMinutes"`
-	AlertEnabled         *bool `json:"alertEnabled"`
-	AlertOnRecover       *bool `json:"alertOnRecover"`
-	HistoryRetentionDays *int  `json:"historyRetentionDays"`
+	Enabled              *bool   `json:"enabled"`
+	IntervalMinutes      *int    `json:"intervalMinutes"`
+	AlertEnabled         *bool   `json:"alertEnabled"`
+	AlertOnRecover       *bool   `json:"alertOnRecover"`
+	HistoryRetentionDays *int    `json:"historyRetentionDays"`
+	StatsRefreshMinutes  *int    `json:"statsRefreshMinutes"`
+	StatsWindowHours     []int   `json:"statsWindowHours"`
+	TLSExpiryAlertDays   *int    `json:"tlsExpiryAlertDays"`
+	AuditRetentionDays   *int    `json:"auditRetentionDays"`
+	WebhookURL           *string `json:"webhookUrl"`
 }
 
 type apiTestScheduleResponse struct {
-	Id                   string `json:"id"`
-	Enabled              bool   `json:"enabled"`
-	IntervalMinutes      int    `json:"intervalMinutes"`
-	LastRunAt            string `json:"lastRunAt"`
-	NextRunAt            string `json:"nextRunAt"`
-	LastError            string `json:"lastError"`
-	AlertEnabled         bool   `json:"alertEnabled"`
-	AlertOnRecover       bool   `json:"alertOnRecover"`
-	HistoryRetentionDays int    `json:"historyRetentionDays"`
+	Id                   string                     `json:"id"`
+	Enabled              bool                       `json:"enabled"`
+	IntervalMinutes      int                        `json:"intervalMinutes"`
+	LastRunAt            string                     `json:"lastRunAt"`
+	NextRunAt            string                     `json:"nextRunAt"`
+	LastError            string                     `json:"lastError"`
+	AlertEnabled         bool                       `json:"alertEnabled"`
+	AlertOnRecover       bool                       `json:"alertOnRecover"`
+	HistoryRetentionDays int                        `json:"historyRetentionDays"`
+	StatsRefreshMinutes  int                        `json:"statsRefreshMinutes"`
+	StatsWindowHours     []int                      `json:"statsWindowHours"`
+	TLSExpiryAlertDays   int                        `json:"tlsExpiryAlertDays"`
+	AuditRetentionDays   int                        `json:"auditRetentionDays"`
+	WebhookURL           string                     `json:"webhookUrl"`
+	LastRunSummary       *apiTestScheduleRunSummary `json:"lastRunSummary,omitempty"`
 }
 
 type apiTestRunResult struct {
@@ -95,6 +309,8 @@ type apiTestRunResult struct {
 	Error           string `json:"error"`
 	ResponseSnippet string `json:"responseSnippet"`
 	RunAt           string `json:"runAt"`
+	// Aborted 表示用例因执行被中止而未完成，结果未写入执行记录
+	Aborted bool `json:"aborted,omitempty"`
 }
 
 type apiTestCollectionRunSummary struct {
@@ -103,7 +319,10 @@ type apiTestCollectionRunSummary struct {
 	Cases        int                `json:"cases"`
 	Success      int                `json:"success"`
 	Failed       int                `json:"failed"`
+	Skipped      int                `json:"skipped"`
+	Aborted      int                `json:"aborted"`
 	Results      []apiTestRunResult `json:"results"`
+	Cancelled    bool               `json:"cancelled,omitempty"`
 }
 
 type apiTestRunAllSummary struct {
@@ -111,34 +330,87 @@ type apiTestRunAllSummary struct {
 	Cases       int                `json:"cases"`
 	Success     int                `json:"success"`
 	Failed      int                `json:"failed"`
+	Skipped     int                `json:"skipped"`
+	Aborted     int                `json:"aborted"`
 	Results     []apiTestRunResult `json:"results"`
+	Cancelled   bool               `json:"cancelled,omitempty"`
 }
 
 type apiTestExportCollection struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	BaseURL     string   `json:"base_url"`
-	SortOrder   int      `json:"sort_order"`
-	Tags        []string `json:"tags"`
+	Name         string              `json:"name"`
+	Description  string              `json:"description"`
+	BaseURL      string              `json:"base_url"`
+	SortOrder    int                 `json:"sort_order"`
+	Tags         []string            `json:"tags"`
+	ScheduleCron string              `json:"schedule_cron,omitempty"`
+	SnippetBytes int                 `json:"snippet_bytes,omitempty"`
+	HealthWindow int                 `json:"health_window_minutes,omitempty"`
+	HealthAlert  float64             `json:"health_alert_threshold,omitempty"`
+	PacingMs     int                 `json:"pacing_ms,omitempty"`
+	ProxyURL     string              `json:"proxy_url,omitempty"`
+	Variables    []apiTestKeyValue   `json:"variables,omitempty"`
+	OAuth        *apiTestExportOAuth `json:"oauth,omitempty"`
 }
 
 type apiTestExportCase struct {
-	Collection       string           `json:"collection"`
-	Name             string           `json:"name"`
-	Method           string           `json:"method"`
-	URL              string           `json:"url"`
-	Description      string           `json:"description"`
-	Headers          []apiTestKeyValue `json:"headers"`
-	Params           []apiTestKeyValue `json:"params"`
-	BodyType         string           `json:"body_type"`
-	Body             string           `json:"body"`
-	ExpectedStatus   int              `json:"expected_status"`
-	TimeoutMs        int              `json:"timeout_ms"`
-	ScheduleEnabled  bool             `json:"schedule_enabled"`
-	ScheduleMinutes  int              `json:"schedule_minutes"`
-	SortOrder        int              `json:"sort_order"`
-	Tags             []string         `json:"tags"`
-	AlertThreshold   int              `json:"alert_threshold"`
+	Collection         string                   `json:"collection"`
+	Name               string                   `json:"name"`
+	Method             string                   `json:"method"`
+	URL                string                   `json:"url"`
+	Description        string                   `json:"description"`
+	Headers            []apiTestKeyValue        `json:"headers"`
+	Params             []apiTestKeyValue        `json:"params"`
+	BodyType           string                   `json:"body_type"`
+	Body               string                   `json:"body"`
+	ExpectedStatus     int                      `json:"expected_status"`
+	TimeoutMs          int                      `json:"timeout_ms"`
+	ScheduleEnabled    bool                     `json:"schedule_enabled"`
+	Enabled            *bool                    `json:"enabled,omitempty"`
+	ScheduleMinutes    int                      `json:"schedule_minutes"`
+	SortOrder          int                      `json:"sort_order"`
+	Tags               []string                 `json:"tags"`
+	AlertThreshold     int                      `json:"alert_threshold"`
+	AlertMode          string                   `json:"alert_mode,omitempty"`
+	AlertSuccessRate   float64                  `json:"alert_success_rate,omitempty"`
+	AlertWindowMinutes int                      `json:"alert_window_minutes,omitempty"`
+	ScheduleCron       string                   `json:"schedule_cron,omitempty"`
+	MonotonicPath      string                   `json:"monotonic_path,omitempty"`
+	MinResponseBytes   int                      `json:"min_response_bytes,omitempty"`
+	MaxResponseBytes   int                      `json:"max_response_bytes,omitempty"`
+	NotContains        string                   `json:"not_contains,omitempty"`
+	NotContainsRegex   bool                     `json:"not_contains_regex,omitempty"`
+	BodyContains       string                   `json:"expected_body_contains,omitempty"`
+	JSONPath           string                   `json:"expected_json_path,omitempty"`
+	JSONValue          string                   `json:"expected_json_value,omitempty"`
+	System             string                   `json:"system,omitempty"`
+	ResolveIP          string                   `json:"resolve_ip,omitempty"`
+	TLSMinVersion      string                   `json:"tls_min_version,omitempty"`
+	TLSCiphers         []string                 `json:"tls_ciphers,omitempty"`
+	ExpectedBody       string                   `json:"expected_body,omitempty"`
+	ExpectedBodyMode   string                   `json:"expected_body_mode,omitempty"`
+	ExpectedBodyIgnore []string                 `json:"expected_body_ignore,omitempty"`
+	StatusBranches     []apiTestStatusBranch    `json:"status_branches,omitempty"`
+	ExpectedCookies    []apiTestCookieAssertion `json:"expected_cookies,omitempty"`
+	FingerprintMode    string                   `json:"fingerprint_mode,omitempty"`
+	FingerprintPaths   []string                 `json:"fingerprint_paths,omitempty"`
+	ExpectedLocation   string                   `json:"expected_location,omitempty"`
+	LocationRegex      bool                     `json:"expected_location_regex,omitempty"`
+	FollowRedirects    *bool                    `json:"follow_redirects,omitempty"`
+	ProbeType          string                   `json:"probe_type,omitempty"`
+	HTTPProtocol       string                   `json:"http_protocol,omitempty"`
+	SnippetBytes       int                      `json:"response_snippet_bytes,omitempty"`
+	RetryCount         int                      `json:"retry_count,omitempty"`
+	RetryDelayMs       int                      `json:"retry_delay_ms,omitempty"`
+	TotalTimeoutMs     int                      `json:"total_timeout_ms,omitempty"`
+	HealthWeight       int                      `json:"health_weight,omitempty"`
+	MaxLatencyMs       int                      `json:"max_latency_ms,omitempty"`
+	ForwardedFor       string                   `json:"forwarded_for,omitempty"`
+	ForwardedProto     string                   `json:"forwarded_proto,omitempty"`
+	RealIP             string                   `json:"real_ip,omitempty"`
+	AutoContentLength  bool                     `json:"auto_content_length,omitempty"`
+	Auth               *apiTestExportAuth       `json:"auth,omitempty"`
+	// AssertionTemplates 为引用的断言模板名称，导入时要求模板已存在
+	AssertionTemplates []string `json:"assertion_templates,omitempty"`
 }
 
 type apiTestExportPayload struct {
@@ -149,6 +421,8 @@ type apiTestExportPayload struct {
 type apiTestImportRequest struct {
 	Mode string               `json:"mode"`
 	Data apiTestExportPayload `json:"data"`
+	// OpenAPI 为 OpenAPI 3 文档，提供时按文档生成合集与用例并忽略 Data
+	OpenAPI json.RawMessage `json:"openapi,omitempty"`
 }
 
 type apiTestImportSummary struct {
@@ -171,16 +445,23 @@ type apiTestRunsResponse struct {
 }
 
 type apiTestRunItem struct {
-	Id              string `json:"id"`
-	CaseId          string `json:"caseId"`
-	CollectionId    string `json:"collectionId"`
-	Status          int    `json:"status"`
-	DurationMs      int    `json:"durationMs"`
-	Success         bool   `json:"success"`
-	Error           string `json:"error"`
-	ResponseSnippet string `json:"responseSnippet"`
-	Source          string `json:"source"`
-	Created         string `json:"created"`
+	Id              string                 `json:"id"`
+	CaseId          string                 `json:"caseId"`
+	CollectionId    string                 `json:"collectionId"`
+	Status          int                    `json:"status"`
+	DurationMs      int                    `json:"durationMs"`
+	Success         bool                   `json:"success"`
+	Error           string                 `json:"error"`
+	ResponseSnippet string                 `json:"responseSnippet"`
+	Source          string                 `json:"source"`
+	Created         string                 `json:"created"`
+	ExtractedValue  *apiTestExtractedValue `json:"extractedValue,omitempty"`
+	ResponseBytes   int64                  `json:"responseBytes"`
+	Fingerprint     string                 `json:"fingerprint,omitempty"`
+	Protocol        string                 `json:"protocol,omitempty"`
+	ResponseHeaders map[string]string      `json:"responseHeaders,omitempty"`
+	TLSExpiresAt    string                 `json:"tlsExpiresAt,omitempty"`
+	FinalURL        string                 `json:"finalUrl,omitempty"`
 }
 
 type apiTestExecutionResult struct {
@@ -190,6 +471,25 @@ type apiTestExecutionResult struct {
 	Error           string
 	ResponseSnippet string
 	RunAt           types.DateTime
+	ExtractedValue  *apiTestExtractedValue
+	// ResponseBytes 为响应体大小，-1 表示未知（未读完且无 Content-Length）
+	ResponseBytes int64
+	// Fingerprint 为响应结构指纹，未启用指纹或响应未通过其他断言时为空
+	Fingerprint string
+	// Protocol 为实际协商的协议（如 HTTP/2.0），请求未得到响应时为空
+	Protocol string
+	// ResponseHeaders 为保存到执行记录的响应头子集，见 apiTestCaptureResponseHeaders
+	ResponseHeaders map[string]string
+	// TLSExpiresAt 为 HTTPS 响应叶子证书的到期时间，非 TLS 请求或未得到响应时为零值
+	TLSExpiresAt types.DateTime
+	// FinalURL 为跟随重定向后最终到达的地址（隐藏密码），未发生重定向时为空
+	FinalURL string
+}
+
+// apiTestExtractedValue 为单调断言从响应中提取的数值，按执行记录保存，供下次执行比较。
+type apiTestExtractedValue struct {
+	Path  string  `json:"path"`
+	Value float64 `json:"value"`
 }
 
 type apiTestAlertAction struct {
@@ -201,16 +501,62 @@ type apiTestAlertAction struct {
 	DurationMinutes     int
 	StatusCode          int
 	ErrorMessage        string
+	// SuccessRate/RateThreshold 仅在成功率窗口模式与合集健康度告警下设置，此时 DurationMinutes 为统计窗口；
+	// 健康度告警时 CaseName 为合集名称，SuccessRate/RateThreshold 为健康度及其阈值
+	Mode          string
+	SuccessRate   float64
+	RateThreshold float64
+	// DaysLeft/ExpiresAt 仅在证书到期告警下设置，此时 Threshold 为提前告警天数
+	DaysLeft  int
+	ExpiresAt string
+}
+
+// apiTestRunLockAll 为全部执行与定时巡检使用的锁键，与任意合集的锁互斥。
+const apiTestRunLockAll = "all"
+
+// apiTestRunLocks 为按合集 id 加锁的执行锁：不同合集可同时执行，同一合集的执行互斥；
+// apiTestRunLockAll 覆盖所有合集，持有期间任何合集都不能执行，反之亦然。
+// held 的值为持有该锁的执行任务，由 apiTestBindRunLock 登记，供中止接口按锁键取消。
+var apiTestRunLocks = struct {
+	sync.Mutex
+	held map[string]*runningJob
+}{held: make(map[string]*runningJob)}
+
+// apiTestAcquireRunLock 获取 key 对应的执行锁。获取失败时返回占用中的锁键。
+func apiTestAcquireRunLock(key string) (string, bool) {
+	apiTestRunLocks.Lock()
+	defer apiTestRunLocks.Unlock()
+	if _, ok := apiTestRunLocks.held[apiTestRunLockAll]; ok {
+		return apiTestRunLockAll, false
+	}
+	if key == apiTestRunLockAll && len(apiTestRunLocks.held) > 0 {
+		held := slices.Sorted(maps.Keys(apiTestRunLocks.held))
+		return held[0], false
+	}
+	if _, ok := apiTestRunLocks.held[key]; ok {
+		return key, false
+	}
+	apiTestRunLocks.held[key] = nil
+	return "", true
 }
 
-var apiTestRunning int32
+func apiTestReleaseRunLock(key string) {
+	apiTestRunLocks.Lock()
+	delete(apiTestRunLocks.held, key)
+	apiTestRunLocks.Unlock()
+}
 
-func apiTestAcquireRunLock() bool {
-	return atomic.CompareAndSwapInt32(&apiTestRunning, 0, 1)
+// apiTestRunBusyError 描述占用中的执行锁。
+func apiTestRunBusyError(busy string) error {
+	if busy == apiTestRunLockAll {
+		return errors.New("全部执行或定时巡检正在执行")
+	}
+	return fmt.Errorf("合集 %s 正在执行", busy)
 }
 
-func apiTestReleaseRunLock() {
-	atomic.StoreInt32(&apiTestRunning, 0)
+// respondApiTestRunBusy 返回 409，说明占用中的资源。
+func respondApiTestRunBusy(e *core.RequestEvent, busy string) error {
+	return respondErrorWithCode(e, http.StatusConflict, errCodeRunInProgress, formatApiTestError("接口测试执行中", apiTestRunBusyError(busy), map[string]any{"busy": busy}).Error())
 }
 
 func (h *Hub) logApiTestError(message string, err error, fields ...any) {
@@ -219,11 +565,11 @@ func (h *Hub) logApiTestError(message string, err error, fields ...any) {
 	}
 	payload := []any{
 		"logger", "hub",
-		"err", err,
+		"err", h.redactor.redact(err.Error()),
 		"errType", fmt.Sprintf("%T", err),
 		"stack", string(debug.Stack()),
 	}
-	payload = append(payload, fields...)
+	payload = append(payload, h.redactor.redactFields(fields)...)
 	h.Logger().Error(message, payload...)
 }
 
@@ -258,6 +604,55 @@ func apiTestParseBody(e *core.RequestEvent, payload any) error {
 	return nil
 }
 
+// apiTestWantsYAML 判断导入导出是否使用 YAML：?format=yaml 或 YAML 的 Content-Type，默认 JSON。
+func apiTestWantsYAML(e *core.RequestEvent) bool {
+	format := strings.ToLower(strings.TrimSpace(e.Request.URL.Query().Get("format")))
+	if format != "" {
+		return format == "yaml" || format == "yml"
+	}
+	return strings.Contains(strings.ToLower(e.Request.Header.Get("Content-Type")), "yaml")
+}
+
+// apiTestParseYAMLBody 将 YAML 请求体转换为 JSON 后按 apiTestParseBody 的规则解码，
+// 与 JSON 导入共用结构体定义与未知字段校验。
+func apiTestParseYAMLBody(e *core.RequestEvent, payload any) error {
+	var data any
+	if err := yaml.NewDecoder(e.Request.Body).Decode(&data); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(payload)
+}
+
+// apiTestMarshalYAML 按 JSON 标签序列化为 YAML。先编码为 JSON 再解析为 yaml.Node，
+// 以保留字段顺序并与 JSON 导出逐字段一致。
+func apiTestMarshalYAML(value any) ([]byte, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return nil, err
+	}
+	apiTestResetYAMLStyle(&node)
+	return yaml.Marshal(&node)
+}
+
+// apiTestResetYAMLStyle 清除从 JSON 继承的流式与引号样式，输出块状 YAML；
+// 字符串节点保留 !!str 标签，编码时会对易被误解析的值自动加引号。
+func apiTestResetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		apiTestResetYAMLStyle(child)
+	}
+}
+
 var apiTestAllowedMethods = map[string]struct{}{
 	"GET":    {},
 	"POST":   {},
@@ -271,6 +666,8 @@ var apiTestAllowedBodyTypes = map[string]struct{}{
 	"json": {},
 	"text": {},
 	"form": {},
+	// graphql 请求体封装为 {"query": ..., "variables": ...} 后以 JSON 发送
+	apiTestBodyTypeGraphQL: {},
 }
 
 func apiTestNormalizeKeyValues(items []apiTestKeyValue) []apiTestKeyValue {
@@ -280,6 +677,13 @@ func apiTestNormalizeKeyValues(items []apiTestKeyValue) []apiTestKeyValue {
 	return items
 }
 
+func apiTestNormalizeStatusBranches(items []apiTestStatusBranch) []apiTestStatusBranch {
+	if items == nil {
+		return []apiTestStatusBranch{}
+	}
+	return items
+}
+
 func apiTestNormalizeStringList(items []string) []string {
 	if items == nil {
 		return []string{}
@@ -297,202 +701,941 @@ func apiTestIsValidBodyType(value string) bool {
 	return ok
 }
 
-func apiTestIndexCollectionsByName(records []*core.Record) (map[string]*core.Record, error) {
-	result := make(map[string]*core.Record)
-	for _, record := range records {
-		name := record.GetString("name")
-		if strings.TrimSpace(name) == "" {
-			return nil, fmt.Errorf("collection name empty (id=%s)", record.Id)
-		}
-		if existing, ok := result[name]; ok {
-			return nil, fmt.Errorf("duplicate collection name: %s (id=%s, id=%s)", name, existing.Id, record.Id)
-		}
-		result[name] = record
-	}
-	return result, nil
+// apiTestScheduleLocation 返回 cron 表达式的求值时区。
+// 统一使用 Hub 进程的本地时区（time.Local，可通过 TZ 环境变量调整），
+// 以便 "*/5 9-17 * * 1-5" 这类表达式按运维人员所在时区理解。
+func apiTestScheduleLocation() *time.Location {
+	return time.Local
 }
 
-func apiTestIndexCasesByCollection(records []*core.Record) (map[string]map[string]*core.Record, error) {
-	result := make(map[string]map[string]*core.Record)
-	for _, record := range records {
-		collectionId := record.GetString("collection")
-		name := record.GetString("name")
-		if strings.TrimSpace(collectionId) == "" || strings.TrimSpace(name) == "" {
-			return nil, fmt.Errorf("case missing collection or name (id=%s)", record.Id)
-		}
-		group, ok := result[collectionId]
-		if !ok {
-			group = make(map[string]*core.Record)
-			result[collectionId] = group
-		}
-		if existing, ok := group[name]; ok {
-			return nil, fmt.Errorf("duplicate case name: %s (collection=%s, id=%s, id=%s)", name, collectionId, existing.Id, record.Id)
-		}
-		group[name] = record
+// apiTestParseScheduleCron 解析用例或合集的 cron 表达式，空字符串表示未配置（返回 nil）。
+func apiTestParseScheduleCron(expr string) (*cron.Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
 	}
-	return result, nil
+	return cron.NewSchedule(expr)
 }
 
-func apiTestValueListToMap(items []apiTestKeyValue) map[string]string {
-	result := make(map[string]string)
-	for _, item := range items {
-		if !item.Enabled {
-			continue
+// apiTestCronDueBetween 判断 (since, now] 区间内是否存在命中 cron 的分钟。
+// 定时巡检按全局间隔触发，逐分钟回溯可避免错过两次巡检之间命中的时间点；
+// 回溯范围最多 apiTestMaxScheduleMinutes 分钟。
+func apiTestCronDueBetween(schedule *cron.Schedule, since time.Time, now time.Time) bool {
+	location := apiTestScheduleLocation()
+	end := now.In(location).Truncate(time.Minute)
+	start := end.Add(-time.Duration(apiTestMaxScheduleMinutes-1) * time.Minute)
+	if !since.IsZero() {
+		next := since.In(location).Truncate(time.Minute).Add(time.Minute)
+		if next.After(start) {
+			start = next
 		}
-		key := strings.TrimSpace(item.Key)
-		if key == "" {
-			continue
+	}
+	for moment := start; !moment.After(end); moment = moment.Add(time.Minute) {
+		if schedule.IsDue(cron.NewMoment(moment)) {
+			return true
 		}
-		result[key] = item.Value
 	}
-	return result
+	return false
 }
 
-func (h *Hub) buildApiTestHeaders(record *core.Record) (map[string]string, error) {
-	var items []apiTestKeyValue
-	if err := record.UnmarshalJSONField("headers", &items); err != nil {
-		return nil, err
+// validateApiTestRecord 在用例/合集保存前校验 schedule_cron、monotonic_path 等扩展字段，避免无效配置入库。
+func (h *Hub) validateApiTestRecord(e *core.RecordEvent) error {
+	if _, err := apiTestParseScheduleCron(e.Record.GetString("schedule_cron")); err != nil {
+		return validation.Errors{
+			"schedule_cron": validation.NewError("validation_invalid_cron", fmt.Sprintf("cron 表达式无效: %v", err)),
+		}
 	}
-	headers := apiTestValueListToMap(items)
-	bodyType := strings.ToLower(record.GetString("body_type"))
-	if bodyType == "json" {
-		if _, ok := headers["Content-Type"]; !ok {
-			headers["Content-Type"] = "application/json"
+	if err := apiTestValidateJSONPath(e.Record.GetString("monotonic_path")); err != nil {
+		return validation.Errors{
+			"monotonic_path": validation.NewError("validation_invalid_path", err.Error()),
 		}
 	}
-	return headers, nil
-}
-
-func (h *Hub) buildApiTestParams(record *core.Record) (map[string]string, error) {
-	var items []apiTestKeyValue
-	if err := record.UnmarshalJSONField("params", &items); err != nil {
-		return nil, err
+	if field, err := apiTestValidateForwardedHeaders(e.Record.GetString("forwarded_for"), e.Record.GetString("forwarded_proto"), e.Record.GetString("real_ip")); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_forwarded_header", err.Error()),
+		}
 	}
-	return apiTestValueListToMap(items), nil
-}
-
-func (h *Hub) buildApiTestBody(record *core.Record) (io.Reader, string, error) {
-	method := strings.ToUpper(strings.TrimSpace(record.GetString("method")))
-	if method == http.MethodGet || method == http.MethodHead {
-		return nil, "", nil
+	if field, err := apiTestValidateAuth(e.Record.GetString("auth_type"), e.Record.GetString("auth_username"), e.Record.GetString("auth_token") != ""); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_auth", err.Error()),
+		}
 	}
-	body := record.GetString("body")
-	if strings.TrimSpace(body) == "" {
-		return nil, "", nil
+	if field, err := apiTestValidateResponseSize(e.Record.GetInt("min_response_bytes"), e.Record.GetInt("max_response_bytes")); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_response_size", err.Error()),
+		}
 	}
-	bodyType := strings.ToLower(record.GetString("body_type"))
-	switch bodyType {
-	case "json":
-		if !json.Valid([]byte(body)) {
-			return nil, "", errors.New("请求体不是有效的 JSON")
+	if field, err := apiTestValidateAlertRule(e.Record.GetString("alert_mode"), e.Record.GetFloat("alert_success_rate"), e.Record.GetInt("alert_window_minutes")); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_alert_rule", err.Error()),
 		}
-		return bytes.NewBufferString(body), "application/json", nil
-	case "text":
-		return bytes.NewBufferString(body), "text/plain", nil
-	case "form":
-		values := url.Values{}
-		var raw any
-		if err := json.Unmarshal([]byte(body), &raw); err != nil {
-			return nil, "", err
+	}
+	if err := apiTestValidateResolveIP(e.Record.GetString("resolve_ip")); err != nil {
+		return validation.Errors{
+			"resolve_ip": validation.NewError("validation_invalid_resolve_ip", err.Error()),
 		}
-		switch typed := raw.(type) {
-		case []any:
-			for _, item := range typed {
-				itemMap, ok := item.(map[string]any)
-				if !ok {
-					continue
-				}
-				enabled, _ := itemMap["enabled"].(bool)
-				if itemMap["enabled"] != nil && !enabled {
-					continue
-				}
-				key := strings.TrimSpace(fmt.Sprintf("%v", itemMap["key"]))
-				if key == "" {
-					continue
-				}
-				value := ""
-				if rawValue, exists := itemMap["value"]; exists && rawValue != nil {
-					value = fmt.Sprintf("%v", rawValue)
-				}
-				values.Add(key, value)
-			}
-		case map[string]any:
-			for key, value := range typed {
-				trimmedKey := strings.TrimSpace(key)
-				if trimmedKey == "" {
-					continue
-				}
-				values.Add(trimmedKey, fmt.Sprintf("%v", value))
-			}
-		default:
-			return nil, "", errors.New("表单请求体格式不正确")
+	}
+	if err := apiTestValidateNotContains(e.Record.GetString("not_contains"), e.Record.GetBool("not_contains_regex")); err != nil {
+		return validation.Errors{
+			"not_contains": validation.NewError("validation_invalid_not_contains", err.Error()),
 		}
-		return bytes.NewBufferString(values.Encode()), "application/x-www-form-urlencoded", nil
-	default:
-		return nil, "", fmt.Errorf("未知的请求体类型: %s", bodyType)
 	}
-}
-
-func (h *Hub) resolveApiTestURL(collectionRecord *core.Record, caseRecord *core.Record) (string, error) {
-	rawURL := strings.TrimSpace(caseRecord.GetString("url"))
-	if rawURL == "" {
-		return "", errors.New("请求地址不能为空")
+	if field, err := apiTestValidateBodyAssertion(e.Record.GetString("expected_json_path"), e.Record.GetString("expected_json_value")); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_body_assertion", err.Error()),
+		}
 	}
-	if strings.HasPrefix(strings.ToLower(rawURL), "http://") || strings.HasPrefix(strings.ToLower(rawURL), "https://") {
-		return rawURL, nil
+	if field, err := apiTestValidateProbe(e.Record.GetString("probe_type"), e.Record.GetString("url"), e.Record.GetInt("max_latency_ms")); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_probe", err.Error()),
+		}
 	}
-	base := strings.TrimSpace(collectionRecord.GetString("base_url"))
-	if base == "" {
-		return "", errors.New("合集未设置基础地址，无法拼接相对路径")
+	if err := apiTestValidateExpectedLocation(e.Record.GetString("expected_location"), e.Record.GetBool("expected_location_regex")); err != nil {
+		return validation.Errors{
+			"expected_location": validation.NewError("validation_invalid_expected_location", err.Error()),
+		}
 	}
-	baseURL, err := url.Parse(base)
-	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
-		return "", errors.New("合集基础地址不合法")
+	if err := apiTestValidateGraphQLBody(e.Record.GetString("body_type"), e.Record.GetString("body")); err != nil {
+		return validation.Errors{
+			"body": validation.NewError("validation_invalid_graphql_body", err.Error()),
+		}
 	}
-	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(rawURL, "/"), nil
-}
-
-func apiTestParseAllowedHosts(raw string) map[string]struct{} {
-	result := make(map[string]struct{})
-	for _, item := range strings.Split(raw, ",") {
-		host := strings.ToLower(strings.TrimSpace(item))
-		if host == "" {
-			continue
+	if err := apiTestValidateHTTPProtocol(e.Record.GetString("http_protocol")); err != nil {
+		return validation.Errors{
+			"http_protocol": validation.NewError("validation_invalid_http_protocol", err.Error()),
 		}
-		result[host] = struct{}{}
 	}
-	return result
-}
-
-func apiTestParseAllowedCIDRs(raw string) ([]*net.IPNet, []string) {
-	var (
-		result []*net.IPNet
-		errors []string
-	)
-	for _, item := range strings.Split(raw, ",") {
-		cidr := strings.TrimSpace(item)
-		if cidr == "" {
-			continue
+	if err := apiTestValidateTLSMinVersion(e.Record.GetString("tls_min_version")); err != nil {
+		return validation.Errors{
+			"tls_min_version": validation.NewError("validation_invalid_tls_version", err.Error()),
 		}
-		_, network, err := net.ParseCIDR(cidr)
-		if err != nil {
-			errors = append(errors, cidr)
-			continue
+	}
+	tlsCiphers, err := apiTestRecordStringList(e.Record, "tls_ciphers")
+	if err == nil {
+		err = apiTestValidateTLSCiphers(tlsCiphers)
+	}
+	if err != nil {
+		return validation.Errors{
+			"tls_ciphers": validation.NewError("validation_invalid_tls_ciphers", err.Error()),
 		}
-		result = append(result, network)
 	}
-	return result, errors
-}
-
-func (h *Hub) validateApiTestTarget(rawURL string) error {
-	parsed, err := url.Parse(rawURL)
+	expectedBodyIgnore, err := apiTestRecordStringList(e.Record, "expected_body_ignore")
 	if err != nil {
-		return fmt.Errorf("解析 URL 失败: %w", err)
+		return validation.Errors{
+			"expected_body_ignore": validation.NewError("validation_invalid_expected_body", err.Error()),
+		}
 	}
-	if parsed.Scheme != "http" && parsed.Scheme != "https" {
-		return errors.New("仅允许 http/https 协议")
+	if field, err := apiTestValidateExpectedBody(e.Record.GetString("expected_body"), e.Record.GetString("expected_body_mode"), expectedBodyIgnore); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_expected_body", err.Error()),
+		}
+	}
+	statusBranches, err := apiTestRecordStatusBranches(e.Record)
+	if err == nil {
+		err = apiTestValidateStatusBranches(statusBranches)
+	}
+	if err != nil {
+		return validation.Errors{
+			"status_branches": validation.NewError("validation_invalid_status_branches", err.Error()),
+		}
+	}
+	expectedCookies, err := apiTestRecordCookieAssertions(e.Record)
+	if err == nil {
+		err = apiTestValidateCookieAssertions(expectedCookies)
+	}
+	if err != nil {
+		return validation.Errors{
+			"expected_cookies": validation.NewError("validation_invalid_expected_cookies", err.Error()),
+		}
+	}
+	fingerprintPaths, err := apiTestRecordStringList(e.Record, "fingerprint_paths")
+	if err != nil {
+		return validation.Errors{
+			"fingerprint_paths": validation.NewError("validation_invalid_fingerprint", err.Error()),
+		}
+	}
+	if field, err := apiTestValidateFingerprint(e.Record.GetString("fingerprint_mode"), fingerprintPaths); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_fingerprint", err.Error()),
+		}
+	}
+	if err := apiTestValidateSnippetBytes(e.Record.GetInt("snippet_bytes")); err != nil {
+		return validation.Errors{
+			"snippet_bytes": validation.NewError("validation_invalid_snippet_bytes", err.Error()),
+		}
+	}
+	if err := apiTestValidatePacing(e.Record.GetInt("pacing_ms")); err != nil {
+		return validation.Errors{
+			"pacing_ms": validation.NewError("validation_invalid_pacing", err.Error()),
+		}
+	}
+	if err := apiTestValidateStoredProxyURL(e.Record.GetString("proxy_url")); err != nil {
+		return validation.Errors{
+			"proxy_url": validation.NewError("validation_invalid_proxy_url", err.Error()),
+		}
+	}
+	variables, err := apiTestCollectionVariableItems(e.Record)
+	if err == nil {
+		err = apiTestValidateVariables(variables)
+	}
+	if err != nil {
+		return validation.Errors{
+			"variables": validation.NewError("validation_invalid_variables", err.Error()),
+		}
+	}
+	if err := apiTestValidateSnippetBytes(e.Record.GetInt("response_snippet_bytes")); err != nil {
+		return validation.Errors{
+			"response_snippet_bytes": validation.NewError("validation_invalid_snippet_bytes", err.Error()),
+		}
+	}
+	if err := apiTestValidateHealthWeight(e.Record.GetInt("health_weight")); err != nil {
+		return validation.Errors{
+			"health_weight": validation.NewError("validation_invalid_health", err.Error()),
+		}
+	}
+	if field, err := apiTestValidateHealthRule(e.Record.GetInt("health_window_minutes"), e.Record.GetFloat("health_alert_threshold")); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_health", err.Error()),
+		}
+	}
+	if field, err := apiTestValidateRetry(e.Record.GetInt("retry_count"), e.Record.GetInt("retry_delay_ms")); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_retry", err.Error()),
+		}
+	}
+	if err := apiTestValidateTotalTimeout(e.Record.GetInt("timeout_ms"), e.Record.GetInt("total_timeout_ms")); err != nil {
+		return validation.Errors{
+			"total_timeout_ms": validation.NewError("validation_invalid_total_timeout", err.Error()),
+		}
+	}
+	if field, err := apiTestValidateOAuthConfig(e.Record.GetString("oauth_token_url"), e.Record.GetString("oauth_client_id"), e.Record.GetString("oauth_grant_type")); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_oauth", err.Error()),
+		}
+	}
+	return e.Next()
+}
+
+// defaultApiTestCaseEnabled 在创建请求未提供 enabled 时启用用例，保持旧客户端创建的用例可被批量执行；
+// 未提供 follow_redirects 时同样默认跟随重定向，与引入该字段前的行为一致。
+func (h *Hub) defaultApiTestCaseEnabled(e *core.RecordRequestEvent) error {
+	info, err := e.RequestInfo()
+	if err != nil {
+		return err
+	}
+	if _, ok := info.Body["enabled"]; !ok {
+		e.Record.Set("enabled", true)
+	}
+	if _, ok := info.Body["follow_redirects"]; !ok {
+		e.Record.Set("follow_redirects", true)
+	}
+	return e.Next()
+}
+
+// validateApiTestCaseSystem 校验用例关联的系统存在且当前用户可访问，避免关联到无权查看的主机。
+func (h *Hub) validateApiTestCaseSystem(e *core.RecordRequestEvent) error {
+	systemID := strings.TrimSpace(e.Record.GetString("system"))
+	if systemID == "" {
+		return e.Next()
+	}
+	if _, err := h.resolveSystemRecordForUser(e.RequestEvent, systemID); err != nil {
+		if errors.Is(err, errSystemForbidden) {
+			return e.ForbiddenError("无权关联该系统", nil)
+		}
+		return e.BadRequestError("关联的系统不存在", nil)
+	}
+	return e.Next()
+}
+
+// apiTestValidateSnippetBytes 校验合集或用例的响应摘要长度覆盖值，0 表示使用默认值。
+// apiTestTruncateText 将文本截断到 maxChars 个字符以内，截断时以 "…" 结尾。
+func apiTestTruncateText(text string, maxChars int) string {
+	if utf8.RuneCountInString(text) <= maxChars {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:maxChars-1]) + "…"
+}
+
+func apiTestValidateSnippetBytes(value int) error {
+	if value < 0 {
+		return errors.New("响应摘要长度不能为负数")
+	}
+	if int64(value) > apiTestMaxSnippetBytesHardCap {
+		return fmt.Errorf("响应摘要长度不能超过 %d 字节", apiTestMaxSnippetBytesHardCap)
+	}
+	return nil
+}
+
+// apiTestValidateResponseSize 校验响应大小断言的上下限（字节），0 表示不限制，返回出错的字段名。
+func apiTestValidateResponseSize(minBytes int, maxBytes int) (string, error) {
+	if minBytes < 0 {
+		return "min_response_bytes", errors.New("响应大小下限不能为负数")
+	}
+	if maxBytes < 0 {
+		return "max_response_bytes", errors.New("响应大小上限不能为负数")
+	}
+	if maxBytes > 0 && minBytes > maxBytes {
+		return "max_response_bytes", errors.New("响应大小上限不能小于下限")
+	}
+	return "", nil
+}
+
+// apiTestValidateNotContains 校验反向包含断言，regex 为 true 时要求是合法的正则表达式。
+func apiTestValidateNotContains(pattern string, regex bool) error {
+	if pattern == "" || !regex {
+		return nil
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return fmt.Errorf("正则表达式无效: %v", err)
+	}
+	return nil
+}
+
+// apiTestCheckNotContains 执行反向包含断言：响应体出现禁止内容时返回错误。
+func apiTestCheckNotContains(body []byte, pattern string, regex bool) error {
+	if regex {
+		matcher, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("反向包含断言失败: 正则表达式无效: %v", err)
+		}
+		if match := matcher.Find(body); match != nil {
+			return fmt.Errorf("反向包含断言失败: 响应包含禁止内容 %q", apiTestTruncateText(string(match), apiTestMaxMatchExcerptChars))
+		}
+		return nil
+	}
+	if bytes.Contains(body, []byte(pattern)) {
+		return fmt.Errorf("反向包含断言失败: 响应包含禁止内容 %q", apiTestTruncateText(pattern, apiTestMaxMatchExcerptChars))
+	}
+	return nil
+}
+
+// apiTestValidateExpectedLocation 校验 Location 头断言：正则模式下需为合法正则，空字符串表示不断言。
+func apiTestValidateExpectedLocation(pattern string, regex bool) error {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || !regex {
+		return nil
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return fmt.Errorf("正则表达式无效: %v", err)
+	}
+	return nil
+}
+
+// apiTestCheckLocation 执行 Location 头断言。非正则模式按完整地址比较，相对地址按请求地址解析后再比较；
+// 正则模式匹配原始头值或解析后的地址任一即可。失败时报告实际的 Location。
+func apiTestCheckLocation(requestURL *url.URL, location string, pattern string, regex bool) error {
+	pattern = strings.TrimSpace(pattern)
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return fmt.Errorf("Location 断言失败: 响应未返回 Location，期望 %s", pattern)
+	}
+	candidates := []string{location}
+	if parsed, err := url.Parse(location); err == nil && requestURL != nil {
+		if resolved := requestURL.ResolveReference(parsed).String(); resolved != location {
+			candidates = append(candidates, resolved)
+		}
+	}
+	var matcher *regexp.Regexp
+	if regex {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("Location 断言失败: 正则表达式无效: %v", err)
+		}
+		matcher = compiled
+	}
+	for _, candidate := range candidates {
+		if (matcher != nil && matcher.MatchString(candidate)) || (matcher == nil && candidate == pattern) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Location 断言失败: 期望 %s，实际 %s", pattern, location)
+}
+
+// apiTestValidateExpectedBody 校验期望响应体断言：json 模式（默认）要求期望内容为合法 JSON，
+// 忽略路径仅用于 json 模式且需为合法的点分隔路径。返回出错的字段名。
+func apiTestValidateExpectedBody(body string, mode string, ignorePaths []string) (string, error) {
+	if strings.TrimSpace(body) == "" {
+		return "", nil
+	}
+	mode = strings.TrimSpace(mode)
+	switch mode {
+	case "exact":
+		if len(ignorePaths) > 0 {
+			return "expected_body_ignore", errors.New("精确比较模式不支持忽略路径")
+		}
+		return "", nil
+	case "", "json":
+	default:
+		return "expected_body_mode", fmt.Errorf("不支持的比较模式: %s", mode)
+	}
+	var parsed any
+	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
+		return "expected_body", fmt.Errorf("期望响应体不是合法 JSON: %v", err)
+	}
+	for _, path := range ignorePaths {
+		if strings.TrimSpace(path) == "" {
+			return "expected_body_ignore", errors.New("忽略路径不能为空")
+		}
+		if err := apiTestValidateJSONPath(path); err != nil {
+			return "expected_body_ignore", err
+		}
+	}
+	return "", nil
+}
+
+// apiTestCheckExpectedBody 执行期望响应体断言。exact 模式比较去除首尾空白后的原文；
+// json 模式解析两侧 JSON 后深度比较（忽略键顺序与 ignorePaths），失败时报告第一个不同的路径。
+func apiTestCheckExpectedBody(payload []byte, expected string, mode string, ignorePaths []string) error {
+	if strings.TrimSpace(mode) == "exact" {
+		if !bytes.Equal(bytes.TrimSpace(payload), []byte(strings.TrimSpace(expected))) {
+			return errors.New("响应体断言失败: 响应与期望内容不一致")
+		}
+		return nil
+	}
+	expectedValue, err := apiTestDecodeJSON([]byte(expected))
+	if err != nil {
+		return fmt.Errorf("响应体断言失败: 期望内容解析失败: %v", err)
+	}
+	actualValue, err := apiTestDecodeJSON(payload)
+	if err != nil {
+		return fmt.Errorf("响应体断言失败: 响应不是合法 JSON: %v", err)
+	}
+	for _, path := range ignorePaths {
+		segments := strings.Split(strings.TrimSpace(path), ".")
+		expectedValue = apiTestRemoveJSONPath(expectedValue, segments)
+		actualValue = apiTestRemoveJSONPath(actualValue, segments)
+	}
+	if path, ok := apiTestJSONDiff(expectedValue, actualValue, ""); !ok {
+		if path == "" {
+			path = "$"
+		}
+		return fmt.Errorf("响应体断言失败: 路径 %s 与期望不一致", path)
+	}
+	return nil
+}
+
+func apiTestDecodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// apiTestRemoveJSONPath 移除指定路径上的值；数组元素置为 nil 以保持下标不变。路径不存在时原样返回。
+func apiTestRemoveJSONPath(data any, segments []string) any {
+	if len(segments) == 0 {
+		return nil
+	}
+	switch typed := data.(type) {
+	case map[string]any:
+		child, ok := typed[segments[0]]
+		if !ok {
+			return data
+		}
+		if len(segments) == 1 {
+			delete(typed, segments[0])
+		} else {
+			typed[segments[0]] = apiTestRemoveJSONPath(child, segments[1:])
+		}
+	case []any:
+		index, err := strconv.Atoi(segments[0])
+		if err != nil || index < 0 || index >= len(typed) {
+			return data
+		}
+		typed[index] = apiTestRemoveJSONPath(typed[index], segments[1:])
+	}
+	return data
+}
+
+// apiTestJSONDiff 深度比较两个 JSON 值，不一致时返回第一个不同的路径（按键名排序遍历，结果稳定）。
+func apiTestJSONDiff(expected any, actual any, path string) (string, bool) {
+	join := func(segment string) string {
+		if path == "" {
+			return segment
+		}
+		return path + "." + segment
+	}
+	switch typed := expected.(type) {
+	case map[string]any:
+		other, ok := actual.(map[string]any)
+		if !ok {
+			return path, false
+		}
+		keys := make([]string, 0, len(typed)+len(other))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		for key := range other {
+			if _, ok := typed[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			expectedChild, inExpected := typed[key]
+			actualChild, inActual := other[key]
+			if inExpected != inActual {
+				return join(key), false
+			}
+			if diffPath, ok := apiTestJSONDiff(expectedChild, actualChild, join(key)); !ok {
+				return diffPath, false
+			}
+		}
+		return "", true
+	case []any:
+		other, ok := actual.([]any)
+		if !ok || len(other) != len(typed) {
+			return path, false
+		}
+		for index := range typed {
+			if diffPath, ok := apiTestJSONDiff(typed[index], other[index], join(strconv.Itoa(index))); !ok {
+				return diffPath, false
+			}
+		}
+		return "", true
+	case json.Number:
+		other, ok := actual.(json.Number)
+		if !ok {
+			return path, false
+		}
+		if typed.String() == other.String() {
+			return "", true
+		}
+		left, leftErr := typed.Float64()
+		right, rightErr := other.Float64()
+		if leftErr != nil || rightErr != nil || left != right {
+			return path, false
+		}
+		return "", true
+	default:
+		if expected != actual {
+			return path, false
+		}
+		return "", true
+	}
+}
+
+// apiTestParseStatusSpec 解析分支状态码：精确值（200）、通配（2xx）或闭区间（200-299），返回上下界。
+func apiTestParseStatusSpec(spec string) (int, int, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "" {
+		return 0, 0, errors.New("状态码不能为空")
+	}
+	var low, high int
+	switch {
+	case len(spec) == 3 && strings.HasSuffix(spec, "xx"):
+		digit, err := strconv.Atoi(spec[:1])
+		if err != nil {
+			return 0, 0, fmt.Errorf("状态码格式无效: %s", spec)
+		}
+		low, high = digit*100, digit*100+99
+	case strings.Contains(spec, "-"):
+		left, right, _ := strings.Cut(spec, "-")
+		var errLow, errHigh error
+		low, errLow = strconv.Atoi(strings.TrimSpace(left))
+		high, errHigh = strconv.Atoi(strings.TrimSpace(right))
+		if errLow != nil || errHigh != nil {
+			return 0, 0, fmt.Errorf("状态码区间格式无效: %s", spec)
+		}
+		if low > high {
+			return 0, 0, fmt.Errorf("状态码区间下限大于上限: %s", spec)
+		}
+	default:
+		code, err := strconv.Atoi(spec)
+		if err != nil {
+			return 0, 0, fmt.Errorf("状态码格式无效: %s", spec)
+		}
+		low, high = code, code
+	}
+	if low < 100 || high > 599 {
+		return 0, 0, fmt.Errorf("状态码超出 100-599 范围: %s", spec)
+	}
+	return low, high, nil
+}
+
+// apiTestValidateStatusBranches 校验状态码分支：状态码格式合法且不重复，分支内断言按用例级规则校验。
+func apiTestValidateStatusBranches(branches []apiTestStatusBranch) error {
+	seen := make(map[string]struct{}, len(branches))
+	for index, branch := range branches {
+		low, high, err := apiTestParseStatusSpec(branch.Status)
+		if err != nil {
+			return fmt.Errorf("分支[%d]: %v", index, err)
+		}
+		key := fmt.Sprintf("%d-%d", low, high)
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("分支[%d]: 状态码 %s 重复", index, branch.Status)
+		}
+		seen[key] = struct{}{}
+		if err := apiTestValidateNotContains(branch.NotContains, branch.NotContainsRegex); err != nil {
+			return fmt.Errorf("分支[%d].not_contains: %v", index, err)
+		}
+		if field, err := apiTestValidateExpectedBody(branch.ExpectedBody, branch.ExpectedBodyMode, branch.ExpectedBodyIgnore); err != nil {
+			return fmt.Errorf("分支[%d].%s: %v", index, field, err)
+		}
+		if field, err := apiTestValidateResponseSize(branch.MinResponseBytes, branch.MaxResponseBytes); err != nil {
+			return fmt.Errorf("分支[%d].%s: %v", index, field, err)
+		}
+	}
+	return nil
+}
+
+// apiTestRecordStatusBranches 读取用例的 status_branches，未配置时返回空列表。
+func apiTestRecordStatusBranches(record *core.Record) ([]apiTestStatusBranch, error) {
+	raw := strings.TrimSpace(record.GetString("status_branches"))
+	if raw == "" || raw == "null" {
+		return nil, nil
+	}
+	var branches []apiTestStatusBranch
+	if err := json.Unmarshal([]byte(raw), &branches); err != nil {
+		return nil, fmt.Errorf("status_branches 格式无效: %w", err)
+	}
+	return branches, nil
+}
+
+// apiTestMatchStatusBranch 选择与状态码匹配的分支。优先级：精确状态码优先；
+// 其次按配置顺序取第一个包含该状态码的通配或区间分支（区间重叠时先配置者生效）。未匹配返回 nil。
+func apiTestMatchStatusBranch(branches []apiTestStatusBranch, status int) *apiTestStatusBranch {
+	var rangeMatch *apiTestStatusBranch
+	for index := range branches {
+		low, high, err := apiTestParseStatusSpec(branches[index].Status)
+		if err != nil || status < low || status > high {
+			continue
+		}
+		if low == high {
+			return &branches[index]
+		}
+		if rangeMatch == nil {
+			rangeMatch = &branches[index]
+		}
+	}
+	return rangeMatch
+}
+
+func (branch *apiTestStatusBranch) needsBody() bool {
+	return branch.NotContains != "" || strings.TrimSpace(branch.ExpectedBody) != ""
+}
+
+func (branch *apiTestStatusBranch) needsSize() bool {
+	return branch.MinResponseBytes > 0 || branch.MaxResponseBytes > 0
+}
+
+// check 执行分支内的断言，顺序与用例级断言一致。
+func (branch *apiTestStatusBranch) check(payload []byte, responseBytes int64) error {
+	if branch.NotContains != "" {
+		if err := apiTestCheckNotContains(payload, branch.NotContains, branch.NotContainsRegex); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(branch.ExpectedBody) != "" {
+		if err := apiTestCheckExpectedBody(payload, branch.ExpectedBody, branch.ExpectedBodyMode, branch.ExpectedBodyIgnore); err != nil {
+			return err
+		}
+	}
+	if branch.needsSize() {
+		return apiTestCheckResponseSize(responseBytes, int64(branch.MinResponseBytes), int64(branch.MaxResponseBytes))
+	}
+	return nil
+}
+
+// apiTestSnippetLimit 返回用例生效的响应摘要长度：用例的 response_snippet_bytes 优先，其次为合集的 snippet_bytes，
+// 均未配置时使用 apiTestMaxResponseSnippetBytes；配置值始终被限制在 apiTestMaxSnippetBytesHardCap 以内。
+func apiTestSnippetLimit(caseRecord *core.Record, collectionRecord *core.Record) int64 {
+	var value int64
+	if caseRecord != nil {
+		value = int64(caseRecord.GetInt("response_snippet_bytes"))
+	}
+	if value <= 0 && collectionRecord != nil {
+		value = int64(collectionRecord.GetInt("snippet_bytes"))
+	}
+	if value <= 0 {
+		return apiTestMaxResponseSnippetBytes
+	}
+	return min(value, apiTestMaxSnippetBytesHardCap)
+}
+
+// apiTestValidateForwardedHeaders 校验代理转发头字段，返回出错的字段名。
+//   - forwarded_for：逗号分隔的 IP 列表（客户端在前，代理链在后）；
+//   - forwarded_proto：http 或 https；
+//   - real_ip：单个 IP。
+//
+// 均为可选，空值表示不注入对应请求头。
+func apiTestValidateForwardedHeaders(forwardedFor string, forwardedProto string, realIP string) (string, error) {
+	if value := strings.TrimSpace(forwardedFor); value != "" {
+		for _, item := range strings.Split(value, ",") {
+			if net.ParseIP(strings.TrimSpace(item)) == nil {
+				return "forwarded_for", fmt.Errorf("不是有效的 IP: %s", strings.TrimSpace(item))
+			}
+		}
+	}
+	if value := strings.TrimSpace(forwardedProto); value != "" && value != "http" && value != "https" {
+		return "forwarded_proto", fmt.Errorf("仅支持 http 或 https: %s", value)
+	}
+	if value := strings.TrimSpace(realIP); value != "" && net.ParseIP(value) == nil {
+		return "real_ip", fmt.Errorf("不是有效的 IP: %s", value)
+	}
+	return "", nil
+}
+
+// apiTestForwardedHeaders 根据用例的转发头字段生成需要注入的标准代理请求头。
+func apiTestForwardedHeaders(record *core.Record) map[string]string {
+	headers := make(map[string]string)
+	if value := strings.TrimSpace(record.GetString("forwarded_for")); value != "" {
+		parts := strings.Split(value, ",")
+		for index, item := range parts {
+			parts[index] = strings.TrimSpace(item)
+		}
+		headers["X-Forwarded-For"] = strings.Join(parts, ", ")
+	}
+	if value := strings.TrimSpace(record.GetString("forwarded_proto")); value != "" {
+		headers["X-Forwarded-Proto"] = value
+	}
+	if value := strings.TrimSpace(record.GetString("real_ip")); value != "" {
+		headers["X-Real-IP"] = value
+	}
+	return headers
+}
+
+func apiTestIndexCollectionsByName(records []*core.Record) (map[string]*core.Record, error) {
+	result := make(map[string]*core.Record)
+	for _, record := range records {
+		name := record.GetString("name")
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("collection name empty (id=%s)", record.Id)
+		}
+		if existing, ok := result[name]; ok {
+			return nil, fmt.Errorf("duplicate collection name: %s (id=%s, id=%s)", name, existing.Id, record.Id)
+		}
+		result[name] = record
+	}
+	return result, nil
+}
+
+func apiTestIndexCasesByCollection(records []*core.Record) (map[string]map[string]*core.Record, error) {
+	result := make(map[string]map[string]*core.Record)
+	for _, record := range records {
+		collectionId := record.GetString("collection")
+		name := record.GetString("name")
+		if strings.TrimSpace(collectionId) == "" || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("case missing collection or name (id=%s)", record.Id)
+		}
+		group, ok := result[collectionId]
+		if !ok {
+			group = make(map[string]*core.Record)
+			result[collectionId] = group
+		}
+		if existing, ok := group[name]; ok {
+			return nil, fmt.Errorf("duplicate case name: %s (collection=%s, id=%s, id=%s)", name, collectionId, existing.Id, record.Id)
+		}
+		group[name] = record
+	}
+	return result, nil
+}
+
+func apiTestValueListToMap(items []apiTestKeyValue) map[string]string {
+	result := make(map[string]string)
+	for _, item := range items {
+		if !item.Enabled {
+			continue
+		}
+		key := strings.TrimSpace(item.Key)
+		if key == "" {
+			continue
+		}
+		result[key] = item.Value
+	}
+	return result
+}
+
+func (h *Hub) buildApiTestHeaders(record *core.Record) (map[string]string, error) {
+	var items []apiTestKeyValue
+	if err := record.UnmarshalJSONField("headers", &items); err != nil {
+		return nil, err
+	}
+	headers := apiTestValueListToMap(items)
+	bodyType := strings.ToLower(record.GetString("body_type"))
+	if bodyType == "json" || bodyType == apiTestBodyTypeGraphQL {
+		if _, ok := headers["Content-Type"]; !ok {
+			headers["Content-Type"] = "application/json"
+		}
+	}
+	return headers, nil
+}
+
+func (h *Hub) buildApiTestParams(record *core.Record) (map[string]string, error) {
+	var items []apiTestKeyValue
+	if err := record.UnmarshalJSONField("params", &items); err != nil {
+		return nil, err
+	}
+	return apiTestValueListToMap(items), nil
+}
+
+// buildApiTestBody 组装请求体，body 中的合集变量占位符由 resolver 替换后再按 body_type 解析。
+// 返回编码后的完整请求体，nil 表示不发送请求体；Content-Length 校验基于该长度。
+func (h *Hub) buildApiTestBody(record *core.Record, resolver *apiTestVariableResolver) ([]byte, string, error) {
+	method := strings.ToUpper(strings.TrimSpace(record.GetString("method")))
+	if method == http.MethodGet || method == http.MethodHead {
+		return nil, "", nil
+	}
+	body := resolver.apply(record.GetString("body"))
+	if strings.TrimSpace(body) == "" {
+		return nil, "", nil
+	}
+	bodyType := strings.ToLower(record.GetString("body_type"))
+	switch bodyType {
+	case "json":
+		if !json.Valid([]byte(body)) {
+			return nil, "", errors.New("请求体不是有效的 JSON")
+		}
+		return []byte(body), "application/json", nil
+	case "text":
+		return []byte(body), "text/plain", nil
+	case apiTestBodyTypeGraphQL:
+		payload, err := apiTestParseGraphQLBody(body)
+		if err != nil {
+			return nil, "", err
+		}
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, "", err
+		}
+		return encoded, "application/json", nil
+	case "form":
+		values := url.Values{}
+		var raw any
+		if err := json.Unmarshal([]byte(body), &raw); err != nil {
+			return nil, "", err
+		}
+		switch typed := raw.(type) {
+		case []any:
+			for _, item := range typed {
+				itemMap, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				enabled, _ := itemMap["enabled"].(bool)
+				if itemMap["enabled"] != nil && !enabled {
+					continue
+				}
+				key := strings.TrimSpace(fmt.Sprintf("%v", itemMap["key"]))
+				if key == "" {
+					continue
+				}
+				value := ""
+				if rawValue, exists := itemMap["value"]; exists && rawValue != nil {
+					value = fmt.Sprintf("%v", rawValue)
+				}
+				values.Add(key, value)
+			}
+		case map[string]any:
+			for key, value := range typed {
+				trimmedKey := strings.TrimSpace(key)
+				if trimmedKey == "" {
+					continue
+				}
+				values.Add(trimmedKey, fmt.Sprintf("%v", value))
+			}
+		default:
+			return nil, "", errors.New("表单请求体格式不正确")
+		}
+		return []byte(values.Encode()), "application/x-www-form-urlencoded", nil
+	default:
+		return nil, "", fmt.Errorf("未知的请求体类型: %s", bodyType)
+	}
+}
+
+func (h *Hub) resolveApiTestURL(collectionRecord *core.Record, caseRecord *core.Record) (string, error) {
+	rawURL := strings.TrimSpace(caseRecord.GetString("url"))
+	if rawURL == "" {
+		return "", errors.New("请求地址不能为空")
+	}
+	if strings.HasPrefix(strings.ToLower(rawURL), "http://") || strings.HasPrefix(strings.ToLower(rawURL), "https://") {
+		return rawURL, nil
+	}
+	base := strings.TrimSpace(collectionRecord.GetString("base_url"))
+	if base == "" {
+		return "", errors.New("合集未设置基础地址，无法拼接相对路径")
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return "", errors.New("合集基础地址不合法")
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(rawURL, "/"), nil
+}
+
+func apiTestParseAllowedHosts(raw string) map[string]struct{} {
+	result := make(map[string]struct{})
+	for _, item := range strings.Split(raw, ",") {
+		host := strings.ToLower(strings.TrimSpace(item))
+		if host == "" {
+			continue
+		}
+		result[host] = struct{}{}
+	}
+	return result
+}
+
+func apiTestParseAllowedCIDRs(raw string) ([]*net.IPNet, []string) {
+	var (
+		result []*net.IPNet
+		errors []string
+	)
+	for _, item := range strings.Split(raw, ",") {
+		cidr := strings.TrimSpace(item)
+		if cidr == "" {
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			errors = append(errors, cidr)
+			continue
+		}
+		result = append(result, network)
+	}
+	return result, errors
+}
+
+func (h *Hub) validateApiTestTarget(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("解析 URL 失败: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return errors.New("仅允许 http/https 协议")
+	}
+	return h.validateApiTestHost(parsed.Hostname())
+}
+
+var (
+	// errApiTestLoopbackBlocked 与 errApiTestPrivateBlocked 为 SSRF 过滤拦截目标地址时返回的错误
+	errApiTestLoopbackBlocked = errors.New("禁止访问本地回环地址")
+	errApiTestPrivateBlocked  = errors.New("禁止访问内网或本地地址")
+)
+
+// apiTestIsSSRFBlocked 判断错误是否为 SSRF 过滤拦截，接口据此返回 SSRF_BLOCKED 而非一般的参数错误。
+func apiTestIsSSRFBlocked(err error) bool {
+	return errors.Is(err, errApiTestLoopbackBlocked) || errors.Is(err, errApiTestPrivateBlocked)
+}
+
+// validateApiTestDialIP 校验域名 host 解析后实际连接的 ip。白名单主机不限制解析结果。
+func (h *Hub) validateApiTestDialIP(host string, ip net.IP) error {
+	enableFilter, _ := GetEnv("API_TEST_ENABLE_SSRF_FILTER")
+	if strings.ToLower(enableFilter) != "true" {
+		return nil
+	}
+	allowedHostsRaw, _ := GetEnv("API_TEST_ALLOWED_HOSTS")
+	if _, ok := apiTestParseAllowedHosts(allowedHostsRaw)[strings.ToLower(strings.TrimSpace(host))]; ok {
+		return nil
 	}
-	host := strings.ToLower(strings.TrimSpace(parsed.Hostname()))
+	return h.validateApiTestHost(ip.String())
+}
+
+// validateApiTestHost 按 SSRF 过滤配置校验目标主机（域名或 IP），HTTP 用例与 TCP 探测共用。
+func (h *Hub) validateApiTestHost(host string) error {
+	host = strings.ToLower(strings.TrimSpace(host))
 	if host == "" {
 		return errors.New("目标地址缺少主机名")
 	}
@@ -506,7 +1649,7 @@ func (h *Hub) validateApiTestTarget(rawURL string) error {
 		return nil
 	}
 	if host == "localhost" || host == "127.0.0.1" || host == "0.0.0.0" {
-		return errors.New("禁止访问本地回环地址")
+		return errApiTestLoopbackBlocked
 	}
 	allowedCIDRsRaw, _ := GetEnv("API_TEST_ALLOWED_CIDRS")
 	allowedCIDRs, invalidCIDRs := apiTestParseAllowedCIDRs(allowedCIDRsRaw)
@@ -516,7 +1659,7 @@ func (h *Hub) validateApiTestTarget(rawURL string) error {
 	ip := net.ParseIP(host)
 	if ip != nil {
 		if apiTestIPBlocked(ip, allowedCIDRs) {
-			return errors.New("禁止访问内网或本地地址")
+			return errApiTestPrivateBlocked
 		}
 		return nil
 	}
@@ -526,9 +1669,217 @@ func (h *Hub) validateApiTestTarget(rawURL string) error {
 	}
 	for _, addr := range addrs {
 		if apiTestIPBlocked(addr, allowedCIDRs) {
-			return errors.New("禁止访问内网或本地地址")
+			return errApiTestPrivateBlocked
+		}
+	}
+	return nil
+}
+
+// apiTestApplyContentLength 处理 Content-Length 请求头：auto 为 true 时按请求体长度设置（无请求体时移除），
+// 覆盖自定义的同名请求头；否则自定义的 Content-Length 必须与请求体长度一致，避免严格的服务端因长度不符拒绝或截断请求。
+func apiTestApplyContentLength(header http.Header, body []byte, auto bool) error {
+	if auto {
+		if body == nil {
+			header.Del("Content-Length")
+			return nil
+		}
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+		return nil
+	}
+	declared := strings.TrimSpace(header.Get("Content-Length"))
+	if declared == "" {
+		return nil
+	}
+	length, err := strconv.ParseInt(declared, 10, 64)
+	if err != nil || length < 0 {
+		return fmt.Errorf("Content-Length 请求头无效: %s", declared)
+	}
+	if length != int64(len(body)) {
+		return fmt.Errorf("Content-Length 与请求体长度不一致: 请求头为 %d，实际为 %d 字节", length, len(body))
+	}
+	return nil
+}
+
+// apiTestValidateResolveIP 校验主机解析覆盖值，空字符串表示不覆盖。
+func apiTestValidateResolveIP(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if net.ParseIP(value) == nil {
+		return fmt.Errorf("不是有效的 IP 地址: %s", value)
+	}
+	return nil
+}
+
+// apiTestValidationURL 返回用于 SSRF 校验的地址：配置了 resolve_ip 时以该 IP 替换主机名，
+// 保证校验的是实际连接的地址。
+func apiTestValidationURL(target *url.URL, resolveIP string) string {
+	resolveIP = strings.TrimSpace(resolveIP)
+	if resolveIP == "" {
+		return target.String()
+	}
+	overridden := *target
+	if port := target.Port(); port != "" {
+		overridden.Host = net.JoinHostPort(resolveIP, port)
+	} else if strings.Contains(resolveIP, ":") {
+		overridden.Host = "[" + resolveIP + "]"
+	} else {
+		overridden.Host = resolveIP
+	}
+	return overridden.String()
+}
+
+// apiTestHTTPClient 创建执行用例的 HTTP 客户端。配置了 resolveIP 时，连接 host 的请求改为直连该 IP，
+// 请求的 Host 头与 TLS SNI 仍使用原主机名，用于绕过 DNS 探测负载均衡后的单个后端；此时忽略环境变量中的代理。
+// 配置了 tlsPolicy 时握手完成后按断言检查协商结果，不满足则中断连接。proxy 不为 nil 时所有请求经该代理发出。
+func apiTestHTTPClient(timeout time.Duration, host string, resolveIP string, tlsPolicy *apiTestTLSPolicy, proxy *url.URL) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	resolveIP = strings.TrimSpace(resolveIP)
+	overrideHost := resolveIP != "" && host != ""
+	if !overrideHost && tlsPolicy == nil && proxy == nil {
+		return client
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if overrideHost {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		transport.DialContext = func(ctx context.Context, network string, addr string) (net.Conn, error) {
+			if dialHost, port, err := net.SplitHostPort(addr); err == nil && strings.EqualFold(dialHost, host) {
+				addr = net.JoinHostPort(resolveIP, port)
+			}
+			return dialer.DialContext(ctx, network, addr)
+		}
+		// HTTP(S)_PROXY 会让连接发往代理而非 resolveIP，直连时不使用环境变量中的代理
+		transport.Proxy = nil
+	}
+	if tlsPolicy != nil {
+		transport.TLSClientConfig = tlsPolicy.clientConfig()
+	}
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	client.Transport = transport
+	return client
+}
+
+// apiTestTLSVersions 为 tls_min_version 可选值对应的协议版本。
+var apiTestTLSVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// apiTestTLSPolicy 为用例的 TLS 协商断言。violation 记录最近一次握手的违规描述，
+// 同一用例的握手（含重定向）依次进行，无需加锁。
+type apiTestTLSPolicy struct {
+	minVersion uint16
+	ciphers    map[uint16]struct{}
+	violation  string
+}
+
+// apiTestCipherSuiteIDs 返回 Go 支持的全部加密套件（含不安全套件）名称到 ID 的映射。
+func apiTestCipherSuiteIDs() map[string]uint16 {
+	ids := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		ids[suite.Name] = suite.ID
+	}
+	return ids
+}
+
+// apiTestValidateTLSMinVersion 校验 TLS 版本下限，空字符串表示不断言。
+func apiTestValidateTLSMinVersion(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if _, ok := apiTestTLSVersions[value]; !ok {
+		return fmt.Errorf("不支持的 TLS 版本: %s，可选 1.0/1.1/1.2/1.3", value)
+	}
+	return nil
+}
+
+// apiTestValidateTLSCiphers 校验允许的加密套件名称（如 TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256），空列表表示不断言。
+func apiTestValidateTLSCiphers(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	known := apiTestCipherSuiteIDs()
+	for _, name := range names {
+		if _, ok := known[strings.TrimSpace(name)]; !ok {
+			return fmt.Errorf("未知的加密套件: %s", name)
+		}
+	}
+	return nil
+}
+
+// apiTestRecordStringList 读取字符串列表类型的 JSON 字段（如 tls_ciphers），未配置时返回空列表。
+func apiTestRecordStringList(record *core.Record, field string) ([]string, error) {
+	raw := strings.TrimSpace(record.GetString(field))
+	if raw == "" || raw == "null" {
+		return nil, nil
+	}
+	var items []string
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil, fmt.Errorf("%s 格式无效: %w", field, err)
+	}
+	return items, nil
+}
+
+// apiTestBuildTLSPolicy 根据用例配置构建 TLS 断言，未配置断言时返回 nil。
+func apiTestBuildTLSPolicy(caseRecord *core.Record) (*apiTestTLSPolicy, error) {
+	minVersion := strings.TrimSpace(caseRecord.GetString("tls_min_version"))
+	names, err := apiTestRecordStringList(caseRecord, "tls_ciphers")
+	if err != nil {
+		return nil, err
+	}
+	if minVersion == "" && len(names) == 0 {
+		return nil, nil
+	}
+	if err := apiTestValidateTLSMinVersion(minVersion); err != nil {
+		return nil, err
+	}
+	if err := apiTestValidateTLSCiphers(names); err != nil {
+		return nil, err
+	}
+	policy := &apiTestTLSPolicy{minVersion: apiTestTLSVersions[minVersion]}
+	if len(names) > 0 {
+		known := apiTestCipherSuiteIDs()
+		policy.ciphers = make(map[uint16]struct{}, len(names))
+		for _, name := range names {
+			policy.ciphers[known[strings.TrimSpace(name)]] = struct{}{}
+		}
+	}
+	return policy, nil
+}
+
+// clientConfig 放宽客户端可协商的版本与套件，使旧协议或弱套件能完成握手并被如实报告，
+// 违规连接在 VerifyConnection 中断开，不会发出请求。
+func (p *apiTestTLSPolicy) clientConfig() *tls.Config {
+	config := &tls.Config{
+		MinVersion:       tls.VersionTLS10,
+		VerifyConnection: p.verify,
+	}
+	if p.ciphers != nil {
+		for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+			config.CipherSuites = append(config.CipherSuites, suite.ID)
+		}
+	}
+	return config
+}
+
+func (p *apiTestTLSPolicy) verify(state tls.ConnectionState) error {
+	p.violation = ""
+	if p.minVersion != 0 && state.Version < p.minVersion {
+		p.violation = fmt.Sprintf("TLS 断言失败: 协商版本 %s 低于下限 %s", tls.VersionName(state.Version), tls.VersionName(p.minVersion))
+	} else if p.ciphers != nil {
+		if _, ok := p.ciphers[state.CipherSuite]; !ok {
+			p.violation = fmt.Sprintf("TLS 断言失败: 协商套件 %s 不在允许列表中", tls.CipherSuiteName(state.CipherSuite))
 		}
 	}
+	if p.violation != "" {
+		return errors.New(p.violation)
+	}
 	return nil
 }
 
@@ -565,6 +1916,8 @@ func (h *Hub) getOrCreateApiTestScheduleConfig() (*core.Record, error) {
 	newRecord.Set("alert_enabled", false)
 	newRecord.Set("alert_on_recover", true)
 	newRecord.Set("history_retention_days", apiTestDefaultHistoryRetentionDays)
+	newRecord.Set("stats_refresh_minutes", apiTestDefaultStatsRefreshMinutes)
+	newRecord.Set("stats_window_hours", apiTestDefaultStatsWindowHours)
 	newRecord.Set("last_error", "")
 	if err := h.Save(newRecord); err != nil {
 		return nil, err
@@ -583,6 +1936,12 @@ func (h *Hub) buildApiTestScheduleResponse(record *core.Record) apiTestScheduleR
 		AlertEnabled:         record.GetBool("alert_enabled"),
 		AlertOnRecover:       record.GetBool("alert_on_recover"),
 		HistoryRetentionDays: record.GetInt("history_retention_days"),
+		StatsRefreshMinutes:  apiTestStatsRefreshMinutes(record),
+		StatsWindowHours:     apiTestStatsWindows(record),
+		TLSExpiryAlertDays:   record.GetInt("tls_expiry_alert_days"),
+		AuditRetentionDays:   apiTestAuditRetentionDays(record),
+		WebhookURL:           record.GetString("webhook_url"),
+		LastRunSummary:       apiTestRecordScheduleRunSummary(record),
 	}
 }
 
@@ -590,7 +1949,7 @@ func (h *Hub) getApiTestScheduleConfig(e *core.RequestEvent) error {
 	record, err := h.getOrCreateApiTestScheduleConfig()
 	if err != nil {
 		h.logApiTestError("获取接口定时配置失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("获取接口定时配置失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("获取接口定时配置失败", err, nil).Error())
 	}
 	return e.JSON(http.StatusOK, h.buildApiTestScheduleResponse(record))
 }
@@ -599,19 +1958,19 @@ func (h *Hub) updateApiTestScheduleConfig(e *core.RequestEvent) error {
 	var payload apiTestScheduleUpdateRequest
 	if err := apiTestParseBody(e, &payload); err != nil {
 		h.logApiTestError("解析接口定时配置失败", err)
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": formatApiTestError("解析接口定时配置失败", err, nil).Error()})
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析接口定时配置失败", err, nil).Error())
 	}
 	record, err := h.getOrCreateApiTestScheduleConfig()
 	if err != nil {
 		h.logApiTestError("读取接口定时配置失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("读取接口定时配置失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取接口定时配置失败", err, nil).Error())
 	}
 	if payload.Enabled != nil {
 		record.Set("enabled", *payload.Enabled)
 	}
 	if payload.IntervalMinutes != nil {
 		if *payload.IntervalMinutes <= 0 {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": formatApiTestError("intervalMinutes 无效", errors.New("必须大于 0"), map[string]any{"intervalMinutes": *payload.IntervalMinutes}).Error()})
+			return respondError(e, http.StatusBadRequest, formatApiTestError("intervalMinutes 无效", errors.New("必须大于 0"), map[string]any{"intervalMinutes": *payload.IntervalMinutes}).Error())
 		}
 		record.Set("interval_minutes", *payload.IntervalMinutes)
 	}
@@ -623,26 +1982,69 @@ func (h *Hub) updateApiTestScheduleConfig(e *core.RequestEvent) error {
 	}
 	if payload.HistoryRetentionDays != nil {
 		if *payload.HistoryRetentionDays <= 0 {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": formatApiTestError("historyRetentionDays 无效", errors.New("必须大于 0"), map[string]any{"historyRetentionDays": *payload.HistoryRetentionDays}).Error()})
+			return respondError(e, http.StatusBadRequest, formatApiTestError("historyRetentionDays 无效", errors.New("必须大于 0"), map[string]any{"historyRetentionDays": *payload.HistoryRetentionDays}).Error())
 		}
 		record.Set("history_retention_days", *payload.HistoryRetentionDays)
 	}
+	if payload.StatsRefreshMinutes != nil {
+		if *payload.StatsRefreshMinutes <= 0 || *payload.StatsRefreshMinutes > apiTestMaxScheduleMinutes {
+			return respondError(e, http.StatusBadRequest, formatApiTestError("statsRefreshMinutes 无效", fmt.Errorf("必须为 1-%d", apiTestMaxScheduleMinutes), map[string]any{"statsRefreshMinutes": *payload.StatsRefreshMinutes}).Error())
+		}
+		record.Set("stats_refresh_minutes", *payload.StatsRefreshMinutes)
+	}
+	if payload.StatsWindowHours != nil {
+		windows, err := apiTestValidateStatsWindows(payload.StatsWindowHours)
+		if err != nil {
+			return respondError(e, http.StatusBadRequest, formatApiTestError("statsWindowHours 无效", err, nil).Error())
+		}
+		record.Set("stats_window_hours", windows)
+	}
+	if payload.TLSExpiryAlertDays != nil {
+		if err := apiTestValidateTLSExpiryAlertDays(*payload.TLSExpiryAlertDays); err != nil {
+			return respondError(e, http.StatusBadRequest, formatApiTestError("tlsExpiryAlertDays 无效", err, map[string]any{"tlsExpiryAlertDays": *payload.TLSExpiryAlertDays}).Error())
+		}
+		record.Set("tls_expiry_alert_days", *payload.TLSExpiryAlertDays)
+	}
+	if payload.AuditRetentionDays != nil {
+		if err := apiTestValidateAuditRetentionDays(*payload.AuditRetentionDays); err != nil {
+			return respondError(e, http.StatusBadRequest, formatApiTestError("auditRetentionDays 无效", err, map[string]any{"auditRetentionDays": *payload.AuditRetentionDays}).Error())
+		}
+		record.Set("audit_retention_days", *payload.AuditRetentionDays)
+	}
+	if payload.WebhookURL != nil {
+		webhookURL := strings.TrimSpace(*payload.WebhookURL)
+		if err := h.validateApiTestWebhookURL(webhookURL); err != nil {
+			code := errCodeValidationFailed
+			if apiTestIsSSRFBlocked(err) {
+				code = errCodeSSRFBlocked
+			}
+			return respondErrorWithCode(e, http.StatusBadRequest, code, formatApiTestError("webhookUrl 无效", err, nil).Error())
+		}
+		record.Set("webhook_url", webhookURL)
+	}
 	if record.GetBool("enabled") && record.GetDateTime("next_run_at").IsZero() {
 		interval := record.GetInt("interval_minutes")
 		record.Set("next_run_at", apiTestNowDateTime().Add(time.Duration(interval)*time.Minute))
 	}
 	if err := h.Save(record); err != nil {
 		h.logApiTestError("保存接口定时配置失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("保存接口定时配置失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("保存接口定时配置失败", err, nil).Error())
 	}
 	return e.JSON(http.StatusOK, h.buildApiTestScheduleResponse(record))
 }
 
 func (h *Hub) exportApiTests(e *core.RequestEvent) error {
+	// 用例认证密码与令牌默认不导出，includeSecrets=true 时以明文导出，仅限可写用户
+	includeSecrets := e.Request.URL.Query().Get("includeSecrets") == "true"
+	if includeSecrets {
+		if err := requireWritable(e); err != nil {
+			return err
+		}
+	}
 	collections, err := h.FindRecordsByFilter(apiTestCollectionsCollection, "", "sort_order,created", -1, 0, nil)
 	if err != nil {
 		h.logApiTestError("读取接口合集失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("读取接口合集失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取接口合集失败", err, nil).Error())
 	}
 	collectionNameById := make(map[string]string, len(collections))
 	exportCollections := make([]apiTestExportCollection, 0, len(collections))
@@ -650,22 +2052,40 @@ func (h *Hub) exportApiTests(e *core.RequestEvent) error {
 		var tags []string
 		if err := record.UnmarshalJSONField("tags", &tags); err != nil {
 			h.logApiTestError("解析合集标签失败", err, "collectionId", record.Id)
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("解析合集标签失败", err, map[string]any{"collectionId": record.Id}).Error()})
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("解析合集标签失败", err, map[string]any{"collectionId": record.Id}).Error())
+		}
+		variables, err := apiTestCollectionVariableItems(record)
+		if err != nil {
+			h.logApiTestError("解析合集变量失败", err, "collectionId", record.Id)
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("解析合集变量失败", err, map[string]any{"collectionId": record.Id}).Error())
 		}
 		name := record.GetString("name")
 		collectionNameById[record.Id] = name
 		exportCollections = append(exportCollections, apiTestExportCollection{
-			Name:        name,
-			Description: record.GetString("description"),
-			BaseURL:     record.GetString("base_url"),
-			SortOrder:   record.GetInt("sort_order"),
-			Tags:        apiTestNormalizeStringList(tags),
+			Name:         name,
+			Description:  record.GetString("description"),
+			BaseURL:      record.GetString("base_url"),
+			SortOrder:    record.GetInt("sort_order"),
+			Tags:         apiTestNormalizeStringList(tags),
+			ScheduleCron: record.GetString("schedule_cron"),
+			SnippetBytes: record.GetInt("snippet_bytes"),
+			HealthWindow: record.GetInt("health_window_minutes"),
+			HealthAlert:  record.GetFloat("health_alert_threshold"),
+			PacingMs:     record.GetInt("pacing_ms"),
+			ProxyURL:     apiTestExportProxyURL(record.GetString("proxy_url")),
+			Variables:    variables,
+			OAuth:        apiTestExportOAuthFor(record),
 		})
 	}
 	cases, err := h.FindRecordsByFilter(apiTestCasesCollection, "", "collection,sort_order,created", -1, 0, nil)
 	if err != nil {
 		h.logApiTestError("读取接口用例失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("读取接口用例失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取接口用例失败", err, nil).Error())
+	}
+	templateNameById, err := h.apiTestAssertionTemplateNames()
+	if err != nil {
+		h.logApiTestError("读取断言模板失败", err)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取断言模板失败", err, nil).Error())
 	}
 	exportCases := make([]apiTestExportCase, 0, len(cases))
 	for _, record := range cases {
@@ -673,46 +2093,131 @@ func (h *Hub) exportApiTests(e *core.RequestEvent) error {
 		if !ok {
 			err := fmt.Errorf("collection not found for case %s", record.Id)
 			h.logApiTestError("获取用例所属合集失败", err, "caseId", record.Id)
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("获取用例所属合集失败", err, map[string]any{"caseId": record.Id}).Error()})
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("获取用例所属合集失败", err, map[string]any{"caseId": record.Id}).Error())
 		}
 		var headers []apiTestKeyValue
 		if err := record.UnmarshalJSONField("headers", &headers); err != nil {
 			h.logApiTestError("解析用例请求头失败", err, "caseId", record.Id)
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("解析用例请求头失败", err, map[string]any{"caseId": record.Id}).Error()})
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("解析用例请求头失败", err, map[string]any{"caseId": record.Id}).Error())
 		}
 		var params []apiTestKeyValue
 		if err := record.UnmarshalJSONField("params", &params); err != nil {
 			h.logApiTestError("解析用例参数失败", err, "caseId", record.Id)
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("解析用例参数失败", err, map[string]any{"caseId": record.Id}).Error()})
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("解析用例参数失败", err, map[string]any{"caseId": record.Id}).Error())
 		}
 		var tags []string
 		if err := record.UnmarshalJSONField("tags", &tags); err != nil {
 			h.logApiTestError("解析用例标签失败", err, "caseId", record.Id)
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("解析用例标签失败", err, map[string]any{"caseId": record.Id}).Error()})
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("解析用例标签失败", err, map[string]any{"caseId": record.Id}).Error())
+		}
+		tlsCiphers, err := apiTestRecordStringList(record, "tls_ciphers")
+		if err != nil {
+			h.logApiTestError("解析用例加密套件失败", err, "caseId", record.Id)
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("解析用例加密套件失败", err, map[string]any{"caseId": record.Id}).Error())
+		}
+		expectedBodyIgnore, err := apiTestRecordStringList(record, "expected_body_ignore")
+		if err != nil {
+			h.logApiTestError("解析用例忽略路径失败", err, "caseId", record.Id)
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("解析用例忽略路径失败", err, map[string]any{"caseId": record.Id}).Error())
+		}
+		statusBranches, err := apiTestRecordStatusBranches(record)
+		if err != nil {
+			h.logApiTestError("解析用例状态码分支失败", err, "caseId", record.Id)
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("解析用例状态码分支失败", err, map[string]any{"caseId": record.Id}).Error())
+		}
+		expectedCookies, err := apiTestRecordCookieAssertions(record)
+		if err != nil {
+			h.logApiTestError("解析用例 Cookie 断言失败", err, "caseId", record.Id)
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("解析用例 Cookie 断言失败", err, map[string]any{"caseId": record.Id}).Error())
+		}
+		auth, err := h.apiTestExportAuthFor(record, includeSecrets)
+		if err != nil {
+			h.logApiTestError("导出用例认证配置失败", err, "caseId", record.Id)
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("导出用例认证配置失败", err, map[string]any{"caseId": record.Id}).Error())
+		}
+		fingerprintPaths, err := apiTestRecordStringList(record, "fingerprint_paths")
+		if err != nil {
+			h.logApiTestError("解析用例指纹路径失败", err, "caseId", record.Id)
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("解析用例指纹路径失败", err, map[string]any{"caseId": record.Id}).Error())
+		}
+		var assertionTemplates []string
+		for _, id := range record.GetStringSlice("assertion_templates") {
+			if name, ok := templateNameById[id]; ok {
+				assertionTemplates = append(assertionTemplates, name)
+			}
 		}
 		exportCases = append(exportCases, apiTestExportCase{
-			Collection:      collectionName,
-			Name:            record.GetString("name"),
-			Method:          record.GetString("method"),
-			URL:             record.GetString("url"),
-			Description:     record.GetString("description"),
-			Headers:         apiTestNormalizeKeyValues(headers),
-			Params:          apiTestNormalizeKeyValues(params),
-			BodyType:        record.GetString("body_type"),
-			Body:            record.GetString("body"),
-			ExpectedStatus:  record.GetInt("expected_status"),
-			TimeoutMs:       record.GetInt("timeout_ms"),
-			ScheduleEnabled: record.GetBool("schedule_enabled"),
-			ScheduleMinutes: record.GetInt("schedule_minutes"),
-			SortOrder:       record.GetInt("sort_order"),
-			Tags:            apiTestNormalizeStringList(tags),
-			AlertThreshold:  record.GetInt("alert_threshold"),
+			Collection:         collectionName,
+			Name:               record.GetString("name"),
+			Method:             record.GetString("method"),
+			URL:                record.GetString("url"),
+			Description:        record.GetString("description"),
+			Headers:            apiTestNormalizeKeyValues(headers),
+			Params:             apiTestNormalizeKeyValues(params),
+			BodyType:           record.GetString("body_type"),
+			Body:               record.GetString("body"),
+			ExpectedStatus:     record.GetInt("expected_status"),
+			TimeoutMs:          record.GetInt("timeout_ms"),
+			ScheduleEnabled:    record.GetBool("schedule_enabled"),
+			Enabled:            types.Pointer(record.GetBool("enabled")),
+			ScheduleMinutes:    record.GetInt("schedule_minutes"),
+			SortOrder:          record.GetInt("sort_order"),
+			Tags:               apiTestNormalizeStringList(tags),
+			AlertThreshold:     record.GetInt("alert_threshold"),
+			AlertMode:          record.GetString("alert_mode"),
+			AlertSuccessRate:   record.GetFloat("alert_success_rate"),
+			AlertWindowMinutes: record.GetInt("alert_window_minutes"),
+			ScheduleCron:       record.GetString("schedule_cron"),
+			MonotonicPath:      record.GetString("monotonic_path"),
+			MinResponseBytes:   record.GetInt("min_response_bytes"),
+			MaxResponseBytes:   record.GetInt("max_response_bytes"),
+			NotContains:        record.GetString("not_contains"),
+			NotContainsRegex:   record.GetBool("not_contains_regex"),
+			BodyContains:       record.GetString("expected_body_contains"),
+			JSONPath:           record.GetString("expected_json_path"),
+			JSONValue:          record.GetString("expected_json_value"),
+			System:             record.GetString("system"),
+			ResolveIP:          record.GetString("resolve_ip"),
+			AutoContentLength:  record.GetBool("auto_content_length"),
+			TLSMinVersion:      record.GetString("tls_min_version"),
+			TLSCiphers:         tlsCiphers,
+			ExpectedBody:       record.GetString("expected_body"),
+			ExpectedBodyMode:   record.GetString("expected_body_mode"),
+			ExpectedBodyIgnore: expectedBodyIgnore,
+			StatusBranches:     statusBranches,
+			ExpectedCookies:    expectedCookies,
+			Auth:               auth,
+			FingerprintMode:    record.GetString("fingerprint_mode"),
+			FingerprintPaths:   fingerprintPaths,
+			ExpectedLocation:   record.GetString("expected_location"),
+			LocationRegex:      record.GetBool("expected_location_regex"),
+			FollowRedirects:    types.Pointer(record.GetBool("follow_redirects")),
+			ProbeType:          record.GetString("probe_type"),
+			HTTPProtocol:       record.GetString("http_protocol"),
+			SnippetBytes:       record.GetInt("response_snippet_bytes"),
+			RetryCount:         record.GetInt("retry_count"),
+			RetryDelayMs:       record.GetInt("retry_delay_ms"),
+			TotalTimeoutMs:     record.GetInt("total_timeout_ms"),
+			HealthWeight:       record.GetInt("health_weight"),
+			MaxLatencyMs:       record.GetInt("max_latency_ms"),
+			ForwardedFor:       record.GetString("forwarded_for"),
+			ForwardedProto:     record.GetString("forwarded_proto"),
+			RealIP:             record.GetString("real_ip"),
+			AssertionTemplates: assertionTemplates,
 		})
 	}
 	payload := apiTestExportPayload{
 		Collections: exportCollections,
 		Cases:       exportCases,
 	}
+	if apiTestWantsYAML(e) {
+		data, err := apiTestMarshalYAML(payload)
+		if err != nil {
+			h.logApiTestError("导出 YAML 失败", err)
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("导出 YAML 失败", err, nil).Error())
+		}
+		return e.Blob(http.StatusOK, "application/yaml; charset=utf-8", data)
+	}
 	return e.JSON(http.StatusOK, payload)
 }
 
@@ -729,11 +2234,35 @@ func apiTestValidateImportData(payload apiTestExportPayload) (apiTestExportPaylo
 		if collection.SortOrder < 0 {
 			return apiTestExportPayload{}, fmt.Errorf("collections[%d].sort_order 不能为负数", index)
 		}
+		if _, err := apiTestParseScheduleCron(collection.ScheduleCron); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("collections[%d].schedule_cron 无效: %v", index, err)
+		}
+		if err := apiTestValidateSnippetBytes(collection.SnippetBytes); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("collections[%d].snippet_bytes 无效: %v", index, err)
+		}
+		if field, err := apiTestValidateHealthRule(collection.HealthWindow, collection.HealthAlert); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("collections[%d].%s 无效: %v", index, field, err)
+		}
+		if err := apiTestValidatePacing(collection.PacingMs); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("collections[%d].pacing_ms 无效: %v", index, err)
+		}
+		if err := apiTestValidateProxyURL(collection.ProxyURL); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("collections[%d].proxy_url 无效: %v", index, err)
+		}
+		if err := apiTestValidateVariables(collection.Variables); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("collections[%d].variables 无效: %v", index, err)
+		}
+		if collection.OAuth != nil {
+			if field, err := apiTestValidateOAuthConfig(collection.OAuth.TokenURL, collection.OAuth.ClientID, collection.OAuth.GrantType); err != nil {
+				return apiTestExportPayload{}, fmt.Errorf("collections[%d].oauth.%s 无效: %v", index, strings.TrimPrefix(field, "oauth_"), err)
+			}
+		}
 		if _, ok := collectionNames[collection.Name]; ok {
 			return apiTestExportPayload{}, fmt.Errorf("collections[%d].name 重复", index)
 		}
 		collectionNames[collection.Name] = struct{}{}
 		collection.Tags = apiTestNormalizeStringList(collection.Tags)
+		collection.ScheduleCron = strings.TrimSpace(collection.ScheduleCron)
 		normalizedCollections = append(normalizedCollections, collection)
 	}
 	caseKeys := make(map[string]struct{}, len(payload.Cases))
@@ -766,7 +2295,11 @@ func apiTestValidateImportData(payload apiTestExportPayload) (apiTestExportPaylo
 		if !apiTestIsValidBodyType(caseItem.BodyType) {
 			return apiTestExportPayload{}, fmt.Errorf("cases[%d].body_type 无效", index)
 		}
-		if caseItem.ExpectedStatus <= 0 || caseItem.ExpectedStatus > apiTestMaxStatusCode {
+		if err := apiTestValidateGraphQLBody(caseItem.BodyType, caseItem.Body); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].body 无效: %v", index, err)
+		}
+		// TCP 探测不判定状态码
+		if !apiTestIsTCPProbe(caseItem.ProbeType) && (caseItem.ExpectedStatus <= 0 || caseItem.ExpectedStatus > apiTestMaxStatusCode) {
 			return apiTestExportPayload{}, fmt.Errorf("cases[%d].expected_status 无效", index)
 		}
 		if caseItem.TimeoutMs <= 0 || caseItem.TimeoutMs > apiTestMaxTimeoutMs {
@@ -781,6 +2314,78 @@ func apiTestValidateImportData(payload apiTestExportPayload) (apiTestExportPaylo
 		if caseItem.AlertThreshold <= 0 || caseItem.AlertThreshold > apiTestMaxAlertThreshold {
 			return apiTestExportPayload{}, fmt.Errorf("cases[%d].alert_threshold 无效", index)
 		}
+		if field, err := apiTestValidateAlertRule(caseItem.AlertMode, caseItem.AlertSuccessRate, caseItem.AlertWindowMinutes); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].%s 无效: %v", index, field, err)
+		}
+		if _, err := apiTestParseScheduleCron(caseItem.ScheduleCron); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].schedule_cron 无效: %v", index, err)
+		}
+		if err := apiTestValidateJSONPath(caseItem.MonotonicPath); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].monotonic_path 无效: %v", index, err)
+		}
+		if field, err := apiTestValidateForwardedHeaders(caseItem.ForwardedFor, caseItem.ForwardedProto, caseItem.RealIP); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].%s 无效: %v", index, field, err)
+		}
+		if field, err := apiTestValidateResponseSize(caseItem.MinResponseBytes, caseItem.MaxResponseBytes); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].%s 无效: %v", index, field, err)
+		}
+		if err := apiTestValidateNotContains(caseItem.NotContains, caseItem.NotContainsRegex); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].not_contains 无效: %v", index, err)
+		}
+		if field, err := apiTestValidateBodyAssertion(caseItem.JSONPath, caseItem.JSONValue); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].%s 无效: %v", index, field, err)
+		}
+		if field, err := apiTestValidateProbe(caseItem.ProbeType, caseItem.URL, caseItem.MaxLatencyMs); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].%s 无效: %v", index, field, err)
+		}
+		if err := apiTestValidateExpectedLocation(caseItem.ExpectedLocation, caseItem.LocationRegex); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].expected_location 无效: %v", index, err)
+		}
+		if err := apiTestValidateHTTPProtocol(caseItem.HTTPProtocol); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].http_protocol 无效: %v", index, err)
+		}
+		if err := apiTestValidateSnippetBytes(caseItem.SnippetBytes); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].response_snippet_bytes 无效: %v", index, err)
+		}
+		if field, err := apiTestValidateRetry(caseItem.RetryCount, caseItem.RetryDelayMs); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].%s 无效: %v", index, field, err)
+		}
+		if err := apiTestValidateTotalTimeout(caseItem.TimeoutMs, caseItem.TotalTimeoutMs); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].total_timeout_ms 无效: %v", index, err)
+		}
+		if err := apiTestValidateHealthWeight(caseItem.HealthWeight); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].health_weight 无效: %v", index, err)
+		}
+		if err := apiTestValidateResolveIP(caseItem.ResolveIP); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].resolve_ip 无效: %v", index, err)
+		}
+		if field, err := apiTestValidateExportAuth(caseItem.Auth); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].%s 无效: %v", index, field, err)
+		}
+		if err := apiTestValidateTLSMinVersion(caseItem.TLSMinVersion); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].tls_min_version 无效: %v", index, err)
+		}
+		if err := apiTestValidateTLSCiphers(caseItem.TLSCiphers); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].tls_ciphers 无效: %v", index, err)
+		}
+		if field, err := apiTestValidateExpectedBody(caseItem.ExpectedBody, caseItem.ExpectedBodyMode, caseItem.ExpectedBodyIgnore); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].%s 无效: %v", index, field, err)
+		}
+		if err := apiTestValidateStatusBranches(caseItem.StatusBranches); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].status_branches 无效: %v", index, err)
+		}
+		if err := apiTestValidateCookieAssertions(caseItem.ExpectedCookies); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].expected_cookies 无效: %v", index, err)
+		}
+		if field, err := apiTestValidateFingerprint(caseItem.FingerprintMode, caseItem.FingerprintPaths); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].%s 无效: %v", index, field, err)
+		}
+		for i, name := range caseItem.AssertionTemplates {
+			caseItem.AssertionTemplates[i] = strings.TrimSpace(name)
+		}
+		if err := apiTestValidateAssertionTemplateRefs(caseItem.AssertionTemplates); err != nil {
+			return apiTestExportPayload{}, fmt.Errorf("cases[%d].assertion_templates 无效: %v", index, err)
+		}
 		key := fmt.Sprintf("%s::%s", caseItem.Collection, caseItem.Name)
 		if _, ok := caseKeys[key]; ok {
 			return apiTestExportPayload{}, fmt.Errorf("cases[%d] 与其他用例重复", index)
@@ -789,6 +2394,7 @@ func apiTestValidateImportData(payload apiTestExportPayload) (apiTestExportPaylo
 		caseItem.Headers = apiTestNormalizeKeyValues(caseItem.Headers)
 		caseItem.Params = apiTestNormalizeKeyValues(caseItem.Params)
 		caseItem.Tags = apiTestNormalizeStringList(caseItem.Tags)
+		caseItem.ScheduleCron = strings.TrimSpace(caseItem.ScheduleCron)
 		normalizedCases = append(normalizedCases, caseItem)
 	}
 	return apiTestExportPayload{
@@ -799,38 +2405,75 @@ func apiTestValidateImportData(payload apiTestExportPayload) (apiTestExportPaylo
 
 func (h *Hub) importApiTests(e *core.RequestEvent) error {
 	var payload apiTestImportRequest
-	if err := apiTestParseBody(e, &payload); err != nil {
+	parseBody := apiTestParseBody
+	if apiTestWantsYAML(e) {
+		parseBody = apiTestParseYAMLBody
+	}
+	if err := parseBody(e, &payload); err != nil {
 		h.logApiTestError("解析接口导入请求失败", err)
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": formatApiTestError("解析接口导入请求失败", err, nil).Error()})
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析接口导入请求失败", err, nil).Error())
 	}
 	mode := strings.TrimSpace(payload.Mode)
 	if mode != "skip" && mode != "overwrite" {
 		err := errors.New("mode 必须为 skip 或 overwrite")
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": formatApiTestError("导入模式无效", err, map[string]any{"mode": mode}).Error()})
+		return respondError(e, http.StatusBadRequest, formatApiTestError("导入模式无效", err, map[string]any{"mode": mode}).Error())
+	}
+	if len(payload.OpenAPI) > 0 && string(payload.OpenAPI) != "null" {
+		converted, err := apiTestOpenAPIToImportData(payload.OpenAPI)
+		if err != nil {
+			return respondError(e, http.StatusBadRequest, formatApiTestError("OpenAPI 文档转换失败", err, nil).Error())
+		}
+		payload.Data = converted
 	}
 	data, err := apiTestValidateImportData(payload.Data)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": formatApiTestError("导入数据校验失败", err, nil).Error()})
+		return respondError(e, http.StatusBadRequest, formatApiTestError("导入数据校验失败", err, nil).Error())
+	}
+	for index, caseItem := range data.Cases {
+		if caseItem.System == "" {
+			continue
+		}
+		if _, err := h.resolveSystemRecordForUser(e, caseItem.System); err != nil {
+			return respondError(e, http.StatusBadRequest, formatApiTestError("导入数据校验失败", fmt.Errorf("cases[%d].system 无效: %v", index, err), nil).Error())
+		}
+	}
+	templateNameById, err := h.apiTestAssertionTemplateNames()
+	if err != nil {
+		h.logApiTestError("读取断言模板失败", err)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取断言模板失败", err, nil).Error())
+	}
+	templateIdByName := make(map[string]string, len(templateNameById))
+	for id, name := range templateNameById {
+		templateIdByName[name] = id
+	}
+	// 写入任何记录前确认引用的模板都存在
+	caseTemplateIds := make([][]string, len(data.Cases))
+	for index, caseItem := range data.Cases {
+		ids, err := apiTestResolveAssertionTemplateIds(caseItem.AssertionTemplates, templateIdByName)
+		if err != nil {
+			return respondError(e, http.StatusBadRequest, formatApiTestError("导入数据校验失败", fmt.Errorf("cases[%d].assertion_templates 无效: %v", index, err), nil).Error())
+		}
+		caseTemplateIds[index] = ids
 	}
 	collectionsCollection, err := h.FindCollectionByNameOrId(apiTestCollectionsCollection)
 	if err != nil {
 		h.logApiTestError("读取合集集合失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("读取合集集合失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取合集集合失败", err, nil).Error())
 	}
 	casesCollection, err := h.FindCollectionByNameOrId(apiTestCasesCollection)
 	if err != nil {
 		h.logApiTestError("读取用例集合失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("读取用例集合失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取用例集合失败", err, nil).Error())
 	}
 	existingCollections, err := h.FindRecordsByFilter(apiTestCollectionsCollection, "", "sort_order,created", -1, 0, nil)
 	if err != nil {
 		h.logApiTestError("读取现有合集失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("读取现有合集失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取现有合集失败", err, nil).Error())
 	}
 	existingCollectionsByName, err := apiTestIndexCollectionsByName(existingCollections)
 	if err != nil {
 		h.logApiTestError("现有合集名称冲突", err)
-		return e.JSON(http.StatusConflict, map[string]string{"error": formatApiTestError("现有合集名称冲突", err, nil).Error()})
+		return respondError(e, http.StatusConflict, formatApiTestError("现有合集名称冲突", err, nil).Error())
 	}
 	collectionIds := make(map[string]string, len(data.Collections))
 	response := apiTestImportResponse{}
@@ -846,10 +2489,26 @@ func (h *Hub) importApiTests(e *core.RequestEvent) error {
 			existing.Set("base_url", collection.BaseURL)
 			existing.Set("sort_order", collection.SortOrder)
 			existing.Set("tags", apiTestNormalizeStringList(collection.Tags))
+			existing.Set("schedule_cron", collection.ScheduleCron)
+			existing.Set("snippet_bytes", collection.SnippetBytes)
+			existing.Set("health_window_minutes", collection.HealthWindow)
+			existing.Set("health_alert_threshold", collection.HealthAlert)
+			existing.Set("pacing_ms", collection.PacingMs)
+			if err := h.setApiTestProxyURL(existing, collection.ProxyURL, existing.GetString("proxy_url")); err != nil {
+				h.logApiTestError("导入合集代理配置失败", err, "collectionName", collection.Name)
+				return respondError(e, http.StatusBadRequest, formatApiTestError("导入合集代理配置失败", err, map[string]any{"collectionName": collection.Name}).Error())
+			}
+			existing.Set("variables", apiTestNormalizeKeyValues(collection.Variables))
+			if err := h.applyApiTestImportOAuth(existing, collection.OAuth); err != nil {
+				h.logApiTestError("导入合集 OAuth 配置失败", err, "collectionName", collection.Name)
+				return respondError(e, http.StatusBadRequest, formatApiTestError("导入合集 OAuth 配置失败", err, map[string]any{"collectionName": collection.Name}).Error())
+			}
+			original := existing.Original()
 			if err := h.Save(existing); err != nil {
 				h.logApiTestError("更新合集失败", err, "collectionName", collection.Name)
-				return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("更新合集失败", err, map[string]any{"collectionName": collection.Name}).Error()})
+				return respondError(e, http.StatusInternalServerError, formatApiTestError("更新合集失败", err, map[string]any{"collectionName": collection.Name}).Error())
 			}
+			h.recordApiTestConfigAudit(h, apiTestAuditUserId(e.Auth), apiTestAuditActionUpdate, original, existing)
 			response.Collections.Updated++
 			continue
 		}
@@ -859,29 +2518,44 @@ func (h *Hub) importApiTests(e *core.RequestEvent) error {
 		record.Set("base_url", collection.BaseURL)
 		record.Set("sort_order", collection.SortOrder)
 		record.Set("tags", apiTestNormalizeStringList(collection.Tags))
+		record.Set("schedule_cron", collection.ScheduleCron)
+		record.Set("snippet_bytes", collection.SnippetBytes)
+		record.Set("health_window_minutes", collection.HealthWindow)
+		record.Set("health_alert_threshold", collection.HealthAlert)
+		record.Set("pacing_ms", collection.PacingMs)
+		if err := h.setApiTestProxyURL(record, collection.ProxyURL, ""); err != nil {
+			h.logApiTestError("导入合集代理配置失败", err, "collectionName", collection.Name)
+			return respondError(e, http.StatusBadRequest, formatApiTestError("导入合集代理配置失败", err, map[string]any{"collectionName": collection.Name}).Error())
+		}
+		record.Set("variables", apiTestNormalizeKeyValues(collection.Variables))
+		if err := h.applyApiTestImportOAuth(record, collection.OAuth); err != nil {
+			h.logApiTestError("导入合集 OAuth 配置失败", err, "collectionName", collection.Name)
+			return respondError(e, http.StatusBadRequest, formatApiTestError("导入合集 OAuth 配置失败", err, map[string]any{"collectionName": collection.Name}).Error())
+		}
 		if err := h.Save(record); err != nil {
 			h.logApiTestError("创建合集失败", err, "collectionName", collection.Name)
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("创建合集失败", err, map[string]any{"collectionName": collection.Name}).Error()})
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("创建合集失败", err, map[string]any{"collectionName": collection.Name}).Error())
 		}
+		h.recordApiTestConfigAudit(h, apiTestAuditUserId(e.Auth), apiTestAuditActionCreate, nil, record)
 		collectionIds[collection.Name] = record.Id
 		response.Collections.Created++
 	}
 	existingCases, err := h.FindRecordsByFilter(apiTestCasesCollection, "", "collection,name", -1, 0, nil)
 	if err != nil {
 		h.logApiTestError("读取现有用例失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("读取现有用例失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取现有用例失败", err, nil).Error())
 	}
 	existingCasesByCollection, err := apiTestIndexCasesByCollection(existingCases)
 	if err != nil {
 		h.logApiTestError("现有用例名称冲突", err)
-		return e.JSON(http.StatusConflict, map[string]string{"error": formatApiTestError("现有用例名称冲突", err, nil).Error()})
+		return respondError(e, http.StatusConflict, formatApiTestError("现有用例名称冲突", err, nil).Error())
 	}
-	for _, caseItem := range data.Cases {
+	for caseIndex, caseItem := range data.Cases {
 		collectionId := collectionIds[caseItem.Collection]
 		if collectionId == "" {
 			err := fmt.Errorf("collection not found for %s", caseItem.Collection)
 			h.logApiTestError("用例合集不存在", err, "collectionName", caseItem.Collection)
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": formatApiTestError("用例合集不存在", err, map[string]any{"collectionName": caseItem.Collection}).Error()})
+			return respondError(e, http.StatusBadRequest, formatApiTestError("用例合集不存在", err, map[string]any{"collectionName": caseItem.Collection}).Error())
 		}
 		caseGroup := existingCasesByCollection[collectionId]
 		if caseGroup != nil {
@@ -902,14 +2576,60 @@ func (h *Hub) importApiTests(e *core.RequestEvent) error {
 				existing.Set("expected_status", caseItem.ExpectedStatus)
 				existing.Set("timeout_ms", caseItem.TimeoutMs)
 				existing.Set("schedule_enabled", caseItem.ScheduleEnabled)
+				existing.Set("enabled", caseItem.Enabled == nil || *caseItem.Enabled)
 				existing.Set("schedule_minutes", caseItem.ScheduleMinutes)
 				existing.Set("sort_order", caseItem.SortOrder)
 				existing.Set("tags", apiTestNormalizeStringList(caseItem.Tags))
 				existing.Set("alert_threshold", caseItem.AlertThreshold)
+				existing.Set("alert_mode", strings.TrimSpace(caseItem.AlertMode))
+				existing.Set("alert_success_rate", caseItem.AlertSuccessRate)
+				existing.Set("alert_window_minutes", caseItem.AlertWindowMinutes)
+				existing.Set("schedule_cron", caseItem.ScheduleCron)
+				existing.Set("monotonic_path", strings.TrimSpace(caseItem.MonotonicPath))
+				existing.Set("forwarded_for", strings.TrimSpace(caseItem.ForwardedFor))
+				existing.Set("forwarded_proto", strings.TrimSpace(caseItem.ForwardedProto))
+				existing.Set("real_ip", strings.TrimSpace(caseItem.RealIP))
+				existing.Set("min_response_bytes", caseItem.MinResponseBytes)
+				existing.Set("max_response_bytes", caseItem.MaxResponseBytes)
+				existing.Set("not_contains", caseItem.NotContains)
+				existing.Set("not_contains_regex", caseItem.NotContainsRegex)
+				existing.Set("expected_body_contains", caseItem.BodyContains)
+				existing.Set("expected_json_path", strings.TrimSpace(caseItem.JSONPath))
+				existing.Set("expected_json_value", caseItem.JSONValue)
+				existing.Set("system", caseItem.System)
+				existing.Set("resolve_ip", strings.TrimSpace(caseItem.ResolveIP))
+				existing.Set("auto_content_length", caseItem.AutoContentLength)
+				existing.Set("tls_min_version", strings.TrimSpace(caseItem.TLSMinVersion))
+				existing.Set("tls_ciphers", apiTestNormalizeStringList(caseItem.TLSCiphers))
+				existing.Set("expected_body", caseItem.ExpectedBody)
+				existing.Set("expected_body_mode", strings.TrimSpace(caseItem.ExpectedBodyMode))
+				existing.Set("expected_body_ignore", apiTestNormalizeStringList(caseItem.ExpectedBodyIgnore))
+				existing.Set("status_branches", apiTestNormalizeStatusBranches(caseItem.StatusBranches))
+				existing.Set("expected_cookies", apiTestNormalizeCookieAssertions(caseItem.ExpectedCookies))
+				existing.Set("fingerprint_mode", strings.TrimSpace(caseItem.FingerprintMode))
+				existing.Set("fingerprint_paths", apiTestNormalizeStringList(caseItem.FingerprintPaths))
+				existing.Set("expected_location", strings.TrimSpace(caseItem.ExpectedLocation))
+				existing.Set("expected_location_regex", caseItem.LocationRegex)
+				existing.Set("follow_redirects", caseItem.FollowRedirects == nil || *caseItem.FollowRedirects)
+				existing.Set("probe_type", strings.TrimSpace(caseItem.ProbeType))
+				existing.Set("http_protocol", strings.TrimSpace(caseItem.HTTPProtocol))
+				existing.Set("response_snippet_bytes", caseItem.SnippetBytes)
+				existing.Set("retry_count", caseItem.RetryCount)
+				existing.Set("retry_delay_ms", caseItem.RetryDelayMs)
+				existing.Set("total_timeout_ms", caseItem.TotalTimeoutMs)
+				existing.Set("health_weight", caseItem.HealthWeight)
+				existing.Set("max_latency_ms", caseItem.MaxLatencyMs)
+				existing.Set("assertion_templates", caseTemplateIds[caseIndex])
+				if err := h.applyApiTestImportAuth(existing, caseItem.Auth); err != nil {
+					h.logApiTestError("导入用例认证配置失败", err, "caseName", caseItem.Name)
+					return respondError(e, http.StatusBadRequest, formatApiTestError("导入用例认证配置失败", err, map[string]any{"caseName": caseItem.Name}).Error())
+				}
+				original := existing.Original()
 				if err := h.Save(existing); err != nil {
 					h.logApiTestError("更新用例失败", err, "caseName", caseItem.Name)
-					return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("更新用例失败", err, map[string]any{"caseName": caseItem.Name}).Error()})
+					return respondError(e, http.StatusInternalServerError, formatApiTestError("更新用例失败", err, map[string]any{"caseName": caseItem.Name}).Error())
 				}
+				h.recordApiTestConfigAudit(h, apiTestAuditUserId(e.Auth), apiTestAuditActionUpdate, original, existing)
 				response.Cases.Updated++
 				continue
 			}
@@ -927,80 +2647,867 @@ func (h *Hub) importApiTests(e *core.RequestEvent) error {
 		record.Set("expected_status", caseItem.ExpectedStatus)
 		record.Set("timeout_ms", caseItem.TimeoutMs)
 		record.Set("schedule_enabled", caseItem.ScheduleEnabled)
+		record.Set("enabled", caseItem.Enabled == nil || *caseItem.Enabled)
 		record.Set("schedule_minutes", caseItem.ScheduleMinutes)
 		record.Set("sort_order", caseItem.SortOrder)
 		record.Set("tags", apiTestNormalizeStringList(caseItem.Tags))
 		record.Set("alert_threshold", caseItem.AlertThreshold)
+		record.Set("alert_mode", strings.TrimSpace(caseItem.AlertMode))
+		record.Set("alert_success_rate", caseItem.AlertSuccessRate)
+		record.Set("alert_window_minutes", caseItem.AlertWindowMinutes)
+		record.Set("schedule_cron", caseItem.ScheduleCron)
+		record.Set("monotonic_path", strings.TrimSpace(caseItem.MonotonicPath))
+		record.Set("forwarded_for", strings.TrimSpace(caseItem.ForwardedFor))
+		record.Set("forwarded_proto", strings.TrimSpace(caseItem.ForwardedProto))
+		record.Set("real_ip", strings.TrimSpace(caseItem.RealIP))
+		record.Set("min_response_bytes", caseItem.MinResponseBytes)
+		record.Set("max_response_bytes", caseItem.MaxResponseBytes)
+		record.Set("not_contains", caseItem.NotContains)
+		record.Set("not_contains_regex", caseItem.NotContainsRegex)
+		record.Set("expected_body_contains", caseItem.BodyContains)
+		record.Set("expected_json_path", strings.TrimSpace(caseItem.JSONPath))
+		record.Set("expected_json_value", caseItem.JSONValue)
+		record.Set("system", caseItem.System)
+		record.Set("resolve_ip", strings.TrimSpace(caseItem.ResolveIP))
+		record.Set("auto_content_length", caseItem.AutoContentLength)
+		record.Set("tls_min_version", strings.TrimSpace(caseItem.TLSMinVersion))
+		record.Set("tls_ciphers", apiTestNormalizeStringList(caseItem.TLSCiphers))
+		record.Set("expected_body", caseItem.ExpectedBody)
+		record.Set("expected_body_mode", strings.TrimSpace(caseItem.ExpectedBodyMode))
+		record.Set("expected_body_ignore", apiTestNormalizeStringList(caseItem.ExpectedBodyIgnore))
+		record.Set("status_branches", apiTestNormalizeStatusBranches(caseItem.StatusBranches))
+		record.Set("expected_cookies", apiTestNormalizeCookieAssertions(caseItem.ExpectedCookies))
+		record.Set("fingerprint_mode", strings.TrimSpace(caseItem.FingerprintMode))
+		record.Set("fingerprint_paths", apiTestNormalizeStringList(caseItem.FingerprintPaths))
+		record.Set("expected_location", strings.TrimSpace(caseItem.ExpectedLocation))
+		record.Set("expected_location_regex", caseItem.LocationRegex)
+		record.Set("follow_redirects", caseItem.FollowRedirects == nil || *caseItem.FollowRedirects)
+		record.Set("probe_type", strings.TrimSpace(caseItem.ProbeType))
+		record.Set("http_protocol", strings.TrimSpace(caseItem.HTTPProtocol))
+		record.Set("response_snippet_bytes", caseItem.SnippetBytes)
+		record.Set("retry_count", caseItem.RetryCount)
+		record.Set("retry_delay_ms", caseItem.RetryDelayMs)
+		record.Set("total_timeout_ms", caseItem.TotalTimeoutMs)
+		record.Set("health_weight", caseItem.HealthWeight)
+		record.Set("max_latency_ms", caseItem.MaxLatencyMs)
+		record.Set("assertion_templates", caseTemplateIds[caseIndex])
+		if err := h.applyApiTestImportAuth(record, caseItem.Auth); err != nil {
+			h.logApiTestError("导入用例认证配置失败", err, "caseName", caseItem.Name)
+			return respondError(e, http.StatusBadRequest, formatApiTestError("导入用例认证配置失败", err, map[string]any{"caseName": caseItem.Name}).Error())
+		}
 		if err := h.Save(record); err != nil {
 			h.logApiTestError("创建用例失败", err, "caseName", caseItem.Name)
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("创建用例失败", err, map[string]any{"caseName": caseItem.Name}).Error()})
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("创建用例失败", err, map[string]any{"caseName": caseItem.Name}).Error())
 		}
+		h.recordApiTestConfigAudit(h, apiTestAuditUserId(e.Auth), apiTestAuditActionCreate, nil, record)
 		response.Cases.Created++
 	}
-	return e.JSON(http.StatusOK, response)
+	return e.JSON(http.StatusOK, response)
+}
+
+// apiTestApplyTag 在标签列表中添加或移除指定标签，返回新列表与是否发生变化。
+// 列表经 apiTestNormalizeStringList 归一化，添加时不重复写入。
+func apiTestApplyTag(tags []string, tag string, action string) ([]string, bool) {
+	tags = apiTestNormalizeStringList(tags)
+	switch action {
+	case "add":
+		for _, item := range tags {
+			if item == tag {
+				return tags, false
+			}
+		}
+		return append(tags, tag), true
+	case "remove":
+		result := make([]string, 0, len(tags))
+		for _, item := range tags {
+			if item != tag {
+				result = append(result, item)
+			}
+		}
+		return result, len(result) != len(tags)
+	default:
+		return tags, false
+	}
+}
+
+// bulkUpdateApiTestCaseTags 批量为用例添加/移除标签。
+// 目标用例由 caseIds 或 collectionId 指定（二选一），所有修改在同一事务内完成。
+func (h *Hub) bulkUpdateApiTestCaseTags(e *core.RequestEvent) error {
+	var payload apiTestBulkTagRequest
+	if err := apiTestParseBody(e, &payload); err != nil {
+		h.logApiTestError("解析批量标签请求失败", err)
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析批量标签请求失败", err, nil).Error())
+	}
+	action := strings.TrimSpace(payload.Action)
+	if action != "add" && action != "remove" {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("action 无效", errors.New("action 必须为 add 或 remove"), map[string]any{"action": action}).Error())
+	}
+	tag := strings.TrimSpace(payload.Tag)
+	if tag == "" {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("tag 不能为空", errors.New("tag 缺失"), nil).Error())
+	}
+	collectionId := strings.TrimSpace(payload.CollectionId)
+	caseIds := make([]any, 0, len(payload.CaseIds))
+	for _, id := range payload.CaseIds {
+		if trimmed := strings.TrimSpace(id); trimmed != "" {
+			caseIds = append(caseIds, trimmed)
+		}
+	}
+	if (len(caseIds) == 0) == (collectionId == "") {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("目标用例无效", errors.New("caseIds 与 collectionId 必须且只能指定一个"), nil).Error())
+	}
+	response := apiTestBulkTagResponse{}
+	err := h.RunInTransaction(func(txApp core.App) error {
+		var (
+			records []*core.Record
+			err     error
+		)
+		if collectionId != "" {
+			records, err = txApp.FindRecordsByFilter(apiTestCasesCollection, "collection = {:collection}", "sort_order,created", -1, 0, dbx.Params{"collection": collectionId})
+		} else {
+			records, err = txApp.FindAllRecords(apiTestCasesCollection, dbx.In("id", caseIds...))
+		}
+		if err != nil {
+			return err
+		}
+		response.Matched = len(records)
+		for _, record := range records {
+			var tags []string
+			if err := record.UnmarshalJSONField("tags", &tags); err != nil {
+				return fmt.Errorf("解析用例标签失败 (caseId=%s): %w", record.Id, err)
+			}
+			updated, changed := apiTestApplyTag(tags, tag, action)
+			if !changed {
+				continue
+			}
+			original := record.Original()
+			record.Set("tags", updated)
+			if err := txApp.Save(record); err != nil {
+				return err
+			}
+			h.recordApiTestConfigAudit(txApp, apiTestAuditUserId(e.Auth), apiTestAuditActionUpdate, original, record)
+			response.Changed++
+		}
+		return nil
+	})
+	if err != nil {
+		h.logApiTestError("批量更新用例标签失败", err, "action", action, "tag", tag)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("批量更新用例标签失败", err, map[string]any{"action": action, "tag": tag}).Error())
+	}
+	return e.JSON(http.StatusOK, response)
+}
+
+// apiTestFindMoveConflicts 检查移动到目标合集后的用例重名：与目标合集中未参与移动的用例重名，或同批移动的用例之间重名。
+func apiTestFindMoveConflicts(moving []*core.Record, targetCases []*core.Record) []apiTestMoveConflict {
+	movingIds := make(map[string]struct{}, len(moving))
+	for _, record := range moving {
+		movingIds[record.Id] = struct{}{}
+	}
+	names := make(map[string]string, len(targetCases)+len(moving))
+	for _, record := range targetCases {
+		if _, ok := movingIds[record.Id]; !ok {
+			names[record.GetString("name")] = record.Id
+		}
+	}
+	conflicts := []apiTestMoveConflict{}
+	for _, record := range moving {
+		name := record.GetString("name")
+		if existingId, ok := names[name]; ok {
+			conflicts = append(conflicts, apiTestMoveConflict{CaseId: record.Id, Name: name, ConflictingCaseId: existingId})
+			continue
+		}
+		names[name] = record.Id
+	}
+	return conflicts
+}
+
+// bulkMoveApiTestCases 将用例批量移动到目标合集，仅修改 collection，不重置执行状态与告警等运行时字段。
+// 重名检查与写入在同一事务内完成，存在冲突时不做任何修改并返回冲突列表（409）。
+func (h *Hub) bulkMoveApiTestCases(e *core.RequestEvent) error {
+	var payload apiTestBulkMoveRequest
+	if err := apiTestParseBody(e, &payload); err != nil {
+		h.logApiTestError("解析批量移动请求失败", err)
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析批量移动请求失败", err, nil).Error())
+	}
+	collectionId := strings.TrimSpace(payload.CollectionId)
+	if collectionId == "" {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("collectionId 不能为空", errors.New("collectionId 缺失"), nil).Error())
+	}
+	caseIds := make([]any, 0, len(payload.CaseIds))
+	seen := make(map[string]struct{}, len(payload.CaseIds))
+	for _, id := range payload.CaseIds {
+		trimmed := strings.TrimSpace(id)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; !ok {
+			seen[trimmed] = struct{}{}
+			caseIds = append(caseIds, trimmed)
+		}
+	}
+	if len(caseIds) == 0 {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("caseIds 不能为空", errors.New("caseIds 缺失"), nil).Error())
+	}
+	if _, err := h.FindRecordById(apiTestCollectionsCollection, collectionId); err != nil {
+		return respondError(e, http.StatusNotFound, formatApiTestError("目标合集不存在", err, map[string]any{"collectionId": collectionId}).Error())
+	}
+	response := apiTestBulkMoveResponse{}
+	var missing []string
+	err := h.RunInTransaction(func(txApp core.App) error {
+		records, err := txApp.FindAllRecords(apiTestCasesCollection, dbx.In("id", caseIds...))
+		if err != nil {
+			return err
+		}
+		if len(records) != len(caseIds) {
+			found := make(map[string]struct{}, len(records))
+			for _, record := range records {
+				found[record.Id] = struct{}{}
+			}
+			for _, id := range caseIds {
+				if _, ok := found[id.(string)]; !ok {
+					missing = append(missing, id.(string))
+				}
+			}
+			return sql.ErrNoRows
+		}
+		targetCases, err := txApp.FindRecordsByFilter(apiTestCasesCollection, "collection = {:collection}", "", -1, 0, dbx.Params{"collection": collectionId})
+		if err != nil {
+			return err
+		}
+		if conflicts := apiTestFindMoveConflicts(records, targetCases); len(conflicts) > 0 {
+			response.Conflicts = conflicts
+			return errApiTestMoveConflict
+		}
+		for _, record := range records {
+			if record.GetString("collection") == collectionId {
+				response.Unchanged++
+				continue
+			}
+			original := record.Original()
+			record.Set("collection", collectionId)
+			if err := txApp.Save(record); err != nil {
+				return fmt.Errorf("移动用例失败 (caseId=%s): %w", record.Id, err)
+			}
+			h.recordApiTestConfigAudit(txApp, apiTestAuditUserId(e.Auth), apiTestAuditActionUpdate, original, record)
+			response.Moved++
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errApiTestMoveConflict):
+		return e.JSON(http.StatusConflict, response)
+	case len(missing) > 0:
+		return respondError(e, http.StatusNotFound, formatApiTestError("用例不存在", errors.New("部分用例不存在"), map[string]any{"caseIds": missing}).Error())
+	case err != nil:
+		h.logApiTestError("批量移动用例失败", err, "collectionId", collectionId)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("批量移动用例失败", err, map[string]any{"collectionId": collectionId}).Error())
+	}
+	return e.JSON(http.StatusOK, response)
+}
+
+// reorderApiTests 按给定顺序在一个事务内重写 sort_order（从 0 开始）。
+// ids 必须恰好覆盖范围内的全部记录（全部合集，或指定合集内的全部用例），否则返回 400 与差异列表。
+func (h *Hub) reorderApiTests(e *core.RequestEvent) error {
+	var payload apiTestReorderRequest
+	if err := apiTestParseBody(e, &payload); err != nil {
+		h.logApiTestError("解析排序请求失败", err)
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析排序请求失败", err, nil).Error())
+	}
+	collectionId := strings.TrimSpace(payload.CollectionId)
+	ids := make([]string, 0, len(payload.Ids))
+	positions := make(map[string]int, len(payload.Ids))
+	for _, id := range payload.Ids {
+		trimmed := strings.TrimSpace(id)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := positions[trimmed]; ok {
+			return respondError(e, http.StatusBadRequest, formatApiTestError("ids 存在重复", errors.New("重复的 id"), map[string]any{"id": trimmed}).Error())
+		}
+		positions[trimmed] = len(ids)
+		ids = append(ids, trimmed)
+	}
+	if len(ids) == 0 {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("ids 不能为空", errors.New("ids 缺失"), nil).Error())
+	}
+	collectionName := apiTestCollectionsCollection
+	filter := ""
+	params := dbx.Params{}
+	if collectionId != "" {
+		if _, err := h.FindRecordById(apiTestCollectionsCollection, collectionId); err != nil {
+			return respondError(e, http.StatusNotFound, formatApiTestError("合集不存在", err, map[string]any{"collectionId": collectionId}).Error())
+		}
+		collectionName = apiTestCasesCollection
+		filter = "collection = {:collection}"
+		params["collection"] = collectionId
+	}
+
+	response := apiTestReorderResponse{}
+	var missing, unknown []string
+	err := h.RunInTransaction(func(txApp core.App) error {
+		records, err := txApp.FindRecordsByFilter(collectionName, filter, "", -1, 0, params)
+		if err != nil {
+			return err
+		}
+		inScope := make(map[string]struct{}, len(records))
+		for _, record := range records {
+			inScope[record.Id] = struct{}{}
+			if _, ok := positions[record.Id]; !ok {
+				missing = append(missing, record.Id)
+			}
+		}
+		for _, id := range ids {
+			if _, ok := inScope[id]; !ok {
+				unknown = append(unknown, id)
+			}
+		}
+		if len(missing) > 0 || len(unknown) > 0 {
+			return errApiTestReorderScope
+		}
+		for _, record := range records {
+			position := positions[record.Id]
+			if record.GetInt("sort_order") == position {
+				continue
+			}
+			record.Set("sort_order", position)
+			if err := txApp.Save(record); err != nil {
+				return fmt.Errorf("更新排序失败 (id=%s): %w", record.Id, err)
+			}
+			response.Updated++
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errApiTestReorderScope):
+		return respondError(e, http.StatusBadRequest, formatApiTestError("排序列表无效", err, map[string]any{"collectionId": collectionId, "missing": missing, "unknown": unknown}).Error())
+	case err != nil:
+		h.logApiTestError("更新排序失败", err, "collectionId", collectionId)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("更新排序失败", err, map[string]any{"collectionId": collectionId}).Error())
+	}
+	return e.JSON(http.StatusOK, response)
+}
+
+// apiTestRedactedValue 为脱敏后展示的占位值。
+const apiTestRedactedValue = "******"
+
+// apiTestSensitiveHeaders 为无需显式标记即默认脱敏的请求头（小写）。
+var apiTestSensitiveHeaders = map[string]struct{}{
+	"authorization":       {},
+	"proxy-authorization": {},
+	"cookie":              {},
+	"x-api-key":           {},
+	"x-auth-token":        {},
+}
+
+// apiTestSecretKeys 返回 headers/params 中被标记为 secret 的键（headers 按小写比较）。
+func apiTestSecretKeys(record *core.Record, field string) (map[string]struct{}, error) {
+	var items []apiTestKeyValue
+	if err := record.UnmarshalJSONField(field, &items); err != nil {
+		return nil, err
+	}
+	result := make(map[string]struct{})
+	for _, item := range items {
+		key := strings.TrimSpace(item.Key)
+		if !item.Secret || key == "" {
+			continue
+		}
+		if field == "headers" {
+			key = strings.ToLower(key)
+		}
+		result[key] = struct{}{}
+	}
+	return result, nil
+}
+
+// apiTestFormSecretKeys 返回表单请求体中标记为 secret 的字段名，仅支持列表形式的表单项。
+func apiTestFormSecretKeys(body string) map[string]struct{} {
+	result := make(map[string]struct{})
+	var items []map[string]any
+	if err := json.Unmarshal([]byte(body), &items); err != nil {
+		return result
+	}
+	for _, item := range items {
+		if secret, _ := item["secret"].(bool); !secret {
+			continue
+		}
+		if key := strings.TrimSpace(fmt.Sprintf("%v", item["key"])); key != "" {
+			result[key] = struct{}{}
+		}
+	}
+	return result
+}
+
+// buildApiTestPreview 按执行流程组装请求并脱敏，返回的错误已带有所在阶段的说明。
+// 请求预览与生效配置共用该流程。
+func (h *Hub) buildApiTestPreview(caseRecord *core.Record, collectionRecord *core.Record) (apiTestPreviewResponse, error) {
+	request, err := h.buildApiTestRequest(caseRecord, collectionRecord)
+	if err != nil {
+		return apiTestPreviewResponse{}, err
+	}
+	secretHeaders, err := apiTestSecretKeys(caseRecord, "headers")
+	if err != nil {
+		return apiTestPreviewResponse{}, fmt.Errorf("解析请求头失败: %v", err)
+	}
+	secretParams, err := apiTestSecretKeys(caseRecord, "params")
+	if err != nil {
+		return apiTestPreviewResponse{}, fmt.Errorf("解析查询参数失败: %v", err)
+	}
+
+	response := apiTestPreviewResponse{
+		Method:  request.Method,
+		Headers: make(map[string]string, len(request.Header)),
+		Params:  make(map[string]string),
+	}
+	for key := range request.Header {
+		value := request.Header.Get(key)
+		lower := strings.ToLower(key)
+		_, marked := secretHeaders[lower]
+		_, sensitive := apiTestSensitiveHeaders[lower]
+		if marked || sensitive {
+			value = apiTestRedactedValue
+		}
+		response.Headers[key] = value
+	}
+	query := request.URL.Query()
+	for key := range query {
+		if _, ok := secretParams[key]; ok {
+			for index := range query[key] {
+				query[key][index] = apiTestRedactedValue
+			}
+		}
+		response.Params[key] = strings.Join(query[key], ",")
+	}
+	previewURL := *request.URL
+	previewURL.RawQuery = query.Encode()
+	response.URL = previewURL.String()
+	if request.Body != nil {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return apiTestPreviewResponse{}, fmt.Errorf("读取请求体失败: %v", err)
+		}
+		response.Body = string(body)
+		response.BodyBytes = len(body)
+		if strings.ToLower(caseRecord.GetString("body_type")) == "form" {
+			if secretFields := apiTestFormSecretKeys(caseRecord.GetString("body")); len(secretFields) > 0 {
+				if values, err := url.ParseQuery(response.Body); err == nil {
+					for key := range secretFields {
+						if _, ok := values[key]; ok {
+							values.Set(key, apiTestRedactedValue)
+						}
+					}
+					response.Body = values.Encode()
+				}
+			}
+		}
+	}
+	apiTestRedactVariableValues(&response, apiTestSecretVariableValues(collectionRecord))
+	if err := h.validateApiTestTarget(apiTestValidationURL(request.URL, caseRecord.GetString("resolve_ip"))); err != nil {
+		response.TargetError = fmt.Sprintf("请求地址校验失败: %v", err)
+	}
+	return response, nil
+}
+
+// buildApiTestDraftCase 将用例草稿转换为未保存的用例记录，并读取所属合集。返回的错误可直接作为 400 响应。
+func (h *Hub) buildApiTestDraftCase(draft apiTestDraftCase) (*core.Record, *core.Record, error) {
+	collectionId := strings.TrimSpace(draft.Collection)
+	if collectionId == "" {
+		return nil, nil, formatApiTestError("case.collection 不能为空", errors.New("collection 缺失"), nil)
+	}
+	collectionRecord, err := h.FindRecordById(apiTestCollectionsCollection, collectionId)
+	if err != nil {
+		return nil, nil, formatApiTestError("合集不存在", err, map[string]any{"collectionId": collectionId})
+	}
+	casesCollection, err := h.FindCollectionByNameOrId(apiTestCasesCollection)
+	if err != nil {
+		return nil, nil, formatApiTestError("读取用例集合失败", err, nil)
+	}
+	bodyType := strings.TrimSpace(draft.BodyType)
+	if bodyType == "" {
+		bodyType = "json"
+	}
+	if !apiTestIsValidBodyType(bodyType) {
+		return nil, nil, formatApiTestError("case.body_type 无效", fmt.Errorf("不支持的请求体类型: %s", bodyType), nil)
+	}
+	if err := apiTestValidateResolveIP(draft.ResolveIP); err != nil {
+		return nil, nil, formatApiTestError("case.resolve_ip 无效", err, nil)
+	}
+	if field, err := apiTestValidateForwardedHeaders(draft.ForwardedFor, draft.ForwardedProto, draft.RealIP); err != nil {
+		return nil, nil, formatApiTestError("case."+field+" 无效", err, nil)
+	}
+	record := core.NewRecord(casesCollection)
+	record.Set("collection", collectionRecord.Id)
+	record.Set("method", draft.Method)
+	record.Set("url", draft.URL)
+	record.Set("headers", apiTestNormalizeKeyValues(draft.Headers))
+	record.Set("params", apiTestNormalizeKeyValues(draft.Params))
+	record.Set("body_type", bodyType)
+	record.Set("body", draft.Body)
+	record.Set("resolve_ip", strings.TrimSpace(draft.ResolveIP))
+	record.Set("forwarded_for", strings.TrimSpace(draft.ForwardedFor))
+	record.Set("forwarded_proto", strings.TrimSpace(draft.ForwardedProto))
+	record.Set("real_ip", strings.TrimSpace(draft.RealIP))
+	record.Set("auto_content_length", draft.AutoContentLength)
+	return record, collectionRecord, nil
+}
+
+// findApiTestCaseWithCollection 读取用例及其所属合集，返回的错误可直接作为 404 响应。
+func (h *Hub) findApiTestCaseWithCollection(caseId string) (*core.Record, *core.Record, error) {
+	caseRecord, err := h.FindRecordById(apiTestCasesCollection, caseId)
+	if err != nil {
+		return nil, nil, formatApiTestError("用例不存在", err, map[string]any{"caseId": caseId})
+	}
+	collectionRecord, err := h.FindRecordById(apiTestCollectionsCollection, caseRecord.GetString("collection"))
+	if err != nil {
+		return nil, nil, formatApiTestError("合集不存在", err, map[string]any{"caseId": caseId})
+	}
+	return caseRecord, collectionRecord, nil
+}
+
+// previewApiTestCase 按执行流程组装请求但不发送，返回脱敏后的最终请求，便于排查地址拼接与请求头等问题。
+// 目标地址校验（SSRF 拦截）的结果在 targetError 中返回；预览不发送请求，也不写入执行记录。
+func (h *Hub) previewApiTestCase(e *core.RequestEvent) error {
+	var payload apiTestPreviewRequest
+	if err := apiTestParseBody(e, &payload); err != nil {
+		h.logApiTestError("解析预览用例请求失败", err)
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析预览用例请求失败", err, nil).Error())
+	}
+	if payload.Case != nil {
+		caseRecord, collectionRecord, err := h.buildApiTestDraftCase(*payload.Case)
+		if err != nil {
+			return respondError(e, http.StatusBadRequest, err.Error())
+		}
+		response, err := h.buildApiTestPreview(caseRecord, collectionRecord)
+		if err != nil {
+			return respondError(e, http.StatusBadRequest, formatApiTestError("组装请求失败", err, map[string]any{"collectionId": collectionRecord.Id}).Error())
+		}
+		return e.JSON(http.StatusOK, response)
+	}
+	caseId := strings.TrimSpace(payload.CaseId)
+	if caseId == "" {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("caseId 不能为空", errors.New("caseId 缺失"), nil).Error())
+	}
+	caseRecord, collectionRecord, err := h.findApiTestCaseWithCollection(caseId)
+	if err != nil {
+		return respondError(e, http.StatusNotFound, err.Error())
+	}
+	response, err := h.buildApiTestPreview(caseRecord, collectionRecord)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("组装请求失败", err, map[string]any{"caseId": caseId}).Error())
+	}
+	return e.JSON(http.StatusOK, response)
+}
+
+// getApiTestEffectiveConfig 返回用例在继承合集配置与默认值后的生效配置（请求、超时、断言、定时与告警），
+// 敏感值已脱敏。仅用于展示，复用执行时的组装逻辑。
+func (h *Hub) getApiTestEffectiveConfig(e *core.RequestEvent) error {
+	caseId := strings.TrimSpace(e.Request.URL.Query().Get("caseId"))
+	if caseId == "" {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("caseId 不能为空", errors.New("caseId 缺失"), nil).Error())
+	}
+	caseRecord, collectionRecord, err := h.findApiTestCaseWithCollection(caseId)
+	if err != nil {
+		return respondError(e, http.StatusNotFound, err.Error())
+	}
+	scheduleConfig, err := h.getOrCreateApiTestScheduleConfig()
+	if err != nil {
+		h.logApiTestError("读取接口定时配置失败", err)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取接口定时配置失败", err, nil).Error())
+	}
+	// 保存时已校验格式，读取失败按未配置处理
+	tlsCiphers, _ := apiTestRecordStringList(caseRecord, "tls_ciphers")
+	expectedBodyIgnore, _ := apiTestRecordStringList(caseRecord, "expected_body_ignore")
+	statusBranches, _ := apiTestRecordStatusBranches(caseRecord)
+	expectedCookies, _ := apiTestRecordCookieAssertions(caseRecord)
+	fingerprintPaths, _ := apiTestRecordStringList(caseRecord, "fingerprint_paths")
+	templates, err := h.loadApiTestAssertionTemplates(caseRecord)
+	if err != nil {
+		h.logApiTestError("读取断言模板失败", err, "caseId", caseId)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取断言模板失败", err, map[string]any{"caseId": caseId}).Error())
+	}
+	limits := apiTestMergeAssertionLimits(caseRecord, templates)
+
+	response := apiTestEffectiveConfigResponse{
+		CaseId:          caseRecord.Id,
+		CollectionId:    collectionRecord.Id,
+		Name:            caseRecord.GetString("name"),
+		ProbeType:       apiTestProbeTypeHTTP,
+		HTTPProtocol:    strings.TrimSpace(caseRecord.GetString("http_protocol")),
+		FollowRedirects: caseRecord.GetBool("follow_redirects") && strings.TrimSpace(caseRecord.GetString("expected_location")) == "",
+		TimeoutMs:       caseRecord.GetInt("timeout_ms"),
+		ResolveIP:       strings.TrimSpace(caseRecord.GetString("resolve_ip")),
+		ProxyURL:        apiTestRedactedProxyURL(collectionRecord.GetString("proxy_url"), collectionRecord.GetString("proxy_password") != ""),
+		SnippetBytes:    apiTestSnippetLimit(caseRecord, collectionRecord),
+		RetryCount:      caseRecord.GetInt("retry_count"),
+		RetryDelayMs:    caseRecord.GetInt("retry_delay_ms"),
+		TotalTimeoutMs:  caseRecord.GetInt("total_timeout_ms"),
+		Assertions: apiTestEffectiveAssertions{
+			ExpectedStatus:     caseRecord.GetInt("expected_status"),
+			MonotonicPath:      strings.TrimSpace(caseRecord.GetString("monotonic_path")),
+			MinResponseBytes:   limits.MinResponseBytes,
+			MaxResponseBytes:   limits.MaxResponseBytes,
+			NotContains:        caseRecord.GetString("not_contains"),
+			NotContainsRegex:   caseRecord.GetBool("not_contains_regex"),
+			BodyContains:       caseRecord.GetString("expected_body_contains"),
+			JSONPath:           strings.TrimSpace(caseRecord.GetString("expected_json_path")),
+			JSONValue:          caseRecord.GetString("expected_json_value"),
+			TLSMinVersion:      caseRecord.GetString("tls_min_version"),
+			TLSCiphers:         tlsCiphers,
+			ExpectedBody:       caseRecord.GetString("expected_body"),
+			ExpectedBodyMode:   caseRecord.GetString("expected_body_mode"),
+			ExpectedBodyIgnore: expectedBodyIgnore,
+			StatusBranches:     statusBranches,
+			ExpectedCookies:    expectedCookies,
+			FingerprintMode:    caseRecord.GetString("fingerprint_mode"),
+			FingerprintPaths:   fingerprintPaths,
+			ExpectedLocation:   strings.TrimSpace(caseRecord.GetString("expected_location")),
+			LocationRegex:      caseRecord.GetBool("expected_location_regex"),
+			MaxLatencyMs:       limits.MaxLatencyMs,
+			Templates:          templates,
+		},
+		Schedule: apiTestEffectiveSchedule{
+			GlobalEnabled: scheduleConfig.GetBool("enabled"),
+			Enabled:       caseRecord.GetBool("schedule_enabled"),
+		},
+		Alert: apiTestEffectiveAlert{
+			Enabled:   scheduleConfig.GetBool("alert_enabled"),
+			OnRecover: scheduleConfig.GetBool("alert_on_recover"),
+			Threshold: caseRecord.GetInt("alert_threshold"),
+		},
+	}
+	if response.Alert.Threshold <= 0 {
+		response.Alert.Threshold = apiTestDefaultAlertThreshold
+	}
+	response.Alert.Mode = apiTestAlertModeConsecutive
+	if rule := apiTestRecordSuccessRateRule(caseRecord); rule != nil {
+		response.Alert.Mode = apiTestAlertModeSuccessRate
+		response.Alert.SuccessRate = rule.Threshold
+		response.Alert.WindowMinutes = rule.WindowMinutes
+	}
+	// 与 apiTestScheduleDue 的继承顺序一致：用例 cron > 合集 cron > 用例间隔 > 全局间隔
+	if cronExpr := strings.TrimSpace(caseRecord.GetString("schedule_cron")); cronExpr != "" {
+		response.Schedule.Cron, response.Schedule.Source = cronExpr, "case"
+	} else if cronExpr := strings.TrimSpace(collectionRecord.GetString("schedule_cron")); cronExpr != "" {
+		response.Schedule.Cron, response.Schedule.Source = cronExpr, "collection"
+	} else if minutes := caseRecord.GetInt("schedule_minutes"); minutes > 0 {
+		response.Schedule.IntervalMinutes, response.Schedule.Source = minutes, "case"
+	} else {
+		response.Schedule.IntervalMinutes, response.Schedule.Source = scheduleConfig.GetInt("interval_minutes"), "global"
+		if response.Schedule.IntervalMinutes <= 0 {
+			response.Schedule.IntervalMinutes = apiTestDefaultIntervalMinutes
+		}
+	}
+	// TCP 探测不构造 HTTP 请求
+	if apiTestIsTCPProbe(caseRecord.GetString("probe_type")) {
+		response.ProbeType = apiTestProbeTypeTCP
+		response.FollowRedirects = false
+		// 断言模板只作用于 HTTP 用例
+		response.Assertions.MaxLatencyMs = caseRecord.GetInt("max_latency_ms")
+		response.Assertions.MinResponseBytes = caseRecord.GetInt("min_response_bytes")
+		response.Assertions.MaxResponseBytes = caseRecord.GetInt("max_response_bytes")
+		response.Assertions.Templates = nil
+		return e.JSON(http.StatusOK, response)
+	}
+	request, err := h.buildApiTestPreview(caseRecord, collectionRecord)
+	if err != nil {
+		response.RequestError = err.Error()
+	} else {
+		response.Request = &request
+	}
+	return e.JSON(http.StatusOK, response)
+}
+
+func (h *Hub) runApiTestCase(e *core.RequestEvent) error {
+	var payload apiTestRunCaseRequest
+	if err := apiTestParseBody(e, &payload); err != nil {
+		h.logApiTestError("解析执行用例请求失败", err)
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析执行用例请求失败", err, nil).Error())
+	}
+	caseId := strings.TrimSpace(payload.CaseId)
+	if caseId == "" {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("caseId 不能为空", errors.New("caseId 缺失"), nil).Error())
+	}
+	caseRecord, err := h.FindRecordById(apiTestCasesCollection, caseId)
+	if err != nil {
+		return respondError(e, http.StatusNotFound, formatApiTestError("用例不存在", err, map[string]any{"caseId": caseId}).Error())
+	}
+	lockKey := caseRecord.GetString("collection")
+	if busy, ok := apiTestAcquireRunLock(lockKey); !ok {
+		return respondApiTestRunBusy(e, busy)
+	}
+	defer apiTestReleaseRunLock(lockKey)
+	result, err := h.executeApiTestCaseById(context.Background(), caseId, apiTestRunSourceManual, nil)
+	if err != nil {
+		h.logApiTestError("执行接口用例失败", err, "caseId", caseId)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("执行接口用例失败", err, map[string]any{"caseId": caseId}).Error())
+	}
+	return e.JSON(http.StatusOK, result)
+}
+
+// acceptApiTestFingerprint 将用例最近一次执行记录的响应指纹确认为新基线，用于响应结构的预期变更。
+func (h *Hub) acceptApiTestFingerprint(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload apiTestRunCaseRequest
+	if err := apiTestParseBody(e, &payload); err != nil {
+		h.logApiTestError("解析确认指纹请求失败", err)
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析确认指纹请求失败", err, nil).Error())
+	}
+	caseId := strings.TrimSpace(payload.CaseId)
+	if caseId == "" {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("caseId 不能为空", errors.New("caseId 缺失"), nil).Error())
+	}
+	caseRecord, err := h.FindRecordById(apiTestCasesCollection, caseId)
+	if err != nil {
+		return respondError(e, http.StatusNotFound, formatApiTestError("用例不存在", err, map[string]any{"caseId": caseId}).Error())
+	}
+	runs, err := h.FindRecordsByFilter(apiTestRunsCollection, "case = {:case} && fingerprint != ''", "-created", 1, 0, dbx.Params{"case": caseId})
+	if err != nil {
+		h.logApiTestError("读取执行记录失败", err, "caseId", caseId)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取执行记录失败", err, map[string]any{"caseId": caseId}).Error())
+	}
+	if len(runs) == 0 {
+		return respondError(e, http.StatusNotFound, formatApiTestError("没有可确认的响应指纹", errors.New("用例尚无带指纹的执行记录"), map[string]any{"caseId": caseId}).Error())
+	}
+	fingerprint := runs[0].GetString("fingerprint")
+	caseRecord.Set("last_fingerprint", fingerprint)
+	if err := h.Save(caseRecord); err != nil {
+		h.logApiTestError("保存响应指纹失败", err, "caseId", caseId)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("保存响应指纹失败", err, map[string]any{"caseId": caseId}).Error())
+	}
+	return e.JSON(http.StatusOK, map[string]any{"caseId": caseId, "fingerprint": fingerprint})
 }
 
-func (h *Hub) runApiTestCase(e *core.RequestEvent) error {
-	var payload apiTestRunCaseRequest
+// runApiTestCanary 连续执行同一用例 iterations 次并汇总成功率与耗时分布，用于判断用例是否不稳定。
+// 金丝雀执行不写入执行记录，也不影响连续失败计数与告警状态。
+func (h *Hub) runApiTestCanary(e *core.RequestEvent) error {
+	var payload apiTestCanaryRequest
 	if err := apiTestParseBody(e, &payload); err != nil {
-		h.logApiTestError("解析执行用例请求失败", err)
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": formatApiTestError("解析执行用例请求失败", err, nil).Error()})
+		h.logApiTestError("解析金丝雀执行请求失败", err)
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析金丝雀执行请求失败", err, nil).Error())
 	}
 	caseId := strings.TrimSpace(payload.CaseId)
 	if caseId == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": formatApiTestError("caseId 不能为空", errors.New("caseId 缺失"), nil).Error()})
+		return respondError(e, http.StatusBadRequest, formatApiTestError("caseId 不能为空", errors.New("caseId 缺失"), nil).Error())
 	}
-	if !apiTestAcquireRunLock() {
-		return e.JSON(http.StatusConflict, map[string]string{"error": formatApiTestError("接口测试执行中", errors.New("已有任务在执行"), nil).Error()})
+	if payload.Iterations <= 0 || payload.Iterations > apiTestMaxCanaryIterations {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("执行次数无效", fmt.Errorf("iterations 必须在 1 到 %d 之间", apiTestMaxCanaryIterations), nil).Error())
 	}
-	defer apiTestReleaseRunLock()
-	result, err := h.executeApiTestCaseById(caseId, apiTestRunSourceManual, nil)
+	caseRecord, collectionRecord, err := h.findApiTestCaseWithCollection(caseId)
 	if err != nil {
-		h.logApiTestError("执行接口用例失败", err, "caseId", caseId)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("执行接口用例失败", err, map[string]any{"caseId": caseId}).Error()})
+		return respondError(e, http.StatusNotFound, err.Error())
 	}
-	return e.JSON(http.StatusOK, result)
+	if busy, ok := apiTestAcquireRunLock(collectionRecord.Id); !ok {
+		return respondApiTestRunBusy(e, busy)
+	}
+	defer apiTestReleaseRunLock(collectionRecord.Id)
+
+	response := apiTestCanaryResponse{
+		CaseId:      caseRecord.Id,
+		Name:        caseRecord.GetString("name"),
+		Iterations:  payload.Iterations,
+		DurationsMs: make([]int, 0, payload.Iterations),
+		Errors:      []string{},
+	}
+	job, ctx := h.jobs.start("", runningJobTypeApiTest, "canary "+caseRecord.GetString("name"), "", true)
+	defer h.jobs.finish(job)
+	// 登记到合集执行锁，中止合集执行时一并中止金丝雀执行
+	apiTestBindRunLock(collectionRecord.Id, job)
+	seenErrors := map[string]struct{}{}
+	totalMs := 0
+	for i := 0; i < payload.Iterations; i++ {
+		if ctx.Err() != nil {
+			response.Cancelled = true
+			break
+		}
+		job.setProgress(i, payload.Iterations)
+		result := h.performApiTestCaseContext(ctx, caseRecord, collectionRecord)
+		// 被中止的请求不计入统计
+		if ctx.Err() != nil {
+			response.Cancelled = true
+			break
+		}
+		response.DurationsMs = append(response.DurationsMs, result.DurationMs)
+		totalMs += result.DurationMs
+		if i == 0 || result.DurationMs < response.MinDurationMs {
+			response.MinDurationMs = result.DurationMs
+		}
+		if result.DurationMs > response.MaxDurationMs {
+			response.MaxDurationMs = result.DurationMs
+		}
+		if result.Success {
+			response.Success++
+			continue
+		}
+		response.Failed++
+		// 仅保留去重后的错误信息，避免同一错误重复返回
+		if _, ok := seenErrors[result.Error]; !ok {
+			seenErrors[result.Error] = struct{}{}
+			response.Errors = append(response.Errors, result.Error)
+		}
+	}
+	// 按实际完成的次数汇总，中止时 iterations 大于完成次数
+	if completed := len(response.DurationsMs); completed > 0 {
+		response.SuccessRate = float64(response.Success) / float64(completed)
+		response.AvgDurationMs = float64(totalMs) / float64(completed)
+	}
+	return e.JSON(http.StatusOK, response)
 }
 
 func (h *Hub) runApiTestCollection(e *core.RequestEvent) error {
 	var payload apiTestRunCollectionRequest
 	if err := apiTestParseBody(e, &payload); err != nil {
 		h.logApiTestError("解析执行合集请求失败", err)
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": formatApiTestError("解析执行合集请求失败", err, nil).Error()})
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析执行合集请求失败", err, nil).Error())
 	}
 	collectionId := strings.TrimSpace(payload.CollectionId)
 	if collectionId == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": formatApiTestError("collectionId 不能为空", errors.New("collectionId 缺失"), nil).Error()})
+		return respondError(e, http.StatusBadRequest, formatApiTestError("collectionId 不能为空", errors.New("collectionId 缺失"), nil).Error())
 	}
-	if !apiTestAcquireRunLock() {
-		return e.JSON(http.StatusConflict, map[string]string{"error": formatApiTestError("接口测试执行中", errors.New("已有任务在执行"), nil).Error()})
+	if busy, ok := apiTestAcquireRunLock(collectionId); !ok {
+		return respondApiTestRunBusy(e, busy)
 	}
-	defer apiTestReleaseRunLock()
-	summary, err := h.executeApiTestCollection(collectionId, apiTestRunSourceManual)
+	defer apiTestReleaseRunLock(collectionId)
+	job, ctx := h.jobs.start("", runningJobTypeApiTest, "collection "+collectionId, "", true)
+	defer h.jobs.finish(job)
+	apiTestBindRunLock(collectionId, job)
+	summary, err := h.executeApiTestCollection(ctx, job, collectionId, apiTestRunSourceManual)
 	if err != nil {
 		h.logApiTestError("执行接口合集失败", err, "collectionId", collectionId)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("执行接口合集失败", err, map[string]any{"collectionId": collectionId}).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("执行接口合集失败", err, map[string]any{"collectionId": collectionId}).Error())
 	}
 	return e.JSON(http.StatusOK, summary)
 }
 
 func (h *Hub) runAllApiTests(e *core.RequestEvent) error {
-	if !apiTestAcquireRunLock() {
-		return e.JSON(http.StatusConflict, map[string]string{"error": formatApiTestError("接口测试执行中", errors.New("已有任务在执行"), nil).Error()})
+	// 请求体可省略，此时按默认顺序依次执行
+	var options apiTestRunAllOptions
+	if err := apiTestParseBody(e, &options); err != nil && !errors.Is(err, io.EOF) {
+		h.logApiTestError("解析执行全部请求失败", err)
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析执行全部请求失败", err, nil).Error())
+	}
+	options, err := options.normalize()
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("执行选项无效", err, nil).Error())
+	}
+	if busy, ok := apiTestAcquireRunLock(apiTestRunLockAll); !ok {
+		return respondApiTestRunBusy(e, busy)
 	}
-	defer apiTestReleaseRunLock()
-	summary, err := h.executeApiTestAll(apiTestRunSourceManual)
+	defer apiTestReleaseRunLock(apiTestRunLockAll)
+	job, ctx := h.jobs.start("", runningJobTypeApiTest, "all cases", "", true)
+	defer h.jobs.finish(job)
+	apiTestBindRunLock(apiTestRunLockAll, job)
+	summary, err := h.executeApiTestAll(ctx, job, apiTestRunSourceManual, options)
 	if err != nil {
 		h.logApiTestError("执行全部接口用例失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("执行全部接口用例失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("执行全部接口用例失败", err, nil).Error())
 	}
 	return e.JSON(http.StatusOK, summary)
 }
 
 func (h *Hub) listApiTestRuns(e *core.RequestEvent) error {
 	query := e.Request.URL.Query()
-	caseId := strings.TrimSpace(query.Get("case"))
-	collectionId := strings.TrimSpace(query.Get("collection"))
+	scope := apiTestParseRunScope(query)
 	page := apiTestParseInt(query.Get("page"), 1)
 	perPage := apiTestParseInt(query.Get("perPage"), 50)
 	if perPage <= 0 {
@@ -1009,21 +3516,14 @@ func (h *Hub) listApiTestRuns(e *core.RequestEvent) error {
 	if perPage > apiTestMaxPerPage {
 		perPage = apiTestMaxPerPage
 	}
-	filterParts := []string{}
-	countFilterParts := []string{}
-	params := dbx.Params{}
-	if caseId != "" {
-		filterParts = append(filterParts, "case = {:case}")
-		countFilterParts = append(countFilterParts, "`case` = {:case}")
-		params["case"] = caseId
-	}
-	if collectionId != "" {
-		filterParts = append(filterParts, "collection = {:collection}")
-		countFilterParts = append(countFilterParts, "collection = {:collection}")
-		params["collection"] = collectionId
+	timeRange, err := apiTestParseRunTimeRange(query)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("时间范围无效", err, nil).Error())
 	}
-	filter := strings.Join(filterParts, " && ")
-	countFilter := strings.Join(countFilterParts, " AND ")
+	filter, params := scope.filter("case", "collection")
+	filter = timeRange.appendTo(filter, params, "created", " && ")
+	countFilter, _ := scope.sqlWhere("`case`", "collection")
+	countFilter = timeRange.appendTo(countFilter, dbx.Params{}, "created", " AND ")
 	var exp dbx.Expression
 	if countFilter != "" {
 		exp = dbx.NewExp(countFilter, params)
@@ -1031,7 +3531,7 @@ func (h *Hub) listApiTestRuns(e *core.RequestEvent) error {
 	totalItems64, err := h.CountRecords(apiTestRunsCollection, exp)
 	if err != nil {
 		h.logApiTestError("统计接口执行记录失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("统计接口执行记录失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("统计接口执行记录失败", err, nil).Error())
 	}
 	totalItems := int(totalItems64)
 	totalPages := totalItems / perPage
@@ -1048,7 +3548,7 @@ func (h *Hub) listApiTestRuns(e *core.RequestEvent) error {
 	records, err := h.FindRecordsByFilter(apiTestRunsCollection, filter, "-created", perPage, offset, params)
 	if err != nil {
 		h.logApiTestError("读取接口执行记录失败", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": formatApiTestError("读取接口执行记录失败", err, nil).Error()})
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取接口执行记录失败", err, nil).Error())
 	}
 	items := make([]apiTestRunItem, 0, len(records))
 	for _, record := range records {
@@ -1063,244 +3563,1009 @@ func (h *Hub) listApiTestRuns(e *core.RequestEvent) error {
 			ResponseSnippet: record.GetString("response_snippet"),
 			Source:          record.GetString("source"),
 			Created:         apiTestDateTimeString(record.GetDateTime("created")),
+			ExtractedValue:  apiTestRecordExtractedValue(record),
+			ResponseBytes:   int64(record.GetInt("response_bytes")),
+			Fingerprint:     record.GetString("fingerprint"),
+			Protocol:        record.GetString("protocol"),
+			ResponseHeaders: apiTestRecordResponseHeaders(record),
+			TLSExpiresAt:    apiTestDateTimeString(record.GetDateTime("tls_expires_at")),
+			FinalURL:        record.GetString("final_url"),
+		})
+	}
+	return e.JSON(http.StatusOK, apiTestRunsResponse{
+		Items:      items,
+		Page:       page,
+		PerPage:    perPage,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	})
+}
+
+// apiTestRunScope 为执行记录查询的用例/合集过滤条件，执行记录列表与统计接口共用。
+type apiTestRunScope struct {
+	CaseId       string
+	CollectionId string
+}
+
+func apiTestParseRunScope(query url.Values) apiTestRunScope {
+	return apiTestRunScope{
+		CaseId:       strings.TrimSpace(query.Get("case")),
+		CollectionId: strings.TrimSpace(query.Get("collection")),
+	}
+}
+
+// filter 返回 PocketBase 过滤表达式，caseField/collectionField 为对应的字段（可为关联路径）。
+func (s apiTestRunScope) filter(caseField string, collectionField string) (string, dbx.Params) {
+	return s.build(caseField, collectionField, " && ")
+}
+
+// sqlWhere 返回原生 SQL 条件，caseColumn/collectionColumn 需已按需加引号或表别名。
+func (s apiTestRunScope) sqlWhere(caseColumn string, collectionColumn string) (string, dbx.Params) {
+	return s.build(caseColumn, collectionColumn, " AND ")
+}
+
+func (s apiTestRunScope) build(caseField string, collectionField string, separator string) (string, dbx.Params) {
+	parts := []string{}
+	params := dbx.Params{}
+	if s.CaseId != "" {
+		parts = append(parts, caseField+" = {:case}")
+		params["case"] = s.CaseId
+	}
+	if s.CollectionId != "" {
+		parts = append(parts, collectionField+" = {:collection}")
+		params["collection"] = s.CollectionId
+	}
+	return strings.Join(parts, separator), params
+}
+
+func apiTestParseInt(raw string, fallback int) int {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func (h *Hub) executeApiTestCaseById(ctx context.Context, caseId string, source apiTestRunSource, config *core.Record) (apiTestRunResult, error) {
+	caseRecord, err := h.FindRecordById(apiTestCasesCollection, caseId)
+	if err != nil {
+		return apiTestRunResult{}, err
+	}
+	collectionId := caseRecord.GetString("collection")
+	collectionRecord, err := h.FindRecordById(apiTestCollectionsCollection, collectionId)
+	if err != nil {
+		return apiTestRunResult{}, err
+	}
+	return h.executeApiTestCase(ctx, caseRecord, collectionRecord, source, config)
+}
+
+// buildApiTestRequest 按执行流程组装请求：方法校验、地址拼接、请求头/查询参数/请求体、合集变量替换与代理转发头。
+// 执行与预览共用该流程，返回的错误已带有所在阶段的说明，不做目标地址校验。
+func (h *Hub) buildApiTestRequest(caseRecord *core.Record, collectionRecord *core.Record) (*http.Request, error) {
+	method := strings.ToUpper(strings.TrimSpace(caseRecord.GetString("method")))
+	if method == "" {
+		return nil, errors.New("HTTP 方法不能为空")
+	}
+	if method != http.MethodGet && method != http.MethodPost && method != http.MethodPut && method != http.MethodDelete && method != http.MethodPatch && method != http.MethodHead {
+		return nil, fmt.Errorf("不支持的 HTTP 方法: %s", method)
+	}
+	resolver, err := newApiTestVariableResolver(collectionRecord)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := h.buildApiTestHeaders(caseRecord)
+	if err != nil {
+		return nil, fmt.Errorf("解析请求头失败: %v", err)
+	}
+	resolver.applyMap(headers)
+	params, err := h.buildApiTestParams(caseRecord)
+	if err != nil {
+		return nil, fmt.Errorf("解析查询参数失败: %v", err)
+	}
+	resolver.applyMap(params)
+	targetURL, err := h.resolveApiTestURL(collectionRecord, caseRecord)
+	if err != nil {
+		return nil, fmt.Errorf("构建请求地址失败: %v", err)
+	}
+	targetURL = resolver.apply(targetURL)
+	// 请求体解析失败时优先报告缺失的变量，占位符未替换的 JSON 往往同样无效
+	body, contentType, err := h.buildApiTestBody(caseRecord, resolver)
+	if missingErr := resolver.err(); missingErr != nil {
+		return nil, fmt.Errorf("替换合集变量失败: %v", missingErr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析请求体失败: %v", err)
+	}
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequest(method, targetURL, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	// 具名的代理转发头优先于同名的自定义请求头
+	for key, value := range apiTestForwardedHeaders(caseRecord) {
+		request.Header.Set(key, value)
+	}
+	if err := h.applyApiTestAuth(request, caseRecord); err != nil {
+		return nil, fmt.Errorf("设置认证信息失败: %v", err)
+	}
+	if contentType != "" && request.Header.Get("Content-Type") == "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	if err := apiTestApplyContentLength(request.Header, body, caseRecord.GetBool("auto_content_length")); err != nil {
+		return nil, err
+	}
+	if len(params) > 0 {
+		query := request.URL.Query()
+		for key, value := range params {
+			query.Add(key, value)
+		}
+		request.URL.RawQuery = query.Encode()
+	}
+	return request, nil
+}
+
+// executeApiTestCase 执行用例并写入执行记录；ctx 结束时中止请求并返回 errApiTestRunAborted，不写入执行记录。
+func (h *Hub) executeApiTestCase(ctx context.Context, caseRecord *core.Record, collectionRecord *core.Record, source apiTestRunSource, config *core.Record) (apiTestRunResult, error) {
+	result := h.performApiTestCaseWithRetry(ctx, caseRecord, collectionRecord)
+	if ctx.Err() != nil {
+		return apiTestRunResult{}, errApiTestRunAborted
+	}
+	return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+}
+
+// performApiTestCase 发送请求并评估断言，不写入执行记录；执行与金丝雀运行共用。
+func (h *Hub) performApiTestCase(caseRecord *core.Record, collectionRecord *core.Record) apiTestExecutionResult {
+	return h.performApiTestCaseContext(context.Background(), caseRecord, collectionRecord)
+}
+
+// performApiTestCaseContext 同 performApiTestCase，单次请求的超时 context 派生自 ctx，ctx 结束时请求随之中止。
+func (h *Hub) performApiTestCaseContext(ctx context.Context, caseRecord *core.Record, collectionRecord *core.Record) apiTestExecutionResult {
+	start := time.Now()
+	result := apiTestExecutionResult{
+		Status:          0,
+		DurationMs:      0,
+		Success:         false,
+		Error:           "",
+		ResponseSnippet: "",
+		RunAt:           apiTestNowDateTime(),
+		ResponseBytes:   -1,
+	}
+	if apiTestIsTCPProbe(caseRecord.GetString("probe_type")) {
+		return h.performApiTestTCPProbe(ctx, caseRecord)
+	}
+	expectedStatus := caseRecord.GetInt("expected_status")
+	if expectedStatus <= 0 {
+		result.Error = "期望状态码必须大于 0"
+		return result
+	}
+	timeoutMs := caseRecord.GetInt("timeout_ms")
+	if timeoutMs <= 0 {
+		result.Error = "超时时间必须大于 0"
+		return result
+	}
+	request, err := h.buildApiTestRequest(caseRecord, collectionRecord)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if err := h.validateApiTestTarget(apiTestValidationURL(request.URL, caseRecord.GetString("resolve_ip"))); err != nil {
+		result.Error = fmt.Sprintf("请求地址校验失败: %v", err)
+		return result
+	}
+	tlsPolicy, err := apiTestBuildTLSPolicy(caseRecord)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if tlsPolicy != nil && request.URL.Scheme != "https" {
+		result.Error = "TLS 断言失败: 请求未使用 HTTPS"
+		return result
+	}
+	// 每次执行读取模板的当前配置，模板修改即时生效
+	templates, err := h.loadApiTestAssertionTemplates(caseRecord)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	limits := apiTestMergeAssertionLimits(caseRecord, templates)
+	httpProtocol := strings.TrimSpace(caseRecord.GetString("http_protocol"))
+	proxy, err := h.apiTestProxyFor(collectionRecord.GetString("proxy_url"), collectionRecord.GetString("proxy_password"))
+	if err == nil && proxy != nil {
+		err = apiTestCheckProxyOptions(caseRecord.GetString("resolve_ip"), httpProtocol)
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("代理配置无效: %v", err)
+		return result
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+	defer cancel()
+	request = request.WithContext(attemptCtx)
+	client := apiTestHTTPClient(time.Duration(timeoutMs)*time.Millisecond, request.URL.Hostname(), caseRecord.GetString("resolve_ip"), tlsPolicy, proxy)
+	if err := apiTestApplyHTTPProtocol(client, httpProtocol); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	// 未开启 follow_redirects 时直接断言首个 3xx 响应；配置了 Location 断言时同样只执行单跳请求
+	expectedLocation := strings.TrimSpace(caseRecord.GetString("expected_location"))
+	if expectedLocation != "" || !caseRecord.GetBool("follow_redirects") {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	response, err := h.doApiTestRequestWithOAuth(client, request, caseRecord, collectionRecord)
+	if err != nil {
+		result.Error = fmt.Sprintf("请求执行失败: %v", err)
+		if tlsPolicy != nil && tlsPolicy.violation != "" {
+			result.Error = tlsPolicy.violation
+		}
+		result.DurationMs = int(time.Since(start).Milliseconds())
+		return result
+	}
+	defer response.Body.Close()
+	result.Status = response.StatusCode
+	result.Protocol = response.Proto
+	if request.URL.Scheme == "https" {
+		result.TLSExpiresAt = apiTestLeafCertExpiry(response.TLS)
+	}
+	result.ResponseHeaders = apiTestCaptureResponseHeaders(response.Header)
+	if response.Request != nil && response.Request.URL.String() != request.URL.String() {
+		result.FinalURL = response.Request.URL.Redacted()
+	}
+	// 配置了状态码分支时按实际状态码选择分支，替代 expected_status 判定
+	statusBranches, err := apiTestRecordStatusBranches(caseRecord)
+	if err != nil {
+		result.Error = err.Error()
+		result.DurationMs = int(time.Since(start).Milliseconds())
+		return result
+	}
+	branch := apiTestMatchStatusBranch(statusBranches, result.Status)
+	// 仅在需要对响应体做断言时读取更多内容，否则只读取摘要长度
+	monotonicPath := strings.TrimSpace(caseRecord.GetString("monotonic_path"))
+	snippetBytes := apiTestSnippetLimit(caseRecord, collectionRecord)
+	readLimit := snippetBytes + 1
+	notContains := caseRecord.GetString("not_contains")
+	expectedBody := caseRecord.GetString("expected_body")
+	fingerprintMode := caseRecord.GetString("fingerprint_mode")
+	bodyContains := caseRecord.GetString("expected_body_contains")
+	jsonPath := strings.TrimSpace(caseRecord.GetString("expected_json_path"))
+	if monotonicPath != "" || notContains != "" || strings.TrimSpace(expectedBody) != "" || fingerprintMode != "" || bodyContains != "" || jsonPath != "" || (branch != nil && branch.needsBody()) || apiTestAssertionTemplatesNeedBody(templates) {
+		readLimit = max(apiTestMaxAssertionBodyBytes, readLimit)
+	}
+	minResponseBytes := int64(limits.MinResponseBytes)
+	maxResponseBytes := int64(limits.MaxResponseBytes)
+	sizeAssertion := minResponseBytes > 0 || maxResponseBytes > 0 || (branch != nil && branch.needsSize())
+	payload, readErr := io.ReadAll(io.LimitReader(response.Body, readLimit))
+	if readErr != nil {
+		result.Error = fmt.Sprintf("读取响应失败: %v", readErr)
+		result.DurationMs = int(time.Since(start).Milliseconds())
+		return result
+	}
+	// 响应大小优先取已读完的响应体长度，其次取 Content-Length；配置了大小断言时读完剩余响应体计数
+	switch {
+	case int64(len(payload)) < readLimit:
+		result.ResponseBytes = int64(len(payload))
+	case response.ContentLength >= 0:
+		result.ResponseBytes = response.ContentLength
+	case sizeAssertion:
+		rest, drainErr := io.Copy(io.Discard, response.Body)
+		if drainErr != nil {
+			result.Error = fmt.Sprintf("读取响应失败: %v", drainErr)
+			result.DurationMs = int(time.Since(start).Milliseconds())
+			return result
+		}
+		result.ResponseBytes = int64(len(payload)) + rest
+	}
+	snippet := payload
+	if int64(len(snippet)) > snippetBytes+1 {
+		snippet = snippet[:snippetBytes+1]
+	}
+	result.ResponseSnippet = strings.TrimSpace(string(snippet))
+	if len(statusBranches) > 0 {
+		result.Success = branch != nil
+		if branch == nil {
+			result.Error = fmt.Sprintf("状态码 %d 不在任何断言分支中", result.Status)
+		} else if err := branch.check(payload, result.ResponseBytes); err != nil {
+			result.Success = false
+			result.Error = fmt.Sprintf("分支 %s: %v", branch.Status, err)
+		}
+	} else {
+		result.Success = result.Status == expectedStatus
+		if !result.Success {
+			if result.ResponseSnippet != "" {
+				result.Error = result.ResponseSnippet
+			} else {
+				result.Error = fmt.Sprintf("期望状态码 %d，实际 %d", expectedStatus, result.Status)
+			}
+		}
+	}
+	if result.Success && httpProtocol != "" {
+		if err := apiTestCheckHTTPProtocol(httpProtocol, response); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+	if result.Success {
+		expectedCookies, err := apiTestRecordCookieAssertions(caseRecord)
+		if err == nil && len(expectedCookies) > 0 {
+			err = apiTestCheckCookies(response, expectedCookies)
+		}
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+	if result.Success && expectedLocation != "" {
+		if err := apiTestCheckLocation(request.URL, response.Header.Get("Location"), expectedLocation, caseRecord.GetBool("expected_location_regex")); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+	if result.Success && monotonicPath != "" {
+		extracted, assertErr := h.evaluateApiTestMonotonic(caseRecord.Id, monotonicPath, payload)
+		result.ExtractedValue = extracted
+		if assertErr != nil {
+			result.Success = false
+			result.Error = assertErr.Error()
+		}
+	}
+	if result.Success && notContains != "" {
+		if err := apiTestCheckNotContains(payload, notContains, caseRecord.GetBool("not_contains_regex")); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+	if result.Success && bodyContains != "" {
+		if err := apiTestCheckBodyContains(payload, bodyContains); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+	if result.Success && jsonPath != "" {
+		if err := apiTestCheckJSONPathValue(payload, jsonPath, caseRecord.GetString("expected_json_value")); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+	if result.Success && len(templates) > 0 {
+		if err := apiTestCheckAssertionTemplates(payload, templates); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+	if result.Success && strings.TrimSpace(expectedBody) != "" {
+		ignorePaths, err := apiTestRecordStringList(caseRecord, "expected_body_ignore")
+		if err == nil {
+			err = apiTestCheckExpectedBody(payload, expectedBody, caseRecord.GetString("expected_body_mode"), ignorePaths)
+		}
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+	if result.Success && (minResponseBytes > 0 || maxResponseBytes > 0) {
+		if err := apiTestCheckResponseSize(result.ResponseBytes, minResponseBytes, maxResponseBytes); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+	// 指纹只对通过其他断言的响应计算，避免错误页的结构被记为新基线
+	if result.Success && fingerprintMode != "" {
+		fingerprintPaths, err := apiTestRecordStringList(caseRecord, "fingerprint_paths")
+		if err == nil {
+			result.Fingerprint, err = apiTestResponseFingerprint(payload, fingerprintMode, fingerprintPaths)
+		}
+		if err != nil {
+			result.Success = false
+			result.Error = fmt.Sprintf("响应指纹计算失败: %v", err)
+		} else if previous := caseRecord.GetString("last_fingerprint"); previous != "" && previous != result.Fingerprint {
+			result.Success = false
+			result.Error = fmt.Sprintf("响应结构已变化: 指纹 %s → %s", previous, result.Fingerprint)
+		}
+	}
+	result.DurationMs = int(time.Since(start).Milliseconds())
+	if result.Success {
+		if err := apiTestCheckLatency(result.DurationMs, limits.MaxLatencyMs); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+	}
+	return result
+}
+
+// apiTestCheckResponseSize 执行响应大小断言，minBytes/maxBytes 为 0 表示不限制。
+func apiTestCheckResponseSize(size int64, minBytes int64, maxBytes int64) error {
+	if size < 0 {
+		return errors.New("响应大小断言失败: 无法获取响应大小")
+	}
+	if minBytes > 0 && size < minBytes {
+		return fmt.Errorf("响应大小断言失败: %d 字节小于下限 %d 字节", size, minBytes)
+	}
+	if maxBytes > 0 && size > maxBytes {
+		return fmt.Errorf("响应大小断言失败: %d 字节超过上限 %d 字节", size, maxBytes)
+	}
+	return nil
+}
+
+// apiTestPersistRetryDelays 为写入执行结果时遇到 SQLite 锁冲突（SQLITE_BUSY）的重试间隔，
+// 重试次数即切片长度，避免瞬时锁竞争导致本次执行结果丢失。
+var apiTestPersistRetryDelays = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	400 * time.Millisecond,
+}
+
+// apiTestIsRetryableDBError 判断是否为可重试的数据库锁错误。
+// 不同驱动的错误码不一致，因此与 PocketBase 一致按错误文本匹配。
+func apiTestIsRetryableDBError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := err.Error()
+	return strings.Contains(message, "database is locked") ||
+		strings.Contains(message, "table is locked") ||
+		strings.Contains(message, "SQLITE_BUSY")
+}
+
+// apiTestRetryOnBusy 执行 op，仅在遇到锁冲突时按 apiTestPersistRetryDelays 退避重试，其他错误立即返回。
+func apiTestRetryOnBusy(op func() error) error {
+	err := op()
+	for _, delay := range apiTestPersistRetryDelays {
+		if !apiTestIsRetryableDBError(err) {
+			return err
+		}
+		time.Sleep(delay)
+		err = op()
+	}
+	return err
+}
+
+func (h *Hub) persistApiTestRun(caseRecord *core.Record, collectionRecord *core.Record, result apiTestExecutionResult, source apiTestRunSource, config *core.Record) (apiTestRunResult, error) {
+	var alertAction, tlsAction apiTestAlertAction
+	// 事务可能因锁冲突重试，需基于写入前的状态计算，避免连续失败次数被重复累加
+	initialConsecutive := caseRecord.GetInt("consecutive_failures")
+	initialTriggered := caseRecord.GetBool("alert_triggered")
+	initialTLSTriggered := caseRecord.GetBool("tls_expiry_alert_triggered")
+	err := apiTestRetryOnBusy(func() error {
+		alertAction, tlsAction = apiTestAlertAction{}, apiTestAlertAction{}
+		caseRecord.Set("tls_expiry_alert_triggered", initialTLSTriggered)
+		return h.RunInTransaction(func(txApp core.App) error {
+			return h.persistApiTestRunTx(txApp, caseRecord, collectionRecord, result, source, config, initialConsecutive, initialTriggered, &alertAction, &tlsAction)
 		})
+	})
+	if err != nil {
+		return apiTestRunResult{}, err
+	}
+	if source == apiTestRunSourceSchedule {
+		for _, action := range []apiTestAlertAction{alertAction, tlsAction} {
+			if !action.ShouldSend {
+				continue
+			}
+			if sendErr := h.sendApiTestAlert(action); sendErr != nil {
+				return apiTestRunResult{}, sendErr
+			}
+		}
+	}
+	return apiTestNewRunResult(caseRecord, collectionRecord, result), nil
+}
+
+func apiTestNewRunResult(caseRecord *core.Record, collectionRecord *core.Record, result apiTestExecutionResult) apiTestRunResult {
+	return apiTestRunResult{
+		CaseId:          caseRecord.Id,
+		CollectionId:    collectionRecord.Id,
+		Name:            caseRecord.GetString("name"),
+		Status:          result.Status,
+		DurationMs:      result.DurationMs,
+		Success:         result.Success,
+		Error:           result.Error,
+		ResponseSnippet: result.ResponseSnippet,
+		RunAt:           apiTestDateTimeString(result.RunAt),
 	}
-	return e.JSON(http.StatusOK, apiTestRunsResponse{
-		Items:      items,
-		Page:       page,
-		PerPage:    perPage,
-		TotalItems: totalItems,
-		TotalPages: totalPages,
-	})
 }
 
-func apiTestParseInt(raw string, fallback int) int {
-	value := strings.TrimSpace(raw)
-	if value == "" {
-		return fallback
+// apiTestPersistBatchSize 为批量执行时每个写入事务包含的用例数。
+const apiTestPersistBatchSize = 50
+
+type apiTestPendingRun struct {
+	caseRecord       *core.Record
+	collectionRecord *core.Record
+	result           apiTestExecutionResult
+	consecutive      int
+	triggered        bool
+	tlsTriggered     bool
+}
+
+// apiTestRunBatch 在批量执行时累积执行结果，每 size 个用例合并为一个事务写入，
+// 减少逐条开事务带来的写放大。告警状态仍按每个用例写入前的状态计算，写入成功后再发送告警。
+// 同一批次内每个用例只应出现一次（单调断言等依赖已写入的历史记录）。执行前按合集的 pacing_ms 对同一主机节流。
+// execute 与 flush 可被并发调用，写入事务串行执行。
+type apiTestRunBatch struct {
+	hub     *Hub
+	source  apiTestRunSource
+	config  *core.Record
+	size    int
+	mu      sync.Mutex
+	flushMu sync.Mutex
+	pending []apiTestPendingRun
+	pacer   *apiTestHostPacer
+}
+
+func (h *Hub) newApiTestRunBatch(source apiTestRunSource, config *core.Record) *apiTestRunBatch {
+	return &apiTestRunBatch{
+		hub:    h,
+		source: source,
+		config: config,
+		size:   apiTestPersistBatchSize,
+		pacer:  newApiTestHostPacer(),
 	}
-	parsed, err := strconv.Atoi(value)
-	if err != nil {
-		return fallback
+}
+
+// execute 执行用例并加入批次，达到批次大小时写入。返回的结果在写入前即可用于汇总。
+// ctx 结束时中止进行中的请求并返回 errApiTestRunAborted，被中止的结果不加入批次，避免被截断的请求计为失败并触发告警。
+func (b *apiTestRunBatch) execute(ctx context.Context, caseRecord *core.Record, collectionRecord *core.Record) (apiTestRunResult, error) {
+	pacing := time.Duration(collectionRecord.GetInt("pacing_ms")) * time.Millisecond
+	if ctx.Err() != nil {
+		return apiTestRunResult{}, errApiTestRunAborted
+	}
+	b.pacer.wait(b.hub.apiTestCaseHost(caseRecord, collectionRecord), pacing)
+	if ctx.Err() != nil {
+		return apiTestRunResult{}, errApiTestRunAborted
+	}
+	result := b.hub.performApiTestCaseWithRetry(ctx, caseRecord, collectionRecord)
+	if ctx.Err() != nil {
+		return apiTestRunResult{}, errApiTestRunAborted
+	}
+	b.mu.Lock()
+	b.pending = append(b.pending, apiTestPendingRun{
+		caseRecord:       caseRecord,
+		collectionRecord: collectionRecord,
+		result:           result,
+		consecutive:      caseRecord.GetInt("consecutive_failures"),
+		triggered:        caseRecord.GetBool("alert_triggered"),
+		tlsTriggered:     caseRecord.GetBool("tls_expiry_alert_triggered"),
+	})
+	full := len(b.pending) >= b.size
+	b.mu.Unlock()
+	if full {
+		if err := b.flush(); err != nil {
+			return apiTestRunResult{}, err
+		}
 	}
-	return parsed
+	return apiTestNewRunResult(caseRecord, collectionRecord, result), nil
 }
 
-func (h *Hub) executeApiTestCaseById(caseId string, source apiTestRunSource, config *core.Record) (apiTestRunResult, error) {
-	caseRecord, err := h.FindRecordById(apiTestCasesCollection, caseId)
-	if err != nil {
-		return apiTestRunResult{}, err
+// flush 在一个事务内写入累积的执行结果，随后发送定时巡检的告警。调用方在批量执行结束时必须调用。
+// 批次事务失败时逐条在各自的事务中重试，单条记录写入失败不会回滚其他用例的执行记录与告警状态；
+// 写入失败的用例不发送告警，其错误与告警发送错误一并返回。
+func (b *apiTestRunBatch) flush() error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
 	}
-	collectionId := caseRecord.GetString("collection")
-	collectionRecord, err := h.FindRecordById(apiTestCollectionsCollection, collectionId)
+	alertActions := make([]apiTestAlertAction, len(pending))
+	tlsActions := make([]apiTestAlertAction, len(pending))
+	var errorsList []string
+	err := apiTestRetryOnBusy(func() error {
+		for index := range pending {
+			b.resetPending(pending, alertActions, tlsActions, index)
+		}
+		return b.hub.RunInTransaction(func(txApp core.App) error {
+			for index, item := range pending {
+				if err := b.hub.persistApiTestRunTx(txApp, item.caseRecord, item.collectionRecord, item.result, b.source, b.config, item.consecutive, item.triggered, &alertActions[index], &tlsActions[index]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
 	if err != nil {
-		return apiTestRunResult{}, err
+		for index, item := range pending {
+			itemErr := apiTestRetryOnBusy(func() error {
+				b.resetPending(pending, alertActions, tlsActions, index)
+				return b.hub.RunInTransaction(func(txApp core.App) error {
+					return b.hub.persistApiTestRunTx(txApp, item.caseRecord, item.collectionRecord, item.result, b.source, b.config, item.consecutive, item.triggered, &alertActions[index], &tlsActions[index])
+				})
+			})
+			if itemErr != nil {
+				b.resetPending(pending, alertActions, tlsActions, index)
+				b.hub.logApiTestError("写入接口测试执行记录失败", itemErr, "case", item.caseRecord.Id)
+				errorsList = append(errorsList, fmt.Sprintf("case=%s: %v", item.caseRecord.Id, itemErr))
+			}
+		}
 	}
-	return h.executeApiTestCase(caseRecord, collectionRecord, source, config)
-}
-
-func (h *Hub) executeApiTestCase(caseRecord *core.Record, collectionRecord *core.Record, source apiTestRunSource, config *core.Record) (apiTestRunResult, error) {
-	start := time.Now()
-	result := apiTestExecutionResult{
-		Status:          0,
-		DurationMs:      0,
-		Success:         false,
-		Error:           "",
-		ResponseSnippet: "",
-		RunAt:           apiTestNowDateTime(),
+	if b.source == apiTestRunSourceSchedule {
+		for _, action := range append(alertActions, tlsActions...) {
+			if !action.ShouldSend {
+				continue
+			}
+			if sendErr := b.hub.sendApiTestAlert(action); sendErr != nil {
+				errorsList = append(errorsList, sendErr.Error())
+			}
+		}
 	}
-	method := strings.ToUpper(strings.TrimSpace(caseRecord.GetString("method")))
-	if method == "" {
-		result.Error = "HTTP 方法不能为空"
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+	if len(errorsList) > 0 {
+		return errors.New(strings.Join(errorsList, " | "))
 	}
-	if method != http.MethodGet && method != http.MethodPost && method != http.MethodPut && method != http.MethodDelete && method != http.MethodPatch && method != http.MethodHead {
-		result.Error = fmt.Sprintf("不支持的 HTTP 方法: %s", method)
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+	return nil
+}
+
+// resetPending 清空第 index 个结果的告警动作，并恢复用例写入前的证书告警状态，供事务重试前调用。
+func (b *apiTestRunBatch) resetPending(pending []apiTestPendingRun, alertActions []apiTestAlertAction, tlsActions []apiTestAlertAction, index int) {
+	alertActions[index] = apiTestAlertAction{}
+	tlsActions[index] = apiTestAlertAction{}
+	pending[index].caseRecord.Set("tls_expiry_alert_triggered", pending[index].tlsTriggered)
+}
+
+// apiTestValidateJSONPath 校验点分隔的 JSON 路径（如 data.items.0.count），空字符串表示未配置。
+func apiTestValidateJSONPath(path string) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil
 	}
-	expectedStatus := caseRecord.GetInt("expected_status")
-	if expectedStatus <= 0 {
-		result.Error = "期望状态码必须大于 0"
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+	for _, segment := range strings.Split(path, ".") {
+		if strings.TrimSpace(segment) == "" {
+			return fmt.Errorf("路径包含空片段: %s", path)
+		}
 	}
-	timeoutMs := caseRecord.GetInt("timeout_ms")
-	if timeoutMs <= 0 {
-		result.Error = "超时时间必须大于 0"
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+	return nil
+}
+
+// apiTestLookupJSONPath 按点分隔路径在解析后的 JSON 中取值，数字片段可用于数组下标。
+func apiTestLookupJSONPath(data any, path string) (any, bool) {
+	current := data
+	for _, segment := range strings.Split(strings.TrimSpace(path), ".") {
+		switch typed := current.(type) {
+		case map[string]any:
+			value, ok := typed[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil, false
+			}
+			current = typed[index]
+		default:
+			return nil, false
+		}
 	}
-	headers, err := h.buildApiTestHeaders(caseRecord)
-	if err != nil {
-		result.Error = fmt.Sprintf("解析请求头失败: %v", err)
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+	return current, true
+}
+
+func apiTestToFloat(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, true
+	case json.Number:
+		parsed, err := typed.Float64()
+		return parsed, err == nil
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+		return parsed, err == nil
+	default:
+		return 0, false
 	}
-	params, err := h.buildApiTestParams(caseRecord)
-	if err != nil {
-		result.Error = fmt.Sprintf("解析查询参数失败: %v", err)
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+}
+
+func apiTestRecordExtractedValue(record *core.Record) *apiTestExtractedValue {
+	raw := strings.TrimSpace(record.GetString("extracted_value"))
+	if raw == "" || raw == "null" {
+		return nil
 	}
-	bodyReader, contentType, err := h.buildApiTestBody(caseRecord)
-	if err != nil {
-		result.Error = fmt.Sprintf("解析请求体失败: %v", err)
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+	var value apiTestExtractedValue
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return nil
 	}
-	targetURL, err := h.resolveApiTestURL(collectionRecord, caseRecord)
+	return &value
+}
+
+// findPreviousApiTestExtractedValue 查找同一用例、同一路径最近一次记录的提取值，没有时返回 nil。
+func (h *Hub) findPreviousApiTestExtractedValue(caseId string, path string) (*apiTestExtractedValue, error) {
+	record := &core.Record{}
+	err := h.RecordQuery(apiTestRunsCollection).
+		AndWhere(dbx.NewExp("`case` = {:case}", dbx.Params{"case": caseId})).
+		AndWhere(dbx.NewExp("json_extract(extracted_value, '$.path') = {:path}", dbx.Params{"path": path})).
+		OrderBy("created DESC").
+		Limit(1).
+		One(record)
 	if err != nil {
-		result.Error = fmt.Sprintf("构建请求地址失败: %v", err)
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
-	}
-	if err := h.validateApiTestTarget(targetURL); err != nil {
-		result.Error = fmt.Sprintf("请求地址校验失败: %v", err)
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
 	}
-	request, err := http.NewRequest(method, targetURL, bodyReader)
+	return apiTestRecordExtractedValue(record), nil
+}
+
+// evaluateApiTestMonotonic 执行单调断言：按 monotonic_path 从响应 JSON 提取数值，要求不小于上一次记录的值。
+// 语义说明：
+//   - 首次执行（该路径无历史值）视为通过；
+//   - 路径不存在或值非数字视为失败，且不记录提取值；
+//   - 数值下降时断言失败，但仍记录本次值，后续执行以最新记录为基准（便于计数器重置后自动恢复）。
+func (h *Hub) evaluateApiTestMonotonic(caseId string, path string, body []byte) (*apiTestExtractedValue, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("单调断言解析响应 JSON 失败: %w", err)
+	}
+	raw, ok := apiTestLookupJSONPath(data, path)
+	if !ok {
+		return nil, fmt.Errorf("单调断言未找到路径: %s", path)
+	}
+	value, ok := apiTestToFloat(raw)
+	if !ok {
+		return nil, fmt.Errorf("单调断言路径 %s 的值不是数字", path)
+	}
+	current := &apiTestExtractedValue{Path: path, Value: value}
+	previous, err := h.findPreviousApiTestExtractedValue(caseId, path)
 	if err != nil {
-		result.Error = fmt.Sprintf("创建请求失败: %v", err)
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+		return current, fmt.Errorf("读取上次提取值失败: %w", err)
 	}
-	for key, value := range headers {
-		request.Header.Set(key, value)
+	if previous != nil && value < previous.Value {
+		return current, fmt.Errorf("单调断言失败: 当前值 %v 小于上次 %v", value, previous.Value)
 	}
-	if contentType != "" && request.Header.Get("Content-Type") == "" {
-		request.Header.Set("Content-Type", contentType)
+	return current, nil
+}
+
+// apiTestValidateFingerprint 校验响应指纹配置：mode 为 keys（仅键结构）或 types（键结构与值类型），
+// paths 限定参与计算的子树，需先启用 mode。返回出错的字段名。
+func apiTestValidateFingerprint(mode string, paths []string) (string, error) {
+	switch strings.TrimSpace(mode) {
+	case "":
+		if len(paths) > 0 {
+			return "fingerprint_paths", errors.New("未启用指纹时不能配置指纹路径")
+		}
+		return "", nil
+	case "keys", "types":
+	default:
+		return "fingerprint_mode", fmt.Errorf("不支持的指纹模式: %s", mode)
 	}
-	if len(params) > 0 {
-		query := request.URL.Query()
-		for key, value := range params {
-			query.Add(key, value)
+	for _, path := range paths {
+		if strings.TrimSpace(path) == "" {
+			return "fingerprint_paths", errors.New("指纹路径不能为空")
+		}
+		if err := apiTestValidateJSONPath(path); err != nil {
+			return "fingerprint_paths", err
 		}
-		request.URL.RawQuery = query.Encode()
 	}
-	client := &http.Client{Timeout: time.Duration(timeoutMs) * time.Millisecond}
-	response, err := client.Do(request)
+	return "", nil
+}
+
+// apiTestResponseFingerprint 计算 JSON 响应的结构指纹（规范化结构描述的 SHA-256 前 16 位十六进制）。
+// 对象按键排序，数组取元素结构的去重集合（长度变化不影响指纹），值本身不参与计算；
+// types 模式额外区分标量类型。配置了 paths 时只对这些子树计算，路径缺失本身也计入指纹。
+func apiTestResponseFingerprint(payload []byte, mode string, paths []string) (string, error) {
+	data, err := apiTestDecodeJSON(payload)
 	if err != nil {
-		result.Error = fmt.Sprintf("请求执行失败: %v", err)
-		result.DurationMs = int(time.Since(start).Milliseconds())
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+		return "", fmt.Errorf("响应不是合法 JSON: %v", err)
 	}
-	defer response.Body.Close()
-	result.Status = response.StatusCode
-	snippetReader := io.LimitReader(response.Body, apiTestMaxResponseSnippetBytes+1)
-	payload, readErr := io.ReadAll(snippetReader)
-	if readErr != nil {
-		result.Error = fmt.Sprintf("读取响应失败: %v", readErr)
-		result.DurationMs = int(time.Since(start).Milliseconds())
-		return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
-	}
-	result.ResponseSnippet = strings.TrimSpace(string(payload))
-	result.Success = result.Status == expectedStatus
-	if !result.Success {
-		if result.ResponseSnippet != "" {
-			result.Error = result.ResponseSnippet
-		} else {
-			result.Error = fmt.Sprintf("期望状态码 %d，实际 %d", expectedStatus, result.Status)
+	withTypes := mode == "types"
+	var shape string
+	if len(paths) == 0 {
+		shape = apiTestJSONShape(data, withTypes)
+	} else {
+		parts := make([]string, 0, len(paths))
+		for _, path := range paths {
+			path = strings.TrimSpace(path)
+			value, ok := apiTestLookupJSONPath(data, path)
+			if !ok {
+				parts = append(parts, path+"=<missing>")
+				continue
+			}
+			parts = append(parts, path+"="+apiTestJSONShape(value, withTypes))
 		}
+		shape = strings.Join(parts, "\n")
 	}
-	result.DurationMs = int(time.Since(start).Milliseconds())
-	return h.persistApiTestRun(caseRecord, collectionRecord, result, source, config)
+	sum := sha256.Sum256([]byte(shape))
+	return hex.EncodeToString(sum[:8]), nil
 }
 
-func (h *Hub) persistApiTestRun(caseRecord *core.Record, collectionRecord *core.Record, result apiTestExecutionResult, source apiTestRunSource, config *core.Record) (apiTestRunResult, error) {
-	var alertAction apiTestAlertAction
-	err := h.RunInTransaction(func(txApp core.App) error {
-		caseRecord.Set("last_status", result.Status)
-		caseRecord.Set("last_duration_ms", result.DurationMs)
-		caseRecord.Set("last_run_at", result.RunAt)
-		caseRecord.Set("last_success", result.Success)
-		caseRecord.Set("last_error", result.Error)
-		caseRecord.Set("last_response_snippet", result.ResponseSnippet)
-
-		threshold := caseRecord.GetInt("alert_threshold")
-		if threshold <= 0 {
-			threshold = apiTestDefaultAlertThreshold
+func apiTestJSONShape(value any, withTypes bool) string {
+	switch typed := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var builder strings.Builder
+		builder.WriteByte('{')
+		for index, key := range keys {
+			if index > 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteString(strconv.Quote(key))
+			builder.WriteByte(':')
+			builder.WriteString(apiTestJSONShape(typed[key], withTypes))
 		}
-		consecutive := caseRecord.GetInt("consecutive_failures")
-		triggered := caseRecord.GetBool("alert_triggered")
-		previousConsecutive := consecutive
-		intervalMinutes := apiTestDefaultIntervalMinutes
-		if config != nil && config.GetInt("interval_minutes") > 0 {
-			intervalMinutes = config.GetInt("interval_minutes")
+		builder.WriteByte('}')
+		return builder.String()
+	case []any:
+		shapes := make([]string, 0, len(typed))
+		for _, item := range typed {
+			shape := apiTestJSONShape(item, withTypes)
+			if !slices.Contains(shapes, shape) {
+				shapes = append(shapes, shape)
+			}
 		}
+		sort.Strings(shapes)
+		return "[" + strings.Join(shapes, "|") + "]"
+	}
+	if !withTypes {
+		return "*"
+	}
+	switch value.(type) {
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return "null"
+	}
+}
+
+// persistApiTestRunTx 在事务内更新用例最新状态并写入执行记录，consecutive/triggered 为写入前的状态。
+// persistApiTestRunTx 写入执行结果并更新用例状态。alertAction 接收失败/成功率告警，
+// tlsAction 非 nil 时接收证书到期告警；调用方需在重试前恢复用例的 tls_expiry_alert_triggered。
+func (h *Hub) persistApiTestRunTx(txApp core.App, caseRecord *core.Record, collectionRecord *core.Record, result apiTestExecutionResult, source apiTestRunSource, config *core.Record, consecutive int, triggered bool, alertAction *apiTestAlertAction, tlsAction *apiTestAlertAction) error {
+	caseRecord.Set("last_status", result.Status)
+	caseRecord.Set("last_duration_ms", result.DurationMs)
+	caseRecord.Set("last_run_at", result.RunAt)
+	caseRecord.Set("last_success", result.Success)
+	caseRecord.Set("last_error", apiTestTruncateText(result.Error, apiTestMaxStoredErrorChars))
+	caseRecord.Set("last_response_snippet", apiTestTruncateText(result.ResponseSnippet, apiTestMaxStoredSnippetChars))
+	// 指纹只在尚无基线时写入；结构变化后保留原基线直至用户确认，连续失败才能累计到告警阈值
+	if result.Fingerprint != "" && caseRecord.GetString("last_fingerprint") == "" {
+		caseRecord.Set("last_fingerprint", result.Fingerprint)
+	}
+
+	threshold := caseRecord.GetInt("alert_threshold")
+	if threshold <= 0 {
+		threshold = apiTestDefaultAlertThreshold
+	}
+	previousConsecutive := consecutive
+	intervalMinutes := apiTestDefaultIntervalMinutes
+	if config != nil && config.GetInt("interval_minutes") > 0 {
+		intervalMinutes = config.GetInt("interval_minutes")
+	}
 
+	if rule := apiTestRecordSuccessRateRule(caseRecord); rule != nil {
+		// 成功率模式仍维护连续失败次数用于展示，告警状态只在定时执行时按窗口成功率变更
 		if result.Success {
-			if consecutive > 0 {
-				consecutive = 0
-			}
-			if triggered && config != nil && config.GetBool("alert_on_recover") {
-				alertAction = apiTestAlertAction{
-					ShouldSend:          true,
-					State:               alerts.NotificationStateResolved,
-					CaseName:            caseRecord.GetString("name"),
-					ConsecutiveFailures: previousConsecutive,
-					Threshold:           threshold,
-					DurationMinutes:     previousConsecutive * intervalMinutes,
-					StatusCode:          result.Status,
-				}
-			}
-			triggered = false
+			consecutive = 0
 		} else {
 			consecutive++
-			if config != nil && config.GetBool("alert_enabled") && !triggered && consecutive >= threshold {
-				alertAction = apiTestAlertAction{
-					ShouldSend:          true,
-					State:               alerts.NotificationStateTriggered,
-					CaseName:            caseRecord.GetString("name"),
-					ConsecutiveFailures: consecutive,
-					Threshold:           threshold,
-					DurationMinutes:     consecutive * intervalMinutes,
-					StatusCode:          result.Status,
-					ErrorMessage:        result.Error,
+		}
+		if source == apiTestRunSourceSchedule {
+			rate, total, err := apiTestWindowSuccessRate(txApp, caseRecord.Id, rule.WindowMinutes, result.Success)
+			if err != nil {
+				return err
+			}
+			rateAction := apiTestAlertAction{
+				ShouldSend:          true,
+				CaseName:            caseRecord.GetString("name"),
+				ConsecutiveFailures: consecutive,
+				DurationMinutes:     rule.WindowMinutes,
+				StatusCode:          result.Status,
+				Mode:                apiTestAlertModeSuccessRate,
+				SuccessRate:         rate,
+				RateThreshold:       rule.Threshold,
+			}
+			switch {
+			case triggered && rate >= rule.Threshold:
+				if config != nil && config.GetBool("alert_on_recover") {
+					rateAction.State = alerts.NotificationStateResolved
+					*alertAction = rateAction
 				}
+				triggered = false
+			case !triggered && total >= apiTestAlertRateMinRuns && rate < rule.Threshold && config != nil && config.GetBool("alert_enabled"):
+				rateAction.State = alerts.NotificationStateTriggered
+				rateAction.ErrorMessage = result.Error
+				*alertAction = rateAction
 				triggered = true
 			}
 		}
-		caseRecord.Set("consecutive_failures", consecutive)
-		caseRecord.Set("alert_triggered", triggered)
-		if err := txApp.Save(caseRecord); err != nil {
-			return err
+	} else if result.Success {
+		if consecutive > 0 {
+			consecutive = 0
 		}
-		runsCollection, err := txApp.FindCollectionByNameOrId(apiTestRunsCollection)
-		if err != nil {
-			return err
+		if triggered && config != nil && config.GetBool("alert_on_recover") {
+			*alertAction = apiTestAlertAction{
+				ShouldSend:          true,
+				State:               alerts.NotificationStateResolved,
+				CaseName:            caseRecord.GetString("name"),
+				ConsecutiveFailures: previousConsecutive,
+				Threshold:           threshold,
+				DurationMinutes:     previousConsecutive * intervalMinutes,
+				StatusCode:          result.Status,
+			}
 		}
-		runRecord := core.NewRecord(runsCollection)
-		runRecord.Set("collection", collectionRecord.Id)
-		runRecord.Set("case", caseRecord.Id)
-		runRecord.Set("status", result.Status)
-		runRecord.Set("duration_ms", result.DurationMs)
-		runRecord.Set("success", result.Success)
-		runRecord.Set("error", result.Error)
-		runRecord.Set("response_snippet", result.ResponseSnippet)
-		runRecord.Set("source", string(source))
-		if err := txApp.Save(runRecord); err != nil {
-			return err
+		triggered = false
+	} else {
+		consecutive++
+		if config != nil && config.GetBool("alert_enabled") && !triggered && consecutive >= threshold {
+			*alertAction = apiTestAlertAction{
+				ShouldSend:          true,
+				State:               alerts.NotificationStateTriggered,
+				CaseName:            caseRecord.GetString("name"),
+				ConsecutiveFailures: consecutive,
+				Threshold:           threshold,
+				DurationMinutes:     consecutive * intervalMinutes,
+				StatusCode:          result.Status,
+				ErrorMessage:        result.Error,
+			}
+			triggered = true
 		}
-		return nil
-	})
+	}
+	caseRecord.Set("consecutive_failures", consecutive)
+	caseRecord.Set("alert_triggered", triggered)
+	if action := apiTestApplyTLSExpiry(caseRecord, result, source, config, time.Now()); tlsAction != nil {
+		*tlsAction = action
+	}
+	if err := txApp.Save(caseRecord); err != nil {
+		return err
+	}
+	runsCollection, err := txApp.FindCollectionByNameOrId(apiTestRunsCollection)
 	if err != nil {
-		return apiTestRunResult{}, err
+		return err
 	}
-	if alertAction.ShouldSend && source == apiTestRunSourceSchedule {
-		if sendErr := h.sendApiTestAlert(alertAction); sendErr != nil {
-			return apiTestRunResult{}, sendErr
-		}
+	runRecord := core.NewRecord(runsCollection)
+	runRecord.Set("collection", collectionRecord.Id)
+	runRecord.Set("case", caseRecord.Id)
+	runRecord.Set("status", result.Status)
+	runRecord.Set("duration_ms", result.DurationMs)
+	runRecord.Set("success", result.Success)
+	runRecord.Set("error", apiTestTruncateText(result.Error, apiTestMaxStoredErrorChars))
+	runRecord.Set("response_snippet", apiTestTruncateText(result.ResponseSnippet, apiTestMaxStoredSnippetChars))
+	runRecord.Set("source", string(source))
+	if result.ExtractedValue != nil {
+		runRecord.Set("extracted_value", result.ExtractedValue)
+	}
+	if result.ResponseBytes >= 0 {
+		runRecord.Set("response_bytes", result.ResponseBytes)
+	}
+	runRecord.Set("fingerprint", result.Fingerprint)
+	runRecord.Set("protocol", result.Protocol)
+	if len(result.ResponseHeaders) > 0 {
+		runRecord.Set("response_headers", result.ResponseHeaders)
+	}
+	if !result.TLSExpiresAt.IsZero() {
+		runRecord.Set("tls_expires_at", result.TLSExpiresAt)
+	}
+	runRecord.Set("final_url", result.FinalURL)
+	if err := txApp.Save(runRecord); err != nil {
+		return err
 	}
-	return apiTestRunResult{
-		CaseId:          caseRecord.Id,
-		CollectionId:    collectionRecord.Id,
-		Name:            caseRecord.GetString("name"),
-		Status:          result.Status,
-		DurationMs:      result.DurationMs,
-		Success:         result.Success,
-		Error:           result.Error,
-		ResponseSnippet: result.ResponseSnippet,
-		RunAt:           apiTestDateTimeString(result.RunAt),
-	}, nil
+	return nil
 }
 
 func (h *Hub) sendApiTestAlert(action apiTestAlertAction) error {
 	if !action.ShouldSend {
 		return nil
 	}
+	if err := h.sendApiTestWebhook(action); err != nil {
+		h.logApiTestError("推送接口告警 Webhook 失败", err, "caseName", action.CaseName)
+	}
 	lang, err := alerts.GetNotificationLanguage(h)
 	if err != nil {
 		h.logApiTestError("读取通知语言失败", err, "action", action)
@@ -1311,6 +4576,12 @@ func (h *Hub) sendApiTestAlert(action apiTestAlertAction) error {
 		appName = "Aether"
 	}
 	alertType := "API Test"
+	switch action.Mode {
+	case apiTestAlertModeHealth:
+		alertType = "API Health"
+	case apiTestAlertModeTLSExpiry:
+		alertType = "API TLS Certificate"
+	}
 	if strings.TrimSpace(action.CaseName) != "" {
 		alertType = fmt.Sprintf("%s: %s", alertType, action.CaseName)
 	}
@@ -1320,10 +4591,23 @@ func (h *Hub) sendApiTestAlert(action apiTestAlertAction) error {
 	}
 	currentValue := fmt.Sprintf("%d", action.ConsecutiveFailures)
 	threshold := fmt.Sprintf("%d", thresholdValue)
-	if lang == alerts.NotificationLanguageZhCN {
+	switch {
+	case action.Mode == apiTestAlertModeSuccessRate:
+		currentValue = apiTestFormatRate(action.SuccessRate)
+		threshold = apiTestFormatRate(action.RateThreshold)
+	case action.Mode == apiTestAlertModeHealth:
+		currentValue = apiTestFormatScore(action.SuccessRate)
+		threshold = apiTestFormatScore(action.RateThreshold)
+	case action.Mode == apiTestAlertModeTLSExpiry && lang == alerts.NotificationLanguageZhCN:
+		currentValue = fmt.Sprintf("剩余 %d 天", action.DaysLeft)
+		threshold = fmt.Sprintf("%d 天", action.Threshold)
+	case action.Mode == apiTestAlertModeTLSExpiry:
+		currentValue = fmt.Sprintf("%d days left", action.DaysLeft)
+		threshold = fmt.Sprintf("%d days", action.Threshold)
+	case lang == alerts.NotificationLanguageZhCN:
 		currentValue = fmt.Sprintf("%d 次", action.ConsecutiveFailures)
 		threshold = fmt.Sprintf("%d 次", thresholdValue)
-	} else {
+	default:
 		currentValue = fmt.Sprintf("%d times", action.ConsecutiveFailures)
 		threshold = fmt.Sprintf("%d times", thresholdValue)
 	}
@@ -1332,6 +4616,13 @@ func (h *Hub) sendApiTestAlert(action apiTestAlertAction) error {
 		duration = alerts.FormatDurationMinutes(action.DurationMinutes, lang)
 	}
 	details := strings.TrimSpace(action.ErrorMessage)
+	if details == "" && action.ExpiresAt != "" {
+		if lang == alerts.NotificationLanguageZhCN {
+			details = "证书到期时间: " + action.ExpiresAt
+		} else {
+			details = "Certificate expires at: " + action.ExpiresAt
+		}
+	}
 	if details == "" && action.StatusCode > 0 {
 		if lang == alerts.NotificationLanguageZhCN {
 			details = fmt.Sprintf("状态码: %d", action.StatusCode)
@@ -1391,7 +4682,10 @@ func (h *Hub) sendApiTestAlert(action apiTestAlertAction) error {
 	return nil
 }
 
-func (h *Hub) executeApiTestCollection(collectionId string, source apiTestRunSource) (apiTestCollectionRunSummary, error) {
+// executeApiTestCollection 依次执行合集内用例；ctx 取消时中止进行中的请求，
+// 该用例与其后未执行的启用用例计入 Aborted 并以 aborted 结果列出，返回已完成部分的汇总。
+// 停用的用例不执行，计入 Skipped 而不计入 Cases。
+func (h *Hub) executeApiTestCollection(ctx context.Context, job *runningJob, collectionId string, source apiTestRunSource) (apiTestCollectionRunSummary, error) {
 	collectionRecord, err := h.FindRecordById(apiTestCollectionsCollection, collectionId)
 	if err != nil {
 		return apiTestCollectionRunSummary{}, err
@@ -1412,12 +4706,25 @@ func (h *Hub) executeApiTestCollection(collectionId string, source apiTestRunSou
 		Failed:       0,
 		Results:      []apiTestRunResult{},
 	}
-	for _, caseRecord := range cases {
-		summary.Cases++
-		result, runErr := h.executeApiTestCase(caseRecord, collectionRecord, source, nil)
+	batch := h.newApiTestRunBatch(source, nil)
+	for index, caseRecord := range cases {
+		// 与全量巡检一致：进度为开始本用例前已完成的用例数
+		job.setProgress(index, len(cases))
+		if !caseRecord.GetBool("enabled") {
+			summary.Skipped++
+			continue
+		}
+		result, runErr := batch.execute(ctx, caseRecord, collectionRecord)
+		if errors.Is(runErr, errApiTestRunAborted) {
+			summary.Cancelled = true
+			summary.Aborted++
+			summary.Results = append(summary.Results, apiTestAbortedRunResult(caseRecord, collectionRecord))
+			continue
+		}
 		if runErr != nil {
 			return apiTestCollectionRunSummary{}, runErr
 		}
+		summary.Cases++
 		summary.Results = append(summary.Results, result)
 		if result.Success {
 			summary.Success++
@@ -1425,13 +4732,23 @@ func (h *Hub) executeApiTestCollection(collectionId string, source apiTestRunSou
 			summary.Failed++
 		}
 	}
+	if err := batch.flush(); err != nil {
+		return apiTestCollectionRunSummary{}, err
+	}
 	if err := h.cleanupApiTestRuns(scheduleConfig); err != nil {
 		return apiTestCollectionRunSummary{}, err
 	}
 	return summary, nil
 }
 
-func (h *Hub) executeApiTestAll(source apiTestRunSource) (apiTestRunAllSummary, error) {
+// executeApiTestAll 按 options 的模式执行全部用例；ctx 取消时中止进行中的请求且不再开始新的用例，
+// 未完成的启用用例计入 Aborted 并以 aborted 结果列出，返回已完成部分的汇总。
+// 停用的用例不执行，计入 Skipped 而不计入 Cases。
+func (h *Hub) executeApiTestAll(ctx context.Context, job *runningJob, source apiTestRunSource, options apiTestRunAllOptions) (apiTestRunAllSummary, error) {
+	options, err := options.normalize()
+	if err != nil {
+		return apiTestRunAllSummary{}, err
+	}
 	collections, err := h.FindRecordsByFilter(apiTestCollectionsCollection, "", "sort_order,created", -1, 0, nil)
 	if err != nil {
 		return apiTestRunAllSummary{}, err
@@ -1455,21 +4772,31 @@ func (h *Hub) executeApiTestAll(source apiTestRunSource) (apiTestRunAllSummary,
 		Failed:      0,
 		Results:     []apiTestRunResult{},
 	}
-	for _, caseRecord := range cases {
-		collectionRecord := collectionMap[caseRecord.GetString("collection")]
-		if collectionRecord == nil {
-			continue
-		}
-		summary.Cases++
-		result, runErr := h.executeApiTestCase(caseRecord, collectionRecord, source, nil)
-		if runErr != nil {
-			return apiTestRunAllSummary{}, runErr
-		}
-		summary.Results = append(summary.Results, result)
-		if result.Success {
-			summary.Success++
-		} else {
-			summary.Failed++
+	batch := h.newApiTestRunBatch(source, nil)
+	outcomes, cancelled, runErr := h.runApiTestAllGroups(ctx, job, batch, cases, collectionMap, apiTestRunAllGroups(cases, options.Mode), options.Concurrency)
+	// 出错时仍写入已完成的结果
+	if err := batch.flush(); err != nil && runErr == nil {
+		runErr = err
+	}
+	if runErr != nil {
+		return apiTestRunAllSummary{}, runErr
+	}
+	summary.Cancelled = cancelled
+	for _, outcome := range outcomes {
+		switch {
+		case outcome.skipped:
+			summary.Skipped++
+		case outcome.aborted:
+			summary.Aborted++
+			summary.Results = append(summary.Results, outcome.result)
+		case outcome.ran:
+			summary.Cases++
+			summary.Results = append(summary.Results, outcome.result)
+			if outcome.result.Success {
+				summary.Success++
+			} else {
+				summary.Failed++
+			}
 		}
 	}
 	if err := h.cleanupApiTestRuns(scheduleConfig); err != nil {
@@ -1479,6 +4806,13 @@ func (h *Hub) executeApiTestAll(source apiTestRunSource) (apiTestRunAllSummary,
 }
 
 func (h *Hub) runApiTestScheduleTick() {
+	// 启动完成前不执行，也不推进 next_run_at，避免集合/数据库未就绪时误报
+	if !h.ready.Load() {
+		return
+	}
+	if h.apiTestSchedulerActive.CompareAndSwap(false, true) {
+		h.Logger().Info("接口定时巡检已就绪", "logger", "hub")
+	}
 	config, err := h.getOrCreateApiTestScheduleConfig()
 	if err != nil {
 		h.logApiTestError("读取接口定时配置失败", err)
@@ -1504,20 +4838,26 @@ func (h *Hub) runApiTestScheduleTick() {
 	if nextRun.Time().After(now) {
 		return
 	}
-	if !apiTestAcquireRunLock() {
-		config.Set("last_error", "已有任务在执行，本次跳过")
+	if busy, ok := apiTestAcquireRunLock(apiTestRunLockAll); !ok {
+		config.Set("last_error", apiTestRunBusyError(busy).Error()+"，本次跳过")
 		config.Set("next_run_at", apiTestNowDateTime().Add(time.Duration(intervalMinutes)*time.Minute))
 		if err := h.Save(config); err != nil {
 			h.logApiTestError("更新接口定时配置失败", err)
 		}
 		return
 	}
-	defer apiTestReleaseRunLock()
+	defer apiTestReleaseRunLock(apiTestRunLockAll)
 
-	runErr := h.executeScheduledApiTests(config, now, intervalMinutes)
+	job, ctx := h.jobs.start("", runningJobTypeApiTest, "scheduled run", "", true)
+	defer h.jobs.finish(job)
+	apiTestBindRunLock(apiTestRunLockAll, job)
+	summary, runErr := h.executeScheduledApiTests(ctx, job, config, now, intervalMinutes)
+	if summary != nil {
+		config.Set("last_run_summary", summary)
+	}
 	if runErr != nil {
 		h.logApiTestError("接口定时巡检失败", runErr)
-		config.Set("last_error", runErr.Error())
+		config.Set("last_error", apiTestTruncateText(runErr.Error(), apiTestMaxStoredErrorChars))
 	} else {
 		config.Set("last_error", "")
 	}
@@ -1528,10 +4868,12 @@ func (h *Hub) runApiTestScheduleTick() {
 	}
 }
 
-func (h *Hub) executeScheduledApiTests(config *core.Record, now time.Time, intervalMinutes int) error {
+// executeScheduledApiTests 执行本次巡检到期的用例，返回包含跳过原因的摘要；读取用例或合集失败时摘要为 nil。
+func (h *Hub) executeScheduledApiTests(ctx context.Context, job *runningJob, config *core.Record, now time.Time, intervalMinutes int) (*apiTestScheduleRunSummary, error) {
+	// 停用的用例同样读取，以便在摘要中说明跳过原因
 	cases, err := h.FindRecordsByFilter(apiTestCasesCollection, "schedule_enabled = true", "collection,sort_order,created", -1, 0, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	collectionIds := map[string]struct{}{}
 	for _, caseRecord := range cases {
@@ -1540,42 +4882,97 @@ func (h *Hub) executeScheduledApiTests(config *core.Record, now time.Time, inter
 	collectionMap := make(map[string]*core.Record)
 	for id := range collectionIds {
 		record, err := h.FindRecordById(apiTestCollectionsCollection, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
 		if err != nil {
-			return err
+			return nil, err
 		}
 		collectionMap[id] = record
 	}
+	summary := newApiTestScheduleRunSummary(len(cases))
 	var errorsList []string
-	for _, caseRecord := range cases {
-		caseInterval := caseRecord.GetInt("schedule_minutes")
-		if caseInterval <= 0 {
-			caseInterval = intervalMinutes
+	batch := h.newApiTestRunBatch(apiTestRunSourceSchedule, config)
+	for index, caseRecord := range cases {
+		// 取消后未执行的用例保持原 last_run_at，下次巡检仍会到期
+		if ctx.Err() != nil {
+			errorsList = append(errorsList, "巡检已取消")
+			break
 		}
-		lastRun := caseRecord.GetDateTime("last_run_at")
-		if !lastRun.IsZero() {
-			nextDue := lastRun.Time().Add(time.Duration(caseInterval) * time.Minute)
-			if nextDue.After(now) {
-				continue
-			}
+		job.setProgress(index, len(cases))
+		if !caseRecord.GetBool("enabled") {
+			summary.skip(caseRecord, apiTestScheduleSkipDisabled)
+			continue
 		}
 		collectionRecord := collectionMap[caseRecord.GetString("collection")]
 		if collectionRecord == nil {
+			summary.skip(caseRecord, apiTestScheduleSkipCollectionMissing)
 			continue
 		}
-		_, runErr := h.executeApiTestCase(caseRecord, collectionRecord, apiTestRunSourceSchedule, config)
-		if runErr != nil {
+		due, err := apiTestScheduleDue(caseRecord, collectionRecord, now, intervalMinutes)
+		if err != nil {
+			errorsList = append(errorsList, fmt.Sprintf("case=%s: %v", caseRecord.Id, err))
+			continue
+		}
+		if !due {
+			summary.skip(caseRecord, apiTestScheduleSkipNotDue)
+			continue
+		}
+		summary.Executed++
+		if _, runErr := batch.execute(ctx, caseRecord, collectionRecord); runErr != nil {
+			if errors.Is(runErr, errApiTestRunAborted) {
+				errorsList = append(errorsList, "巡检已取消")
+				break
+			}
 			errorsList = append(errorsList, runErr.Error())
 		}
 	}
+	if err := batch.flush(); err != nil {
+		errorsList = append(errorsList, err.Error())
+	}
+	if err := h.evaluateApiTestHealthAlerts(collectionMap); err != nil {
+		errorsList = append(errorsList, err.Error())
+	}
 	if err := h.cleanupApiTestRuns(config); err != nil {
 		errorsList = append(errorsList, err.Error())
 	}
 	if len(errorsList) > 0 {
-		return errors.New(strings.Join(errorsList, " | "))
+		return summary, errors.New(strings.Join(errorsList, " | "))
 	}
-	return nil
+	return summary, nil
+}
+
+// apiTestScheduleDue 判断用例本次巡检是否到期。
+// 优先使用用例的 schedule_cron，其次为合集的 schedule_cron，均未配置时回退到 schedule_minutes 间隔。
+func apiTestScheduleDue(caseRecord *core.Record, collectionRecord *core.Record, now time.Time, intervalMinutes int) (bool, error) {
+	lastRun := caseRecord.GetDateTime("last_run_at")
+	cronExpr := strings.TrimSpace(caseRecord.GetString("schedule_cron"))
+	if cronExpr == "" {
+		cronExpr = strings.TrimSpace(collectionRecord.GetString("schedule_cron"))
+	}
+	if cronExpr != "" {
+		schedule, err := apiTestParseScheduleCron(cronExpr)
+		if err != nil {
+			return false, fmt.Errorf("cron 表达式无效: %w", err)
+		}
+		since := now.Add(-time.Duration(intervalMinutes) * time.Minute)
+		if !lastRun.IsZero() {
+			since = lastRun.Time()
+		}
+		return apiTestCronDueBetween(schedule, since, now), nil
+	}
+	caseInterval := caseRecord.GetInt("schedule_minutes")
+	if caseInterval <= 0 {
+		caseInterval = intervalMinutes
+	}
+	if lastRun.IsZero() {
+		return true, nil
+	}
+	nextDue := lastRun.Time().Add(time.Duration(caseInterval) * time.Minute)
+	return !nextDue.After(now), nil
 }
 
+// cleanupApiTestRuns 按保留天数清理执行记录与配置变更审计。
 func (h *Hub) cleanupApiTestRuns(config *core.Record) error {
 	retentionDays := config.GetInt("history_retention_days")
 	if retentionDays <= 0 {
@@ -1588,5 +4985,5 @@ func (h *Hub) cleanupApiTestRuns(config *core.Record) error {
 	if err != nil {
 		return err
 	}
-	return nil
+	return h.cleanupApiTestConfigAudits(config)
 }
@@ -0,0 +1,72 @@
+// Package hub 提供中止执行中的接口测试批量执行。
+// 合集执行、金丝雀执行、全部执行与定时巡检在持有执行锁期间将其运行任务登记到锁上，中止接口按锁键找到任务并取消其 context：
+// 进行中的请求随之中止（包括重试等待），不再开始新的用例。被中止的用例不写入执行记录，也不参与连续失败计数与告警，
+// 汇总中以 aborted 结果列出并计入 Aborted。中止与 /jobs/cancel 取消同一任务，效果一致。
+package hub
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// errApiTestRunAborted 表示用例因执行被中止而未完成。
+var errApiTestRunAborted = errors.New("执行已中止")
+
+type apiTestAbortRunRequest struct {
+	// CollectionId 为空时中止全部执行或定时巡检
+	CollectionId string `json:"collectionId"`
+}
+
+// apiTestBindRunLock 将持有 key 执行锁的任务登记到锁上，锁释放时随之移除。
+func apiTestBindRunLock(key string, job *runningJob) {
+	apiTestRunLocks.Lock()
+	defer apiTestRunLocks.Unlock()
+	if _, ok := apiTestRunLocks.held[key]; ok {
+		apiTestRunLocks.held[key] = job
+	}
+}
+
+// apiTestRunLockJob 返回持有 key 执行锁的任务，未持有或尚未登记时返回 nil。
+func apiTestRunLockJob(key string) *runningJob {
+	apiTestRunLocks.Lock()
+	defer apiTestRunLocks.Unlock()
+	return apiTestRunLocks.held[key]
+}
+
+// apiTestAbortedRunResult 返回被中止用例在汇总中的结果。
+func apiTestAbortedRunResult(caseRecord *core.Record, collectionRecord *core.Record) apiTestRunResult {
+	return apiTestRunResult{
+		CaseId:       caseRecord.Id,
+		CollectionId: collectionRecord.Id,
+		Name:         caseRecord.GetString("name"),
+		Error:        errApiTestRunAborted.Error(),
+		Aborted:      true,
+	}
+}
+
+// abortApiTestRun 中止执行中的合集执行（collectionId）或全部执行/定时巡检（collectionId 为空）。
+// 中止是异步的：执行请求在进行中的请求被中止后返回汇总，本接口只确认已发出中止。与 /jobs/cancel 一样要求可写权限。
+func (h *Hub) abortApiTestRun(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload apiTestAbortRunRequest
+	if err := apiTestParseBody(e, &payload); err != nil && !errors.Is(err, io.EOF) {
+		h.logApiTestError("解析中止执行请求失败", err)
+		return respondError(e, http.StatusBadRequest, formatApiTestError("解析中止执行请求失败", err, nil).Error())
+	}
+	key := strings.TrimSpace(payload.CollectionId)
+	if key == "" {
+		key = apiTestRunLockAll
+	}
+	job := apiTestRunLockJob(key)
+	if job == nil {
+		return respondError(e, http.StatusNotFound, formatApiTestError("中止执行失败", errors.New("没有执行中的任务"), map[string]any{"run": key}).Error())
+	}
+	job.requestCancel()
+	return e.JSON(http.StatusOK, map[string]any{"status": "ok", "run": key, "jobId": job.id})
+}
@@ -0,0 +1,94 @@
+// Package hub 提供接口用例的成功率窗口告警。
+// alert_mode 为 success_rate 时，定时执行写入结果后按用例统计最近 alert_window_minutes 内执行记录的成功率，
+// 低于 alert_success_rate 时触发告警，恢复到阈值及以上时解除，替代连续失败次数判定。
+// 评估开销：每次定时执行对 api_test_runs 做一次按 (case, created) 索引范围的 COUNT/SUM 聚合，
+// 扫描行数等于窗口内该用例的执行次数（如 5 分钟间隔、60 分钟窗口约 12 行），手动执行不参与评估。
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	apiTestAlertModeConsecutive = "consecutive"
+	apiTestAlertModeSuccessRate = "success_rate"
+
+	apiTestDefaultAlertSuccessRate   = 95.0
+	apiTestDefaultAlertWindowMinutes = 60
+	apiTestMaxAlertWindowMinutes     = 7 * 24 * 60
+	// apiTestAlertRateMinRuns 为成功率判定所需的最少样本数，避免窗口内首次失败即以 0% 告警
+	apiTestAlertRateMinRuns = 3
+)
+
+// apiTestSuccessRateRule 为用例的成功率告警规则。
+type apiTestSuccessRateRule struct {
+	Threshold     float64
+	WindowMinutes int
+}
+
+// apiTestValidateAlertRule 校验告警模式与成功率规则，0 表示使用默认值。返回出错的字段名。
+func apiTestValidateAlertRule(mode string, successRate float64, windowMinutes int) (string, error) {
+	switch strings.TrimSpace(mode) {
+	case "", apiTestAlertModeConsecutive, apiTestAlertModeSuccessRate:
+	default:
+		return "alert_mode", fmt.Errorf("不支持的告警模式: %s", mode)
+	}
+	if successRate < 0 || successRate > 100 {
+		return "alert_success_rate", errors.New("成功率阈值必须在 0-100 之间")
+	}
+	if windowMinutes < 0 || windowMinutes > apiTestMaxAlertWindowMinutes {
+		return "alert_window_minutes", fmt.Errorf("统计窗口不能超过 %d 分钟", apiTestMaxAlertWindowMinutes)
+	}
+	return "", nil
+}
+
+// apiTestRecordSuccessRateRule 返回用例的成功率告警规则，alert_mode 不是 success_rate 时返回 nil。
+func apiTestRecordSuccessRateRule(caseRecord *core.Record) *apiTestSuccessRateRule {
+	if caseRecord.GetString("alert_mode") != apiTestAlertModeSuccessRate {
+		return nil
+	}
+	rule := &apiTestSuccessRateRule{
+		Threshold:     caseRecord.GetFloat("alert_success_rate"),
+		WindowMinutes: caseRecord.GetInt("alert_window_minutes"),
+	}
+	if rule.Threshold <= 0 {
+		rule.Threshold = apiTestDefaultAlertSuccessRate
+	}
+	if rule.WindowMinutes <= 0 {
+		rule.WindowMinutes = apiTestDefaultAlertWindowMinutes
+	}
+	return rule
+}
+
+// apiTestWindowSuccessRate 统计用例在窗口内已写入的执行记录并计入本次结果，返回成功率（百分比）与样本数。
+func apiTestWindowSuccessRate(app core.App, caseId string, windowMinutes int, currentSuccess bool) (float64, int, error) {
+	var row struct {
+		Total     int `db:"total"`
+		Successes int `db:"successes"`
+	}
+	cutoff := apiTestNowDateTime().Add(-time.Duration(windowMinutes) * time.Minute).String()
+	err := app.DB().NewQuery(
+		"SELECT COUNT(*) AS total, COALESCE(SUM(CASE WHEN success THEN 1 ELSE 0 END), 0) AS successes FROM " +
+			apiTestRunsCollection + " WHERE `case` = {:case} AND created >= {:cutoff}",
+	).Bind(dbx.Params{"case": caseId, "cutoff": cutoff}).One(&row)
+	if err != nil {
+		return 0, 0, err
+	}
+	row.Total++
+	if currentSuccess {
+		row.Successes++
+	}
+	return float64(row.Successes) * 100 / float64(row.Total), row.Total, nil
+}
+
+// apiTestFormatRate 按告警展示格式化百分比，保留至多一位小数。
+func apiTestFormatRate(rate float64) string {
+	text := fmt.Sprintf("%.1f", rate)
+	return strings.TrimSuffix(text, ".0") + "%"
+}
@@ -0,0 +1,216 @@
+// Package hub 提供接口用例的断言模板。
+// 断言模板（api_test_assertion_templates）保存一组可复用的响应断言，用例通过 assertion_templates 引用一个或多个模板。
+// 执行时读取模板的当前配置与用例自身断言合并，修改模板即作用于所有引用它的用例。合并规则：
+//   - 文本类断言（require_json、expected_body_contains、not_contains、expected_json_path）逐项叠加，用例与每个模板的断言都必须通过，
+//     模板断言在用例自身的响应体断言之后按引用顺序执行；
+//   - 数值限制（max_latency_ms、min_response_bytes、max_response_bytes）以用例配置的非零值为准，
+//     用例未配置时取引用列表中最后一个配置了该项的模板。
+//
+// 模板只作用于 HTTP 用例；TCP 探测仍只使用用例自身的延迟阈值。删除模板时会自动从引用它的用例中移除。
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const apiTestAssertionTemplatesCollection = "api_test_assertion_templates"
+
+// apiTestMaxAssertionTemplates 为单个用例可引用的模板数上限，与 assertion_templates 字段的 MaxSelect 一致。
+const apiTestMaxAssertionTemplates = 10
+
+// apiTestAssertionTemplate 为执行与生效配置展示使用的断言模板快照。
+type apiTestAssertionTemplate struct {
+	Id               string `json:"id"`
+	Name             string `json:"name"`
+	RequireJSON      bool   `json:"requireJson,omitempty"`
+	BodyContains     string `json:"expectedBodyContains,omitempty"`
+	NotContains      string `json:"notContains,omitempty"`
+	NotContainsRegex bool   `json:"notContainsRegex,omitempty"`
+	JSONPath         string `json:"expectedJsonPath,omitempty"`
+	JSONValue        string `json:"expectedJsonValue,omitempty"`
+	MaxLatencyMs     int    `json:"maxLatencyMs,omitempty"`
+	MinResponseBytes int    `json:"minResponseBytes,omitempty"`
+	MaxResponseBytes int    `json:"maxResponseBytes,omitempty"`
+}
+
+// apiTestAssertionLimits 为合并后的数值限制，0 表示不限制。
+type apiTestAssertionLimits struct {
+	MaxLatencyMs     int
+	MinResponseBytes int
+	MaxResponseBytes int
+}
+
+func apiTestAssertionTemplateFromRecord(record *core.Record) apiTestAssertionTemplate {
+	return apiTestAssertionTemplate{
+		Id:               record.Id,
+		Name:             record.GetString("name"),
+		RequireJSON:      record.GetBool("require_json"),
+		BodyContains:     record.GetString("expected_body_contains"),
+		NotContains:      record.GetString("not_contains"),
+		NotContainsRegex: record.GetBool("not_contains_regex"),
+		JSONPath:         strings.TrimSpace(record.GetString("expected_json_path")),
+		JSONValue:        record.GetString("expected_json_value"),
+		MaxLatencyMs:     record.GetInt("max_latency_ms"),
+		MinResponseBytes: record.GetInt("min_response_bytes"),
+		MaxResponseBytes: record.GetInt("max_response_bytes"),
+	}
+}
+
+// validateApiTestAssertionTemplate 在断言模板保存前校验断言配置，规则与用例上的同名字段一致。
+func (h *Hub) validateApiTestAssertionTemplate(e *core.RecordEvent) error {
+	if err := apiTestValidateNotContains(e.Record.GetString("not_contains"), e.Record.GetBool("not_contains_regex")); err != nil {
+		return validation.Errors{
+			"not_contains": validation.NewError("validation_invalid_not_contains", err.Error()),
+		}
+	}
+	if field, err := apiTestValidateBodyAssertion(e.Record.GetString("expected_json_path"), e.Record.GetString("expected_json_value")); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_body_assertion", err.Error()),
+		}
+	}
+	if field, err := apiTestValidateResponseSize(e.Record.GetInt("min_response_bytes"), e.Record.GetInt("max_response_bytes")); err != nil {
+		return validation.Errors{
+			field: validation.NewError("validation_invalid_response_size", err.Error()),
+		}
+	}
+	return e.Next()
+}
+
+// loadApiTestAssertionTemplates 按用例的引用顺序读取断言模板的当前配置，未引用模板时返回 nil。
+func (h *Hub) loadApiTestAssertionTemplates(caseRecord *core.Record) ([]apiTestAssertionTemplate, error) {
+	ids := caseRecord.GetStringSlice("assertion_templates")
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	records, err := h.FindRecordsByIds(apiTestAssertionTemplatesCollection, ids)
+	if err != nil {
+		return nil, fmt.Errorf("读取断言模板失败: %w", err)
+	}
+	byId := make(map[string]*core.Record, len(records))
+	for _, record := range records {
+		byId[record.Id] = record
+	}
+	templates := make([]apiTestAssertionTemplate, 0, len(ids))
+	for _, id := range ids {
+		record, ok := byId[id]
+		if !ok {
+			return nil, fmt.Errorf("断言模板 %s 不存在", id)
+		}
+		templates = append(templates, apiTestAssertionTemplateFromRecord(record))
+	}
+	return templates, nil
+}
+
+// apiTestMergeAssertionLimits 合并用例与模板的数值限制：用例的非零值优先，否则后引用的模板覆盖先引用的模板。
+func apiTestMergeAssertionLimits(caseRecord *core.Record, templates []apiTestAssertionTemplate) apiTestAssertionLimits {
+	var limits apiTestAssertionLimits
+	for _, template := range templates {
+		if template.MaxLatencyMs > 0 {
+			limits.MaxLatencyMs = template.MaxLatencyMs
+		}
+		if template.MinResponseBytes > 0 {
+			limits.MinResponseBytes = template.MinResponseBytes
+		}
+		if template.MaxResponseBytes > 0 {
+			limits.MaxResponseBytes = template.MaxResponseBytes
+		}
+	}
+	if value := caseRecord.GetInt("max_latency_ms"); value > 0 {
+		limits.MaxLatencyMs = value
+	}
+	if value := caseRecord.GetInt("min_response_bytes"); value > 0 {
+		limits.MinResponseBytes = value
+	}
+	if value := caseRecord.GetInt("max_response_bytes"); value > 0 {
+		limits.MaxResponseBytes = value
+	}
+	return limits
+}
+
+// apiTestAssertionTemplatesNeedBody 判断模板是否包含需要读取完整响应体的断言。
+func apiTestAssertionTemplatesNeedBody(templates []apiTestAssertionTemplate) bool {
+	for _, template := range templates {
+		if template.RequireJSON || template.BodyContains != "" || template.NotContains != "" || template.JSONPath != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// apiTestCheckAssertionTemplates 按引用顺序执行模板的文本类断言，返回第一个失败，错误带模板名称。
+func apiTestCheckAssertionTemplates(payload []byte, templates []apiTestAssertionTemplate) error {
+	for _, template := range templates {
+		if err := apiTestCheckAssertionTemplate(payload, template); err != nil {
+			return fmt.Errorf("断言模板 %s: %w", template.Name, err)
+		}
+	}
+	return nil
+}
+
+func apiTestCheckAssertionTemplate(payload []byte, template apiTestAssertionTemplate) error {
+	if template.RequireJSON {
+		if _, err := apiTestDecodeJSON(payload); err != nil {
+			return fmt.Errorf("响应体断言失败: 响应不是合法 JSON: %v", err)
+		}
+	}
+	if template.BodyContains != "" {
+		if err := apiTestCheckBodyContains(payload, template.BodyContains); err != nil {
+			return err
+		}
+	}
+	if template.NotContains != "" {
+		if err := apiTestCheckNotContains(payload, template.NotContains, template.NotContainsRegex); err != nil {
+			return err
+		}
+	}
+	if template.JSONPath != "" {
+		if err := apiTestCheckJSONPathValue(payload, template.JSONPath, template.JSONValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apiTestAssertionTemplateNames 返回模板 ID 到名称的映射，供导出按名称引用模板。
+func (h *Hub) apiTestAssertionTemplateNames() (map[string]string, error) {
+	records, err := h.FindAllRecords(apiTestAssertionTemplatesCollection)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(records))
+	for _, record := range records {
+		names[record.Id] = record.GetString("name")
+	}
+	return names, nil
+}
+
+// apiTestResolveAssertionTemplateIds 将导入数据中的模板名称转换为 ID，引用不存在的模板时返回错误。
+func apiTestResolveAssertionTemplateIds(names []string, idsByName map[string]string) ([]string, error) {
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		id, ok := idsByName[name]
+		if !ok {
+			return nil, fmt.Errorf("断言模板 %s 不存在", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// apiTestValidateAssertionTemplateRefs 校验导入数据中的模板引用列表，名称已归一化。
+func apiTestValidateAssertionTemplateRefs(names []string) error {
+	if len(names) > apiTestMaxAssertionTemplates {
+		return fmt.Errorf("最多引用 %d 个断言模板", apiTestMaxAssertionTemplates)
+	}
+	for _, name := range names {
+		if name == "" {
+			return errors.New("断言模板名称不能为空")
+		}
+	}
+	return nil
+}
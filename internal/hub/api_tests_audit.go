@@ -0,0 +1,326 @@
+// Package hub 提供接口用例与合集的配置变更审计。
+// 通过集合 API 创建、修改、删除用例或合集，以及导入、批量修改标签、批量移动用例时，记录变更人、变更的字段及新旧值，
+// 用于排查用例开始失败前是否有人改动过配置。执行状态字段（last_*、连续失败次数、告警状态等）与排序不计入审计。
+// 隐藏字段（认证密码、令牌、client secret）只记录是否变更；headers/params/variables 中标记为 secret 的值与默认敏感请求头的值
+// 记为脱敏占位值，其余字符串值再经过日志脱敏规则。审计记录按 audit_retention_days 保留，随执行记录一同清理。
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	apiTestConfigAuditsCollection    = "api_test_config_audits"
+	apiTestDefaultAuditRetentionDays = 90
+	apiTestMaxAuditRetentionDays     = 3650
+	apiTestAuditActionCreate         = "create"
+	apiTestAuditActionUpdate         = "update"
+	apiTestAuditActionDelete         = "delete"
+	apiTestAuditResourceCase         = "case"
+	apiTestAuditResourceCollection   = "collection"
+)
+
+// apiTestAuditIgnoredFields 为执行过程中维护的状态字段，不属于配置变更。以 last_ 开头的字段同样忽略。
+var apiTestAuditIgnoredFields = []string{
+	"id",
+	"created",
+	"updated",
+	"sort_order",
+	"consecutive_failures",
+	"alert_triggered",
+	"health_alert_triggered",
+	"tls_expires_at",
+	"tls_expiry_alert_triggered",
+}
+
+// apiTestConfigChange 为单个字段的变更，新建时 Old 为空，删除时不记录字段。
+type apiTestConfigChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+type apiTestConfigAuditItem struct {
+	Id           string                `json:"id"`
+	ResourceType string                `json:"resourceType"`
+	ResourceId   string                `json:"resourceId"`
+	ResourceName string                `json:"resourceName"`
+	Action       string                `json:"action"`
+	User         string                `json:"user"`
+	UserEmail    string                `json:"userEmail,omitempty"`
+	Changes      []apiTestConfigChange `json:"changes"`
+	Created      string                `json:"created"`
+}
+
+type apiTestConfigAuditsResponse struct {
+	Items      []apiTestConfigAuditItem `json:"items"`
+	Page       int                      `json:"page"`
+	PerPage    int                      `json:"perPage"`
+	TotalItems int                      `json:"totalItems"`
+	TotalPages int                      `json:"totalPages"`
+}
+
+// apiTestAuditResourceType 返回记录对应的审计资源类型。
+func apiTestAuditResourceType(record *core.Record) string {
+	if record.Collection().Name == apiTestCollectionsCollection {
+		return apiTestAuditResourceCollection
+	}
+	return apiTestAuditResourceCase
+}
+
+// apiTestAuditUserId 返回发起变更的用户 id；超级管理员等非 users 集合的身份不关联用户。
+func apiTestAuditUserId(auth *core.Record) string {
+	if auth == nil || auth.Collection().Name != "users" {
+		return ""
+	}
+	return auth.Id
+}
+
+func apiTestAuditIgnored(name string) bool {
+	return strings.HasPrefix(name, "last_") || slices.Contains(apiTestAuditIgnoredFields, name)
+}
+
+// apiTestAuditEmpty 判断字段值是否为空值，新建记录时不记录空字段。
+func apiTestAuditEmpty(encoded []byte) bool {
+	switch string(encoded) {
+	case "null", `""`, "0", "false", "[]", "{}":
+		return true
+	}
+	return false
+}
+
+// apiTestConfigDiff 比较 original 与 record 的配置字段，original 为 nil 表示新建。
+func (h *Hub) apiTestConfigDiff(original *core.Record, record *core.Record) []apiTestConfigChange {
+	changes := make([]apiTestConfigChange, 0)
+	for _, field := range record.Collection().Fields {
+		name := field.GetName()
+		if apiTestAuditIgnored(name) {
+			continue
+		}
+		newValue := record.Get(name)
+		newEncoded, _ := json.Marshal(newValue)
+		var oldValue any
+		oldEncoded := []byte("null")
+		if original != nil {
+			oldValue = original.Get(name)
+			oldEncoded, _ = json.Marshal(oldValue)
+		}
+		if string(oldEncoded) == string(newEncoded) || (original == nil && apiTestAuditEmpty(newEncoded)) {
+			continue
+		}
+		change := apiTestConfigChange{Field: name}
+		if original != nil {
+			change.Old = h.apiTestAuditValue(field, oldValue)
+		}
+		change.New = h.apiTestAuditValue(field, newValue)
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// apiTestAuditValue 返回写入审计的字段值，敏感值已脱敏。
+func (h *Hub) apiTestAuditValue(field core.Field, value any) any {
+	name := field.GetName()
+	encoded, _ := json.Marshal(value)
+	if field.GetHidden() {
+		if apiTestAuditEmpty(encoded) {
+			return ""
+		}
+		return apiTestRedactedValue
+	}
+	switch name {
+	case "headers", "params", "variables":
+		var items []apiTestKeyValue
+		if err := json.Unmarshal(encoded, &items); err != nil {
+			return apiTestRedactedValue
+		}
+		for index, item := range items {
+			_, sensitive := apiTestSensitiveHeaders[strings.ToLower(strings.TrimSpace(item.Key))]
+			if item.Secret || (name == "headers" && sensitive) {
+				items[index].Value = apiTestRedactedValue
+			} else {
+				items[index].Value = h.redactor.redact(item.Value)
+			}
+		}
+		return items
+	}
+	if text, ok := value.(string); ok {
+		return h.redactor.redact(text)
+	}
+	return value
+}
+
+// recordApiTestConfigAudit 记录一次配置变更。更新时 original 为保存前的记录（record.Original()）；
+// 没有配置字段变化的更新不记录。写入失败只记日志，不影响配置保存。
+func (h *Hub) recordApiTestConfigAudit(app core.App, userId string, action string, original *core.Record, record *core.Record) {
+	var changes []apiTestConfigChange
+	if action != apiTestAuditActionDelete {
+		changes = h.apiTestConfigDiff(original, record)
+		if action == apiTestAuditActionUpdate && len(changes) == 0 {
+			return
+		}
+	}
+	err := func() error {
+		collection, err := app.FindCollectionByNameOrId(apiTestConfigAuditsCollection)
+		if err != nil {
+			return err
+		}
+		audit := core.NewRecord(collection)
+		audit.Set("resource_type", apiTestAuditResourceType(record))
+		audit.Set("resource_id", record.Id)
+		audit.Set("resource_name", record.GetString("name"))
+		audit.Set("action", action)
+		audit.Set("user", userId)
+		audit.Set("changes", changes)
+		return app.Save(audit)
+	}()
+	if err != nil {
+		h.logApiTestError("记录配置变更失败", err, "resourceId", record.Id, "action", action)
+	}
+}
+
+// auditApiTestConfigCreate 在通过集合 API 创建用例或合集成功后记录审计。
+func (h *Hub) auditApiTestConfigCreate(e *core.RecordRequestEvent) error {
+	if err := e.Next(); err != nil {
+		return err
+	}
+	h.recordApiTestConfigAudit(e.App, apiTestAuditUserId(e.Auth), apiTestAuditActionCreate, nil, e.Record)
+	return nil
+}
+
+// auditApiTestConfigUpdate 在通过集合 API 修改用例或合集成功后记录变更的字段。
+func (h *Hub) auditApiTestConfigUpdate(e *core.RecordRequestEvent) error {
+	original := e.Record.Original()
+	if err := e.Next(); err != nil {
+		return err
+	}
+	h.recordApiTestConfigAudit(e.App, apiTestAuditUserId(e.Auth), apiTestAuditActionUpdate, original, e.Record)
+	return nil
+}
+
+// auditApiTestConfigDelete 在通过集合 API 删除用例或合集成功后记录审计。
+func (h *Hub) auditApiTestConfigDelete(e *core.RecordRequestEvent) error {
+	if err := e.Next(); err != nil {
+		return err
+	}
+	h.recordApiTestConfigAudit(e.App, apiTestAuditUserId(e.Auth), apiTestAuditActionDelete, nil, e.Record)
+	return nil
+}
+
+// apiTestAuditRetentionDays 返回审计记录保留天数，未配置时使用默认值。
+func apiTestAuditRetentionDays(config *core.Record) int {
+	days := config.GetInt("audit_retention_days")
+	if days <= 0 {
+		return apiTestDefaultAuditRetentionDays
+	}
+	return days
+}
+
+func apiTestValidateAuditRetentionDays(days int) error {
+	if days <= 0 || days > apiTestMaxAuditRetentionDays {
+		return fmt.Errorf("必须为 1-%d", apiTestMaxAuditRetentionDays)
+	}
+	return nil
+}
+
+// cleanupApiTestConfigAudits 删除超过保留天数的审计记录。
+func (h *Hub) cleanupApiTestConfigAudits(config *core.Record) error {
+	cutoff := apiTestNowDateTime().Add(-time.Duration(apiTestAuditRetentionDays(config)) * 24 * time.Hour)
+	_, err := h.DB().NewQuery("DELETE FROM " + apiTestConfigAuditsCollection + " WHERE created < {:cutoff}").Bind(dbx.Params{
+		"cutoff": cutoff.String(),
+	}).Execute()
+	return err
+}
+
+// listApiTestConfigAudits 返回用例或合集的配置变更历史，按时间倒序分页。
+// caseId 与 collectionId 均未指定时返回全部记录；指定 collectionId 时仅返回合集自身的变更。
+func (h *Hub) listApiTestConfigAudits(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	caseId := strings.TrimSpace(query.Get("caseId"))
+	collectionId := strings.TrimSpace(query.Get("collectionId"))
+	page := apiTestParseInt(query.Get("page"), 1)
+	perPage := apiTestParseInt(query.Get("perPage"), 50)
+	if perPage <= 0 {
+		perPage = 50
+	}
+	if perPage > apiTestMaxPerPage {
+		perPage = apiTestMaxPerPage
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if caseId != "" && collectionId != "" {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("caseId 与 collectionId 不能同时指定", fmt.Errorf("caseId=%s collectionId=%s", caseId, collectionId), nil).Error())
+	}
+	resourceType, resourceId := apiTestAuditResourceCase, caseId
+	if collectionId != "" {
+		resourceType, resourceId = apiTestAuditResourceCollection, collectionId
+	}
+	var exp dbx.Expression
+	filter := ""
+	params := dbx.Params{"type": resourceType, "id": resourceId}
+	if resourceId != "" {
+		filter = "resource_type = {:type} && resource_id = {:id}"
+		exp = dbx.HashExp{"resource_type": resourceType, "resource_id": resourceId}
+	}
+	totalItems64, err := h.CountRecords(apiTestConfigAuditsCollection, exp)
+	if err != nil {
+		h.logApiTestError("统计配置变更记录失败", err)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("统计配置变更记录失败", err, nil).Error())
+	}
+	totalItems := int(totalItems64)
+	totalPages := (totalItems + perPage - 1) / perPage
+	records, err := h.FindRecordsByFilter(apiTestConfigAuditsCollection, filter, "-created", perPage, (page-1)*perPage, params)
+	if err != nil {
+		h.logApiTestError("读取配置变更记录失败", err)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取配置变更记录失败", err, nil).Error())
+	}
+	emails := make(map[string]string)
+	items := make([]apiTestConfigAuditItem, 0, len(records))
+	for _, record := range records {
+		var changes []apiTestConfigChange
+		if err := record.UnmarshalJSONField("changes", &changes); err != nil {
+			h.logApiTestError("解析配置变更记录失败", err, "auditId", record.Id)
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("解析配置变更记录失败", err, map[string]any{"auditId": record.Id}).Error())
+		}
+		userId := record.GetString("user")
+		if _, ok := emails[userId]; !ok && userId != "" {
+			if user, err := h.FindRecordById("users", userId); err == nil {
+				emails[userId] = user.GetString("email")
+			}
+		}
+		items = append(items, apiTestConfigAuditItem{
+			Id:           record.Id,
+			ResourceType: record.GetString("resource_type"),
+			ResourceId:   record.GetString("resource_id"),
+			ResourceName: record.GetString("resource_name"),
+			Action:       record.GetString("action"),
+			User:         userId,
+			UserEmail:    emails[userId],
+			Changes:      apiTestNormalizeConfigChanges(changes),
+			Created:      apiTestDateTimeString(record.GetDateTime("created")),
+		})
+	}
+	return e.JSON(http.StatusOK, apiTestConfigAuditsResponse{
+		Items:      items,
+		Page:       page,
+		PerPage:    perPage,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	})
+}
+
+func apiTestNormalizeConfigChanges(items []apiTestConfigChange) []apiTestConfigChange {
+	if items == nil {
+		return []apiTestConfigChange{}
+	}
+	return items
+}
@@ -0,0 +1,60 @@
+// Package hub 提供接口用例的响应体包含断言与 JSON 路径断言。
+// expected_body_contains 要求响应体包含指定子串；expected_json_path 为点分隔路径（如 data.id），
+// 要求响应 JSON 中存在该路径，配置了 expected_json_value 时还要求取值相等。两者在状态码判定通过后执行，均未配置时不读取额外响应体。
+package hub
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// apiTestValidateBodyAssertion 校验响应体断言配置，返回出错的字段名。
+func apiTestValidateBodyAssertion(jsonPath string, jsonValue string) (string, error) {
+	if err := apiTestValidateJSONPath(jsonPath); err != nil {
+		return "expected_json_path", err
+	}
+	if strings.TrimSpace(jsonPath) == "" && jsonValue != "" {
+		return "expected_json_value", errors.New("配置期望值前需先配置 JSON 路径")
+	}
+	return "", nil
+}
+
+// apiTestCheckBodyContains 执行响应体包含断言。
+func apiTestCheckBodyContains(payload []byte, expected string) error {
+	if !bytes.Contains(payload, []byte(expected)) {
+		return fmt.Errorf("响应体断言失败: 未包含 %q", expected)
+	}
+	return nil
+}
+
+// apiTestCheckJSONPathValue 执行 JSON 路径断言。expectedValue 为空时只要求路径存在；
+// 否则字符串值按原文比较，其他值按 JSON 编码比较（如 42、true、null）。
+func apiTestCheckJSONPathValue(payload []byte, path string, expectedValue string) error {
+	path = strings.TrimSpace(path)
+	data, err := apiTestDecodeJSON(payload)
+	if err != nil {
+		return fmt.Errorf("响应体断言失败: 响应不是合法 JSON: %v", err)
+	}
+	value, ok := apiTestLookupJSONPath(data, path)
+	if !ok {
+		return fmt.Errorf("响应体断言失败: 路径 %s 不存在", path)
+	}
+	if expectedValue == "" {
+		return nil
+	}
+	actual, ok := value.(string)
+	if !ok {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("响应体断言失败: 路径 %s 的值无法编码: %v", path, err)
+		}
+		actual = string(encoded)
+	}
+	if actual != expectedValue {
+		return fmt.Errorf("响应体断言失败: 路径 %s 期望 %s，实际 %s", path, expectedValue, actual)
+	}
+	return nil
+}
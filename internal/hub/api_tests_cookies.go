@@ -0,0 +1,118 @@
+// Package hub 提供接口响应 Cookie 断言。
+// 用例的 expected_cookies 为 Cookie 断言列表，执行时从响应的 Set-Cookie 头解析 Cookie：要求每个断言名称的 Cookie 存在，
+// 并按配置检查 Secure、HttpOnly 与 SameSite 属性。同名 Cookie 出现多次时以最后一次为准；失败时报告缺失的 Cookie 或属性。
+// Cookie 值不参与断言，也不会写入执行记录。
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const apiTestMaxCookieAssertions = 20
+
+// apiTestCookieSameSiteModes 为 SameSite 断言允许的取值，空字符串表示不检查。
+var apiTestCookieSameSiteModes = map[string]http.SameSite{
+	"lax":    http.SameSiteLaxMode,
+	"strict": http.SameSiteStrictMode,
+	"none":   http.SameSiteNoneMode,
+}
+
+// apiTestCookieAssertion 为单个 Cookie 断言。Secure、HttpOnly 为 true 时要求对应属性存在，为 false 时不检查。
+type apiTestCookieAssertion struct {
+	Name     string `json:"name"`
+	Secure   bool   `json:"secure,omitempty"`
+	HttpOnly bool   `json:"http_only,omitempty"`
+	SameSite string `json:"same_site,omitempty"`
+}
+
+func apiTestNormalizeCookieAssertions(items []apiTestCookieAssertion) []apiTestCookieAssertion {
+	if items == nil {
+		return []apiTestCookieAssertion{}
+	}
+	return items
+}
+
+// apiTestValidateCookieAssertions 校验 Cookie 断言：名称非空且不重复，SameSite 只能为 lax、strict、none 或空。
+func apiTestValidateCookieAssertions(items []apiTestCookieAssertion) error {
+	if len(items) > apiTestMaxCookieAssertions {
+		return fmt.Errorf("Cookie 断言不能超过 %d 个", apiTestMaxCookieAssertions)
+	}
+	seen := make(map[string]struct{}, len(items))
+	for index, item := range items {
+		name := strings.TrimSpace(item.Name)
+		if name == "" {
+			return fmt.Errorf("断言[%d]: Cookie 名称不能为空", index)
+		}
+		if strings.ContainsAny(name, " \t;=,") {
+			return fmt.Errorf("断言[%d]: Cookie 名称无效: %q", index, item.Name)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("断言[%d]: Cookie %s 重复", index, name)
+		}
+		seen[name] = struct{}{}
+		if _, ok := apiTestCookieSameSiteModes[strings.ToLower(strings.TrimSpace(item.SameSite))]; !ok && strings.TrimSpace(item.SameSite) != "" {
+			return fmt.Errorf("断言[%d]: SameSite 只能为 lax、strict 或 none", index)
+		}
+	}
+	return nil
+}
+
+// apiTestRecordCookieAssertions 读取用例的 expected_cookies，未配置时返回空列表。
+func apiTestRecordCookieAssertions(record *core.Record) ([]apiTestCookieAssertion, error) {
+	raw := strings.TrimSpace(record.GetString("expected_cookies"))
+	if raw == "" || raw == "null" {
+		return nil, nil
+	}
+	var items []apiTestCookieAssertion
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil, fmt.Errorf("expected_cookies 格式无效: %w", err)
+	}
+	return items, nil
+}
+
+// apiTestCheckCookies 按断言顺序检查响应的 Set-Cookie，返回第一个未满足的断言。
+func apiTestCheckCookies(response *http.Response, assertions []apiTestCookieAssertion) error {
+	cookies := make(map[string]*http.Cookie)
+	for _, cookie := range response.Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	for _, assertion := range assertions {
+		name := strings.TrimSpace(assertion.Name)
+		cookie, ok := cookies[name]
+		if !ok {
+			return fmt.Errorf("响应未设置 Cookie %s", name)
+		}
+		if assertion.Secure && !cookie.Secure {
+			return fmt.Errorf("Cookie %s 缺少 Secure 属性", name)
+		}
+		if assertion.HttpOnly && !cookie.HttpOnly {
+			return fmt.Errorf("Cookie %s 缺少 HttpOnly 属性", name)
+		}
+		sameSite := strings.ToLower(strings.TrimSpace(assertion.SameSite))
+		if sameSite == "" {
+			continue
+		}
+		if cookie.SameSite == http.SameSiteDefaultMode {
+			return fmt.Errorf("Cookie %s 缺少 SameSite 属性，期望 %s", name, sameSite)
+		}
+		if cookie.SameSite != apiTestCookieSameSiteModes[sameSite] {
+			return fmt.Errorf("Cookie %s 的 SameSite 属性不符: 期望 %s，实际 %s", name, sameSite, apiTestCookieSameSiteName(cookie.SameSite))
+		}
+	}
+	return nil
+}
+
+// apiTestCookieSameSiteName 返回 SameSite 属性的配置名称。
+func apiTestCookieSameSiteName(mode http.SameSite) string {
+	for name, value := range apiTestCookieSameSiteModes {
+		if value == mode {
+			return name
+		}
+	}
+	return "default"
+}
@@ -0,0 +1,51 @@
+// Package hub 提供接口用例的 GraphQL 请求体。
+// body_type 为 graphql 时，body 可以直接填写查询语句，也可以填写 {"query": ..., "variables": ..., "operationName": ...} 形式的 JSON；
+// 发送前统一封装为 GraphQL over HTTP 的 JSON 请求体，Content-Type 为 application/json。
+package hub
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+const apiTestBodyTypeGraphQL = "graphql"
+
+// apiTestGraphQLBody 为发送给 GraphQL 服务的请求体。
+type apiTestGraphQLBody struct {
+	Query         string          `json:"query"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+	OperationName string          `json:"operationName,omitempty"`
+}
+
+// apiTestParseGraphQLBody 解析用例的 GraphQL 请求体。body 为 JSON 对象时读取 query/variables/operationName，
+// 否则整体视为查询语句（GraphQL 的简写查询 "{ ... }" 不是合法 JSON，同样按查询语句处理）。
+func apiTestParseGraphQLBody(body string) (apiTestGraphQLBody, error) {
+	trimmed := strings.TrimSpace(body)
+	var payload apiTestGraphQLBody
+	if strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		if err := json.Unmarshal([]byte(trimmed), &payload); err != nil {
+			return apiTestGraphQLBody{}, errors.New("GraphQL 请求体格式不正确")
+		}
+		variables := bytes.TrimSpace(payload.Variables)
+		if len(variables) > 0 && !bytes.Equal(variables, []byte("null")) && variables[0] != '{' {
+			return apiTestGraphQLBody{}, errors.New("GraphQL variables 必须为 JSON 对象")
+		}
+	} else {
+		payload.Query = trimmed
+	}
+	if strings.TrimSpace(payload.Query) == "" {
+		return apiTestGraphQLBody{}, errors.New("GraphQL query 不能为空")
+	}
+	return payload, nil
+}
+
+// apiTestValidateGraphQLBody 校验 graphql 类型用例的请求体，其他类型不处理。
+func apiTestValidateGraphQLBody(bodyType string, body string) error {
+	if strings.ToLower(bodyType) != apiTestBodyTypeGraphQL {
+		return nil
+	}
+	_, err := apiTestParseGraphQLBody(body)
+	return err
+}
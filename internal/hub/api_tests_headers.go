@@ -0,0 +1,66 @@
+// Package hub 提供接口执行记录的响应头留存。
+// 每次执行保存响应头的受限子集：排除携带会话凭据的头，单个值截断到 apiTestMaxHeaderValueBytes，
+// 总大小不超过 apiTestMaxResponseHeadersBytes，按头名称排序依次保留，避免执行记录表膨胀。
+package hub
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	apiTestMaxHeaderValueBytes     = 512
+	apiTestMaxResponseHeadersBytes = 4096
+)
+
+// apiTestExcludedResponseHeaders 为不保存的响应头（规范化名称）。
+var apiTestExcludedResponseHeaders = map[string]struct{}{
+	"Set-Cookie":         {},
+	"Set-Cookie2":        {},
+	"Proxy-Authenticate": {},
+	"Authorization":      {},
+}
+
+// apiTestCaptureResponseHeaders 返回需要保存的响应头，多个值以 ", " 连接。没有可保存的头时返回 nil。
+func apiTestCaptureResponseHeaders(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(header))
+	for name := range header {
+		if _, excluded := apiTestExcludedResponseHeaders[http.CanonicalHeaderKey(name)]; !excluded {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	captured := make(map[string]string, len(names))
+	budget := apiTestMaxResponseHeadersBytes
+	for _, name := range names {
+		value := strings.Join(header.Values(name), ", ")
+		if len(value) > apiTestMaxHeaderValueBytes {
+			value = value[:apiTestMaxHeaderValueBytes] + "..."
+		}
+		size := len(name) + len(value)
+		if size > budget {
+			continue
+		}
+		budget -= size
+		captured[name] = value
+	}
+	if len(captured) == 0 {
+		return nil
+	}
+	return captured
+}
+
+// apiTestRecordResponseHeaders 读取执行记录保存的响应头，未保存或解析失败时返回 nil。
+func apiTestRecordResponseHeaders(record *core.Record) map[string]string {
+	var headers map[string]string
+	if err := record.UnmarshalJSONField("response_headers", &headers); err != nil || len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
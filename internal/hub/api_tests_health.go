@@ -0,0 +1,316 @@
+// Package hub 提供接口合集的加权健康度。
+// 合集健康度为合集内各用例最近 health_window_minutes 内执行成功率按 health_weight 加权的平均值（0-100），
+// 窗口内没有执行记录的用例不参与计算；合集内所有用例均无记录时健康度为空。
+// 合集配置 health_alert_threshold 后，定时执行写入结果后评估本次涉及的合集，健康度低于阈值时触发告警，
+// 恢复到阈值及以上时解除；告警状态记录在合集的 health_alert_triggered 上。
+// 系统汇总中的健康度按同样方式对关联到该系统的用例加权计算，各用例使用所属合集的统计窗口。
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"aether/internal/alerts"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	// apiTestAlertModeHealth 仅用于合集健康度告警，不是用例可选的 alert_mode
+	apiTestAlertModeHealth = "health"
+
+	apiTestDefaultHealthWeight        = 1
+	apiTestMaxHealthWeight            = 100
+	apiTestDefaultHealthWindowMinutes = 60
+	apiTestMaxHealthWindowMinutes     = 7 * 24 * 60
+)
+
+// apiTestCaseHealth 为用例在统计窗口内的成功率，SuccessRate 在窗口内无执行记录时为空。
+type apiTestCaseHealth struct {
+	CaseId      string   `json:"caseId"`
+	Name        string   `json:"name"`
+	Weight      int      `json:"weight"`
+	Total       int      `json:"total"`
+	SuccessRate *float64 `json:"successRate"`
+}
+
+// apiTestCollectionHealth 为合集的加权健康度，Score 在合集内所有用例均无执行记录时为空。
+type apiTestCollectionHealth struct {
+	CollectionId   string              `json:"collectionId"`
+	Name           string              `json:"name"`
+	Score          *float64            `json:"score"`
+	WindowMinutes  int                 `json:"windowMinutes"`
+	AlertThreshold float64             `json:"alertThreshold"`
+	BelowThreshold bool                `json:"belowThreshold"`
+	Cases          []apiTestCaseHealth `json:"cases"`
+}
+
+type apiTestHealthResponse struct {
+	Items []apiTestCollectionHealth `json:"items"`
+}
+
+type apiTestCaseRateRow struct {
+	Case      string `db:"case"`
+	Total     int    `db:"total"`
+	Successes int    `db:"successes"`
+}
+
+// apiTestValidateHealthWeight 校验用例的健康度权重，0 表示使用默认权重。
+func apiTestValidateHealthWeight(weight int) error {
+	if weight < 0 || weight > apiTestMaxHealthWeight {
+		return fmt.Errorf("健康度权重必须在 0-%d 之间", apiTestMaxHealthWeight)
+	}
+	return nil
+}
+
+// apiTestValidateHealthRule 校验合集的健康度统计窗口与告警阈值，0 表示使用默认窗口或不告警。返回出错的字段名。
+func apiTestValidateHealthRule(windowMinutes int, threshold float64) (string, error) {
+	if windowMinutes < 0 || windowMinutes > apiTestMaxHealthWindowMinutes {
+		return "health_window_minutes", fmt.Errorf("统计窗口不能超过 %d 分钟", apiTestMaxHealthWindowMinutes)
+	}
+	if threshold < 0 || threshold > 100 {
+		return "health_alert_threshold", errors.New("健康度告警阈值必须在 0-100 之间")
+	}
+	return "", nil
+}
+
+// apiTestHealthWeight 返回用例生效的健康度权重。
+func apiTestHealthWeight(caseRecord *core.Record) int {
+	if weight := caseRecord.GetInt("health_weight"); weight > 0 {
+		return weight
+	}
+	return apiTestDefaultHealthWeight
+}
+
+// apiTestHealthWindowMinutes 返回合集生效的健康度统计窗口。
+func apiTestHealthWindowMinutes(collectionRecord *core.Record) int {
+	if window := collectionRecord.GetInt("health_window_minutes"); window > 0 {
+		return window
+	}
+	return apiTestDefaultHealthWindowMinutes
+}
+
+// apiTestCaseWindowRates 按用例聚合窗口内的执行记录，返回用例 ID 到统计行的映射；无记录的用例不在结果中。
+func apiTestCaseWindowRates(app core.App, caseIds []any, windowMinutes int) (map[string]apiTestCaseRateRow, error) {
+	result := make(map[string]apiTestCaseRateRow, len(caseIds))
+	if len(caseIds) == 0 {
+		return result, nil
+	}
+	cutoff := apiTestNowDateTime().Add(-time.Duration(windowMinutes) * time.Minute).String()
+	var rows []apiTestCaseRateRow
+	err := app.DB().
+		Select("case", "COUNT(*) AS total", "COALESCE(SUM(CASE WHEN success THEN 1 ELSE 0 END), 0) AS successes").
+		From(apiTestRunsCollection).
+		Where(dbx.In("case", caseIds...)).
+		AndWhere(dbx.NewExp("created >= {:cutoff}", dbx.Params{"cutoff": cutoff})).
+		GroupBy("case").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.Case] = row
+	}
+	return result, nil
+}
+
+// apiTestWeightedScore 计算有执行记录的用例成功率的加权平均值，保留两位小数；没有可用样本时返回 nil。
+func apiTestWeightedScore(cases []apiTestCaseHealth) *float64 {
+	var weighted float64
+	var totalWeight int
+	for _, item := range cases {
+		if item.SuccessRate == nil {
+			continue
+		}
+		weighted += *item.SuccessRate * float64(item.Weight)
+		totalWeight += item.Weight
+	}
+	if totalWeight == 0 {
+		return nil
+	}
+	score := math.Round(weighted/float64(totalWeight)*100) / 100
+	return &score
+}
+
+// apiTestFormatScore 按告警展示格式化健康度，保留至多一位小数。
+func apiTestFormatScore(score float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", score), ".0")
+}
+
+// apiTestCaseHealthItems 将用例与窗口统计合并为健康度明细。
+func apiTestCaseHealthItems(cases []*core.Record, rates map[string]apiTestCaseRateRow) []apiTestCaseHealth {
+	items := make([]apiTestCaseHealth, 0, len(cases))
+	for _, caseRecord := range cases {
+		item := apiTestCaseHealth{
+			CaseId: caseRecord.Id,
+			Name:   caseRecord.GetString("name"),
+			Weight: apiTestHealthWeight(caseRecord),
+		}
+		if row, ok := rates[caseRecord.Id]; ok && row.Total > 0 {
+			rate := float64(row.Successes) * 100 / float64(row.Total)
+			item.Total = row.Total
+			item.SuccessRate = &rate
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// computeApiTestCollectionHealth 计算合集的加权健康度。
+func (h *Hub) computeApiTestCollectionHealth(collectionRecord *core.Record) (apiTestCollectionHealth, error) {
+	cases, err := h.FindRecordsByFilter(apiTestCasesCollection, "collection = {:collection}", "sort_order,created", -1, 0, dbx.Params{"collection": collectionRecord.Id})
+	if err != nil {
+		return apiTestCollectionHealth{}, err
+	}
+	window := apiTestHealthWindowMinutes(collectionRecord)
+	caseIds := make([]any, 0, len(cases))
+	for _, caseRecord := range cases {
+		caseIds = append(caseIds, caseRecord.Id)
+	}
+	rates, err := apiTestCaseWindowRates(h, caseIds, window)
+	if err != nil {
+		return apiTestCollectionHealth{}, err
+	}
+	health := apiTestCollectionHealth{
+		CollectionId:   collectionRecord.Id,
+		Name:           collectionRecord.GetString("name"),
+		WindowMinutes:  window,
+		AlertThreshold: collectionRecord.GetFloat("health_alert_threshold"),
+		Cases:          apiTestCaseHealthItems(cases, rates),
+	}
+	health.Score = apiTestWeightedScore(health.Cases)
+	health.BelowThreshold = health.AlertThreshold > 0 && health.Score != nil && *health.Score < health.AlertThreshold
+	return health, nil
+}
+
+// getApiTestHealth 处理 GET /api/aether/api-tests/health，可选参数 collection 限定单个合集。
+func (h *Hub) getApiTestHealth(e *core.RequestEvent) error {
+	collectionId := strings.TrimSpace(e.Request.URL.Query().Get("collection"))
+	var collections []*core.Record
+	if collectionId != "" {
+		record, err := h.FindRecordById(apiTestCollectionsCollection, collectionId)
+		if err != nil {
+			return respondError(e, http.StatusNotFound, formatApiTestError("合集不存在", err, map[string]any{"collectionId": collectionId}).Error())
+		}
+		collections = []*core.Record{record}
+	} else {
+		records, err := h.FindRecordsByFilter(apiTestCollectionsCollection, "", "sort_order,created", -1, 0, nil)
+		if err != nil {
+			h.logApiTestError("读取接口合集失败", err)
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("读取接口合集失败", err, nil).Error())
+		}
+		collections = records
+	}
+	response := apiTestHealthResponse{Items: make([]apiTestCollectionHealth, 0, len(collections))}
+	for _, record := range collections {
+		health, err := h.computeApiTestCollectionHealth(record)
+		if err != nil {
+			h.logApiTestError("计算合集健康度失败", err, "collectionId", record.Id)
+			return respondError(e, http.StatusInternalServerError, formatApiTestError("计算合集健康度失败", err, map[string]any{"collectionId": record.Id}).Error())
+		}
+		response.Items = append(response.Items, health)
+	}
+	return e.JSON(http.StatusOK, response)
+}
+
+// evaluateApiTestHealthAlerts 在定时执行写入结果后评估合集健康度告警，未配置阈值或窗口内无样本的合集保持原状态。
+func (h *Hub) evaluateApiTestHealthAlerts(collections map[string]*core.Record) error {
+	var errorsList []string
+	for _, collectionRecord := range collections {
+		threshold := collectionRecord.GetFloat("health_alert_threshold")
+		triggered := collectionRecord.GetBool("health_alert_triggered")
+		if threshold <= 0 && !triggered {
+			continue
+		}
+		health, err := h.computeApiTestCollectionHealth(collectionRecord)
+		if err != nil {
+			errorsList = append(errorsList, fmt.Sprintf("collection=%s: %v", collectionRecord.Id, err))
+			continue
+		}
+		var state alerts.NotificationState
+		switch {
+		case health.BelowThreshold && !triggered:
+			state = alerts.NotificationStateTriggered
+		case triggered && (threshold <= 0 || (health.Score != nil && !health.BelowThreshold)):
+			state = alerts.NotificationStateResolved
+		default:
+			continue
+		}
+		collectionRecord.Set("health_alert_triggered", state == alerts.NotificationStateTriggered)
+		if err := h.Save(collectionRecord); err != nil {
+			errorsList = append(errorsList, fmt.Sprintf("collection=%s: %v", collectionRecord.Id, err))
+			continue
+		}
+		action := apiTestAlertAction{
+			ShouldSend:      true,
+			State:           state,
+			CaseName:        health.Name,
+			DurationMinutes: health.WindowMinutes,
+			Mode:            apiTestAlertModeHealth,
+			RateThreshold:   threshold,
+		}
+		if health.Score != nil {
+			action.SuccessRate = *health.Score
+		}
+		if err := h.sendApiTestAlert(action); err != nil {
+			h.logApiTestError("发送合集健康度告警失败", err, "collectionId", collectionRecord.Id)
+		}
+	}
+	if len(errorsList) > 0 {
+		return errors.New(strings.Join(errorsList, " | "))
+	}
+	return nil
+}
+
+// apiTestSystemHealthScores 计算各系统关联用例的加权健康度，没有可用样本的系统不在结果中。
+func (h *Hub) apiTestSystemHealthScores(systemIDs []any) (map[string]float64, error) {
+	result := make(map[string]float64, len(systemIDs))
+	if len(systemIDs) == 0 {
+		return result, nil
+	}
+	cases, err := h.FindAllRecords(apiTestCasesCollection, dbx.In("system", systemIDs...))
+	if err != nil {
+		return nil, err
+	}
+	windows := make(map[string]int)
+	casesByWindow := make(map[int][]*core.Record)
+	for _, caseRecord := range cases {
+		collectionId := caseRecord.GetString("collection")
+		window, ok := windows[collectionId]
+		if !ok {
+			collectionRecord, err := h.FindRecordById(apiTestCollectionsCollection, collectionId)
+			if err != nil {
+				return nil, err
+			}
+			window = apiTestHealthWindowMinutes(collectionRecord)
+			windows[collectionId] = window
+		}
+		casesByWindow[window] = append(casesByWindow[window], caseRecord)
+	}
+	itemsBySystem := make(map[string][]apiTestCaseHealth)
+	for window, windowCases := range casesByWindow {
+		caseIds := make([]any, 0, len(windowCases))
+		for _, caseRecord := range windowCases {
+			caseIds = append(caseIds, caseRecord.Id)
+		}
+		rates, err := apiTestCaseWindowRates(h, caseIds, window)
+		if err != nil {
+			return nil, err
+		}
+		for index, item := range apiTestCaseHealthItems(windowCases, rates) {
+			systemID := windowCases[index].GetString("system")
+			itemsBySystem[systemID] = append(itemsBySystem[systemID], item)
+		}
+	}
+	for systemID, items := range itemsBySystem {
+		if score := apiTestWeightedScore(items); score != nil {
+			result[systemID] = *score
+		}
+	}
+	return result, nil
+}
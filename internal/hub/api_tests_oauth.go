@@ -0,0 +1,312 @@
+// Package hub 提供接口测试合集级的 OAuth2 令牌获取。
+// 合集配置 oauth_token_url 与 oauth_client_id 后，执行用例前按 client_credentials 流程获取访问令牌，
+// 以 "Authorization: Bearer <token>" 注入请求（用例自身配置了 Authorization 时不注入）。
+// 令牌按合集缓存在内存中：响应带 expires_in 时提前 30 秒过期；不带时一直复用，直到目标接口返回 401。
+// 目标接口返回 401 时强制刷新令牌并重发一次请求。合集配置变更后缓存自动失效。
+// client secret 以 API_TEST_SECRET_KEY 加密存储，以 client_secret_post 方式（表单参数）提交给令牌端点。
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"aether/internal/common"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+const (
+	apiTestSecretKeyEnv                      = "API_TEST_SECRET_KEY"
+	apiTestOAuthGrantClientCredentials       = "client_credentials"
+	apiTestOAuthTimeout                      = 10 * time.Second
+	apiTestOAuthExpirySkew                   = 30 * time.Second
+	apiTestOAuthMaxResponseBytes       int64 = 64 << 10
+	// apiTestOAuthMaxErrorBodyChars 为错误信息中引用令牌端点响应的长度上限
+	apiTestOAuthMaxErrorBodyChars = 300
+)
+
+// apiTestExportOAuth 为导入导出中的合集 OAuth2 配置。导出不包含 client_secret；
+// 导入时可选提供明文 client_secret（保存时加密），未提供时保留已有值。
+type apiTestExportOAuth struct {
+	TokenURL     string `json:"token_url"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	GrantType    string `json:"grant_type,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+type apiTestOAuthSource struct {
+	collectionId  string
+	tokenURL      string
+	clientID      string
+	secret        string // 加密后的 client secret
+	scope         string
+	proxyURL      string // 合集的代理地址，令牌请求与用例请求经同一代理发出
+	proxyPassword string // 加密后的代理密码
+}
+
+type apiTestOAuthToken struct {
+	fingerprint string
+	value       string
+	expiresAt   time.Time // 零值表示令牌端点未返回 expires_in
+}
+
+type apiTestOAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// apiTestOAuthTokens 按合集 id 缓存访问令牌
+var apiTestOAuthTokens = struct {
+	sync.Mutex
+	items map[string]apiTestOAuthToken
+}{items: make(map[string]apiTestOAuthToken)}
+
+func (h *Hub) getApiTestSecretKey() (string, error) {
+	key, ok := GetEnv(apiTestSecretKeyEnv)
+	if !ok || strings.TrimSpace(key) == "" {
+		return "", fmt.Errorf("缺少加密密钥环境变量: %s（或 AETHER_HUB_%s）", apiTestSecretKeyEnv, apiTestSecretKeyEnv)
+	}
+	if len(key) != 32 {
+		return "", fmt.Errorf("加密密钥长度无效: 需要 32 字节，实际 %d", len(key))
+	}
+	return key, nil
+}
+
+func (h *Hub) encryptApiTestSecret(value string) (string, error) {
+	key, err := h.getApiTestSecretKey()
+	if err != nil {
+		return "", err
+	}
+	return security.Encrypt([]byte(value), key)
+}
+
+func (h *Hub) decryptApiTestSecret(value string) (string, error) {
+	key, err := h.getApiTestSecretKey()
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := security.Decrypt(value, key)
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
+
+// apiTestValidateOAuthConfig 校验合集的 OAuth2 配置，全部为空表示未配置，返回出错的字段名。
+func apiTestValidateOAuthConfig(tokenURL string, clientID string, grantType string) (string, error) {
+	tokenURL = strings.TrimSpace(tokenURL)
+	clientID = strings.TrimSpace(clientID)
+	if tokenURL == "" && clientID == "" {
+		return "", nil
+	}
+	if tokenURL == "" {
+		return "oauth_token_url", errors.New("配置 client id 时必须填写令牌地址")
+	}
+	if clientID == "" {
+		return "oauth_client_id", errors.New("配置令牌地址时必须填写 client id")
+	}
+	parsed, err := url.Parse(tokenURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "oauth_token_url", errors.New("令牌地址必须为 http/https 绝对地址")
+	}
+	if grantType != "" && grantType != apiTestOAuthGrantClientCredentials {
+		return "oauth_grant_type", fmt.Errorf("不支持的授权类型: %s", grantType)
+	}
+	return "", nil
+}
+
+// encryptApiTestCollectionSecret 加密合集创建/更新请求中新提交的 oauth_client_secret，未修改时保留已加密的值。
+func (h *Hub) encryptApiTestCollectionSecret(e *core.RecordRequestEvent) error {
+	secret := e.Record.GetString("oauth_client_secret")
+	if secret == "" || (!e.Record.IsNew() && secret == e.Record.Original().GetString("oauth_client_secret")) {
+		return e.Next()
+	}
+	encrypted, err := h.encryptApiTestSecret(secret)
+	if err != nil {
+		h.logApiTestError("加密 client secret 失败", err, "collectionId", e.Record.Id)
+		return e.BadRequestError("加密 client secret 失败: "+err.Error(), nil)
+	}
+	e.Record.Set("oauth_client_secret", encrypted)
+	return e.Next()
+}
+
+// apiTestExportOAuthFor 返回合集导出用的 OAuth2 配置（不含 client secret），未配置时返回 nil。
+func apiTestExportOAuthFor(record *core.Record) *apiTestExportOAuth {
+	tokenURL := strings.TrimSpace(record.GetString("oauth_token_url"))
+	clientID := strings.TrimSpace(record.GetString("oauth_client_id"))
+	if tokenURL == "" && clientID == "" {
+		return nil
+	}
+	return &apiTestExportOAuth{
+		TokenURL:  tokenURL,
+		ClientID:  clientID,
+		GrantType: record.GetString("oauth_grant_type"),
+		Scope:     record.GetString("oauth_scope"),
+	}
+}
+
+// applyApiTestImportOAuth 将导入的 OAuth2 配置写入合集记录；config 为 nil 时清空配置。
+// 提供 client_secret 时加密后写入，未提供时保留记录中已有的值。
+func (h *Hub) applyApiTestImportOAuth(record *core.Record, config *apiTestExportOAuth) error {
+	if config == nil {
+		config = &apiTestExportOAuth{}
+		record.Set("oauth_client_secret", "")
+	}
+	record.Set("oauth_token_url", strings.TrimSpace(config.TokenURL))
+	record.Set("oauth_client_id", strings.TrimSpace(config.ClientID))
+	record.Set("oauth_grant_type", config.GrantType)
+	record.Set("oauth_scope", strings.TrimSpace(config.Scope))
+	if config.ClientSecret != "" {
+		encrypted, err := h.encryptApiTestSecret(config.ClientSecret)
+		if err != nil {
+			return err
+		}
+		record.Set("oauth_client_secret", encrypted)
+	}
+	return nil
+}
+
+// apiTestOAuthSourceFor 返回合集的令牌配置，未配置时返回 nil。
+func apiTestOAuthSourceFor(collectionRecord *core.Record) *apiTestOAuthSource {
+	tokenURL := strings.TrimSpace(collectionRecord.GetString("oauth_token_url"))
+	clientID := strings.TrimSpace(collectionRecord.GetString("oauth_client_id"))
+	if tokenURL == "" || clientID == "" {
+		return nil
+	}
+	return &apiTestOAuthSource{
+		collectionId:  collectionRecord.Id,
+		tokenURL:      tokenURL,
+		clientID:      clientID,
+		secret:        collectionRecord.GetString("oauth_client_secret"),
+		scope:         strings.TrimSpace(collectionRecord.GetString("oauth_scope")),
+		proxyURL:      collectionRecord.GetString("proxy_url"),
+		proxyPassword: collectionRecord.GetString("proxy_password"),
+	}
+}
+
+func (s *apiTestOAuthSource) fingerprint() string {
+	return strings.Join([]string{s.tokenURL, s.clientID, s.secret, s.scope}, "\x00")
+}
+
+// apiTestOAuthToken 返回合集的访问令牌，缓存未命中、已过期、配置已变更或 forceRefresh 时重新获取。
+func (h *Hub) apiTestOAuthToken(source *apiTestOAuthSource, forceRefresh bool) (string, error) {
+	fingerprint := source.fingerprint()
+	if !forceRefresh {
+		apiTestOAuthTokens.Lock()
+		cached, ok := apiTestOAuthTokens.items[source.collectionId]
+		apiTestOAuthTokens.Unlock()
+		if ok && cached.fingerprint == fingerprint && (cached.expiresAt.IsZero() || time.Now().Before(cached.expiresAt)) {
+			return cached.value, nil
+		}
+	}
+	token, err := h.fetchApiTestOAuthToken(source)
+	if err != nil {
+		return "", err
+	}
+	token.fingerprint = fingerprint
+	apiTestOAuthTokens.Lock()
+	apiTestOAuthTokens.items[source.collectionId] = token
+	apiTestOAuthTokens.Unlock()
+	return token.value, nil
+}
+
+func (h *Hub) fetchApiTestOAuthToken(source *apiTestOAuthSource) (apiTestOAuthToken, error) {
+	if err := h.validateApiTestTarget(source.tokenURL); err != nil {
+		return apiTestOAuthToken{}, fmt.Errorf("令牌地址校验失败: %w", err)
+	}
+	if source.secret == "" {
+		return apiTestOAuthToken{}, errors.New("未配置 client secret")
+	}
+	secret, err := h.decryptApiTestSecret(source.secret)
+	if err != nil {
+		return apiTestOAuthToken{}, fmt.Errorf("解密 client secret 失败: %w", err)
+	}
+	form := url.Values{}
+	form.Set("grant_type", apiTestOAuthGrantClientCredentials)
+	form.Set("client_id", source.clientID)
+	form.Set("client_secret", secret)
+	if source.scope != "" {
+		form.Set("scope", source.scope)
+	}
+	request, err := http.NewRequest(http.MethodPost, source.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return apiTestOAuthToken{}, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	proxy, err := h.apiTestProxyFor(source.proxyURL, source.proxyPassword)
+	if err != nil {
+		return apiTestOAuthToken{}, err
+	}
+	client := apiTestHTTPClient(apiTestOAuthTimeout, request.URL.Hostname(), "", nil, proxy)
+	requestedAt := time.Now()
+	response, err := client.Do(request)
+	if err != nil {
+		return apiTestOAuthToken{}, err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, apiTestOAuthMaxResponseBytes))
+	if err != nil {
+		return apiTestOAuthToken{}, fmt.Errorf("读取令牌响应失败: %w", err)
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		// 响应可能回显 client_secret 等凭据，脱敏并截断后再写入错误信息
+		excerpt := apiTestTruncateText(common.RedactText(strings.TrimSpace(string(body))), apiTestOAuthMaxErrorBodyChars)
+		return apiTestOAuthToken{}, fmt.Errorf("令牌端点返回 %d: %s", response.StatusCode, excerpt)
+	}
+	var payload apiTestOAuthTokenResponse
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return apiTestOAuthToken{}, fmt.Errorf("解析令牌响应失败: %w", err)
+	}
+	if payload.AccessToken == "" {
+		return apiTestOAuthToken{}, errors.New("令牌响应缺少 access_token")
+	}
+	if payload.TokenType != "" && !strings.EqualFold(payload.TokenType, "bearer") {
+		return apiTestOAuthToken{}, fmt.Errorf("不支持的令牌类型: %s", payload.TokenType)
+	}
+	token := apiTestOAuthToken{value: payload.AccessToken}
+	if payload.ExpiresIn > 0 {
+		lifetime := time.Duration(payload.ExpiresIn) * time.Second
+		token.expiresAt = requestedAt.Add(lifetime - min(apiTestOAuthExpirySkew, lifetime/2))
+	}
+	return token, nil
+}
+
+// doApiTestRequestWithOAuth 为请求注入访问令牌后发送；目标接口返回 401 时强制刷新令牌，重建请求并重发一次。
+// 用例自身配置了 Authorization 时直接发送。
+func (h *Hub) doApiTestRequestWithOAuth(client *http.Client, request *http.Request, caseRecord *core.Record, collectionRecord *core.Record) (*http.Response, error) {
+	source := apiTestOAuthSourceFor(collectionRecord)
+	if source == nil || request.Header.Get("Authorization") != "" {
+		return client.Do(request)
+	}
+	token, err := h.apiTestOAuthToken(source, false)
+	if err != nil {
+		return nil, fmt.Errorf("获取访问令牌失败: %w", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	response, err := client.Do(request)
+	if err != nil || response.StatusCode != http.StatusUnauthorized {
+		return response, err
+	}
+	response.Body.Close()
+	token, err = h.apiTestOAuthToken(source, true)
+	if err != nil {
+		return nil, fmt.Errorf("刷新访问令牌失败: %w", err)
+	}
+	retry, err := h.buildApiTestRequest(caseRecord, collectionRecord)
+	if err != nil {
+		return nil, err
+	}
+	retry.Header.Set("Authorization", "Bearer "+token)
+	return client.Do(retry.WithContext(request.Context()))
+}
@@ -0,0 +1,338 @@
+// Package hub 提供 OpenAPI 3 文档导入。
+// 导入请求的 openapi 字段为 OpenAPI 3 文档（JSON 或 YAML 导入均可），转换为以 info.title 命名的合集与每个操作（方法 + 路径）一个用例，
+// 再与普通导入一样经 apiTestValidateImportData 校验并按 skip/overwrite 模式写入。转换规则：
+//   - base_url 取 servers 的第一项并以变量默认值替换占位符；相对地址不作为 base_url，而是拼接到各用例路径前；
+//   - 路径参数 {id} 转换为 {{id}} 并生成同名合集变量，有示例或默认值时启用，否则保持禁用待补全；
+//   - header/query 参数生成请求头与查询参数占位，取示例或默认值，仅必填参数启用；Accept、Content-Type、Authorization 按规范忽略；
+//   - expected_status 取最小的 2xx 响应码，仅声明 2XX 或未声明时为 200；请求体取 JSON 或表单内容的示例。
+//
+// 不支持的方法（OPTIONS、TRACE）跳过；参数只解析 #/components/parameters 下的本地引用。
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// apiTestOpenAPIDefaultTimeoutMs 与界面新建用例的默认超时一致。
+const apiTestOpenAPIDefaultTimeoutMs = 15000
+
+// apiTestOpenAPIMethods 为按文档顺序生成用例的方法列表，仅包含用例支持的方法。
+var apiTestOpenAPIMethods = []string{"get", "post", "put", "patch", "delete", "head"}
+
+var apiTestOpenAPIPathParam = regexp.MustCompile(`\{([^{}]+)\}`)
+
+type apiTestOpenAPIDocument struct {
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title string `json:"title"`
+	} `json:"info"`
+	Servers []struct {
+		URL       string `json:"url"`
+		Variables map[string]struct {
+			Default string `json:"default"`
+		} `json:"variables"`
+	} `json:"servers"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Parameters map[string]apiTestOpenAPIParameter `json:"parameters"`
+	} `json:"components"`
+}
+
+type apiTestOpenAPIParameter struct {
+	Ref      string `json:"$ref"`
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Example  any    `json:"example"`
+	Schema   *struct {
+		Default any `json:"default"`
+		Example any `json:"example"`
+	} `json:"schema"`
+}
+
+type apiTestOpenAPIMediaType struct {
+	Example  any `json:"example"`
+	Examples map[string]struct {
+		Value any `json:"value"`
+	} `json:"examples"`
+	Schema *struct {
+		Example any `json:"example"`
+	} `json:"schema"`
+}
+
+type apiTestOpenAPIOperation struct {
+	OperationID string                    `json:"operationId"`
+	Summary     string                    `json:"summary"`
+	Description string                    `json:"description"`
+	Tags        []string                  `json:"tags"`
+	Parameters  []apiTestOpenAPIParameter `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]apiTestOpenAPIMediaType `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]json.RawMessage `json:"responses"`
+}
+
+// apiTestOpenAPIToImportData 将 OpenAPI 3 文档转换为导入数据，结果仍需经 apiTestValidateImportData 校验。
+func apiTestOpenAPIToImportData(raw json.RawMessage) (apiTestExportPayload, error) {
+	var doc apiTestOpenAPIDocument
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return apiTestExportPayload{}, fmt.Errorf("解析 OpenAPI 文档失败: %w", err)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(doc.OpenAPI), "3.") {
+		return apiTestExportPayload{}, fmt.Errorf("仅支持 OpenAPI 3 文档，当前版本: %q", doc.OpenAPI)
+	}
+	collectionName := strings.TrimSpace(doc.Info.Title)
+	if collectionName == "" {
+		return apiTestExportPayload{}, errors.New("OpenAPI 文档缺少 info.title")
+	}
+	baseURL, pathPrefix := apiTestOpenAPIServer(doc)
+
+	collection := apiTestExportCollection{
+		Name:      collectionName,
+		BaseURL:   baseURL,
+		Tags:      []string{},
+		Variables: []apiTestKeyValue{},
+	}
+	variableNames := make(map[string]struct{})
+	cases := make([]apiTestExportCase, 0)
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		item := doc.Paths[path]
+		var shared []apiTestOpenAPIParameter
+		if rawParams, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(rawParams, &shared); err != nil {
+				return apiTestExportPayload{}, fmt.Errorf("解析路径 %s 的参数失败: %w", path, err)
+			}
+		}
+		for _, method := range apiTestOpenAPIMethods {
+			rawOperation, ok := item[method]
+			if !ok {
+				continue
+			}
+			var operation apiTestOpenAPIOperation
+			if err := json.Unmarshal(rawOperation, &operation); err != nil {
+				return apiTestExportPayload{}, fmt.Errorf("解析操作 %s %s 失败: %w", strings.ToUpper(method), path, err)
+			}
+			params, err := apiTestOpenAPIMergeParameters(doc, shared, operation.Parameters)
+			if err != nil {
+				return apiTestExportPayload{}, fmt.Errorf("解析操作 %s %s 的参数失败: %w", strings.ToUpper(method), path, err)
+			}
+			caseItem := apiTestExportCase{
+				Collection:      collectionName,
+				Name:            strings.TrimSpace(operation.OperationID),
+				Method:          strings.ToUpper(method),
+				URL:             pathPrefix + apiTestOpenAPIPathParam.ReplaceAllStringFunc(path, apiTestOpenAPIPlaceholder),
+				Description:     strings.TrimSpace(operation.Summary),
+				Headers:         []apiTestKeyValue{},
+				Params:          []apiTestKeyValue{},
+				BodyType:        "json",
+				ExpectedStatus:  apiTestOpenAPIExpectedStatus(operation.Responses),
+				TimeoutMs:       apiTestOpenAPIDefaultTimeoutMs,
+				ScheduleMinutes: apiTestDefaultIntervalMinutes,
+				SortOrder:       len(cases),
+				Tags:            apiTestNormalizeStringList(operation.Tags),
+				AlertThreshold:  apiTestDefaultAlertThreshold,
+			}
+			if caseItem.Name == "" {
+				caseItem.Name = caseItem.Method + " " + path
+			}
+			if caseItem.Description == "" {
+				caseItem.Description = strings.TrimSpace(operation.Description)
+			}
+			for _, param := range params {
+				value := apiTestOpenAPIParameterValue(param)
+				switch param.In {
+				case "path":
+					if _, ok := variableNames[param.Name]; ok || !apiTestVariableName.MatchString(param.Name) {
+						continue
+					}
+					variableNames[param.Name] = struct{}{}
+					collection.Variables = append(collection.Variables, apiTestKeyValue{Key: param.Name, Value: value, Enabled: value != ""})
+				case "header":
+					switch strings.ToLower(param.Name) {
+					case "accept", "content-type", "authorization":
+						continue
+					}
+					caseItem.Headers = append(caseItem.Headers, apiTestKeyValue{Key: param.Name, Value: value, Enabled: param.Required})
+				case "query":
+					caseItem.Params = append(caseItem.Params, apiTestKeyValue{Key: param.Name, Value: value, Enabled: param.Required})
+				}
+			}
+			if operation.RequestBody != nil {
+				caseItem.BodyType, caseItem.Body = apiTestOpenAPIRequestBody(operation.RequestBody.Content)
+			}
+			cases = append(cases, caseItem)
+		}
+	}
+	return apiTestExportPayload{
+		Collections: []apiTestExportCollection{collection},
+		Cases:       cases,
+	}, nil
+}
+
+// apiTestOpenAPIServer 返回 base_url 与需拼接到用例路径前的前缀：绝对地址作为 base_url，相对地址作为路径前缀。
+func apiTestOpenAPIServer(doc apiTestOpenAPIDocument) (string, string) {
+	if len(doc.Servers) == 0 {
+		return "", ""
+	}
+	server := doc.Servers[0]
+	resolved := apiTestOpenAPIPathParam.ReplaceAllStringFunc(strings.TrimSpace(server.URL), func(match string) string {
+		if variable, ok := server.Variables[match[1:len(match)-1]]; ok {
+			return variable.Default
+		}
+		return match
+	})
+	resolved = strings.TrimSuffix(resolved, "/")
+	if parsed, err := url.Parse(resolved); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		return resolved, ""
+	}
+	if resolved != "" && !strings.HasPrefix(resolved, "/") {
+		resolved = "/" + resolved
+	}
+	return "", resolved
+}
+
+// apiTestOpenAPIPlaceholder 将路径参数 {name} 转换为合集变量占位符，变量名不合法时保持原样。
+func apiTestOpenAPIPlaceholder(match string) string {
+	name := match[1 : len(match)-1]
+	if !apiTestVariableName.MatchString(name) {
+		return match
+	}
+	return "{{" + name + "}}"
+}
+
+// apiTestOpenAPIMergeParameters 合并路径级与操作级参数（同名同位置时操作级覆盖），并解析本地引用。
+func apiTestOpenAPIMergeParameters(doc apiTestOpenAPIDocument, shared []apiTestOpenAPIParameter, own []apiTestOpenAPIParameter) ([]apiTestOpenAPIParameter, error) {
+	merged := make([]apiTestOpenAPIParameter, 0, len(shared)+len(own))
+	index := make(map[string]int)
+	for _, param := range slices.Concat(shared, own) {
+		if param.Ref != "" {
+			name, ok := strings.CutPrefix(param.Ref, "#/components/parameters/")
+			if !ok {
+				return nil, fmt.Errorf("不支持的参数引用: %s", param.Ref)
+			}
+			resolved, ok := doc.Components.Parameters[name]
+			if !ok {
+				return nil, fmt.Errorf("参数引用不存在: %s", param.Ref)
+			}
+			param = resolved
+		}
+		param.Name = strings.TrimSpace(param.Name)
+		if param.Name == "" {
+			continue
+		}
+		key := param.In + ":" + param.Name
+		if position, ok := index[key]; ok {
+			merged[position] = param
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, param)
+	}
+	return merged, nil
+}
+
+// apiTestOpenAPIParameterValue 依次取参数示例、schema 示例与 schema 默认值，均未提供时返回空字符串。
+func apiTestOpenAPIParameterValue(param apiTestOpenAPIParameter) string {
+	candidates := []any{param.Example}
+	if param.Schema != nil {
+		candidates = append(candidates, param.Schema.Example, param.Schema.Default)
+	}
+	for _, candidate := range candidates {
+		switch typed := candidate.(type) {
+		case nil:
+			continue
+		case string:
+			return typed
+		default:
+			encoded, err := json.Marshal(typed)
+			if err == nil {
+				return string(encoded)
+			}
+		}
+	}
+	return ""
+}
+
+// apiTestOpenAPIExpectedStatus 返回最小的 2xx 响应码，未声明具体 2xx 响应码时返回 200。
+func apiTestOpenAPIExpectedStatus(responses map[string]json.RawMessage) int {
+	status := 0
+	for code := range responses {
+		value, err := strconv.Atoi(code)
+		if err != nil || value < 200 || value > 299 {
+			continue
+		}
+		if status == 0 || value < status {
+			status = value
+		}
+	}
+	if status == 0 {
+		return 200
+	}
+	return status
+}
+
+// apiTestOpenAPIRequestBody 按内容类型生成请求体：优先 JSON，其次表单，均取示例值；无示例的表单请求体为空对象。
+func apiTestOpenAPIRequestBody(content map[string]apiTestOpenAPIMediaType) (string, string) {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	// application/json 优先，其余 JSON 类型（如 application/merge-patch+json）按名称顺序
+	slices.Sort(mediaTypes)
+	if index := slices.Index(mediaTypes, "application/json"); index > 0 {
+		mediaTypes = slices.Insert(slices.Delete(mediaTypes, index, index+1), 0, "application/json")
+	}
+	for _, mediaType := range mediaTypes {
+		if !strings.Contains(strings.ToLower(mediaType), "json") {
+			continue
+		}
+		media := content[mediaType]
+		if example := apiTestOpenAPIMediaExample(media); example != nil {
+			if encoded, err := json.MarshalIndent(example, "", "  "); err == nil {
+				return "json", string(encoded)
+			}
+		}
+		return "json", ""
+	}
+	if media, ok := content["application/x-www-form-urlencoded"]; ok {
+		if example, ok := apiTestOpenAPIMediaExample(media).(map[string]any); ok {
+			if encoded, err := json.Marshal(example); err == nil {
+				return "form", string(encoded)
+			}
+		}
+		return "form", "{}"
+	}
+	return "json", ""
+}
+
+func apiTestOpenAPIMediaExample(media apiTestOpenAPIMediaType) any {
+	if media.Example != nil {
+		return media.Example
+	}
+	names := make([]string, 0, len(media.Examples))
+	for name := range media.Examples {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if value := media.Examples[name].Value; value != nil {
+			return value
+		}
+	}
+	if media.Schema != nil {
+		return media.Schema.Example
+	}
+	return nil
+}
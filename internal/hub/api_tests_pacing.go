@@ -0,0 +1,84 @@
+// Package hub 提供批量执行时按主机的请求节流。
+// 合集配置 pacing_ms 后，合集执行、全部执行与定时巡检在同一批次内对同一主机（不区分端口）的相邻请求至少间隔 pacing_ms，
+// 避免突发请求触发上游限流或 WAF。间隔以本次请求所属合集的配置为准，其他合集发往同一主机的请求同样计入最近请求时间。
+// 节流器按主机预约发送时间，并发执行的用例各自获得递增的时间槽，因此与并行执行同时启用时同一主机的请求仍按间隔串行发出，
+// 不同主机之间互不影响；用例自身的失败重试不经过节流器，由 retry_delay_ms 控制间隔。单用例执行与金丝雀运行不节流。
+package hub
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const apiTestMaxPacingMs = 60000
+
+// apiTestPacingSleep 为节流等待函数，测试中替换以避免真实等待。
+var apiTestPacingSleep = time.Sleep
+
+// apiTestHostPacer 记录批次内每个主机最近一次请求的预约时间，可被并发调用。
+type apiTestHostPacer struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+	now  func() time.Time
+}
+
+func newApiTestHostPacer() *apiTestHostPacer {
+	return &apiTestHostPacer{last: make(map[string]time.Time), now: time.Now}
+}
+
+// apiTestValidatePacing 校验合集的请求间隔，0 表示不节流。
+func apiTestValidatePacing(pacingMs int) error {
+	if pacingMs < 0 || pacingMs > apiTestMaxPacingMs {
+		return fmt.Errorf("请求间隔必须在 0-%d 毫秒之间", apiTestMaxPacingMs)
+	}
+	return nil
+}
+
+// apiTestCaseHost 返回用例请求的目标主机（小写），无法解析时返回空字符串，由执行阶段报告地址错误。
+func (h *Hub) apiTestCaseHost(caseRecord *core.Record, collectionRecord *core.Record) string {
+	if apiTestIsTCPProbe(caseRecord.GetString("probe_type")) {
+		host, _, err := apiTestParseTCPTarget(caseRecord.GetString("url"))
+		if err != nil {
+			return ""
+		}
+		return strings.ToLower(host)
+	}
+	targetURL, err := h.resolveApiTestURL(collectionRecord, caseRecord)
+	if err != nil {
+		return ""
+	}
+	if resolver, err := newApiTestVariableResolver(collectionRecord); err == nil {
+		targetURL = resolver.apply(targetURL)
+	}
+	parsed, err := url.Parse(targetURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// wait 为发往 host 的请求预约发送时间并等待到该时间：距该主机上一次请求不足 interval 时补足间隔。
+// interval 为 0 时不等待，但仍记录请求时间，供后续配置了间隔的请求参考。
+func (p *apiTestHostPacer) wait(host string, interval time.Duration) {
+	if host == "" {
+		return
+	}
+	p.mu.Lock()
+	now := p.now()
+	at := now
+	if last, ok := p.last[host]; ok && interval > 0 {
+		if next := last.Add(interval); next.After(at) {
+			at = next
+		}
+	}
+	p.last[host] = at
+	p.mu.Unlock()
+	if delay := at.Sub(now); delay > 0 {
+		apiTestPacingSleep(delay)
+	}
+}
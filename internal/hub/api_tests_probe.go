@@ -0,0 +1,132 @@
+// Package hub 提供接口用例的 TCP 连接延迟探测。
+// probe_type 为 tcp 的用例以 url 作为 host:port 目标，由 hub 测量 TCP 建连耗时；
+// 执行结果与 HTTP 用例一样写入 api_test_runs（duration_ms 即延迟序列），复用定时调度、告警、历史保留与统计接口。
+// max_latency_ms 为延迟阈值，超过视为失败，同样适用于 HTTP 用例。
+// ICMP 需要原始套接字权限，当前不支持。
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	apiTestProbeTypeHTTP = "http"
+	apiTestProbeTypeTCP  = "tcp"
+)
+
+// apiTestIsTCPProbe 判断用例是否为 TCP 探测，未设置 probe_type 的用例为 HTTP。
+func apiTestIsTCPProbe(probeType string) bool {
+	return strings.TrimSpace(probeType) == apiTestProbeTypeTCP
+}
+
+// apiTestParseTCPTarget 解析 TCP 探测目标，接受 host:port 或 tcp://host:port。
+func apiTestParseTCPTarget(raw string) (string, string, error) {
+	target := strings.TrimPrefix(strings.TrimSpace(raw), "tcp://")
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		return "", "", fmt.Errorf("TCP 目标需为 host:port: %v", err)
+	}
+	if strings.TrimSpace(host) == "" {
+		return "", "", errors.New("TCP 目标缺少主机名")
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil || portNumber < 1 || portNumber > 65535 {
+		return "", "", fmt.Errorf("TCP 端口无效: %s", port)
+	}
+	return host, port, nil
+}
+
+// apiTestValidateProbe 校验探测类型、TCP 目标与延迟阈值，返回出错的字段名。
+func apiTestValidateProbe(probeType string, target string, maxLatencyMs int) (string, error) {
+	switch strings.TrimSpace(probeType) {
+	case "", apiTestProbeTypeHTTP:
+	case apiTestProbeTypeTCP:
+		if _, _, err := apiTestParseTCPTarget(target); err != nil {
+			return "url", err
+		}
+	default:
+		return "probe_type", fmt.Errorf("不支持的探测类型: %s", probeType)
+	}
+	if maxLatencyMs < 0 || maxLatencyMs > apiTestMaxTimeoutMs {
+		return "max_latency_ms", fmt.Errorf("延迟阈值必须在 0-%d 毫秒之间", apiTestMaxTimeoutMs)
+	}
+	return "", nil
+}
+
+// apiTestCheckLatency 执行延迟阈值断言，maxLatencyMs 为 0 表示不限制。
+func apiTestCheckLatency(durationMs int, maxLatencyMs int) error {
+	if maxLatencyMs > 0 && durationMs > maxLatencyMs {
+		return fmt.Errorf("延迟断言失败: %dms 超过阈值 %dms", durationMs, maxLatencyMs)
+	}
+	return nil
+}
+
+// performApiTestTCPProbe 测量到目标的 TCP 建连耗时。域名先行解析且不计入耗时，配置了 resolve_ip 时直连该 IP。
+// 建连超时的 context 派生自 parent，用于与重试的总超时联动。
+func (h *Hub) performApiTestTCPProbe(parent context.Context, caseRecord *core.Record) apiTestExecutionResult {
+	result := apiTestExecutionResult{
+		RunAt:         apiTestNowDateTime(),
+		ResponseBytes: -1,
+	}
+	timeoutMs := caseRecord.GetInt("timeout_ms")
+	if timeoutMs <= 0 {
+		result.Error = "超时时间必须大于 0"
+		return result
+	}
+	host, port, err := apiTestParseTCPTarget(caseRecord.GetString("url"))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	dialHost := host
+	if resolveIP := strings.TrimSpace(caseRecord.GetString("resolve_ip")); resolveIP != "" {
+		dialHost = resolveIP
+	}
+	if err := h.validateApiTestHost(dialHost); err != nil {
+		result.Error = fmt.Sprintf("探测地址校验失败: %v", err)
+		return result
+	}
+	timeout := time.Duration(timeoutMs) * time.Millisecond
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	if net.ParseIP(dialHost) == nil {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, dialHost)
+		if err != nil {
+			result.Error = fmt.Sprintf("解析域名失败: %v", err)
+			return result
+		}
+		if len(addrs) == 0 {
+			result.Error = "解析域名失败: 无可用地址"
+			return result
+		}
+		// 校验实际连接的地址，避免前后两次解析结果不同（DNS rebinding）绕过 SSRF 过滤
+		if err := h.validateApiTestDialIP(dialHost, addrs[0].IP); err != nil {
+			result.Error = fmt.Sprintf("探测地址校验失败: %v", err)
+			return result
+		}
+		dialHost = addrs[0].IP.String()
+	}
+	dialer := &net.Dialer{}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(dialHost, port))
+	result.DurationMs = int(time.Since(start).Milliseconds())
+	if err != nil {
+		result.Error = fmt.Sprintf("TCP 连接失败: %v", err)
+		return result
+	}
+	_ = conn.Close()
+	result.Success = true
+	if err := apiTestCheckLatency(result.DurationMs, caseRecord.GetInt("max_latency_ms")); err != nil {
+		result.Success = false
+		result.Error = err.Error()
+	}
+	return result
+}
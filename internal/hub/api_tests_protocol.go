@@ -0,0 +1,92 @@
+// Package hub 提供接口用例的 HTTP 协议版本选择与断言。
+// http_protocol 为空时由 Go 客户端自动协商；http1 只允许 HTTP/1.1；http2 只允许 HTTP/2（https 经 ALPN 协商，http 使用 h2c 明文）；
+// http3 需要 QUIC 传输层。标准库不含 QUIC 实现，默认构建只编译了 http1 与 http2，
+// 引入 QUIC 库的构建在 init 中设置 apiTestHTTP3Transport 即可启用 http3。
+// 无论是否强制，实际协商的协议都写入执行记录的 protocol 字段；强制协议时响应协议不一致视为失败。
+package hub
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	apiTestProtocolHTTP1 = "http1"
+	apiTestProtocolHTTP2 = "http2"
+	apiTestProtocolHTTP3 = "http3"
+)
+
+// apiTestHTTP3Transport 构造 HTTP/3 传输层，为 nil 表示当前构建未包含 HTTP/3 支持。
+// HTTP/3 基于 UDP，不经过 resolve_ip 的 TCP 拨号覆盖。
+var apiTestHTTP3Transport func(tlsConfig *tls.Config) http.RoundTripper
+
+// apiTestProtocolMajor 为各协议选项对应的响应 ProtoMajor。
+var apiTestProtocolMajor = map[string]int{
+	apiTestProtocolHTTP1: 1,
+	apiTestProtocolHTTP2: 2,
+	apiTestProtocolHTTP3: 3,
+}
+
+// apiTestAvailableProtocols 返回当前构建可用的协议选项。
+func apiTestAvailableProtocols() []string {
+	protocols := []string{apiTestProtocolHTTP1, apiTestProtocolHTTP2}
+	if apiTestHTTP3Transport != nil {
+		protocols = append(protocols, apiTestProtocolHTTP3)
+	}
+	return protocols
+}
+
+// apiTestValidateHTTPProtocol 校验协议选项，空字符串表示自动协商。
+func apiTestValidateHTTPProtocol(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if _, ok := apiTestProtocolMajor[value]; !ok {
+		return fmt.Errorf("不支持的 HTTP 协议: %s", value)
+	}
+	if value == apiTestProtocolHTTP3 && apiTestHTTP3Transport == nil {
+		return fmt.Errorf("当前构建未包含 HTTP/3 支持，可用协议: %s", strings.Join(apiTestAvailableProtocols(), "/"))
+	}
+	return nil
+}
+
+// apiTestApplyHTTPProtocol 按协议选项替换客户端的传输层。
+func apiTestApplyHTTPProtocol(client *http.Client, protocol string) error {
+	protocol = strings.TrimSpace(protocol)
+	if protocol == "" {
+		return nil
+	}
+	if err := apiTestValidateHTTPProtocol(protocol); err != nil {
+		return err
+	}
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	switch protocol {
+	case apiTestProtocolHTTP3:
+		client.Transport = apiTestHTTP3Transport(transport.TLSClientConfig)
+		return nil
+	case apiTestProtocolHTTP1:
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP1(true)
+	case apiTestProtocolHTTP2:
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP2(true)
+		transport.Protocols.SetUnencryptedHTTP2(true)
+	}
+	client.Transport = transport
+	return nil
+}
+
+// apiTestCheckHTTPProtocol 断言响应使用了指定的协议。
+func apiTestCheckHTTPProtocol(protocol string, response *http.Response) error {
+	expected, ok := apiTestProtocolMajor[strings.TrimSpace(protocol)]
+	if !ok || response.ProtoMajor == expected {
+		return nil
+	}
+	return fmt.Errorf("协议断言失败: 期望 %s，实际 %s", protocol, response.Proto)
+}
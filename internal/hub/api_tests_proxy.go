@@ -0,0 +1,208 @@
+// Package hub 提供接口用例经 HTTP 代理发出请求。
+// 合集配置 proxy_url 后，该合集的 HTTP 用例与 OAuth 令牌请求经代理连接目标，支持 http/https/socks5 代理，
+// 代理需要认证时在地址中携带用户名与密码：保存时密码从地址中拆出，加密存入隐藏字段 proxy_password，
+// proxy_url 只保留用户名，请求时再解密拼回。SSRF 过滤仍按用例的实际目标地址校验（开启过滤时目标域名需在本机可解析），
+// 代理地址由管理员配置，不受过滤限制。
+//
+// 请求经代理时连接建立在代理上，resolve_ip 无法生效；HTTP/3 基于 UDP，不经过代理。两者与代理同时配置时用例执行失败。
+// TCP 探测不经过代理。未配置时行为不变，客户端仍沿用进程环境变量（HTTP_PROXY/HTTPS_PROXY/NO_PROXY）中的代理。
+// 导出时不含代理密码，导入或更新合集时若代理地址与用户名未变，保留已保存的密码。
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// apiTestParseProxyURL 解析合集的代理地址，空字符串返回 nil。
+func apiTestParseProxyURL(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("解析代理地址失败: %w", err)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.New("代理仅支持 http/https/socks5 协议")
+	}
+	if parsed.Hostname() == "" {
+		return nil, errors.New("代理地址缺少主机名")
+	}
+	if (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return nil, errors.New("代理地址不能包含路径或查询参数")
+	}
+	return parsed, nil
+}
+
+// apiTestValidateProxyURL 校验代理地址，空字符串表示不使用代理。
+func apiTestValidateProxyURL(raw string) error {
+	_, err := apiTestParseProxyURL(raw)
+	return err
+}
+
+// apiTestValidateStoredProxyURL 校验保存到合集的代理地址，密码须已拆出到 proxy_password。
+func apiTestValidateStoredProxyURL(raw string) error {
+	parsed, err := apiTestParseProxyURL(raw)
+	if err != nil || parsed == nil || parsed.User == nil {
+		return err
+	}
+	if _, ok := parsed.User.Password(); ok {
+		return errors.New("代理地址不能包含密码，请填写 proxy_password")
+	}
+	return nil
+}
+
+// apiTestCheckProxyOptions 校验与代理不兼容的用例配置。
+func apiTestCheckProxyOptions(resolveIP string, httpProtocol string) error {
+	if strings.TrimSpace(resolveIP) != "" {
+		return errors.New("合集配置了代理，用例不支持 resolve_ip")
+	}
+	if strings.TrimSpace(httpProtocol) == apiTestProtocolHTTP3 {
+		return errors.New("合集配置了代理，用例不支持 HTTP/3")
+	}
+	return nil
+}
+
+// apiTestRedactedProxyURL 返回隐藏密码后的代理地址，用于生效配置展示；hasPassword 表示已保存代理密码。无法解析时原样返回。
+func apiTestRedactedProxyURL(raw string, hasPassword bool) string {
+	parsed, err := apiTestParseProxyURL(raw)
+	if err != nil || parsed == nil {
+		return strings.TrimSpace(raw)
+	}
+	if hasPassword && parsed.User != nil {
+		parsed.User = url.UserPassword(parsed.User.Username(), "xxxxx")
+	}
+	return parsed.Redacted()
+}
+
+// apiTestExportProxyURL 返回移除密码后的代理地址，用户名保留。
+func apiTestExportProxyURL(raw string) string {
+	parsed, err := apiTestParseProxyURL(raw)
+	if err != nil || parsed == nil {
+		return strings.TrimSpace(raw)
+	}
+	if parsed.User != nil {
+		parsed.User = url.User(parsed.User.Username())
+	}
+	return parsed.String()
+}
+
+// apiTestSameProxyUser 判断两个代理地址的协议、主机与用户名是否一致（均需带用户名）。
+func apiTestSameProxyUser(next *url.URL, existing string) bool {
+	current, err := apiTestParseProxyURL(existing)
+	if err != nil || current == nil || current.User == nil || next.User == nil {
+		return false
+	}
+	return current.Scheme == next.Scheme && current.Host == next.Host && current.User.Username() == next.User.Username()
+}
+
+// setApiTestProxyURL 将代理地址写入合集记录：地址中的密码加密后存入 proxy_password，proxy_url 只保存用户名。
+// 地址不含密码时，协议、主机与用户名与 existing 一致则保留已保存的密码，否则清空。无法解析的地址原样写入，交由校验拒绝。
+func (h *Hub) setApiTestProxyURL(record *core.Record, raw string, existing string) error {
+	raw = strings.TrimSpace(raw)
+	parsed, err := apiTestParseProxyURL(raw)
+	if err != nil {
+		record.Set("proxy_url", raw)
+		return nil
+	}
+	if parsed == nil {
+		record.Set("proxy_url", "")
+		record.Set("proxy_password", "")
+		return nil
+	}
+	if parsed.User != nil {
+		if password, ok := parsed.User.Password(); ok {
+			encrypted, err := h.encryptApiTestSecret(password)
+			if err != nil {
+				return err
+			}
+			record.Set("proxy_url", apiTestExportProxyURL(raw))
+			record.Set("proxy_password", encrypted)
+			return nil
+		}
+	}
+	if !apiTestSameProxyUser(parsed, existing) {
+		record.Set("proxy_password", "")
+	}
+	record.Set("proxy_url", raw)
+	return nil
+}
+
+// encryptApiTestCollectionProxyPassword 处理合集创建/更新请求中的代理认证信息：直接提交的 proxy_password 加密保存，
+// proxy_url 中携带的密码拆出后加密保存；代理地址或用户名变更且未提供新密码时清空已保存的密码。
+func (h *Hub) encryptApiTestCollectionProxyPassword(e *core.RecordRequestEvent) error {
+	existing := ""
+	if !e.Record.IsNew() {
+		existing = e.Record.Original().GetString("proxy_url")
+	}
+	password := e.Record.GetString("proxy_password")
+	if password != "" && (e.Record.IsNew() || password != e.Record.Original().GetString("proxy_password")) {
+		encrypted, err := h.encryptApiTestSecret(password)
+		if err != nil {
+			h.logApiTestError("加密代理密码失败", err, "collectionId", e.Record.Id)
+			return e.BadRequestError("加密代理密码失败: "+err.Error(), nil)
+		}
+		e.Record.Set("proxy_password", encrypted)
+		// 与新密码一同提交的地址视为未变更，不清空刚写入的密码
+		existing = e.Record.GetString("proxy_url")
+	}
+	if err := h.setApiTestProxyURL(e.Record, e.Record.GetString("proxy_url"), existing); err != nil {
+		h.logApiTestError("加密代理密码失败", err, "collectionId", e.Record.Id)
+		return e.BadRequestError("加密代理密码失败: "+err.Error(), nil)
+	}
+	return e.Next()
+}
+
+// apiTestProxyFor 返回请求使用的代理地址，已保存密码时解密后拼入地址；未配置代理返回 nil。
+func (h *Hub) apiTestProxyFor(raw string, encryptedPassword string) (*url.URL, error) {
+	proxy, err := apiTestParseProxyURL(raw)
+	if err != nil || proxy == nil || encryptedPassword == "" {
+		return proxy, err
+	}
+	password, err := h.decryptApiTestSecret(encryptedPassword)
+	if err != nil {
+		return nil, fmt.Errorf("解密代理密码失败: %w", err)
+	}
+	username := ""
+	if proxy.User != nil {
+		username = proxy.User.Username()
+	}
+	proxy.User = url.UserPassword(username, password)
+	return proxy, nil
+}
+
+// moveApiTestProxyPasswords 将旧版本保存在 proxy_url 中的明文密码加密移入 proxy_password。
+// 缺少加密密钥时仍从地址中移除密码，避免明文继续暴露，需重新填写代理密码。
+func (h *Hub) moveApiTestProxyPasswords(app core.App) error {
+	records, err := app.FindRecordsByFilter(apiTestCollectionsCollection, "proxy_url ~ '@'", "", 0, 0)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		raw := record.GetString("proxy_url")
+		parsed, err := apiTestParseProxyURL(raw)
+		if err != nil || parsed == nil || parsed.User == nil {
+			continue
+		}
+		if _, ok := parsed.User.Password(); !ok {
+			continue
+		}
+		if err := h.setApiTestProxyURL(record, raw, ""); err != nil {
+			h.logApiTestError("加密代理密码失败，已从代理地址中移除，需重新填写", err, "collectionId", record.Id)
+			record.Set("proxy_url", apiTestExportProxyURL(raw))
+			record.Set("proxy_password", "")
+		}
+		if err := app.Save(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package hub 提供接口用例失败后的重试。
+// retry_count 大于 0 时，执行失败后等待 retry_delay_ms 再次执行，最多重试 retry_count 次；
+// 只有最终结果写入执行记录并参与连续失败计数与告警判定，金丝雀运行不重试。
+// timeout_ms 限制单次请求，total_timeout_ms 限制包含重试间隔在内的总耗时：单次请求的 context 派生自总超时的 context，
+// 因此最后一次请求同时受两者约束。总超时到达时不再发起新的重试（等待重试间隔期间到达则立即停止），
+// 记录最后一次执行的结果并注明已超过总超时。总超时不能小于单次超时，0 表示不限制。
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	apiTestMaxRetryCount     = 5
+	apiTestMaxRetryDelayMs   = 60000
+	apiTestMaxTotalTimeoutMs = 600000
+)
+
+// apiTestValidateRetry 校验重试次数与间隔，返回出错的字段名。
+func apiTestValidateRetry(retryCount int, retryDelayMs int) (string, error) {
+	if retryCount < 0 || retryCount > apiTestMaxRetryCount {
+		return "retry_count", fmt.Errorf("重试次数必须在 0-%d 之间", apiTestMaxRetryCount)
+	}
+	if retryDelayMs < 0 || retryDelayMs > apiTestMaxRetryDelayMs {
+		return "retry_delay_ms", fmt.Errorf("重试间隔必须在 0-%d 毫秒之间", apiTestMaxRetryDelayMs)
+	}
+	if retryCount == 0 && retryDelayMs > 0 {
+		return "retry_delay_ms", errors.New("未配置重试次数时不能配置重试间隔")
+	}
+	return "", nil
+}
+
+// apiTestValidateTotalTimeout 校验总超时，0 表示不限制；配置时不能小于单次超时。
+func apiTestValidateTotalTimeout(timeoutMs int, totalTimeoutMs int) error {
+	if totalTimeoutMs < 0 || totalTimeoutMs > apiTestMaxTotalTimeoutMs {
+		return fmt.Errorf("总超时必须在 0-%d 毫秒之间", apiTestMaxTotalTimeoutMs)
+	}
+	if totalTimeoutMs > 0 && totalTimeoutMs < timeoutMs {
+		return fmt.Errorf("总超时 %dms 不能小于单次超时 %dms", totalTimeoutMs, timeoutMs)
+	}
+	return nil
+}
+
+// performApiTestCaseWithRetry 执行用例，失败时按用例配置重试，返回最后一次的结果。
+// 配置了 total_timeout_ms 时所有尝试共用同一个总超时；总超时派生自 ctx，ctx 结束时进行中的请求随之中止且不再重试。
+func (h *Hub) performApiTestCaseWithRetry(ctx context.Context, caseRecord *core.Record, collectionRecord *core.Record) apiTestExecutionResult {
+	totalTimeoutMs := caseRecord.GetInt("total_timeout_ms")
+	if totalTimeoutMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(totalTimeoutMs)*time.Millisecond)
+		defer cancel()
+	}
+	result := h.performApiTestCaseContext(ctx, caseRecord, collectionRecord)
+	attempts := 1
+	retryCount := min(max(caseRecord.GetInt("retry_count"), 0), apiTestMaxRetryCount)
+	delay := time.Duration(caseRecord.GetInt("retry_delay_ms")) * time.Millisecond
+	for attempt := 1; attempt <= retryCount && !result.Success && ctx.Err() == nil; attempt++ {
+		if delay > 0 && !apiTestSleepContext(ctx, delay) {
+			break
+		}
+		result = h.performApiTestCaseContext(ctx, caseRecord, collectionRecord)
+		attempts++
+		if !result.Success && attempt == retryCount && ctx.Err() == nil {
+			result.Error = fmt.Sprintf("%s（已重试 %d 次）", result.Error, retryCount)
+		}
+	}
+	if !result.Success && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.Error = fmt.Sprintf("%s（deadline exceeded: 已超过总超时 %dms，共执行 %d 次）", result.Error, totalTimeoutMs, attempts)
+	}
+	return result
+}
+
+// apiTestSleepContext 等待 delay，ctx 先结束时返回 false。
+func apiTestSleepContext(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,178 @@
+// Package hub 提供全部执行的并行度与顺序选项。
+// 执行全部用例时可按次选择模式：
+//   - sequential（默认）：按合集、sort_order 依次执行，与逐个执行合集的顺序一致，请求对上游的压力最小，
+//     依赖前序用例副作用（如先创建后查询）的场景只能使用该模式；
+//   - collections：不同合集并行执行，合集内用例仍按 sort_order 依次执行，适合合集彼此独立、合集内存在顺序依赖的场景；
+//   - parallel：所有用例并行执行，总耗时最短，但用例之间不保证任何顺序。
+//
+// 并行模式下同时执行的合集或用例数不超过 concurrency（默认 4，最大 16）。并行只影响请求发出的顺序：
+// 同一主机仍受合集 pacing_ms 节流，执行结果仍按批次合并写入，返回的 results 按用例顺序排列而非完成顺序。
+// 全部执行期间仍持有执行锁。取消或中止后不再开始新的用例，已发出的请求随之中止，
+// 未完成的启用用例以 aborted 结果列出，返回已完成部分的汇总。
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	apiTestRunAllSequential  = "sequential"
+	apiTestRunAllCollections = "collections"
+	apiTestRunAllParallel    = "parallel"
+
+	apiTestRunAllDefaultConcurrency = 4
+	apiTestRunAllMaxConcurrency     = 16
+)
+
+// apiTestRunAllOptions 为全部执行的模式与并行度，零值表示按默认顺序依次执行。
+type apiTestRunAllOptions struct {
+	Mode        string `json:"mode"`
+	Concurrency int    `json:"concurrency"`
+}
+
+// normalize 校验选项并补全默认值。
+func (o apiTestRunAllOptions) normalize() (apiTestRunAllOptions, error) {
+	switch o.Mode {
+	case "":
+		o.Mode = apiTestRunAllSequential
+	case apiTestRunAllSequential, apiTestRunAllCollections, apiTestRunAllParallel:
+	default:
+		return o, fmt.Errorf("不支持的执行模式: %s", o.Mode)
+	}
+	if o.Concurrency < 0 || o.Concurrency > apiTestRunAllMaxConcurrency {
+		return o, fmt.Errorf("并行度必须在 1-%d 之间", apiTestRunAllMaxConcurrency)
+	}
+	if o.Concurrency == 0 {
+		o.Concurrency = apiTestRunAllDefaultConcurrency
+	}
+	if o.Mode == apiTestRunAllSequential {
+		o.Concurrency = 1
+	}
+	return o, nil
+}
+
+// apiTestRunAllGroups 按模式将用例下标划分为组：组内依次执行，组之间可并行。
+func apiTestRunAllGroups(cases []*core.Record, mode string) [][]int {
+	switch mode {
+	case apiTestRunAllCollections:
+		groups := make([][]int, 0)
+		positions := make(map[string]int)
+		for index, caseRecord := range cases {
+			collectionId := caseRecord.GetString("collection")
+			position, ok := positions[collectionId]
+			if !ok {
+				position = len(groups)
+				positions[collectionId] = position
+				groups = append(groups, nil)
+			}
+			groups[position] = append(groups[position], index)
+		}
+		return groups
+	case apiTestRunAllParallel:
+		groups := make([][]int, len(cases))
+		for index := range cases {
+			groups[index] = []int{index}
+		}
+		return groups
+	default:
+		all := make([]int, len(cases))
+		for index := range cases {
+			all[index] = index
+		}
+		return [][]int{all}
+	}
+}
+
+// apiTestRunAllOutcome 为单个用例在全部执行中的结果。
+type apiTestRunAllOutcome struct {
+	ran     bool
+	skipped bool
+	aborted bool
+	result  apiTestRunResult
+}
+
+// runApiTestAllGroups 以最多 concurrency 个 worker 执行各组用例，返回按用例下标排列的结果与是否被取消。
+// 被取消时执行中与未开始的启用用例标记为 aborted。任一用例写入失败时停止开始新的用例并返回该错误。
+func (h *Hub) runApiTestAllGroups(ctx context.Context, job *runningJob, batch *apiTestRunBatch, cases []*core.Record, collectionMap map[string]*core.Record, groups [][]int, concurrency int) ([]apiTestRunAllOutcome, bool, error) {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	outcomes := make([]apiTestRunAllOutcome, len(cases))
+	queue := make(chan []int)
+	var (
+		done     atomic.Int32
+		stopped  atomic.Bool
+		errOnce  sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	for range min(concurrency, max(len(groups), 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range queue {
+				for _, index := range group {
+					if runCtx.Err() != nil {
+						stopped.Store(true)
+						break
+					}
+					caseRecord := cases[index]
+					collectionRecord := collectionMap[caseRecord.GetString("collection")]
+					switch {
+					case collectionRecord == nil:
+					case !caseRecord.GetBool("enabled"):
+						outcomes[index].skipped = true
+					default:
+						result, runErr := batch.execute(runCtx, caseRecord, collectionRecord)
+						if errors.Is(runErr, errApiTestRunAborted) {
+							stopped.Store(true)
+							continue
+						}
+						if runErr != nil {
+							errOnce.Do(func() {
+								firstErr = runErr
+								cancel()
+							})
+							continue
+						}
+						outcomes[index] = apiTestRunAllOutcome{ran: true, result: result}
+					}
+					job.setProgress(int(done.Add(1)), len(cases))
+				}
+			}
+		}()
+	}
+	for _, group := range groups {
+		if runCtx.Err() != nil {
+			stopped.Store(true)
+			break
+		}
+		queue <- group
+	}
+	close(queue)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, false, firstErr
+	}
+	cancelled := stopped.Load() && ctx.Err() != nil
+	if cancelled {
+		// 未执行完的用例：停用的仍计为跳过，其余标记为已中止
+		for index, caseRecord := range cases {
+			collectionRecord := collectionMap[caseRecord.GetString("collection")]
+			if outcomes[index].ran || outcomes[index].skipped || collectionRecord == nil {
+				continue
+			}
+			if !caseRecord.GetBool("enabled") {
+				outcomes[index].skipped = true
+				continue
+			}
+			outcomes[index] = apiTestRunAllOutcome{aborted: true, result: apiTestAbortedRunResult(caseRecord, collectionRecord)}
+		}
+	}
+	return outcomes, cancelled, nil
+}
@@ -0,0 +1,167 @@
+// Package hub 提供接口执行记录的时间范围过滤与 CSV 导出。
+// 执行记录列表与导出共用 case/collection 与 start/end（RFC3339，闭区间）查询参数。
+// 导出按创建时间倒序逐行读取并写出，不在内存中缓存完整结果；以 =、+、-、@ 等开头的文本单元格加前缀单引号，
+// 避免在表格软件中被当作公式执行。
+package hub
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// apiTestRunsExportFlushRows 为导出时每写出多少行刷新一次响应。
+const apiTestRunsExportFlushRows = 500
+
+var apiTestRunsExportHeader = []string{"created", "collection", "case_name", "status", "duration_ms", "success", "source", "error"}
+
+// apiTestRunTimeRange 为执行记录的创建时间范围，零值表示不限制。
+type apiTestRunTimeRange struct {
+	Start types.DateTime
+	End   types.DateTime
+}
+
+func apiTestParseRunTimeRange(query url.Values) (apiTestRunTimeRange, error) {
+	var timeRange apiTestRunTimeRange
+	for _, item := range []struct {
+		name   string
+		target *types.DateTime
+	}{{"start", &timeRange.Start}, {"end", &timeRange.End}} {
+		raw := strings.TrimSpace(query.Get(item.name))
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return apiTestRunTimeRange{}, fmt.Errorf("%s 必须为 RFC3339 格式", item.name)
+		}
+		value, err := types.ParseDateTime(parsed)
+		if err != nil {
+			return apiTestRunTimeRange{}, fmt.Errorf("%s 无效: %w", item.name, err)
+		}
+		*item.target = value
+	}
+	if !timeRange.Start.IsZero() && !timeRange.End.IsZero() && timeRange.Start.After(timeRange.End) {
+		return apiTestRunTimeRange{}, errors.New("start 不能晚于 end")
+	}
+	return timeRange, nil
+}
+
+// appendTo 将时间条件追加到 where（过滤表达式或原生 SQL 条件均可），参数写入 params。
+func (r apiTestRunTimeRange) appendTo(where string, params dbx.Params, column string, separator string) string {
+	parts := []string{}
+	if where != "" {
+		parts = append(parts, where)
+	}
+	if !r.Start.IsZero() {
+		parts = append(parts, column+" >= {:start}")
+		params["start"] = r.Start.String()
+	}
+	if !r.End.IsZero() {
+		parts = append(parts, column+" <= {:end}")
+		params["end"] = r.End.String()
+	}
+	return strings.Join(parts, separator)
+}
+
+// apiTestCSVCell 中和表格软件会解析为公式的文本。
+func apiTestCSVCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// exportApiTestRuns 以 CSV 流式导出执行记录。
+func (h *Hub) exportApiTestRuns(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	scope := apiTestParseRunScope(query)
+	timeRange, err := apiTestParseRunTimeRange(query)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("时间范围无效", err, nil).Error())
+	}
+	where, params := scope.sqlWhere("r.`case`", "r.collection")
+	where = timeRange.appendTo(where, params, "r.created", " AND ")
+
+	selectQuery := h.DB().Select(
+		"r.created", "COALESCE(col.name, '') AS collection_name", "COALESCE(c.name, '') AS case_name",
+		"r.status", "r.duration_ms", "r.success", "r.source", "r.error",
+	).
+		From(apiTestRunsCollection+" r").
+		LeftJoin(apiTestCasesCollection+" c", dbx.NewExp("c.id = r.`case`")).
+		LeftJoin(apiTestCollectionsCollection+" col", dbx.NewExp("col.id = r.collection")).
+		OrderBy("r.created DESC")
+	if where != "" {
+		selectQuery = selectQuery.Where(dbx.NewExp(where, params))
+	}
+	rows, err := selectQuery.Rows()
+	if err != nil {
+		h.logApiTestError("读取接口执行记录失败", err)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取接口执行记录失败", err, nil).Error())
+	}
+	defer rows.Close()
+
+	filename := fmt.Sprintf("api-test-runs-%s.csv", time.Now().UTC().Format("20060102-150405"))
+	e.Response.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	e.Response.Header().Set("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	e.Response.WriteHeader(http.StatusOK)
+
+	// 响应头已发送，后续错误只能记日志并截断输出
+	writer := csv.NewWriter(e.Response)
+	if err := writer.Write(apiTestRunsExportHeader); err != nil {
+		return err
+	}
+	var row struct {
+		Created        string `db:"created"`
+		CollectionName string `db:"collection_name"`
+		CaseName       string `db:"case_name"`
+		Status         int    `db:"status"`
+		DurationMs     int    `db:"duration_ms"`
+		Success        bool   `db:"success"`
+		Source         string `db:"source"`
+		Error          string `db:"error"`
+	}
+	count := 0
+	for rows.Next() {
+		if err := rows.ScanStruct(&row); err != nil {
+			h.logApiTestError("导出接口执行记录失败", err)
+			break
+		}
+		record := []string{
+			row.Created,
+			apiTestCSVCell(row.CollectionName),
+			apiTestCSVCell(row.CaseName),
+			strconv.Itoa(row.Status),
+			strconv.Itoa(row.DurationMs),
+			strconv.FormatBool(row.Success),
+			row.Source,
+			apiTestCSVCell(row.Error),
+		}
+		if err := writer.Write(record); err != nil {
+			h.logApiTestError("导出接口执行记录失败", err)
+			return nil
+		}
+		count++
+		if count%apiTestRunsExportFlushRows == 0 {
+			writer.Flush()
+			e.Flush()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		h.logApiTestError("导出接口执行记录失败", err)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		h.logApiTestError("导出接口执行记录失败", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Package hub 提供定时巡检的执行摘要。
+// 每次巡检结束后将参与巡检的用例数、实际执行数与跳过的用例（及原因）写入定时配置的 last_run_summary，
+// 便于排查某个用例“没有执行”的原因：未到期、所属合集不存在或用例已停用。跳过明细最多保留 apiTestMaxScheduleSkips 条，
+// 按原因的计数始终完整。巡检因执行锁被占用而整体跳过时不更新摘要，原因记录在 last_error。
+package hub
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	apiTestScheduleSkipNotDue            = "not_due"
+	apiTestScheduleSkipCollectionMissing = "collection_missing"
+	apiTestScheduleSkipDisabled          = "disabled"
+
+	apiTestMaxScheduleSkips = 200
+)
+
+// apiTestScheduleSkip 为一条跳过记录。
+type apiTestScheduleSkip struct {
+	CaseId   string `json:"caseId"`
+	CaseName string `json:"caseName"`
+	Reason   string `json:"reason"`
+}
+
+// apiTestScheduleRunSummary 为一次定时巡检的摘要。
+type apiTestScheduleRunSummary struct {
+	Total            int                   `json:"total"`
+	Executed         int                   `json:"executed"`
+	Skipped          int                   `json:"skipped"`
+	SkippedByReason  map[string]int        `json:"skippedByReason"`
+	SkippedCases     []apiTestScheduleSkip `json:"skippedCases"`
+	SkippedTruncated bool                  `json:"skippedTruncated,omitempty"`
+}
+
+func newApiTestScheduleRunSummary(total int) *apiTestScheduleRunSummary {
+	return &apiTestScheduleRunSummary{
+		Total:           total,
+		SkippedByReason: make(map[string]int),
+		SkippedCases:    make([]apiTestScheduleSkip, 0),
+	}
+}
+
+// skip 记录被跳过的用例。
+func (s *apiTestScheduleRunSummary) skip(caseRecord *core.Record, reason string) {
+	s.Skipped++
+	s.SkippedByReason[reason]++
+	if len(s.SkippedCases) >= apiTestMaxScheduleSkips {
+		s.SkippedTruncated = true
+		return
+	}
+	s.SkippedCases = append(s.SkippedCases, apiTestScheduleSkip{
+		CaseId:   caseRecord.Id,
+		CaseName: caseRecord.GetString("name"),
+		Reason:   reason,
+	})
+}
+
+// apiTestRecordScheduleRunSummary 读取定时配置中的最近一次巡检摘要，未巡检过时返回 nil。
+func apiTestRecordScheduleRunSummary(config *core.Record) *apiTestScheduleRunSummary {
+	raw := strings.TrimSpace(config.GetString("last_run_summary"))
+	if raw == "" || raw == "null" {
+		return nil
+	}
+	var summary apiTestScheduleRunSummary
+	if err := json.Unmarshal([]byte(raw), &summary); err != nil {
+		return nil
+	}
+	return &summary
+}
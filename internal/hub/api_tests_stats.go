@@ -0,0 +1,362 @@
+// Package hub 提供接口测试用例的成功率/耗时统计。
+// 后台任务按 stats_refresh_minutes 定期将各滚动窗口（stats_window_hours）的聚合结果写入 api_test_case_stats，
+// 统计接口优先读取该汇总表；汇总缺失或过期（超过两个刷新周期未更新）时回退为实时聚合 api_test_runs。
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	apiTestCaseStatsCollection        = "api_test_case_stats"
+	apiTestDefaultStatsRefreshMinutes = 10
+	apiTestMaxStatsWindowHours        = 90 * 24
+	apiTestMaxStatsWindows            = 8
+)
+
+var apiTestDefaultStatsWindowHours = []int{24, 168}
+
+// apiTestStatsRefreshing 防止上一次刷新未完成时重复刷新
+var apiTestStatsRefreshing atomic.Bool
+
+type apiTestCaseStats struct {
+	CaseId        string  `json:"caseId"`
+	Total         int     `json:"total"`
+	SuccessCount  int     `json:"successCount"`
+	FailureCount  int     `json:"failureCount"`
+	SuccessRate   float64 `json:"successRate"`
+	AvgDurationMs float64 `json:"avgDurationMs"`
+	P50DurationMs int     `json:"p50DurationMs"`
+	P95DurationMs int     `json:"p95DurationMs"`
+	P99DurationMs int     `json:"p99DurationMs"`
+	LastFailureAt string  `json:"lastFailureAt"`
+}
+
+type apiTestStatsResponse struct {
+	WindowHours int                `json:"windowHours"`
+	Source      string             `json:"source"`
+	ComputedAt  string             `json:"computedAt"`
+	Items       []apiTestCaseStats `json:"items"`
+}
+
+type apiTestCaseStatsRow struct {
+	CaseId        string  `db:"caseId"`
+	Total         int     `db:"total"`
+	SuccessCount  int     `db:"successCount"`
+	AvgDurationMs float64 `db:"avgDurationMs"`
+	LastFailureAt string  `db:"lastFailureAt"`
+}
+
+// apiTestStatsRefreshMinutes 返回汇总刷新间隔，未配置时使用默认值。
+func apiTestStatsRefreshMinutes(config *core.Record) int {
+	minutes := config.GetInt("stats_refresh_minutes")
+	if minutes <= 0 {
+		return apiTestDefaultStatsRefreshMinutes
+	}
+	return minutes
+}
+
+// apiTestStatsWindows 返回预计算的窗口（小时），未配置时使用默认窗口，第一个窗口为统计接口的默认窗口。
+func apiTestStatsWindows(config *core.Record) []int {
+	var windows []int
+	if err := config.UnmarshalJSONField("stats_window_hours", &windows); err != nil || len(windows) == 0 {
+		return apiTestDefaultStatsWindowHours
+	}
+	return windows
+}
+
+// apiTestValidateStatsWindows 校验并去重统计窗口，保留配置顺序。
+func apiTestValidateStatsWindows(windows []int) ([]int, error) {
+	if len(windows) > apiTestMaxStatsWindows {
+		return nil, fmt.Errorf("最多配置 %d 个窗口", apiTestMaxStatsWindows)
+	}
+	normalized := make([]int, 0, len(windows))
+	for _, hours := range windows {
+		if hours <= 0 || hours > apiTestMaxStatsWindowHours {
+			return nil, fmt.Errorf("窗口 %d 小时超出 1-%d 范围", hours, apiTestMaxStatsWindowHours)
+		}
+		if !slices.Contains(normalized, hours) {
+			normalized = append(normalized, hours)
+		}
+	}
+	return normalized, nil
+}
+
+// computeApiTestCaseStats 实时聚合窗口内的执行记录；scope 为空时统计全部用例（无执行记录的用例计数为 0）。
+// 分位数（p50/p95/p99）按最近秩法逐个用例取值，借助 (case, created) 索引避免加载全部行。
+func (h *Hub) computeApiTestCaseStats(windowHours int, scope apiTestRunScope) ([]apiTestCaseStats, error) {
+	cutoff := apiTestNowDateTime().Add(-time.Duration(windowHours) * time.Hour).String()
+	where, params := scope.sqlWhere("c.id", "c.collection")
+	if where != "" {
+		where = "WHERE " + where
+	}
+	params["cutoff"] = cutoff
+	var rows []apiTestCaseStatsRow
+	err := h.DB().NewQuery(`SELECT c.id AS caseId,
+			COUNT(r.id) AS total,
+			COALESCE(SUM(CASE WHEN r.success THEN 1 ELSE 0 END), 0) AS successCount,
+			COALESCE(AVG(r.duration_ms), 0) AS avgDurationMs,
+			COALESCE(MAX(CASE WHEN r.success THEN NULL ELSE r.created END), '') AS lastFailureAt
+		FROM ` + apiTestCasesCollection + ` c
+		LEFT JOIN ` + apiTestRunsCollection + " r ON r.`case` = c.id AND r.created >= {:cutoff} " +
+		where + " GROUP BY c.id ORDER BY c.id").Bind(params).All(&rows)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]apiTestCaseStats, 0, len(rows))
+	for _, row := range rows {
+		item := apiTestCaseStats{
+			CaseId:        row.CaseId,
+			Total:         row.Total,
+			SuccessCount:  row.SuccessCount,
+			FailureCount:  row.Total - row.SuccessCount,
+			AvgDurationMs: math.Round(row.AvgDurationMs*100) / 100,
+		}
+		if lastFailure, err := types.ParseDateTime(row.LastFailureAt); err == nil {
+			item.LastFailureAt = apiTestDateTimeString(lastFailure)
+		}
+		if row.Total > 0 {
+			item.SuccessRate = float64(row.SuccessCount) / float64(row.Total)
+			for _, percentile := range []struct {
+				quantile float64
+				target   *int
+			}{{0.5, &item.P50DurationMs}, {0.95, &item.P95DurationMs}, {0.99, &item.P99DurationMs}} {
+				value, err := h.apiTestDurationPercentile(row.CaseId, cutoff, row.Total, percentile.quantile)
+				if err != nil {
+					return nil, err
+				}
+				*percentile.target = value
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// apiTestDurationPercentile 按最近秩法返回用例窗口内执行耗时的分位数，total 为窗口内的执行次数。
+func (h *Hub) apiTestDurationPercentile(caseId string, cutoff string, total int, quantile float64) (int, error) {
+	var row struct {
+		DurationMs int `db:"duration_ms"`
+	}
+	err := h.DB().NewQuery("SELECT duration_ms FROM " + apiTestRunsCollection +
+		" WHERE `case` = {:case} AND created >= {:cutoff} ORDER BY duration_ms LIMIT 1 OFFSET {:offset}").Bind(dbx.Params{
+		"case":   caseId,
+		"cutoff": cutoff,
+		"offset": max(int(math.Ceil(float64(total)*quantile))-1, 0),
+	}).One(&row)
+	return row.DurationMs, err
+}
+
+// refreshApiTestCaseStats 重新计算全部窗口并整体替换汇总表，已删除的窗口与用例随之清理。
+// 聚合在事务外完成，事务内只做写入，缩短写锁持有时间。
+func (h *Hub) refreshApiTestCaseStats(config *core.Record) error {
+	windows := apiTestStatsWindows(config)
+	computed := make(map[int][]apiTestCaseStats, len(windows))
+	for _, hours := range windows {
+		items, err := h.computeApiTestCaseStats(hours, apiTestRunScope{})
+		if err != nil {
+			return fmt.Errorf("聚合 %d 小时窗口失败: %w", hours, err)
+		}
+		computed[hours] = items
+	}
+	computedAt := apiTestNowDateTime()
+	return h.RunInTransaction(func(txApp core.App) error {
+		collection, err := txApp.FindCollectionByNameOrId(apiTestCaseStatsCollection)
+		if err != nil {
+			return err
+		}
+		if _, err := txApp.DB().NewQuery("DELETE FROM " + apiTestCaseStatsCollection).Execute(); err != nil {
+			return err
+		}
+		for _, hours := range windows {
+			for _, item := range computed[hours] {
+				record := core.NewRecord(collection)
+				record.Set("case", item.CaseId)
+				record.Set("window_hours", hours)
+				record.Set("total", item.Total)
+				record.Set("success_count", item.SuccessCount)
+				record.Set("failure_count", item.FailureCount)
+				record.Set("avg_duration_ms", item.AvgDurationMs)
+				record.Set("p50_duration_ms", item.P50DurationMs)
+				record.Set("p95_duration_ms", item.P95DurationMs)
+				record.Set("p99_duration_ms", item.P99DurationMs)
+				record.Set("last_failure_at", item.LastFailureAt)
+				record.Set("computed_at", computedAt)
+				if err := txApp.Save(record); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// apiTestStatsComputedAt 返回汇总表最近一次刷新时间，未刷新过时返回零值。
+func (h *Hub) apiTestStatsComputedAt() (types.DateTime, error) {
+	var row struct {
+		ComputedAt string `db:"computedAt"`
+	}
+	err := h.DB().NewQuery("SELECT COALESCE(MAX(computed_at), '') AS computedAt FROM " + apiTestCaseStatsCollection).One(&row)
+	if err != nil {
+		return types.DateTime{}, err
+	}
+	return types.ParseDateTime(row.ComputedAt)
+}
+
+func (h *Hub) runApiTestStatsTick() {
+	if !h.ready.Load() {
+		return
+	}
+	if !apiTestStatsRefreshing.CompareAndSwap(false, true) {
+		return
+	}
+	defer apiTestStatsRefreshing.Store(false)
+	config, err := h.getOrCreateApiTestScheduleConfig()
+	if err != nil {
+		h.logApiTestError("读取接口定时配置失败", err)
+		return
+	}
+	computedAt, err := h.apiTestStatsComputedAt()
+	if err != nil {
+		h.logApiTestError("读取接口统计汇总失败", err)
+		return
+	}
+	interval := time.Duration(apiTestStatsRefreshMinutes(config)) * time.Minute
+	if !computedAt.IsZero() && time.Since(computedAt.Time()) < interval {
+		return
+	}
+	if err := h.refreshApiTestCaseStats(config); err != nil {
+		h.logApiTestError("刷新接口统计汇总失败", err)
+	}
+}
+
+// loadCachedApiTestCaseStats 读取窗口的汇总结果。汇总过期或缺少用例时返回 ok=false，由调用方回退实时聚合。
+func (h *Hub) loadCachedApiTestCaseStats(config *core.Record, windowHours int, scope apiTestRunScope) ([]apiTestCaseStats, types.DateTime, bool, error) {
+	computedAt, err := h.apiTestStatsComputedAt()
+	if err != nil || computedAt.IsZero() {
+		return nil, computedAt, false, err
+	}
+	staleAfter := 2 * time.Duration(apiTestStatsRefreshMinutes(config)) * time.Minute
+	if time.Since(computedAt.Time()) > staleAfter {
+		return nil, computedAt, false, nil
+	}
+	filter, params := scope.filter("case", "case.collection")
+	if filter != "" {
+		filter = " && " + filter
+	}
+	filter = "window_hours = {:window}" + filter
+	params["window"] = windowHours
+	records, err := h.FindRecordsByFilter(apiTestCaseStatsCollection, filter, "case", -1, 0, params)
+	if err != nil {
+		return nil, computedAt, false, err
+	}
+	expected := int64(1)
+	if scope.CaseId == "" {
+		var exp dbx.Expression
+		if scope.CollectionId != "" {
+			exp = dbx.HashExp{"collection": scope.CollectionId}
+		}
+		if expected, err = h.CountRecords(apiTestCasesCollection, exp); err != nil {
+			return nil, computedAt, false, err
+		}
+	}
+	if int64(len(records)) < expected {
+		return nil, computedAt, false, nil
+	}
+	items := make([]apiTestCaseStats, 0, len(records))
+	for _, record := range records {
+		item := apiTestCaseStats{
+			CaseId:        record.GetString("case"),
+			Total:         record.GetInt("total"),
+			SuccessCount:  record.GetInt("success_count"),
+			FailureCount:  record.GetInt("failure_count"),
+			AvgDurationMs: record.GetFloat("avg_duration_ms"),
+			P50DurationMs: record.GetInt("p50_duration_ms"),
+			P95DurationMs: record.GetInt("p95_duration_ms"),
+			P99DurationMs: record.GetInt("p99_duration_ms"),
+			LastFailureAt: apiTestDateTimeString(record.GetDateTime("last_failure_at")),
+		}
+		if item.Total > 0 {
+			item.SuccessRate = float64(item.SuccessCount) / float64(item.Total)
+		}
+		items = append(items, item)
+	}
+	return items, computedAt, true, nil
+}
+
+// getApiTestStats 返回各用例在窗口内的成功率与耗时统计，可按 case、collection 过滤。
+// 窗口由 hours（或兼容的 window）小时数或 days 天数指定，默认取第一个预计算窗口；非预计算窗口直接实时聚合。
+func (h *Hub) getApiTestStats(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	scope := apiTestParseRunScope(query)
+	config, err := h.getOrCreateApiTestScheduleConfig()
+	if err != nil {
+		h.logApiTestError("读取接口定时配置失败", err)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("读取接口定时配置失败", err, nil).Error())
+	}
+	windows := apiTestStatsWindows(config)
+	windowHours, err := apiTestParseStatsWindow(query, windows[0])
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, formatApiTestError("统计窗口无效", err, nil).Error())
+	}
+	response := apiTestStatsResponse{WindowHours: windowHours}
+	if slices.Contains(windows, windowHours) {
+		items, computedAt, ok, err := h.loadCachedApiTestCaseStats(config, windowHours, scope)
+		if err != nil {
+			h.logApiTestError("读取接口统计汇总失败", err)
+		}
+		if ok {
+			response.Source = "cache"
+			response.ComputedAt = apiTestDateTimeString(computedAt)
+			response.Items = items
+			return e.JSON(http.StatusOK, response)
+		}
+	}
+	items, err := h.computeApiTestCaseStats(windowHours, scope)
+	if err != nil {
+		h.logApiTestError("统计接口执行记录失败", err)
+		return respondError(e, http.StatusInternalServerError, formatApiTestError("统计接口执行记录失败", err, nil).Error())
+	}
+	response.Source = "live"
+	response.ComputedAt = apiTestDateTimeString(apiTestNowDateTime())
+	response.Items = items
+	return e.JSON(http.StatusOK, response)
+}
+
+// apiTestParseStatsWindow 解析统计窗口（小时）：hours 与 window 为小时数，days 为天数，最多指定其中一个，均未指定时返回 fallback。
+func apiTestParseStatsWindow(query url.Values, fallback int) (int, error) {
+	windowHours := fallback
+	specified := 0
+	for _, param := range []struct {
+		name  string
+		scale int
+	}{{"hours", 1}, {"window", 1}, {"days", 24}} {
+		raw := strings.TrimSpace(query.Get(param.name))
+		if raw == "" {
+			continue
+		}
+		specified++
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 || value*param.scale > apiTestMaxStatsWindowHours {
+			return 0, fmt.Errorf("%s 必须为不超过 %d 小时的正整数: %s", param.name, apiTestMaxStatsWindowHours, raw)
+		}
+		windowHours = value * param.scale
+	}
+	if specified > 1 {
+		return 0, errors.New("hours、window 与 days 只能指定一个")
+	}
+	return windowHours, nil
+}
@@ -0,0 +1,88 @@
+// Package hub 提供 HTTPS 证书到期监测。
+// HTTPS 用例执行得到响应时，叶子证书的 NotAfter 记录到执行记录与用例的 tls_expires_at；非 TLS 请求不记录，用例上的值随之清空。
+// 定时配置 tls_expiry_alert_days 大于 0 且开启告警时，定时巡检发现证书剩余有效期不足该天数即告警一次，
+// 证书更新后按 alert_on_recover 发送恢复通知。告警状态保存在用例的 tls_expiry_alert_triggered，与失败告警相互独立。
+package hub
+
+import (
+	"crypto/tls"
+	"fmt"
+	"math"
+	"time"
+
+	"aether/internal/alerts"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	apiTestAlertModeTLSExpiry    = "tls_expiry"
+	apiTestMaxTLSExpiryAlertDays = 365
+)
+
+// apiTestLeafCertExpiry 返回叶子证书的到期时间，无证书时返回零值。
+func apiTestLeafCertExpiry(state *tls.ConnectionState) types.DateTime {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return types.DateTime{}
+	}
+	expiresAt, err := types.ParseDateTime(state.PeerCertificates[0].NotAfter)
+	if err != nil {
+		return types.DateTime{}
+	}
+	return expiresAt
+}
+
+// apiTestValidateTLSExpiryAlertDays 校验证书到期告警的提前天数，0 表示不告警。
+func apiTestValidateTLSExpiryAlertDays(days int) error {
+	if days < 0 || days > apiTestMaxTLSExpiryAlertDays {
+		return fmt.Errorf("必须为 0-%d", apiTestMaxTLSExpiryAlertDays)
+	}
+	return nil
+}
+
+// apiTestApplyTLSExpiry 将执行结果中的证书到期时间写入用例，并在定时巡检时更新证书到期告警状态，返回需要发送的告警。
+func apiTestApplyTLSExpiry(caseRecord *core.Record, result apiTestExecutionResult, source apiTestRunSource, config *core.Record, now time.Time) apiTestAlertAction {
+	switch {
+	case !result.TLSExpiresAt.IsZero():
+		caseRecord.Set("tls_expires_at", result.TLSExpiresAt)
+	case result.Protocol != "":
+		// 得到响应但没有证书说明不是 HTTPS 请求
+		caseRecord.Set("tls_expires_at", "")
+	}
+	if source != apiTestRunSourceSchedule || result.TLSExpiresAt.IsZero() {
+		return apiTestAlertAction{}
+	}
+	triggered := caseRecord.GetBool("tls_expiry_alert_triggered")
+	days := 0
+	if config != nil {
+		days = config.GetInt("tls_expiry_alert_days")
+	}
+	if days <= 0 {
+		caseRecord.Set("tls_expiry_alert_triggered", false)
+		return apiTestAlertAction{}
+	}
+	remaining := result.TLSExpiresAt.Time().Sub(now)
+	action := apiTestAlertAction{
+		ShouldSend: true,
+		CaseName:   caseRecord.GetString("name"),
+		Mode:       apiTestAlertModeTLSExpiry,
+		Threshold:  days,
+		DaysLeft:   int(math.Floor(remaining.Hours() / 24)),
+		ExpiresAt:  apiTestDateTimeString(result.TLSExpiresAt),
+	}
+	expiring := remaining < time.Duration(days)*24*time.Hour
+	switch {
+	case !triggered && expiring && config.GetBool("alert_enabled"):
+		action.State = alerts.NotificationStateTriggered
+		caseRecord.Set("tls_expiry_alert_triggered", true)
+		return action
+	case triggered && !expiring:
+		caseRecord.Set("tls_expiry_alert_triggered", false)
+		if config.GetBool("alert_on_recover") {
+			action.State = alerts.NotificationStateResolved
+			return action
+		}
+	}
+	return apiTestAlertAction{}
+}
@@ -0,0 +1,133 @@
+// Package hub 提供接口合集变量。
+// 合集的 variables 为 key/value 列表（格式同 headers，enabled 为 false 的项不生效），用例的地址、请求头值、查询参数值与请求体中
+// 的 {{name}} 占位符在组装请求时替换为变量值：地址在与合集 base_url 拼接后替换，因此 SSRF 校验与节流均基于替换后的地址。
+// 未定义或未启用的变量不会原样发送，组装请求直接失败并列出缺失的变量名。标记为 secret 的变量值在请求预览中脱敏。
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const apiTestMaxVariables = 100
+
+var (
+	apiTestVariablePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+	apiTestVariableName        = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+)
+
+// apiTestVariableResolver 替换占位符并记录未定义的变量名。
+type apiTestVariableResolver struct {
+	values  map[string]string
+	missing []string
+}
+
+// apiTestValidateVariables 校验合集变量：变量名只能包含字母、数字、下划线、点与连字符且不能以数字开头，不允许重复。
+func apiTestValidateVariables(items []apiTestKeyValue) error {
+	if len(items) > apiTestMaxVariables {
+		return fmt.Errorf("变量不能超过 %d 个", apiTestMaxVariables)
+	}
+	seen := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		name := strings.TrimSpace(item.Key)
+		if !apiTestVariableName.MatchString(name) {
+			return fmt.Errorf("变量名无效: %q", item.Key)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("变量名重复: %s", name)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
+
+// apiTestCollectionVariableItems 读取合集的变量列表。
+func apiTestCollectionVariableItems(collectionRecord *core.Record) ([]apiTestKeyValue, error) {
+	raw := strings.TrimSpace(collectionRecord.GetString("variables"))
+	if raw == "" || raw == "null" {
+		return nil, nil
+	}
+	var items []apiTestKeyValue
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil, fmt.Errorf("variables 格式无效: %w", err)
+	}
+	return items, nil
+}
+
+// newApiTestVariableResolver 使用合集中已启用的变量创建替换器。
+func newApiTestVariableResolver(collectionRecord *core.Record) (*apiTestVariableResolver, error) {
+	items, err := apiTestCollectionVariableItems(collectionRecord)
+	if err != nil {
+		return nil, fmt.Errorf("解析合集变量失败: %v", err)
+	}
+	return &apiTestVariableResolver{values: apiTestValueListToMap(items)}, nil
+}
+
+// apply 替换 text 中的占位符，未定义的变量保留原文并记入缺失列表。
+func (r *apiTestVariableResolver) apply(text string) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+	return apiTestVariablePlaceholder.ReplaceAllStringFunc(text, func(match string) string {
+		name := apiTestVariablePlaceholder.FindStringSubmatch(match)[1]
+		if value, ok := r.values[name]; ok {
+			return value
+		}
+		if !slices.Contains(r.missing, name) {
+			r.missing = append(r.missing, name)
+		}
+		return match
+	})
+}
+
+// applyMap 替换 map 中每个值的占位符。
+func (r *apiTestVariableResolver) applyMap(values map[string]string) {
+	for key, value := range values {
+		values[key] = r.apply(value)
+	}
+}
+
+// err 在存在未定义变量时返回错误。
+func (r *apiTestVariableResolver) err() error {
+	if len(r.missing) == 0 {
+		return nil
+	}
+	return errors.New("未定义的合集变量: " + strings.Join(r.missing, ", "))
+}
+
+// apiTestSecretVariableValues 返回合集中标记为 secret 的已启用变量值，用于请求预览脱敏。
+func apiTestSecretVariableValues(collectionRecord *core.Record) []string {
+	items, err := apiTestCollectionVariableItems(collectionRecord)
+	if err != nil {
+		return nil
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Enabled && item.Secret && item.Value != "" {
+			values = append(values, item.Value)
+		}
+	}
+	return values
+}
+
+// apiTestRedactVariableValues 将预览中出现的 secret 变量值（含查询串编码形式）替换为脱敏占位值。
+func apiTestRedactVariableValues(response *apiTestPreviewResponse, secrets []string) {
+	for _, secret := range secrets {
+		replacer := strings.NewReplacer(secret, apiTestRedactedValue, url.QueryEscape(secret), apiTestRedactedValue)
+		response.URL = replacer.Replace(response.URL)
+		response.Body = replacer.Replace(response.Body)
+		for key, value := range response.Headers {
+			response.Headers[key] = replacer.Replace(value)
+		}
+		for key, value := range response.Params {
+			response.Params[key] = replacer.Replace(value)
+		}
+	}
+}
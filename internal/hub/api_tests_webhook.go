@@ -0,0 +1,123 @@
+// Package hub 提供接口告警的通用 Webhook 推送。
+// 定时配置 webhook_url 不为空时，每条接口告警（含恢复通知）除按用户通知设置发送外，另以 JSON POST 推送到该地址，
+// 便于转发到 Slack、Teams 或自建系统。地址与用例目标一样经过 SSRF 过滤，保存配置与每次推送时均校验，且不跟随重定向。
+// 推送失败只记日志（不含地址，避免泄露其中的令牌），不影响用户通知的发送结果。
+package hub
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	apiTestWebhookTimeout   = 10 * time.Second
+	apiTestMaxWebhookURLLen = 2048
+)
+
+// apiTestWebhookPayload 为推送到 Webhook 的告警内容。
+type apiTestWebhookPayload struct {
+	Event               string  `json:"event"`
+	Mode                string  `json:"mode"`
+	State               string  `json:"state"`
+	CaseName            string  `json:"caseName"`
+	ConsecutiveFailures int     `json:"consecutiveFailures"`
+	Threshold           int     `json:"threshold"`
+	StatusCode          int     `json:"statusCode"`
+	Error               string  `json:"error"`
+	SuccessRate         float64 `json:"successRate,omitempty"`
+	RateThreshold       float64 `json:"rateThreshold,omitempty"`
+	DaysLeft            int     `json:"daysLeft,omitempty"`
+	ExpiresAt           string  `json:"expiresAt,omitempty"`
+	Link                string  `json:"link"`
+	Timestamp           string  `json:"timestamp"`
+}
+
+// apiTestWebhookClient 为推送使用的 HTTP 客户端，不跟随重定向，避免重定向绕过 SSRF 校验。
+var apiTestWebhookClient = &http.Client{
+	Timeout: apiTestWebhookTimeout,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// validateApiTestWebhookURL 校验 Webhook 地址，空字符串表示不推送。
+func (h *Hub) validateApiTestWebhookURL(rawURL string) error {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil
+	}
+	if len(rawURL) > apiTestMaxWebhookURLLen {
+		return fmt.Errorf("长度不能超过 %d", apiTestMaxWebhookURLLen)
+	}
+	return h.validateApiTestTarget(rawURL)
+}
+
+// buildApiTestWebhookPayload 将告警动作转换为推送内容。
+func (h *Hub) buildApiTestWebhookPayload(action apiTestAlertAction) apiTestWebhookPayload {
+	mode := action.Mode
+	if mode == "" {
+		mode = apiTestAlertModeConsecutive
+	}
+	return apiTestWebhookPayload{
+		Event:               "api_test.alert",
+		Mode:                mode,
+		State:               string(action.State),
+		CaseName:            action.CaseName,
+		ConsecutiveFailures: action.ConsecutiveFailures,
+		Threshold:           action.Threshold,
+		StatusCode:          action.StatusCode,
+		Error:               strings.TrimSpace(action.ErrorMessage),
+		SuccessRate:         action.SuccessRate,
+		RateThreshold:       action.RateThreshold,
+		DaysLeft:            action.DaysLeft,
+		ExpiresAt:           action.ExpiresAt,
+		Link:                h.MakeLink("api-tests"),
+		Timestamp:           time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// sendApiTestWebhook 将告警推送到定时配置中的 Webhook，未配置时不做任何事。
+func (h *Hub) sendApiTestWebhook(action apiTestAlertAction) error {
+	config, err := h.getOrCreateApiTestScheduleConfig()
+	if err != nil {
+		return fmt.Errorf("读取接口定时配置失败: %w", err)
+	}
+	webhookURL := strings.TrimSpace(config.GetString("webhook_url"))
+	if webhookURL == "" {
+		return nil
+	}
+	if err := h.validateApiTestWebhookURL(webhookURL); err != nil {
+		return fmt.Errorf("Webhook 地址无效: %w", err)
+	}
+	body, err := json.Marshal(h.buildApiTestWebhookPayload(action))
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := apiTestWebhookClient.Do(request)
+	if err != nil {
+		// Webhook 地址通常包含令牌，错误中只保留底层原因
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("请求 Webhook 失败: %w", urlErr.Err)
+		}
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return errors.New("Webhook 返回状态码 " + response.Status)
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 // Package hub 记录 Docker 操作审计信息。
-// 审计日志用于追踪写操作与关键状态变更。
+// 审计日志用于追踪写操作与关键状态变更。设置 DOCKER_AUDIT_READS=true 后，
+// 总览与列表等读操作也写入审计（action 如 container.list），默认关闭以免审计表膨胀。
 package hub
 
 import (
 	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/pocketbase/pocketbase/core"
@@ -14,6 +16,9 @@ const (
 	dockerAuditStatusFailed  = "failed"
 )
 
+// dockerAuditMaxPerPage caps the page size of the audit list.
+const dockerAuditMaxPerPage = 200
+
 type dockerAuditEntry struct {
 	SystemID     string
 	UserID       string
@@ -22,13 +27,16 @@ type dockerAuditEntry struct {
 	ResourceID   string
 	Status       string
 	Detail       string
+	// SystemActor allows an empty UserID for actions the hub performs on its own, such as
+	// finishing a cleanup run whose triggering user is unknown.
+	SystemActor bool
 }
 
 func (h *Hub) recordDockerAudit(entry dockerAuditEntry) error {
 	if h == nil {
 		return errors.New("hub is nil")
 	}
-	if strings.TrimSpace(entry.UserID) == "" {
+	if strings.TrimSpace(entry.UserID) == "" && !entry.SystemActor {
 		return errors.New("audit requires user id")
 	}
 	systemID := strings.TrimSpace(entry.SystemID)
@@ -43,11 +51,45 @@ func (h *Hub) recordDockerAudit(entry dockerAuditEntry) error {
 	if systemID != "" {
 		record.Set("system", systemID)
 	}
-	record.Set("user", entry.UserID)
+	if entry.UserID != "" {
+		record.Set("user", entry.UserID)
+	}
 	record.Set("action", entry.Action)
 	record.Set("resource_type", entry.ResourceType)
 	record.Set("resource_id", entry.ResourceID)
 	record.Set("status", entry.Status)
-	record.Set("detail", entry.Detail)
+	record.Set("detail", h.redactor.redact(entry.Detail))
 	return h.Save(record)
 }
+
+// dockerAuditReadsFromEnv reports whether DOCKER_AUDIT_READS enables auditing of read operations.
+func dockerAuditReadsFromEnv() bool {
+	value, _ := GetEnv("DOCKER_AUDIT_READS")
+	enabled, _ := strconv.ParseBool(strings.TrimSpace(value))
+	return enabled
+}
+
+// recordDockerReadAudit records a read operation when read auditing is enabled and is a no-op otherwise.
+// Failures are logged instead of failing the read.
+func (h *Hub) recordDockerReadAudit(e *core.RequestEvent, systemID string, action string, resourceType string, resourceID string, readErr error) {
+	if !h.auditDockerReads || e.Auth == nil {
+		return
+	}
+	status := dockerAuditStatusSuccess
+	detail := action
+	if readErr != nil {
+		status = dockerAuditStatusFailed
+		detail = readErr.Error()
+	}
+	if err := h.recordDockerAudit(dockerAuditEntry{
+		SystemID:     systemID,
+		UserID:       e.Auth.Id,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Status:       status,
+		Detail:       detail,
+	}); err != nil {
+		h.Logger().Warn("failed to record docker read audit", "logger", "hub", "action", action, "system", systemID, "err", err)
+	}
+}
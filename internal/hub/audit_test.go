@@ -0,0 +1,105 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDockerAuditsPagination(t *testing.T) {
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	user, err := createTestUser(testApp)
+	require.NoError(t, err)
+	for range 5 {
+		require.NoError(t, hub.recordDockerAudit(dockerAuditEntry{
+			UserID:       user.Id,
+			Action:       "registry.create",
+			ResourceType: "registry",
+			Status:       dockerAuditStatusSuccess,
+		}))
+	}
+
+	list := func(query string) map[string]any {
+		recorder := httptest.NewRecorder()
+		e := &core.RequestEvent{App: testApp}
+		e.Request = httptest.NewRequest(http.MethodGet, "/api/aether/docker/audits?"+query, nil)
+		e.Response = recorder
+		require.NoError(t, hub.listDockerAudits(e))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		return body
+	}
+
+	body := list("page=2&perPage=2")
+	assert.Len(t, body["items"], 2)
+	assert.EqualValues(t, 2, body["page"])
+	assert.EqualValues(t, 2, body["perPage"])
+	assert.EqualValues(t, 5, body["totalItems"])
+	assert.EqualValues(t, 3, body["totalPages"])
+
+	body = list("page=1&perPage=100000")
+	assert.EqualValues(t, dockerAuditMaxPerPage, body["perPage"])
+	assert.EqualValues(t, 1, body["totalPages"])
+
+	body = list("system=missing")
+	assert.Empty(t, body["items"])
+	assert.EqualValues(t, 0, body["totalItems"])
+	assert.EqualValues(t, 0, body["totalPages"])
+
+	body = list("start=2000-01-01T00:00:00Z&page=1&perPage=10")
+	assert.EqualValues(t, 5, body["totalItems"])
+
+	body = list("")
+	assert.Len(t, body["items"], 5)
+	assert.EqualValues(t, 1, body["totalPages"])
+}
+
+func TestRecordDockerReadAudit(t *testing.T) {
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	user, err := createTestUser(testApp)
+	require.NoError(t, err)
+	system, err := createTestRecord(testApp, "systems", map[string]any{
+		"name":   "audited",
+		"host":   "localhost",
+		"port":   "45876",
+		"status": "pending",
+		"users":  []string{user.Id},
+	})
+	require.NoError(t, err)
+	e := &core.RequestEvent{App: testApp}
+	e.Auth = user
+
+	hub.auditDockerReads = false
+	hub.recordDockerReadAudit(e, system.Id, "container.list", "container", "", nil)
+	count, err := testApp.CountRecords("docker_audits")
+	require.NoError(t, err)
+	assert.Zero(t, count, "read auditing is off by default")
+
+	hub.auditDockerReads = true
+	hub.recordDockerReadAudit(e, system.Id, "container.list", "container", "", nil)
+	hub.recordDockerReadAudit(e, system.Id, "image.list", "image", "", errors.New("agent offline"))
+	records, err := testApp.FindRecordsByFilter("docker_audits", "system = {:system}", "action", 0, 0, dbx.Params{"system": system.Id})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "container.list", records[0].GetString("action"))
+	assert.Equal(t, dockerAuditStatusSuccess, records[0].GetString("status"))
+	assert.Equal(t, user.Id, records[0].GetString("user"))
+	assert.Equal(t, "image.list", records[1].GetString("action"))
+	assert.Equal(t, dockerAuditStatusFailed, records[1].GetString("status"))
+	assert.Equal(t, "agent offline", records[1].GetString("detail"))
+}
@@ -19,13 +19,20 @@ import (
 	"aether/internal/common"
 	"aether/internal/hub/systems"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/types"
-	"gopkg.in/yaml.v3"
 )
+// errWriteForbidden is returned by requireWritable after it has written the 403 response, so the
+// calling handler stops; the router does not write a second response.
+var errWriteForbidden = errors.New("write access required")
+
 func requireWritable(e *core.RequestEvent) error {
 	if e.Auth == nil || e.Auth.GetString("role") == "readonly" {
-		return e.JSON(http.StatusForbidden, map[string]string{"error": "forbidden"})
+		if err := respondError(e, http.StatusForbidden, "forbidden"); err != nil {
+			return err
+		}
+		return errWriteForbidden
 	}
 	return nil
 }
@@ -46,7 +53,7 @@ func (h *Hub) resolveSystem(systemID string) (*systems.System, error) {
 	}
 	system, err := h.sm.GetSystem(systemID)
 	if err != nil {
-		return nil, err
+		return nil, errSystemNotFound
 	}
 	return system, nil
 }
@@ -109,11 +116,11 @@ func validateServiceConfigURL(rawURL string) (string, error) {
 func respondSystemAccessError(e *core.RequestEvent, err error) error {
 	switch {
 	case errors.Is(err, errSystemForbidden):
-		return e.JSON(http.StatusForbidden, map[string]string{"error": "forbidden"})
+		return respondErrorWithCode(e, http.StatusForbidden, errCodeForbidden, "forbidden")
 	case errors.Is(err, errSystemNotFound):
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+		return respondErrorWithCode(e, http.StatusNotFound, errCodeSystemNotFound, "system not found")
 	default:
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 }
 
@@ -121,11 +128,12 @@ func (h *Hub) getDockerOverview(e *core.RequestEvent) error {
 	systemID := e.Request.URL.Query().Get("system")
 	system, err := h.resolveSystem(systemID)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	overview, err := system.FetchDockerOverviewFromAgent()
+	h.recordDockerReadAudit(e, systemID, "overview.view", "system", systemID, err)
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, overview)
 }
@@ -135,25 +143,84 @@ func (h *Hub) listDockerContainers(e *core.RequestEvent) error {
 	all := parseBoolParam(e.Request.URL.Query().Get("all"))
 	system, err := h.resolveSystem(systemID)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	containers, err := system.FetchDockerContainersFromAgent(all)
+	h.recordDockerReadAudit(e, systemID, "container.list", "container", "", err)
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, containers)
 }
 
+// getDockerContainerDiff handles GET /api/aether/docker/containers/diff. It returns the
+// container's filesystem changes relative to its image; the agent caps the list at limit
+// entries (default 1000, max 10000) and reports the full count with a truncated flag.
+func (h *Hub) getDockerContainerDiff(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	systemID := query.Get("system")
+	containerID := strings.TrimSpace(query.Get("container"))
+	if containerID == "" {
+		return respondError(e, http.StatusBadRequest, "container is required")
+	}
+	limit := 0
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return respondError(e, http.StatusBadRequest, "limit must be a positive integer")
+		}
+		limit = parsed
+	}
+	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	system, err := h.resolveSystem(systemID)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	diff, err := system.FetchContainerDiffFromAgent(common.ContainerDiffRequest{ContainerID: containerID, Limit: limit})
+	h.recordDockerReadAudit(e, systemID, "container.diff", "container", containerID, err)
+	if err != nil {
+		return respondError(e, http.StatusBadGateway, err.Error())
+	}
+	return e.JSON(http.StatusOK, diff)
+}
+
+// getDockerContainerStats handles GET /api/aether/docker/containers/stats. It returns a live
+// CPU/memory/network reading of one container without storing it.
+func (h *Hub) getDockerContainerStats(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	systemID := query.Get("system")
+	containerID := strings.TrimSpace(query.Get("container"))
+	if containerID == "" {
+		return respondError(e, http.StatusBadRequest, "container is required")
+	}
+	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	system, err := h.resolveSystem(systemID)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	stats, err := system.FetchContainerStatsFromAgent(containerID)
+	h.recordDockerReadAudit(e, systemID, "container.stats", "container", containerID, err)
+	if err != nil {
+		return respondError(e, http.StatusBadGateway, err.Error())
+	}
+	return e.JSON(http.StatusOK, stats)
+}
+
 func (h *Hub) listDockerImages(e *core.RequestEvent) error {
 	systemID := e.Request.URL.Query().Get("system")
 	all := parseBoolParam(e.Request.URL.Query().Get("all"))
 	system, err := h.resolveSystem(systemID)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	images, err := system.FetchDockerImagesFromAgent(all)
+	h.recordDockerReadAudit(e, systemID, "image.list", "image", "", err)
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, images)
 }
@@ -163,6 +230,97 @@ type dockerImageOpPayload struct {
 	Image      string `json:"image"`
 	RegistryID string `json:"registryId"`
 	Force      bool   `json:"force"`
+	// RegistryIDs is an optional ordered fallback list used by pull only; when set,
+	// each registry is tried in turn and RegistryID is ignored.
+	RegistryIDs []string `json:"registryIds"`
+}
+
+// pullRegistryIDs returns the registries a pull goes through. A single RegistryID is a
+// one-entry fallback list, so both request shapes rewrite the image the same way.
+func (p dockerImageOpPayload) pullRegistryIDs() []string {
+	if len(p.RegistryIDs) == 0 && strings.TrimSpace(p.RegistryID) != "" {
+		return []string{p.RegistryID}
+	}
+	return p.RegistryIDs
+}
+
+// dockerHubHosts are the registry hosts that serve Docker Hub; images pulled with their
+// credentials keep their original reference.
+var dockerHubHosts = map[string]struct{}{
+	"docker.io":               {},
+	"index.docker.io":         {},
+	"registry-1.docker.io":    {},
+	"registry.hub.docker.com": {},
+}
+
+// dockerRegistryHost returns the host[:port] of a registry server, which may be given as a bare
+// host or as a URL with a scheme and path such as https://index.docker.io/v1/.
+func dockerRegistryHost(server string) string {
+	server = strings.TrimSpace(server)
+	if server == "" {
+		return ""
+	}
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+	parsed, err := url.Parse(server)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Host)
+}
+
+// dockerImageForRegistry rewrites an image reference to be served by the given registry
+// server, replacing any registry host already present. A first path segment that is
+// "localhost" or contains "." or ":" is a registry host. Docker Hub official images gain
+// the "library/" namespace so pull-through mirrors resolve them; images from other hosts
+// keep their path. Docker Hub itself and servers that do not parse leave the image unchanged.
+func dockerImageForRegistry(image string, server string) string {
+	host := dockerRegistryHost(server)
+	if host == "" {
+		return image
+	}
+	if _, ok := dockerHubHosts[host]; ok {
+		return image
+	}
+	path := image
+	hubImage := true
+	if first, rest, ok := strings.Cut(image, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		path = rest
+		_, hubImage = dockerHubHosts[strings.ToLower(first)]
+	}
+	if hubImage && !strings.Contains(path, "/") {
+		path = "library/" + path
+	}
+	return host + "/" + path
+}
+
+// pullDockerImageWithFallback tries each registry in order until one pull succeeds.
+// It returns the logs, the serving registry id and the image reference that was pulled.
+func (h *Hub) pullDockerImageWithFallback(system *systems.System, image string, registryIDs []string) (string, string, string, error) {
+	var failures []string
+	for _, registryID := range registryIDs {
+		registryID = strings.TrimSpace(registryID)
+		if registryID == "" {
+			continue
+		}
+		auth, err := h.getRegistryAuth(registryID)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", registryID, err))
+			continue
+		}
+		ref := dockerImageForRegistry(image, auth.Server)
+		logs, err := system.PullDockerImageFromAgent(common.DockerImagePullRequest{Image: ref, Registry: auth})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", registryID, err))
+			continue
+		}
+		return logs, registryID, ref, nil
+	}
+	if len(failures) == 0 {
+		return "", "", "", errors.New("registryIds is empty")
+	}
+	return "", "", "", fmt.Errorf("all registries failed: %s", strings.Join(failures, "; "))
 }
 
 func (h *Hub) pullDockerImage(e *core.RequestEvent) error {
@@ -171,17 +329,19 @@ func (h *Hub) pullDockerImage(e *core.RequestEvent) error {
 	}
 	var payload dockerImageOpPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
-	auth, err := h.getRegistryAuth(payload.RegistryID)
-	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	// listed in running jobs only; the agent pull cannot be interrupted
+	job, _ := h.jobs.start("", runningJobTypeDocker, "pull "+payload.Image, payload.System, false)
+	defer h.jobs.finish(job)
+	if payload.RegistryIDs = payload.pullRegistryIDs(); len(payload.RegistryIDs) > 0 {
+		return h.pullDockerImageFromRegistries(e, system, payload)
 	}
-	logs, err := system.PullDockerImageFromAgent(common.DockerImagePullRequest{Image: payload.Image, Registry: auth})
+	logs, err := system.PullDockerImageFromAgent(common.DockerImagePullRequest{Image: payload.Image})
 	status := dockerAuditStatusSuccess
 	message := "pull image"
 	if err != nil {
@@ -197,30 +357,59 @@ func (h *Hub) pullDockerImage(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok", "logs": logs})
 }
 
+// pullDockerImageFromRegistries handles pulls with a registry fallback list and records a
+// single audit entry naming the registry that served the image.
+func (h *Hub) pullDockerImageFromRegistries(e *core.RequestEvent, system *systems.System, payload dockerImageOpPayload) error {
+	logs, registryID, image, err := h.pullDockerImageWithFallback(system, payload.Image, payload.RegistryIDs)
+	status := dockerAuditStatusSuccess
+	message := fmt.Sprintf("pull image %s via registry %s", image, registryID)
+	if err != nil {
+		status = dockerAuditStatusFailed
+		message = err.Error()
+	}
+	if auditErr := h.recordDockerAudit(dockerAuditEntry{
+		SystemID:     payload.System,
+		UserID:       e.Auth.Id,
+		Action:       "image.pull",
+		ResourceType: "image",
+		ResourceID:   payload.Image,
+		Status:       status,
+		Detail:       message,
+	}); auditErr != nil {
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
+	}
+	if err != nil {
+		return respondError(e, http.StatusBadGateway, err.Error())
+	}
+	return e.JSON(http.StatusOK, map[string]any{"status": "ok", "logs": logs, "registryId": registryID, "image": image})
+}
+
 func (h *Hub) pushDockerImage(e *core.RequestEvent) error {
 	if err := requireWritable(e); err != nil {
 		return err
 	}
 	var payload dockerImageOpPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	auth, err := h.getRegistryAuth(payload.RegistryID)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
+	job, _ := h.jobs.start("", runningJobTypeDocker, "push "+payload.Image, payload.System, false)
+	defer h.jobs.finish(job)
 	logs, err := system.PushDockerImageFromAgent(common.DockerImagePushRequest{Image: payload.Image, Registry: auth})
 	status := dockerAuditStatusSuccess
 	message := "push image"
@@ -237,10 +426,10 @@ func (h *Hub) pushDockerImage(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok", "logs": logs})
 }
@@ -251,11 +440,11 @@ func (h *Hub) removeDockerImage(e *core.RequestEvent) error {
 	}
 	var payload dockerImageOpPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	err = system.RemoveDockerImageFromAgent(common.DockerImageRemoveRequest{ImageID: payload.Image, Force: payload.Force})
 	status := dockerAuditStatusSuccess
@@ -273,14 +462,55 @@ func (h *Hub) removeDockerImage(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok"})
 }
 
+type dockerImagePrunePayload struct {
+	System string `json:"system"`
+}
+
+// pruneDockerImages handles POST /api/aether/docker/images/prune. It removes dangling images
+// and returns the removed image ids and the reclaimed space in bytes.
+func (h *Hub) pruneDockerImages(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload dockerImagePrunePayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	system, err := h.resolveSystem(payload.System)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	report, err := system.PruneDockerImagesFromAgent(common.DockerImagePruneRequest{})
+	status := dockerAuditStatusSuccess
+	message := fmt.Sprintf("prune dangling images: %d removed, %d bytes reclaimed", len(report.ImagesDeleted), report.SpaceReclaimed)
+	if err != nil {
+		status = dockerAuditStatusFailed
+		message = err.Error()
+	}
+	if auditErr := h.recordDockerAudit(dockerAuditEntry{
+		SystemID:     payload.System,
+		UserID:       e.Auth.Id,
+		Action:       "image.prune",
+		ResourceType: "image",
+		Status:       status,
+		Detail:       message,
+	}); auditErr != nil {
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
+	}
+	if err != nil {
+		return respondError(e, http.StatusBadGateway, err.Error())
+	}
+	return e.JSON(http.StatusOK, report)
+}
+
 type dockerNetworkPayload struct {
 	System     string            `json:"system"`
 	Name       string            `json:"name"`
@@ -297,11 +527,12 @@ func (h *Hub) listDockerNetworks(e *core.RequestEvent) error {
 	systemID := e.Request.URL.Query().Get("system")
 	system, err := h.resolveSystem(systemID)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	items, err := system.FetchDockerNetworksFromAgent()
+	h.recordDockerReadAudit(e, systemID, "network.list", "network", "", err)
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, items)
 }
@@ -312,11 +543,11 @@ func (h *Hub) createDockerNetwork(e *core.RequestEvent) error {
 	}
 	var payload dockerNetworkPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	err = system.CreateDockerNetworkFromAgent(common.DockerNetworkCreateRequest{
 		Name:       payload.Name,
@@ -342,10 +573,10 @@ func (h *Hub) createDockerNetwork(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok"})
 }
@@ -356,11 +587,11 @@ func (h *Hub) removeDockerNetwork(e *core.RequestEvent) error {
 	}
 	var payload dockerNetworkPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	err = system.RemoveDockerNetworkFromAgent(common.DockerNetworkRemoveRequest{NetworkID: payload.NetworkID})
 	status := dockerAuditStatusSuccess
@@ -378,10 +609,10 @@ func (h *Hub) removeDockerNetwork(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok"})
 }
@@ -399,11 +630,12 @@ func (h *Hub) listDockerVolumes(e *core.RequestEvent) error {
 	systemID := e.Request.URL.Query().Get("system")
 	system, err := h.resolveSystem(systemID)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	items, err := system.FetchDockerVolumesFromAgent()
+	h.recordDockerReadAudit(e, systemID, "volume.list", "volume", "", err)
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, items)
 }
@@ -414,11 +646,11 @@ func (h *Hub) createDockerVolume(e *core.RequestEvent) error {
 	}
 	var payload dockerVolumePayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	err = system.CreateDockerVolumeFromAgent(common.DockerVolumeCreateRequest{
 		Name:    payload.Name,
@@ -441,10 +673,10 @@ func (h *Hub) createDockerVolume(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok"})
 }
@@ -455,11 +687,11 @@ func (h *Hub) removeDockerVolume(e *core.RequestEvent) error {
 	}
 	var payload dockerVolumePayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	err = system.RemoveDockerVolumeFromAgent(common.DockerVolumeRemoveRequest{Name: payload.Name, Force: payload.Force})
 	status := dockerAuditStatusSuccess
@@ -477,10 +709,10 @@ func (h *Hub) removeDockerVolume(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok"})
 }
@@ -498,11 +730,12 @@ func (h *Hub) listDockerComposeProjects(e *core.RequestEvent) error {
 	systemID := e.Request.URL.Query().Get("system")
 	system, err := h.resolveSystem(systemID)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	items, err := system.FetchDockerComposeProjectsFromAgent()
+	h.recordDockerReadAudit(e, systemID, "compose.list", "compose", "", err)
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, items)
 }
@@ -513,11 +746,14 @@ func (h *Hub) createDockerComposeProject(e *core.RequestEvent) error {
 	}
 	var payload dockerComposePayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	if err := validateComposeTemplate(payload.Content); err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	output, err := system.CreateDockerComposeProjectFromAgent(common.DockerComposeProjectCreateRequest{
 		Name:    payload.Name,
@@ -539,10 +775,10 @@ func (h *Hub) createDockerComposeProject(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok", "logs": output})
 }
@@ -553,11 +789,14 @@ func (h *Hub) updateDockerComposeProject(e *core.RequestEvent) error {
 	}
 	var payload dockerComposePayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	if err := validateComposeTemplate(payload.Content); err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	output, err := system.UpdateDockerComposeProjectFromAgent(common.DockerComposeProjectUpdateRequest{
 		Name:    payload.Name,
@@ -579,10 +818,10 @@ func (h *Hub) updateDockerComposeProject(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok", "logs": output})
 }
@@ -593,11 +832,11 @@ func (h *Hub) operateDockerComposeProject(e *core.RequestEvent) error {
 	}
 	var payload dockerComposePayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	output, err := system.OperateDockerComposeProjectFromAgent(common.DockerComposeProjectOperateRequest{
 		Name:      payload.Name,
@@ -618,10 +857,10 @@ func (h *Hub) operateDockerComposeProject(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok", "logs": output})
 }
@@ -632,11 +871,11 @@ func (h *Hub) deleteDockerComposeProject(e *core.RequestEvent) error {
 	}
 	var payload dockerComposePayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	output, err := system.DeleteDockerComposeProjectFromAgent(common.DockerComposeProjectDeleteRequest{
 		Name:       payload.Name,
@@ -657,10 +896,10 @@ func (h *Hub) deleteDockerComposeProject(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok", "logs": output})
 }
@@ -669,11 +908,11 @@ func (h *Hub) getDockerConfig(e *core.RequestEvent) error {
 	systemID := e.Request.URL.Query().Get("system")
 	system, err := h.resolveSystem(systemID)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	config, err := system.FetchDockerConfigFromAgent()
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, config)
 }
@@ -691,11 +930,11 @@ func (h *Hub) updateDockerConfig(e *core.RequestEvent) error {
 	}
 	var payload dockerConfigPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	err = system.UpdateDockerConfigFromAgent(common.DockerConfigUpdateRequest{
 		Content: payload.Content,
@@ -717,10 +956,10 @@ func (h *Hub) updateDockerConfig(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       message,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok"})
 }
@@ -773,7 +1012,7 @@ func (h *Hub) listDockerServiceConfigs(e *core.RequestEvent) error {
 	)
 	if err != nil {
 		h.logServiceConfigError("list service configs failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	items := make([]map[string]any, 0, len(records))
 userIDs := make([]string, 0, len(records))
@@ -797,7 +1036,7 @@ if len(userIDs) > 0 {
 	for _, id := range uniqueList {
 		userRecord, userErr := h.FindRecordById("users", id)
 		if userErr != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": userErr.Error()})
+			return respondError(e, http.StatusInternalServerError, userErr.Error())
 		}
 		usernames[id] = userRecord.GetString("username")
 	}
@@ -821,27 +1060,27 @@ func (h *Hub) createDockerServiceConfig(e *core.RequestEvent) error {
 	}
 	var payload dockerServiceConfigPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	if _, err := h.resolveSystemRecordForUser(e, payload.System); err != nil {
 		return respondSystemAccessError(e, err)
 	}
 	name := strings.TrimSpace(payload.Name)
 	if name == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "name is required"})
+		return respondError(e, http.StatusBadRequest, "name is required")
 	}
 	urlValue, err := validateServiceConfigURL(payload.URL)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	token := strings.TrimSpace(payload.Token)
 	if token == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "token is required"})
+		return respondError(e, http.StatusBadRequest, "token is required")
 	}
 	collection, err := h.FindCollectionByNameOrId("docker_service_configs")
 	if err != nil {
 		h.logServiceConfigError("find service configs collection failed", err, "system", payload.System)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	record := core.NewRecord(collection)
 	record.Set("system", strings.TrimSpace(payload.System))
@@ -850,7 +1089,7 @@ func (h *Hub) createDockerServiceConfig(e *core.RequestEvent) error {
 	record.Set("token", token)
 	if err := h.Save(record); err != nil {
 		h.logServiceConfigError("create service config failed", err, "system", payload.System, "name", name, "url", urlValue)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	h.Logger().Info(
 		"service config created",
@@ -874,20 +1113,20 @@ func (h *Hub) updateDockerServiceConfig(e *core.RequestEvent) error {
 	}
 	var payload dockerServiceConfigUpdatePayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	if strings.TrimSpace(payload.ID) == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return respondError(e, http.StatusBadRequest, "id is required")
 	}
 	if payload.Token != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "token cannot be updated"})
+		return respondError(e, http.StatusBadRequest, "token cannot be updated")
 	}
 	if payload.Name == nil && payload.URL == nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "name or url is required"})
+		return respondError(e, http.StatusBadRequest, "name or url is required")
 	}
 	record, err := h.FindRecordById("docker_service_configs", payload.ID)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "service config not found"})
+		return respondError(e, http.StatusNotFound, "service config not found")
 	}
 	if _, err := h.resolveSystemRecordForUser(e, record.GetString("system")); err != nil {
 		return respondSystemAccessError(e, err)
@@ -895,20 +1134,20 @@ func (h *Hub) updateDockerServiceConfig(e *core.RequestEvent) error {
 	if payload.Name != nil {
 		name := strings.TrimSpace(*payload.Name)
 		if name == "" {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": "name is required"})
+			return respondError(e, http.StatusBadRequest, "name is required")
 		}
 		record.Set("name", name)
 	}
 	if payload.URL != nil {
 		urlValue, err := validateServiceConfigURL(*payload.URL)
 		if err != nil {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return respondError(e, http.StatusBadRequest, err.Error())
 		}
 		record.Set("url", urlValue)
 	}
 	if err := h.Save(record); err != nil {
 		h.logServiceConfigError("update service config failed", err, "id", payload.ID, "system", record.GetString("system"))
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	h.Logger().Info(
 		"service config updated",
@@ -928,18 +1167,18 @@ func (h *Hub) deleteDockerServiceConfig(e *core.RequestEvent) error {
 	}
 	id := strings.TrimSpace(e.Request.URL.Query().Get("id"))
 	if id == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return respondError(e, http.StatusBadRequest, "id is required")
 	}
 	record, err := h.FindRecordById("docker_service_configs", id)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "service config not found"})
+		return respondError(e, http.StatusNotFound, "service config not found")
 	}
 	if _, err := h.resolveSystemRecordForUser(e, record.GetString("system")); err != nil {
 		return respondSystemAccessError(e, err)
 	}
 	if err := h.Delete(record); err != nil {
 		h.logServiceConfigError("delete service config failed", err, "id", id, "system", record.GetString("system"))
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	h.Logger().Info(
 		"service config deleted",
@@ -958,17 +1197,17 @@ func (h *Hub) getDockerServiceConfigContent(e *core.RequestEvent) error {
 	systemID := strings.TrimSpace(query.Get("system"))
 	configID := strings.TrimSpace(query.Get("id"))
 	if systemID == "" || configID == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system and id are required"})
+		return respondError(e, http.StatusBadRequest, "system and id are required")
 	}
 	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
 		return respondSystemAccessError(e, err)
 	}
 	record, err := h.FindRecordById("docker_service_configs", configID)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "service config not found"})
+		return respondError(e, http.StatusNotFound, "service config not found")
 	}
 	if record.GetString("system") != systemID {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system mismatch"})
+		return respondError(e, http.StatusBadRequest, "system mismatch")
 	}
 	targetURL := record.GetString("url")
 	token := record.GetString("token")
@@ -979,7 +1218,7 @@ func (h *Hub) getDockerServiceConfigContent(e *core.RequestEvent) error {
 			"system", systemID,
 			"id", configID,
 		)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": "service config missing url or token"})
+		return respondError(e, http.StatusInternalServerError, "service config missing url or token")
 	}
 	ctx, cancel := context.WithTimeout(e.Request.Context(), 10*time.Second)
 	defer cancel()
@@ -995,12 +1234,12 @@ func (h *Hub) getDockerServiceConfigContent(e *core.RequestEvent) error {
 			"response_size", len(body),
 		)
 		if status == http.StatusForbidden {
-			return e.JSON(http.StatusBadGateway, map[string]string{"error": "upstream rejected token"})
+			return respondError(e, http.StatusBadGateway, "upstream rejected token")
 		}
 		if status != http.StatusOK && status != 0 {
-			return e.JSON(http.StatusBadGateway, map[string]string{"error": fmt.Sprintf("upstream status %d", status)})
+			return respondError(e, http.StatusBadGateway, fmt.Sprintf("upstream status %d", status))
 		}
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": "failed to fetch config content"})
+		return respondError(e, http.StatusBadGateway, "failed to fetch config content")
 	}
 	if status != http.StatusOK {
 		h.logServiceConfigError(
@@ -1011,7 +1250,7 @@ func (h *Hub) getDockerServiceConfigContent(e *core.RequestEvent) error {
 			"url", targetURL,
 			"response_size", len(body),
 		)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": fmt.Sprintf("upstream status %d", status)})
+		return respondError(e, http.StatusBadGateway, fmt.Sprintf("upstream status %d", status))
 	}
 	var response serviceConfigContentResponse
 	if err := json.Unmarshal(body, &response); err != nil {
@@ -1022,7 +1261,7 @@ func (h *Hub) getDockerServiceConfigContent(e *core.RequestEvent) error {
 			"id", configID,
 			"url", targetURL,
 		)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": "invalid upstream response"})
+		return respondError(e, http.StatusBadGateway, "invalid upstream response")
 	}
 	if response.Code != http.StatusOK {
 		message := strings.TrimSpace(response.Message)
@@ -1037,7 +1276,7 @@ func (h *Hub) getDockerServiceConfigContent(e *core.RequestEvent) error {
 			"url", targetURL,
 			"message", message,
 		)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": message})
+		return respondError(e, http.StatusBadGateway, message)
 	}
 	return e.JSON(http.StatusOK, map[string]any{"content": response.Data.Content})
 }
@@ -1048,24 +1287,24 @@ func (h *Hub) updateDockerServiceConfigContent(e *core.RequestEvent) error {
 	}
 	var payload dockerServiceConfigContentPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	systemID := strings.TrimSpace(payload.System)
 	if systemID == "" || strings.TrimSpace(payload.ID) == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system and id are required"})
+		return respondError(e, http.StatusBadRequest, "system and id are required")
 	}
 	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
 		return respondSystemAccessError(e, err)
 	}
 	if strings.TrimSpace(payload.Content) == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "content is required"})
+		return respondError(e, http.StatusBadRequest, "content is required")
 	}
 	record, err := h.FindRecordById("docker_service_configs", payload.ID)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "service config not found"})
+		return respondError(e, http.StatusNotFound, "service config not found")
 	}
 	if record.GetString("system") != systemID {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system mismatch"})
+		return respondError(e, http.StatusBadRequest, "system mismatch")
 	}
 	targetURL := record.GetString("url")
 	token := record.GetString("token")
@@ -1076,7 +1315,7 @@ func (h *Hub) updateDockerServiceConfigContent(e *core.RequestEvent) error {
 			"system", systemID,
 			"id", payload.ID,
 		)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": "service config missing url or token"})
+		return respondError(e, http.StatusInternalServerError, "service config missing url or token")
 	}
 	requestBody, err := json.Marshal(map[string]string{"content": payload.Content})
 	if err != nil {
@@ -1088,7 +1327,7 @@ func (h *Hub) updateDockerServiceConfigContent(e *core.RequestEvent) error {
 			"url", targetURL,
 			"content_len", len(payload.Content),
 		)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to encode content"})
+		return respondError(e, http.StatusInternalServerError, "failed to encode content")
 	}
 	ctx, cancel := context.WithTimeout(e.Request.Context(), 10*time.Second)
 	defer cancel()
@@ -1104,12 +1343,12 @@ func (h *Hub) updateDockerServiceConfigContent(e *core.RequestEvent) error {
 			"response_size", len(body),
 		)
 		if status == http.StatusForbidden {
-			return e.JSON(http.StatusBadGateway, map[string]string{"error": "upstream rejected token"})
+			return respondError(e, http.StatusBadGateway, "upstream rejected token")
 		}
 		if status != http.StatusOK && status != 0 {
-			return e.JSON(http.StatusBadGateway, map[string]string{"error": fmt.Sprintf("upstream status %d", status)})
+			return respondError(e, http.StatusBadGateway, fmt.Sprintf("upstream status %d", status))
 		}
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": "failed to update config content"})
+		return respondError(e, http.StatusBadGateway, "failed to update config content")
 	}
 	if status != http.StatusOK {
 		h.logServiceConfigError(
@@ -1120,7 +1359,7 @@ func (h *Hub) updateDockerServiceConfigContent(e *core.RequestEvent) error {
 			"url", targetURL,
 			"response_size", len(body),
 		)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": fmt.Sprintf("upstream status %d", status)})
+		return respondError(e, http.StatusBadGateway, fmt.Sprintf("upstream status %d", status))
 	}
 	var response serviceConfigStatusResponse
 	if err := json.Unmarshal(body, &response); err != nil {
@@ -1131,7 +1370,7 @@ func (h *Hub) updateDockerServiceConfigContent(e *core.RequestEvent) error {
 			"id", payload.ID,
 			"url", targetURL,
 		)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": "invalid upstream response"})
+		return respondError(e, http.StatusBadGateway, "invalid upstream response")
 	}
 	if response.Code != http.StatusOK {
 		message := strings.TrimSpace(response.Message)
@@ -1146,7 +1385,7 @@ func (h *Hub) updateDockerServiceConfigContent(e *core.RequestEvent) error {
 			"url", targetURL,
 			"message", message,
 		)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": message})
+		return respondError(e, http.StatusBadGateway, message)
 	}
 	h.Logger().Info(
 		"service config content updated",
@@ -1197,7 +1436,7 @@ func (h *Hub) requestServiceConfig(
 func (h *Hub) listDockerRegistries(e *core.RequestEvent) error {
 	records, err := h.FindRecordsByFilter("docker_registries", "", "-created", -1, 0)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	items := make([]map[string]any, 0, len(records))
 userIDs := make([]string, 0, len(records))
@@ -1221,7 +1460,7 @@ if len(userIDs) > 0 {
 	for _, id := range uniqueList {
 		userRecord, userErr := h.FindRecordById("users", id)
 		if userErr != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": userErr.Error()})
+			return respondError(e, http.StatusInternalServerError, userErr.Error())
 		}
 		usernames[id] = userRecord.GetString("username")
 	}
@@ -1261,14 +1500,14 @@ func (h *Hub) createDockerRegistry(e *core.RequestEvent) error {
 	}
 	var payload dockerRegistryPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	if strings.TrimSpace(payload.Name) == "" || strings.TrimSpace(payload.Server) == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "name and server are required"})
+		return respondError(e, http.StatusBadRequest, "name and server are required")
 	}
 	collection, err := h.FindCollectionByNameOrId("docker_registries")
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	record := core.NewRecord(collection)
 	record.Set("name", payload.Name)
@@ -1285,9 +1524,9 @@ func (h *Hub) createDockerRegistry(e *core.RequestEvent) error {
 			Status:       dockerAuditStatusFailed,
 			Detail:       err.Error(),
 		}); auditErr != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+			return respondError(e, http.StatusInternalServerError, auditErr.Error())
 		}
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if auditErr := h.recordDockerAudit(dockerAuditEntry{
 		UserID:       e.Auth.Id,
@@ -1297,7 +1536,7 @@ func (h *Hub) createDockerRegistry(e *core.RequestEvent) error {
 		Status:       dockerAuditStatusSuccess,
 		Detail:       "create registry",
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"id": record.Id})
 }
@@ -1308,14 +1547,14 @@ func (h *Hub) updateDockerRegistry(e *core.RequestEvent) error {
 	}
 	var payload dockerRegistryUpdatePayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	if strings.TrimSpace(payload.ID) == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return respondError(e, http.StatusBadRequest, "id is required")
 	}
 	record, err := h.FindRecordById("docker_registries", payload.ID)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "registry not found"})
+		return respondError(e, http.StatusNotFound, "registry not found")
 	}
 	if payload.Name != nil {
 		record.Set("name", strings.TrimSpace(*payload.Name))
@@ -1338,9 +1577,9 @@ func (h *Hub) updateDockerRegistry(e *core.RequestEvent) error {
 			Status:       dockerAuditStatusFailed,
 			Detail:       err.Error(),
 		}); auditErr != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+			return respondError(e, http.StatusInternalServerError, auditErr.Error())
 		}
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if auditErr := h.recordDockerAudit(dockerAuditEntry{
 		UserID:       e.Auth.Id,
@@ -1350,7 +1589,7 @@ func (h *Hub) updateDockerRegistry(e *core.RequestEvent) error {
 		Status:       dockerAuditStatusSuccess,
 		Detail:       "update registry",
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok"})
 }
@@ -1361,11 +1600,11 @@ func (h *Hub) deleteDockerRegistry(e *core.RequestEvent) error {
 	}
 	id := e.Request.URL.Query().Get("id")
 	if strings.TrimSpace(id) == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return respondError(e, http.StatusBadRequest, "id is required")
 	}
 	record, err := h.FindRecordById("docker_registries", id)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "registry not found"})
+		return respondError(e, http.StatusNotFound, "registry not found")
 	}
 	if err := h.Delete(record); err != nil {
 		if auditErr := h.recordDockerAudit(dockerAuditEntry{
@@ -1376,9 +1615,9 @@ func (h *Hub) deleteDockerRegistry(e *core.RequestEvent) error {
 			Status:       dockerAuditStatusFailed,
 			Detail:       err.Error(),
 		}); auditErr != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+			return respondError(e, http.StatusInternalServerError, auditErr.Error())
 		}
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if auditErr := h.recordDockerAudit(dockerAuditEntry{
 		UserID:       e.Auth.Id,
@@ -1388,7 +1627,7 @@ func (h *Hub) deleteDockerRegistry(e *core.RequestEvent) error {
 		Status:       dockerAuditStatusSuccess,
 		Detail:       "delete registry",
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok"})
 }
@@ -1396,7 +1635,7 @@ func (h *Hub) deleteDockerRegistry(e *core.RequestEvent) error {
 func (h *Hub) listDockerComposeTemplates(e *core.RequestEvent) error {
 	records, err := h.FindRecordsByFilter("docker_compose_templates", "", "-created", -1, 0)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	items := make([]map[string]any, 0, len(records))
 userIDs := make([]string, 0, len(records))
@@ -1420,7 +1659,7 @@ if len(userIDs) > 0 {
 	for _, id := range uniqueList {
 		userRecord, userErr := h.FindRecordById("users", id)
 		if userErr != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": userErr.Error()})
+			return respondError(e, http.StatusInternalServerError, userErr.Error())
 		}
 		usernames[id] = userRecord.GetString("username")
 	}
@@ -1461,17 +1700,17 @@ func (h *Hub) createDockerComposeTemplate(e *core.RequestEvent) error {
 	}
 	var payload dockerComposeTemplatePayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	if strings.TrimSpace(payload.Name) == "" || strings.TrimSpace(payload.Content) == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "name and content are required"})
+		return respondError(e, http.StatusBadRequest, "name and content are required")
 	}
 	if err := validateComposeTemplate(payload.Content); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	collection, err := h.FindCollectionByNameOrId("docker_compose_templates")
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	record := core.NewRecord(collection)
 	record.Set("name", payload.Name)
@@ -1488,9 +1727,9 @@ func (h *Hub) createDockerComposeTemplate(e *core.RequestEvent) error {
 			Status:       dockerAuditStatusFailed,
 			Detail:       err.Error(),
 		}); auditErr != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+			return respondError(e, http.StatusInternalServerError, auditErr.Error())
 		}
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if auditErr := h.recordDockerAudit(dockerAuditEntry{
 		UserID:       e.Auth.Id,
@@ -1500,7 +1739,7 @@ func (h *Hub) createDockerComposeTemplate(e *core.RequestEvent) error {
 		Status:       dockerAuditStatusSuccess,
 		Detail:       "create compose template",
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"id": record.Id})
 }
@@ -1511,14 +1750,14 @@ func (h *Hub) updateDockerComposeTemplate(e *core.RequestEvent) error {
 	}
 	var payload dockerComposeTemplateUpdatePayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	if strings.TrimSpace(payload.ID) == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return respondError(e, http.StatusBadRequest, "id is required")
 	}
 	record, err := h.FindRecordById("docker_compose_templates", payload.ID)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "template not found"})
+		return respondError(e, http.StatusNotFound, "template not found")
 	}
 	if payload.Name != nil {
 		record.Set("name", strings.TrimSpace(*payload.Name))
@@ -1528,7 +1767,7 @@ func (h *Hub) updateDockerComposeTemplate(e *core.RequestEvent) error {
 	}
 	if payload.Content != nil {
 		if err := validateComposeTemplate(*payload.Content); err != nil {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return respondError(e, http.StatusBadRequest, err.Error())
 		}
 		record.Set("content", *payload.Content)
 	}
@@ -1544,9 +1783,9 @@ func (h *Hub) updateDockerComposeTemplate(e *core.RequestEvent) error {
 			Status:       dockerAuditStatusFailed,
 			Detail:       err.Error(),
 		}); auditErr != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+			return respondError(e, http.StatusInternalServerError, auditErr.Error())
 		}
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if auditErr := h.recordDockerAudit(dockerAuditEntry{
 		UserID:       e.Auth.Id,
@@ -1556,7 +1795,7 @@ func (h *Hub) updateDockerComposeTemplate(e *core.RequestEvent) error {
 		Status:       dockerAuditStatusSuccess,
 		Detail:       "update compose template",
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok"})
 }
@@ -1567,11 +1806,11 @@ func (h *Hub) deleteDockerComposeTemplate(e *core.RequestEvent) error {
 	}
 	id := e.Request.URL.Query().Get("id")
 	if strings.TrimSpace(id) == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return respondError(e, http.StatusBadRequest, "id is required")
 	}
 	record, err := h.FindRecordById("docker_compose_templates", id)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "template not found"})
+		return respondError(e, http.StatusNotFound, "template not found")
 	}
 	if err := h.Delete(record); err != nil {
 		if auditErr := h.recordDockerAudit(dockerAuditEntry{
@@ -1582,9 +1821,9 @@ func (h *Hub) deleteDockerComposeTemplate(e *core.RequestEvent) error {
 			Status:       dockerAuditStatusFailed,
 			Detail:       err.Error(),
 		}); auditErr != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+			return respondError(e, http.StatusInternalServerError, auditErr.Error())
 		}
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if auditErr := h.recordDockerAudit(dockerAuditEntry{
 		UserID:       e.Auth.Id,
@@ -1594,11 +1833,14 @@ func (h *Hub) deleteDockerComposeTemplate(e *core.RequestEvent) error {
 		Status:       dockerAuditStatusSuccess,
 		Detail:       "delete compose template",
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"status": "ok"})
 }
 
+// listDockerAudits handles GET /api/aether/docker/audits requests. With page/perPage the
+// result is paginated (perPage is capped at dockerAuditMaxPerPage); totalItems/totalPages
+// always describe the whole filtered set.
 func (h *Hub) listDockerAudits(e *core.RequestEvent) error {
 	query := e.Request.URL.Query()
 	systemID := strings.TrimSpace(query.Get("system"))
@@ -1619,12 +1861,12 @@ func (h *Hub) listDockerAudits(e *core.RequestEvent) error {
 	if startRaw != "" {
 		parsed, err := time.Parse(time.RFC3339, startRaw)
 		if err != nil {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": "start must be RFC3339"})
+			return respondError(e, http.StatusBadRequest, "start must be RFC3339")
 		}
 		startTime = parsed
 		startDate, err := types.ParseDateTime(startTime)
 		if err != nil {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid start time"})
+			return respondError(e, http.StatusBadRequest, "invalid start time")
 		}
 		filters = append(filters, "created >= {:start}")
 		params["start"] = startDate
@@ -1632,42 +1874,54 @@ func (h *Hub) listDockerAudits(e *core.RequestEvent) error {
 	if endRaw != "" {
 		parsed, err := time.Parse(time.RFC3339, endRaw)
 		if err != nil {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": "end must be RFC3339"})
+			return respondError(e, http.StatusBadRequest, "end must be RFC3339")
 		}
 		endTime = parsed
 		endDate, err := types.ParseDateTime(endTime)
 		if err != nil {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid end time"})
+			return respondError(e, http.StatusBadRequest, "invalid end time")
 		}
 		filters = append(filters, "created <= {:end}")
 		params["end"] = endDate
 	}
 	if !startTime.IsZero() && !endTime.IsZero() && startTime.After(endTime) {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "start must be before end"})
+		return respondError(e, http.StatusBadRequest, "start must be before end")
 	}
 
 	limit := -1
 	offset := 0
+	page := 1
 	if pageRaw != "" || perPageRaw != "" {
 		if pageRaw == "" || perPageRaw == "" {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": "page and perPage are required"})
+			return respondError(e, http.StatusBadRequest, "page and perPage are required")
 		}
-		page, err := strconv.Atoi(pageRaw)
-		if err != nil || page <= 0 {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": "page must be a positive integer"})
+		parsedPage, err := strconv.Atoi(pageRaw)
+		if err != nil || parsedPage <= 0 {
+			return respondError(e, http.StatusBadRequest, "page must be a positive integer")
 		}
 		perPage, err := strconv.Atoi(perPageRaw)
 		if err != nil || perPage <= 0 {
-			return e.JSON(http.StatusBadRequest, map[string]string{"error": "perPage must be a positive integer"})
+			return respondError(e, http.StatusBadRequest, "perPage must be a positive integer")
 		}
-		limit = perPage
-		offset = (page - 1) * perPage
+		page = parsedPage
+		limit = min(perPage, dockerAuditMaxPerPage)
+		offset = (page - 1) * limit
 	}
 
 	filter := strings.Join(filters, " && ")
+	// filters are plain comparisons, valid both as a record filter and as SQL for counting
+	var countExp dbx.Expression
+	if len(filters) > 0 {
+		countExp = dbx.NewExp(strings.Join(filters, " AND "), dbx.Params(params))
+	}
+	totalItems64, err := h.CountRecords("docker_audits", countExp)
+	if err != nil {
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	totalItems := int(totalItems64)
 	records, err := h.FindRecordsByFilter("docker_audits", filter, "-created", limit, offset, params)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	items := make([]map[string]any, 0, len(records))
 	userIDs := make([]string, 0, len(records))
@@ -1701,7 +1955,7 @@ func (h *Hub) listDockerAudits(e *core.RequestEvent) error {
 					"stack", string(debug.Stack()),
 					"user_id", id,
 				)
-				return e.JSON(http.StatusInternalServerError, map[string]string{"error": userErr.Error()})
+				return respondError(e, http.StatusInternalServerError, userErr.Error())
 			}
 			usernames[id] = userRecord.GetString("username")
 			userEmails[id] = userRecord.GetString("email")
@@ -1722,7 +1976,22 @@ func (h *Hub) listDockerAudits(e *core.RequestEvent) error {
 			"created":       record.Get("created"),
 		})
 	}
-	return e.JSON(http.StatusOK, map[string]any{"items": items})
+	// without page/perPage every matching entry is returned as a single page
+	perPage := limit
+	if perPage < 0 {
+		perPage = totalItems
+	}
+	totalPages := 0
+	if perPage > 0 {
+		totalPages = (totalItems + perPage - 1) / perPage
+	}
+	return e.JSON(http.StatusOK, map[string]any{
+		"items":      items,
+		"page":       page,
+		"perPage":    perPage,
+		"totalItems": totalItems,
+		"totalPages": totalPages,
+	})
 }
 
 func (h *Hub) getRegistryAuth(registryID string) (*common.DockerRegistryAuth, error) {
@@ -1739,16 +2008,3 @@ func (h *Hub) getRegistryAuth(registryID string) (*common.DockerRegistryAuth, er
 		Password: record.GetString("password"),
 	}, nil
 }
-
-func validateComposeTemplate(content string) error {
-	var payload struct {
-		Services map[string]any `yaml:"services"`
-	}
-	if err := yaml.Unmarshal([]byte(content), &payload); err != nil {
-		return err
-	}
-	if len(payload.Services) == 0 {
-		return errors.New("compose services is required")
-	}
-	return nil
-}
@@ -0,0 +1,188 @@
+// Package hub 提供 compose 项目的合并日志：一次性读取最近日志，以及合并实时日志流。
+// 一次性读取返回每个服务最近 tail 行按时间合并后的文本，每行带服务名前缀，WebSocket 与 SSH 连接的 agent 均支持。
+// hub 按固定间隔向 agent 拉取项目全部服务在上次截止时间之后写入的日志，按时间合并后以 SSE 推送，每行带服务名前缀。
+// 每个服务单次拉取的行数与字节数由 agent 限制，超出时推送 truncated 事件；日志事件的 id 为写入时间（unix 纳秒），
+// 客户端重连时携带 Last-Event-ID 即可从断点续传。客户端断开后当前拉取请求随请求 context 取消，循环随即结束。
+package hub
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"aether/internal/common"
+	"aether/internal/entities/docker"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	dockerComposeLogsPollInterval = 2 * time.Second
+	dockerComposeLogsDefaultTail  = 100
+	dockerComposeLogsMaxTail      = 1000
+	// dockerComposeLogsMaxFailures ends the stream after this many consecutive failed polls.
+	dockerComposeLogsMaxFailures = 3
+)
+
+// dockerComposeLogsFetch fetches one window of compose project logs from the agent.
+type dockerComposeLogsFetch func(ctx context.Context, req common.DockerComposeLogsRequest) (docker.ComposeLogs, error)
+
+// getDockerComposeLogs handles GET /api/aether/docker/compose/projects/logs. It returns the last
+// `tail` lines of every service in the project merged by time, each prefixed with its service.
+func (h *Hub) getDockerComposeLogs(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	systemID := query.Get("system")
+	name := strings.TrimSpace(query.Get("name"))
+	if name == "" {
+		return respondError(e, http.StatusBadRequest, "name is required")
+	}
+	tail, err := parseDockerComposeLogsTail(query.Get("tail"))
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	system, err := h.resolveSystem(systemID)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	logs, err := system.FetchDockerComposeLogsFromAgent(e.Request.Context(), common.DockerComposeLogsRequest{Name: name, Tail: tail})
+	h.recordDockerReadAudit(e, systemID, "compose.logs", "compose", name, err)
+	if err != nil {
+		return respondError(e, http.StatusBadGateway, err.Error())
+	}
+	return e.JSON(http.StatusOK, map[string]any{
+		"status":    "ok",
+		"logs":      formatDockerComposeLogs(logs),
+		"truncated": logs.Truncated,
+	})
+}
+
+// parseDockerComposeLogsTail parses the optional tail query param.
+func parseDockerComposeLogsTail(raw string) (int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return dockerComposeLogsDefaultTail, nil
+	}
+	tail, err := strconv.Atoi(raw)
+	if err != nil || tail <= 0 || tail > dockerComposeLogsMaxTail {
+		return 0, fmt.Errorf("tail must be between 1 and %d", dockerComposeLogsMaxTail)
+	}
+	return tail, nil
+}
+
+// formatDockerComposeLogs renders the lines as text, one "service | line" per line.
+func formatDockerComposeLogs(logs docker.ComposeLogs) string {
+	var builder strings.Builder
+	for _, line := range logs.Lines {
+		builder.WriteString(line.Service)
+		builder.WriteString(" | ")
+		builder.WriteString(line.Line)
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// streamDockerComposeLogs handles GET /api/aether/docker/compose/projects/logs/stream. It streams
+// the logs of every service in the project as server-sent events until the client disconnects.
+func (h *Hub) streamDockerComposeLogs(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	systemID := query.Get("system")
+	name := strings.TrimSpace(query.Get("name"))
+	if name == "" {
+		return respondError(e, http.StatusBadRequest, "name is required")
+	}
+	tail, err := parseDockerComposeLogsTail(query.Get("tail"))
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	system, err := h.resolveSystem(systemID)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	req := common.DockerComposeLogsRequest{Name: name, Tail: tail}
+	// EventSource 重连时从最后收到的事件之后继续
+	if lastID, err := strconv.ParseInt(strings.TrimSpace(e.Request.Header.Get("Last-Event-ID")), 10, 64); err == nil && lastID > 0 {
+		req.Since = lastID
+	}
+
+	header := e.Response.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	e.Response.WriteHeader(http.StatusOK)
+
+	if err := relayDockerComposeLogs(e.Request.Context(), e.Response, e.Flush, system.FetchDockerComposeLogsFromAgent, req, dockerComposeLogsPollInterval); err != nil {
+		h.Logger().Warn("compose log stream ended", "logger", "hub", "system", systemID, "name", name, "err", err)
+	}
+	return nil
+}
+
+// relayDockerComposeLogs polls fetch every interval and writes the lines as SSE events until ctx
+// ends, writing fails, or dockerComposeLogsMaxFailures polls fail in a row. Each poll continues
+// from the cursor of the previous one, so no line is sent twice.
+func relayDockerComposeLogs(ctx context.Context, w io.Writer, flush func() error, fetch dockerComposeLogsFetch, req common.DockerComposeLogsRequest, interval time.Duration) error {
+	failures := 0
+	for {
+		logs, err := fetch(ctx, req)
+		if ctx.Err() != nil {
+			return nil
+		}
+		var writeErr error
+		if err != nil {
+			failures++
+			_, writeErr = fmt.Fprintf(w, "event: error\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+			if writeErr == nil && failures >= dockerComposeLogsMaxFailures {
+				_ = flush()
+				return err
+			}
+		} else {
+			failures = 0
+			writeErr = writeDockerComposeLogEvents(w, logs)
+			if logs.Cursor > req.Since {
+				req.Since = logs.Cursor
+			}
+		}
+		if writeErr != nil {
+			return nil
+		}
+		if err := flush(); err != nil {
+			return nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// writeDockerComposeLogEvents writes one log event per line prefixed with its service, a truncated
+// event per service that hit the agent limit, and finally the cursor as the event id so a
+// reconnecting client resumes after this window even when it was empty.
+func writeDockerComposeLogEvents(w io.Writer, logs docker.ComposeLogs) error {
+	for _, line := range logs.Lines {
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: log\ndata: %s | %s\n\n", line.Timestamp, line.Service, line.Line); err != nil {
+			return err
+		}
+	}
+	for _, service := range logs.Truncated {
+		if _, err := fmt.Fprintf(w, "event: truncated\ndata: %s\n\n", service); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "id: %d\n\n", logs.Cursor)
+	return err
+}
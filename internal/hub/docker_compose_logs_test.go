@@ -0,0 +1,101 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"aether/internal/common"
+	"aether/internal/entities/docker"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayDockerComposeLogs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requests []common.DockerComposeLogsRequest
+	fetch := func(_ context.Context, req common.DockerComposeLogsRequest) (docker.ComposeLogs, error) {
+		requests = append(requests, req)
+		switch len(requests) {
+		case 1:
+			return docker.ComposeLogs{
+				Lines: []docker.ComposeLogLine{
+					{Service: "db", Timestamp: 10, Line: "ready"},
+					{Service: "web", Timestamp: 20, Line: "listening"},
+				},
+				Cursor:    30,
+				Truncated: []string{"web"},
+			}, nil
+		case 2:
+			return docker.ComposeLogs{}, errors.New("agent busy")
+		default:
+			// 客户端断开
+			cancel()
+			return docker.ComposeLogs{}, context.Canceled
+		}
+	}
+
+	var out bytes.Buffer
+	flushes := 0
+	err := relayDockerComposeLogs(ctx, &out, func() error { flushes++; return nil }, fetch, common.DockerComposeLogsRequest{Name: "app", Since: 5, Tail: 50}, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, 2, flushes)
+
+	require.Len(t, requests, 3)
+	assert.Equal(t, int64(5), requests[0].Since)
+	assert.Equal(t, int64(30), requests[1].Since, "the next poll continues from the cursor")
+	assert.Equal(t, int64(30), requests[2].Since, "a failed poll keeps the cursor")
+	assert.Equal(t, 50, requests[2].Tail)
+
+	assert.Equal(t, "id: 10\nevent: log\ndata: db | ready\n\n"+
+		"id: 20\nevent: log\ndata: web | listening\n\n"+
+		"event: truncated\ndata: web\n\n"+
+		"id: 30\n\n"+
+		"event: error\ndata: agent busy\n\n", out.String())
+}
+
+func TestRelayDockerComposeLogsStopsAfterRepeatedFailures(t *testing.T) {
+	calls := 0
+	fetch := func(context.Context, common.DockerComposeLogsRequest) (docker.ComposeLogs, error) {
+		calls++
+		return docker.ComposeLogs{}, errors.New("agent offline")
+	}
+	var out bytes.Buffer
+	err := relayDockerComposeLogs(context.Background(), &out, func() error { return nil }, fetch, common.DockerComposeLogsRequest{Name: "app"}, time.Millisecond)
+	assert.EqualError(t, err, "agent offline")
+	assert.Equal(t, dockerComposeLogsMaxFailures, calls)
+}
+
+func TestParseDockerComposeLogsTail(t *testing.T) {
+	tail, err := parseDockerComposeLogsTail("")
+	require.NoError(t, err)
+	assert.Equal(t, dockerComposeLogsDefaultTail, tail)
+
+	tail, err = parseDockerComposeLogsTail(" 20 ")
+	require.NoError(t, err)
+	assert.Equal(t, 20, tail)
+
+	for _, raw := range []string{"0", "-1", "abc", "1001"} {
+		_, err := parseDockerComposeLogsTail(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestFormatDockerComposeLogs(t *testing.T) {
+	text := formatDockerComposeLogs(docker.ComposeLogs{
+		Lines: []docker.ComposeLogLine{
+			{Service: "db", Timestamp: 10, Line: "ready"},
+			{Service: "web", Timestamp: 20, Line: "listening"},
+		},
+	})
+	assert.Equal(t, "db | ready\nweb | listening\n", text)
+	assert.Empty(t, formatDockerComposeLogs(docker.ComposeLogs{}))
+}
@@ -0,0 +1,244 @@
+// Package hub 提供 Compose 模板渲染预览。
+// 按 docker compose 的变量替换规则（$VAR、${VAR}、${VAR:-default}、${VAR:?err} 等）代入 env，
+// 并对渲染结果重新执行 validateComposeTemplate，提前暴露只会在部署时出现的替换错误。
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type dockerComposeRenderPayload struct {
+	// ID renders a saved template; Content and Env override its stored values when set.
+	ID      string  `json:"id"`
+	Content *string `json:"content"`
+	Env     *string `json:"env"`
+}
+
+type dockerComposeRenderResponse struct {
+	Rendered string   `json:"rendered"`
+	Valid    bool     `json:"valid"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+// parseComposeEnv parses dotenv content: KEY=VALUE per line, blank lines and # comments
+// skipped, an optional "export " prefix and matching surrounding quotes removed.
+func parseComposeEnv(content string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for index, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isComposeVarName(key) {
+			return nil, fmt.Errorf("env line %d: expected KEY=VALUE", index+1)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+func isComposeVarName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// interpolateCompose substitutes variables the way docker compose does. "$$" yields a
+// literal "$". Unset variables without a default render empty and are reported as warnings;
+// ${VAR:?msg} / ${VAR?msg} and malformed expressions are reported as errors. Defaults and
+// alternative values may themselves contain expressions, e.g. ${A:-${B}}.
+func interpolateCompose(content string, vars map[string]string) (string, []string, []string) {
+	unset := map[string]struct{}{}
+	out, errs := interpolateComposeVars(content, vars, unset)
+	warnings := make([]string, 0, len(unset))
+	for name := range unset {
+		warnings = append(warnings, fmt.Sprintf("variable %s is not set, defaulting to a blank string", name))
+	}
+	sort.Strings(warnings)
+	return out, errs, warnings
+}
+
+// interpolateComposeVars substitutes the variables of content and records unset names in unset.
+func interpolateComposeVars(content string, vars map[string]string, unset map[string]struct{}) (string, []string) {
+	var out strings.Builder
+	var errs []string
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if c != '$' || i+1 >= len(content) {
+			out.WriteByte(c)
+			continue
+		}
+		next := content[i+1]
+		switch {
+		case next == '$':
+			out.WriteByte('$')
+			i++
+		case next == '{':
+			end := composeExprEnd(content[i+2:])
+			if end < 0 {
+				errs = append(errs, fmt.Sprintf("unterminated variable expression at offset %d", i))
+				out.WriteString(content[i:])
+				i = len(content)
+				continue
+			}
+			expr := content[i+2 : i+2+end]
+			value, exprErrs := resolveComposeExpr(expr, vars, unset)
+			errs = append(errs, exprErrs...)
+			out.WriteString(value)
+			i += 2 + end
+		case next == '_' || (next >= 'a' && next <= 'z') || (next >= 'A' && next <= 'Z'):
+			j := i + 1
+			for j < len(content) && isComposeVarName(content[i+1:j+1]) {
+				j++
+			}
+			name := content[i+1 : j]
+			value, ok := vars[name]
+			if !ok {
+				unset[name] = struct{}{}
+			}
+			out.WriteString(value)
+			i = j - 1
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String(), errs
+}
+
+// composeExprEnd returns the index of the "}" closing an expression whose "${" precedes s,
+// skipping nested expressions and "$$" escapes, or -1 when it is not closed.
+func composeExprEnd(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '$':
+			i++
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// resolveComposeExpr evaluates the expression inside ${...}. The default, error message or
+// alternative value is only interpolated when it is used.
+func resolveComposeExpr(expr string, vars map[string]string, unset map[string]struct{}) (string, []string) {
+	nameEnd := 0
+	for nameEnd < len(expr) && isComposeVarName(expr[:nameEnd+1]) {
+		nameEnd++
+	}
+	name := expr[:nameEnd]
+	if name == "" {
+		return "", []string{fmt.Sprintf("invalid variable expression ${%s}", expr)}
+	}
+	value, set := vars[name]
+	rest := expr[nameEnd:]
+	if rest == "" {
+		if !set {
+			unset[name] = struct{}{}
+		}
+		return value, nil
+	}
+	// the ":" forms also treat an empty value as unset
+	emptyIsUnset := strings.HasPrefix(rest, ":")
+	operator := strings.TrimPrefix(rest, ":")
+	if operator == "" {
+		return "", []string{fmt.Sprintf("invalid variable expression ${%s}", expr)}
+	}
+	missing := !set || (emptyIsUnset && value == "")
+	arg := operator[1:]
+	switch operator[0] {
+	case '-':
+		if missing {
+			return interpolateComposeVars(arg, vars, unset)
+		}
+		return value, nil
+	case '?':
+		if missing {
+			message, errs := interpolateComposeVars(arg, vars, unset)
+			if message == "" {
+				message = "required variable is missing a value"
+			}
+			return "", append(errs, fmt.Sprintf("variable %s: %s", name, message))
+		}
+		return value, nil
+	case '+':
+		if missing {
+			return "", nil
+		}
+		return interpolateComposeVars(arg, vars, unset)
+	default:
+		return "", []string{fmt.Sprintf("invalid variable expression ${%s}", expr)}
+	}
+}
+
+// renderDockerComposeTemplate handles POST /api/aether/docker/compose-templates/render requests.
+// Render problems are returned in the response body rather than as an error status.
+func (h *Hub) renderDockerComposeTemplate(e *core.RequestEvent) error {
+	var payload dockerComposeRenderPayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	content := ""
+	env := ""
+	if id := strings.TrimSpace(payload.ID); id != "" {
+		record, err := h.FindRecordById("docker_compose_templates", id)
+		if err != nil {
+			return respondError(e, http.StatusNotFound, "template not found")
+		}
+		content = record.GetString("content")
+		env = record.GetString("env")
+	}
+	if payload.Content != nil {
+		content = *payload.Content
+	}
+	if payload.Env != nil {
+		env = *payload.Env
+	}
+	if strings.TrimSpace(content) == "" {
+		return respondError(e, http.StatusBadRequest, "content is required")
+	}
+
+	response := dockerComposeRenderResponse{Errors: []string{}, Warnings: []string{}}
+	vars, err := parseComposeEnv(env)
+	if err != nil {
+		response.Errors = append(response.Errors, err.Error())
+		return e.JSON(http.StatusOK, response)
+	}
+	rendered, errs, warnings := interpolateCompose(content, vars)
+	response.Rendered = rendered
+	response.Errors = append(response.Errors, errs...)
+	response.Warnings = append(response.Warnings, warnings...)
+	if err := validateComposeTemplate(rendered); err != nil {
+		response.Errors = append(response.Errors, err.Error())
+	}
+	response.Valid = len(response.Errors) == 0
+	return e.JSON(http.StatusOK, response)
+}
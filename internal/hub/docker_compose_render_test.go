@@ -0,0 +1,69 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolateCompose(t *testing.T) {
+	vars := map[string]string{"A": "a", "B": "b", "EMPTY": ""}
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		errs     int
+		warnings []string
+	}{
+		{"escaped dollar", "cost: $$5 and $${A}", "cost: $5 and ${A}", 0, nil},
+		{"bare and braced", "$A-${B}", "a-b", 0, nil},
+		{"lone dollar", "price $ 5", "price $ 5", 0, nil},
+		{"colon default on empty", "${EMPTY:-x}", "x", 0, nil},
+		{"default keeps empty", "${EMPTY-x}", "", 0, nil},
+		{"default on unset", "${MISSING-x}|${MISSING:-y}", "x|y", 0, nil},
+		{"default unused", "${A:-x}", "a", 0, nil},
+		{"colon required on empty", "${EMPTY:?must be set}", "", 1, nil},
+		{"required keeps empty", "${EMPTY?must be set}", "", 0, nil},
+		{"required on unset", "${MISSING?}", "", 1, nil},
+		{"required set", "${A:?must be set}", "a", 0, nil},
+		{"alternative", "${A:+alt}|${EMPTY:+alt}|${EMPTY+alt}|${MISSING+alt}", "alt||alt|", 0, nil},
+		{"unset warns", "$MISSING ${OTHER}", " ", 0, []string{
+			"variable MISSING is not set, defaulting to a blank string",
+			"variable OTHER is not set, defaulting to a blank string",
+		}},
+		{"nested default", "${MISSING:-${B}}", "b", 0, nil},
+		{"nested default chain", "${MISSING:-${OTHER:-x}}-${A:-${OTHER}}", "x-a", 0, nil},
+		{"nested default unset", "${MISSING:-${OTHER}}", "", 0, []string{
+			"variable OTHER is not set, defaulting to a blank string",
+		}},
+		{"nested alternative", "${A:+[${B}]}", "[b]", 0, nil},
+		{"nested escape", "${MISSING:-$${B}}", "${B}", 0, nil},
+		{"nested required", "${MISSING:-${OTHER:?needed}}", "", 1, nil},
+		{"unterminated", "x ${A:-${B}", "x ${A:-${B}", 1, nil},
+		{"invalid name", "${1A}", "", 1, nil},
+		{"invalid operator", "${A:}|${A*x}", "|", 2, nil},
+	}
+	for _, test := range tests {
+		out, errs, warnings := interpolateCompose(test.content, vars)
+		assert.Equal(t, test.expected, out, test.name)
+		assert.Len(t, errs, test.errs, test.name)
+		if test.warnings == nil {
+			assert.Empty(t, warnings, test.name)
+		} else {
+			assert.Equal(t, test.warnings, warnings, test.name)
+		}
+	}
+}
+
+func TestParseComposeEnv(t *testing.T) {
+	vars, err := parseComposeEnv("# comment\n\nexport A=1\nB = \"two words\"\nC='x'\nD=\n")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"A": "1", "B": "two words", "C": "x", "D": ""}, vars)
+
+	_, err = parseComposeEnv("A=1\nnot a pair\n")
+	assert.EqualError(t, err, "env line 2: expected KEY=VALUE")
+}
@@ -0,0 +1,234 @@
+// Package hub 提供 Compose 内容校验。
+// 模板保存与编排项目创建/更新前校验 compose 内容：services 不能为空，每个服务需配置 image 或 build（或通过 extends 继承），
+// 同一文件内宿主机端口不能重复发布。错误信息带 YAML 行号，便于定位。
+// 含变量替换（$）的端口无法在替换前确定，跳过重复检查，由部署时的 docker compose 报告。
+package hub
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// composePortRangeMax caps the number of host ports expanded from one port range.
+const composePortRangeMax = 1024
+
+// composePublishedPort is a host port binding declared by a service.
+type composePublishedPort struct {
+	hostIP   string
+	port     int
+	protocol string
+}
+
+type composePortOwner struct {
+	service string
+	line    int
+}
+
+// validateComposeTemplate checks compose content before it is stored or sent to an agent.
+func validateComposeTemplate(content string) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		return err
+	}
+	if len(document.Content) == 0 {
+		return errors.New("compose services is required")
+	}
+	root := resolveYAMLAlias(document.Content[0])
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: compose file must be a mapping", root.Line)
+	}
+	services := yamlMappingValue(root, "services")
+	if services == nil || services.Kind != yaml.MappingNode || len(services.Content) == 0 {
+		return errors.New("compose services is required")
+	}
+
+	published := make(map[composePublishedPort]composePortOwner)
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		name := services.Content[i].Value
+		service := resolveYAMLAlias(services.Content[i+1])
+		if service.Kind != yaml.MappingNode {
+			return fmt.Errorf("line %d: service %q must be a mapping", service.Line, name)
+		}
+		// decoding resolves merge keys, so image/build inherited through "<<" is honoured
+		var definition struct {
+			Image   any `yaml:"image"`
+			Build   any `yaml:"build"`
+			Extends any `yaml:"extends"`
+		}
+		if err := service.Decode(&definition); err != nil {
+			return err
+		}
+		if definition.Image == nil && definition.Build == nil && definition.Extends == nil {
+			return fmt.Errorf("line %d: service %q requires image or build", services.Content[i].Line, name)
+		}
+		if err := checkComposeServicePorts(name, service, published); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkComposeServicePorts records the host ports published by a service and rejects ports
+// already published earlier in the file.
+func checkComposeServicePorts(service string, node *yaml.Node, published map[composePublishedPort]composePortOwner) error {
+	ports := yamlMappingValue(node, "ports")
+	if ports == nil || ports.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, item := range ports.Content {
+		item = resolveYAMLAlias(item)
+		bindings, err := parseComposePortEntry(item)
+		if err != nil {
+			return fmt.Errorf("line %d: service %q: %w", item.Line, service, err)
+		}
+		for _, binding := range bindings {
+			if owner, ok := findComposePortConflict(published, binding); ok {
+				return fmt.Errorf("line %d: service %q publishes %d/%s already published by service %q (line %d)",
+					item.Line, service, binding.port, binding.protocol, owner.service, owner.line)
+			}
+			published[binding] = composePortOwner{service: service, line: item.Line}
+		}
+	}
+	return nil
+}
+
+// findComposePortConflict reports an earlier binding of the same port and protocol whose host
+// address overlaps; an empty or wildcard address overlaps every address.
+func findComposePortConflict(published map[composePublishedPort]composePortOwner, binding composePublishedPort) (composePortOwner, bool) {
+	if owner, ok := published[binding]; ok {
+		return owner, true
+	}
+	for existing, owner := range published {
+		if existing.port != binding.port || existing.protocol != binding.protocol {
+			continue
+		}
+		if isComposeWildcardIP(existing.hostIP) || isComposeWildcardIP(binding.hostIP) {
+			return owner, true
+		}
+	}
+	return composePortOwner{}, false
+}
+
+func isComposeWildcardIP(ip string) bool {
+	return ip == "" || ip == "0.0.0.0" || ip == "::"
+}
+
+// parseComposePortEntry returns the host ports bound by one ports entry in short
+// ("[ip:]host:container[/proto]") or long (mapping with published/host_ip/protocol) syntax.
+// Entries without a fixed host port or containing variables yield no bindings.
+func parseComposePortEntry(node *yaml.Node) ([]composePublishedPort, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return parseComposeShortPort(node.Value)
+	case yaml.MappingNode:
+		var entry struct {
+			Published any    `yaml:"published"`
+			HostIP    string `yaml:"host_ip"`
+			Protocol  string `yaml:"protocol"`
+		}
+		if err := node.Decode(&entry); err != nil {
+			return nil, err
+		}
+		if entry.Published == nil {
+			return nil, nil
+		}
+		return expandComposeHostPorts(entry.HostIP, fmt.Sprint(entry.Published), entry.Protocol)
+	default:
+		return nil, errors.New("invalid ports entry")
+	}
+}
+
+func parseComposeShortPort(spec string) ([]composePublishedPort, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.Contains(spec, "$") {
+		return nil, nil
+	}
+	protocol := ""
+	if index := strings.LastIndex(spec, "/"); index >= 0 {
+		spec, protocol = spec[:index], spec[index+1:]
+	}
+	hostIP := ""
+	if strings.HasPrefix(spec, "[") {
+		end := strings.Index(spec, "]:")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid port %q", spec)
+		}
+		hostIP, spec = spec[1:end], spec[end+2:]
+	}
+	parts := strings.Split(spec, ":")
+	switch len(parts) {
+	case 1:
+		// container port only, the host port is assigned at random
+		return nil, nil
+	case 2:
+		return expandComposeHostPorts(hostIP, parts[0], protocol)
+	case 3:
+		if hostIP != "" {
+			return nil, fmt.Errorf("invalid port %q", spec)
+		}
+		return expandComposeHostPorts(parts[0], parts[1], protocol)
+	default:
+		return nil, fmt.Errorf("invalid port %q", spec)
+	}
+}
+
+// expandComposeHostPorts expands a host port or port range; an empty port yields no bindings.
+func expandComposeHostPorts(hostIP string, hostPorts string, protocol string) ([]composePublishedPort, error) {
+	hostPorts = strings.TrimSpace(hostPorts)
+	if hostPorts == "" || strings.Contains(hostPorts, "$") {
+		return nil, nil
+	}
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	start, end, isRange := strings.Cut(hostPorts, "-")
+	first, err := parseComposePortNumber(start)
+	if err != nil {
+		return nil, err
+	}
+	last := first
+	if isRange {
+		if last, err = parseComposePortNumber(end); err != nil {
+			return nil, err
+		}
+	}
+	if last < first || last-first >= composePortRangeMax {
+		return nil, fmt.Errorf("invalid port range %q", hostPorts)
+	}
+	hostIP = strings.Trim(strings.TrimSpace(hostIP), "[]")
+	bindings := make([]composePublishedPort, 0, last-first+1)
+	for port := first; port <= last; port++ {
+		bindings = append(bindings, composePublishedPort{hostIP: hostIP, port: port, protocol: protocol})
+	}
+	return bindings, nil
+}
+
+func parseComposePortNumber(value string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", value)
+	}
+	return port, nil
+}
+
+// yamlMappingValue returns the value node of key in a mapping node, or nil.
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return resolveYAMLAlias(node.Content[i+1])
+		}
+	}
+	return nil
+}
+
+func resolveYAMLAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
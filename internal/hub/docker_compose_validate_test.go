@@ -0,0 +1,81 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateComposeTemplate(t *testing.T) {
+	valid := `
+x-base: &base
+  image: nginx:alpine
+services:
+  web:
+    <<: *base
+    ports:
+      - "8080:80"
+      - "127.0.0.1:9090:90/udp"
+      - "3000"
+  api:
+    build: ./api
+    ports:
+      - "9090:90"
+      - target: 443
+        published: 8443
+  child:
+    extends:
+      file: common.yml
+      service: base
+    ports:
+      - "${PORT}:80"
+      - "8080:80/udp"
+`
+	require.NoError(t, validateComposeTemplate(valid))
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "empty", content: "", want: "compose services is required"},
+		{name: "no services", content: "services: {}\n", want: "compose services is required"},
+		{name: "syntax", content: "services:\n  web:\n    image: [\n", want: "line"},
+		{
+			name:    "missing image and build",
+			content: "services:\n  web:\n    image: nginx\n  worker:\n    command: run\n",
+			want:    `line 4: service "worker" requires image or build`,
+		},
+		{
+			name:    "duplicate port",
+			content: "services:\n  web:\n    image: nginx\n    ports:\n      - \"8080:80\"\n  api:\n    image: api\n    ports:\n      - published: \"8080\"\n        target: 80\n",
+			want:    `line 9: service "api" publishes 8080/tcp already published by service "web" (line 5)`,
+		},
+		{
+			name:    "duplicate in range",
+			content: "services:\n  web:\n    image: nginx\n    ports:\n      - \"0.0.0.0:8000-8010:8000-8010\"\n      - \"127.0.0.1:8005:80\"\n",
+			want:    `line 6: service "web" publishes 8005/tcp`,
+		},
+		{
+			name:    "invalid port",
+			content: "services:\n  web:\n    image: nginx\n    ports:\n      - \"http:80\"\n",
+			want:    `line 5: service "web": invalid port`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateComposeTemplate(tt.content)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestValidateComposeTemplateDistinctHostIPs(t *testing.T) {
+	content := "services:\n  a:\n    image: a\n    ports:\n      - \"127.0.0.1:8080:80\"\n  b:\n    image: b\n    ports:\n      - \"127.0.0.2:8080:80\"\n      - \"[::1]:8080:80\"\n"
+	assert.NoError(t, validateComposeTemplate(content))
+}
@@ -3,6 +3,7 @@
 package hub
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -11,6 +12,7 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"aether/internal/common"
@@ -25,8 +27,27 @@ const (
 	dataCleanupConfigCollection = "docker_data_cleanup_configs"
 	dataCleanupRunsCollection   = "docker_data_cleanup_runs"
 	dataCleanupKeyEnv           = "DATA_CLEANUP_KEY"
+
+	// upper bounds for per-module timeout overrides (seconds)
+	dataCleanupMaxListTimeoutSec   = 600
+	dataCleanupMaxActionTimeoutSec = 24 * 60 * 60
 )
 
+var errDataCleanupRunCancelled = errors.New("cleanup run cancelled")
+
+// dataCleanupJobPollGrace is how long a run keeps polling an unreachable agent job before
+// failing the module, covering transient WebSocket disconnects.
+const dataCleanupJobPollGrace = 2 * time.Minute
+
+// dataCleanupCancelWait bounds how long a cancelled run waits for the agent job to stop so the
+// amount deleted before the cancellation can be recorded.
+const dataCleanupCancelWait = 10 * time.Second
+
+// dataCleanupItemProgressScale is the amount an in-flight item must delete to count as half done.
+// Agent jobs do not know how many objects a prefix holds, so progress within an item approaches
+// but never reaches a whole item until the agent marks it done.
+const dataCleanupItemProgressScale = 100000
+
 var dataCleanupRedisPatterns = []string{
 	"task:*",
 	"pending_queue",
@@ -42,12 +63,163 @@ var dataCleanupRedisPatterns = []string{
 	"processing:*",
 }
 
+// dataCleanupTimeouts holds optional per-module timeout overrides in seconds.
+// Zero keeps the agent defaults (20s for listing, 30m for cleanup).
+type dataCleanupTimeouts struct {
+	ListTimeoutSec   int `json:"listTimeoutSec,omitempty"`
+	ActionTimeoutSec int `json:"actionTimeoutSec,omitempty"`
+}
+
+func (t dataCleanupTimeouts) validate(module string) error {
+	if t.ListTimeoutSec < 0 || t.ListTimeoutSec > dataCleanupMaxListTimeoutSec {
+		return fmt.Errorf("%s listTimeoutSec must be between 0 and %d", module, dataCleanupMaxListTimeoutSec)
+	}
+	if t.ActionTimeoutSec < 0 || t.ActionTimeoutSec > dataCleanupMaxActionTimeoutSec {
+		return fmt.Errorf("%s actionTimeoutSec must be between 0 and %d", module, dataCleanupMaxActionTimeoutSec)
+	}
+	return nil
+}
+
 type dataCleanupMySQLStored struct {
 	Host     string   `json:"host"`
 	Port     int      `json:"port"`
 	Username string   `json:"username,omitempty"`
 	Database string   `json:"database,omitempty"`
 	Tables   []string `json:"tables,omitempty"`
+	// Mode is "delete" (default) or "truncate"
+	Mode string `json:"mode,omitempty"`
+	// Conditions restrict the delete of individual tables to matching rows
+	Conditions []dataCleanupMySQLCondition `json:"conditions,omitempty"`
+	dataCleanupTimeouts
+	common.DataCleanupTLS
+}
+
+// dataCleanupMySQLCondition is the stored form of common.DataCleanupMySQLCondition.
+type dataCleanupMySQLCondition struct {
+	Table string   `json:"table"`
+	Where string   `json:"where"`
+	Args  []string `json:"args,omitempty"`
+}
+
+// normalizeDataCleanupMySQLConditions validates the conditions of a MySQL cleanup config. Every
+// condition must name a configured table, at most once, and conditions require delete mode.
+func normalizeDataCleanupMySQLConditions(conditions []dataCleanupMySQLCondition, tables []string, mode string) ([]dataCleanupMySQLCondition, error) {
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	if mode != common.DataCleanupMySQLModeDelete {
+		return nil, errors.New("mysql conditions require delete mode")
+	}
+	configured := make(map[string]struct{}, len(tables))
+	for _, table := range tables {
+		configured[table] = struct{}{}
+	}
+	seen := make(map[string]struct{}, len(conditions))
+	normalized := make([]dataCleanupMySQLCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		condition.Table = strings.TrimSpace(condition.Table)
+		condition.Where = strings.TrimSpace(condition.Where)
+		if err := condition.toCommon().Validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := configured[condition.Table]; !ok {
+			return nil, fmt.Errorf("mysql condition table %q is not a cleanup table", condition.Table)
+		}
+		if _, ok := seen[condition.Table]; ok {
+			return nil, fmt.Errorf("mysql condition for table %q is duplicated", condition.Table)
+		}
+		seen[condition.Table] = struct{}{}
+		normalized = append(normalized, condition)
+	}
+	return normalized, nil
+}
+
+// normalizeDataCleanupStored validates the module configs of one system and normalizes them in
+// place to the form that is stored. Every path that writes a cleanup config goes through it, so a
+// config the agent would reject is refused on save rather than in the middle of a run.
+func normalizeDataCleanupStored(
+	mysqlStored *dataCleanupMySQLStored,
+	redisStored *dataCleanupRedisStored,
+	minioStored *dataCleanupMinioStored,
+	esStored *dataCleanupESStored,
+) error {
+	for module, port := range map[string]int{
+		"mysql": mysqlStored.Port,
+		"redis": redisStored.Port,
+		"minio": minioStored.Port,
+		"es":    esStored.Port,
+	} {
+		if port < 0 || port > 65535 {
+			return fmt.Errorf("%s port must be between 0 and 65535", module)
+		}
+	}
+	if redisStored.DB < 0 {
+		return errors.New("redis db must not be negative")
+	}
+	for module, timeouts := range map[string]dataCleanupTimeouts{
+		"mysql": mysqlStored.dataCleanupTimeouts,
+		"redis": redisStored.dataCleanupTimeouts,
+		"minio": minioStored.dataCleanupTimeouts,
+		"es":    esStored.dataCleanupTimeouts,
+	} {
+		if err := timeouts.validate(module); err != nil {
+			return err
+		}
+	}
+	if minioStored.Concurrency < 0 || minioStored.Concurrency > common.DataCleanupMinioMaxConcurrency {
+		return fmt.Errorf("minio concurrency must be between 0 and %d", common.DataCleanupMinioMaxConcurrency)
+	}
+
+	mysqlStored.Host = strings.TrimSpace(mysqlStored.Host)
+	mysqlStored.Username = strings.TrimSpace(mysqlStored.Username)
+	mysqlStored.Database = strings.TrimSpace(mysqlStored.Database)
+	mysqlStored.Tables = normalizeStringSlice(mysqlStored.Tables)
+	mode, err := common.NormalizeDataCleanupMySQLMode(mysqlStored.Mode)
+	if err != nil {
+		return err
+	}
+	mysqlStored.Mode = mode
+	mysqlStored.Conditions, err = normalizeDataCleanupMySQLConditions(mysqlStored.Conditions, mysqlStored.Tables, mode)
+	if err != nil {
+		return err
+	}
+
+	redisStored.Host = strings.TrimSpace(redisStored.Host)
+	redisStored.Username = strings.TrimSpace(redisStored.Username)
+	redisStored.Patterns = normalizeStringSlice(redisStored.Patterns)
+	if len(redisStored.Patterns) == 0 {
+		redisStored.Patterns = append([]string{}, dataCleanupRedisPatterns...)
+	}
+	redisStored.Filter, err = redisStored.Filter.Normalize()
+	if err != nil {
+		return err
+	}
+
+	minioStored.Host = strings.TrimSpace(minioStored.Host)
+	minioStored.AccessKey = strings.TrimSpace(minioStored.AccessKey)
+	minioStored.Bucket = strings.TrimSpace(minioStored.Bucket)
+	minioStored.Prefixes = normalizeStringSlice(minioStored.Prefixes)
+
+	esStored.Host = strings.TrimSpace(esStored.Host)
+	esStored.Username = strings.TrimSpace(esStored.Username)
+	esStored.Indices = normalizeStringSlice(esStored.Indices)
+	esStored.Mode, err = common.NormalizeDataCleanupESMode(esStored.Mode)
+	return err
+}
+
+func (c dataCleanupMySQLCondition) toCommon() common.DataCleanupMySQLCondition {
+	return common.DataCleanupMySQLCondition{Table: c.Table, Where: c.Where, Args: c.Args}
+}
+
+func dataCleanupMySQLConditionsToCommon(conditions []dataCleanupMySQLCondition) []common.DataCleanupMySQLCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	converted := make([]common.DataCleanupMySQLCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		converted = append(converted, condition.toCommon())
+	}
+	return converted
 }
 
 type dataCleanupRedisStored struct {
@@ -56,6 +228,10 @@ type dataCleanupRedisStored struct {
 	Username string   `json:"username,omitempty"`
 	DB       int      `json:"db"`
 	Patterns []string `json:"patterns,omitempty"`
+	// Filter limits deletion to keys of a type or TTL range; the zero value deletes every match
+	Filter common.DataCleanupRedisFilter `json:"filter"`
+	dataCleanupTimeouts
+	common.DataCleanupTLS
 }
 
 type dataCleanupMinioStored struct {
@@ -64,6 +240,10 @@ type dataCleanupMinioStored struct {
 	AccessKey string   `json:"accessKey,omitempty"`
 	Bucket    string   `json:"bucket,omitempty"`
 	Prefixes  []string `json:"prefixes,omitempty"`
+	// Concurrency is the number of prefixes cleaned in parallel; 0 uses the agent default
+	Concurrency int `json:"concurrency,omitempty"`
+	dataCleanupTimeouts
+	common.DataCleanupTLS
 }
 
 type dataCleanupESStored struct {
@@ -71,6 +251,10 @@ type dataCleanupESStored struct {
 	Port     int      `json:"port"`
 	Username string   `json:"username,omitempty"`
 	Indices  []string `json:"indices,omitempty"`
+	// Mode is "delete_by_query" (default) or "drop_index"
+	Mode string `json:"mode,omitempty"`
+	dataCleanupTimeouts
+	common.DataCleanupTLS
 }
 
 type dataCleanupConfigResponse struct {
@@ -83,13 +267,17 @@ type dataCleanupConfigResponse struct {
 }
 
 type dataCleanupMySQLPayload struct {
-	Host        string   `json:"host"`
-	Port        int      `json:"port"`
-	Username    string   `json:"username,omitempty"`
-	Password    string   `json:"password,omitempty"`
-	Database    string   `json:"database,omitempty"`
-	Tables      []string `json:"tables,omitempty"`
-	HasPassword bool     `json:"hasPassword,omitempty"`
+	Host        string                      `json:"host"`
+	Port        int                         `json:"port"`
+	Username    string                      `json:"username,omitempty"`
+	Password    string                      `json:"password,omitempty"`
+	Database    string                      `json:"database,omitempty"`
+	Tables      []string                    `json:"tables,omitempty"`
+	Mode        string                      `json:"mode,omitempty"`
+	Conditions  []dataCleanupMySQLCondition `json:"conditions,omitempty"`
+	HasPassword bool                        `json:"hasPassword,omitempty"`
+	dataCleanupTimeouts
+	common.DataCleanupTLS
 }
 
 type dataCleanupRedisPayload struct {
@@ -100,6 +288,10 @@ type dataCleanupRedisPayload struct {
 	DB          int      `json:"db"`
 	Patterns    []string `json:"patterns,omitempty"`
 	HasPassword bool     `json:"hasPassword,omitempty"`
+	// Filter limits deletion to keys of a type or TTL range
+	Filter common.DataCleanupRedisFilter `json:"filter"`
+	dataCleanupTimeouts
+	common.DataCleanupTLS
 }
 
 type dataCleanupMinioPayload struct {
@@ -109,7 +301,10 @@ type dataCleanupMinioPayload struct {
 	SecretKey    string   `json:"secretKey,omitempty"`
 	Bucket       string   `json:"bucket,omitempty"`
 	Prefixes     []string `json:"prefixes,omitempty"`
+	Concurrency  int      `json:"concurrency,omitempty"`
 	HasSecretKey bool     `json:"hasSecretKey,omitempty"`
+	dataCleanupTimeouts
+	common.DataCleanupTLS
 }
 
 type dataCleanupESPayload struct {
@@ -119,6 +314,9 @@ type dataCleanupESPayload struct {
 	Password    string   `json:"password,omitempty"`
 	Indices     []string `json:"indices,omitempty"`
 	HasPassword bool     `json:"hasPassword,omitempty"`
+	Mode        string   `json:"mode,omitempty"`
+	dataCleanupTimeouts
+	common.DataCleanupTLS
 }
 
 type dataCleanupListPayload struct {
@@ -129,6 +327,8 @@ type dataCleanupListPayload struct {
 	Password          string `json:"password"`
 	UseStoredPassword bool   `json:"useStoredPassword"`
 	Database          string `json:"database"`
+	TimeoutSec        int    `json:"timeoutSec"`
+	common.DataCleanupTLS
 }
 
 type dataCleanupMinioListPayload struct {
@@ -139,16 +339,65 @@ type dataCleanupMinioListPayload struct {
 	SecretKey       string `json:"secretKey"`
 	UseStoredSecret bool   `json:"useStoredSecret"`
 	Bucket          string `json:"bucket"`
+	TimeoutSec      int    `json:"timeoutSec"`
+	common.DataCleanupTLS
+}
+
+// dataCleanupMatchCountPayload carries a single Redis pattern or MinIO prefix to count.
+// Connection fields mirror the list payloads of the selected module.
+type dataCleanupMatchCountPayload struct {
+	System            string `json:"system"`
+	Module            string `json:"module"`
+	Host              string `json:"host"`
+	Port              int    `json:"port"`
+	Username          string `json:"username"`
+	Password          string `json:"password"`
+	UseStoredPassword bool   `json:"useStoredPassword"`
+	DB                int    `json:"db"`
+	AccessKey         string `json:"accessKey"`
+	SecretKey         string `json:"secretKey"`
+	UseStoredSecret   bool   `json:"useStoredSecret"`
+	Bucket            string `json:"bucket"`
+	Pattern           string `json:"pattern"`
+	common.DataCleanupTLS
+}
+
+type dataCleanupMatchCountResponse struct {
+	Module    string `json:"module"`
+	Pattern   string `json:"pattern"`
+	Matched   int64  `json:"matched"`
+	Truncated bool   `json:"truncated"`
 }
 
 type dataCleanupRunPayload struct {
 	System string `json:"system"`
+	// DryRun counts what each module would delete without deleting anything
+	DryRun bool `json:"dryRun"`
+	// Confirm is the user's confirmation to delete data; a run that is not a dry run requires it
+	Confirm bool `json:"confirm"`
 }
 
+// dataCleanupResultDryRun is the result status of a module counted in a dry run.
+const dataCleanupResultDryRun = "dry-run"
+
+// dataCleanupStatusCancelled is the status of a cancelled run and of the module it stopped.
+const dataCleanupStatusCancelled = "cancelled"
+
 type dataCleanupRunResult struct {
 	Module string `json:"module"`
 	Status string `json:"status"`
 	Detail string `json:"detail,omitempty"`
+	// WouldDelete is the number of rows, keys, objects or documents matched in a dry run
+	WouldDelete int64 `json:"wouldDelete,omitempty"`
+	// Deleted is the amount a cancelled module removed before it stopped
+	Deleted int64 `json:"deleted,omitempty"`
+	// Skipped is the number of matching Redis keys left in place by the configured filter
+	Skipped int64 `json:"skipped,omitempty"`
+	// Mode is the ES cleanup mode that ran
+	Mode string `json:"mode,omitempty"`
+	// Dropped lists the ES indices deleted in drop_index mode; configured indices that did not
+	// exist are left out
+	Dropped []string `json:"dropped,omitempty"`
 }
 
 func (h *Hub) getDataCleanupEncryptionKey() (string, error) {
@@ -198,11 +447,11 @@ func (h *Hub) logDataCleanupError(message string, err error, fields ...any) {
 	}
 	payload := []any{
 		"logger", "hub",
-		"err", err,
+		"err", h.redactor.redact(err.Error()),
 		"errType", fmt.Sprintf("%T", err),
 		"stack", string(debug.Stack()),
 	}
-	payload = append(payload, fields...)
+	payload = append(payload, h.redactor.redactFields(fields)...)
 	h.Logger().Error(message, payload...)
 }
 
@@ -280,7 +529,7 @@ func (h *Hub) findCleanupConfig(systemID string) (*core.Record, error) {
 func (h *Hub) getDockerDataCleanupConfig(e *core.RequestEvent) error {
 	systemID := strings.TrimSpace(e.Request.URL.Query().Get("system"))
 	if systemID == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system is required"})
+		return respondError(e, http.StatusBadRequest, "system is required")
 	}
 	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
 		return respondSystemAccessError(e, err)
@@ -288,7 +537,7 @@ func (h *Hub) getDockerDataCleanupConfig(e *core.RequestEvent) error {
 	record, err := h.findCleanupConfig(systemID)
 	if err != nil {
 		h.logDataCleanupError("load cleanup config failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	response := dataCleanupConfigResponse{
 		System: systemID,
@@ -308,55 +557,68 @@ func (h *Hub) getDockerDataCleanupConfig(e *core.RequestEvent) error {
 
 	if err := parseJSONField(record, "mysql", &mysqlStored); err != nil {
 		h.logDataCleanupError("parse mysql config failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if err := parseJSONField(record, "redis", &redisStored); err != nil {
 		h.logDataCleanupError("parse redis config failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if err := parseJSONField(record, "minio", &minioStored); err != nil {
 		h.logDataCleanupError("parse minio config failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if err := parseJSONField(record, "es", &esStored); err != nil {
 		h.logDataCleanupError("parse es config failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	response.ID = record.Id
 	response.MySQL = dataCleanupMySQLPayload{
-		Host:        mysqlStored.Host,
-		Port:        mysqlStored.Port,
-		Username:    mysqlStored.Username,
-		Database:    mysqlStored.Database,
-		Tables:      normalizeStringSlice(mysqlStored.Tables),
-		HasPassword: record.GetString("mysql_password") != "",
+		Host:                mysqlStored.Host,
+		Port:                mysqlStored.Port,
+		Username:            mysqlStored.Username,
+		Database:            mysqlStored.Database,
+		Tables:              normalizeStringSlice(mysqlStored.Tables),
+		Mode:                mysqlStored.Mode,
+		Conditions:          mysqlStored.Conditions,
+		HasPassword:         record.GetString("mysql_password") != "",
+		dataCleanupTimeouts: mysqlStored.dataCleanupTimeouts,
+		DataCleanupTLS:      mysqlStored.DataCleanupTLS,
 	}
 	response.Redis = dataCleanupRedisPayload{
-		Host:        redisStored.Host,
-		Port:        redisStored.Port,
-		Username:    redisStored.Username,
-		DB:          redisStored.DB,
-		Patterns:    normalizeStringSlice(redisStored.Patterns),
-		HasPassword: record.GetString("redis_password") != "",
+		Host:                redisStored.Host,
+		Port:                redisStored.Port,
+		Username:            redisStored.Username,
+		DB:                  redisStored.DB,
+		Patterns:            normalizeStringSlice(redisStored.Patterns),
+		HasPassword:         record.GetString("redis_password") != "",
+		Filter:              redisStored.Filter,
+		dataCleanupTimeouts: redisStored.dataCleanupTimeouts,
+		DataCleanupTLS:      redisStored.DataCleanupTLS,
 	}
 	if len(response.Redis.Patterns) == 0 {
 		response.Redis.Patterns = append([]string{}, dataCleanupRedisPatterns...)
 	}
 	response.Minio = dataCleanupMinioPayload{
-		Host:         minioStored.Host,
-		Port:         minioStored.Port,
-		AccessKey:    minioStored.AccessKey,
-		Bucket:       minioStored.Bucket,
-		Prefixes:     normalizeStringSlice(minioStored.Prefixes),
-		HasSecretKey: record.GetString("minio_secret_key") != "",
+		Host:                minioStored.Host,
+		Port:                minioStored.Port,
+		AccessKey:           minioStored.AccessKey,
+		Bucket:              minioStored.Bucket,
+		Prefixes:            normalizeStringSlice(minioStored.Prefixes),
+		Concurrency:         minioStored.Concurrency,
+		HasSecretKey:        record.GetString("minio_secret_key") != "",
+		dataCleanupTimeouts: minioStored.dataCleanupTimeouts,
+		DataCleanupTLS:      minioStored.DataCleanupTLS,
 	}
 	response.ES = dataCleanupESPayload{
-		Host:        esStored.Host,
-		Port:        esStored.Port,
-		Username:    esStored.Username,
-		Indices:     normalizeStringSlice(esStored.Indices),
-		HasPassword: record.GetString("es_password") != "",
+		Host:                esStored.Host,
+		Port:                esStored.Port,
+		Username:            esStored.Username,
+		Indices:             normalizeStringSlice(esStored.Indices),
+		Mode:                esStored.Mode,
+		HasPassword:         record.GetString("es_password") != "",
+		dataCleanupTimeouts: esStored.dataCleanupTimeouts,
+		DataCleanupTLS:      esStored.DataCleanupTLS,
 	}
 
 	return e.JSON(http.StatusOK, response)
@@ -368,81 +630,93 @@ func (h *Hub) upsertDockerDataCleanupConfig(e *core.RequestEvent) error {
 	}
 	var payload dataCleanupConfigResponse
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	systemID := strings.TrimSpace(payload.System)
 	if systemID == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system is required"})
+		return respondError(e, http.StatusBadRequest, "system is required")
 	}
 	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
 		return respondSystemAccessError(e, err)
 	}
+	mysqlStored := dataCleanupMySQLStored{
+		Host:                payload.MySQL.Host,
+		Port:                payload.MySQL.Port,
+		Username:            payload.MySQL.Username,
+		Database:            payload.MySQL.Database,
+		Tables:              payload.MySQL.Tables,
+		Mode:                payload.MySQL.Mode,
+		Conditions:          payload.MySQL.Conditions,
+		dataCleanupTimeouts: payload.MySQL.dataCleanupTimeouts,
+		DataCleanupTLS:      payload.MySQL.DataCleanupTLS,
+	}
+	redisStored := dataCleanupRedisStored{
+		Host:                payload.Redis.Host,
+		Port:                payload.Redis.Port,
+		Username:            payload.Redis.Username,
+		DB:                  payload.Redis.DB,
+		Patterns:            payload.Redis.Patterns,
+		Filter:              payload.Redis.Filter,
+		dataCleanupTimeouts: payload.Redis.dataCleanupTimeouts,
+		DataCleanupTLS:      payload.Redis.DataCleanupTLS,
+	}
+	minioStored := dataCleanupMinioStored{
+		Host:                payload.Minio.Host,
+		Port:                payload.Minio.Port,
+		AccessKey:           payload.Minio.AccessKey,
+		Bucket:              payload.Minio.Bucket,
+		Prefixes:            payload.Minio.Prefixes,
+		Concurrency:         payload.Minio.Concurrency,
+		dataCleanupTimeouts: payload.Minio.dataCleanupTimeouts,
+		DataCleanupTLS:      payload.Minio.DataCleanupTLS,
+	}
+	esStored := dataCleanupESStored{
+		Host:                payload.ES.Host,
+		Port:                payload.ES.Port,
+		Username:            payload.ES.Username,
+		Indices:             payload.ES.Indices,
+		Mode:                payload.ES.Mode,
+		dataCleanupTimeouts: payload.ES.dataCleanupTimeouts,
+		DataCleanupTLS:      payload.ES.DataCleanupTLS,
+	}
+	if err := normalizeDataCleanupStored(&mysqlStored, &redisStored, &minioStored, &esStored); err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
 
 	record, err := h.findCleanupConfig(systemID)
 	if err != nil {
 		h.logDataCleanupError("load cleanup config failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	isCreate := record == nil
 	if record == nil {
 		collection, err := h.FindCollectionByNameOrId(dataCleanupConfigCollection)
 		if err != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return respondError(e, http.StatusInternalServerError, err.Error())
 		}
 		record = core.NewRecord(collection)
 		record.Set("system", systemID)
 	}
 
-	mysqlStored := dataCleanupMySQLStored{
-		Host:     strings.TrimSpace(payload.MySQL.Host),
-		Port:     payload.MySQL.Port,
-		Username: strings.TrimSpace(payload.MySQL.Username),
-		Database: strings.TrimSpace(payload.MySQL.Database),
-		Tables:   normalizeStringSlice(payload.MySQL.Tables),
-	}
-	redisStored := dataCleanupRedisStored{
-		Host:     strings.TrimSpace(payload.Redis.Host),
-		Port:     payload.Redis.Port,
-		Username: strings.TrimSpace(payload.Redis.Username),
-		DB:       payload.Redis.DB,
-		Patterns: normalizeStringSlice(payload.Redis.Patterns),
-	}
-	if len(redisStored.Patterns) == 0 {
-		redisStored.Patterns = append([]string{}, dataCleanupRedisPatterns...)
-	}
-	minioStored := dataCleanupMinioStored{
-		Host:      strings.TrimSpace(payload.Minio.Host),
-		Port:      payload.Minio.Port,
-		AccessKey: strings.TrimSpace(payload.Minio.AccessKey),
-		Bucket:    strings.TrimSpace(payload.Minio.Bucket),
-		Prefixes:  normalizeStringSlice(payload.Minio.Prefixes),
-	}
-	esStored := dataCleanupESStored{
-		Host:     strings.TrimSpace(payload.ES.Host),
-		Port:     payload.ES.Port,
-		Username: strings.TrimSpace(payload.ES.Username),
-		Indices:  normalizeStringSlice(payload.ES.Indices),
-	}
-
 	mysqlRaw, err := toJSONRaw(mysqlStored)
 	if err != nil {
 		h.logDataCleanupError("encode mysql config failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	redisRaw, err := toJSONRaw(redisStored)
 	if err != nil {
 		h.logDataCleanupError("encode redis config failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	minioRaw, err := toJSONRaw(minioStored)
 	if err != nil {
 		h.logDataCleanupError("encode minio config failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	esRaw, err := toJSONRaw(esStored)
 	if err != nil {
 		h.logDataCleanupError("encode es config failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	record.Set("mysql", mysqlRaw)
@@ -455,7 +729,7 @@ func (h *Hub) upsertDockerDataCleanupConfig(e *core.RequestEvent) error {
 		encrypted, err := h.encryptDataCleanupSecret(mysqlPassword)
 		if err != nil {
 			h.logDataCleanupError("encrypt mysql password failed", err, "system", systemID)
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return respondError(e, http.StatusInternalServerError, err.Error())
 		}
 		record.Set("mysql_password", encrypted)
 	}
@@ -464,7 +738,7 @@ func (h *Hub) upsertDockerDataCleanupConfig(e *core.RequestEvent) error {
 		encrypted, err := h.encryptDataCleanupSecret(redisPassword)
 		if err != nil {
 			h.logDataCleanupError("encrypt redis password failed", err, "system", systemID)
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return respondError(e, http.StatusInternalServerError, err.Error())
 		}
 		record.Set("redis_password", encrypted)
 	}
@@ -473,7 +747,7 @@ func (h *Hub) upsertDockerDataCleanupConfig(e *core.RequestEvent) error {
 		encrypted, err := h.encryptDataCleanupSecret(minioSecret)
 		if err != nil {
 			h.logDataCleanupError("encrypt minio secret failed", err, "system", systemID)
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return respondError(e, http.StatusInternalServerError, err.Error())
 		}
 		record.Set("minio_secret_key", encrypted)
 	}
@@ -482,14 +756,14 @@ func (h *Hub) upsertDockerDataCleanupConfig(e *core.RequestEvent) error {
 		encrypted, err := h.encryptDataCleanupSecret(esPassword)
 		if err != nil {
 			h.logDataCleanupError("encrypt es password failed", err, "system", systemID)
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return respondError(e, http.StatusInternalServerError, err.Error())
 		}
 		record.Set("es_password", encrypted)
 	}
 
 	if err := h.Save(record); err != nil {
 		h.logDataCleanupError("save cleanup config failed", err, "system", systemID, "create", isCreate)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"id": record.Id, "status": "ok"})
 }
@@ -520,14 +794,35 @@ func (h *Hub) resolveCleanupPassword(
 	return h.decryptDataCleanupSecret(encrypted)
 }
 
+// resolveCleanupListTimeout returns the list timeout override for a module:
+// the request value when set, otherwise the module's stored listTimeoutSec (0 = agent default).
+func (h *Hub) resolveCleanupListTimeout(systemID string, module string, override int) int {
+	if override > 0 {
+		return override
+	}
+	record, err := h.findCleanupConfig(systemID)
+	if err != nil || record == nil {
+		return 0
+	}
+	var stored dataCleanupTimeouts
+	if err := parseJSONField(record, module, &stored); err != nil {
+		h.logDataCleanupError("parse cleanup timeouts failed", err, "system", systemID, "module", module)
+		return 0
+	}
+	return stored.ListTimeoutSec
+}
+
 func (h *Hub) listDataCleanupMySQLDatabases(e *core.RequestEvent) error {
 	var payload dataCleanupListPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	payload.Host = strings.TrimSpace(payload.Host)
 	if payload.System == "" || payload.Host == "" || payload.Port <= 0 {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system, host and port are required"})
+		return respondError(e, http.StatusBadRequest, "system, host and port are required")
+	}
+	if payload.TimeoutSec < 0 || payload.TimeoutSec > dataCleanupMaxListTimeoutSec {
+		return respondError(e, http.StatusBadRequest, fmt.Sprintf("timeoutSec must be between 0 and %d", dataCleanupMaxListTimeoutSec))
 	}
 	if _, err := h.resolveSystemRecordForUser(e, payload.System); err != nil {
 		return respondSystemAccessError(e, err)
@@ -535,21 +830,23 @@ func (h *Hub) listDataCleanupMySQLDatabases(e *core.RequestEvent) error {
 	password, err := h.resolveCleanupPassword(payload.System, "mysql_password", payload.Password, payload.UseStoredPassword)
 	if err != nil {
 		h.logDataCleanupError("resolve mysql password failed", err, "system", payload.System)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	items, err := system.FetchDataCleanupMySQLDatabasesFromAgent(common.DataCleanupMySQLDatabasesRequest{
-		Host:     payload.Host,
-		Port:     payload.Port,
-		Username: payload.Username,
-		Password: password,
+		Host:       payload.Host,
+		Port:       payload.Port,
+		Username:   payload.Username,
+		Password:   password,
+		TimeoutSec: h.resolveCleanupListTimeout(payload.System, "mysql", payload.TimeoutSec),
+		TLS:        payload.DataCleanupTLS,
 	})
 	if err != nil {
 		h.logDataCleanupError("list mysql databases failed", err, "system", payload.System, "host", payload.Host, "port", payload.Port)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"items": items})
 }
@@ -557,12 +854,15 @@ func (h *Hub) listDataCleanupMySQLDatabases(e *core.RequestEvent) error {
 func (h *Hub) listDataCleanupMySQLTables(e *core.RequestEvent) error {
 	var payload dataCleanupListPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	payload.Host = strings.TrimSpace(payload.Host)
 	payload.Database = strings.TrimSpace(payload.Database)
 	if payload.System == "" || payload.Host == "" || payload.Port <= 0 || payload.Database == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system, host, port, database are required"})
+		return respondError(e, http.StatusBadRequest, "system, host, port, database are required")
+	}
+	if payload.TimeoutSec < 0 || payload.TimeoutSec > dataCleanupMaxListTimeoutSec {
+		return respondError(e, http.StatusBadRequest, fmt.Sprintf("timeoutSec must be between 0 and %d", dataCleanupMaxListTimeoutSec))
 	}
 	if _, err := h.resolveSystemRecordForUser(e, payload.System); err != nil {
 		return respondSystemAccessError(e, err)
@@ -570,22 +870,24 @@ func (h *Hub) listDataCleanupMySQLTables(e *core.RequestEvent) error {
 	password, err := h.resolveCleanupPassword(payload.System, "mysql_password", payload.Password, payload.UseStoredPassword)
 	if err != nil {
 		h.logDataCleanupError("resolve mysql password failed", err, "system", payload.System)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	items, err := system.FetchDataCleanupMySQLTablesFromAgent(common.DataCleanupMySQLTablesRequest{
-		Host:     payload.Host,
-		Port:     payload.Port,
-		Username: payload.Username,
-		Password: password,
-		Database: payload.Database,
+		Host:       payload.Host,
+		Port:       payload.Port,
+		Username:   payload.Username,
+		Password:   password,
+		Database:   payload.Database,
+		TimeoutSec: h.resolveCleanupListTimeout(payload.System, "mysql", payload.TimeoutSec),
+		TLS:        payload.DataCleanupTLS,
 	})
 	if err != nil {
 		h.logDataCleanupError("list mysql tables failed", err, "system", payload.System, "database", payload.Database)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"items": items})
 }
@@ -593,11 +895,14 @@ func (h *Hub) listDataCleanupMySQLTables(e *core.RequestEvent) error {
 func (h *Hub) listDataCleanupRedisDatabases(e *core.RequestEvent) error {
 	var payload dataCleanupListPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	payload.Host = strings.TrimSpace(payload.Host)
 	if payload.System == "" || payload.Host == "" || payload.Port <= 0 {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system, host and port are required"})
+		return respondError(e, http.StatusBadRequest, "system, host and port are required")
+	}
+	if payload.TimeoutSec < 0 || payload.TimeoutSec > dataCleanupMaxListTimeoutSec {
+		return respondError(e, http.StatusBadRequest, fmt.Sprintf("timeoutSec must be between 0 and %d", dataCleanupMaxListTimeoutSec))
 	}
 	if _, err := h.resolveSystemRecordForUser(e, payload.System); err != nil {
 		return respondSystemAccessError(e, err)
@@ -605,21 +910,23 @@ func (h *Hub) listDataCleanupRedisDatabases(e *core.RequestEvent) error {
 	password, err := h.resolveCleanupPassword(payload.System, "redis_password", payload.Password, payload.UseStoredPassword)
 	if err != nil {
 		h.logDataCleanupError("resolve redis password failed", err, "system", payload.System)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	items, err := system.FetchDataCleanupRedisDatabasesFromAgent(common.DataCleanupRedisDatabasesRequest{
-		Host:     payload.Host,
-		Port:     payload.Port,
-		Username: payload.Username,
-		Password: password,
+		Host:       payload.Host,
+		Port:       payload.Port,
+		Username:   payload.Username,
+		Password:   password,
+		TimeoutSec: h.resolveCleanupListTimeout(payload.System, "redis", payload.TimeoutSec),
+		TLS:        payload.DataCleanupTLS,
 	})
 	if err != nil {
 		h.logDataCleanupError("list redis databases failed", err, "system", payload.System, "host", payload.Host, "port", payload.Port)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"items": items})
 }
@@ -627,12 +934,15 @@ func (h *Hub) listDataCleanupRedisDatabases(e *core.RequestEvent) error {
 func (h *Hub) listDataCleanupMinioBuckets(e *core.RequestEvent) error {
 	var payload dataCleanupMinioListPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	payload.Host = strings.TrimSpace(payload.Host)
 	payload.AccessKey = strings.TrimSpace(payload.AccessKey)
 	if payload.System == "" || payload.Host == "" || payload.Port <= 0 {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system, host and port are required"})
+		return respondError(e, http.StatusBadRequest, "system, host and port are required")
+	}
+	if payload.TimeoutSec < 0 || payload.TimeoutSec > dataCleanupMaxListTimeoutSec {
+		return respondError(e, http.StatusBadRequest, fmt.Sprintf("timeoutSec must be between 0 and %d", dataCleanupMaxListTimeoutSec))
 	}
 	if _, err := h.resolveSystemRecordForUser(e, payload.System); err != nil {
 		return respondSystemAccessError(e, err)
@@ -640,21 +950,23 @@ func (h *Hub) listDataCleanupMinioBuckets(e *core.RequestEvent) error {
 	secret, err := h.resolveCleanupPassword(payload.System, "minio_secret_key", payload.SecretKey, payload.UseStoredSecret)
 	if err != nil {
 		h.logDataCleanupError("resolve minio secret failed", err, "system", payload.System)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	items, err := system.FetchDataCleanupMinioBucketsFromAgent(common.DataCleanupMinioBucketsRequest{
-		Host:      payload.Host,
-		Port:      payload.Port,
-		AccessKey: payload.AccessKey,
-		SecretKey: secret,
+		Host:       payload.Host,
+		Port:       payload.Port,
+		AccessKey:  payload.AccessKey,
+		SecretKey:  secret,
+		TimeoutSec: h.resolveCleanupListTimeout(payload.System, "minio", payload.TimeoutSec),
+		TLS:        payload.DataCleanupTLS,
 	})
 	if err != nil {
 		h.logDataCleanupError("list minio buckets failed", err, "system", payload.System, "host", payload.Host, "port", payload.Port)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"items": items})
 }
@@ -662,13 +974,16 @@ func (h *Hub) listDataCleanupMinioBuckets(e *core.RequestEvent) error {
 func (h *Hub) listDataCleanupMinioPrefixes(e *core.RequestEvent) error {
 	var payload dataCleanupMinioListPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	payload.Host = strings.TrimSpace(payload.Host)
 	payload.AccessKey = strings.TrimSpace(payload.AccessKey)
 	payload.Bucket = strings.TrimSpace(payload.Bucket)
 	if payload.System == "" || payload.Host == "" || payload.Port <= 0 || payload.Bucket == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system, host, port, bucket are required"})
+		return respondError(e, http.StatusBadRequest, "system, host, port, bucket are required")
+	}
+	if payload.TimeoutSec < 0 || payload.TimeoutSec > dataCleanupMaxListTimeoutSec {
+		return respondError(e, http.StatusBadRequest, fmt.Sprintf("timeoutSec must be between 0 and %d", dataCleanupMaxListTimeoutSec))
 	}
 	if _, err := h.resolveSystemRecordForUser(e, payload.System); err != nil {
 		return respondSystemAccessError(e, err)
@@ -676,22 +991,24 @@ func (h *Hub) listDataCleanupMinioPrefixes(e *core.RequestEvent) error {
 	secret, err := h.resolveCleanupPassword(payload.System, "minio_secret_key", payload.SecretKey, payload.UseStoredSecret)
 	if err != nil {
 		h.logDataCleanupError("resolve minio secret failed", err, "system", payload.System)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	items, err := system.FetchDataCleanupMinioPrefixesFromAgent(common.DataCleanupMinioPrefixesRequest{
-		Host:      payload.Host,
-		Port:      payload.Port,
-		AccessKey: payload.AccessKey,
-		SecretKey: secret,
-		Bucket:    payload.Bucket,
+		Host:       payload.Host,
+		Port:       payload.Port,
+		AccessKey:  payload.AccessKey,
+		SecretKey:  secret,
+		Bucket:     payload.Bucket,
+		TimeoutSec: h.resolveCleanupListTimeout(payload.System, "minio", payload.TimeoutSec),
+		TLS:        payload.DataCleanupTLS,
 	})
 	if err != nil {
 		h.logDataCleanupError("list minio prefixes failed", err, "system", payload.System, "bucket", payload.Bucket)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"items": items})
 }
@@ -699,11 +1016,14 @@ func (h *Hub) listDataCleanupMinioPrefixes(e *core.RequestEvent) error {
 func (h *Hub) listDataCleanupESIndices(e *core.RequestEvent) error {
 	var payload dataCleanupListPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	payload.Host = strings.TrimSpace(payload.Host)
 	if payload.System == "" || payload.Host == "" || payload.Port <= 0 {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system, host and port are required"})
+		return respondError(e, http.StatusBadRequest, "system, host and port are required")
+	}
+	if payload.TimeoutSec < 0 || payload.TimeoutSec > dataCleanupMaxListTimeoutSec {
+		return respondError(e, http.StatusBadRequest, fmt.Sprintf("timeoutSec must be between 0 and %d", dataCleanupMaxListTimeoutSec))
 	}
 	if _, err := h.resolveSystemRecordForUser(e, payload.System); err != nil {
 		return respondSystemAccessError(e, err)
@@ -711,107 +1031,230 @@ func (h *Hub) listDataCleanupESIndices(e *core.RequestEvent) error {
 	password, err := h.resolveCleanupPassword(payload.System, "es_password", payload.Password, payload.UseStoredPassword)
 	if err != nil {
 		h.logDataCleanupError("resolve es password failed", err, "system", payload.System)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	system, err := h.resolveSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	items, err := system.FetchDataCleanupESIndicesFromAgent(common.DataCleanupESIndicesRequest{
-		Host:     payload.Host,
-		Port:     payload.Port,
-		Username: payload.Username,
-		Password: password,
+		Host:       payload.Host,
+		Port:       payload.Port,
+		Username:   payload.Username,
+		Password:   password,
+		TimeoutSec: h.resolveCleanupListTimeout(payload.System, "es", payload.TimeoutSec),
+		TLS:        payload.DataCleanupTLS,
 	})
 	if err != nil {
 		h.logDataCleanupError("list es indices failed", err, "system", payload.System, "host", payload.Host, "port", payload.Port)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 	return e.JSON(http.StatusOK, map[string]any{"items": items})
 }
 
+// countDataCleanupMatches handles POST /api/aether/docker/data-cleanup/match-count.
+// It reports how many Redis keys or MinIO objects a single pattern/prefix matches right now,
+// without deleting anything. The agent bounds the scan time and flags truncated counts.
+func (h *Hub) countDataCleanupMatches(e *core.RequestEvent) error {
+	var payload dataCleanupMatchCountPayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	payload.Module = strings.ToLower(strings.TrimSpace(payload.Module))
+	payload.Host = strings.TrimSpace(payload.Host)
+	payload.AccessKey = strings.TrimSpace(payload.AccessKey)
+	payload.Bucket = strings.TrimSpace(payload.Bucket)
+	payload.Pattern = strings.TrimSpace(payload.Pattern)
+	if payload.System == "" || payload.Host == "" || payload.Port <= 0 || payload.Pattern == "" {
+		return respondError(e, http.StatusBadRequest, "system, host, port and pattern are required")
+	}
+	switch payload.Module {
+	case "redis":
+		if payload.DB < 0 {
+			return respondError(e, http.StatusBadRequest, "invalid redis db")
+		}
+	case "minio":
+		if payload.Bucket == "" {
+			return respondError(e, http.StatusBadRequest, "bucket is required")
+		}
+	default:
+		return respondError(e, http.StatusBadRequest, "module must be redis or minio")
+	}
+	if _, err := h.resolveSystemRecordForUser(e, payload.System); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	system, err := h.resolveSystem(payload.System)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+
+	var result common.DockerDataCleanupResult
+	if payload.Module == "redis" {
+		password, err := h.resolveCleanupPassword(payload.System, "redis_password", payload.Password, payload.UseStoredPassword)
+		if err != nil {
+			h.logDataCleanupError("resolve redis password failed", err, "system", payload.System)
+			return respondError(e, http.StatusInternalServerError, err.Error())
+		}
+		result, err = system.CountDataCleanupRedisMatchesFromAgent(common.DataCleanupRedisMatchCountRequest{
+			Host:     payload.Host,
+			Port:     payload.Port,
+			Username: payload.Username,
+			Password: password,
+			DB:       payload.DB,
+			Pattern:  payload.Pattern,
+			TLS:      payload.DataCleanupTLS,
+		})
+		if err != nil {
+			h.logDataCleanupError("count redis matches failed", err, "system", payload.System, "db", payload.DB, "pattern", payload.Pattern)
+			return respondError(e, http.StatusBadGateway, err.Error())
+		}
+	} else {
+		secret, err := h.resolveCleanupPassword(payload.System, "minio_secret_key", payload.SecretKey, payload.UseStoredSecret)
+		if err != nil {
+			h.logDataCleanupError("resolve minio secret failed", err, "system", payload.System)
+			return respondError(e, http.StatusInternalServerError, err.Error())
+		}
+		result, err = system.CountDataCleanupMinioMatchesFromAgent(common.DataCleanupMinioMatchCountRequest{
+			Host:      payload.Host,
+			Port:      payload.Port,
+			AccessKey: payload.AccessKey,
+			SecretKey: secret,
+			Bucket:    payload.Bucket,
+			Prefix:    payload.Pattern,
+			TLS:       payload.DataCleanupTLS,
+		})
+		if err != nil {
+			h.logDataCleanupError("count minio matches failed", err, "system", payload.System, "bucket", payload.Bucket, "prefix", payload.Pattern)
+			return respondError(e, http.StatusBadGateway, err.Error())
+		}
+	}
+	return e.JSON(http.StatusOK, dataCleanupMatchCountResponse{
+		Module:    payload.Module,
+		Pattern:   payload.Pattern,
+		Matched:   result.Matched,
+		Truncated: result.Truncated,
+	})
+}
+
 func (h *Hub) startDataCleanupRun(e *core.RequestEvent) error {
 	if err := requireWritable(e); err != nil {
 		return err
 	}
 	var payload dataCleanupRunPayload
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	systemID := strings.TrimSpace(payload.System)
 	if systemID == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system is required"})
+		return respondError(e, http.StatusBadRequest, "system is required")
+	}
+	if !payload.DryRun && !payload.Confirm {
+		return respondError(e, http.StatusBadRequest, "confirm is required")
 	}
 	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
 		return respondSystemAccessError(e, err)
 	}
 
 	// 同一系统一次只允许一个进行中的清理任务，避免重复触发导致负载与状态混乱。
-	var existingRun struct {
-		ID string `db:"id"`
-	}
-	existingErr := h.DB().
-		NewQuery("SELECT id FROM " + dataCleanupRunsCollection + " WHERE system = {:system} AND status IN ('pending','running') LIMIT 1").
-		Bind(dbx.Params{"system": systemID}).
-		One(&existingRun)
-	if existingErr == nil {
-		return e.JSON(http.StatusConflict, map[string]string{"error": "cleanup run already in progress"})
+	unlock := lockDataCleanupSystem(systemID)
+	defer unlock()
+	active, err := h.hasActiveDataCleanupRun(systemID)
+	if err != nil {
+		h.logDataCleanupError("check existing cleanup run failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
-	if existingErr != nil && !errors.Is(existingErr, sql.ErrNoRows) {
-		h.logDataCleanupError("check existing cleanup run failed", existingErr, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": existingErr.Error()})
+	if active {
+		return respondErrorWithCode(e, http.StatusConflict, errCodeRunInProgress, "cleanup run already in progress")
 	}
 
 	configRecord, err := h.findCleanupConfig(systemID)
 	if err != nil {
 		h.logDataCleanupError("load cleanup config failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if configRecord == nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "cleanup config not found"})
+		return respondError(e, http.StatusBadRequest, "cleanup config not found")
 	}
+	userID := e.Auth.Id
+	runRecord, err := h.createDataCleanupRun(systemID, configRecord.Id, "", userID, payload.DryRun)
+	if err != nil {
+		h.logDataCleanupError("create cleanup run failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+
+	job, ctx := h.jobs.start(runningJobTypeDataCleanup+":"+runRecord.Id, runningJobTypeDataCleanup, runRecord.Id, systemID, true)
+	go h.cleanupQueue.run(func() {
+		defer h.jobs.finish(job)
+		h.executeDataCleanupRun(ctx, job, runRecord.Id, systemID, configRecord.Id, dataCleanupRunOptions{UserID: userID, DryRun: payload.DryRun, Confirm: payload.Confirm})
+	})
+
+	return e.JSON(http.StatusOK, map[string]any{"runId": runRecord.Id})
+}
+
+// hasActiveDataCleanupRun reports whether the system has a pending or running cleanup run.
+func (h *Hub) hasActiveDataCleanupRun(systemID string) (bool, error) {
+	var existingRun struct {
+		ID string `db:"id"`
+	}
+	err := h.DB().
+		NewQuery("SELECT id FROM " + dataCleanupRunsCollection + " WHERE system = {:system} AND status IN ('pending','running') LIMIT 1").
+		Bind(dbx.Params{"system": systemID}).
+		One(&existingRun)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return false, err
+}
+
+// createDataCleanupRun saves a queued run record for the system. sourceRunID links a re-run to
+// the run it was created from and is empty otherwise. triggeredBy and dryRun are kept so a run
+// resumed after a restart is still audited under the user who started it.
+func (h *Hub) createDataCleanupRun(systemID, configID, sourceRunID, triggeredBy string, dryRun bool) (*core.Record, error) {
 	runCollection, err := h.FindCollectionByNameOrId(dataCleanupRunsCollection)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return nil, err
 	}
 	runRecord := core.NewRecord(runCollection)
 	runRecord.Set("system", systemID)
-	runRecord.Set("config", configRecord.Id)
+	runRecord.Set("config", configID)
+	runRecord.Set("source_run", sourceRunID)
+	runRecord.Set("triggered_by", triggeredBy)
+	runRecord.Set("dry_run", dryRun)
 	runRecord.Set("status", "pending")
 	runRecord.Set("progress", 0)
-	runRecord.Set("step", "")
+	runRecord.Set("step", "queued")
 	runRecord.Set("logs", types.JSONRaw("[]"))
 	runRecord.Set("results", types.JSONRaw("[]"))
 	if err := h.Save(runRecord); err != nil {
-		h.logDataCleanupError("create cleanup run failed", err, "system", systemID)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return nil, err
 	}
-
-	go h.executeDataCleanupRun(runRecord.Id, systemID, configRecord.Id, e.Auth.Id)
-
-	return e.JSON(http.StatusOK, map[string]any{"runId": runRecord.Id})
+	return runRecord, nil
 }
 
 func (h *Hub) getDataCleanupRun(e *core.RequestEvent) error {
 	runID := strings.TrimSpace(e.Request.URL.Query().Get("id"))
 	if runID == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+		return respondError(e, http.StatusBadRequest, "id is required")
 	}
 	record, err := h.FindRecordById(dataCleanupRunsCollection, runID)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "run not found"})
+		return respondError(e, http.StatusNotFound, "run not found")
 	}
 	systemID := record.GetString("system")
 	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
 		return respondSystemAccessError(e, err)
 	}
 	return e.JSON(http.StatusOK, map[string]any{
-		"id":       record.Id,
-		"status":   record.GetString("status"),
-		"progress": record.GetInt("progress"),
-		"step":     record.GetString("step"),
-		"logs":     record.Get("logs"),
-		"results":  record.Get("results"),
+		"id":        record.Id,
+		"status":    record.GetString("status"),
+		"progress":  record.GetInt("progress"),
+		"step":      record.GetString("step"),
+		"logs":      record.Get("logs"),
+		"results":   record.Get("results"),
+		"sourceRun": record.GetString("source_run"),
 	})
 }
 
@@ -819,10 +1262,53 @@ func (h *Hub) retryDataCleanupRun(e *core.RequestEvent) error {
 	return h.startDataCleanupRun(e)
 }
 
-func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
+// dataCleanupRunSourceSchedule marks runs started by the cleanup schedule in their audit entry.
+const dataCleanupRunSourceSchedule = "schedule"
+
+// dataCleanupRunOptions carries the per-run settings of executeDataCleanupRun.
+type dataCleanupRunOptions struct {
+	// UserID is recorded in the audit entry
+	UserID string
+	// DryRun asks the agent to count the matching data instead of deleting it; the counts are
+	// recorded as dry-run results and no audit entry is written
+	DryRun bool
+	// Confirm is passed to the agent with each delete request. It must come from an explicit
+	// confirmation; without it the agent deletes only when started with DATA_CLEANUP_ENABLED=true
+	Confirm bool
+	// Overrides, when set, replace the stored targets of the listed modules for this run only
+	Overrides *dataCleanupTargetOverrides
+	// Source names what started the run when it was not a user request, e.g. the schedule
+	Source string
+}
+
+// dataCleanupSystemLocks holds one mutex per system. Starting a run checks for an active run and
+// creates the new run record under the lock, so manual and scheduled starts cannot both pass the check.
+var dataCleanupSystemLocks sync.Map
+
+// lockDataCleanupSystem locks run creation for the system and returns the unlock function.
+func lockDataCleanupSystem(systemID string) func() {
+	value, _ := dataCleanupSystemLocks.LoadOrStore(systemID, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// executeDataCleanupRun runs each configured module in turn. Cancelling ctx stops the run before
+// the next module and stops polling the current one; a job already started on the agent finishes there.
+func (h *Hub) executeDataCleanupRun(ctx context.Context, job *runningJob, runID, systemID, configID string, opts dataCleanupRunOptions) {
+	dryRun, confirm, overrides := opts.DryRun, opts.Confirm, opts.Overrides
 	logs := make([]string, 0, 16)
 	results := make([]dataCleanupRunResult, 0, 4)
 
+	if ctx.Err() != nil {
+		logs = append(logs, fmt.Sprintf("[%s] cleanup run cancelled before start", time.Now().Format(time.RFC3339)))
+		results = append(results, dataCleanupRunResult{Module: "run", Status: dataCleanupStatusCancelled, Detail: errDataCleanupRunCancelled.Error()})
+		if err := h.updateDataCleanupRun(runID, dataCleanupStatusCancelled, 100, "done", logs, results); err != nil {
+			h.logDataCleanupError("finalize cleanup run failed", err, "run", runID)
+		}
+		return
+	}
+
 	updateErr := h.updateDataCleanupRun(runID, "running", 0, "init", logs, results)
 	if updateErr != nil {
 		h.logDataCleanupError("update cleanup run failed", updateErr, "run", runID)
@@ -904,13 +1390,20 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 		return
 	}
 
-	mysqlTables := normalizeStringSlice(mysqlStored.Tables)
-	minioPrefixes := normalizeStringSlice(minioStored.Prefixes)
-	esIndices := normalizeStringSlice(esStored.Indices)
+	mysqlTables := overrides.apply("mysql", normalizeStringSlice(mysqlStored.Tables))
+	minioPrefixes := overrides.apply("minio", normalizeStringSlice(minioStored.Prefixes))
+	esIndices := overrides.apply("es", normalizeStringSlice(esStored.Indices))
 	redisPatterns := normalizeStringSlice(redisStored.Patterns)
 	if len(redisPatterns) == 0 {
 		redisPatterns = append([]string{}, dataCleanupRedisPatterns...)
 	}
+	redisPatterns = overrides.apply("redis", redisPatterns)
+	if dryRun {
+		logs = append(logs, fmt.Sprintf("[%s] dry run: matches are counted, nothing is deleted", time.Now().Format(time.RFC3339)))
+	}
+	if modules := overrides.modules(); len(modules) > 0 {
+		logs = append(logs, fmt.Sprintf("[%s] target overrides: %s", time.Now().Format(time.RFC3339), strings.Join(modules, ",")))
+	}
 
 	mysqlTargets := 0
 	if mysqlStored.Host != "" && mysqlStored.Port > 0 && mysqlStored.Database != "" {
@@ -939,30 +1432,6 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 	completedOps := 0
 	failures := 0
 
-	fetchJobStatus := func(module, jobID string) (common.DataCleanupJobStatusDetail, int64, error) {
-		result, err := system.FetchDataCleanupJobStatusFromAgent(common.DataCleanupJobStatusRequest{JobID: jobID})
-		if err != nil {
-			return common.DataCleanupJobStatusDetail{}, 0, err
-		}
-		raw := strings.TrimSpace(result.Detail)
-		if raw == "" {
-			return common.DataCleanupJobStatusDetail{}, 0, formatDataCleanupError(
-				"data cleanup job status empty",
-				errors.New("empty status detail"),
-				map[string]any{"jobId": jobID, "module": module},
-			)
-		}
-		var detail common.DataCleanupJobStatusDetail
-		if err := json.Unmarshal([]byte(raw), &detail); err != nil {
-			return common.DataCleanupJobStatusDetail{}, 0, formatDataCleanupError(
-				"decode data cleanup job status failed",
-				err,
-				map[string]any{"jobId": jobID, "module": module, "detail": raw},
-			)
-		}
-		return detail, result.Deleted, nil
-	}
-
 	waitJob := func(module, jobID string) (common.DataCleanupJobStatusDetail, int64, error) {
 		ticker := time.NewTicker(2 * time.Second)
 		defer ticker.Stop()
@@ -970,12 +1439,27 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 		var lastDone int
 		var lastDeleted int64
 		var lastStatus string
+		var unreachableSince time.Time
 
 		for {
-			detail, deleted, err := fetchJobStatus(module, jobID)
+			detail, deleted, err := h.fetchDataCleanupJobStatus(systemID, module, jobID)
 			if err != nil {
-				return common.DataCleanupJobStatusDetail{}, 0, err
+				// 连接中断时保持轮询，agent 重连后会从新的连接继续读取同一个 job 的状态。
+				if unreachableSince.IsZero() {
+					unreachableSince = time.Now()
+					logs = append(logs, fmt.Sprintf("[%s] %s job status unavailable, waiting for agent: %s", time.Now().Format(time.RFC3339), module, err.Error()))
+				}
+				if time.Since(unreachableSince) > dataCleanupJobPollGrace {
+					return common.DataCleanupJobStatusDetail{}, 0, err
+				}
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return common.DataCleanupJobStatusDetail{}, h.stopDataCleanupAgentJob(systemID, module, jobID), errDataCleanupRunCancelled
+				}
+				continue
 			}
+			unreachableSince = time.Time{}
 
 			changed := detail.Done != lastDone || deleted != lastDeleted || detail.Status != lastStatus
 			if changed {
@@ -996,13 +1480,14 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 				lastDeleted = deleted
 				lastStatus = detail.Status
 
-				progress := int(float64(completedOps+detail.Done) / float64(totalOps) * 100)
+				progress := int((float64(completedOps+detail.Done) + dataCleanupItemFraction(detail.ItemDeleted)) / float64(totalOps) * 100)
 				if progress < 0 {
 					progress = 0
 				}
 				if progress > 100 {
 					progress = 100
 				}
+				job.setProgress(progress, 100)
 				if err := h.updateDataCleanupRun(runID, "running", progress, module, logs, results); err != nil {
 					h.logDataCleanupError("update cleanup run failed", err, "run", runID)
 					return common.DataCleanupJobStatusDetail{}, 0, err
@@ -1011,7 +1496,11 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 
 			switch detail.Status {
 			case "running":
-				<-ticker.C
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return common.DataCleanupJobStatusDetail{}, h.stopDataCleanupAgentJob(systemID, module, jobID), errDataCleanupRunCancelled
+				}
 				continue
 			case "success", "failed":
 				return detail, deleted, nil
@@ -1025,18 +1514,24 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 		}
 	}
 
-	if mysqlTargets > 0 {
+	if mysqlTargets > 0 && ctx.Err() == nil {
 		module := "mysql"
 		jobID := fmt.Sprintf("%s:%s", runID, module)
 		logs = append(logs, fmt.Sprintf("[%s] start mysql cleanup job", time.Now().Format(time.RFC3339)))
 		_, err := system.CleanupMySQLTablesFromAgent(common.DataCleanupMySQLDeleteTablesRequest{
-			Host:     mysqlStored.Host,
-			Port:     mysqlStored.Port,
-			Username: mysqlStored.Username,
-			Password: mysqlPassword,
-			Database: mysqlStored.Database,
-			Tables:   mysqlTables,
-			JobID:    jobID,
+			Host:       mysqlStored.Host,
+			Port:       mysqlStored.Port,
+			Username:   mysqlStored.Username,
+			Password:   mysqlPassword,
+			Database:   mysqlStored.Database,
+			Tables:     mysqlTables,
+			JobID:      jobID,
+			TimeoutSec: mysqlStored.ActionTimeoutSec,
+			TLS:        mysqlStored.DataCleanupTLS,
+			Confirm:    confirm,
+			DryRun:     dryRun,
+			Mode:       mysqlStored.Mode,
+			Conditions: dataCleanupMySQLConditionsToCommon(mysqlStored.Conditions),
 		})
 		if err != nil {
 			failures++
@@ -1047,7 +1542,7 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 			if err != nil {
 				failures++
 				logs = append(logs, fmt.Sprintf("[%s] mysql job poll failed: %s", time.Now().Format(time.RFC3339), err.Error()))
-				results = append(results, dataCleanupRunResult{Module: module, Status: "failed", Detail: err.Error()})
+				results = append(results, dataCleanupPollFailedResult(module, deleted, err))
 			} else if detail.Status == "failed" {
 				failures++
 				errMsg := strings.TrimSpace(detail.Error)
@@ -1055,6 +1550,10 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 					errMsg = "mysql cleanup job failed"
 				}
 				results = append(results, dataCleanupRunResult{Module: module, Status: "failed", Detail: errMsg})
+			} else if dryRun {
+				completedOps += mysqlTargets
+				logs = append(logs, fmt.Sprintf("[%s] mysql dry run completed matched=%d", time.Now().Format(time.RFC3339), deleted))
+				results = append(results, dataCleanupDryRunResult(module, deleted))
 			} else {
 				completedOps += mysqlTargets
 				logs = append(logs, fmt.Sprintf("[%s] mysql job completed deleted=%d", time.Now().Format(time.RFC3339), deleted))
@@ -1064,6 +1563,7 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 			if progress > 100 {
 				progress = 100
 			}
+			job.setProgress(completedOps, totalOps)
 			if err := h.updateDataCleanupRun(runID, "running", progress, module, logs, results); err != nil {
 				h.logDataCleanupError("update cleanup run failed", err, "run", runID)
 				return
@@ -1071,18 +1571,23 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 		}
 	}
 
-	if redisTargets > 0 {
+	if redisTargets > 0 && ctx.Err() == nil {
 		module := "redis"
 		jobID := fmt.Sprintf("%s:%s", runID, module)
 		logs = append(logs, fmt.Sprintf("[%s] start redis cleanup job", time.Now().Format(time.RFC3339)))
 		_, err := system.CleanupRedisFromAgent(common.DataCleanupRedisCleanupRequest{
-			Host:     redisStored.Host,
-			Port:     redisStored.Port,
-			Username: redisStored.Username,
-			Password: redisPassword,
-			DB:       redisStored.DB,
-			Patterns: redisPatterns,
-			JobID:    jobID,
+			Host:       redisStored.Host,
+			Port:       redisStored.Port,
+			Username:   redisStored.Username,
+			Password:   redisPassword,
+			DB:         redisStored.DB,
+			Patterns:   redisPatterns,
+			JobID:      jobID,
+			TimeoutSec: redisStored.ActionTimeoutSec,
+			TLS:        redisStored.DataCleanupTLS,
+			Confirm:    confirm,
+			DryRun:     dryRun,
+			Filter:     redisStored.Filter,
 		})
 		if err != nil {
 			failures++
@@ -1093,7 +1598,7 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 			if err != nil {
 				failures++
 				logs = append(logs, fmt.Sprintf("[%s] redis job poll failed: %s", time.Now().Format(time.RFC3339), err.Error()))
-				results = append(results, dataCleanupRunResult{Module: module, Status: "failed", Detail: err.Error()})
+				results = append(results, dataCleanupPollFailedResult(module, deleted, err))
 			} else if detail.Status == "failed" {
 				failures++
 				errMsg := strings.TrimSpace(detail.Error)
@@ -1101,15 +1606,22 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 					errMsg = "redis cleanup job failed"
 				}
 				results = append(results, dataCleanupRunResult{Module: module, Status: "failed", Detail: errMsg})
+			} else if dryRun {
+				completedOps += redisTargets
+				logs = append(logs, fmt.Sprintf("[%s] redis dry run completed matched=%d skipped=%d", time.Now().Format(time.RFC3339), deleted, detail.Skipped))
+				result := dataCleanupDryRunResult(module, deleted)
+				result.Skipped = detail.Skipped
+				results = append(results, result)
 			} else {
 				completedOps += redisTargets
-				logs = append(logs, fmt.Sprintf("[%s] redis job completed deleted=%d", time.Now().Format(time.RFC3339), deleted))
-				results = append(results, dataCleanupRunResult{Module: module, Status: "success"})
+				logs = append(logs, fmt.Sprintf("[%s] redis job completed deleted=%d skipped=%d", time.Now().Format(time.RFC3339), deleted, detail.Skipped))
+				results = append(results, dataCleanupRunResult{Module: module, Status: "success", Skipped: detail.Skipped})
 			}
 			progress := int(float64(completedOps) / float64(totalOps) * 100)
 			if progress > 100 {
 				progress = 100
 			}
+			job.setProgress(completedOps, totalOps)
 			if err := h.updateDataCleanupRun(runID, "running", progress, module, logs, results); err != nil {
 				h.logDataCleanupError("update cleanup run failed", err, "run", runID)
 				return
@@ -1117,18 +1629,23 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 		}
 	}
 
-	if minioTargets > 0 {
+	if minioTargets > 0 && ctx.Err() == nil {
 		module := "minio"
 		jobID := fmt.Sprintf("%s:%s", runID, module)
 		logs = append(logs, fmt.Sprintf("[%s] start minio cleanup job", time.Now().Format(time.RFC3339)))
 		_, err := system.CleanupMinioFromAgent(common.DataCleanupMinioCleanupRequest{
-			Host:      minioStored.Host,
-			Port:      minioStored.Port,
-			AccessKey: minioStored.AccessKey,
-			SecretKey: minioSecret,
-			Bucket:    minioStored.Bucket,
-			Prefixes:  minioPrefixes,
-			JobID:     jobID,
+			Host:        minioStored.Host,
+			Port:        minioStored.Port,
+			AccessKey:   minioStored.AccessKey,
+			SecretKey:   minioSecret,
+			Bucket:      minioStored.Bucket,
+			Prefixes:    minioPrefixes,
+			JobID:       jobID,
+			TimeoutSec:  minioStored.ActionTimeoutSec,
+			TLS:         minioStored.DataCleanupTLS,
+			Concurrency: minioStored.Concurrency,
+			Confirm:     confirm,
+			DryRun:      dryRun,
 		})
 		if err != nil {
 			failures++
@@ -1139,7 +1656,7 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 			if err != nil {
 				failures++
 				logs = append(logs, fmt.Sprintf("[%s] minio job poll failed: %s", time.Now().Format(time.RFC3339), err.Error()))
-				results = append(results, dataCleanupRunResult{Module: module, Status: "failed", Detail: err.Error()})
+				results = append(results, dataCleanupPollFailedResult(module, deleted, err))
 			} else if detail.Status == "failed" {
 				failures++
 				errMsg := strings.TrimSpace(detail.Error)
@@ -1147,6 +1664,10 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 					errMsg = "minio cleanup job failed"
 				}
 				results = append(results, dataCleanupRunResult{Module: module, Status: "failed", Detail: errMsg})
+			} else if dryRun {
+				completedOps += minioTargets
+				logs = append(logs, fmt.Sprintf("[%s] minio dry run completed matched=%d", time.Now().Format(time.RFC3339), deleted))
+				results = append(results, dataCleanupDryRunResult(module, deleted))
 			} else {
 				completedOps += minioTargets
 				logs = append(logs, fmt.Sprintf("[%s] minio job completed deleted=%d", time.Now().Format(time.RFC3339), deleted))
@@ -1156,6 +1677,7 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 			if progress > 100 {
 				progress = 100
 			}
+			job.setProgress(completedOps, totalOps)
 			if err := h.updateDataCleanupRun(runID, "running", progress, module, logs, results); err != nil {
 				h.logDataCleanupError("update cleanup run failed", err, "run", runID)
 				return
@@ -1163,17 +1685,27 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 		}
 	}
 
-	if esTargets > 0 {
+	if esTargets > 0 && ctx.Err() == nil {
 		module := "es"
 		jobID := fmt.Sprintf("%s:%s", runID, module)
+		// stored modes are validated on save; an invalid one is reported as is and rejected by the agent
+		esMode, modeErr := common.NormalizeDataCleanupESMode(esStored.Mode)
+		if modeErr != nil {
+			esMode = esStored.Mode
+		}
 		logs = append(logs, fmt.Sprintf("[%s] start es cleanup job", time.Now().Format(time.RFC3339)))
 		_, err := system.CleanupESFromAgent(common.DataCleanupESCleanupRequest{
-			Host:     esStored.Host,
-			Port:     esStored.Port,
-			Username: esStored.Username,
-			Password: esPassword,
-			Indices:  esIndices,
-			JobID:    jobID,
+			Host:       esStored.Host,
+			Port:       esStored.Port,
+			Username:   esStored.Username,
+			Password:   esPassword,
+			Indices:    esIndices,
+			JobID:      jobID,
+			TimeoutSec: esStored.ActionTimeoutSec,
+			TLS:        esStored.DataCleanupTLS,
+			Confirm:    confirm,
+			DryRun:     dryRun,
+			Mode:       esStored.Mode,
 		})
 		if err != nil {
 			failures++
@@ -1184,7 +1716,7 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 			if err != nil {
 				failures++
 				logs = append(logs, fmt.Sprintf("[%s] es job poll failed: %s", time.Now().Format(time.RFC3339), err.Error()))
-				results = append(results, dataCleanupRunResult{Module: module, Status: "failed", Detail: err.Error()})
+				results = append(results, dataCleanupPollFailedResult(module, deleted, err))
 			} else if detail.Status == "failed" {
 				failures++
 				errMsg := strings.TrimSpace(detail.Error)
@@ -1192,15 +1724,32 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 					errMsg = "es cleanup job failed"
 				}
 				results = append(results, dataCleanupRunResult{Module: module, Status: "failed", Detail: errMsg})
+			} else if dryRun {
+				completedOps += esTargets
+				logs = append(logs, fmt.Sprintf("[%s] es dry run completed matched=%d", time.Now().Format(time.RFC3339), deleted))
+				result := dataCleanupDryRunResult(module, deleted)
+				result.Mode = esMode
+				results = append(results, result)
+			} else if esMode == common.DataCleanupESModeDropIndex {
+				completedOps += esTargets
+				logs = append(logs, fmt.Sprintf("[%s] es job completed mode=%s dropped=%s", time.Now().Format(time.RFC3339), esMode, strings.Join(detail.Dropped, ",")))
+				results = append(results, dataCleanupRunResult{
+					Module:  module,
+					Status:  "success",
+					Detail:  fmt.Sprintf("dropped %d of %d indices", len(detail.Dropped), len(esIndices)),
+					Mode:    esMode,
+					Dropped: detail.Dropped,
+				})
 			} else {
 				completedOps += esTargets
-				logs = append(logs, fmt.Sprintf("[%s] es job completed deleted=%d", time.Now().Format(time.RFC3339), deleted))
-				results = append(results, dataCleanupRunResult{Module: module, Status: "success"})
+				logs = append(logs, fmt.Sprintf("[%s] es job completed mode=%s deleted=%d", time.Now().Format(time.RFC3339), esMode, deleted))
+				results = append(results, dataCleanupRunResult{Module: module, Status: "success", Mode: esMode})
 			}
 			progress := int(float64(completedOps) / float64(totalOps) * 100)
 			if progress > 100 {
 				progress = 100
 			}
+			job.setProgress(completedOps, totalOps)
 			if err := h.updateDataCleanupRun(runID, "running", progress, module, logs, results); err != nil {
 				h.logDataCleanupError("update cleanup run failed", err, "run", runID)
 				return
@@ -1212,24 +1761,124 @@ func (h *Hub) executeDataCleanupRun(runID, systemID, configID, userID string) {
 	if failures > 0 {
 		status = "failed"
 	}
+	if ctx.Err() != nil {
+		status = dataCleanupStatusCancelled
+		logs = append(logs, fmt.Sprintf("[%s] cleanup run cancelled", time.Now().Format(time.RFC3339)))
+		results = append(results, dataCleanupRunResult{Module: "run", Status: dataCleanupStatusCancelled, Detail: errDataCleanupRunCancelled.Error()})
+	}
 	if err := h.updateDataCleanupRun(runID, status, 100, "done", logs, results); err != nil {
 		h.logDataCleanupError("finalize cleanup run failed", err, "run", runID)
 		return
 	}
 
+	if dryRun {
+		return
+	}
+	detail := fmt.Sprintf("cleanup run %s", status)
+	if opts.Source != "" {
+		detail = fmt.Sprintf("cleanup run %s (source: %s)", status, opts.Source)
+	}
 	if auditErr := h.recordDockerAudit(dockerAuditEntry{
 		SystemID:     systemID,
-		UserID:       userID,
+		UserID:       opts.UserID,
 		Action:       "data_cleanup.run",
 		ResourceType: "data_cleanup",
 		ResourceID:   runID,
 		Status:       status,
-		Detail:       fmt.Sprintf("cleanup run %s", status),
+		Detail:       detail,
 	}); auditErr != nil {
 		h.logDataCleanupError("record cleanup audit failed", auditErr, "run", runID)
 	}
 }
 
+// dataCleanupItemFraction converts the amount deleted by in-flight items into a fraction of one
+// item in [0, 1), so progress moves while a large item runs without passing the next item boundary.
+func dataCleanupItemFraction(itemDeleted int64) float64 {
+	if itemDeleted <= 0 {
+		return 0
+	}
+	deleted := float64(itemDeleted)
+	return deleted / (deleted + dataCleanupItemProgressScale)
+}
+
+// dataCleanupPollFailedResult records a module whose job could not be followed to the end. A run
+// cancelled while the module was running keeps the amount deleted before the agent job stopped.
+func dataCleanupPollFailedResult(module string, deleted int64, err error) dataCleanupRunResult {
+	if errors.Is(err, errDataCleanupRunCancelled) {
+		return dataCleanupRunResult{Module: module, Status: dataCleanupStatusCancelled, Detail: err.Error(), Deleted: deleted}
+	}
+	return dataCleanupRunResult{Module: module, Status: "failed", Detail: err.Error()}
+}
+
+// stopDataCleanupAgentJob cancels a running agent job and waits up to dataCleanupCancelWait for it
+// to stop, returning the amount it deleted. Errors are logged; the agent job then runs to its end.
+func (h *Hub) stopDataCleanupAgentJob(systemID, module, jobID string) int64 {
+	system, err := h.resolveSystem(systemID)
+	if err != nil {
+		h.logDataCleanupError("cancel cleanup job failed", err, "system", systemID, "jobId", jobID)
+		return 0
+	}
+	result, err := system.CancelDataCleanupJobFromAgent(common.DataCleanupJobCancelRequest{JobID: jobID})
+	if err != nil {
+		h.logDataCleanupError("cancel cleanup job failed", err, "system", systemID, "jobId", jobID)
+		return 0
+	}
+	deleted := result.Deleted
+	deadline := time.Now().Add(dataCleanupCancelWait)
+	for time.Now().Before(deadline) {
+		detail, polled, err := h.fetchDataCleanupJobStatus(systemID, module, jobID)
+		if err != nil {
+			break
+		}
+		deleted = polled
+		if detail.Status != "running" {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return deleted
+}
+
+// dataCleanupDryRunResult records the count of a module that completed in a dry run.
+func dataCleanupDryRunResult(module string, matched int64) dataCleanupRunResult {
+	return dataCleanupRunResult{
+		Module:      module,
+		Status:      dataCleanupResultDryRun,
+		Detail:      fmt.Sprintf("would delete %d", matched),
+		WouldDelete: matched,
+	}
+}
+
+// fetchDataCleanupJobStatus polls an agent cleanup job. The system is resolved on every call so
+// polling moves to the new connection after the agent reconnects.
+func (h *Hub) fetchDataCleanupJobStatus(systemID, module, jobID string) (common.DataCleanupJobStatusDetail, int64, error) {
+	system, err := h.resolveSystem(systemID)
+	if err != nil {
+		return common.DataCleanupJobStatusDetail{}, 0, err
+	}
+	result, err := system.FetchDataCleanupJobStatusFromAgent(common.DataCleanupJobStatusRequest{JobID: jobID})
+	if err != nil {
+		return common.DataCleanupJobStatusDetail{}, 0, err
+	}
+	raw := strings.TrimSpace(result.Detail)
+	if raw == "" {
+		return common.DataCleanupJobStatusDetail{}, 0, formatDataCleanupError(
+			"data cleanup job status empty",
+			errors.New("empty status detail"),
+			map[string]any{"jobId": jobID, "module": module},
+		)
+	}
+	var detail common.DataCleanupJobStatusDetail
+	if err := json.Unmarshal([]byte(raw), &detail); err != nil {
+		return common.DataCleanupJobStatusDetail{}, 0, formatDataCleanupError(
+			"decode data cleanup job status failed",
+			err,
+			map[string]any{"jobId": jobID, "module": module, "detail": raw},
+		)
+	}
+	return detail, result.Deleted, nil
+}
+
 func (h *Hub) updateDataCleanupRun(
 	runID string,
 	status string,
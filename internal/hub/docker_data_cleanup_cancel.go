@@ -0,0 +1,64 @@
+// Package hub 提供进行中数据清理运行的取消。
+// 运行在执行期间登记在运行中任务注册表里（id 为 data_cleanup:<runId>），取消即取消该任务的 context：
+// 执行流程停止后续模块，并通知 agent 停止当前模块的 job，运行以 cancelled 结束并记录取消前已删除的数量。
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type dataCleanupCancelPayload struct {
+	RunID string `json:"runId"`
+}
+
+// cancelDataCleanupRun handles POST /api/aether/docker/data-cleanup/cancel requests.
+// The run reaches the cancelled status asynchronously once the current agent job has stopped.
+func (h *Hub) cancelDataCleanupRun(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload dataCleanupCancelPayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	runID := strings.TrimSpace(payload.RunID)
+	if runID == "" {
+		return respondError(e, http.StatusBadRequest, "runId is required")
+	}
+	record, err := h.FindRecordById(dataCleanupRunsCollection, runID)
+	if err != nil {
+		return respondError(e, http.StatusNotFound, "run not found")
+	}
+	systemID := record.GetString("system")
+	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	if status := record.GetString("status"); status != "pending" && status != "running" {
+		return respondError(e, http.StatusConflict, fmt.Sprintf("run is %s, not in progress", status))
+	}
+	job := h.jobs.get(runningJobTypeDataCleanup + ":" + runID)
+	if job == nil {
+		return respondError(e, http.StatusConflict, "run is not executing on this hub")
+	}
+	if !job.requestCancel() {
+		return respondError(e, http.StatusConflict, "run cannot be cancelled")
+	}
+
+	if auditErr := h.recordDockerAudit(dockerAuditEntry{
+		SystemID:     systemID,
+		UserID:       e.Auth.Id,
+		Action:       "data_cleanup.cancel",
+		ResourceType: "data_cleanup",
+		ResourceID:   runID,
+		Status:       "success",
+		Detail:       "cleanup run cancel requested",
+	}); auditErr != nil {
+		h.logDataCleanupError("record cleanup audit failed", auditErr, "run", runID)
+	}
+	return e.JSON(http.StatusOK, map[string]any{"status": "ok", "runId": runID})
+}
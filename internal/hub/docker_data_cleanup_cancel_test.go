@@ -0,0 +1,104 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelDataCleanupRun(t *testing.T) {
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	user, err := createTestUser(testApp)
+	require.NoError(t, err)
+	system, err := createTestRecord(testApp, "systems", map[string]any{
+		"name":   "cleanup",
+		"host":   "localhost",
+		"port":   "45876",
+		"status": "pending",
+		"users":  []string{user.Id},
+	})
+	require.NoError(t, err)
+	config, err := createTestRecord(testApp, dataCleanupConfigCollection, map[string]any{"system": system.Id})
+	require.NoError(t, err)
+
+	cancel := func(runID string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		e := &core.RequestEvent{App: testApp, Auth: user}
+		e.Request = httptest.NewRequest(http.MethodPost, "/api/aether/docker/data-cleanup/cancel", strings.NewReader(`{"runId":"`+runID+`"}`))
+		e.Response = recorder
+		require.NoError(t, hub.cancelDataCleanupRun(e))
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusNotFound, cancel("missing").Code)
+
+	finished, err := hub.createDataCleanupRun(system.Id, config.Id, "", user.Id, false)
+	require.NoError(t, err)
+	finished.Set("status", "success")
+	require.NoError(t, testApp.Save(finished))
+	recorder := cancel(finished.Id)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "run is success, not in progress")
+
+	run, err := hub.createDataCleanupRun(system.Id, config.Id, "", user.Id, false)
+	require.NoError(t, err)
+	recorder = cancel(run.Id)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "run is not executing on this hub")
+
+	job, ctx := hub.jobs.start(runningJobTypeDataCleanup+":"+run.Id, runningJobTypeDataCleanup, run.Id, system.Id, true)
+	recorder = cancel(run.Id)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	assert.Error(t, ctx.Err(), "cancelling the run cancels its job")
+	audits, err := testApp.FindAllRecords("docker_audits", dbx.HashExp{"resource_id": run.Id, "action": "data_cleanup.cancel"})
+	require.NoError(t, err)
+	require.Len(t, audits, 1)
+	assert.Equal(t, user.Id, audits[0].GetString("user"))
+
+	// the executor sees the cancelled context and finishes the run as cancelled
+	hub.executeDataCleanupRun(ctx, job, run.Id, system.Id, config.Id, dataCleanupRunOptions{UserID: user.Id, Confirm: true})
+	hub.jobs.finish(job)
+	run, err = testApp.FindRecordById(dataCleanupRunsCollection, run.Id)
+	require.NoError(t, err)
+	assert.Equal(t, dataCleanupStatusCancelled, run.GetString("status"))
+	var results []dataCleanupRunResult
+	require.NoError(t, parseJSONField(run, "results", &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, dataCleanupStatusCancelled, results[0].Status)
+
+	recorder = cancel(run.Id)
+	assert.Equal(t, http.StatusConflict, recorder.Code, "a cancelled run cannot be cancelled again")
+}
+
+func TestDataCleanupPollFailedResult(t *testing.T) {
+	// a module cancelled mid-run keeps the amount the agent job deleted before it stopped
+	result := dataCleanupPollFailedResult("mysql", 42, errDataCleanupRunCancelled)
+	assert.Equal(t, dataCleanupStatusCancelled, result.Status)
+	assert.EqualValues(t, 42, result.Deleted)
+
+	result = dataCleanupPollFailedResult("mysql", 42, errors.New("agent unreachable"))
+	assert.Equal(t, "failed", result.Status)
+	assert.Zero(t, result.Deleted, "a poll failure does not report a deleted count")
+	assert.Equal(t, "agent unreachable", result.Detail)
+}
+
+func TestStopDataCleanupAgentJobWithoutAgent(t *testing.T) {
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+
+	// an unknown system cannot be reached, so nothing is reported as deleted
+	assert.Zero(t, hub.stopDataCleanupAgentJob("missing", "mysql", "run:mysql"))
+}
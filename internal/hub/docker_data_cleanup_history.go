@@ -0,0 +1,128 @@
+// Package hub 提供数据清理运行历史的分页查询。
+// 按系统列出 docker_data_cleanup_runs 中的历史运行（新的在前），每条只返回各模块的状态摘要；完整日志与结果仍通过单条运行接口获取。
+package hub
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	dataCleanupRunsDefaultPerPage = 50
+	dataCleanupRunsMaxPerPage     = 200
+)
+
+// dataCleanupRunModuleSummary is the per-module part of a run result without its detail text.
+type dataCleanupRunModuleSummary struct {
+	Module      string `json:"module"`
+	Status      string `json:"status"`
+	WouldDelete int64  `json:"wouldDelete,omitempty"`
+	Deleted     int64  `json:"deleted,omitempty"`
+	Skipped     int64  `json:"skipped,omitempty"`
+	// Mode and Dropped are only set for ES results
+	Mode    string   `json:"mode,omitempty"`
+	Dropped []string `json:"dropped,omitempty"`
+}
+
+type dataCleanupRunListItem struct {
+	ID       string                        `json:"id"`
+	Status   string                        `json:"status"`
+	Progress int                           `json:"progress"`
+	Step     string                        `json:"step"`
+	Created  string                        `json:"created"`
+	Results  []dataCleanupRunModuleSummary `json:"results"`
+}
+
+type dataCleanupRunsResponse struct {
+	Items      []dataCleanupRunListItem `json:"items"`
+	Page       int                      `json:"page"`
+	PerPage    int                      `json:"perPage"`
+	TotalItems int                      `json:"totalItems"`
+	TotalPages int                      `json:"totalPages"`
+}
+
+// listDataCleanupRuns handles GET /api/aether/docker/data-cleanup/runs requests.
+func (h *Hub) listDataCleanupRuns(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	systemID := strings.TrimSpace(query.Get("system"))
+	if systemID == "" {
+		return respondError(e, http.StatusBadRequest, "system is required")
+	}
+	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	page := apiTestParseInt(query.Get("page"), 1)
+	perPage := apiTestParseInt(query.Get("perPage"), dataCleanupRunsDefaultPerPage)
+	if perPage <= 0 {
+		perPage = dataCleanupRunsDefaultPerPage
+	}
+	if perPage > dataCleanupRunsMaxPerPage {
+		perPage = dataCleanupRunsMaxPerPage
+	}
+
+	totalItems64, err := h.CountRecords(dataCleanupRunsCollection, dbx.HashExp{"system": systemID})
+	if err != nil {
+		h.logDataCleanupError("count cleanup runs failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	totalItems := int(totalItems64)
+	totalPages := totalItems / perPage
+	if totalItems%perPage != 0 {
+		totalPages++
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if totalPages > 0 && page > totalPages {
+		page = totalPages
+	}
+	offset := (page - 1) * perPage
+	records, err := h.FindRecordsByFilter(dataCleanupRunsCollection, "system = {:system}", "-created", perPage, offset, dbx.Params{"system": systemID})
+	if err != nil {
+		h.logDataCleanupError("list cleanup runs failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	items := make([]dataCleanupRunListItem, 0, len(records))
+	for _, record := range records {
+		items = append(items, dataCleanupRunListItem{
+			ID:       record.Id,
+			Status:   record.GetString("status"),
+			Progress: record.GetInt("progress"),
+			Step:     record.GetString("step"),
+			Created:  formatHealthTime(record.GetDateTime("created").Time()),
+			Results:  h.dataCleanupRunModuleSummaries(record),
+		})
+	}
+	return e.JSON(http.StatusOK, dataCleanupRunsResponse{
+		Items:      items,
+		Page:       page,
+		PerPage:    perPage,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	})
+}
+
+// dataCleanupRunModuleSummaries reads the module results of a run. Results that fail to parse are
+// logged and reported as empty so one damaged record does not break the list.
+func (h *Hub) dataCleanupRunModuleSummaries(record *core.Record) []dataCleanupRunModuleSummary {
+	var results []dataCleanupRunResult
+	if err := parseJSONField(record, "results", &results); err != nil {
+		h.logDataCleanupError("parse cleanup run results failed", err, "run", record.Id)
+	}
+	summaries := make([]dataCleanupRunModuleSummary, 0, len(results))
+	for _, result := range results {
+		summaries = append(summaries, dataCleanupRunModuleSummary{
+			Module:      result.Module,
+			Status:      result.Status,
+			WouldDelete: result.WouldDelete,
+			Deleted:     result.Deleted,
+			Skipped:     result.Skipped,
+			Mode:        result.Mode,
+			Dropped:     result.Dropped,
+		})
+	}
+	return summaries
+}
@@ -0,0 +1,112 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDataCleanupRuns(t *testing.T) {
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	user, err := createTestUser(testApp)
+	require.NoError(t, err)
+	other, err := createTestRecord(testApp, "users", map[string]any{"email": "other@test.com", "password": "testtesttest"})
+	require.NoError(t, err)
+	system, err := createTestRecord(testApp, "systems", map[string]any{
+		"name":   "cleanup",
+		"host":   "localhost",
+		"port":   "45876",
+		"status": "pending",
+		"users":  []string{user.Id},
+	})
+	require.NoError(t, err)
+	config, err := createTestRecord(testApp, dataCleanupConfigCollection, map[string]any{"system": system.Id})
+	require.NoError(t, err)
+
+	runIDs := make([]string, 0, 5)
+	for range 5 {
+		run, err := hub.createDataCleanupRun(system.Id, config.Id, "", user.Id, false)
+		require.NoError(t, err)
+		runIDs = append(runIDs, run.Id)
+	}
+	summarized, err := testApp.FindRecordById(dataCleanupRunsCollection, runIDs[0])
+	require.NoError(t, err)
+	summarized.Set("status", "failed")
+	summarized.Set("results", types.JSONRaw(`[
+		{"module":"mysql","status":"failed","detail":"access denied for user cleaner"},
+		{"module":"redis","status":"success","skipped":3},
+		{"module":"es","status":"success","mode":"drop_index","dropped":["logs-1"]}
+	]`))
+	require.NoError(t, testApp.Save(summarized))
+	damaged, err := testApp.FindRecordById(dataCleanupRunsCollection, runIDs[1])
+	require.NoError(t, err)
+	damaged.Set("results", types.JSONRaw(`{"module":"mysql"}`))
+	require.NoError(t, testApp.Save(damaged))
+
+	list := func(auth *core.Record, query string) (*httptest.ResponseRecorder, dataCleanupRunsResponse) {
+		recorder := httptest.NewRecorder()
+		e := &core.RequestEvent{App: testApp, Auth: auth}
+		e.Request = httptest.NewRequest(http.MethodGet, "/api/aether/docker/data-cleanup/runs?"+query, nil)
+		e.Response = recorder
+		require.NoError(t, hub.listDataCleanupRuns(e))
+		var response dataCleanupRunsResponse
+		if recorder.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		}
+		return recorder, response
+	}
+
+	recorder, _ := list(user, "")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code, "system is required")
+	recorder, _ = list(other, "system="+system.Id)
+	assert.Equal(t, http.StatusForbidden, recorder.Code, "users without access to the system see no runs")
+	recorder, _ = list(user, "system=missing")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	_, response := list(user, "system="+system.Id+"&perPage=2")
+	assert.Equal(t, 1, response.Page)
+	assert.Equal(t, 2, response.PerPage)
+	assert.Equal(t, 5, response.TotalItems)
+	assert.Equal(t, 3, response.TotalPages)
+	assert.Len(t, response.Items, 2)
+
+	_, response = list(user, "system="+system.Id+"&perPage=2&page=99")
+	assert.Equal(t, 3, response.Page, "a page past the end is clamped to the last page")
+	assert.Len(t, response.Items, 1)
+
+	_, response = list(user, "system="+system.Id+"&perPage=2&page=0")
+	assert.Equal(t, 1, response.Page)
+
+	_, response = list(user, "system="+system.Id+"&perPage=1000")
+	assert.Equal(t, dataCleanupRunsMaxPerPage, response.PerPage)
+	_, response = list(user, "system="+system.Id+"&perPage=-1")
+	assert.Equal(t, dataCleanupRunsDefaultPerPage, response.PerPage)
+	require.Len(t, response.Items, 5)
+
+	items := make(map[string]dataCleanupRunListItem, len(response.Items))
+	for _, item := range response.Items {
+		items[item.ID] = item
+	}
+	assert.Equal(t, "failed", items[runIDs[0]].Status)
+	assert.Equal(t, []dataCleanupRunModuleSummary{
+		{Module: "mysql", Status: "failed"},
+		{Module: "redis", Status: "success", Skipped: 3},
+		{Module: "es", Status: "success", Mode: "drop_index", Dropped: []string{"logs-1"}},
+	}, items[runIDs[0]].Results, "summaries leave out the detail text")
+	assert.Empty(t, items[runIDs[1]].Results, "damaged results do not break the list")
+	assert.Equal(t, "queued", items[runIDs[2]].Step)
+
+	recorder, _ = list(user, "system="+system.Id)
+	assert.NotContains(t, recorder.Body.String(), "access denied")
+}
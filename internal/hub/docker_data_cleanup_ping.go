@@ -0,0 +1,119 @@
+// Package hub 提供数据清理各模块（MySQL/Redis/MinIO/ES）的连接测试。
+// 由指定主机上的 agent 仅建立连接并校验凭据，不列举库、桶或索引，也不做任何修改；密码可沿用已保存的配置。
+// 连接失败在响应体中以 success=false 返回，而不是错误状态码，便于前端直接展示原因。
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"aether/internal/common"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// dataCleanupPingTarget is the connection to test, taken from a module's list payload.
+// For MinIO, Username and Secret are the access key and secret key.
+type dataCleanupPingTarget struct {
+	System          string
+	Host            string
+	Port            int
+	Username        string
+	Secret          string
+	UseStoredSecret bool
+	TimeoutSec      int
+	TLS             common.DataCleanupTLS
+}
+
+// pingDataCleanupMySQL handles POST /api/aether/docker/data-cleanup/mysql/ping requests.
+func (h *Hub) pingDataCleanupMySQL(e *core.RequestEvent) error {
+	return h.pingDataCleanupPasswordModule(e, "mysql")
+}
+
+// pingDataCleanupRedis handles POST /api/aether/docker/data-cleanup/redis/ping requests.
+func (h *Hub) pingDataCleanupRedis(e *core.RequestEvent) error {
+	return h.pingDataCleanupPasswordModule(e, "redis")
+}
+
+// pingDataCleanupES handles POST /api/aether/docker/data-cleanup/es/ping requests.
+func (h *Hub) pingDataCleanupES(e *core.RequestEvent) error {
+	return h.pingDataCleanupPasswordModule(e, "es")
+}
+
+// pingDataCleanupMinio handles POST /api/aether/docker/data-cleanup/minio/ping requests.
+func (h *Hub) pingDataCleanupMinio(e *core.RequestEvent) error {
+	var payload dataCleanupMinioListPayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	return h.pingDataCleanupModule(e, "minio", "minio_secret_key", dataCleanupPingTarget{
+		System:          payload.System,
+		Host:            payload.Host,
+		Port:            payload.Port,
+		Username:        strings.TrimSpace(payload.AccessKey),
+		Secret:          payload.SecretKey,
+		UseStoredSecret: payload.UseStoredSecret,
+		TimeoutSec:      payload.TimeoutSec,
+		TLS:             payload.DataCleanupTLS,
+	})
+}
+
+// pingDataCleanupPasswordModule tests a module whose list payload carries a username and password.
+func (h *Hub) pingDataCleanupPasswordModule(e *core.RequestEvent, module string) error {
+	var payload dataCleanupListPayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	return h.pingDataCleanupModule(e, module, module+"_password", dataCleanupPingTarget{
+		System:          payload.System,
+		Host:            payload.Host,
+		Port:            payload.Port,
+		Username:        payload.Username,
+		Secret:          payload.Password,
+		UseStoredSecret: payload.UseStoredPassword,
+		TimeoutSec:      payload.TimeoutSec,
+		TLS:             payload.DataCleanupTLS,
+	})
+}
+
+// pingDataCleanupModule asks the agent to connect to the target and reports whether it succeeded
+// and how long the round trip took.
+func (h *Hub) pingDataCleanupModule(e *core.RequestEvent, module string, secretField string, target dataCleanupPingTarget) error {
+	target.Host = strings.TrimSpace(target.Host)
+	if target.System == "" || target.Host == "" || target.Port <= 0 {
+		return respondError(e, http.StatusBadRequest, "system, host and port are required")
+	}
+	if target.TimeoutSec < 0 || target.TimeoutSec > dataCleanupMaxListTimeoutSec {
+		return respondError(e, http.StatusBadRequest, fmt.Sprintf("timeoutSec must be between 0 and %d", dataCleanupMaxListTimeoutSec))
+	}
+	if _, err := h.resolveSystemRecordForUser(e, target.System); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	secret, err := h.resolveCleanupPassword(target.System, secretField, target.Secret, target.UseStoredSecret)
+	if err != nil {
+		h.logDataCleanupError("resolve "+module+" secret failed", err, "system", target.System)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	system, err := h.resolveSystem(target.System)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	start := time.Now()
+	_, err = system.PingDataCleanupFromAgent(common.DataCleanupPingRequest{
+		Module:     module,
+		Host:       target.Host,
+		Port:       target.Port,
+		Username:   target.Username,
+		Password:   secret,
+		TimeoutSec: h.resolveCleanupListTimeout(target.System, module, target.TimeoutSec),
+		TLS:        target.TLS,
+	})
+	latencyMs := time.Since(start).Milliseconds()
+	if err != nil {
+		return e.JSON(http.StatusOK, map[string]any{"success": false, "message": err.Error(), "latencyMs": latencyMs})
+	}
+	return e.JSON(http.StatusOK, map[string]any{"success": true, "latencyMs": latencyMs})
+}
@@ -0,0 +1,69 @@
+// Package hub 提供数据清理任务的并发控制。
+// 多个系统的清理任务共享固定数量的执行槽位，超出的任务保持 pending 排队，避免同时冲击共享的存储设施。
+package hub
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultDataCleanupMaxConcurrentRuns is used when DATA_CLEANUP_MAX_CONCURRENT_RUNS is unset or invalid.
+const defaultDataCleanupMaxConcurrentRuns = 4
+
+// dataCleanupRunQueue bounds how many cleanup runs execute at once across all systems.
+// A run that hangs only holds its own slot; each agent job is still bounded by its action timeout.
+type dataCleanupRunQueue struct {
+	slots  chan struct{}
+	queued atomic.Int32
+	active atomic.Int32
+}
+
+type dataCleanupQueueStats struct {
+	MaxConcurrent int `json:"maxConcurrent"`
+	Active        int `json:"active"`
+	Queued        int `json:"queued"`
+}
+
+func newDataCleanupRunQueue(limit int) *dataCleanupRunQueue {
+	if limit <= 0 {
+		limit = defaultDataCleanupMaxConcurrentRuns
+	}
+	return &dataCleanupRunQueue{slots: make(chan struct{}, limit)}
+}
+
+// dataCleanupMaxConcurrentRunsFromEnv reads DATA_CLEANUP_MAX_CONCURRENT_RUNS. The limit is env-only on
+// purpose: cleanup configs are per system while the limit is shared by all of them, and the slots are
+// sized once when the hub starts, so changing it takes a restart either way.
+func dataCleanupMaxConcurrentRunsFromEnv() int {
+	value, ok := GetEnv("DATA_CLEANUP_MAX_CONCURRENT_RUNS")
+	if !ok {
+		return defaultDataCleanupMaxConcurrentRuns
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || limit <= 0 {
+		return defaultDataCleanupMaxConcurrentRuns
+	}
+	return limit
+}
+
+// run blocks until a slot is free, then executes fn. Callers start it in a goroutine.
+func (q *dataCleanupRunQueue) run(fn func()) {
+	q.queued.Add(1)
+	q.slots <- struct{}{}
+	q.queued.Add(-1)
+	q.active.Add(1)
+	defer func() {
+		q.active.Add(-1)
+		<-q.slots
+	}()
+	fn()
+}
+
+func (q *dataCleanupRunQueue) stats() dataCleanupQueueStats {
+	return dataCleanupQueueStats{
+		MaxConcurrent: cap(q.slots),
+		Active:        int(q.active.Load()),
+		Queued:        int(q.queued.Load()),
+	}
+}
@@ -0,0 +1,230 @@
+// Package hub 提供 agent 重连后数据清理任务的状态对账。
+// 清理 job 在 agent 上独立运行，hub 与 agent 的连接中断（或 hub 重启）后，运行记录可能停留在 running。
+// agent 每次通过 WebSocket 连接时，hub 查询 agent 仍保留的 job 列表：匹配到的运行记录重新挂上进度轮询，
+// 找不到对应 job 的运行记录标记为失败。仍由本进程执行的运行自行重连轮询，不在此处理。
+// 接续的运行结束后按运行记录的 triggered_by 写入审计，旧记录未保存发起用户时以系统身份记录。
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"aether/internal/common"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+var errDataCleanupJobGone = errors.New("cleanup job no longer exists on agent")
+
+// dataCleanupReconcileMu serializes reattaching so concurrent connects never poll a run twice.
+var dataCleanupReconcileMu sync.Mutex
+
+type dataCleanupOrphanRun struct {
+	ID       string `db:"id"`
+	Progress int    `db:"progress"`
+}
+
+// reconcileDataCleanupRuns runs after an agent connects. Unfinished runs of the system that no
+// in-process executor owns are either reattached to the agent jobs they started or marked failed.
+func (h *Hub) reconcileDataCleanupRuns(systemID string) {
+	var runs []dataCleanupOrphanRun
+	err := h.DB().
+		NewQuery("SELECT id, progress FROM " + dataCleanupRunsCollection + " WHERE system = {:system} AND status IN ('pending','running')").
+		Bind(dbx.Params{"system": systemID}).
+		All(&runs)
+	if err != nil {
+		h.logDataCleanupError("load unfinished cleanup runs failed", err, "system", systemID)
+		return
+	}
+	orphans := make([]dataCleanupOrphanRun, 0, len(runs))
+	for _, run := range runs {
+		if h.jobs.get(runningJobTypeDataCleanup+":"+run.ID) == nil {
+			orphans = append(orphans, run)
+		}
+	}
+	if len(orphans) == 0 {
+		return
+	}
+
+	system, err := h.resolveSystem(systemID)
+	if err != nil {
+		h.logDataCleanupError("resolve system failed", err, "system", systemID)
+		return
+	}
+	list, err := system.FetchDataCleanupJobsFromAgent()
+	if err != nil {
+		// leave the runs untouched; the next connect retries
+		h.logDataCleanupError("list agent cleanup jobs failed", err, "system", systemID)
+		return
+	}
+
+	for _, run := range orphans {
+		prefix := run.ID + ":"
+		jobs := make([]common.DataCleanupJobStatusDetail, 0, 4)
+		for _, detail := range list.Jobs {
+			if strings.HasPrefix(detail.JobID, prefix) {
+				jobs = append(jobs, detail)
+			}
+		}
+		sort.Slice(jobs, func(i, j int) bool { return jobs[i].JobID < jobs[j].JobID })
+
+		dataCleanupReconcileMu.Lock()
+		if h.jobs.get(runningJobTypeDataCleanup+":"+run.ID) != nil {
+			dataCleanupReconcileMu.Unlock()
+			continue
+		}
+		job, _ := h.jobs.start(runningJobTypeDataCleanup+":"+run.ID, runningJobTypeDataCleanup, run.ID, systemID, false)
+		dataCleanupReconcileMu.Unlock()
+
+		go func(run dataCleanupOrphanRun, jobs []common.DataCleanupJobStatusDetail) {
+			defer h.jobs.finish(job)
+			h.resumeDataCleanupRun(systemID, run, jobs)
+		}(run, jobs)
+	}
+}
+
+// resumeDataCleanupRun polls the agent jobs of an orphaned run until they finish and finalizes the
+// run record. A run without any job left on the agent is marked failed.
+func (h *Hub) resumeDataCleanupRun(systemID string, run dataCleanupOrphanRun, jobs []common.DataCleanupJobStatusDetail) {
+	record, err := h.FindRecordById(dataCleanupRunsCollection, run.ID)
+	if err != nil {
+		h.logDataCleanupError("load cleanup run failed", err, "run", run.ID)
+		return
+	}
+	logs := make([]string, 0, 16)
+	results := make([]dataCleanupRunResult, 0, 4)
+	if err := parseJSONField(record, "logs", &logs); err != nil {
+		h.logDataCleanupError("parse cleanup run logs failed", err, "run", run.ID)
+	}
+	if err := parseJSONField(record, "results", &results); err != nil {
+		h.logDataCleanupError("parse cleanup run results failed", err, "run", run.ID)
+	}
+
+	dryRun := record.GetBool("dry_run")
+
+	if len(jobs) == 0 {
+		err := formatDataCleanupError("cleanup job lost after reconnect", errDataCleanupJobGone, map[string]any{"run": run.ID, "system": systemID})
+		if err := h.failDataCleanupRun(run.ID, logs, results, err); err != nil {
+			h.logDataCleanupError("fail cleanup run failed", err, "run", run.ID)
+			return
+		}
+		h.auditResumedDataCleanupRun(record, "failed")
+		return
+	}
+
+	logs = append(logs, fmt.Sprintf("[%s] agent reconnected, resuming %d cleanup job(s)", time.Now().Format(time.RFC3339), len(jobs)))
+	if err := h.updateDataCleanupRun(run.ID, "running", run.Progress, "resumed", logs, results); err != nil {
+		h.logDataCleanupError("update cleanup run failed", err, "run", run.ID)
+		return
+	}
+
+	for _, detail := range jobs {
+		module := detail.Module
+		final, deleted, err := h.waitDataCleanupJob(context.Background(), systemID, module, detail.JobID)
+		switch {
+		case err != nil:
+			logs = append(logs, fmt.Sprintf("[%s] %s job poll failed: %s", time.Now().Format(time.RFC3339), module, err.Error()))
+			results = append(results, dataCleanupRunResult{Module: module, Status: "failed", Detail: err.Error()})
+		case final.Status == "failed":
+			errMsg := strings.TrimSpace(final.Error)
+			if errMsg == "" {
+				errMsg = module + " cleanup job failed"
+			}
+			results = append(results, dataCleanupRunResult{Module: module, Status: "failed", Detail: errMsg})
+		case dryRun:
+			logs = append(logs, fmt.Sprintf("[%s] %s job completed matched=%d", time.Now().Format(time.RFC3339), module, deleted))
+			results = append(results, dataCleanupDryRunResult(module, deleted))
+		default:
+			logs = append(logs, fmt.Sprintf("[%s] %s job completed deleted=%d", time.Now().Format(time.RFC3339), module, deleted))
+			results = append(results, dataCleanupRunResult{Module: module, Status: "success"})
+		}
+		if err := h.updateDataCleanupRun(run.ID, "running", run.Progress, module, logs, results); err != nil {
+			h.logDataCleanupError("update cleanup run failed", err, "run", run.ID)
+			return
+		}
+	}
+
+	status := "success"
+	for _, result := range results {
+		if result.Status == "failed" {
+			status = "failed"
+			break
+		}
+	}
+	if err := h.updateDataCleanupRun(run.ID, status, 100, "done", logs, results); err != nil {
+		h.logDataCleanupError("finalize cleanup run failed", err, "run", run.ID)
+		return
+	}
+	h.auditResumedDataCleanupRun(record, status)
+}
+
+// auditResumedDataCleanupRun records the outcome of a resumed run under the user who triggered it,
+// or as a system action for runs created before triggered_by was stored. Dry runs are not audited,
+// matching runs that finish in the process that started them.
+func (h *Hub) auditResumedDataCleanupRun(record *core.Record, status string) {
+	if record.GetBool("dry_run") {
+		return
+	}
+	userID := record.GetString("triggered_by")
+	if auditErr := h.recordDockerAudit(dockerAuditEntry{
+		SystemID:     record.GetString("system"),
+		UserID:       userID,
+		Action:       "data_cleanup.run",
+		ResourceType: "data_cleanup",
+		ResourceID:   record.Id,
+		Status:       status,
+		Detail:       fmt.Sprintf("cleanup run %s (resumed)", status),
+		SystemActor:  userID == "",
+	}); auditErr != nil {
+		h.logDataCleanupError("record cleanup audit failed", auditErr, "run", record.Id)
+	}
+}
+
+// waitDataCleanupJob polls a single agent job until it is no longer running, tolerating
+// unreachable periods up to dataCleanupJobPollGrace. Cancelling ctx stops polling only.
+func (h *Hub) waitDataCleanupJob(ctx context.Context, systemID, module, jobID string) (common.DataCleanupJobStatusDetail, int64, error) {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	var unreachableSince time.Time
+	for {
+		detail, deleted, err := h.fetchDataCleanupJobStatus(systemID, module, jobID)
+		if err != nil {
+			if unreachableSince.IsZero() {
+				unreachableSince = time.Now()
+			}
+			if time.Since(unreachableSince) > dataCleanupJobPollGrace {
+				return common.DataCleanupJobStatusDetail{}, 0, err
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return common.DataCleanupJobStatusDetail{}, 0, errDataCleanupRunCancelled
+			}
+			continue
+		}
+		unreachableSince = time.Time{}
+		switch detail.Status {
+		case "running":
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return common.DataCleanupJobStatusDetail{}, 0, errDataCleanupRunCancelled
+			}
+		case "success", "failed":
+			return detail, deleted, nil
+		default:
+			return common.DataCleanupJobStatusDetail{}, 0, formatDataCleanupError(
+				"data cleanup job status invalid",
+				errors.New("unexpected job status"),
+				map[string]any{"jobId": jobID, "module": module, "status": detail.Status},
+			)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumedDataCleanupRunIsAudited(t *testing.T) {
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	user, err := createTestUser(testApp)
+	require.NoError(t, err)
+	system, err := createTestRecord(testApp, "systems", map[string]any{
+		"name":   "cleanup",
+		"host":   "localhost",
+		"port":   "45876",
+		"status": "pending",
+		"users":  []string{user.Id},
+	})
+	require.NoError(t, err)
+	config, err := createTestRecord(testApp, dataCleanupConfigCollection, map[string]any{"system": system.Id})
+	require.NoError(t, err)
+
+	resume := func(triggeredBy string, dryRun bool) *core.Record {
+		run, err := hub.createDataCleanupRun(system.Id, config.Id, "", triggeredBy, dryRun)
+		require.NoError(t, err)
+		assert.Equal(t, triggeredBy, run.GetString("triggered_by"))
+		// the agent no longer has the jobs, so the resumed run fails
+		hub.resumeDataCleanupRun(system.Id, dataCleanupOrphanRun{ID: run.Id}, nil)
+		run, err = testApp.FindRecordById(dataCleanupRunsCollection, run.Id)
+		require.NoError(t, err)
+		assert.Equal(t, "failed", run.GetString("status"))
+		return run
+	}
+	audits := func(runID string) []*core.Record {
+		records, err := testApp.FindAllRecords("docker_audits", dbx.HashExp{"resource_id": runID})
+		require.NoError(t, err)
+		return records
+	}
+
+	run := resume(user.Id, false)
+	records := audits(run.Id)
+	require.Len(t, records, 1)
+	assert.Equal(t, user.Id, records[0].GetString("user"), "the triggering user owns the audit")
+	assert.Equal(t, "data_cleanup.run", records[0].GetString("action"))
+	assert.Equal(t, "failed", records[0].GetString("status"))
+
+	run = resume("", false)
+	records = audits(run.Id)
+	require.Len(t, records, 1)
+	assert.Empty(t, records[0].GetString("user"), "runs without a triggering user are audited as the system")
+
+	run = resume(user.Id, true)
+	assert.Empty(t, audits(run.Id), "dry runs are not audited")
+
+	err = hub.recordDockerAudit(dockerAuditEntry{SystemID: system.Id, Action: "data_cleanup.run", ResourceType: "data_cleanup"})
+	assert.Error(t, err, "only system actions may omit the user")
+}
@@ -0,0 +1,187 @@
+// Package hub 提供按覆盖目标重新执行清理。
+// 以一次已有运行为模板重新执行完整清理，可按模块覆盖清理目标（例如排除上次失败的表）。连接信息与凭据仍取自已保存的
+// 清理配置，覆盖只作用于本次运行，不修改配置本身。新运行通过 source_run 关联原运行，执行流程与完整清理相同。
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// dataCleanupTargetOverrides replaces the stored targets of a module for a single run. A nil list
+// keeps the stored targets; an empty list skips the module.
+type dataCleanupTargetOverrides struct {
+	MySQL []string `json:"mysql"`
+	Redis []string `json:"redis"`
+	Minio []string `json:"minio"`
+	ES    []string `json:"es"`
+}
+
+type dataCleanupRerunPayload struct {
+	RunID     string                     `json:"runId"`
+	Overrides dataCleanupTargetOverrides `json:"overrides"`
+	// Confirm must be true; the re-run deletes data like a full cleanup run
+	Confirm bool `json:"confirm"`
+}
+
+// apply returns the targets of module for the run: the override when set, otherwise stored.
+// It is safe to call on a nil receiver.
+func (o *dataCleanupTargetOverrides) apply(module string, stored []string) []string {
+	if o == nil {
+		return stored
+	}
+	var override []string
+	switch module {
+	case "mysql":
+		override = o.MySQL
+	case "redis":
+		override = o.Redis
+	case "minio":
+		override = o.Minio
+	case "es":
+		override = o.ES
+	}
+	if override == nil {
+		return stored
+	}
+	return normalizeStringSlice(override)
+}
+
+// modules lists the modules whose targets are overridden, for the run log.
+func (o *dataCleanupTargetOverrides) modules() []string {
+	if o == nil {
+		return nil
+	}
+	modules := make([]string, 0, 4)
+	for _, item := range []struct {
+		module  string
+		targets []string
+	}{{"mysql", o.MySQL}, {"redis", o.Redis}, {"minio", o.Minio}, {"es", o.ES}} {
+		if item.targets != nil {
+			modules = append(modules, item.module)
+		}
+	}
+	return modules
+}
+
+// validateDataCleanupOverrides checks that every module with override targets has its connection
+// configured in the stored config, and that the run still has at least one target.
+func validateDataCleanupOverrides(configRecord *core.Record, overrides *dataCleanupTargetOverrides) error {
+	var mysqlStored dataCleanupMySQLStored
+	var redisStored dataCleanupRedisStored
+	var minioStored dataCleanupMinioStored
+	var esStored dataCleanupESStored
+	if err := parseJSONField(configRecord, "mysql", &mysqlStored); err != nil {
+		return fmt.Errorf("parse mysql config failed: %v", err)
+	}
+	if err := parseJSONField(configRecord, "redis", &redisStored); err != nil {
+		return fmt.Errorf("parse redis config failed: %v", err)
+	}
+	if err := parseJSONField(configRecord, "minio", &minioStored); err != nil {
+		return fmt.Errorf("parse minio config failed: %v", err)
+	}
+	if err := parseJSONField(configRecord, "es", &esStored); err != nil {
+		return fmt.Errorf("parse es config failed: %v", err)
+	}
+	redisPatterns := normalizeStringSlice(redisStored.Patterns)
+	if len(redisPatterns) == 0 {
+		redisPatterns = append([]string{}, dataCleanupRedisPatterns...)
+	}
+
+	modules := []struct {
+		name       string
+		stored     []string
+		override   []string
+		configured bool
+		missing    string
+	}{
+		{"mysql", normalizeStringSlice(mysqlStored.Tables), overrides.MySQL, mysqlStored.Host != "" && mysqlStored.Port > 0 && mysqlStored.Database != "", "connection or database"},
+		{"redis", redisPatterns, overrides.Redis, redisStored.Host != "" && redisStored.Port > 0, "connection"},
+		{"minio", normalizeStringSlice(minioStored.Prefixes), overrides.Minio, minioStored.Host != "" && minioStored.Port > 0 && minioStored.Bucket != "", "connection or bucket"},
+		{"es", normalizeStringSlice(esStored.Indices), overrides.ES, esStored.Host != "" && esStored.Port > 0, "connection"},
+	}
+	total := 0
+	for _, module := range modules {
+		targets := overrides.apply(module.name, module.stored)
+		if module.override != nil && len(targets) > 0 && !module.configured {
+			return fmt.Errorf("%s %s is not configured", module.name, module.missing)
+		}
+		if module.configured {
+			total += len(targets)
+		}
+	}
+	if total == 0 {
+		return errors.New("no cleanup target")
+	}
+	return nil
+}
+
+// rerunDataCleanupRun handles POST /api/aether/docker/data-cleanup/rerun.
+func (h *Hub) rerunDataCleanupRun(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload dataCleanupRerunPayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	runID := strings.TrimSpace(payload.RunID)
+	if runID == "" {
+		return respondError(e, http.StatusBadRequest, "runId is required")
+	}
+	if !payload.Confirm {
+		return respondError(e, http.StatusBadRequest, "confirm is required")
+	}
+	sourceRun, err := h.FindRecordById(dataCleanupRunsCollection, runID)
+	if err != nil {
+		return respondError(e, http.StatusNotFound, "run not found")
+	}
+	systemID := sourceRun.GetString("system")
+	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+
+	unlock := lockDataCleanupSystem(systemID)
+	defer unlock()
+	active, err := h.hasActiveDataCleanupRun(systemID)
+	if err != nil {
+		h.logDataCleanupError("check existing cleanup run failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	if active {
+		return respondErrorWithCode(e, http.StatusConflict, errCodeRunInProgress, "cleanup run already in progress")
+	}
+
+	configRecord, err := h.findCleanupConfig(systemID)
+	if err != nil {
+		h.logDataCleanupError("load cleanup config failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	if configRecord == nil {
+		return respondError(e, http.StatusBadRequest, "cleanup config not found")
+	}
+	overrides := payload.Overrides
+	if err := validateDataCleanupOverrides(configRecord, &overrides); err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+
+	userID := e.Auth.Id
+	runRecord, err := h.createDataCleanupRun(systemID, configRecord.Id, sourceRun.Id, userID, false)
+	if err != nil {
+		h.logDataCleanupError("create cleanup run failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+
+	job, ctx := h.jobs.start(runningJobTypeDataCleanup+":"+runRecord.Id, runningJobTypeDataCleanup, runRecord.Id, systemID, true)
+	go h.cleanupQueue.run(func() {
+		defer h.jobs.finish(job)
+		h.executeDataCleanupRun(ctx, job, runRecord.Id, systemID, configRecord.Id, dataCleanupRunOptions{UserID: userID, Confirm: payload.Confirm, Overrides: &overrides})
+	})
+
+	return e.JSON(http.StatusOK, map[string]any{"runId": runRecord.Id, "sourceRun": sourceRun.Id})
+}
@@ -0,0 +1,60 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDataCleanupOverrides(t *testing.T) {
+	_, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	collection, err := testApp.FindCollectionByNameOrId(dataCleanupConfigCollection)
+	require.NoError(t, err)
+
+	const (
+		mysqlConfigured = `{"host":"db.internal","port":3306,"database":"app","tables":["orders"]}`
+		mysqlNoDatabase = `{"host":"db.internal","port":3306,"tables":["orders"]}`
+		redisConfigured = `{"host":"cache.internal","port":6379}`
+		minioNoBucket   = `{"host":"s3.internal","port":9000,"prefixes":["logs/"]}`
+	)
+	tests := []struct {
+		name      string
+		config    map[string]string
+		overrides dataCleanupTargetOverrides
+		err       string
+	}{
+		{name: "nil overrides keep the stored targets", config: map[string]string{"mysql": mysqlConfigured}},
+		{name: "nothing configured", err: "no cleanup target"},
+		{name: "empty override skips the only module", config: map[string]string{"mysql": mysqlConfigured}, overrides: dataCleanupTargetOverrides{MySQL: []string{}}, err: "no cleanup target"},
+		{name: "blank targets count as empty", config: map[string]string{"mysql": mysqlConfigured}, overrides: dataCleanupTargetOverrides{MySQL: []string{" ", ""}}, err: "no cleanup target"},
+		{name: "override replaces the stored targets", config: map[string]string{"mysql": mysqlConfigured}, overrides: dataCleanupTargetOverrides{MySQL: []string{"audit"}}},
+		{name: "redis without stored patterns uses the defaults", config: map[string]string{"redis": redisConfigured}},
+		{name: "empty redis override drops the defaults", config: map[string]string{"redis": redisConfigured}, overrides: dataCleanupTargetOverrides{Redis: []string{}}, err: "no cleanup target"},
+		{name: "override on a module without connection", config: map[string]string{"redis": redisConfigured}, overrides: dataCleanupTargetOverrides{ES: []string{"logs-*"}}, err: "es connection is not configured"},
+		{name: "override on mysql without database", config: map[string]string{"mysql": mysqlNoDatabase, "redis": redisConfigured}, overrides: dataCleanupTargetOverrides{MySQL: []string{"orders"}}, err: "mysql connection or database is not configured"},
+		{name: "override on minio without bucket", config: map[string]string{"minio": minioNoBucket, "redis": redisConfigured}, overrides: dataCleanupTargetOverrides{Minio: []string{"logs/"}}, err: "minio connection or bucket is not configured"},
+		{name: "empty override on an unconfigured module is allowed", config: map[string]string{"redis": redisConfigured}, overrides: dataCleanupTargetOverrides{MySQL: []string{}}},
+		{name: "stored targets of an unconfigured module do not count", config: map[string]string{"mysql": mysqlNoDatabase}, err: "no cleanup target"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			record := core.NewRecord(collection)
+			for field, value := range test.config {
+				record.Set(field, value)
+			}
+			err := validateDataCleanupOverrides(record, &test.overrides)
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,285 @@
+// Package hub 提供按系统配置的数据清理定时执行。
+// 每个系统一条定时配置（间隔、启用状态、可选的每日时间点），调度 tick 每分钟检查到期配置并发起清理；
+// 全局调度暂停期间不发起也不推进下次执行时间。定时任务与手动任务共用系统级锁，同一系统不会同时执行两个清理。
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	dataCleanupSchedulesCollection = "docker_data_cleanup_schedules"
+
+	dataCleanupScheduleDefaultIntervalMinutes = 24 * 60
+	dataCleanupScheduleMinIntervalMinutes     = 5
+	dataCleanupScheduleMaxIntervalMinutes     = 30 * 24 * 60
+	dataCleanupScheduleTimeOfDayLayout        = "15:04"
+)
+
+var errDataCleanupRunInProgress = errors.New("cleanup run already in progress")
+
+type dataCleanupSchedulePayload struct {
+	System          string `json:"system"`
+	Enabled         bool   `json:"enabled"`
+	IntervalMinutes int    `json:"intervalMinutes"`
+	// TimeOfDay is optional; HH:MM in the hub's local time. When set, runs are aligned to it.
+	TimeOfDay string `json:"timeOfDay"`
+}
+
+type dataCleanupScheduleResponse struct {
+	System          string `json:"system"`
+	Enabled         bool   `json:"enabled"`
+	IntervalMinutes int    `json:"intervalMinutes"`
+	TimeOfDay       string `json:"timeOfDay"`
+	LastRunAt       string `json:"lastRunAt,omitempty"`
+	NextRunAt       string `json:"nextRunAt,omitempty"`
+	LastRun         string `json:"lastRun,omitempty"`
+	LastError       string `json:"lastError,omitempty"`
+}
+
+// parseDataCleanupScheduleTimeOfDay validates an HH:MM time of day. An empty value is allowed.
+func parseDataCleanupScheduleTimeOfDay(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	parsed, err := time.Parse(dataCleanupScheduleTimeOfDayLayout, value)
+	if err != nil {
+		return "", errors.New("timeOfDay must be HH:MM")
+	}
+	return parsed.Format(dataCleanupScheduleTimeOfDayLayout), nil
+}
+
+// nextDataCleanupScheduleRun returns the first run time strictly after now. Without a time of day
+// the next run is one interval from now; with one, runs fall on that time today plus whole intervals.
+func nextDataCleanupScheduleRun(now time.Time, intervalMinutes int, timeOfDay string) time.Time {
+	if intervalMinutes <= 0 {
+		intervalMinutes = dataCleanupScheduleDefaultIntervalMinutes
+	}
+	interval := time.Duration(intervalMinutes) * time.Minute
+	clock, err := time.Parse(dataCleanupScheduleTimeOfDayLayout, timeOfDay)
+	if timeOfDay == "" || err != nil {
+		return now.Add(interval)
+	}
+	anchor := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	elapsed := now.Sub(anchor)
+	steps := elapsed / interval
+	if elapsed < 0 && elapsed%interval != 0 {
+		steps--
+	}
+	return anchor.Add((steps + 1) * interval)
+}
+
+func (h *Hub) findDataCleanupSchedule(systemID string) (*core.Record, error) {
+	records, err := h.FindRecordsByFilter(
+		dataCleanupSchedulesCollection,
+		"system = {:system}",
+		"",
+		1,
+		0,
+		dbx.Params{"system": systemID},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return records[0], nil
+}
+
+func buildDataCleanupScheduleResponse(systemID string, record *core.Record) dataCleanupScheduleResponse {
+	if record == nil {
+		return dataCleanupScheduleResponse{System: systemID, IntervalMinutes: dataCleanupScheduleDefaultIntervalMinutes}
+	}
+	return dataCleanupScheduleResponse{
+		System:          systemID,
+		Enabled:         record.GetBool("enabled"),
+		IntervalMinutes: record.GetInt("interval_minutes"),
+		TimeOfDay:       record.GetString("time_of_day"),
+		LastRunAt:       formatHealthTime(record.GetDateTime("last_run_at").Time()),
+		NextRunAt:       formatHealthTime(record.GetDateTime("next_run_at").Time()),
+		LastRun:         record.GetString("last_run"),
+		LastError:       record.GetString("last_error"),
+	}
+}
+
+// getDataCleanupSchedule handles GET /api/aether/docker/data-cleanup/schedule requests.
+func (h *Hub) getDataCleanupSchedule(e *core.RequestEvent) error {
+	systemID := strings.TrimSpace(e.Request.URL.Query().Get("system"))
+	if systemID == "" {
+		return respondError(e, http.StatusBadRequest, "system is required")
+	}
+	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	record, err := h.findDataCleanupSchedule(systemID)
+	if err != nil {
+		h.logDataCleanupError("load cleanup schedule failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	return e.JSON(http.StatusOK, buildDataCleanupScheduleResponse(systemID, record))
+}
+
+// upsertDataCleanupSchedule handles POST /api/aether/docker/data-cleanup/schedule requests.
+// Saving recomputes the next run time; scheduled runs are audited as the user who saved the schedule.
+func (h *Hub) upsertDataCleanupSchedule(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload dataCleanupSchedulePayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	systemID := strings.TrimSpace(payload.System)
+	if systemID == "" {
+		return respondError(e, http.StatusBadRequest, "system is required")
+	}
+	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	intervalMinutes := payload.IntervalMinutes
+	if intervalMinutes == 0 {
+		intervalMinutes = dataCleanupScheduleDefaultIntervalMinutes
+	}
+	if intervalMinutes < dataCleanupScheduleMinIntervalMinutes || intervalMinutes > dataCleanupScheduleMaxIntervalMinutes {
+		return respondError(e, http.StatusBadRequest, fmt.Sprintf("intervalMinutes must be between %d and %d", dataCleanupScheduleMinIntervalMinutes, dataCleanupScheduleMaxIntervalMinutes))
+	}
+	timeOfDay, err := parseDataCleanupScheduleTimeOfDay(payload.TimeOfDay)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	if payload.Enabled {
+		configRecord, err := h.findCleanupConfig(systemID)
+		if err != nil {
+			h.logDataCleanupError("load cleanup config failed", err, "system", systemID)
+			return respondError(e, http.StatusInternalServerError, err.Error())
+		}
+		if configRecord == nil {
+			return respondError(e, http.StatusBadRequest, "cleanup config not found")
+		}
+	}
+
+	record, err := h.findDataCleanupSchedule(systemID)
+	if err != nil {
+		h.logDataCleanupError("load cleanup schedule failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	if record == nil {
+		collection, err := h.FindCollectionByNameOrId(dataCleanupSchedulesCollection)
+		if err != nil {
+			h.logDataCleanupError("load cleanup schedule collection failed", err)
+			return respondError(e, http.StatusInternalServerError, err.Error())
+		}
+		record = core.NewRecord(collection)
+		record.Set("system", systemID)
+	}
+	record.Set("enabled", payload.Enabled)
+	record.Set("interval_minutes", intervalMinutes)
+	record.Set("time_of_day", timeOfDay)
+	if payload.Enabled {
+		record.Set("next_run_at", nextDataCleanupScheduleRun(time.Now(), intervalMinutes, timeOfDay))
+	} else {
+		record.Set("next_run_at", nil)
+	}
+	record.Set("updated_by", e.Auth.Id)
+	if err := h.Save(record); err != nil {
+		h.logDataCleanupError("save cleanup schedule failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	return e.JSON(http.StatusOK, buildDataCleanupScheduleResponse(systemID, record))
+}
+
+// runDataCleanupScheduleTick starts a cleanup run for every enabled schedule that is due.
+func (h *Hub) runDataCleanupScheduleTick() {
+	// 启动完成前不执行，也不推进 next_run_at
+	if !h.ready.Load() {
+		return
+	}
+	paused, err := h.dataCleanupSchedulerPaused()
+	if err != nil {
+		h.logDataCleanupError("load cleanup scheduler state failed", err)
+		return
+	}
+	if paused {
+		return
+	}
+	schedules, err := h.FindRecordsByFilter(dataCleanupSchedulesCollection, "enabled = true", "", -1, 0, nil)
+	if err != nil {
+		h.logDataCleanupError("load cleanup schedules failed", err)
+		return
+	}
+	now := time.Now()
+	for _, schedule := range schedules {
+		systemID := schedule.GetString("system")
+		intervalMinutes := schedule.GetInt("interval_minutes")
+		timeOfDay := schedule.GetString("time_of_day")
+		nextRun := schedule.GetDateTime("next_run_at")
+		if nextRun.IsZero() {
+			schedule.Set("next_run_at", nextDataCleanupScheduleRun(now, intervalMinutes, timeOfDay))
+			if err := h.Save(schedule); err != nil {
+				h.logDataCleanupError("initialize cleanup schedule failed", err, "system", systemID)
+			}
+			continue
+		}
+		if nextRun.Time().After(now) {
+			continue
+		}
+		runID, runErr := h.startScheduledDataCleanupRun(schedule)
+		if runErr != nil {
+			h.logDataCleanupError("scheduled cleanup run not started", runErr, "system", systemID)
+			schedule.Set("last_error", runErr.Error())
+		} else {
+			schedule.Set("last_error", "")
+			schedule.Set("last_run", runID)
+		}
+		schedule.Set("last_run_at", now)
+		schedule.Set("next_run_at", nextDataCleanupScheduleRun(now, intervalMinutes, timeOfDay))
+		if err := h.Save(schedule); err != nil {
+			h.logDataCleanupError("save cleanup schedule failed", err, "system", systemID)
+		}
+	}
+}
+
+// startScheduledDataCleanupRun queues a cleanup run for the schedule's system and returns its id.
+// A run already in progress for the system makes this occurrence skip rather than wait.
+func (h *Hub) startScheduledDataCleanupRun(schedule *core.Record) (string, error) {
+	systemID := schedule.GetString("system")
+	unlock := lockDataCleanupSystem(systemID)
+	defer unlock()
+	active, err := h.hasActiveDataCleanupRun(systemID)
+	if err != nil {
+		return "", err
+	}
+	if active {
+		return "", errDataCleanupRunInProgress
+	}
+	configRecord, err := h.findCleanupConfig(systemID)
+	if err != nil {
+		return "", err
+	}
+	if configRecord == nil {
+		return "", errors.New("cleanup config not found")
+	}
+	// enabling the schedule is the user's confirmation for the runs it starts
+	opts := dataCleanupRunOptions{UserID: schedule.GetString("updated_by"), Confirm: true, Source: dataCleanupRunSourceSchedule}
+	runRecord, err := h.createDataCleanupRun(systemID, configRecord.Id, "", opts.UserID, false)
+	if err != nil {
+		return "", err
+	}
+
+	job, ctx := h.jobs.start(runningJobTypeDataCleanup+":"+runRecord.Id, runningJobTypeDataCleanup, runRecord.Id, systemID, true)
+	go h.cleanupQueue.run(func() {
+		defer h.jobs.finish(job)
+		h.executeDataCleanupRun(ctx, job, runRecord.Id, systemID, configRecord.Id, opts)
+	})
+	return runRecord.Id, nil
+}
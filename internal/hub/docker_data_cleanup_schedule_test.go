@@ -0,0 +1,41 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextDataCleanupScheduleRun(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 20, 0, 0, time.UTC)
+
+	assert.Equal(t, now.Add(90*time.Minute), nextDataCleanupScheduleRun(now, 90, ""))
+	assert.Equal(t, now.Add(24*time.Hour), nextDataCleanupScheduleRun(now, 0, ""))
+
+	// daily at 03:00: already passed today, so tomorrow
+	assert.Equal(t, time.Date(2026, 3, 11, 3, 0, 0, 0, time.UTC), nextDataCleanupScheduleRun(now, 24*60, "03:00"))
+	// daily at 18:30: still ahead today
+	assert.Equal(t, time.Date(2026, 3, 10, 18, 30, 0, 0, time.UTC), nextDataCleanupScheduleRun(now, 24*60, "18:30"))
+	// every 6 hours aligned to 02:00 -> 02:00, 08:00, 14:00, 20:00
+	assert.Equal(t, time.Date(2026, 3, 10, 20, 0, 0, 0, time.UTC), nextDataCleanupScheduleRun(now, 6*60, "02:00"))
+	// every 6 hours aligned to 23:00 -> 05:00, 11:00, 17:00 today
+	assert.Equal(t, time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC), nextDataCleanupScheduleRun(now, 6*60, "23:00"))
+	// exactly on the slot moves to the following one
+	assert.Equal(t, time.Date(2026, 3, 11, 14, 20, 0, 0, time.UTC), nextDataCleanupScheduleRun(now, 24*60, "14:20"))
+
+	timeOfDay, err := parseDataCleanupScheduleTimeOfDay(" 3:05 ")
+	require.NoError(t, err)
+	assert.Equal(t, "03:05", timeOfDay)
+	timeOfDay, err = parseDataCleanupScheduleTimeOfDay("")
+	require.NoError(t, err)
+	assert.Empty(t, timeOfDay)
+	for _, value := range []string{"24:00", "12:60", "noon"} {
+		_, err := parseDataCleanupScheduleTimeOfDay(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,184 @@
+// Package hub 提供数据清理定时调度的全局暂停开关。
+// 暂停状态持久化在单条记录中，调度 tick 在启动任何定时清理前检查；暂停期间不推进下次执行时间。
+// 设置了自动恢复时间时，到期后首次检查即自动恢复并记录日志。
+package hub
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const dataCleanupSchedulerCollection = "docker_data_cleanup_scheduler"
+
+type dataCleanupSchedulerPausePayload struct {
+	// ResumeAt is optional; RFC3339. When set, the scheduler resumes automatically at that time.
+	ResumeAt string `json:"resumeAt"`
+	Reason   string `json:"reason"`
+}
+
+type dataCleanupSchedulerState struct {
+	Paused   bool   `json:"paused"`
+	PausedAt string `json:"pausedAt,omitempty"`
+	ResumeAt string `json:"resumeAt,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	PausedBy string `json:"pausedBy,omitempty"`
+}
+
+// findDataCleanupSchedulerRecord returns the scheduler state record, or nil if none was saved yet.
+func (h *Hub) findDataCleanupSchedulerRecord() (*core.Record, error) {
+	record, err := h.FindFirstRecordByFilter(dataCleanupSchedulerCollection, "", dbx.Params{})
+	if err == nil {
+		return record, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// getOrCreateDataCleanupSchedulerRecord returns the scheduler state record, creating it if needed.
+// Only the write endpoints call it; reads fall back to the default state instead.
+func (h *Hub) getOrCreateDataCleanupSchedulerRecord() (*core.Record, error) {
+	record, err := h.findDataCleanupSchedulerRecord()
+	if err != nil || record != nil {
+		return record, err
+	}
+	collection, err := h.FindCollectionByNameOrId(dataCleanupSchedulerCollection)
+	if err != nil {
+		return nil, err
+	}
+	record = core.NewRecord(collection)
+	record.Set("paused", false)
+	if err := h.Save(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// dataCleanupSchedulerState returns the global pause state, resuming first if the
+// auto-resume time has passed. Without a saved record the scheduler is running.
+func (h *Hub) dataCleanupSchedulerState() (dataCleanupSchedulerState, error) {
+	record, err := h.findDataCleanupSchedulerRecord()
+	if err != nil || record == nil {
+		return dataCleanupSchedulerState{}, err
+	}
+	if record.GetBool("paused") {
+		resumeAt := record.GetDateTime("resume_at")
+		if !resumeAt.IsZero() && !resumeAt.Time().After(time.Now()) {
+			clearDataCleanupSchedulerPause(record)
+			if err := h.Save(record); err != nil {
+				return dataCleanupSchedulerState{}, err
+			}
+			h.Logger().Info("data cleanup scheduler auto-resumed", "logger", "hub", "resumeAt", resumeAt.String())
+		}
+	}
+	return buildDataCleanupSchedulerState(record), nil
+}
+
+// dataCleanupSchedulerPaused reports whether scheduled cleanup runs must be skipped.
+// The scheduler tick calls it before starting any run and leaves next-run times untouched while paused.
+func (h *Hub) dataCleanupSchedulerPaused() (bool, error) {
+	state, err := h.dataCleanupSchedulerState()
+	if err != nil {
+		return false, err
+	}
+	return state.Paused, nil
+}
+
+func clearDataCleanupSchedulerPause(record *core.Record) {
+	record.Set("paused", false)
+	record.Set("paused_at", nil)
+	record.Set("resume_at", nil)
+	record.Set("reason", "")
+	record.Set("paused_by", "")
+}
+
+func buildDataCleanupSchedulerState(record *core.Record) dataCleanupSchedulerState {
+	state := dataCleanupSchedulerState{Paused: record.GetBool("paused")}
+	if !state.Paused {
+		return state
+	}
+	state.PausedAt = formatHealthTime(record.GetDateTime("paused_at").Time())
+	state.ResumeAt = formatHealthTime(record.GetDateTime("resume_at").Time())
+	state.Reason = record.GetString("reason")
+	state.PausedBy = record.GetString("paused_by")
+	return state
+}
+
+// pauseDataCleanupScheduler handles POST /api/aether/docker/data-cleanup/scheduler/pause requests.
+// Manual runs are not affected.
+func (h *Hub) pauseDataCleanupScheduler(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload dataCleanupSchedulerPausePayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	var resumeAt time.Time
+	if value := strings.TrimSpace(payload.ResumeAt); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return respondError(e, http.StatusBadRequest, "resumeAt must be an RFC3339 time")
+		}
+		if !parsed.After(time.Now()) {
+			return respondError(e, http.StatusBadRequest, "resumeAt must be in the future")
+		}
+		resumeAt = parsed
+	}
+
+	record, err := h.getOrCreateDataCleanupSchedulerRecord()
+	if err != nil {
+		h.logDataCleanupError("load cleanup scheduler state failed", err)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	record.Set("paused", true)
+	record.Set("paused_at", time.Now())
+	if resumeAt.IsZero() {
+		record.Set("resume_at", nil)
+	} else {
+		record.Set("resume_at", resumeAt)
+	}
+	record.Set("reason", strings.TrimSpace(payload.Reason))
+	record.Set("paused_by", e.Auth.Id)
+	if err := h.Save(record); err != nil {
+		h.logDataCleanupError("pause cleanup scheduler failed", err)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	return e.JSON(http.StatusOK, buildDataCleanupSchedulerState(record))
+}
+
+// resumeDataCleanupScheduler handles POST /api/aether/docker/data-cleanup/scheduler/resume requests.
+func (h *Hub) resumeDataCleanupScheduler(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	record, err := h.getOrCreateDataCleanupSchedulerRecord()
+	if err != nil {
+		h.logDataCleanupError("load cleanup scheduler state failed", err)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	clearDataCleanupSchedulerPause(record)
+	if err := h.Save(record); err != nil {
+		h.logDataCleanupError("resume cleanup scheduler failed", err)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	return e.JSON(http.StatusOK, buildDataCleanupSchedulerState(record))
+}
+
+// getDataCleanupScheduler handles GET /api/aether/docker/data-cleanup/scheduler requests.
+func (h *Hub) getDataCleanupScheduler(e *core.RequestEvent) error {
+	state, err := h.dataCleanupSchedulerState()
+	if err != nil {
+		h.logDataCleanupError("load cleanup scheduler state failed", err)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	return e.JSON(http.StatusOK, state)
+}
@@ -0,0 +1,49 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDataCleanupSchedulerDoesNotCreateRecord(t *testing.T) {
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	user, err := createTestUser(testApp)
+	require.NoError(t, err)
+
+	call := func(handler func(*core.RequestEvent) error, method, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		e := &core.RequestEvent{App: testApp, Auth: user}
+		e.Request = httptest.NewRequest(method, "/api/aether/docker/data-cleanup/scheduler", strings.NewReader(body))
+		e.Response = recorder
+		require.NoError(t, handler(e))
+		return recorder
+	}
+
+	recorder := call(hub.getDataCleanupScheduler, http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"paused":false}`, recorder.Body.String())
+	count, err := testApp.CountRecords(dataCleanupSchedulerCollection)
+	require.NoError(t, err)
+	assert.Zero(t, count, "reading the default state must not save a record")
+
+	recorder = call(hub.pauseDataCleanupScheduler, http.MethodPost, `{"reason":"maintenance"}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	count, err = testApp.CountRecords(dataCleanupSchedulerCollection)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	recorder = call(hub.getDataCleanupScheduler, http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"reason":"maintenance"`)
+}
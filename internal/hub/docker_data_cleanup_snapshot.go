@@ -0,0 +1,274 @@
+// Package hub 提供数据清理配置的快照导出与导入，用于迁移与灾备。
+// 导出包含当前用户可访问系统的全部清理配置（连接信息、清理目标与超时），按系统输出。
+// 密钥处理：mysql/redis/es 密码与 minio secretKey 永不导出，快照中仅以 secrets 标记源配置是否保存过该密钥。
+// 导入时按系统 ID 匹配（找不到时按唯一的系统名称匹配），已有配置的系统保留原有密钥；新建配置的系统不带任何密钥，
+// 源配置保存过密钥的模块在响应的 secretsRequired 中列出，需要在清理配置页重新填写后才能执行清理。
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const dataCleanupSnapshotVersion = 1
+
+// dataCleanupSnapshotSecrets marks which secrets the source config had stored; values are never exported.
+type dataCleanupSnapshotSecrets struct {
+	MySQLPassword  bool `json:"mysqlPassword,omitempty"`
+	RedisPassword  bool `json:"redisPassword,omitempty"`
+	MinioSecretKey bool `json:"minioSecretKey,omitempty"`
+	ESPassword     bool `json:"esPassword,omitempty"`
+}
+
+type dataCleanupSnapshotConfig struct {
+	System     string                     `json:"system"`
+	SystemName string                     `json:"systemName,omitempty"`
+	MySQL      dataCleanupMySQLStored     `json:"mysql"`
+	Redis      dataCleanupRedisStored     `json:"redis"`
+	Minio      dataCleanupMinioStored     `json:"minio"`
+	ES         dataCleanupESStored        `json:"es"`
+	Secrets    dataCleanupSnapshotSecrets `json:"secrets"`
+}
+
+type dataCleanupSnapshot struct {
+	Version int                         `json:"version"`
+	Configs []dataCleanupSnapshotConfig `json:"configs"`
+}
+
+type dataCleanupSnapshotImportRequest struct {
+	Mode string              `json:"mode"`
+	Data dataCleanupSnapshot `json:"data"`
+}
+
+// dataCleanupSecretsRequired lists the modules of an imported system whose secrets must be re-entered.
+type dataCleanupSecretsRequired struct {
+	System  string   `json:"system"`
+	Modules []string `json:"modules"`
+}
+
+type dataCleanupSnapshotImportResponse struct {
+	Created         int                          `json:"created"`
+	Updated         int                          `json:"updated"`
+	Skipped         int                          `json:"skipped"`
+	SecretsRequired []dataCleanupSecretsRequired `json:"secretsRequired"`
+}
+
+// exportDataCleanupConfigs handles GET /api/aether/docker/data-cleanup/export.
+func (h *Hub) exportDataCleanupConfigs(e *core.RequestEvent) error {
+	systemRecords, err := h.listAccessibleSystemRecords(e)
+	if err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	snapshot := dataCleanupSnapshot{Version: dataCleanupSnapshotVersion, Configs: make([]dataCleanupSnapshotConfig, 0, len(systemRecords))}
+	for _, systemRecord := range systemRecords {
+		record, err := h.findCleanupConfig(systemRecord.Id)
+		if err != nil {
+			h.logDataCleanupError("load cleanup config failed", err, "system", systemRecord.Id)
+			return respondError(e, http.StatusInternalServerError, err.Error())
+		}
+		if record == nil {
+			continue
+		}
+		config := dataCleanupSnapshotConfig{
+			System:     systemRecord.Id,
+			SystemName: systemRecord.GetString("name"),
+			Secrets: dataCleanupSnapshotSecrets{
+				MySQLPassword:  record.GetString("mysql_password") != "",
+				RedisPassword:  record.GetString("redis_password") != "",
+				MinioSecretKey: record.GetString("minio_secret_key") != "",
+				ESPassword:     record.GetString("es_password") != "",
+			},
+		}
+		if err := parseDataCleanupSnapshotModules(record, &config); err != nil {
+			h.logDataCleanupError("parse cleanup config failed", err, "system", systemRecord.Id)
+			return respondError(e, http.StatusInternalServerError, err.Error())
+		}
+		snapshot.Configs = append(snapshot.Configs, config)
+	}
+	return e.JSON(http.StatusOK, snapshot)
+}
+
+func parseDataCleanupSnapshotModules(record *core.Record, config *dataCleanupSnapshotConfig) error {
+	if err := parseJSONField(record, "mysql", &config.MySQL); err != nil {
+		return fmt.Errorf("parse mysql config failed: %w", err)
+	}
+	if err := parseJSONField(record, "redis", &config.Redis); err != nil {
+		return fmt.Errorf("parse redis config failed: %w", err)
+	}
+	if err := parseJSONField(record, "minio", &config.Minio); err != nil {
+		return fmt.Errorf("parse minio config failed: %w", err)
+	}
+	if err := parseJSONField(record, "es", &config.ES); err != nil {
+		return fmt.Errorf("parse es config failed: %w", err)
+	}
+	return nil
+}
+
+// validateDataCleanupSnapshot checks the snapshot structure and returns the configs validated and
+// normalized by normalizeDataCleanupStored, the same as upsertDockerDataCleanupConfig stores them.
+func validateDataCleanupSnapshot(snapshot dataCleanupSnapshot) ([]dataCleanupSnapshotConfig, error) {
+	if snapshot.Version != dataCleanupSnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snapshot.Version)
+	}
+	configs := make([]dataCleanupSnapshotConfig, 0, len(snapshot.Configs))
+	for index, config := range snapshot.Configs {
+		config.System = strings.TrimSpace(config.System)
+		config.SystemName = strings.TrimSpace(config.SystemName)
+		if config.System == "" && config.SystemName == "" {
+			return nil, fmt.Errorf("configs[%d]: system or systemName is required", index)
+		}
+		if err := normalizeDataCleanupStored(&config.MySQL, &config.Redis, &config.Minio, &config.ES); err != nil {
+			return nil, fmt.Errorf("configs[%d]: %v", index, err)
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// resolveDataCleanupSnapshotSystem matches a snapshot entry to an accessible system, by id first and
+// then by a unique system name.
+func resolveDataCleanupSnapshotSystem(config dataCleanupSnapshotConfig, systemRecords []*core.Record) (*core.Record, error) {
+	if config.System != "" {
+		for _, record := range systemRecords {
+			if record.Id == config.System {
+				return record, nil
+			}
+		}
+	}
+	var match *core.Record
+	if config.SystemName != "" {
+		for _, record := range systemRecords {
+			if record.GetString("name") != config.SystemName {
+				continue
+			}
+			if match != nil {
+				return nil, fmt.Errorf("system name %q is ambiguous", config.SystemName)
+			}
+			match = record
+		}
+	}
+	if match == nil {
+		return nil, errSystemNotFound
+	}
+	return match, nil
+}
+
+// importDataCleanupConfigs handles POST /api/aether/docker/data-cleanup/import.
+// mode=skip leaves systems that already have a config untouched, mode=overwrite updates them.
+func (h *Hub) importDataCleanupConfigs(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload dataCleanupSnapshotImportRequest
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	mode := strings.TrimSpace(payload.Mode)
+	if mode != "skip" && mode != "overwrite" {
+		return respondError(e, http.StatusBadRequest, "mode must be skip or overwrite")
+	}
+	configs, err := validateDataCleanupSnapshot(payload.Data)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	systemRecords, err := h.listAccessibleSystemRecords(e)
+	if err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	targets := make([]*core.Record, len(configs))
+	resolved := make(map[string]int, len(configs))
+	for index, config := range configs {
+		systemRecord, err := resolveDataCleanupSnapshotSystem(config, systemRecords)
+		if err != nil {
+			return respondError(e, http.StatusBadRequest, fmt.Sprintf("configs[%d]: %v", index, err))
+		}
+		if previous, ok := resolved[systemRecord.Id]; ok {
+			return respondError(e, http.StatusBadRequest, fmt.Sprintf("configs[%d]: same system as configs[%d]", index, previous))
+		}
+		resolved[systemRecord.Id] = index
+		targets[index] = systemRecord
+	}
+	collection, err := h.FindCollectionByNameOrId(dataCleanupConfigCollection)
+	if err != nil {
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+
+	response := dataCleanupSnapshotImportResponse{SecretsRequired: []dataCleanupSecretsRequired{}}
+	for index, config := range configs {
+		systemID := targets[index].Id
+		record, err := h.findCleanupConfig(systemID)
+		if err != nil {
+			h.logDataCleanupError("load cleanup config failed", err, "system", systemID)
+			return respondError(e, http.StatusInternalServerError, err.Error())
+		}
+		isCreate := record == nil
+		if !isCreate && mode == "skip" {
+			response.Skipped++
+			continue
+		}
+		if isCreate {
+			record = core.NewRecord(collection)
+			record.Set("system", systemID)
+		}
+		for field, stored := range map[string]any{
+			"mysql": config.MySQL,
+			"redis": config.Redis,
+			"minio": config.Minio,
+			"es":    config.ES,
+		} {
+			raw, err := toJSONRaw(stored)
+			if err != nil {
+				h.logDataCleanupError("encode "+field+" config failed", err, "system", systemID)
+				return respondError(e, http.StatusInternalServerError, err.Error())
+			}
+			record.Set(field, raw)
+		}
+		// secrets are never part of a snapshot: updates keep the stored ones, new configs start without
+		if err := h.Save(record); err != nil {
+			h.logDataCleanupError("save cleanup config failed", err, "system", systemID, "create", isCreate)
+			return respondError(e, http.StatusInternalServerError, err.Error())
+		}
+		if !isCreate {
+			response.Updated++
+			continue
+		}
+		response.Created++
+		if modules := config.Secrets.modules(); len(modules) > 0 {
+			response.SecretsRequired = append(response.SecretsRequired, dataCleanupSecretsRequired{System: systemID, Modules: modules})
+		}
+	}
+
+	if auditErr := h.recordDockerAudit(dockerAuditEntry{
+		UserID:       e.Auth.Id,
+		Action:       "data_cleanup.import",
+		ResourceType: "data_cleanup",
+		Status:       dockerAuditStatusSuccess,
+		Detail:       fmt.Sprintf("imported cleanup configs created=%d updated=%d skipped=%d", response.Created, response.Updated, response.Skipped),
+	}); auditErr != nil {
+		h.logDataCleanupError("record cleanup audit failed", auditErr)
+	}
+	return e.JSON(http.StatusOK, response)
+}
+
+// modules returns the modules whose secret the source config had stored.
+func (s dataCleanupSnapshotSecrets) modules() []string {
+	modules := make([]string, 0, 4)
+	for _, item := range []struct {
+		module string
+		stored bool
+	}{
+		{"mysql", s.MySQLPassword},
+		{"redis", s.RedisPassword},
+		{"minio", s.MinioSecretKey},
+		{"es", s.ESPassword},
+	} {
+		if item.stored {
+			modules = append(modules, item.module)
+		}
+	}
+	return modules
+}
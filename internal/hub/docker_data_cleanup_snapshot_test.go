@@ -0,0 +1,66 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"testing"
+
+	"aether/internal/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDataCleanupSnapshot(t *testing.T) {
+	valid := dataCleanupSnapshotConfig{
+		System: " sys1 ",
+		MySQL: dataCleanupMySQLStored{
+			Host:       " db ",
+			Port:       3306,
+			Tables:     []string{"events", " events "},
+			Conditions: []dataCleanupMySQLCondition{{Table: " events ", Where: "created_at < ?", Args: []string{"2024-01-01"}}},
+		},
+		ES: dataCleanupESStored{Mode: " DROP_INDEX "},
+	}
+	configs, err := validateDataCleanupSnapshot(dataCleanupSnapshot{Version: dataCleanupSnapshotVersion, Configs: []dataCleanupSnapshotConfig{valid}})
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.Equal(t, "sys1", configs[0].System)
+	assert.Equal(t, "db", configs[0].MySQL.Host)
+	assert.Equal(t, []string{"events"}, configs[0].MySQL.Tables)
+	assert.Equal(t, common.DataCleanupMySQLModeDelete, configs[0].MySQL.Mode)
+	assert.Equal(t, "events", configs[0].MySQL.Conditions[0].Table)
+	assert.Equal(t, dataCleanupRedisPatterns, configs[0].Redis.Patterns)
+	assert.Equal(t, common.DataCleanupESModeDropIndex, configs[0].ES.Mode)
+
+	invalid := map[string]func(config *dataCleanupSnapshotConfig){
+		"invalid mysql cleanup mode": func(config *dataCleanupSnapshotConfig) {
+			config.MySQL.Mode = "drop"
+		},
+		"not a cleanup table": func(config *dataCleanupSnapshotConfig) {
+			config.MySQL.Conditions = []dataCleanupMySQLCondition{{Table: "users", Where: "id > 0"}}
+		},
+		"require delete mode": func(config *dataCleanupSnapshotConfig) {
+			config.MySQL.Mode = common.DataCleanupMySQLModeTruncate
+		},
+		"invalid es cleanup mode": func(config *dataCleanupSnapshotConfig) {
+			config.ES.Mode = "truncate"
+		},
+		"invalid redis key type": func(config *dataCleanupSnapshotConfig) {
+			config.Redis.Filter = common.DataCleanupRedisFilter{Type: "stream-ish"}
+		},
+		"minio port": func(config *dataCleanupSnapshotConfig) {
+			config.Minio.Port = 70000
+		},
+	}
+	for expected, mutate := range invalid {
+		config := valid
+		config.MySQL.Conditions = append([]dataCleanupMySQLCondition{}, valid.MySQL.Conditions...)
+		mutate(&config)
+		_, err := validateDataCleanupSnapshot(dataCleanupSnapshot{Version: dataCleanupSnapshotVersion, Configs: []dataCleanupSnapshotConfig{config}})
+		require.Error(t, err, expected)
+		assert.Contains(t, err.Error(), "configs[0]", expected)
+		assert.Contains(t, err.Error(), expected)
+	}
+}
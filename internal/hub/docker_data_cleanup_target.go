@@ -0,0 +1,304 @@
+// Package hub 提供单个清理目标的一次性清理。
+// 请求显式指定模块与单个目标（MySQL 表、Redis pattern、MinIO prefix 或 ES 索引），连接信息与凭据取自已保存的清理配置，
+// 不修改配置本身。执行与完整清理共用运行记录、并发槽位、运行中任务登记与 agent 侧确认要求。
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"aether/internal/common"
+	"aether/internal/hub/systems"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// dataCleanupTargetPayload describes a one-off cleanup of a single target. Database, DB and Bucket
+// override the stored module config when set.
+type dataCleanupTargetPayload struct {
+	System   string `json:"system"`
+	Module   string `json:"module"`
+	Target   string `json:"target"`
+	Database string `json:"database"`
+	DB       *int   `json:"db"`
+	Bucket   string `json:"bucket"`
+	// Confirm must be true; the request deletes data without a preview step.
+	Confirm bool `json:"confirm"`
+}
+
+// dataCleanupTargetStart starts the agent job for the target with the given job id.
+type dataCleanupTargetStart func(system *systems.System, jobID string) error
+
+// startDataCleanupTargetRun handles POST /api/aether/docker/data-cleanup/target.
+func (h *Hub) startDataCleanupTargetRun(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload dataCleanupTargetPayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	systemID := strings.TrimSpace(payload.System)
+	payload.Module = strings.ToLower(strings.TrimSpace(payload.Module))
+	payload.Target = strings.TrimSpace(payload.Target)
+	if systemID == "" || payload.Module == "" || payload.Target == "" {
+		return respondError(e, http.StatusBadRequest, "system, module and target are required")
+	}
+	if !payload.Confirm {
+		return respondError(e, http.StatusBadRequest, "confirm is required")
+	}
+	if _, err := h.resolveSystemRecordForUser(e, systemID); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+
+	unlock := lockDataCleanupSystem(systemID)
+	defer unlock()
+	active, err := h.hasActiveDataCleanupRun(systemID)
+	if err != nil {
+		h.logDataCleanupError("check existing cleanup run failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	if active {
+		return respondErrorWithCode(e, http.StatusConflict, errCodeRunInProgress, "cleanup run already in progress")
+	}
+
+	configRecord, err := h.findCleanupConfig(systemID)
+	if err != nil {
+		h.logDataCleanupError("load cleanup config failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	if configRecord == nil {
+		return respondError(e, http.StatusBadRequest, "cleanup config not found")
+	}
+	start, err := h.buildDataCleanupTargetStart(configRecord, payload)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+
+	userID := e.Auth.Id
+	runRecord, err := h.createDataCleanupRun(systemID, configRecord.Id, "", userID, false)
+	if err != nil {
+		h.logDataCleanupError("create cleanup run failed", err, "system", systemID)
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+
+	job, ctx := h.jobs.start(runningJobTypeDataCleanup+":"+runRecord.Id, runningJobTypeDataCleanup, runRecord.Id, systemID, true)
+	go h.cleanupQueue.run(func() {
+		defer h.jobs.finish(job)
+		h.executeDataCleanupTargetRun(ctx, runRecord.Id, systemID, userID, payload.Module, payload.Target, start)
+	})
+
+	return e.JSON(http.StatusOK, map[string]any{"runId": runRecord.Id})
+}
+
+// buildDataCleanupTargetStart returns the agent call that starts the cleanup of the single target.
+func (h *Hub) buildDataCleanupTargetStart(configRecord *core.Record, payload dataCleanupTargetPayload) (dataCleanupTargetStart, error) {
+	request, err := h.buildDataCleanupTargetRequest(configRecord, payload)
+	if err != nil {
+		return nil, err
+	}
+	return func(system *systems.System, jobID string) error {
+		var err error
+		switch req := request.(type) {
+		case common.DataCleanupMySQLDeleteTablesRequest:
+			req.JobID = jobID
+			_, err = system.CleanupMySQLTablesFromAgent(req)
+		case common.DataCleanupRedisCleanupRequest:
+			req.JobID = jobID
+			_, err = system.CleanupRedisFromAgent(req)
+		case common.DataCleanupMinioCleanupRequest:
+			req.JobID = jobID
+			_, err = system.CleanupMinioFromAgent(req)
+		case common.DataCleanupESCleanupRequest:
+			req.JobID = jobID
+			_, err = system.CleanupESFromAgent(req)
+		}
+		return err
+	}, nil
+}
+
+// buildDataCleanupTargetRequest resolves the stored connection and credentials of the module and
+// returns the agent request for the single target without a job id.
+func (h *Hub) buildDataCleanupTargetRequest(configRecord *core.Record, payload dataCleanupTargetPayload) (any, error) {
+	targets := []string{payload.Target}
+	switch payload.Module {
+	case "mysql":
+		var stored dataCleanupMySQLStored
+		if err := parseJSONField(configRecord, "mysql", &stored); err != nil {
+			return nil, fmt.Errorf("parse mysql config failed: %v", err)
+		}
+		database := strings.TrimSpace(payload.Database)
+		if database == "" {
+			database = stored.Database
+		}
+		if stored.Host == "" || stored.Port <= 0 || database == "" {
+			return nil, errors.New("mysql connection or database is not configured")
+		}
+		password, err := h.decryptDataCleanupSecret(configRecord.GetString("mysql_password"))
+		if err != nil {
+			return nil, fmt.Errorf("decrypt mysql password failed: %v", err)
+		}
+		return common.DataCleanupMySQLDeleteTablesRequest{
+			Host:       stored.Host,
+			Port:       stored.Port,
+			Username:   stored.Username,
+			Password:   password,
+			Database:   database,
+			Tables:     targets,
+			TimeoutSec: stored.ActionTimeoutSec,
+			TLS:        stored.DataCleanupTLS,
+			Confirm:    payload.Confirm,
+		}, nil
+	case "redis":
+		var stored dataCleanupRedisStored
+		if err := parseJSONField(configRecord, "redis", &stored); err != nil {
+			return nil, fmt.Errorf("parse redis config failed: %v", err)
+		}
+		db := stored.DB
+		if payload.DB != nil {
+			db = *payload.DB
+		}
+		if stored.Host == "" || stored.Port <= 0 {
+			return nil, errors.New("redis connection is not configured")
+		}
+		if db < 0 {
+			return nil, errors.New("invalid redis db")
+		}
+		password, err := h.decryptDataCleanupSecret(configRecord.GetString("redis_password"))
+		if err != nil {
+			return nil, fmt.Errorf("decrypt redis password failed: %v", err)
+		}
+		return common.DataCleanupRedisCleanupRequest{
+			Host:       stored.Host,
+			Port:       stored.Port,
+			Username:   stored.Username,
+			Password:   password,
+			DB:         db,
+			Patterns:   targets,
+			TimeoutSec: stored.ActionTimeoutSec,
+			TLS:        stored.DataCleanupTLS,
+			Confirm:    payload.Confirm,
+			Filter:     stored.Filter,
+		}, nil
+	case "minio":
+		var stored dataCleanupMinioStored
+		if err := parseJSONField(configRecord, "minio", &stored); err != nil {
+			return nil, fmt.Errorf("parse minio config failed: %v", err)
+		}
+		bucket := strings.TrimSpace(payload.Bucket)
+		if bucket == "" {
+			bucket = stored.Bucket
+		}
+		if stored.Host == "" || stored.Port <= 0 || bucket == "" {
+			return nil, errors.New("minio connection or bucket is not configured")
+		}
+		secret, err := h.decryptDataCleanupSecret(configRecord.GetString("minio_secret_key"))
+		if err != nil {
+			return nil, fmt.Errorf("decrypt minio secret failed: %v", err)
+		}
+		return common.DataCleanupMinioCleanupRequest{
+			Host:       stored.Host,
+			Port:       stored.Port,
+			AccessKey:  stored.AccessKey,
+			SecretKey:  secret,
+			Bucket:     bucket,
+			Prefixes:   targets,
+			TimeoutSec: stored.ActionTimeoutSec,
+			TLS:        stored.DataCleanupTLS,
+			Confirm:    payload.Confirm,
+		}, nil
+	case "es":
+		var stored dataCleanupESStored
+		if err := parseJSONField(configRecord, "es", &stored); err != nil {
+			return nil, fmt.Errorf("parse es config failed: %v", err)
+		}
+		if stored.Host == "" || stored.Port <= 0 {
+			return nil, errors.New("es connection is not configured")
+		}
+		password, err := h.decryptDataCleanupSecret(configRecord.GetString("es_password"))
+		if err != nil {
+			return nil, fmt.Errorf("decrypt es password failed: %v", err)
+		}
+		return common.DataCleanupESCleanupRequest{
+			Host:       stored.Host,
+			Port:       stored.Port,
+			Username:   stored.Username,
+			Password:   password,
+			Indices:    targets,
+			TimeoutSec: stored.ActionTimeoutSec,
+			TLS:        stored.DataCleanupTLS,
+			Confirm:    payload.Confirm,
+			Mode:       stored.Mode,
+		}, nil
+	default:
+		return nil, errors.New("module must be mysql, redis, minio or es")
+	}
+}
+
+// executeDataCleanupTargetRun runs the single-target job and records the outcome on the run.
+func (h *Hub) executeDataCleanupTargetRun(ctx context.Context, runID, systemID, userID, module, target string, start dataCleanupTargetStart) {
+	logs := make([]string, 0, 4)
+	results := make([]dataCleanupRunResult, 0, 1)
+
+	if ctx.Err() != nil {
+		_ = h.failDataCleanupRun(runID, logs, results, errDataCleanupRunCancelled)
+		return
+	}
+	system, err := h.resolveSystem(systemID)
+	if err != nil {
+		err = formatDataCleanupError("resolve system failed", err, map[string]any{"system": systemID})
+		h.logDataCleanupError("resolve system failed", err, "system", systemID)
+		_ = h.failDataCleanupRun(runID, logs, results, err)
+		return
+	}
+
+	jobID := fmt.Sprintf("%s:%s", runID, module)
+	logs = append(logs, fmt.Sprintf("[%s] start %s cleanup job target=%s", time.Now().Format(time.RFC3339), module, target))
+	if err := h.updateDataCleanupRun(runID, "running", 0, module, logs, results); err != nil {
+		h.logDataCleanupError("update cleanup run failed", err, "run", runID)
+		return
+	}
+
+	result := dataCleanupRunResult{Module: module, Status: "success"}
+	if err := start(system, jobID); err != nil {
+		logs = append(logs, fmt.Sprintf("[%s] %s job start failed: %s", time.Now().Format(time.RFC3339), module, err.Error()))
+		result = dataCleanupRunResult{Module: module, Status: "failed", Detail: err.Error()}
+	} else {
+		detail, deleted, err := h.waitDataCleanupJob(ctx, systemID, module, jobID)
+		switch {
+		case err != nil:
+			logs = append(logs, fmt.Sprintf("[%s] %s job poll failed: %s", time.Now().Format(time.RFC3339), module, err.Error()))
+			result = dataCleanupRunResult{Module: module, Status: "failed", Detail: err.Error()}
+		case detail.Status == "failed":
+			errMsg := strings.TrimSpace(detail.Error)
+			if errMsg == "" {
+				errMsg = module + " cleanup job failed"
+			}
+			result = dataCleanupRunResult{Module: module, Status: "failed", Detail: errMsg}
+		default:
+			logs = append(logs, fmt.Sprintf("[%s] %s job completed deleted=%d", time.Now().Format(time.RFC3339), module, deleted))
+		}
+	}
+	results = append(results, result)
+	if err := h.updateDataCleanupRun(runID, result.Status, 100, "done", logs, results); err != nil {
+		h.logDataCleanupError("finalize cleanup run failed", err, "run", runID)
+		return
+	}
+
+	if auditErr := h.recordDockerAudit(dockerAuditEntry{
+		SystemID:     systemID,
+		UserID:       userID,
+		Action:       "data_cleanup.target",
+		ResourceType: "data_cleanup",
+		ResourceID:   runID,
+		Status:       result.Status,
+		Detail:       fmt.Sprintf("%s target %s cleanup %s", module, target, result.Status),
+	}); auditErr != nil {
+		h.logDataCleanupError("record cleanup audit failed", auditErr, "run", runID)
+	}
+}
@@ -0,0 +1,165 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"aether/internal/common"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartDataCleanupTargetRun(t *testing.T) {
+	t.Setenv(dataCleanupKeyEnv, "0123456789abcdef0123456789abcdef")
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	user, err := createTestUser(testApp)
+	require.NoError(t, err)
+	other, err := createTestRecord(testApp, "users", map[string]any{"email": "other@test.com", "password": "testtesttest"})
+	require.NoError(t, err)
+	system, err := createTestRecord(testApp, "systems", map[string]any{
+		"name":   "cleanup",
+		"host":   "localhost",
+		"port":   "45876",
+		"status": "pending",
+		"users":  []string{user.Id},
+	})
+	require.NoError(t, err)
+	password, err := hub.encryptDataCleanupSecret("mysql-secret")
+	require.NoError(t, err)
+	config, err := createTestRecord(testApp, dataCleanupConfigCollection, map[string]any{
+		"system":         system.Id,
+		"mysql":          `{"host":"db.internal","port":3306,"username":"cleaner","database":"app","actionTimeoutSec":90}`,
+		"mysql_password": password,
+	})
+	require.NoError(t, err)
+
+	post := func(auth *core.Record, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		e := &core.RequestEvent{App: testApp, Auth: auth}
+		e.Request = httptest.NewRequest(http.MethodPost, "/api/aether/docker/data-cleanup/target", strings.NewReader(body))
+		e.Response = recorder
+		require.NoError(t, hub.startDataCleanupTargetRun(e))
+		return recorder
+	}
+	target := func(module, extra string) string {
+		return `{"system":"` + system.Id + `","module":"` + module + `","target":"orders"` + extra + `}`
+	}
+
+	recorder := post(user, target("mysql", ""))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "confirm is required")
+
+	recorder = post(user, `{"system":"`+system.Id+`","module":"mysql","confirm":true}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "system, module and target are required")
+
+	recorder = post(other, target("mysql", `,"confirm":true`))
+	assert.Equal(t, http.StatusForbidden, recorder.Code, "the user must have access to the system")
+
+	// the module must be configured on the stored connection
+	recorder = post(user, target("redis", `,"confirm":true`))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "redis connection is not configured")
+	recorder = post(user, target("mongo", `,"confirm":true`))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "module must be mysql, redis, minio or es")
+	count, err := testApp.CountRecords(dataCleanupRunsCollection)
+	require.NoError(t, err)
+	assert.Zero(t, count, "rejected requests queue no run")
+
+	_, err = hub.createDataCleanupRun(system.Id, config.Id, "", user.Id, false)
+	require.NoError(t, err)
+	recorder = post(user, target("mysql", `,"confirm":true`))
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), errCodeRunInProgress)
+}
+
+func TestBuildDataCleanupTargetRequest(t *testing.T) {
+	t.Setenv(dataCleanupKeyEnv, "0123456789abcdef0123456789abcdef")
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	user, err := createTestUser(testApp)
+	require.NoError(t, err)
+	system, err := createTestRecord(testApp, "systems", map[string]any{
+		"name":   "cleanup",
+		"host":   "localhost",
+		"port":   "45876",
+		"status": "pending",
+		"users":  []string{user.Id},
+	})
+	require.NoError(t, err)
+	secret := func(value string) string {
+		encrypted, err := hub.encryptDataCleanupSecret(value)
+		require.NoError(t, err)
+		return encrypted
+	}
+	config, err := createTestRecord(testApp, dataCleanupConfigCollection, map[string]any{
+		"system":           system.Id,
+		"mysql":            `{"host":"db.internal","port":3306,"username":"cleaner","database":"app","actionTimeoutSec":90}`,
+		"mysql_password":   secret("mysql-secret"),
+		"redis":            `{"host":"cache.internal","port":6379,"db":2}`,
+		"redis_password":   secret("redis-secret"),
+		"minio":            `{"host":"s3.internal","port":9000,"accessKey":"access"}`,
+		"minio_secret_key": secret("minio-secret"),
+		"es":               `{"host":"es.internal","port":9200,"username":"elastic","mode":"drop_index"}`,
+		"es_password":      secret("es-secret"),
+	})
+	require.NoError(t, err)
+	build := func(payload dataCleanupTargetPayload) (any, error) {
+		payload.Confirm = true
+		payload.Target = "orders"
+		return hub.buildDataCleanupTargetRequest(config, payload)
+	}
+
+	request, err := build(dataCleanupTargetPayload{Module: "mysql"})
+	require.NoError(t, err)
+	assert.Equal(t, common.DataCleanupMySQLDeleteTablesRequest{
+		Host:       "db.internal",
+		Port:       3306,
+		Username:   "cleaner",
+		Password:   "mysql-secret",
+		Database:   "app",
+		Tables:     []string{"orders"},
+		TimeoutSec: 90,
+		Confirm:    true,
+	}, request, "the stored connection and decrypted password are used")
+	request, err = build(dataCleanupTargetPayload{Module: "mysql", Database: "archive"})
+	require.NoError(t, err)
+	assert.Equal(t, "archive", request.(common.DataCleanupMySQLDeleteTablesRequest).Database)
+
+	db := 5
+	request, err = build(dataCleanupTargetPayload{Module: "redis"})
+	require.NoError(t, err)
+	redis := request.(common.DataCleanupRedisCleanupRequest)
+	assert.Equal(t, "redis-secret", redis.Password)
+	assert.Equal(t, 2, redis.DB)
+	request, err = build(dataCleanupTargetPayload{Module: "redis", DB: &db})
+	require.NoError(t, err)
+	assert.Equal(t, 5, request.(common.DataCleanupRedisCleanupRequest).DB)
+
+	_, err = build(dataCleanupTargetPayload{Module: "minio"})
+	assert.EqualError(t, err, "minio connection or bucket is not configured")
+	request, err = build(dataCleanupTargetPayload{Module: "minio", Bucket: "logs"})
+	require.NoError(t, err)
+	minio := request.(common.DataCleanupMinioCleanupRequest)
+	assert.Equal(t, "minio-secret", minio.SecretKey)
+	assert.Equal(t, "access", minio.AccessKey)
+	assert.Equal(t, []string{"orders"}, minio.Prefixes)
+
+	request, err = build(dataCleanupTargetPayload{Module: "es"})
+	require.NoError(t, err)
+	es := request.(common.DataCleanupESCleanupRequest)
+	assert.Equal(t, "es-secret", es.Password)
+	assert.Equal(t, "drop_index", es.Mode)
+	assert.Equal(t, []string{"orders"}, es.Indices)
+}
@@ -0,0 +1,60 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataCleanupRunRequiresConfirm(t *testing.T) {
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	user, err := createTestUser(testApp)
+	require.NoError(t, err)
+	system, err := createTestRecord(testApp, "systems", map[string]any{
+		"name":   "cleanup",
+		"host":   "localhost",
+		"port":   "45876",
+		"status": "pending",
+		"users":  []string{user.Id},
+	})
+	require.NoError(t, err)
+
+	post := func(handler func(*core.RequestEvent) error, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		e := &core.RequestEvent{App: testApp, Auth: user}
+		e.Request = httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		e.Response = recorder
+		require.NoError(t, handler(e))
+		return recorder
+	}
+
+	recorder := post(hub.startDataCleanupRun, "/api/aether/docker/data-cleanup/run", `{"system":"`+system.Id+`"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "confirm is required")
+	count, err := testApp.CountRecords(dataCleanupRunsCollection)
+	require.NoError(t, err)
+	assert.Zero(t, count, "an unconfirmed run must not be queued")
+
+	recorder = post(hub.retryDataCleanupRun, "/api/aether/docker/data-cleanup/retry", `{"system":"`+system.Id+`","confirm":false}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "confirm is required")
+
+	recorder = post(hub.rerunDataCleanupRun, "/api/aether/docker/data-cleanup/rerun", `{"runId":"missing"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "confirm is required")
+
+	// a dry run deletes nothing, so it passes the check and fails later on the missing config
+	recorder = post(hub.startDataCleanupRun, "/api/aether/docker/data-cleanup/run", `{"system":"`+system.Id+`","dryRun":true}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "cleanup config not found")
+}
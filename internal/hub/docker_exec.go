@@ -0,0 +1,113 @@
+// Package hub 提供在容器内执行单次命令。
+// 命令以参数数组形式传给 agent 直接执行（不经过 shell，需要管道等语法时显式传入 sh -c），
+// 不分配 TTY，返回合并的 stdout/stderr 与退出码。每次执行写一条审计记录，detail 中记录命令。
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"aether/internal/common"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	// containerExecMaxArgs caps the number of command arguments.
+	containerExecMaxArgs = 64
+	// containerExecMaxCommandBytes caps the total size of the command.
+	containerExecMaxCommandBytes = 4096
+)
+
+type dockerContainerExecPayload struct {
+	System    string   `json:"system"`
+	Container string   `json:"container"`
+	Cmd       []string `json:"cmd"`
+}
+
+// validateContainerExecCommand checks the command before it is sent to the agent.
+func validateContainerExecCommand(cmd []string) error {
+	if len(cmd) == 0 || strings.TrimSpace(cmd[0]) == "" {
+		return errors.New("cmd is required")
+	}
+	if len(cmd) > containerExecMaxArgs {
+		return fmt.Errorf("cmd has more than %d arguments", containerExecMaxArgs)
+	}
+	size := 0
+	for _, arg := range cmd {
+		size += len(arg)
+	}
+	if size > containerExecMaxCommandBytes {
+		return fmt.Errorf("cmd exceeds %d bytes", containerExecMaxCommandBytes)
+	}
+	return nil
+}
+
+// formatContainerExecCommand renders the command for the audit log, quoting arguments that
+// contain whitespace or quotes.
+func formatContainerExecCommand(cmd []string) string {
+	parts := make([]string, 0, len(cmd))
+	for _, arg := range cmd {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\") {
+			arg = strconv.Quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// execDockerContainer handles POST /api/aether/docker/containers/exec requests. A command that
+// runs and exits non-zero is not an error; its exit code and output are returned.
+func (h *Hub) execDockerContainer(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload dockerContainerExecPayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	containerID := strings.TrimSpace(payload.Container)
+	if containerID == "" {
+		return respondError(e, http.StatusBadRequest, "container is required")
+	}
+	if err := validateContainerExecCommand(payload.Cmd); err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	if _, err := h.resolveSystemRecordForUser(e, payload.System); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	system, err := h.resolveSystem(payload.System)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	result, err := system.ExecInContainerFromAgent(common.ContainerExecRequest{ContainerID: containerID, Cmd: payload.Cmd})
+	command := formatContainerExecCommand(payload.Cmd)
+	status := dockerAuditStatusSuccess
+	detail := fmt.Sprintf("exec %s (exit %d)", command, result.ExitCode)
+	switch {
+	case err != nil:
+		status = dockerAuditStatusFailed
+		detail = fmt.Sprintf("exec %s: %s", command, err.Error())
+	case result.TimedOut:
+		detail = fmt.Sprintf("exec %s (timed out)", command)
+	}
+	if auditErr := h.recordDockerAudit(dockerAuditEntry{
+		SystemID:     payload.System,
+		UserID:       e.Auth.Id,
+		Action:       "container.exec",
+		ResourceType: "container",
+		ResourceID:   containerID,
+		Status:       status,
+		Detail:       detail,
+	}); auditErr != nil {
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
+	}
+	if err != nil {
+		return respondError(e, http.StatusBadGateway, err.Error())
+	}
+	return e.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,24 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateContainerExecCommand(t *testing.T) {
+	assert.NoError(t, validateContainerExecCommand([]string{"cat", "/etc/hostname"}))
+	assert.Error(t, validateContainerExecCommand(nil))
+	assert.Error(t, validateContainerExecCommand([]string{" ", "x"}))
+	assert.Error(t, validateContainerExecCommand(make([]string, containerExecMaxArgs+1)))
+	assert.Error(t, validateContainerExecCommand([]string{"echo", strings.Repeat("a", containerExecMaxCommandBytes)}))
+}
+
+func TestFormatContainerExecCommand(t *testing.T) {
+	assert.Equal(t, "cat /etc/hostname", formatContainerExecCommand([]string{"cat", "/etc/hostname"}))
+	assert.Equal(t, `sh -c "ls -l | wc -l" ""`, formatContainerExecCommand([]string{"sh", "-c", "ls -l | wc -l", ""}))
+}
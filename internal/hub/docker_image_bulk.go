@@ -0,0 +1,147 @@
+// Package hub 提供批量删除 Docker 镜像。
+// 一次请求删除同一主机上的多个镜像：按顺序逐个调用 agent 删除，单个镜像失败不影响其余镜像，
+// 每个镜像各写一条审计记录，返回逐个镜像的结果与汇总计数。批量删除登记为可取消的运行任务，
+// 取消后不再删除剩余镜像，剩余镜像以 cancelled 状态返回。
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"aether/internal/common"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// dockerImageBulkRemoveMax caps the number of images removed in one request.
+const dockerImageBulkRemoveMax = 200
+
+const dockerImageRemoveCancelled = "cancelled"
+
+type dockerImageBulkRemovePayload struct {
+	System   string   `json:"system"`
+	ImageIDs []string `json:"imageIds"`
+	Force    bool     `json:"force"`
+}
+
+type dockerImageRemoveResult struct {
+	Image  string `json:"image"`
+	Status string `json:"status"` // success, failed or cancelled
+	Error  string `json:"error,omitempty"`
+}
+
+type dockerImageBulkRemoveResponse struct {
+	Results   []dockerImageRemoveResult `json:"results"`
+	Removed   int                       `json:"removed"`
+	Failed    int                       `json:"failed"`
+	Cancelled int                       `json:"cancelled"`
+}
+
+// dockerImageRemoveFunc removes one image on the agent.
+type dockerImageRemoveFunc func(req common.DockerImageRemoveRequest) error
+
+// dockerImageRemoveAuditFunc records the audit entry of one removal attempt.
+type dockerImageRemoveAuditFunc func(imageID string, err error) error
+
+// normalizeDockerImageIDs trims and de-duplicates the requested image ids, keeping their order.
+func normalizeDockerImageIDs(imageIDs []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(imageIDs))
+	normalized := make([]string, 0, len(imageIDs))
+	for _, imageID := range imageIDs {
+		imageID = strings.TrimSpace(imageID)
+		if imageID == "" {
+			continue
+		}
+		if _, ok := seen[imageID]; ok {
+			continue
+		}
+		seen[imageID] = struct{}{}
+		normalized = append(normalized, imageID)
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("imageIds is required")
+	}
+	if len(normalized) > dockerImageBulkRemoveMax {
+		return nil, fmt.Errorf("at most %d images can be removed at once", dockerImageBulkRemoveMax)
+	}
+	return normalized, nil
+}
+
+// removeDockerImages removes the images in order and continues past individual failures.
+// A failed audit write stops the batch and is returned. Once ctx is cancelled the remaining
+// images are reported as cancelled without being removed.
+func removeDockerImages(ctx context.Context, imageIDs []string, force bool, remove dockerImageRemoveFunc, audit dockerImageRemoveAuditFunc, progress func(done int)) (dockerImageBulkRemoveResponse, error) {
+	response := dockerImageBulkRemoveResponse{Results: make([]dockerImageRemoveResult, 0, len(imageIDs))}
+	for index, imageID := range imageIDs {
+		if ctx.Err() != nil {
+			response.Results = append(response.Results, dockerImageRemoveResult{Image: imageID, Status: dockerImageRemoveCancelled})
+			response.Cancelled++
+			continue
+		}
+		err := remove(common.DockerImageRemoveRequest{ImageID: imageID, Force: force})
+		if auditErr := audit(imageID, err); auditErr != nil {
+			return response, auditErr
+		}
+		result := dockerImageRemoveResult{Image: imageID, Status: dockerAuditStatusSuccess}
+		if err != nil {
+			result.Status = dockerAuditStatusFailed
+			result.Error = err.Error()
+			response.Failed++
+		} else {
+			response.Removed++
+		}
+		response.Results = append(response.Results, result)
+		progress(index + 1)
+	}
+	return response, nil
+}
+
+// removeDockerImagesBulk handles POST /api/aether/docker/images/remove-bulk requests.
+func (h *Hub) removeDockerImagesBulk(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload dockerImageBulkRemovePayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	imageIDs, err := normalizeDockerImageIDs(payload.ImageIDs)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	if _, err := h.resolveSystemRecordForUser(e, payload.System); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	system, err := h.resolveSystem(payload.System)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	job, ctx := h.jobs.start("", runningJobTypeDocker, fmt.Sprintf("remove %d images", len(imageIDs)), payload.System, true)
+	defer h.jobs.finish(job)
+	audit := func(imageID string, err error) error {
+		status := dockerAuditStatusSuccess
+		message := "remove image"
+		if err != nil {
+			status = dockerAuditStatusFailed
+			message = err.Error()
+		}
+		return h.recordDockerAudit(dockerAuditEntry{
+			SystemID:     payload.System,
+			UserID:       e.Auth.Id,
+			Action:       "image.remove",
+			ResourceType: "image",
+			ResourceID:   imageID,
+			Status:       status,
+			Detail:       message,
+		})
+	}
+	progress := func(done int) { job.setProgress(done, len(imageIDs)) }
+	response, err := removeDockerImages(ctx, imageIDs, payload.Force, system.RemoveDockerImageFromAgent, audit, progress)
+	if err != nil {
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	return e.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,93 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"aether/internal/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeDockerImageIDs(t *testing.T) {
+	ids, err := normalizeDockerImageIDs([]string{" sha256:a ", "", "sha256:b", "sha256:a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sha256:a", "sha256:b"}, ids)
+
+	_, err = normalizeDockerImageIDs([]string{" ", ""})
+	assert.Error(t, err)
+
+	tooMany := make([]string, dockerImageBulkRemoveMax+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("x", i+1)
+	}
+	_, err = normalizeDockerImageIDs(tooMany)
+	assert.Error(t, err)
+}
+
+func TestRemoveDockerImages(t *testing.T) {
+	var removed []common.DockerImageRemoveRequest
+	remove := func(req common.DockerImageRemoveRequest) error {
+		removed = append(removed, req)
+		if req.ImageID == "in-use" {
+			return errors.New("image is being used by a container")
+		}
+		return nil
+	}
+	audited := map[string]error{}
+	audit := func(imageID string, err error) error {
+		audited[imageID] = err
+		return nil
+	}
+	var progress []int
+
+	response, err := removeDockerImages(context.Background(), []string{"a", "in-use", "b"}, true, remove, audit, func(done int) {
+		progress = append(progress, done)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.Removed)
+	assert.Equal(t, 1, response.Failed)
+	require.Len(t, response.Results, 3)
+	assert.Equal(t, dockerAuditStatusSuccess, response.Results[0].Status)
+	assert.Equal(t, dockerAuditStatusFailed, response.Results[1].Status)
+	assert.Contains(t, response.Results[1].Error, "being used")
+	assert.Equal(t, dockerAuditStatusSuccess, response.Results[2].Status)
+	assert.Len(t, removed, 3)
+	assert.True(t, removed[0].Force)
+	assert.Len(t, audited, 3)
+	assert.Error(t, audited["in-use"])
+	assert.Equal(t, []int{1, 2, 3}, progress)
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		response, err := removeDockerImages(ctx, []string{"a", "b", "c"}, false, func(common.DockerImageRemoveRequest) error {
+			calls++
+			cancel()
+			return nil
+		}, audit, func(int) {})
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 1, response.Removed)
+		assert.Equal(t, 2, response.Cancelled)
+		assert.Equal(t, dockerImageRemoveCancelled, response.Results[2].Status)
+	})
+
+	t.Run("audit failure stops the batch", func(t *testing.T) {
+		calls := 0
+		_, err := removeDockerImages(context.Background(), []string{"a", "b"}, false, func(common.DockerImageRemoveRequest) error {
+			calls++
+			return nil
+		}, func(string, error) error {
+			return errors.New("db locked")
+		}, func(int) {})
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,87 @@
+// Package hub 提供镜像仓库凭据连通性测试。
+// 保存凭据前，由指定主机上的 agent 使用凭据登录镜像仓库，返回登录是否成功。测试可基于表单填写的凭据，
+// 也可基于已保存的仓库（id），此时表单中填写的字段覆盖已保存的值；留空的密码仅在仓库地址未改动时沿用已保存的密码。
+// 测试不写入仓库记录，也不写审计记录；agent 侧同样不保存凭据。
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"aether/internal/common"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type dockerRegistryTestPayload struct {
+	System string `json:"system"`
+	// ID optionally names a saved registry whose credentials fill the fields left empty
+	ID       string `json:"id"`
+	Server   string `json:"server"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// dockerRegistryTestAuth returns the credentials to test: the payload fields, with empty ones
+// taken from the saved registry when one is given.
+func dockerRegistryTestAuth(payload dockerRegistryTestPayload, saved *core.Record) (common.DockerRegistryAuth, error) {
+	auth := common.DockerRegistryAuth{
+		Server:   strings.TrimSpace(payload.Server),
+		Username: strings.TrimSpace(payload.Username),
+		Password: payload.Password,
+	}
+	if saved != nil {
+		if auth.Server == "" {
+			auth.Server = saved.GetString("server")
+		}
+		if auth.Username == "" {
+			auth.Username = saved.GetString("username")
+		}
+		// the saved password is only sent to the saved server
+		if auth.Password == "" && auth.Server == saved.GetString("server") {
+			auth.Password = saved.GetString("password")
+		}
+	}
+	if auth.Server == "" {
+		return auth, errors.New("server is required")
+	}
+	return auth, nil
+}
+
+// testDockerRegistry handles POST /api/aether/docker/registries/test requests. Login failures are
+// reported in the response body with success=false rather than as an error status.
+func (h *Hub) testDockerRegistry(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload dockerRegistryTestPayload
+	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	var saved *core.Record
+	if id := strings.TrimSpace(payload.ID); id != "" {
+		record, err := h.FindRecordById("docker_registries", id)
+		if err != nil {
+			return respondError(e, http.StatusNotFound, "registry not found")
+		}
+		saved = record
+	}
+	auth, err := dockerRegistryTestAuth(payload, saved)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	if _, err := h.resolveSystemRecordForUser(e, payload.System); err != nil {
+		return respondSystemAccessError(e, err)
+	}
+	system, err := h.resolveSystem(payload.System)
+	if err != nil {
+		return respondError(e, http.StatusBadRequest, err.Error())
+	}
+	status, err := system.LoginDockerRegistryFromAgent(common.DockerRegistryLoginRequest{Registry: auth})
+	if err != nil {
+		return e.JSON(http.StatusOK, map[string]any{"success": false, "message": err.Error()})
+	}
+	return e.JSON(http.StatusOK, map[string]any{"success": true, "message": status})
+}
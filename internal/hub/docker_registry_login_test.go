@@ -0,0 +1,45 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerRegistryTestAuth(t *testing.T) {
+	_, err := dockerRegistryTestAuth(dockerRegistryTestPayload{Username: "bob"}, nil)
+	assert.Error(t, err)
+
+	auth, err := dockerRegistryTestAuth(dockerRegistryTestPayload{Server: " registry.example.com ", Username: " bob ", Password: "secret"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com", auth.Server)
+	assert.Equal(t, "bob", auth.Username)
+	assert.Equal(t, "secret", auth.Password)
+
+	saved := core.NewRecord(core.NewBaseCollection("docker_registries"))
+	saved.Set("server", "registry.example.com")
+	saved.Set("username", "alice")
+	saved.Set("password", "stored")
+
+	auth, err = dockerRegistryTestAuth(dockerRegistryTestPayload{ID: "r1"}, saved)
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com", auth.Server)
+	assert.Equal(t, "alice", auth.Username)
+	assert.Equal(t, "stored", auth.Password)
+
+	auth, err = dockerRegistryTestAuth(dockerRegistryTestPayload{ID: "r1", Username: "bob", Password: "typed"}, saved)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", auth.Username)
+	assert.Equal(t, "typed", auth.Password)
+
+	// a changed server never receives the saved password
+	auth, err = dockerRegistryTestAuth(dockerRegistryTestPayload{ID: "r1", Server: "other.example.com"}, saved)
+	require.NoError(t, err)
+	assert.Equal(t, "other.example.com", auth.Server)
+	assert.Empty(t, auth.Password)
+}
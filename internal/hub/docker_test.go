@@ -0,0 +1,102 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireWritableStopsHandler(t *testing.T) {
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	user, err := createTestUser(testApp)
+	require.NoError(t, err)
+	readonlyUser, err := createTestRecord(testApp, "users", map[string]any{
+		"email":    "readonly@test.com",
+		"password": "testtesttest",
+		"role":     "readonly",
+	})
+	require.NoError(t, err)
+	template, err := createTestRecord(testApp, "docker_compose_templates", map[string]any{
+		"name":       "web",
+		"content":    "services: {}",
+		"created_by": readonlyUser.Id,
+	})
+	require.NoError(t, err)
+
+	call := func(handler func(*core.RequestEvent) error, auth *core.Record, method, target, body string) (*httptest.ResponseRecorder, error) {
+		recorder := httptest.NewRecorder()
+		e := &core.RequestEvent{App: testApp, Auth: auth}
+		e.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+		e.Response = recorder
+		return recorder, handler(e)
+	}
+
+	registryBody := `{"name":"mirror","server":"mirror.example.com"}`
+	recorder, err := call(hub.createDockerRegistry, readonlyUser, http.MethodPost, "/api/aether/docker/registries", registryBody)
+	assert.ErrorIs(t, err, errWriteForbidden)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	count, err := testApp.CountRecords("docker_registries")
+	require.NoError(t, err)
+	assert.Zero(t, count, "a readonly user must not create a registry")
+
+	recorder, err = call(hub.deleteDockerComposeTemplate, readonlyUser, http.MethodDelete, "/api/aether/docker/compose-templates?id="+template.Id, "")
+	assert.ErrorIs(t, err, errWriteForbidden)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	_, err = testApp.FindRecordById("docker_compose_templates", template.Id)
+	assert.NoError(t, err, "a readonly user must not delete a template")
+
+	count, err = testApp.CountRecords("docker_audits")
+	require.NoError(t, err)
+	assert.Zero(t, count, "rejected requests never reach the audited write")
+
+	recorder, err = call(hub.createDockerRegistry, user, http.MethodPost, "/api/aether/docker/registries", registryBody)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	count, err = testApp.CountRecords("docker_registries")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+}
+
+func TestDockerImageForRegistry(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    string
+		server   string
+		expected string
+	}{
+		{"bare host", "nginx:1.27", "mirror.example.com", "mirror.example.com/library/nginx:1.27"},
+		{"host with port", "team/app:v1", "mirror.example.com:5000", "mirror.example.com:5000/team/app:v1"},
+		{"scheme and path", "nginx", "https://mirror.example.com/v2/", "mirror.example.com/library/nginx"},
+		{"http scheme", "team/app", "http://10.0.0.5:5000", "10.0.0.5:5000/team/app"},
+		{"replaces registry host", "ghcr.io/org/app:v2", "mirror.example.com", "mirror.example.com/org/app:v2"},
+		{"replaces localhost", "localhost/app", "mirror.example.com", "mirror.example.com/app"},
+		{"replaces localhost with port", "localhost:5000/team/app:v1", "mirror.example.com", "mirror.example.com/team/app:v1"},
+		{"replaces single-segment host path", "registry.internal/app:v1", "mirror.example.com", "mirror.example.com/app:v1"},
+		{"replaces host with port", "10.0.0.5:5000/app", "mirror.example.com", "mirror.example.com/app"},
+		{"docker hub host in image", "docker.io/nginx", "mirror.example.com", "mirror.example.com/library/nginx"},
+		{"namespace without host", "team/app", "mirror.example.com", "mirror.example.com/team/app"},
+		{"docker hub credential server", "nginx", "https://index.docker.io/v1/", "nginx"},
+		{"docker hub host", "team/app", "docker.io", "team/app"},
+		{"docker hub registry host", "nginx:1.27", "registry-1.docker.io", "nginx:1.27"},
+		{"empty server", "nginx", " ", "nginx"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, dockerImageForRegistry(test.image, test.server), test.name)
+	}
+}
+
+func TestDockerImageOpPullRegistryIDs(t *testing.T) {
+	assert.Equal(t, []string{"a"}, dockerImageOpPayload{RegistryID: "a"}.pullRegistryIDs(), "a single registry is rewritten like a fallback list")
+	assert.Equal(t, []string{"b", "c"}, dockerImageOpPayload{RegistryID: "a", RegistryIDs: []string{"b", "c"}}.pullRegistryIDs())
+	assert.Empty(t, dockerImageOpPayload{RegistryID: " "}.pullRegistryIDs())
+}
@@ -10,7 +10,9 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"aether"
@@ -18,6 +20,7 @@ import (
 	"aether/internal/hub/config"
 	"aether/internal/hub/logging"
 	"aether/internal/hub/systems"
+	"aether/internal/hub/ws"
 	"aether/internal/records"
 	"aether/internal/users"
 
@@ -35,9 +38,18 @@ type Hub struct {
 	rm            *records.RecordManager
 	sm            *systems.SystemManager
 	ingestMonitor *ingestMonitorService
+	cleanupQueue  *dataCleanupRunQueue
+	jobs          *runningJobRegistry
+	redactor      *logRedactor
 	pubKey        string
 	signer        ssh.Signer
 	appURL        string
+	// auditDockerReads enables audit entries for docker read operations (DOCKER_AUDIT_READS)
+	auditDockerReads bool
+	// ready is set once StartHub finishes initialization; scheduled jobs no-op until then
+	ready atomic.Bool
+	// apiTestSchedulerActive records whether the api test scheduler has run its first ready tick
+	apiTestSchedulerActive atomic.Bool
 }
 
 // NewHub creates a new Hub instance with default configuration
@@ -49,7 +61,29 @@ func NewHub(app core.App) *Hub {
 	hub.um = users.NewUserManager(hub)
 	hub.rm = records.NewRecordManager(hub)
 	hub.sm = systems.NewSystemManager(hub)
+	if value, ok := GetEnv("WS_MISSED_PONG_THRESHOLD"); ok {
+		if threshold, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			hub.sm.SetWsMissedPongThreshold(threshold)
+		}
+	}
+	if value, ok := GetEnv("SSH_MAX_SESSIONS"); ok {
+		if limit, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			hub.sm.SetSSHMaxSessions(limit)
+		}
+	}
+	// WS_COMPRESSION=false disables permessage-deflate negotiation with agents
+	if value, ok := GetEnv("WS_COMPRESSION"); ok {
+		if enabled, err := strconv.ParseBool(strings.TrimSpace(value)); err == nil {
+			ws.SetCompressionEnabled(enabled)
+		}
+	}
 	hub.ingestMonitor = newIngestMonitorService(hub)
+	// the cleanup run limit spans all systems, so it comes from the environment rather than a per-system config
+	hub.cleanupQueue = newDataCleanupRunQueue(dataCleanupMaxConcurrentRunsFromEnv())
+	hub.jobs = newRunningJobRegistry()
+	hub.redactor = newLogRedactorFromEnv()
+	hub.auditDockerReads = dockerAuditReadsFromEnv()
+	hub.sm.SetOnWebSocketConnect(hub.reconcileDataCleanupRuns)
 	hub.appURL, _ = GetEnv("APP_URL")
 	return hub
 }
@@ -69,6 +103,9 @@ func (h *Hub) StartHub() error {
 		if err := h.initialize(e); err != nil {
 			return err
 		}
+		if len(h.redactor.invalid) > 0 {
+			h.Logger().Warn("ignoring invalid REDACT_PATTERNS entries", "logger", "hub", "patterns", h.redactor.invalid)
+		}
 		// sync systems with config
 		if err := config.SyncSystems(e); err != nil {
 			return err
@@ -91,6 +128,8 @@ func (h *Hub) StartHub() error {
 		if err := h.sm.Initialize(); err != nil {
 			return err
 		}
+		// mark the hub ready for scheduled jobs
+		h.ready.Store(true)
 		return e.Next()
 	})
 
@@ -98,6 +137,27 @@ func (h *Hub) StartHub() error {
 	// handle default values for user / user_settings creation
 	h.App.OnRecordCreate("users").BindFunc(h.um.InitializeUserRole)
 	h.App.OnRecordCreate("user_settings").BindFunc(h.um.InitializeUserSettings)
+	// validate api test extended fields (cron, assertions) before save
+	h.App.OnRecordValidate(apiTestCasesCollection, apiTestCollectionsCollection).BindFunc(h.validateApiTestRecord)
+	h.App.OnRecordValidate(apiTestAssertionTemplatesCollection).BindFunc(h.validateApiTestAssertionTemplate)
+	// cases created without an explicit enabled flag are enabled
+	h.App.OnRecordCreateRequest(apiTestCasesCollection).BindFunc(h.defaultApiTestCaseEnabled)
+	// the linked system must be accessible to the requesting user
+	h.App.OnRecordCreateRequest(apiTestCasesCollection).BindFunc(h.validateApiTestCaseSystem)
+	h.App.OnRecordUpdateRequest(apiTestCasesCollection).BindFunc(h.validateApiTestCaseSystem)
+	// collection OAuth2 client secrets are stored encrypted
+	h.App.OnRecordCreateRequest(apiTestCollectionsCollection).BindFunc(h.encryptApiTestCollectionSecret)
+	h.App.OnRecordUpdateRequest(apiTestCollectionsCollection).BindFunc(h.encryptApiTestCollectionSecret)
+	// collection proxy passwords are split out of proxy_url and stored encrypted
+	h.App.OnRecordCreateRequest(apiTestCollectionsCollection).BindFunc(h.encryptApiTestCollectionProxyPassword)
+	h.App.OnRecordUpdateRequest(apiTestCollectionsCollection).BindFunc(h.encryptApiTestCollectionProxyPassword)
+	// case basic auth passwords and bearer tokens are stored encrypted
+	h.App.OnRecordCreateRequest(apiTestCasesCollection).BindFunc(h.encryptApiTestCaseSecrets)
+	h.App.OnRecordUpdateRequest(apiTestCasesCollection).BindFunc(h.encryptApiTestCaseSecrets)
+	// record who changed api test config and which fields
+	h.App.OnRecordCreateRequest(apiTestCasesCollection, apiTestCollectionsCollection).BindFunc(h.auditApiTestConfigCreate)
+	h.App.OnRecordUpdateRequest(apiTestCasesCollection, apiTestCollectionsCollection).BindFunc(h.auditApiTestConfigUpdate)
+	h.App.OnRecordDeleteRequest(apiTestCasesCollection, apiTestCollectionsCollection).BindFunc(h.auditApiTestConfigDelete)
 
 	if pb, ok := h.App.(*pocketbase.PocketBase); ok {
 		// log.Println("Starting pocketbase")
@@ -127,7 +187,8 @@ func (h *Hub) initialize(e *core.ServeEvent) error {
 	if err := setCollectionAuthSettings(e.App); err != nil {
 		return err
 	}
-	return nil
+	// move plaintext proxy passwords saved by older versions out of proxy_url
+	return h.moveApiTestProxyPasswords(e.App)
 }
 
 // setCollectionAuthSettings sets up default authentication settings for the app
@@ -306,6 +367,10 @@ func (h *Hub) registerCronJobs(_ *core.ServeEvent) error {
 	h.Cron().MustAdd("create longer records", "*/10 * * * *", h.rm.CreateLongerRecords)
 	// run api tests schedule check every minute
 	h.Cron().MustAdd("api tests schedule", "*/1 * * * *", h.runApiTestScheduleTick)
+	// refresh api test stats summary when due (interval configured in the schedule config)
+	h.Cron().MustAdd("api tests stats", "*/1 * * * *", h.runApiTestStatsTick)
+	// start due data cleanup schedules every minute
+	h.Cron().MustAdd("data cleanup schedule", "*/1 * * * *", h.runDataCleanupScheduleTick)
 	return nil
 }
 
@@ -392,6 +457,14 @@ func (h *Hub) registerApiRoutes(se *core.ServeEvent) error {
 	apiAuth.POST("/repo-sources/refresh", h.refreshRepoSources)
 	// get systemd service details
 	apiAuth.GET("/systemd/info", h.getSystemdInfo)
+	// consolidated per-system summary for dashboards
+	apiAuth.GET("/systems/summary", h.getSystemsSummary)
+	// per-system connection health (transport, ws heartbeat metrics, reconnects)
+	apiAuth.GET("/systems/health", h.getSystemsHealth)
+	apiAuth.GET("/systems/connections", h.getSystemsConnectionStats)
+	apiAuth.GET("/systems/probe", h.probeSystems)
+	apiAuth.GET("/jobs/running", h.listRunningJobs)
+	apiAuth.POST("/jobs/cancel", h.cancelRunningJob)
 	// local agent control for the hub host
 	localAgentGroup := apiAuth.Group("/local-agent")
 	localAgentGroup.GET("/status", h.getLocalAgentStatus)
@@ -414,10 +487,15 @@ func (h *Hub) registerApiRoutes(se *core.ServeEvent) error {
 	dockerGroup := apiAuth.Group("/docker")
 	dockerGroup.GET("/overview", h.getDockerOverview)
 	dockerGroup.GET("/containers", h.listDockerContainers)
+	dockerGroup.GET("/containers/diff", h.getDockerContainerDiff)
+	dockerGroup.GET("/containers/stats", h.getDockerContainerStats)
+	dockerGroup.POST("/containers/exec", h.execDockerContainer)
 	dockerGroup.GET("/images", h.listDockerImages)
 	dockerGroup.POST("/images/pull", h.pullDockerImage)
 	dockerGroup.POST("/images/push", h.pushDockerImage)
 	dockerGroup.POST("/images/remove", h.removeDockerImage)
+	dockerGroup.POST("/images/remove-bulk", h.removeDockerImagesBulk)
+	dockerGroup.POST("/images/prune", h.pruneDockerImages)
 	dockerGroup.GET("/networks", h.listDockerNetworks)
 	dockerGroup.POST("/networks", h.createDockerNetwork)
 	dockerGroup.POST("/networks/remove", h.removeDockerNetwork)
@@ -429,16 +507,20 @@ func (h *Hub) registerApiRoutes(se *core.ServeEvent) error {
 	dockerGroup.POST("/compose/projects/update", h.updateDockerComposeProject)
 	dockerGroup.POST("/compose/projects/operate", h.operateDockerComposeProject)
 	dockerGroup.POST("/compose/projects/delete", h.deleteDockerComposeProject)
+	dockerGroup.GET("/compose/projects/logs", h.getDockerComposeLogs)
+	dockerGroup.GET("/compose/projects/logs/stream", h.streamDockerComposeLogs)
 	dockerGroup.GET("/config", h.getDockerConfig)
 	dockerGroup.POST("/config", h.updateDockerConfig)
 	dockerGroup.GET("/registries", h.listDockerRegistries)
 	dockerGroup.POST("/registries", h.createDockerRegistry)
 	dockerGroup.POST("/registries/update", h.updateDockerRegistry)
 	dockerGroup.POST("/registries/delete", h.deleteDockerRegistry)
+	dockerGroup.POST("/registries/test", h.testDockerRegistry)
 	dockerGroup.GET("/compose-templates", h.listDockerComposeTemplates)
 	dockerGroup.POST("/compose-templates", h.createDockerComposeTemplate)
 	dockerGroup.POST("/compose-templates/update", h.updateDockerComposeTemplate)
 	dockerGroup.POST("/compose-templates/delete", h.deleteDockerComposeTemplate)
+	dockerGroup.POST("/compose-templates/render", h.renderDockerComposeTemplate)
 	dockerGroup.GET("/service-configs", h.listDockerServiceConfigs)
 	dockerGroup.POST("/service-configs", h.createDockerServiceConfig)
 	dockerGroup.POST("/service-configs/update", h.updateDockerServiceConfig)
@@ -448,15 +530,31 @@ func (h *Hub) registerApiRoutes(se *core.ServeEvent) error {
 	dockerCleanupGroup := dockerGroup.Group("/data-cleanup")
 	dockerCleanupGroup.GET("/config", h.getDockerDataCleanupConfig)
 	dockerCleanupGroup.POST("/config", h.upsertDockerDataCleanupConfig)
+	dockerCleanupGroup.GET("/export", h.exportDataCleanupConfigs)
+	dockerCleanupGroup.POST("/import", h.importDataCleanupConfigs)
 	dockerCleanupGroup.POST("/mysql/databases", h.listDataCleanupMySQLDatabases)
 	dockerCleanupGroup.POST("/mysql/tables", h.listDataCleanupMySQLTables)
+	dockerCleanupGroup.POST("/mysql/ping", h.pingDataCleanupMySQL)
 	dockerCleanupGroup.POST("/redis/dbs", h.listDataCleanupRedisDatabases)
+	dockerCleanupGroup.POST("/redis/ping", h.pingDataCleanupRedis)
 	dockerCleanupGroup.POST("/minio/buckets", h.listDataCleanupMinioBuckets)
 	dockerCleanupGroup.POST("/minio/prefixes", h.listDataCleanupMinioPrefixes)
+	dockerCleanupGroup.POST("/minio/ping", h.pingDataCleanupMinio)
 	dockerCleanupGroup.POST("/es/indices", h.listDataCleanupESIndices)
+	dockerCleanupGroup.POST("/es/ping", h.pingDataCleanupES)
+	dockerCleanupGroup.POST("/match-count", h.countDataCleanupMatches)
 	dockerCleanupGroup.POST("/run", h.startDataCleanupRun)
+	dockerCleanupGroup.POST("/target", h.startDataCleanupTargetRun)
 	dockerCleanupGroup.GET("/run", h.getDataCleanupRun)
+	dockerCleanupGroup.GET("/runs", h.listDataCleanupRuns)
 	dockerCleanupGroup.POST("/retry", h.retryDataCleanupRun)
+	dockerCleanupGroup.POST("/rerun", h.rerunDataCleanupRun)
+	dockerCleanupGroup.POST("/cancel", h.cancelDataCleanupRun)
+	dockerCleanupGroup.GET("/schedule", h.getDataCleanupSchedule)
+	dockerCleanupGroup.POST("/schedule", h.upsertDataCleanupSchedule)
+	dockerCleanupGroup.GET("/scheduler", h.getDataCleanupScheduler)
+	dockerCleanupGroup.POST("/scheduler/pause", h.pauseDataCleanupScheduler)
+	dockerCleanupGroup.POST("/scheduler/resume", h.resumeDataCleanupScheduler)
 	dockerGroup.GET("/audits", h.listDockerAudits)
 	// /api-tests routes
 	apiTestsGroup := apiAuth.Group("/api-tests")
@@ -464,10 +562,22 @@ func (h *Hub) registerApiRoutes(se *core.ServeEvent) error {
 	apiTestsGroup.PUT("/schedule", h.updateApiTestScheduleConfig)
 	apiTestsGroup.GET("/export", h.exportApiTests)
 	apiTestsGroup.POST("/import", h.importApiTests)
+	apiTestsGroup.POST("/cases/tags", h.bulkUpdateApiTestCaseTags)
+	apiTestsGroup.POST("/cases/move", h.bulkMoveApiTestCases)
+	apiTestsGroup.POST("/reorder", h.reorderApiTests)
+	apiTestsGroup.POST("/preview-case", h.previewApiTestCase)
+	apiTestsGroup.GET("/effective-config", h.getApiTestEffectiveConfig)
 	apiTestsGroup.POST("/run-case", h.runApiTestCase)
+	apiTestsGroup.POST("/canary", h.runApiTestCanary)
+	apiTestsGroup.POST("/accept-fingerprint", h.acceptApiTestFingerprint)
 	apiTestsGroup.POST("/run-collection", h.runApiTestCollection)
 	apiTestsGroup.POST("/run-all", h.runAllApiTests)
+	apiTestsGroup.POST("/abort-run", h.abortApiTestRun)
 	apiTestsGroup.GET("/runs", h.listApiTestRuns)
+	apiTestsGroup.GET("/runs/export", h.exportApiTestRuns)
+	apiTestsGroup.GET("/stats", h.getApiTestStats)
+	apiTestsGroup.GET("/health", h.getApiTestHealth)
+	apiTestsGroup.GET("/audits", h.listApiTestConfigAudits)
 
 	// ingest monitor (formal ingest + XXL batch runs)
 	ingestGroup := apiAuth.Group("/ingest-monitor")
@@ -511,17 +621,17 @@ func (h *Hub) containerRequestHandler(e *core.RequestEvent, fetchFunc func(*syst
 	containerID := e.Request.URL.Query().Get("container")
 
 	if systemID == "" || containerID == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system and container parameters are required"})
+		return respondError(e, http.StatusBadRequest, "system and container parameters are required")
 	}
 
 	system, err := h.sm.GetSystem(systemID)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+		return respondError(e, http.StatusNotFound, "system not found")
 	}
 
 	data, err := fetchFunc(system, containerID)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusNotFound, err.Error())
 	}
 
 	return e.JSON(http.StatusOK, map[string]string{responseKey: data})
@@ -544,7 +654,7 @@ func (h *Hub) getContainerInfo(e *core.RequestEvent) error {
 func (h *Hub) operateContainer(e *core.RequestEvent) error {
 	// RBAC: only admin / non-readonly allowed
 	if e.Auth == nil || e.Auth.GetString("role") == "readonly" {
-		return e.JSON(http.StatusForbidden, map[string]string{"error": "forbidden"})
+		return respondError(e, http.StatusForbidden, "forbidden")
 	}
 
 	var payload struct {
@@ -554,15 +664,15 @@ func (h *Hub) operateContainer(e *core.RequestEvent) error {
 		Signal    string `json:"signal"`
 	}
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 	if payload.System == "" || payload.Container == "" || payload.Operation == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system, container and operation are required"})
+		return respondError(e, http.StatusBadRequest, "system, container and operation are required")
 	}
 
 	system, err := h.sm.GetSystem(payload.System)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+		return respondError(e, http.StatusNotFound, "system not found")
 	}
 
 	err = system.OperateContainer(payload.Container, payload.Operation, payload.Signal)
@@ -584,10 +694,10 @@ func (h *Hub) operateContainer(e *core.RequestEvent) error {
 		Status:       status,
 		Detail:       detail,
 	}); auditErr != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": auditErr.Error()})
+		return respondError(e, http.StatusInternalServerError, auditErr.Error())
 	}
 	if err != nil {
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadGateway, err.Error())
 	}
 
 	// trigger an immediate refresh so status/Uptime update quickly
@@ -605,7 +715,7 @@ func (h *Hub) operateContainer(e *core.RequestEvent) error {
 			"err",
 			err,
 		)
-		return e.JSON(http.StatusBadGateway, map[string]string{"error": "operation succeeded but refresh failed"})
+		return respondError(e, http.StatusBadGateway, "operation succeeded but refresh failed")
 	}
 
 	return e.JSON(http.StatusOK, map[string]string{"status": "ok"})
@@ -618,15 +728,15 @@ func (h *Hub) getSystemdInfo(e *core.RequestEvent) error {
 	serviceName := query.Get("service")
 
 	if systemID == "" || serviceName == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system and service parameters are required"})
+		return respondError(e, http.StatusBadRequest, "system and service parameters are required")
 	}
 	system, err := h.sm.GetSystem(systemID)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+		return respondError(e, http.StatusNotFound, "system not found")
 	}
 	details, err := system.FetchSystemdInfoFromAgent(serviceName)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusNotFound, err.Error())
 	}
 	e.Response.Header().Set("Cache-Control", "public, max-age=60")
 	return e.JSON(http.StatusOK, map[string]any{"details": details})
@@ -637,17 +747,17 @@ func (h *Hub) getSystemdInfo(e *core.RequestEvent) error {
 func (h *Hub) refreshSmartData(e *core.RequestEvent) error {
 	systemID := e.Request.URL.Query().Get("system")
 	if systemID == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system parameter is required"})
+		return respondError(e, http.StatusBadRequest, "system parameter is required")
 	}
 
 	system, err := h.sm.GetSystem(systemID)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+		return respondError(e, http.StatusNotFound, "system not found")
 	}
 
 	// Fetch and save SMART devices
 	if err := system.FetchAndSaveSmartDevices(); err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	return e.JSON(http.StatusOK, map[string]string{"status": "ok"})
@@ -658,16 +768,16 @@ func (h *Hub) refreshSmartData(e *core.RequestEvent) error {
 func (h *Hub) refreshRepoSources(e *core.RequestEvent) error {
 	systemID := e.Request.URL.Query().Get("system")
 	if systemID == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system parameter is required"})
+		return respondError(e, http.StatusBadRequest, "system parameter is required")
 	}
 
 	system, err := h.sm.GetSystem(systemID)
 	if err != nil {
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+		return respondError(e, http.StatusNotFound, "system not found")
 	}
 
 	if err := system.FetchAndSaveRepoSources(true); err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	return e.JSON(http.StatusOK, map[string]string{"status": "ok"})
@@ -558,7 +558,7 @@ func formatNullableTime(value sql.NullTime) string {
 
 func (h *Hub) getIngestMonitorSummary(e *core.RequestEvent) error {
 	if h.ingestMonitor == nil {
-		return e.JSON(http.StatusServiceUnavailable, map[string]string{"error": "ingest-monitor 未初始化"})
+		return respondError(e, http.StatusServiceUnavailable, "ingest-monitor 未初始化")
 	}
 
 	response, err := h.ingestMonitor.GetSummary(e.Request.Context())
@@ -571,12 +571,12 @@ func (h *Hub) getIngestMonitorSummary(e *core.RequestEvent) error {
 
 func (h *Hub) getIngestMonitorDetail(e *core.RequestEvent) error {
 	if h.ingestMonitor == nil {
-		return e.JSON(http.StatusServiceUnavailable, map[string]string{"error": "ingest-monitor 未初始化"})
+		return respondError(e, http.StatusServiceUnavailable, "ingest-monitor 未初始化")
 	}
 
 	itemCode := strings.TrimSpace(e.Request.URL.Query().Get("itemCode"))
 	if itemCode == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "itemCode 参数不能为空"})
+		return respondError(e, http.StatusBadRequest, "itemCode 参数不能为空")
 	}
 
 	response, err := h.ingestMonitor.GetDetail(e.Request.Context(), itemCode)
@@ -591,11 +591,11 @@ func (h *Hub) handleIngestMonitorError(e *core.RequestEvent, logMessage string,
 	var cfgErr *ingestMonitorConfigError
 	switch {
 	case errors.As(err, &cfgErr):
-		return e.JSON(http.StatusServiceUnavailable, map[string]string{"error": cfgErr.Error()})
+		return respondError(e, http.StatusServiceUnavailable, cfgErr.Error())
 	case errors.Is(err, sql.ErrNoRows):
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "未找到对应的正式入库记录"})
+		return respondError(e, http.StatusNotFound, "未找到对应的正式入库记录")
 	default:
 		h.Logger().Error(logMessage, "logger", "hub", "err", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": "查询入库状态失败"})
+		return respondError(e, http.StatusInternalServerError, "查询入库状态失败")
 	}
 }
@@ -621,7 +621,7 @@ func nullableFloatPointer(value sql.NullFloat64) *float64 {
 
 func (h *Hub) getIngestMonitorBatches(e *core.RequestEvent) error {
 	if h.ingestMonitor == nil {
-		return e.JSON(http.StatusServiceUnavailable, map[string]string{"error": "ingest-monitor 未初始化"})
+		return respondError(e, http.StatusServiceUnavailable, "ingest-monitor 未初始化")
 	}
 
 	response, err := h.ingestMonitor.GetBatchList(e.Request.Context())
@@ -634,12 +634,12 @@ func (h *Hub) getIngestMonitorBatches(e *core.RequestEvent) error {
 
 func (h *Hub) getIngestMonitorBatchDetail(e *core.RequestEvent) error {
 	if h.ingestMonitor == nil {
-		return e.JSON(http.StatusServiceUnavailable, map[string]string{"error": "ingest-monitor 未初始化"})
+		return respondError(e, http.StatusServiceUnavailable, "ingest-monitor 未初始化")
 	}
 
 	batchRunID := strings.TrimSpace(e.Request.URL.Query().Get("batchRunId"))
 	if batchRunID == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "batchRunId 参数不能为空"})
+		return respondError(e, http.StatusBadRequest, "batchRunId 参数不能为空")
 	}
 
 	response, err := h.ingestMonitor.GetBatchDetail(e.Request.Context(), batchRunID)
@@ -654,11 +654,11 @@ func (h *Hub) handleIngestMonitorBatchError(e *core.RequestEvent, logMessage str
 	var cfgErr *ingestMonitorConfigError
 	switch {
 	case errors.As(err, &cfgErr):
-		return e.JSON(http.StatusServiceUnavailable, map[string]string{"error": cfgErr.Error()})
+		return respondError(e, http.StatusServiceUnavailable, cfgErr.Error())
 	case errors.Is(err, sql.ErrNoRows):
-		return e.JSON(http.StatusNotFound, map[string]string{"error": "未找到对应的入库批次"})
+		return respondError(e, http.StatusNotFound, "未找到对应的入库批次")
 	default:
 		h.Logger().Error(logMessage, "logger", "hub", "err", err)
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": "查询入库批次失败"})
+		return respondError(e, http.StatusInternalServerError, "查询入库批次失败")
 	}
 }
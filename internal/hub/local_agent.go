@@ -83,7 +83,7 @@ func newLocalAgentController(h *Hub) *localAgentController {
 func (h *Hub) getLocalAgentStatus(e *core.RequestEvent) error {
 	resp, err := newLocalAgentController(h).status(e.Request)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	return e.JSON(http.StatusOK, resp)
 }
@@ -94,7 +94,7 @@ func (h *Hub) setupLocalAgent(e *core.RequestEvent) error {
 	}
 	req, err := decodeLocalAgentRequest(e.Request.Body)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	autoStart := true
 	if req.AutoStart != nil {
@@ -102,7 +102,7 @@ func (h *Hub) setupLocalAgent(e *core.RequestEvent) error {
 	}
 	resp, err := newLocalAgentController(h).setup(e.Request, e.Auth.Id, req.Name, autoStart)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	return e.JSON(http.StatusOK, resp)
 }
@@ -113,7 +113,7 @@ func (h *Hub) startLocalAgent(e *core.RequestEvent) error {
 	}
 	resp, err := newLocalAgentController(h).start(e.Request)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	return e.JSON(http.StatusOK, resp)
 }
@@ -124,7 +124,7 @@ func (h *Hub) stopLocalAgent(e *core.RequestEvent) error {
 	}
 	resp, err := newLocalAgentController(h).stop(e.Request)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	return e.JSON(http.StatusOK, resp)
 }
@@ -135,7 +135,7 @@ func (h *Hub) restartLocalAgent(e *core.RequestEvent) error {
 	}
 	resp, err := newLocalAgentController(h).restart(e.Request)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	return e.JSON(http.StatusOK, resp)
 }
@@ -143,7 +143,7 @@ func (h *Hub) restartLocalAgent(e *core.RequestEvent) error {
 func (h *Hub) getLocalAgentLogs(e *core.RequestEvent) error {
 	resp, err := newLocalAgentController(h).logs()
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	return e.JSON(http.StatusOK, resp)
 }
@@ -154,7 +154,7 @@ func (h *Hub) deleteLocalAgent(e *core.RequestEvent) error {
 	}
 	resp, err := newLocalAgentController(h).remove(e.Request)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	return e.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,124 @@
+// Package hub 提供错误日志与审计详情的敏感信息脱敏。
+// 默认遮盖 Authorization/Cookie 头、Bearer/Basic 凭据以及 password/secret/token 等字段的值；
+// REDACT_FIELDS（逗号分隔的字段名）与 REDACT_PATTERNS（正则的 JSON 数组，整段匹配被遮盖）追加规则。
+// logApiTestError、logDataCleanupError 与 recordDockerAudit 在写入前统一经过该脱敏。
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"aether/internal/common"
+)
+
+const logRedactedValue = common.RedactedValue
+
+// logRedactor 按顺序应用脱敏规则。
+type logRedactor struct {
+	rules []logRedactRule
+	// invalid 记录无法编译的自定义正则，启动时告警后忽略
+	invalid []string
+}
+
+type logRedactRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// newLogRedactor 以默认规则加上 extraFields 与 extraPatterns 构造脱敏器。
+// 默认字段名与匹配规则定义在 common 中，与 agent 的数据清理错误脱敏共用。
+func newLogRedactor(extraFields []string, extraPatterns []string) *logRedactor {
+	names := append(append([]string{}, common.DefaultRedactFields...), extraFields...)
+	r := &logRedactor{}
+	// key: value、key=value 与 "key":"value"，值可带 Bearer/Basic 前缀
+	r.rules = append(r.rules, logRedactRule{
+		pattern:     common.RedactFieldPattern(names),
+		replacement: "${1}" + logRedactedValue,
+	})
+	// 没有字段名的凭据，如错误信息中出现的 "Bearer eyJ..."
+	r.rules = append(r.rules, logRedactRule{
+		pattern:     common.RedactCredentialPattern,
+		replacement: "${1} " + logRedactedValue,
+	})
+	for _, raw := range extraPatterns {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(raw)
+		if err != nil {
+			r.invalid = append(r.invalid, raw)
+			continue
+		}
+		r.rules = append(r.rules, logRedactRule{pattern: pattern, replacement: logRedactedValue})
+	}
+	return r
+}
+
+// newLogRedactorFromEnv 读取 REDACT_FIELDS 与 REDACT_PATTERNS 构造脱敏器。
+func newLogRedactorFromEnv() *logRedactor {
+	var fields []string
+	if value, ok := GetEnv("REDACT_FIELDS"); ok {
+		fields = strings.Split(value, ",")
+	}
+	var patterns []string
+	if value, ok := GetEnv("REDACT_PATTERNS"); ok && strings.TrimSpace(value) != "" {
+		if err := json.Unmarshal([]byte(value), &patterns); err != nil {
+			r := newLogRedactor(fields, nil)
+			r.invalid = append(r.invalid, value)
+			return r
+		}
+	}
+	return newLogRedactor(fields, patterns)
+}
+
+// redact 返回遮盖敏感内容后的文本。nil 脱敏器原样返回。
+func (r *logRedactor) redact(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	for _, rule := range r.rules {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+	return text
+}
+
+// redactFields 脱敏 slog 风格的键值对参数：字符串、error 与其他复合值按文本脱敏，数值与布尔值保持原样。
+// 键名本身命中敏感字段时整体遮盖。
+func (r *logRedactor) redactFields(fields []any) []any {
+	if r == nil {
+		return fields
+	}
+	redacted := make([]any, len(fields))
+	for index, value := range fields {
+		if index%2 == 0 {
+			redacted[index] = value
+			continue
+		}
+		if key, ok := fields[index-1].(string); ok && r.sensitiveKey(key) {
+			redacted[index] = logRedactedValue
+			continue
+		}
+		switch v := value.(type) {
+		case nil, bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+			redacted[index] = v
+		case string:
+			redacted[index] = r.redact(v)
+		case error:
+			redacted[index] = r.redact(v.Error())
+		default:
+			// %+v 输出结构体字段名，使结构体中的 Password 等字段同样能被规则命中
+			redacted[index] = r.redact(fmt.Sprintf("%+v", v))
+		}
+	}
+	return redacted
+}
+
+// sensitiveKey 判断日志键名是否为敏感字段，复用第一条规则的字段名匹配。
+func (r *logRedactor) sensitiveKey(key string) bool {
+	if len(r.rules) == 0 {
+		return false
+	}
+	return r.rules[0].pattern.MatchString(key + "=x")
+}
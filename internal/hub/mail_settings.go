@@ -342,7 +342,7 @@ func (h *Hub) respondMailSettingsError(e *core.RequestEvent, status int, context
 	}
 	formatted := formatMailSettingsError(context, err, fields)
 	h.logMailSettingsError(context, formatted, "status", status)
-	return e.JSON(status, map[string]string{"error": formatted.Error()})
+	return respondError(e, status, formatted.Error())
 }
 
 func (h *Hub) logMailSettingsError(message string, err error, fields ...any) {
@@ -27,7 +27,7 @@ func (h *Hub) GetNotificationSettings(e *core.RequestEvent) error {
 	record, err := alerts.GetOrCreateNotificationSettings(h)
 	if err != nil {
 		h.logNotificationSettingsError("读取通知设置失败", err, map[string]any{"action": "get"})
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("读取通知设置失败: %v", err)})
+		return respondError(e, http.StatusInternalServerError, fmt.Sprintf("读取通知设置失败: %v", err))
 	}
 	return e.JSON(http.StatusOK, notificationSettingsResponse{Language: record.GetString("language")})
 }
@@ -36,28 +36,28 @@ func (h *Hub) UpdateNotificationSettings(e *core.RequestEvent) error {
 	var payload notificationSettingsUpdateRequest
 	if err := decodeNotificationSettingsBody(e, &payload); err != nil {
 		h.logNotificationSettingsError("解析通知设置失败", err, map[string]any{"action": "update"})
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("解析通知设置失败: %v", err)})
+		return respondError(e, http.StatusBadRequest, fmt.Sprintf("解析通知设置失败: %v", err))
 	}
 	languageRaw := strings.TrimSpace(payload.Language)
 	if languageRaw == "" {
 		err := errors.New("language is required")
 		h.logNotificationSettingsError("通知语言缺失", err, map[string]any{"action": "update"})
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "通知语言不能为空"})
+		return respondError(e, http.StatusBadRequest, "通知语言不能为空")
 	}
 	language, err := alerts.ParseNotificationLanguage(languageRaw)
 	if err != nil {
 		h.logNotificationSettingsError("通知语言非法", err, map[string]any{"action": "update", "language": languageRaw})
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("通知语言非法: %s", languageRaw)})
+		return respondError(e, http.StatusBadRequest, fmt.Sprintf("通知语言非法: %s", languageRaw))
 	}
 	record, err := alerts.GetOrCreateNotificationSettings(h)
 	if err != nil {
 		h.logNotificationSettingsError("读取通知设置失败", err, map[string]any{"action": "update", "language": language})
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("读取通知设置失败: %v", err)})
+		return respondError(e, http.StatusInternalServerError, fmt.Sprintf("读取通知设置失败: %v", err))
 	}
 	record.Set("language", string(language))
 	if err := h.Save(record); err != nil {
 		h.logNotificationSettingsError("保存通知设置失败", err, map[string]any{"action": "update", "language": language})
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("保存通知设置失败: %v", err)})
+		return respondError(e, http.StatusInternalServerError, fmt.Sprintf("保存通知设置失败: %v", err))
 	}
 	h.Logger().Info("通知语言设置已更新", "logger", "hub", "language", language, "user", e.Auth.Id)
 	return e.JSON(http.StatusOK, notificationSettingsResponse{Language: string(language)})
@@ -184,17 +184,17 @@ func (h *Hub) downloadOfflineLicenseCollector(e *core.RequestEvent) error {
 func (h *Hub) previewOfflineLicenseActivation(e *core.RequestEvent) error {
 	body, err := io.ReadAll(e.Request.Body)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 
 	envelope, err := parseOfflineActivationImportBody(body)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 
 	prepared, err := h.prepareOfflineActivation(envelope)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 
 	preview := offlineLicenseActivationPreviewResponse{
@@ -207,7 +207,7 @@ func (h *Hub) previewOfflineLicenseActivation(e *core.RequestEvent) error {
 	if prepared.ExistingRecord != nil {
 		existing, buildErr := h.buildOfflineLicenseActivationRecord(prepared.ExistingRecord)
 		if buildErr != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": buildErr.Error()})
+			return respondError(e, http.StatusInternalServerError, buildErr.Error())
 		}
 		preview.ExistingActivation = &existing
 	}
@@ -218,25 +218,25 @@ func (h *Hub) previewOfflineLicenseActivation(e *core.RequestEvent) error {
 func (h *Hub) importOfflineLicenseActivation(e *core.RequestEvent) error {
 	body, err := io.ReadAll(e.Request.Body)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 
 	envelope, err := parseOfflineActivationImportBody(body)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	if strings.TrimSpace(envelope.Customer) == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "customer is required"})
+		return respondError(e, http.StatusBadRequest, "customer is required")
 	}
 
 	prepared, err := h.prepareOfflineActivation(envelope)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 
 	collection, err := h.FindCollectionByNameOrId(offlineLicenseActivationsCollection)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	var record *core.Record
@@ -267,12 +267,12 @@ func (h *Hub) importOfflineLicenseActivation(e *core.RequestEvent) error {
 	}
 
 	if err := h.Save(record); err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	activationRecord, err := h.buildOfflineLicenseActivationRecord(record)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	return e.JSON(http.StatusOK, map[string]any{
@@ -290,7 +290,7 @@ func (h *Hub) getOfflineLicenseOverview(e *core.RequestEvent) error {
 	signingState := inspectOfflineLicenseSigningState()
 	ready, err := h.areOfflineLicenseCollectionsReady()
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if !ready {
 		return e.JSON(http.StatusOK, offlineLicenseOverviewResponse{
@@ -302,7 +302,7 @@ func (h *Hub) getOfflineLicenseOverview(e *core.RequestEvent) error {
 
 	activations, err := h.listOfflineLicenseActivations()
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	return e.JSON(http.StatusOK, offlineLicenseOverviewResponse{
@@ -315,39 +315,39 @@ func (h *Hub) getOfflineLicenseOverview(e *core.RequestEvent) error {
 func (h *Hub) issueOfflineLicense(e *core.RequestEvent) error {
 	var payload offlineLicenseIssueRequest
 	if err := json.NewDecoder(e.Request.Body).Decode(&payload); err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		return respondError(e, http.StatusBadRequest, "invalid body")
 	}
 
 	payload.ActivationID = strings.TrimSpace(payload.ActivationID)
 	payload.Customer = strings.TrimSpace(payload.Customer)
 	payload.Tenant = strings.TrimSpace(payload.Tenant)
 	if payload.ActivationID == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "activationId is required"})
+		return respondError(e, http.StatusBadRequest, "activationId is required")
 	}
 
 	notBefore, err := normalizeOfflineLicenseTime(payload.NotBefore)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	notAfter, err := normalizeOfflineLicenseTime(payload.NotAfter)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 	if notBefore != "" && notAfter != "" && notBefore > notAfter {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "notBefore must be earlier than notAfter"})
+		return respondError(e, http.StatusBadRequest, "notBefore must be earlier than notAfter")
 	}
 
 	activationRecord, err := h.FindRecordById(offlineLicenseActivationsCollection, payload.ActivationID)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "activation not found"})
+		return respondError(e, http.StatusBadRequest, "activation not found")
 	}
 	if status := strings.TrimSpace(activationRecord.GetString("status")); status == "disabled" || status == "revoked" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "activation is disabled"})
+		return respondError(e, http.StatusBadRequest, "activation is disabled")
 	}
 
 	var activation offlineActivationRequest
 	if err := activationRecord.UnmarshalJSONField("activation_payload", &activation); err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("decode activation payload failed: %v", err)})
+		return respondError(e, http.StatusInternalServerError, fmt.Sprintf("decode activation payload failed: %v", err))
 	}
 
 	customer := strings.TrimSpace(payload.Customer)
@@ -359,25 +359,25 @@ func (h *Hub) issueOfflineLicense(e *core.RequestEvent) error {
 		tenant = strings.TrimSpace(activationRecord.GetString("tenant"))
 	}
 	if customer == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "customer is required"})
+		return respondError(e, http.StatusBadRequest, "customer is required")
 	}
 
 	signingState := inspectOfflineLicenseSigningState()
 	if !signingState.Ready {
-		return e.JSON(http.StatusPreconditionFailed, map[string]string{"error": strings.Join(signingState.Errors, "; ")})
+		return respondError(e, http.StatusPreconditionFailed, strings.Join(signingState.Errors, "; "))
 	}
 
 	signingKey, err := loadOfflineLicenseSigningKey()
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	manifest, err := loadOfflineLicenseManifest()
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	models, err := selectOfflineLicenseModels(manifest, payload.ModelNames)
 	if err != nil {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusBadRequest, err.Error())
 	}
 
 	devicePublicKeyPEM := activationRecord.GetString("device_public_key_pem")
@@ -385,7 +385,7 @@ func (h *Hub) issueOfflineLicense(e *core.RequestEvent) error {
 	for _, model := range models {
 		wrappedKey, wrapErr := wrapOfflineLicenseModelKey(devicePublicKeyPEM, model.KeyB64, model.Name)
 		if wrapErr != nil {
-			return e.JSON(http.StatusInternalServerError, map[string]string{"error": wrapErr.Error()})
+			return respondError(e, http.StatusInternalServerError, wrapErr.Error())
 		}
 		licenseModels = append(licenseModels, offlineLicensedModelEntry{
 			Name:         model.Name,
@@ -416,16 +416,16 @@ func (h *Hub) issueOfflineLicense(e *core.RequestEvent) error {
 	}
 	signature, err := signOfflineLicensePayload(licensePayload, signingKey)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	licensePayloadRaw, err := toOfflineLicenseJSONRaw(licensePayload)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	modelsRaw, err := toOfflineLicenseJSONRaw(licenseModels)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	licenseID := fmt.Sprintf("%s-%d", activationRecord.Id, time.Now().UTC().UnixNano())
@@ -442,7 +442,7 @@ func (h *Hub) issueOfflineLicense(e *core.RequestEvent) error {
 	activationRecord.Set("current_license_payload", licensePayloadRaw)
 	activationRecord.Set("current_license_signature", signature)
 	if err := h.SaveNoValidate(activationRecord); err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	return e.JSON(http.StatusOK, map[string]any{
@@ -457,7 +457,7 @@ func (h *Hub) issueOfflineLicense(e *core.RequestEvent) error {
 func (h *Hub) exportOfflineLicense(e *core.RequestEvent) error {
 	licenseID := strings.TrimSpace(e.Request.URL.Query().Get("licenseId"))
 	if licenseID == "" {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "licenseId is required"})
+		return respondError(e, http.StatusBadRequest, "licenseId is required")
 	}
 
 	records, err := h.FindRecordsByFilter(
@@ -469,16 +469,16 @@ func (h *Hub) exportOfflineLicense(e *core.RequestEvent) error {
 		dbx.Params{"license_id": licenseID},
 	)
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 	if len(records) == 0 {
-		return e.JSON(http.StatusBadRequest, map[string]string{"error": "license not found"})
+		return respondError(e, http.StatusBadRequest, "license not found")
 	}
 	record := records[0]
 
 	var payload offlineLicensePayload
 	if err := record.UnmarshalJSONField("current_license_payload", &payload); err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("decode license payload failed: %v", err)})
+		return respondError(e, http.StatusInternalServerError, fmt.Sprintf("decode license payload failed: %v", err))
 	}
 	document := offlineLicenseDocument{
 		Payload:   payload,
@@ -486,7 +486,7 @@ func (h *Hub) exportOfflineLicense(e *core.RequestEvent) error {
 	}
 	encoded, err := json.MarshalIndent(document, "", "  ")
 	if err != nil {
-		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return respondError(e, http.StatusInternalServerError, err.Error())
 	}
 
 	response := offlineLicenseExportResponse{
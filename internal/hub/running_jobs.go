@@ -0,0 +1,188 @@
+// Package hub 提供运行中任务的统一登记与取消接口。
+// 接口测试批量执行、数据清理任务与耗时的 Docker 操作在执行期间登记到内存注册表，
+// 便于在一处查看正在执行的任务，并按 id 取消支持取消的任务。
+package hub
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	runningJobTypeApiTest     = "api_test"
+	runningJobTypeDataCleanup = "data_cleanup"
+	runningJobTypeDocker      = "docker"
+)
+
+// runningJob is an in-flight operation. cancel is nil for operations that cannot be interrupted.
+type runningJob struct {
+	id        string
+	jobType   string
+	subject   string
+	system    string // optional; restricts visibility to users with access to the system
+	startedAt time.Time
+	progress  atomic.Int32 // 0-100, -1 when unknown
+	cancel    context.CancelFunc
+	cancelled atomic.Bool
+}
+
+type runningJobItem struct {
+	Id          string `json:"id"`
+	Type        string `json:"type"`
+	Subject     string `json:"subject"`
+	System      string `json:"system,omitempty"`
+	StartedAt   string `json:"startedAt"`
+	Progress    *int   `json:"progress,omitempty"`
+	Cancellable bool   `json:"cancellable"`
+	Cancelled   bool   `json:"cancelled"`
+}
+
+type runningJobCancelPayload struct {
+	Id string `json:"id"`
+}
+
+// runningJobRegistry is the central in-memory registry of running operations keyed by id.
+type runningJobRegistry struct {
+	mu   sync.Mutex
+	jobs map[string]*runningJob
+}
+
+func newRunningJobRegistry() *runningJobRegistry {
+	return &runningJobRegistry{jobs: make(map[string]*runningJob)}
+}
+
+// start registers an operation and returns it with the context the operation should observe.
+// An empty id generates one. The caller must call finish when the operation ends.
+func (r *runningJobRegistry) start(id string, jobType string, subject string, systemID string, cancellable bool) (*runningJob, context.Context) {
+	if id == "" {
+		id = jobType + ":" + uuid.NewString()
+	}
+	ctx := context.Background()
+	job := &runningJob{
+		id:        id,
+		jobType:   jobType,
+		subject:   subject,
+		system:    systemID,
+		startedAt: time.Now(),
+	}
+	if cancellable {
+		ctx, job.cancel = context.WithCancel(ctx)
+	}
+	job.progress.Store(-1)
+	r.mu.Lock()
+	r.jobs[id] = job
+	r.mu.Unlock()
+	return job, ctx
+}
+
+// finish removes the operation from the registry and releases its context.
+func (r *runningJobRegistry) finish(job *runningJob) {
+	r.mu.Lock()
+	if r.jobs[job.id] == job {
+		delete(r.jobs, job.id)
+	}
+	r.mu.Unlock()
+	if job.cancel != nil {
+		job.cancel()
+	}
+}
+
+// setProgress records completion as done out of total. A nil job is ignored.
+func (job *runningJob) setProgress(done int, total int) {
+	if job == nil || total <= 0 {
+		return
+	}
+	job.progress.Store(int32(min(done*100/total, 100)))
+}
+
+func (r *runningJobRegistry) list() []runningJobItem {
+	r.mu.Lock()
+	items := make([]runningJobItem, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		item := runningJobItem{
+			Id:          job.id,
+			Type:        job.jobType,
+			Subject:     job.subject,
+			System:      job.system,
+			StartedAt:   job.startedAt.UTC().Format(time.RFC3339),
+			Cancellable: job.cancel != nil,
+			Cancelled:   job.cancelled.Load(),
+		}
+		if progress := int(job.progress.Load()); progress >= 0 {
+			item.Progress = &progress
+		}
+		items = append(items, item)
+	}
+	r.mu.Unlock()
+	sort.Slice(items, func(i, j int) bool { return items[i].StartedAt < items[j].StartedAt })
+	return items
+}
+
+func (r *runningJobRegistry) get(id string) *runningJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.jobs[id]
+}
+
+// requestCancel cancels the job's context. It reports false when the job cannot be cancelled.
+func (job *runningJob) requestCancel() bool {
+	if job.cancel == nil {
+		return false
+	}
+	job.cancelled.Store(true)
+	job.cancel()
+	return true
+}
+
+// listRunningJobs handles GET /api/aether/jobs/running requests.
+// Jobs bound to a system are only listed for users who can access that system.
+func (h *Hub) listRunningJobs(e *core.RequestEvent) error {
+	all := h.jobs.list()
+	items := make([]runningJobItem, 0, len(all))
+	for _, item := range all {
+		if item.System != "" {
+			if _, err := h.resolveSystemRecordForUser(e, item.System); err != nil {
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	return e.JSON(http.StatusOK, map[string]any{"items": items})
+}
+
+// cancelRunningJob handles POST /api/aether/jobs/cancel requests.
+// Cancellation is cooperative: the operation stops at its next checkpoint.
+func (h *Hub) cancelRunningJob(e *core.RequestEvent) error {
+	if err := requireWritable(e); err != nil {
+		return err
+	}
+	var payload runningJobCancelPayload
+	if err := apiTestParseBody(e, &payload); err != nil {
+		return respondError(e, http.StatusBadRequest, "invalid body")
+	}
+	id := strings.TrimSpace(payload.Id)
+	if id == "" {
+		return respondError(e, http.StatusBadRequest, "id is required")
+	}
+	job := h.jobs.get(id)
+	if job == nil {
+		return respondError(e, http.StatusNotFound, "job not found")
+	}
+	if job.system != "" {
+		if _, err := h.resolveSystemRecordForUser(e, job.system); err != nil {
+			return respondSystemAccessError(e, err)
+		}
+	}
+	if !job.requestCancel() {
+		return respondError(e, http.StatusConflict, "job cannot be cancelled")
+	}
+	return e.JSON(http.StatusOK, map[string]any{"status": "ok", "id": id})
+}
@@ -0,0 +1,150 @@
+// Package hub 提供系统连接健康接口。
+// 汇总 WebSocket 心跳指标（最近 RTT、连续未响应 pong、重连次数）与当前传输方式，便于排查不稳定的 WS 链路；
+// 同时返回数据清理任务的并发槽位、排队数量与定时调度暂停状态，以及按状态/传输方式聚合的连接统计（支持 Prometheus 文本格式）。
+package hub
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"aether/internal/hub/systems"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type systemHealthItem struct {
+	Id            string  `json:"id"`
+	Name          string  `json:"name"`
+	Status        string  `json:"status"`
+	Transport     string  `json:"transport"`
+	ConnectedAt   string  `json:"connectedAt,omitempty"`
+	LastPingAt    string  `json:"lastPingAt,omitempty"`
+	LastPongAt    string  `json:"lastPongAt,omitempty"`
+	LastPingRTTMs float64 `json:"lastPingRttMs"`
+	MissedPongs   int     `json:"missedPongs"`
+	Reconnects    int     `json:"reconnects"`
+	Compression   bool    `json:"compression"`
+	PayloadBytes  int64   `json:"payloadBytesIn"`
+	WireBytes     int64   `json:"wireBytesIn"`
+	BytesSaved    int64   `json:"bytesSaved"`
+}
+
+type systemHealthResponse struct {
+	MissedPongThreshold int                   `json:"missedPongThreshold"`
+	CleanupQueue        dataCleanupQueueStats `json:"cleanupQueue"`
+	// CleanupScheduler is omitted when the pause state cannot be loaded
+	CleanupScheduler *dataCleanupSchedulerState `json:"cleanupScheduler,omitempty"`
+	Items            []systemHealthItem         `json:"items"`
+}
+
+func formatHealthTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// getSystemsHealth handles GET /api/aether/systems/health requests.
+// Systems not currently tracked by the system manager are reported with transport "none".
+func (h *Hub) getSystemsHealth(e *core.RequestEvent) error {
+	records, err := h.listAccessibleSystemRecords(e)
+	if err != nil {
+		return respondSystemAccessError(e, err)
+	}
+
+	items := make([]systemHealthItem, 0, len(records))
+	for _, record := range records {
+		item := systemHealthItem{
+			Id:        record.Id,
+			Name:      record.GetString("name"),
+			Status:    record.GetString("status"),
+			Transport: "none",
+		}
+		if sys, err := h.sm.GetSystem(record.Id); err == nil {
+			health := sys.ConnectionHealth()
+			item.Transport = health.Transport
+			item.Reconnects = health.Reconnects
+			item.ConnectedAt = formatHealthTime(health.Heartbeat.ConnectedAt)
+			item.LastPingAt = formatHealthTime(health.Heartbeat.LastPingAt)
+			item.LastPongAt = formatHealthTime(health.Heartbeat.LastPongAt)
+			item.LastPingRTTMs = float64(health.Heartbeat.LastPingRTT.Microseconds()) / 1000
+			item.MissedPongs = health.Heartbeat.MissedPongs
+			item.Compression = health.Compression.Enabled
+			item.PayloadBytes = health.Compression.PayloadBytesIn
+			item.WireBytes = health.Compression.WireBytesIn
+			item.BytesSaved = health.Compression.BytesSaved
+		}
+		items = append(items, item)
+	}
+	response := systemHealthResponse{
+		MissedPongThreshold: h.sm.WsMissedPongThreshold(),
+		CleanupQueue:        h.cleanupQueue.stats(),
+		Items:               items,
+	}
+	if state, err := h.dataCleanupSchedulerState(); err == nil {
+		response.CleanupScheduler = &state
+	} else {
+		h.logDataCleanupError("load cleanup scheduler state failed", err)
+	}
+	return e.JSON(http.StatusOK, response)
+}
+
+type systemConnectionStatsResponse struct {
+	Systems            int            `json:"systems"`
+	ByStatus           map[string]int `json:"byStatus"`
+	ByTransport        map[string]int `json:"byTransport"`
+	SSHClients         int            `json:"sshClients"`
+	Reconnects         int            `json:"reconnects"`
+	ReconnectsLast5m   int            `json:"reconnectsLast5m"`
+	ReconnectsLastHour int            `json:"reconnectsLastHour"`
+}
+
+// getSystemsConnectionStats handles GET /api/aether/systems/connections requests.
+// Counts cover every system in the manager, so the endpoint is limited to admins.
+// ?format=prometheus returns the same numbers in the Prometheus text exposition format.
+func (h *Hub) getSystemsConnectionStats(e *core.RequestEvent) error {
+	if e.Auth == nil || e.Auth.GetString("role") != "admin" {
+		return respondError(e, http.StatusForbidden, "admin role required")
+	}
+	stats := h.sm.ConnectionStats()
+	if e.Request.URL.Query().Get("format") == "prometheus" {
+		return e.String(http.StatusOK, formatConnectionStatsPrometheus(stats))
+	}
+	return e.JSON(http.StatusOK, systemConnectionStatsResponse{
+		Systems:            stats.Systems,
+		ByStatus:           stats.ByStatus,
+		ByTransport:        stats.ByTransport,
+		SSHClients:         stats.SSHClients,
+		Reconnects:         stats.Reconnects,
+		ReconnectsLast5m:   stats.ReconnectsLast5m,
+		ReconnectsLastHour: stats.ReconnectsLastHour,
+	})
+}
+
+func formatConnectionStatsPrometheus(stats systems.ConnectionStats) string {
+	var b strings.Builder
+	writeLabeled := func(name, help, label string, values map[string]int) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, "%s{%s=%q} %d\n", name, label, key, values[key])
+		}
+	}
+	writeValue := func(name, help, metricType string, value int) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, metricType, name, value)
+	}
+	writeLabeled("aether_systems_by_status", "Systems held by the hub grouped by status.", "status", stats.ByStatus)
+	writeLabeled("aether_systems_by_transport", "Systems grouped by the transport used to reach the agent.", "transport", stats.ByTransport)
+	writeValue("aether_ssh_clients_active", "Open SSH clients to agents.", "gauge", stats.SSHClients)
+	writeValue("aether_ws_reconnects_total", "WebSocket agent reconnects since hub start.", "counter", stats.Reconnects)
+	writeValue("aether_ws_reconnects_last_5m", "WebSocket agent reconnects in the last 5 minutes.", "gauge", stats.ReconnectsLast5m)
+	writeValue("aether_ws_reconnects_last_hour", "WebSocket agent reconnects in the last hour.", "gauge", stats.ReconnectsLastHour)
+	return b.String()
+}
@@ -0,0 +1,90 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aether/internal/hub/systems"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSystemsConnectionStats(t *testing.T) {
+	hub, testApp, err := createTestHub(t)
+	require.NoError(t, err)
+	defer testApp.Cleanup()
+	user, err := createTestUser(testApp)
+	require.NoError(t, err)
+	admin, err := createTestRecord(testApp, "users", map[string]any{
+		"email":    "admin@test.com",
+		"password": "testtesttest",
+		"role":     "admin",
+	})
+	require.NoError(t, err)
+
+	call := func(auth *core.Record, target string) (*httptest.ResponseRecorder, error) {
+		recorder := httptest.NewRecorder()
+		e := &core.RequestEvent{App: testApp, Auth: auth}
+		e.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		e.Response = recorder
+		return recorder, hub.getSystemsConnectionStats(e)
+	}
+
+	recorder, _ := call(user, "/api/aether/systems/connections")
+	assert.Equal(t, http.StatusForbidden, recorder.Code, "counts cover every system, so only admins may read them")
+
+	recorder, err = call(admin, "/api/aether/systems/connections")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var response systemConnectionStatsResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Zero(t, response.Systems)
+	assert.Equal(t, map[string]int{"up": 0, "down": 0, "paused": 0, "pending": 0}, response.ByStatus)
+	assert.Equal(t, map[string]int{"websocket": 0, "ssh": 0, "none": 0}, response.ByTransport)
+
+	recorder, err = call(admin, "/api/aether/systems/connections?format=prometheus")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "# TYPE aether_systems_by_status gauge\n")
+}
+
+func TestFormatConnectionStatsPrometheus(t *testing.T) {
+	text := formatConnectionStatsPrometheus(systems.ConnectionStats{
+		Systems:            3,
+		ByStatus:           map[string]int{"up": 2, "down": 1},
+		ByTransport:        map[string]int{"websocket": 1, "ssh": 1, "none": 1},
+		SSHClients:         1,
+		Reconnects:         7,
+		ReconnectsLast5m:   1,
+		ReconnectsLastHour: 4,
+	})
+	assert.Equal(t, `# HELP aether_systems_by_status Systems held by the hub grouped by status.
+# TYPE aether_systems_by_status gauge
+aether_systems_by_status{status="down"} 1
+aether_systems_by_status{status="up"} 2
+# HELP aether_systems_by_transport Systems grouped by the transport used to reach the agent.
+# TYPE aether_systems_by_transport gauge
+aether_systems_by_transport{transport="none"} 1
+aether_systems_by_transport{transport="ssh"} 1
+aether_systems_by_transport{transport="websocket"} 1
+# HELP aether_ssh_clients_active Open SSH clients to agents.
+# TYPE aether_ssh_clients_active gauge
+aether_ssh_clients_active 1
+# HELP aether_ws_reconnects_total WebSocket agent reconnects since hub start.
+# TYPE aether_ws_reconnects_total counter
+aether_ws_reconnects_total 7
+# HELP aether_ws_reconnects_last_5m WebSocket agent reconnects in the last 5 minutes.
+# TYPE aether_ws_reconnects_last_5m gauge
+aether_ws_reconnects_last_5m 1
+# HELP aether_ws_reconnects_last_hour WebSocket agent reconnects in the last hour.
+# TYPE aether_ws_reconnects_last_hour gauge
+aether_ws_reconnects_last_hour 4
+`, text)
+}
@@ -0,0 +1,175 @@
+// Package hub 提供主机存活批量探测接口。
+// 对当前用户可访问的全部主机并发执行一次轻量探测（WebSocket ping 或 SSH keepalive），不采集任何指标，
+// 用于仪表盘顶部的整体可达性指示。并发数与总耗时均有上限，超过总耗时仍未返回的主机标记为 timeout，其余结果照常返回。
+package hub
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	systemProbeConcurrency   = 16
+	systemProbeTotalTimeout  = 5 * time.Second
+	systemProbeDefaultSlowMs = 500
+	systemProbeMaxSlowMs     = 5000
+)
+
+const (
+	systemProbeUp      = "up"
+	systemProbeSlow    = "slow"
+	systemProbeDown    = "down"
+	systemProbeTimeout = "timeout"
+	systemProbePaused  = "paused"
+)
+
+type systemProbeItem struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Transport string  `json:"transport,omitempty"`
+	RTTMs     float64 `json:"rttMs,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type systemProbeResponse struct {
+	// Items is keyed by system id
+	Items      map[string]systemProbeItem `json:"items"`
+	Counts     map[string]int             `json:"counts"`
+	SlowMs     int                        `json:"slowMs"`
+	Partial    bool                       `json:"partial"`
+	DurationMs int64                      `json:"durationMs"`
+}
+
+// systemProbeFunc probes one system and returns the transport used and the round trip.
+type systemProbeFunc func(ctx context.Context, systemID string) (string, time.Duration, error)
+
+type systemProbeResult struct {
+	transport string
+	rtt       time.Duration
+	err       error
+}
+
+// probeSystems handles GET /api/aether/systems/probe requests.
+// Optional query param `slowMs` sets the RTT above which a reachable system is reported as slow.
+func (h *Hub) probeSystems(e *core.RequestEvent) error {
+	slowMs := systemProbeDefaultSlowMs
+	if raw := strings.TrimSpace(e.Request.URL.Query().Get("slowMs")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > systemProbeMaxSlowMs {
+			return respondError(e, http.StatusBadRequest, "slowMs must be between 1 and "+strconv.Itoa(systemProbeMaxSlowMs))
+		}
+		slowMs = parsed
+	}
+	records, err := h.listAccessibleSystemRecords(e)
+	if err != nil {
+		return respondSystemAccessError(e, err)
+	}
+
+	start := time.Now()
+	response := systemProbeResponse{
+		Items:  make(map[string]systemProbeItem, len(records)),
+		Counts: make(map[string]int),
+		SlowMs: slowMs,
+	}
+	targets := make([]string, 0, len(records))
+	for _, record := range records {
+		item := systemProbeItem{Name: record.GetString("name")}
+		if record.GetString("status") == systemProbePaused {
+			item.Status = systemProbePaused
+		} else {
+			targets = append(targets, record.Id)
+		}
+		response.Items[record.Id] = item
+	}
+
+	ctx, cancel := context.WithTimeout(e.Request.Context(), systemProbeTotalTimeout)
+	defer cancel()
+	results := runSystemProbes(ctx, targets, h.probeSystem, systemProbeConcurrency)
+	for _, id := range targets {
+		item := response.Items[id]
+		result, ok := results[id]
+		switch {
+		case !ok:
+			item.Status = systemProbeTimeout
+			response.Partial = true
+		case result.err != nil:
+			item.Transport = result.transport
+			item.Status = systemProbeDown
+			item.Error = result.err.Error()
+			if errors.Is(result.err, context.DeadlineExceeded) {
+				item.Status = systemProbeTimeout
+				response.Partial = true
+			}
+		default:
+			item.Transport = result.transport
+			item.RTTMs = float64(result.rtt.Microseconds()) / 1000
+			item.Status = systemProbeUp
+			if result.rtt > time.Duration(slowMs)*time.Millisecond {
+				item.Status = systemProbeSlow
+			}
+		}
+		response.Items[id] = item
+	}
+	for _, item := range response.Items {
+		response.Counts[item.Status]++
+	}
+	response.DurationMs = time.Since(start).Milliseconds()
+	return e.JSON(http.StatusOK, response)
+}
+
+// probeSystem probes a system tracked by the system manager.
+func (h *Hub) probeSystem(ctx context.Context, systemID string) (string, time.Duration, error) {
+	sys, err := h.sm.GetSystem(systemID)
+	if err != nil {
+		return "none", 0, err
+	}
+	return sys.Probe(ctx)
+}
+
+// runSystemProbes probes the targets with at most concurrency probes in flight and
+// returns once every probe finished or ctx ended. Targets that did not finish in time
+// are missing from the result.
+func runSystemProbes(ctx context.Context, targets []string, probe systemProbeFunc, concurrency int) map[string]systemProbeResult {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]systemProbeResult, len(targets))
+		slots   = make(chan struct{}, max(concurrency, 1))
+		done    = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		for _, id := range targets {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			}
+			wg.Go(func() {
+				defer func() { <-slots }()
+				transport, rtt, err := probe(ctx, id)
+				mu.Lock()
+				results[id] = systemProbeResult{transport: transport, rtt: rtt, err: err}
+				mu.Unlock()
+			})
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// copy so late probes cannot race with the caller
+	return maps.Clone(results)
+}
@@ -0,0 +1,54 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSystemProbes(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	probe := func(ctx context.Context, id string) (string, time.Duration, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		switch id {
+		case "hung":
+			<-ctx.Done()
+			// the result arrives after the sweep already returned
+			time.Sleep(20 * time.Millisecond)
+			return "ssh", 0, ctx.Err()
+		case "offline":
+			return "none", 0, errors.New("agent not connected")
+		default:
+			time.Sleep(5 * time.Millisecond)
+			return "websocket", 3 * time.Millisecond, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	results := runSystemProbes(ctx, []string{"a", "b", "c", "d", "hung", "offline"}, probe, 2)
+	assert.Less(t, time.Since(start), time.Second, "the sweep is bounded by ctx")
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+
+	require.Len(t, results, 5, "the hung probe is missing from the result")
+	assert.Equal(t, systemProbeResult{transport: "websocket", rtt: 3 * time.Millisecond}, results["a"])
+	assert.EqualError(t, results["offline"].err, "agent not connected")
+	_, ok := results["hung"]
+	assert.False(t, ok)
+}
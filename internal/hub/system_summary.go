@@ -0,0 +1,148 @@
+// Package hub 提供系统汇总（仪表盘）接口。
+// 以批量查询聚合系统状态、最新资源占用、容器数量、告警状态与关联的接口用例，减少前端请求次数。
+package hub
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"aether/internal/entities/system"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type systemSummaryItem struct {
+	Id              string   `json:"id"`
+	Name            string   `json:"name"`
+	Status          string   `json:"status"`
+	Tags            []string `json:"tags"`
+	Cpu             float64  `json:"cpu"`
+	MemPct          float64  `json:"mp"`
+	DiskPct         float64  `json:"dp"`
+	Containers      int      `json:"containers"`
+	TriggeredAlerts int      `json:"triggeredAlerts"`
+	ApiTests        int      `json:"apiTests"`
+	FailingApiTests int      `json:"failingApiTests"`
+	// ApiTestHealth 为关联用例的加权健康度（0-100），没有执行记录时省略
+	ApiTestHealth *float64 `json:"apiTestHealth,omitempty"`
+	Updated       string   `json:"updated"`
+}
+
+type systemSummaryResponse struct {
+	Items []systemSummaryItem `json:"items"`
+}
+
+type systemCountRow struct {
+	System string `db:"system"`
+	Total  int    `db:"total"`
+}
+
+// listAccessibleSystemRecords returns the systems visible to the request user,
+// honoring SHARE_ALL_SYSTEMS the same way resolveSystemRecordForUser does.
+func (h *Hub) listAccessibleSystemRecords(e *core.RequestEvent) ([]*core.Record, error) {
+	if shareAllSystems, _ := GetEnv("SHARE_ALL_SYSTEMS"); shareAllSystems == "true" {
+		return h.FindRecordsByFilter("systems", "", "name", -1, 0, nil)
+	}
+	if e.Auth == nil {
+		return nil, errSystemForbidden
+	}
+	return h.FindRecordsByFilter("systems", "users ?= {:user}", "name", -1, 0, dbx.Params{"user": e.Auth.Id})
+}
+
+// countBySystem runs a grouped COUNT(*) over the given table for the provided system ids.
+func (h *Hub) countBySystem(table string, systemIDs []any, where dbx.Expression) (map[string]int, error) {
+	result := make(map[string]int, len(systemIDs))
+	if len(systemIDs) == 0 {
+		return result, nil
+	}
+	query := h.DB().Select("system", "COUNT(*) AS total").
+		From(table).
+		Where(dbx.In("system", systemIDs...)).
+		GroupBy("system")
+	if where != nil {
+		query = query.AndWhere(where)
+	}
+	var rows []systemCountRow
+	if err := query.All(&rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.System] = row.Total
+	}
+	return result, nil
+}
+
+// getSystemsSummary handles GET /api/aether/systems/summary requests.
+// Optional query param `tag` restricts the result to systems carrying that tag.
+func (h *Hub) getSystemsSummary(e *core.RequestEvent) error {
+	tag := strings.TrimSpace(e.Request.URL.Query().Get("tag"))
+	records, err := h.listAccessibleSystemRecords(e)
+	if err != nil {
+		return respondSystemAccessError(e, err)
+	}
+
+	items := make([]systemSummaryItem, 0, len(records))
+	systemIDs := make([]any, 0, len(records))
+	for _, record := range records {
+		var tags []string
+		if err := record.UnmarshalJSONField("tags", &tags); err != nil {
+			return respondError(e, http.StatusInternalServerError, err.Error())
+		}
+		if tag != "" && !slices.Contains(tags, tag) {
+			continue
+		}
+		var info system.Info
+		if err := record.UnmarshalJSONField("info", &info); err != nil {
+			return respondError(e, http.StatusInternalServerError, err.Error())
+		}
+		items = append(items, systemSummaryItem{
+			Id:      record.Id,
+			Name:    record.GetString("name"),
+			Status:  record.GetString("status"),
+			Tags:    apiTestNormalizeStringList(tags),
+			Cpu:     info.Cpu,
+			MemPct:  info.MemPct,
+			DiskPct: info.DiskPct,
+			Updated: apiTestDateTimeString(record.GetDateTime("updated")),
+		})
+		systemIDs = append(systemIDs, record.Id)
+	}
+
+	containerCounts, err := h.countBySystem("containers", systemIDs, nil)
+	if err != nil {
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	alertFilter := dbx.HashExp{"triggered": true}
+	if e.Auth != nil {
+		alertFilter["user"] = e.Auth.Id
+	}
+	alertCounts, err := h.countBySystem("alerts", systemIDs, alertFilter)
+	if err != nil {
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	apiTestCounts, err := h.countBySystem(apiTestCasesCollection, systemIDs, nil)
+	if err != nil {
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	// 仅统计已执行过且最近一次失败的用例
+	failingApiTestCounts, err := h.countBySystem(apiTestCasesCollection, systemIDs, dbx.NewExp("last_success = FALSE AND last_run_at != ''"))
+	if err != nil {
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	apiTestHealth, err := h.apiTestSystemHealthScores(systemIDs)
+	if err != nil {
+		return respondError(e, http.StatusInternalServerError, err.Error())
+	}
+	for i := range items {
+		if score, ok := apiTestHealth[items[i].Id]; ok {
+			items[i].ApiTestHealth = &score
+		}
+		items[i].Containers = containerCounts[items[i].Id]
+		items[i].TriggeredAlerts = alertCounts[items[i].Id]
+		items[i].ApiTests = apiTestCounts[items[i].Id]
+		items[i].FailingApiTests = failingApiTestCounts[items[i].Id]
+	}
+	return e.JSON(http.StatusOK, systemSummaryResponse{Items: items})
+}
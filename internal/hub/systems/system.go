@@ -9,6 +9,7 @@ import (
 	"math/rand"
 	"net"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -49,6 +50,17 @@ type System struct {
 	smartFetching     atomic.Bool    // True if SMART devices are currently being fetched
 	smartInterval     time.Duration  // Interval for periodic SMART data updates
 	lastSmartFetch    atomic.Int64   // Unix milliseconds of last SMART data fetch
+	wsConnections     int            // Number of WebSocket connections accepted for this system since hub start
+	sshSessionsOnce   sync.Once
+	sshSessions       chan struct{} // Semaphore limiting concurrent sessions on the SSH client
+}
+
+// ConnectionHealth is a snapshot of how the hub currently reaches a system's agent.
+type ConnectionHealth struct {
+	Transport   string // websocket, ssh or none
+	Heartbeat   ws.HeartbeatMetrics
+	Compression ws.CompressionMetrics
+	Reconnects  int
 }
 
 func (sm *SystemManager) NewSystem(systemId string) *System {
@@ -161,7 +173,7 @@ func (sys *System) handlePaused() {
 		_ = sys.manager.RemoveSystem(sys.Id)
 	} else {
 		// Send a ping to the agent to keep the connection alive if the system is paused
-		if err := sys.WsConn.Ping(); err != nil {
+		if err := sys.checkWsHeartbeat(); err != nil {
 			sys.manager.hub.Logger().Warn("Failed to ping agent", "logger", "systems", "system", sys.Id, "err", err)
 			_ = sys.manager.RemoveSystem(sys.Id)
 		}
@@ -385,9 +397,13 @@ func (sys *System) fetchDataFromAgent(options common.DataRequestOptions) (*syste
 	}
 
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
-		wsData, err := sys.fetchDataViaWebSocket(options)
-		if err == nil {
-			return wsData, nil
+		if err := sys.checkWsHeartbeat(); err != nil {
+			sys.manager.hub.Logger().Warn("WebSocket heartbeat unhealthy, falling back to SSH", "logger", "systems", "system", sys.Id, "err", err)
+		} else {
+			wsData, err := sys.fetchDataViaWebSocket(options)
+			if err == nil {
+				return wsData, nil
+			}
 		}
 		// close the WebSocket connection if error and try SSH
 		sys.closeWebSocketConnection()
@@ -486,6 +502,57 @@ func (sys *System) FetchContainerInfoFromAgent(containerID string) (string, erro
 	return sys.fetchStringFromAgentViaSSH(common.GetContainerInfo, common.ContainerInfoRequest{ContainerID: containerID}, "no info in response")
 }
 
+// FetchContainerDiffFromAgent fetches container filesystem changes from the agent
+func (sys *System) FetchContainerDiffFromAgent(req common.ContainerDiffRequest) (docker.ContainerDiff, error) {
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return sys.WsConn.RequestContainerDiff(ctx, req)
+	}
+	resp, err := sys.fetchDockerResponseViaSSH(common.GetContainerDiff, req, 30*time.Second)
+	if err != nil {
+		return docker.ContainerDiff{}, err
+	}
+	if resp.DockerContainerDiff == nil {
+		return docker.ContainerDiff{}, errors.New("no container diff in response")
+	}
+	return *resp.DockerContainerDiff, nil
+}
+
+// FetchContainerStatsFromAgent fetches a live stats reading of one container from the agent
+func (sys *System) FetchContainerStatsFromAgent(containerID string) (docker.ContainerStats, error) {
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		return sys.WsConn.RequestContainerStats(ctx, containerID)
+	}
+	resp, err := sys.fetchDockerResponseViaSSH(common.GetContainerStats, common.ContainerStatsRequest{ContainerID: containerID}, 15*time.Second)
+	if err != nil {
+		return docker.ContainerStats{}, err
+	}
+	if resp.DockerContainerStats == nil {
+		return docker.ContainerStats{}, errors.New("no container stats in response")
+	}
+	return *resp.DockerContainerStats, nil
+}
+
+// ExecInContainerFromAgent runs a single command in a container on the agent and returns its output
+func (sys *System) ExecInContainerFromAgent(req common.ContainerExecRequest) (docker.ExecResult, error) {
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+		defer cancel()
+		return sys.WsConn.RequestContainerExec(ctx, req)
+	}
+	resp, err := sys.fetchDockerResponseViaSSH(common.ExecInContainer, req, 45*time.Second)
+	if err != nil {
+		return docker.ExecResult{}, err
+	}
+	if resp.DockerContainerExec == nil {
+		return docker.ExecResult{}, errors.New("no exec result in response")
+	}
+	return *resp.DockerContainerExec, nil
+}
+
 // FetchContainerLogsFromAgent fetches container logs from the agent
 func (sys *System) FetchContainerLogsFromAgent(containerID string) (string, error) {
 	// fetch via websocket
@@ -645,6 +712,34 @@ func (sys *System) RemoveDockerImageFromAgent(req common.DockerImageRemoveReques
 	return err
 }
 
+// PruneDockerImagesFromAgent removes dangling docker images on the agent.
+func (sys *System) PruneDockerImagesFromAgent(req common.DockerImagePruneRequest) (docker.ImagePruneReport, error) {
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		return sys.WsConn.RequestDockerImagePrune(ctx, req)
+	}
+	resp, err := sys.fetchDockerResponseViaSSH(common.PruneDockerImages, req, 60*time.Second)
+	if err != nil {
+		return docker.ImagePruneReport{}, err
+	}
+	if resp.DockerImagePrune == nil {
+		return docker.ImagePruneReport{}, errors.New("no image prune report in response")
+	}
+	return *resp.DockerImagePrune, nil
+}
+
+// LoginDockerRegistryFromAgent checks registry credentials by logging in from the agent.
+// Nothing is stored on the agent.
+func (sys *System) LoginDockerRegistryFromAgent(req common.DockerRegistryLoginRequest) (string, error) {
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		return sys.WsConn.RequestDockerRegistryLogin(ctx, req)
+	}
+	return sys.fetchStringFromAgentViaSSH(common.DockerRegistryLogin, req, "docker registry login failed")
+}
+
 // FetchDockerNetworksFromAgent fetches docker network list from the agent.
 func (sys *System) FetchDockerNetworksFromAgent() ([]docker.Network, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
@@ -744,6 +839,24 @@ func (sys *System) FetchDockerComposeProjectsFromAgent() ([]docker.ComposeProjec
 	return resp.DockerComposeProjects, nil
 }
 
+// FetchDockerComposeLogsFromAgent fetches interleaved compose project logs from the agent.
+// Cancelling ctx abandons a WebSocket request; SSH requests run to their own timeout.
+func (sys *System) FetchDockerComposeLogsFromAgent(ctx context.Context, req common.DockerComposeLogsRequest) (docker.ComposeLogs, error) {
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		return sys.WsConn.RequestDockerComposeLogs(ctx, req)
+	}
+	resp, err := sys.fetchDockerResponseViaSSH(common.GetDockerComposeLogs, req, 30*time.Second)
+	if err != nil {
+		return docker.ComposeLogs{}, err
+	}
+	if resp.DockerComposeLogs == nil {
+		return docker.ComposeLogs{}, errors.New("no compose logs in response")
+	}
+	return *resp.DockerComposeLogs, nil
+}
+
 // CreateDockerComposeProjectFromAgent creates a compose project on the agent.
 func (sys *System) CreateDockerComposeProjectFromAgent(req common.DockerComposeProjectCreateRequest) (string, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
@@ -935,6 +1048,12 @@ func (sys *System) fetchDataViaSSH(options common.DataRequestOptions) (*system.C
 // runSSHOperation establishes an SSH session and executes the provided operation.
 // The operation can request a retry by returning true as the first return value.
 func (sys *System) runSSHOperation(timeout time.Duration, retries int, operation func(*ssh.Session) (bool, error)) error {
+	release, err := sys.acquireSSHSession()
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	for attempt := 0; attempt <= retries; attempt++ {
 		if sys.client == nil || sys.Status == down {
 			if err := sys.createSSHClient(); err != nil {
@@ -974,6 +1093,29 @@ func (sys *System) runSSHOperation(timeout time.Duration, retries int, operation
 	return fmt.Errorf("ssh operation failed")
 }
 
+// acquireSSHSession blocks until a session slot on the system's SSH client is free,
+// so servers that cap sessions per connection don't reject concurrent operations.
+// The returned func releases the slot.
+func (sys *System) acquireSSHSession() (func(), error) {
+	sys.sshSessionsOnce.Do(func() {
+		limit := defaultSSHMaxSessions
+		if sys.manager != nil && sys.manager.sshMaxSessions > 0 {
+			limit = sys.manager.sshMaxSessions
+		}
+		sys.sshSessions = make(chan struct{}, limit)
+	})
+	var done <-chan struct{}
+	if sys.ctx != nil {
+		done = sys.ctx.Done()
+	}
+	select {
+	case sys.sshSessions <- struct{}{}:
+		return func() { <-sys.sshSessions }, nil
+	case <-done:
+		return nil, sys.ctx.Err()
+	}
+}
+
 // createSSHClient creates a new SSH client for the system
 func (s *System) createSSHClient() error {
 	if s.manager.sshConfig == nil {
@@ -1036,6 +1178,66 @@ func (sys *System) closeSSHConnection() {
 	}
 }
 
+// checkWsHeartbeat pings the agent and reports an error once the number of
+// consecutive unanswered pings reaches the manager's missed pong threshold.
+func (sys *System) checkWsHeartbeat() error {
+	if err := sys.WsConn.Ping(); err != nil {
+		return err
+	}
+	threshold := defaultWsMissedPongThreshold
+	if sys.manager != nil {
+		threshold = sys.manager.wsMissedPongThreshold
+	}
+	if missed := sys.WsConn.MissedPongs(); missed >= threshold {
+		return fmt.Errorf("missed %d consecutive pongs (threshold %d)", missed, threshold)
+	}
+	return nil
+}
+
+// ConnectionHealth returns the current transport and WebSocket heartbeat metrics.
+func (sys *System) ConnectionHealth() ConnectionHealth {
+	health := ConnectionHealth{Transport: "none"}
+	if sys.wsConnections > 1 {
+		health.Reconnects = sys.wsConnections - 1
+	}
+	if wsConn := sys.WsConn; wsConn != nil && wsConn.IsConnected() {
+		health.Transport = "websocket"
+		health.Heartbeat = wsConn.HeartbeatMetrics()
+		health.Compression = wsConn.CompressionMetrics()
+	} else if sys.client != nil {
+		health.Transport = "ssh"
+	}
+	return health
+}
+
+// Probe measures a cheap round trip to the agent without collecting any data: a
+// WebSocket ping when the agent is connected over WebSocket, otherwise a keepalive
+// request on the open SSH client. No new connection is dialed, so a system without
+// an open transport is reported as unreachable. It returns the transport used.
+func (sys *System) Probe(ctx context.Context) (string, time.Duration, error) {
+	if wsConn := sys.WsConn; wsConn != nil && wsConn.IsConnected() {
+		rtt, err := wsConn.ProbeRTT(ctx)
+		return "websocket", rtt, err
+	}
+	client := sys.client
+	if client == nil {
+		return "none", 0, errors.New("agent not connected")
+	}
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		// servers that do not know the request still reply, which is all we need
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return "ssh", time.Since(start), err
+	case <-ctx.Done():
+		return "ssh", 0, ctx.Err()
+	}
+}
+
 // closeWebSocketConnection closes the WebSocket connection but keeps the system in the manager
 // to allow updating via SSH. It will be removed if the WS connection is re-established.
 // The system will be set as down a few seconds later if the connection is not re-established.
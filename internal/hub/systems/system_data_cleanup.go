@@ -2,6 +2,7 @@ package systems
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -17,11 +18,11 @@ func (sys *System) FetchDataCleanupMySQLDatabasesFromAgent(
 	req common.DataCleanupMySQLDatabasesRequest,
 ) ([]string, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
-		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 		defer cancel()
 		return sys.WsConn.RequestDataCleanupMySQLDatabases(ctx, req)
 	}
-	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMySQLDatabases, req, dataCleanupListTimeout)
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMySQLDatabases, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -35,11 +36,11 @@ func (sys *System) FetchDataCleanupMySQLTablesFromAgent(
 	req common.DataCleanupMySQLTablesRequest,
 ) ([]string, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
-		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 		defer cancel()
 		return sys.WsConn.RequestDataCleanupMySQLTables(ctx, req)
 	}
-	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMySQLTables, req, dataCleanupListTimeout)
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMySQLTables, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -53,11 +54,11 @@ func (sys *System) CleanupMySQLTablesFromAgent(
 	req common.DataCleanupMySQLDeleteTablesRequest,
 ) (common.DockerDataCleanupResult, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
-		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupActionTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 		defer cancel()
 		return sys.WsConn.RequestDataCleanupMySQLDeleteTables(ctx, req)
 	}
-	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMySQLDeleteTables, req, dataCleanupActionTimeout)
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMySQLDeleteTables, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	if err != nil {
 		return common.DockerDataCleanupResult{}, err
 	}
@@ -71,11 +72,11 @@ func (sys *System) FetchDataCleanupRedisDatabasesFromAgent(
 	req common.DataCleanupRedisDatabasesRequest,
 ) ([]int, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
-		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 		defer cancel()
 		return sys.WsConn.RequestDataCleanupRedisDatabases(ctx, req)
 	}
-	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupRedisDatabases, req, dataCleanupListTimeout)
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupRedisDatabases, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -89,11 +90,11 @@ func (sys *System) CleanupRedisFromAgent(
 	req common.DataCleanupRedisCleanupRequest,
 ) (common.DockerDataCleanupResult, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
-		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupActionTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 		defer cancel()
 		return sys.WsConn.RequestDataCleanupRedisCleanup(ctx, req)
 	}
-	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupRedisCleanup, req, dataCleanupActionTimeout)
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupRedisCleanup, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	if err != nil {
 		return common.DockerDataCleanupResult{}, err
 	}
@@ -107,11 +108,11 @@ func (sys *System) FetchDataCleanupMinioBucketsFromAgent(
 	req common.DataCleanupMinioBucketsRequest,
 ) ([]string, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
-		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 		defer cancel()
 		return sys.WsConn.RequestDataCleanupMinioBuckets(ctx, req)
 	}
-	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMinioBuckets, req, dataCleanupListTimeout)
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMinioBuckets, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -125,11 +126,11 @@ func (sys *System) FetchDataCleanupMinioPrefixesFromAgent(
 	req common.DataCleanupMinioPrefixesRequest,
 ) ([]string, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
-		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 		defer cancel()
 		return sys.WsConn.RequestDataCleanupMinioPrefixes(ctx, req)
 	}
-	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMinioPrefixes, req, dataCleanupListTimeout)
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMinioPrefixes, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -143,11 +144,11 @@ func (sys *System) CleanupMinioFromAgent(
 	req common.DataCleanupMinioCleanupRequest,
 ) (common.DockerDataCleanupResult, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
-		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupActionTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 		defer cancel()
 		return sys.WsConn.RequestDataCleanupMinioCleanup(ctx, req)
 	}
-	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMinioCleanup, req, dataCleanupActionTimeout)
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMinioCleanup, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	if err != nil {
 		return common.DockerDataCleanupResult{}, err
 	}
@@ -161,11 +162,11 @@ func (sys *System) FetchDataCleanupESIndicesFromAgent(
 	req common.DataCleanupESIndicesRequest,
 ) ([]string, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
-		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 		defer cancel()
 		return sys.WsConn.RequestDataCleanupESIndices(ctx, req)
 	}
-	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupESIndices, req, dataCleanupListTimeout)
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupESIndices, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -179,11 +180,11 @@ func (sys *System) CleanupESFromAgent(
 	req common.DataCleanupESCleanupRequest,
 ) (common.DockerDataCleanupResult, error) {
 	if sys.WsConn != nil && sys.WsConn.IsConnected() {
-		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupActionTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 		defer cancel()
 		return sys.WsConn.RequestDataCleanupESCleanup(ctx, req)
 	}
-	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupESCleanup, req, dataCleanupActionTimeout)
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupESCleanup, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	if err != nil {
 		return common.DockerDataCleanupResult{}, err
 	}
@@ -210,3 +211,105 @@ func (sys *System) FetchDataCleanupJobStatusFromAgent(
 	}
 	return *resp.DataCleanupResult, nil
 }
+
+// CancelDataCleanupJobFromAgent stops a running cleanup job on the agent.
+func (sys *System) CancelDataCleanupJobFromAgent(
+	req common.DataCleanupJobCancelRequest,
+) (common.DockerDataCleanupResult, error) {
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+		defer cancel()
+		return sys.WsConn.RequestDataCleanupJobCancel(ctx, req)
+	}
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupJobCancel, req, dataCleanupListTimeout)
+	if err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	if resp.DataCleanupResult == nil {
+		return common.DockerDataCleanupResult{}, errors.New("no data cleanup job cancel result in response")
+	}
+	return *resp.DataCleanupResult, nil
+}
+
+// PingDataCleanupFromAgent checks that the agent can connect and authenticate to a cleanup module.
+func (sys *System) PingDataCleanupFromAgent(
+	req common.DataCleanupPingRequest,
+) (common.DockerDataCleanupResult, error) {
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		ctx, cancel := context.WithTimeout(context.Background(), common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
+		defer cancel()
+		return sys.WsConn.RequestDataCleanupPing(ctx, req)
+	}
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupPing, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
+	if err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	if resp.DataCleanupResult == nil {
+		return common.DockerDataCleanupResult{}, errors.New("no data cleanup ping result in response")
+	}
+	return *resp.DataCleanupResult, nil
+}
+
+// FetchDataCleanupJobsFromAgent lists the cleanup jobs the agent still retains.
+func (sys *System) FetchDataCleanupJobsFromAgent() (common.DataCleanupJobListDetail, error) {
+	var result common.DockerDataCleanupResult
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+		defer cancel()
+		resp, err := sys.WsConn.RequestDataCleanupJobList(ctx)
+		if err != nil {
+			return common.DataCleanupJobListDetail{}, err
+		}
+		result = resp
+	} else {
+		resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupJobList, common.DataCleanupJobListRequest{}, dataCleanupListTimeout)
+		if err != nil {
+			return common.DataCleanupJobListDetail{}, err
+		}
+		if resp.DataCleanupResult == nil {
+			return common.DataCleanupJobListDetail{}, errors.New("no data cleanup job list in response")
+		}
+		result = *resp.DataCleanupResult
+	}
+	var list common.DataCleanupJobListDetail
+	if err := json.Unmarshal([]byte(result.Detail), &list); err != nil {
+		return common.DataCleanupJobListDetail{}, err
+	}
+	return list, nil
+}
+
+func (sys *System) CountDataCleanupRedisMatchesFromAgent(
+	req common.DataCleanupRedisMatchCountRequest,
+) (common.DockerDataCleanupResult, error) {
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+		defer cancel()
+		return sys.WsConn.RequestDataCleanupRedisMatchCount(ctx, req)
+	}
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupRedisMatchCount, req, dataCleanupListTimeout)
+	if err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	if resp.DataCleanupResult == nil {
+		return common.DockerDataCleanupResult{}, errors.New("no redis match count in response")
+	}
+	return *resp.DataCleanupResult, nil
+}
+
+func (sys *System) CountDataCleanupMinioMatchesFromAgent(
+	req common.DataCleanupMinioMatchCountRequest,
+) (common.DockerDataCleanupResult, error) {
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		ctx, cancel := context.WithTimeout(context.Background(), dataCleanupListTimeout)
+		defer cancel()
+		return sys.WsConn.RequestDataCleanupMinioMatchCount(ctx, req)
+	}
+	resp, err := sys.fetchDockerResponseViaSSH(common.DataCleanupMinioMatchCount, req, dataCleanupListTimeout)
+	if err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	if resp.DataCleanupResult == nil {
+		return common.DockerDataCleanupResult{}, errors.New("no minio match count in response")
+	}
+	return *resp.DataCleanupResult, nil
+}
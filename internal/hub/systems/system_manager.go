@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"sync"
 	"time"
 
 	"aether/internal/hub/ws"
@@ -33,6 +34,17 @@ const (
 
 	// sessionTimeout is the maximum time to wait for SSH connections
 	sessionTimeout = 4 * time.Second
+
+	// defaultWsMissedPongThreshold is the number of consecutive unanswered pings
+	// after which the WebSocket connection is closed and the system falls back to SSH
+	defaultWsMissedPongThreshold = 3
+
+	// defaultSSHMaxSessions is the number of concurrent sessions opened on a system's SSH client.
+	// It stays well below OpenSSH's default MaxSessions of 10.
+	defaultSSHMaxSessions = 4
+
+	// reconnectWindow is how long WebSocket reconnect timestamps are kept for rate reporting
+	reconnectWindow = time.Hour
 )
 
 // errSystemExists is returned when attempting to add a system that already exists
@@ -41,9 +53,26 @@ var errSystemExists = errors.New("system exists")
 // SystemManager manages a collection of monitored systems and their connections.
 // It handles system lifecycle, status updates, and maintains both SSH and WebSocket connections.
 type SystemManager struct {
-	hub       hubLike                       // Hub interface for database and alert operations
-	systems   *store.Store[string, *System] // Thread-safe store of active systems
-	sshConfig *ssh.ClientConfig             // SSH client configuration for system connections
+	hub                   hubLike                       // Hub interface for database and alert operations
+	systems               *store.Store[string, *System] // Thread-safe store of active systems
+	sshConfig             *ssh.ClientConfig             // SSH client configuration for system connections
+	wsMissedPongThreshold int                           // Unanswered pings before a proactive WebSocket reconnect
+	sshMaxSessions        int                           // Concurrent SSH sessions allowed per system
+	onWebSocketConnect    func(systemID string)         // Optional; called after an agent (re)connects via WebSocket
+	reconnectMu           sync.Mutex
+	reconnectTimes        []time.Time // WebSocket reconnects within reconnectWindow, oldest first
+	reconnectTotal        int         // WebSocket reconnects since hub start
+}
+
+// ConnectionStats aggregates connection state across all systems held by the manager.
+type ConnectionStats struct {
+	Systems            int
+	ByStatus           map[string]int
+	ByTransport        map[string]int
+	SSHClients         int // systems with an open SSH client
+	Reconnects         int // WebSocket reconnects since hub start
+	ReconnectsLast5m   int
+	ReconnectsLastHour int
 }
 
 // hubLike defines the interface requirements for the hub dependency.
@@ -60,11 +89,41 @@ type hubLike interface {
 // The hub must implement the hubLike interface to provide database and alert functionality.
 func NewSystemManager(hub hubLike) *SystemManager {
 	return &SystemManager{
-		systems: store.New(map[string]*System{}),
-		hub:     hub,
+		systems:               store.New(map[string]*System{}),
+		hub:                   hub,
+		wsMissedPongThreshold: defaultWsMissedPongThreshold,
+		sshMaxSessions:        defaultSSHMaxSessions,
+	}
+}
+
+// SetSSHMaxSessions sets how many SSH sessions may be open concurrently on a single
+// system's connection; further operations wait for a free slot. Values below 1 are ignored.
+// It only affects systems whose first SSH operation runs after the call.
+func (sm *SystemManager) SetSSHMaxSessions(limit int) {
+	if limit > 0 {
+		sm.sshMaxSessions = limit
 	}
 }
 
+// SetOnWebSocketConnect registers a callback run in its own goroutine each time an agent
+// connects or reconnects via WebSocket, after the system has been added to the manager.
+func (sm *SystemManager) SetOnWebSocketConnect(fn func(systemID string)) {
+	sm.onWebSocketConnect = fn
+}
+
+// SetWsMissedPongThreshold sets how many consecutive pings may go unanswered
+// before the WebSocket connection is dropped in favor of SSH. Values below 1 are ignored.
+func (sm *SystemManager) SetWsMissedPongThreshold(threshold int) {
+	if threshold > 0 {
+		sm.wsMissedPongThreshold = threshold
+	}
+}
+
+// WsMissedPongThreshold returns the configured missed pong threshold.
+func (sm *SystemManager) WsMissedPongThreshold() int {
+	return sm.wsMissedPongThreshold
+}
+
 // GetSystem returns a system by ID from the store
 func (sm *SystemManager) GetSystem(systemID string) (*System, error) {
 	sys, ok := sm.systems.GetOk(systemID)
@@ -324,13 +383,77 @@ func (sm *SystemManager) AddWebSocketSystem(systemId string, agentVersion semver
 	system := sm.NewSystem(systemId)
 	system.WsConn = wsConn
 	system.agentVersion = agentVersion
+	// carry the connection count over so reconnects survive the system being replaced
+	system.wsConnections = 1
+	if previous, ok := sm.systems.GetOk(systemId); ok {
+		system.wsConnections += previous.wsConnections
+	}
+	if system.wsConnections > 1 {
+		sm.recordReconnect(time.Now())
+	}
 
 	if err := sm.AddRecord(systemRecord, system); err != nil {
 		return err
 	}
+	if sm.onWebSocketConnect != nil {
+		go sm.onWebSocketConnect(systemId)
+	}
 	return nil
 }
 
+func (sm *SystemManager) recordReconnect(now time.Time) {
+	sm.reconnectMu.Lock()
+	defer sm.reconnectMu.Unlock()
+	sm.reconnectTotal++
+	sm.reconnectTimes = append(sm.reconnectTimes, now)
+	sm.pruneReconnects(now)
+}
+
+// pruneReconnects drops timestamps older than reconnectWindow. Callers must hold reconnectMu.
+func (sm *SystemManager) pruneReconnects(now time.Time) {
+	cutoff := now.Add(-reconnectWindow)
+	drop := 0
+	for drop < len(sm.reconnectTimes) && sm.reconnectTimes[drop].Before(cutoff) {
+		drop++
+	}
+	sm.reconnectTimes = sm.reconnectTimes[drop:]
+}
+
+// ConnectionStats returns connection counts computed from in-memory state only.
+// Systems not held by the manager (deleted or never started) are not counted.
+func (sm *SystemManager) ConnectionStats() ConnectionStats {
+	stats := ConnectionStats{
+		ByStatus:    map[string]int{up: 0, down: 0, paused: 0, pending: 0},
+		ByTransport: map[string]int{"websocket": 0, "ssh": 0, "none": 0},
+	}
+	for _, sys := range sm.systems.GetAll() {
+		stats.Systems++
+		status := sys.Status
+		if status == "" {
+			status = pending
+		}
+		stats.ByStatus[status]++
+		stats.ByTransport[sys.ConnectionHealth().Transport]++
+		if sys.client != nil {
+			stats.SSHClients++
+		}
+	}
+
+	now := time.Now()
+	sm.reconnectMu.Lock()
+	sm.pruneReconnects(now)
+	stats.Reconnects = sm.reconnectTotal
+	stats.ReconnectsLastHour = len(sm.reconnectTimes)
+	recentCutoff := now.Add(-5 * time.Minute)
+	for _, t := range sm.reconnectTimes {
+		if !t.Before(recentCutoff) {
+			stats.ReconnectsLast5m++
+		}
+	}
+	sm.reconnectMu.Unlock()
+	return stats
+}
+
 // createSSHClientConfig initializes the SSH client configuration for connecting to an agent's server
 func (sm *SystemManager) createSSHClientConfig() error {
 	privateKey, err := sm.hub.GetSSHKey("")
@@ -0,0 +1,41 @@
+//go:build testing
+
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/store"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestConnectionStats(t *testing.T) {
+	sm := &SystemManager{systems: store.New(map[string]*System{
+		"a": {Id: "a", Status: up, client: &ssh.Client{}},
+		"b": {Id: "b", Status: up, client: &ssh.Client{}},
+		"c": {Id: "c", Status: down},
+		"d": {Id: "d", Status: paused},
+		"e": {Id: "e"},
+	})}
+
+	now := time.Now()
+	sm.recordReconnect(now.Add(-2 * time.Hour))
+	sm.recordReconnect(now.Add(-30 * time.Minute))
+	sm.recordReconnect(now.Add(-time.Minute))
+
+	stats := sm.ConnectionStats()
+	assert.Equal(t, 5, stats.Systems)
+	assert.Equal(t, map[string]int{up: 2, down: 1, paused: 1, pending: 1}, stats.ByStatus, "an empty status counts as pending")
+	assert.Equal(t, map[string]int{"websocket": 0, "ssh": 2, "none": 3}, stats.ByTransport)
+	assert.Equal(t, 2, stats.SSHClients)
+	assert.Equal(t, 3, stats.Reconnects, "the total keeps reconnects older than the rate window")
+	assert.Equal(t, 2, stats.ReconnectsLastHour)
+	assert.Equal(t, 1, stats.ReconnectsLast5m)
+
+	empty := (&SystemManager{systems: store.New(map[string]*System{})}).ConnectionStats()
+	assert.Zero(t, empty.Systems)
+	assert.Equal(t, map[string]int{up: 0, down: 0, paused: 0, pending: 0}, empty.ByStatus, "every status is reported even when zero")
+	assert.Zero(t, empty.Reconnects)
+}
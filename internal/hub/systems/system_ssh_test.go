@@ -0,0 +1,56 @@
+//go:build testing
+
+package systems
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireSSHSessionLimitsConcurrency(t *testing.T) {
+	sm := &SystemManager{sshMaxSessions: 2}
+	sys := &System{manager: sm}
+	sys.ctx, sys.cancel = context.WithCancel(context.Background())
+	defer sys.cancel()
+
+	first, err := sys.acquireSSHSession()
+	require.NoError(t, err)
+	_, err = sys.acquireSSHSession()
+	require.NoError(t, err)
+
+	acquired := make(chan func(), 1)
+	go func() {
+		release, err := sys.acquireSSHSession()
+		if err == nil {
+			acquired <- release
+		}
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("third session acquired while the limit was reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	first()
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("queued session not acquired after a slot was released")
+	}
+}
+
+func TestAcquireSSHSessionStopsWhenSystemRemoved(t *testing.T) {
+	sys := &System{manager: &SystemManager{sshMaxSessions: 1}}
+	sys.ctx, sys.cancel = context.WithCancel(context.Background())
+
+	_, err := sys.acquireSSHSession()
+	require.NoError(t, err)
+	sys.cancel()
+	_, err = sys.acquireSSHSession()
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,107 @@
+package ws
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/lxzan/gws"
+)
+
+// wireStatsKey is the session key holding the wireStats of an upgraded connection.
+const wireStatsKey = "wireStats"
+
+// compressionDisabled turns off permessage-deflate negotiation. It must be set before
+// the first call to GetUpgrader.
+var compressionDisabled atomic.Bool
+
+// upgraderDeflate is the permessage-deflate setting GetUpgrader built the upgrader with.
+var upgraderDeflate bool
+
+// SetCompressionEnabled enables or disables permessage-deflate for agent connections.
+// Agents that do not offer the extension are unaffected either way.
+func SetCompressionEnabled(enabled bool) {
+	compressionDisabled.Store(!enabled)
+}
+
+// CompressionMetrics reports inbound traffic on a connection. WireBytesIn includes
+// frame headers and control frames, so BytesSaved is an estimate.
+type CompressionMetrics struct {
+	Enabled        bool
+	PayloadBytesIn int64
+	WireBytesIn    int64
+	BytesSaved     int64
+}
+
+// countingReader counts bytes read from the hijacked connection reader.
+type countingReader struct {
+	r    io.Reader
+	read atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+// wireStats is stored in the connection session by Upgrade and picked up by NewWsConnection.
+type wireStats struct {
+	compressed bool
+	reader     *countingReader
+}
+
+// negotiatedDeflate reports whether the upgrader accepts permessage-deflate for a client
+// offering extensions, following the same rule gws applies when it answers the handshake.
+func negotiatedDeflate(serverEnabled bool, extensions string) bool {
+	return serverEnabled && strings.Contains(extensions, "permessage-deflate")
+}
+
+func serverOption() *gws.ServerOption {
+	return &gws.ServerOption{
+		PermessageDeflate: gws.PermessageDeflate{
+			Enabled: !compressionDisabled.Load(),
+		},
+	}
+}
+
+// Upgrade upgrades an agent request to a WebSocket connection, counting wire bytes
+// so compression savings can be reported.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*gws.Conn, error) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, http.ErrNotSupported
+	}
+	netConn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	// read through the hijacked reader: it may already hold the client's first frames
+	counted := &countingReader{r: brw.Reader}
+	upgrader := GetUpgrader()
+	conn, err := upgrader.UpgradeFromConn(netConn, bufio.NewReader(counted), r)
+	if err != nil {
+		return nil, err
+	}
+	conn.Session().Store(wireStatsKey, &wireStats{
+		compressed: negotiatedDeflate(upgraderDeflate, r.Header.Get("Sec-WebSocket-Extensions")),
+		reader:     counted,
+	})
+	return conn, nil
+}
+
+// CompressionMetrics returns inbound payload and wire byte counts for the connection.
+func (ws *WsConn) CompressionMetrics() CompressionMetrics {
+	metrics := CompressionMetrics{PayloadBytesIn: ws.payloadBytesIn.Load()}
+	if ws.wire == nil {
+		return metrics
+	}
+	metrics.Enabled = ws.wire.compressed
+	metrics.WireBytesIn = ws.wire.reader.read.Load()
+	if saved := metrics.PayloadBytesIn - metrics.WireBytesIn; saved > 0 {
+		metrics.BytesSaved = saved
+	}
+	return metrics
+}
@@ -0,0 +1,36 @@
+//go:build testing
+// +build testing
+
+package ws
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountingReaderKeepsBufferedBytes(t *testing.T) {
+	hijacked := bufio.NewReader(strings.NewReader("handshake\nframe"))
+	// the server has already buffered past the request line
+	line, err := hijacked.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "handshake\n", line)
+
+	counted := &countingReader{r: hijacked}
+	rest, err := io.ReadAll(bufio.NewReader(counted))
+	require.NoError(t, err)
+	assert.Equal(t, "frame", string(rest))
+	assert.EqualValues(t, len("frame"), counted.read.Load())
+}
+
+func TestNegotiatedDeflate(t *testing.T) {
+	offer := "permessage-deflate; client_max_window_bits"
+	assert.True(t, negotiatedDeflate(true, offer))
+	assert.False(t, negotiatedDeflate(false, offer))
+	assert.False(t, negotiatedDeflate(true, ""))
+	assert.False(t, negotiatedDeflate(true, "x-webkit-deflate-frame"))
+}
@@ -31,6 +31,20 @@ func (h *BaseHandler) HandleLegacy(rawData []byte) error {
 
 const systemDataRequestTimeout = 65 * time.Second
 
+const dockerComposeLogsTimeout = 30 * time.Second
+
+// dockerRegistryLoginTimeout outlasts the agent's operate timeout so its error is reported instead.
+const dockerRegistryLoginTimeout = 60 * time.Second
+
+// containerStatsTimeout covers the two samples the daemon takes for a one-off stats reading.
+const containerStatsTimeout = 15 * time.Second
+
+// containerExecTimeout outlasts the agent's command timeout so a timed-out command still returns its output.
+const containerExecTimeout = 45 * time.Second
+
+// dockerImagePruneTimeout outlasts the agent's operate timeout so its error is reported instead.
+const dockerImagePruneTimeout = 60 * time.Second
+
 ////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////
@@ -118,6 +132,127 @@ func (ws *WsConn) RequestContainerInfo(ctx context.Context, containerID string)
 	return ws.requestContainerStringViaWS(ctx, common.GetContainerInfo, common.ContainerInfoRequest{ContainerID: containerID}, "no info in response")
 }
 
+// RequestContainerDiff requests filesystem changes of a specific container via WebSocket.
+func (ws *WsConn) RequestContainerDiff(ctx context.Context, req common.ContainerDiffRequest) (docker.ContainerDiff, error) {
+	if !ws.IsConnected() {
+		return docker.ContainerDiff{}, gws.ErrConnClosed
+	}
+	handleReq, err := ws.requestManager.SendRequest(ctx, common.GetContainerDiff, req)
+	if err != nil {
+		return docker.ContainerDiff{}, err
+	}
+	var result docker.ContainerDiff
+	handler := &containerDiffHandler{result: &result}
+	if err := ws.handleAgentRequest(handleReq, handler); err != nil {
+		return docker.ContainerDiff{}, err
+	}
+	return result, nil
+}
+
+type containerDiffHandler struct {
+	BaseHandler
+	result *docker.ContainerDiff
+}
+
+func (h *containerDiffHandler) Handle(agentResponse common.AgentResponse) error {
+	if agentResponse.DockerContainerDiff == nil {
+		return errors.New("no container diff in response")
+	}
+	*h.result = *agentResponse.DockerContainerDiff
+	return nil
+}
+
+// RequestContainerStats requests a live stats reading of a specific container via WebSocket.
+func (ws *WsConn) RequestContainerStats(ctx context.Context, containerID string) (docker.ContainerStats, error) {
+	if !ws.IsConnected() {
+		return docker.ContainerStats{}, gws.ErrConnClosed
+	}
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.GetContainerStats, common.ContainerStatsRequest{ContainerID: containerID}, containerStatsTimeout)
+	if err != nil {
+		return docker.ContainerStats{}, err
+	}
+	var result docker.ContainerStats
+	handler := &containerStatsHandler{result: &result}
+	if err := ws.handleAgentRequest(handleReq, handler); err != nil {
+		return docker.ContainerStats{}, err
+	}
+	return result, nil
+}
+
+type containerStatsHandler struct {
+	BaseHandler
+	result *docker.ContainerStats
+}
+
+func (h *containerStatsHandler) Handle(agentResponse common.AgentResponse) error {
+	if agentResponse.DockerContainerStats == nil {
+		return errors.New("no container stats in response")
+	}
+	*h.result = *agentResponse.DockerContainerStats
+	return nil
+}
+
+// RequestContainerExec runs a single command in a container via WebSocket.
+func (ws *WsConn) RequestContainerExec(ctx context.Context, req common.ContainerExecRequest) (docker.ExecResult, error) {
+	if !ws.IsConnected() {
+		return docker.ExecResult{}, gws.ErrConnClosed
+	}
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.ExecInContainer, req, containerExecTimeout)
+	if err != nil {
+		return docker.ExecResult{}, err
+	}
+	var result docker.ExecResult
+	handler := &containerExecHandler{result: &result}
+	if err := ws.handleAgentRequest(handleReq, handler); err != nil {
+		return docker.ExecResult{}, err
+	}
+	return result, nil
+}
+
+type containerExecHandler struct {
+	BaseHandler
+	result *docker.ExecResult
+}
+
+func (h *containerExecHandler) Handle(agentResponse common.AgentResponse) error {
+	if agentResponse.DockerContainerExec == nil {
+		return errors.New("no exec result in response")
+	}
+	*h.result = *agentResponse.DockerContainerExec
+	return nil
+}
+
+// RequestDockerComposeLogs requests interleaved logs of a compose project via WebSocket.
+func (ws *WsConn) RequestDockerComposeLogs(ctx context.Context, req common.DockerComposeLogsRequest) (docker.ComposeLogs, error) {
+	if !ws.IsConnected() {
+		return docker.ComposeLogs{}, gws.ErrConnClosed
+	}
+	// Reading several services can exceed the default request timeout; ctx bounds the wait.
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.GetDockerComposeLogs, req, dockerComposeLogsTimeout)
+	if err != nil {
+		return docker.ComposeLogs{}, err
+	}
+	var result docker.ComposeLogs
+	handler := &dockerComposeLogsHandler{result: &result}
+	if err := ws.handleAgentRequest(handleReq, handler); err != nil {
+		return docker.ComposeLogs{}, err
+	}
+	return result, nil
+}
+
+type dockerComposeLogsHandler struct {
+	BaseHandler
+	result *docker.ComposeLogs
+}
+
+func (h *dockerComposeLogsHandler) Handle(agentResponse common.AgentResponse) error {
+	if agentResponse.DockerComposeLogs == nil {
+		return errors.New("no compose logs in response")
+	}
+	*h.result = *agentResponse.DockerComposeLogs
+	return nil
+}
+
 // RequestContainerOperate executes a container operation (start/stop/restart/kill/pause/unpause) via WebSocket.
 func (ws *WsConn) RequestContainerOperate(ctx context.Context, req common.ContainerOperateRequest) (string, error) {
 	return ws.requestContainerStringViaWS(ctx, common.OperateContainer, req, "operation failed")
@@ -284,7 +419,7 @@ func (ws *WsConn) RequestDataCleanupMySQLDatabases(
 	if !ws.IsConnected() {
 		return nil, gws.ErrConnClosed
 	}
-	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMySQLDatabases, req, dataCleanupListTimeout)
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMySQLDatabases, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -303,7 +438,7 @@ func (ws *WsConn) RequestDataCleanupMySQLTables(
 	if !ws.IsConnected() {
 		return nil, gws.ErrConnClosed
 	}
-	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMySQLTables, req, dataCleanupListTimeout)
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMySQLTables, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -322,7 +457,7 @@ func (ws *WsConn) RequestDataCleanupMySQLDeleteTables(
 	if !ws.IsConnected() {
 		return common.DockerDataCleanupResult{}, gws.ErrConnClosed
 	}
-	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMySQLDeleteTables, req, dataCleanupActionTimeout)
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMySQLDeleteTables, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	if err != nil {
 		return common.DockerDataCleanupResult{}, err
 	}
@@ -341,7 +476,7 @@ func (ws *WsConn) RequestDataCleanupRedisDatabases(
 	if !ws.IsConnected() {
 		return nil, gws.ErrConnClosed
 	}
-	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupRedisDatabases, req, dataCleanupListTimeout)
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupRedisDatabases, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -360,7 +495,7 @@ func (ws *WsConn) RequestDataCleanupRedisCleanup(
 	if !ws.IsConnected() {
 		return common.DockerDataCleanupResult{}, gws.ErrConnClosed
 	}
-	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupRedisCleanup, req, dataCleanupActionTimeout)
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupRedisCleanup, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	if err != nil {
 		return common.DockerDataCleanupResult{}, err
 	}
@@ -379,7 +514,7 @@ func (ws *WsConn) RequestDataCleanupMinioBuckets(
 	if !ws.IsConnected() {
 		return nil, gws.ErrConnClosed
 	}
-	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMinioBuckets, req, dataCleanupListTimeout)
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMinioBuckets, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -398,7 +533,7 @@ func (ws *WsConn) RequestDataCleanupMinioPrefixes(
 	if !ws.IsConnected() {
 		return nil, gws.ErrConnClosed
 	}
-	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMinioPrefixes, req, dataCleanupListTimeout)
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMinioPrefixes, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -417,7 +552,7 @@ func (ws *WsConn) RequestDataCleanupMinioCleanup(
 	if !ws.IsConnected() {
 		return common.DockerDataCleanupResult{}, gws.ErrConnClosed
 	}
-	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMinioCleanup, req, dataCleanupActionTimeout)
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMinioCleanup, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	if err != nil {
 		return common.DockerDataCleanupResult{}, err
 	}
@@ -436,7 +571,7 @@ func (ws *WsConn) RequestDataCleanupESIndices(
 	if !ws.IsConnected() {
 		return nil, gws.ErrConnClosed
 	}
-	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupESIndices, req, dataCleanupListTimeout)
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupESIndices, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
 	if err != nil {
 		return nil, err
 	}
@@ -455,7 +590,7 @@ func (ws *WsConn) RequestDataCleanupESCleanup(
 	if !ws.IsConnected() {
 		return common.DockerDataCleanupResult{}, gws.ErrConnClosed
 	}
-	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupESCleanup, req, dataCleanupActionTimeout)
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupESCleanup, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupActionTimeout))
 	if err != nil {
 		return common.DockerDataCleanupResult{}, err
 	}
@@ -486,6 +621,98 @@ func (ws *WsConn) RequestDataCleanupJobStatus(
 	return result, nil
 }
 
+func (ws *WsConn) RequestDataCleanupJobList(ctx context.Context) (common.DockerDataCleanupResult, error) {
+	if !ws.IsConnected() {
+		return common.DockerDataCleanupResult{}, gws.ErrConnClosed
+	}
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupJobList, common.DataCleanupJobListRequest{}, dataCleanupListTimeout)
+	if err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	var result common.DockerDataCleanupResult
+	handler := &dataCleanupResultHandler{result: &result, errorMsg: "no data cleanup job list in response"}
+	if err := ws.handleAgentRequest(handleReq, handler); err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	return result, nil
+}
+
+func (ws *WsConn) RequestDataCleanupJobCancel(
+	ctx context.Context,
+	req common.DataCleanupJobCancelRequest,
+) (common.DockerDataCleanupResult, error) {
+	if !ws.IsConnected() {
+		return common.DockerDataCleanupResult{}, gws.ErrConnClosed
+	}
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupJobCancel, req, dataCleanupListTimeout)
+	if err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	var result common.DockerDataCleanupResult
+	handler := &dataCleanupResultHandler{result: &result, errorMsg: "no data cleanup job cancel result in response"}
+	if err := ws.handleAgentRequest(handleReq, handler); err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	return result, nil
+}
+
+func (ws *WsConn) RequestDataCleanupPing(
+	ctx context.Context,
+	req common.DataCleanupPingRequest,
+) (common.DockerDataCleanupResult, error) {
+	if !ws.IsConnected() {
+		return common.DockerDataCleanupResult{}, gws.ErrConnClosed
+	}
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupPing, req, common.DataCleanupTimeout(req.TimeoutSec, dataCleanupListTimeout))
+	if err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	var result common.DockerDataCleanupResult
+	handler := &dataCleanupResultHandler{result: &result, errorMsg: "no data cleanup ping result in response"}
+	if err := ws.handleAgentRequest(handleReq, handler); err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	return result, nil
+}
+
+func (ws *WsConn) RequestDataCleanupRedisMatchCount(
+	ctx context.Context,
+	req common.DataCleanupRedisMatchCountRequest,
+) (common.DockerDataCleanupResult, error) {
+	if !ws.IsConnected() {
+		return common.DockerDataCleanupResult{}, gws.ErrConnClosed
+	}
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupRedisMatchCount, req, dataCleanupListTimeout)
+	if err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	var result common.DockerDataCleanupResult
+	handler := &dataCleanupResultHandler{result: &result, errorMsg: "no redis match count in response"}
+	if err := ws.handleAgentRequest(handleReq, handler); err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	return result, nil
+}
+
+func (ws *WsConn) RequestDataCleanupMinioMatchCount(
+	ctx context.Context,
+	req common.DataCleanupMinioMatchCountRequest,
+) (common.DockerDataCleanupResult, error) {
+	if !ws.IsConnected() {
+		return common.DockerDataCleanupResult{}, gws.ErrConnClosed
+	}
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DataCleanupMinioMatchCount, req, dataCleanupListTimeout)
+	if err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	var result common.DockerDataCleanupResult
+	handler := &dataCleanupResultHandler{result: &result, errorMsg: "no minio match count in response"}
+	if err := ws.handleAgentRequest(handleReq, handler); err != nil {
+		return common.DockerDataCleanupResult{}, err
+	}
+	return result, nil
+}
+
 type dockerImagesHandler struct {
 	BaseHandler
 	result *[]docker.Image
@@ -634,6 +861,52 @@ func (ws *WsConn) RequestDockerImageRemove(ctx context.Context, req common.Docke
 	return ws.requestContainerStringViaWS(ctx, common.RemoveDockerImage, req, "docker image remove failed")
 }
 
+// RequestDockerImagePrune removes dangling docker images via WebSocket.
+func (ws *WsConn) RequestDockerImagePrune(ctx context.Context, req common.DockerImagePruneRequest) (docker.ImagePruneReport, error) {
+	if !ws.IsConnected() {
+		return docker.ImagePruneReport{}, gws.ErrConnClosed
+	}
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.PruneDockerImages, req, dockerImagePruneTimeout)
+	if err != nil {
+		return docker.ImagePruneReport{}, err
+	}
+	var result docker.ImagePruneReport
+	handler := &dockerImagePruneHandler{result: &result}
+	if err := ws.handleAgentRequest(handleReq, handler); err != nil {
+		return docker.ImagePruneReport{}, err
+	}
+	return result, nil
+}
+
+type dockerImagePruneHandler struct {
+	BaseHandler
+	result *docker.ImagePruneReport
+}
+
+func (h *dockerImagePruneHandler) Handle(agentResponse common.AgentResponse) error {
+	if agentResponse.DockerImagePrune == nil {
+		return errors.New("no image prune report in response")
+	}
+	*h.result = *agentResponse.DockerImagePrune
+	return nil
+}
+
+// RequestDockerRegistryLogin checks registry credentials on the agent via WebSocket.
+func (ws *WsConn) RequestDockerRegistryLogin(ctx context.Context, req common.DockerRegistryLoginRequest) (string, error) {
+	if !ws.IsConnected() {
+		return "", gws.ErrConnClosed
+	}
+	handleReq, err := ws.requestManager.SendRequestWithTimeout(ctx, common.DockerRegistryLogin, req, dockerRegistryLoginTimeout)
+	if err != nil {
+		return "", err
+	}
+	handler := &stringResponseHandler{errorMsg: "docker registry login failed"}
+	if err := ws.handleAgentRequest(handleReq, handler); err != nil {
+		return "", err
+	}
+	return handler.value, nil
+}
+
 // RequestDockerNetworkCreate creates docker network via WebSocket.
 func (ws *WsConn) RequestDockerNetworkCreate(ctx context.Context, req common.DockerNetworkCreateRequest) (string, error) {
 	return ws.requestContainerStringViaWS(ctx, common.CreateDockerNetwork, req, "docker network create failed")
@@ -1,7 +1,9 @@
 package ws
 
 import (
+	"context"
 	"errors"
+	"sync/atomic"
 	"time"
 	"weak"
 
@@ -29,6 +31,24 @@ type WsConn struct {
 	requestManager *RequestManager
 	DownChan       chan struct{}
 	agentVersion   semver.Version
+	connectedAt    time.Time
+	lastPingSent   atomic.Int64                  // unix nanoseconds of the last ping sent
+	lastPongAt     atomic.Int64                  // unix nanoseconds of the last pong received
+	lastPingRTT    atomic.Int64                  // round trip of the last answered ping in nanoseconds
+	awaitingPong   atomic.Bool                   // true while the last ping has not been answered
+	missedPongs    atomic.Int32                  // consecutive pings sent without a pong in between
+	pongSignal     atomic.Pointer[chan struct{}] // closed and cleared when the next pong arrives
+	wire           *wireStats                    // set when the connection was upgraded through Upgrade
+	payloadBytesIn atomic.Int64                  // decompressed size of binary messages received
+}
+
+// HeartbeatMetrics is a snapshot of the ping/pong health of a WebSocket connection.
+type HeartbeatMetrics struct {
+	ConnectedAt time.Time
+	LastPingAt  time.Time
+	LastPongAt  time.Time
+	LastPingRTT time.Duration
+	MissedPongs int
 }
 
 // FingerprintRecord is fingerprints collection record data in the hub
@@ -47,18 +67,27 @@ func GetUpgrader() *gws.Upgrader {
 		return upgrader
 	}
 	handler := &Handler{}
-	upgrader = gws.NewUpgrader(handler, &gws.ServerOption{})
+	option := serverOption()
+	upgraderDeflate = option.PermessageDeflate.Enabled
+	upgrader = gws.NewUpgrader(handler, option)
 	return upgrader
 }
 
 // NewWsConnection creates a new WebSocket connection wrapper with agent version.
 func NewWsConnection(conn *gws.Conn, agentVersion semver.Version) *WsConn {
-	return &WsConn{
+	wsConn := &WsConn{
 		conn:           conn,
 		requestManager: NewRequestManager(conn),
 		DownChan:       make(chan struct{}, 1),
 		agentVersion:   agentVersion,
+		connectedAt:    time.Now(),
 	}
+	if conn != nil {
+		if stats, ok := conn.Session().Load(wireStatsKey); ok {
+			wsConn.wire = stats.(*wireStats)
+		}
+	}
+	return wsConn
 }
 
 // OnOpen sets a deadline for the WebSocket connection and extracts agent version.
@@ -77,9 +106,20 @@ func (h *Handler) OnMessage(conn *gws.Conn, message *gws.Message) {
 		_ = conn.WriteClose(1000, nil)
 		return
 	}
+	wsConn.(*WsConn).payloadBytesIn.Add(int64(message.Data.Len()))
 	wsConn.(*WsConn).requestManager.handleResponse(message)
 }
 
+// OnPong records the round trip of the last ping and resets the missed pong counter.
+func (h *Handler) OnPong(conn *gws.Conn, payload []byte) {
+	conn.SetDeadline(time.Now().Add(deadline))
+	wsConn, ok := conn.Session().Load("wsConn")
+	if !ok {
+		return
+	}
+	wsConn.(*WsConn).recordPong(time.Now())
+}
+
 // OnClose handles WebSocket connection closures and triggers system down status after delay.
 func (h *Handler) OnClose(conn *gws.Conn, err error) {
 	wsConn, ok := conn.Session().Load("wsConn")
@@ -109,11 +149,87 @@ func (ws *WsConn) Close(msg []byte) {
 }
 
 // Ping sends a ping frame to keep the connection alive.
+// If the previous ping is still unanswered it is counted as a missed pong.
 func (ws *WsConn) Ping() error {
-	ws.conn.SetDeadline(time.Now().Add(deadline))
+	if ws.conn == nil {
+		return gws.ErrConnClosed
+	}
+	now := time.Now()
+	if ws.awaitingPong.Swap(true) {
+		ws.missedPongs.Add(1)
+	}
+	ws.lastPingSent.Store(now.UnixNano())
+	ws.conn.SetDeadline(now.Add(deadline))
 	return ws.conn.WritePing(nil)
 }
 
+// recordPong marks the outstanding ping as answered.
+func (ws *WsConn) recordPong(now time.Time) {
+	ws.lastPongAt.Store(now.UnixNano())
+	if sent := ws.lastPingSent.Load(); sent > 0 && ws.awaitingPong.Swap(false) {
+		ws.lastPingRTT.Store(now.UnixNano() - sent)
+	}
+	ws.missedPongs.Store(0)
+	if signal := ws.pongSignal.Swap(nil); signal != nil {
+		close(*signal)
+	}
+}
+
+// nextPong returns a channel that is closed when the next pong arrives.
+func (ws *WsConn) nextPong() <-chan struct{} {
+	for {
+		if signal := ws.pongSignal.Load(); signal != nil {
+			return *signal
+		}
+		signal := make(chan struct{})
+		if ws.pongSignal.CompareAndSwap(nil, &signal) {
+			return signal
+		}
+	}
+}
+
+// ProbeRTT pings the agent and waits for the pong, returning the round trip.
+// A heartbeat ping that is still unanswered is awaited instead of sending another
+// one, so probing never counts as a missed pong.
+func (ws *WsConn) ProbeRTT(ctx context.Context) (time.Duration, error) {
+	if ws.conn == nil {
+		return 0, gws.ErrConnClosed
+	}
+	pong := ws.nextPong()
+	if !ws.awaitingPong.Load() {
+		if err := ws.Ping(); err != nil {
+			return 0, err
+		}
+	}
+	select {
+	case <-pong:
+		return time.Duration(ws.lastPingRTT.Load()), nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// MissedPongs returns the number of consecutive pings that went unanswered.
+func (ws *WsConn) MissedPongs() int {
+	return int(ws.missedPongs.Load())
+}
+
+// HeartbeatMetrics returns a snapshot of the connection's ping/pong health.
+func (ws *WsConn) HeartbeatMetrics() HeartbeatMetrics {
+	metrics := HeartbeatMetrics{
+		ConnectedAt: ws.connectedAt,
+		LastPingRTT: time.Duration(ws.lastPingRTT.Load()),
+		MissedPongs: ws.MissedPongs(),
+	}
+	if sent := ws.lastPingSent.Load(); sent > 0 {
+		metrics.LastPingAt = time.Unix(0, sent)
+	}
+	if pong := ws.lastPongAt.Load(); pong > 0 {
+		metrics.LastPongAt = time.Unix(0, pong)
+	}
+	return metrics
+}
+
 // sendMessage encodes data to CBOR and sends it as a binary message to the agent.
 // This is kept for backwards compatibility but new actions should use RequestManager.
 func (ws *WsConn) sendMessage(data common.HubRequest[any]) error {
@@ -4,6 +4,7 @@
 package ws
 
 import (
+	"context"
 	"crypto/ed25519"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"aether/internal/common"
 
 	"github.com/fxamacker/cbor/v2"
+	"github.com/lxzan/gws"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/ssh"
@@ -226,3 +228,33 @@ func TestWsConnChannelBehavior(t *testing.T) {
 	// Request manager should have no pending requests initially
 	assert.Equal(t, 0, wsConn.requestManager.GetPendingCount(), "Should have no pending requests initially")
 }
+
+func TestWsConnProbeRTT(t *testing.T) {
+	wsConn := NewWsConnection(nil, semver.MustParse("1.0.0"))
+	_, err := wsConn.ProbeRTT(context.Background())
+	assert.ErrorIs(t, err, gws.ErrConnClosed)
+
+	// an outstanding heartbeat ping is awaited instead of sending another
+	wsConn.conn = &gws.Conn{}
+	wsConn.lastPingSent.Store(time.Now().Add(-15 * time.Millisecond).UnixNano())
+	wsConn.awaitingPong.Store(true)
+	go func() {
+		for wsConn.pongSignal.Load() == nil {
+			time.Sleep(time.Millisecond)
+		}
+		wsConn.recordPong(time.Now())
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rtt, err := wsConn.ProbeRTT(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, rtt, 15*time.Millisecond)
+	assert.Zero(t, wsConn.MissedPongs())
+
+	wsConn.awaitingPong.Store(true)
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer shortCancel()
+	_, err = wsConn.ProbeRTT(shortCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, wsConn.MissedPongs())
+}
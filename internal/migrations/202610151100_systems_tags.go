@@ -0,0 +1,29 @@
+// systems 增加 tags 字段，用于按标签筛选系统（例如系统汇总接口）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("systems")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.JSONField{Name: "tags"})
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("systems")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.RemoveByName("tags")
+
+		return app.Save(collection)
+	})
+}
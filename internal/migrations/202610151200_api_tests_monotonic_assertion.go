@@ -0,0 +1,43 @@
+// api_test_cases 增加 monotonic_path（单调断言取值路径），api_test_runs 增加 extracted_value（本次提取值）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.Add(&core.TextField{Name: "monotonic_path"})
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.Add(&core.JSONField{Name: "extracted_value"})
+		return app.Save(runs)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("monotonic_path")
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.RemoveByName("extracted_value")
+		return app.Save(runs)
+	})
+}
@@ -0,0 +1,33 @@
+// api_test_cases 增加代理转发头字段（X-Forwarded-For / X-Forwarded-Proto / X-Real-IP），用于模拟经负载均衡转发的请求。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.TextField{Name: "forwarded_for"})
+		collection.Fields.Add(&core.TextField{Name: "forwarded_proto"})
+		collection.Fields.Add(&core.TextField{Name: "real_ip"})
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.RemoveByName("forwarded_for")
+		collection.Fields.RemoveByName("forwarded_proto")
+		collection.Fields.RemoveByName("real_ip")
+
+		return app.Save(collection)
+	})
+}
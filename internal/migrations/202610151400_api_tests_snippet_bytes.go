@@ -0,0 +1,29 @@
+// api_test_collections 增加 snippet_bytes 字段，按合集覆盖响应摘要长度（受全局硬上限约束）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.NumberField{Name: "snippet_bytes", OnlyInt: true})
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.RemoveByName("snippet_bytes")
+
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,45 @@
+// api_test_cases 增加 min_response_bytes / max_response_bytes（响应大小断言），api_test_runs 增加 response_bytes（本次响应大小）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.Add(&core.NumberField{Name: "min_response_bytes", OnlyInt: true})
+		cases.Fields.Add(&core.NumberField{Name: "max_response_bytes", OnlyInt: true})
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.Add(&core.NumberField{Name: "response_bytes", OnlyInt: true})
+		return app.Save(runs)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("min_response_bytes")
+		cases.Fields.RemoveByName("max_response_bytes")
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.RemoveByName("response_bytes")
+		return app.Save(runs)
+	})
+}
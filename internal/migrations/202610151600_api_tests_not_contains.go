@@ -0,0 +1,31 @@
+// api_test_cases 增加 not_contains（响应体禁止包含的内容）与 not_contains_regex（按正则匹配）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.TextField{Name: "not_contains"})
+		collection.Fields.Add(&core.BoolField{Name: "not_contains_regex"})
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.RemoveByName("not_contains")
+		collection.Fields.RemoveByName("not_contains_regex")
+
+		return app.Save(collection)
+	})
+}
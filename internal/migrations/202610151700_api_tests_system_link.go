@@ -0,0 +1,39 @@
+// api_test_cases 增加 system 关联字段（可选），用于将接口用例与被监控主机对应，便于联合排查。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		systems, err := app.FindCollectionByNameOrId("systems")
+		if err != nil {
+			return err
+		}
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.RelationField{
+			Name:         "system",
+			CollectionId: systems.Id,
+			MaxSelect:    1,
+		})
+		collection.AddIndex("idx_api_test_cases_system", false, "system", "")
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.RemoveIndex("idx_api_test_cases_system")
+		collection.Fields.RemoveByName("system")
+
+		return app.Save(collection)
+	})
+}
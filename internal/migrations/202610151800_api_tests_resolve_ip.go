@@ -0,0 +1,29 @@
+// api_test_cases 增加 resolve_ip（请求时将目标主机名解析为指定 IP，类似 curl --resolve）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.TextField{Name: "resolve_ip"})
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.RemoveByName("resolve_ip")
+
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,31 @@
+// api_test_cases 增加 tls_min_version / tls_ciphers（协商的 TLS 协议版本下限与允许的加密套件列表断言）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.SelectField{Name: "tls_min_version", MaxSelect: 1, Values: []string{"1.0", "1.1", "1.2", "1.3"}})
+		collection.Fields.Add(&core.JSONField{Name: "tls_ciphers"})
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.RemoveByName("tls_min_version")
+		collection.Fields.RemoveByName("tls_ciphers")
+
+		return app.Save(collection)
+	})
+}
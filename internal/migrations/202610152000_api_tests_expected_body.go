@@ -0,0 +1,33 @@
+// api_test_cases 增加 expected_body / expected_body_mode / expected_body_ignore（期望响应体断言：精确或 JSON 归一化比较，可忽略指定路径）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.TextField{Name: "expected_body"})
+		collection.Fields.Add(&core.SelectField{Name: "expected_body_mode", MaxSelect: 1, Values: []string{"exact", "json"}})
+		collection.Fields.Add(&core.JSONField{Name: "expected_body_ignore"})
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.RemoveByName("expected_body")
+		collection.Fields.RemoveByName("expected_body_mode")
+		collection.Fields.RemoveByName("expected_body_ignore")
+
+		return app.Save(collection)
+	})
+}
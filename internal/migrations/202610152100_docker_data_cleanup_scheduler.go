@@ -0,0 +1,25 @@
+// 新增 docker_data_cleanup_scheduler（数据清理定时调度的全局暂停状态，单条记录，仅通过接口读写）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection := core.NewBaseCollection("docker_data_cleanup_scheduler")
+
+		collection.Fields.Add(&core.BoolField{Name: "paused"})
+		collection.Fields.Add(&core.DateField{Name: "paused_at"})
+		collection.Fields.Add(&core.DateField{Name: "resume_at"})
+		collection.Fields.Add(&core.TextField{Name: "reason"})
+		collection.Fields.Add(&core.TextField{Name: "paused_by"})
+		collection.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+		collection.Fields.Add(&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true})
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		return deleteCollection(app, "docker_data_cleanup_scheduler")
+	})
+}
@@ -0,0 +1,66 @@
+// 新增 api_test_case_stats（按用例与滚动窗口预计算的成功率/耗时汇总，由后台任务定期刷新），
+// api_test_schedule_config 增加 stats_refresh_minutes 与 stats_window_hours。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		config, err := app.FindCollectionByNameOrId("api_test_schedule_config")
+		if err != nil {
+			return err
+		}
+		minZero := 0.0
+		maxMinutes := 1440.0
+		config.Fields.Add(&core.NumberField{Name: "stats_refresh_minutes", OnlyInt: true, Min: &minZero, Max: &maxMinutes})
+		config.Fields.Add(&core.JSONField{Name: "stats_window_hours"})
+		if err := app.Save(config); err != nil {
+			return err
+		}
+
+		casesCollection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		collection := core.NewBaseCollection("api_test_case_stats")
+		authRule := "@request.auth.id != \"\""
+		collection.ListRule = &authRule
+		collection.ViewRule = &authRule
+
+		collection.Fields.Add(&core.RelationField{
+			Name:          "case",
+			CollectionId:  casesCollection.Id,
+			Required:      true,
+			MaxSelect:     1,
+			CascadeDelete: true,
+		})
+		collection.Fields.Add(&core.NumberField{Name: "window_hours", OnlyInt: true, Min: &minZero})
+		collection.Fields.Add(&core.NumberField{Name: "total", OnlyInt: true, Min: &minZero})
+		collection.Fields.Add(&core.NumberField{Name: "success_count", OnlyInt: true, Min: &minZero})
+		collection.Fields.Add(&core.NumberField{Name: "failure_count", OnlyInt: true, Min: &minZero})
+		collection.Fields.Add(&core.NumberField{Name: "avg_duration_ms", Min: &minZero})
+		collection.Fields.Add(&core.NumberField{Name: "p95_duration_ms", OnlyInt: true, Min: &minZero})
+		collection.Fields.Add(&core.DateField{Name: "last_failure_at"})
+		collection.Fields.Add(&core.DateField{Name: "computed_at"})
+		collection.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+		collection.Fields.Add(&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true})
+
+		collection.AddIndex("idx_api_test_case_stats_case_window", true, "case,window_hours", "")
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		if err := deleteCollection(app, "api_test_case_stats"); err != nil {
+			return err
+		}
+		config, err := app.FindCollectionByNameOrId("api_test_schedule_config")
+		if err != nil {
+			return err
+		}
+		config.Fields.RemoveByName("stats_refresh_minutes")
+		config.Fields.RemoveByName("stats_window_hours")
+		return app.Save(config)
+	})
+}
@@ -0,0 +1,40 @@
+// api_test_collections 增加 OAuth2 令牌获取配置（oauth_token_url、oauth_client_id、oauth_client_secret、
+// oauth_grant_type、oauth_scope）。oauth_client_secret 加密存储且不随接口返回。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.TextField{Name: "oauth_token_url"})
+		collection.Fields.Add(&core.TextField{Name: "oauth_client_id"})
+		collection.Fields.Add(&core.TextField{Name: "oauth_client_secret", Hidden: true})
+		collection.Fields.Add(&core.SelectField{
+			Name:      "oauth_grant_type",
+			MaxSelect: 1,
+			Values:    []string{"client_credentials"},
+		})
+		collection.Fields.Add(&core.TextField{Name: "oauth_scope"})
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+
+		for _, name := range []string{"oauth_token_url", "oauth_client_id", "oauth_client_secret", "oauth_grant_type", "oauth_scope"} {
+			collection.Fields.RemoveByName(name)
+		}
+
+		return app.Save(collection)
+	})
+}
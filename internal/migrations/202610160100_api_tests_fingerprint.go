@@ -0,0 +1,52 @@
+// api_test_cases 增加 fingerprint_mode、fingerprint_paths（响应结构指纹的计算方式与参与路径）与 last_fingerprint，
+// api_test_runs 增加 fingerprint（本次响应的结构指纹）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.Add(&core.SelectField{
+			Name:      "fingerprint_mode",
+			MaxSelect: 1,
+			Values:    []string{"keys", "types"},
+		})
+		cases.Fields.Add(&core.JSONField{Name: "fingerprint_paths"})
+		cases.Fields.Add(&core.TextField{Name: "last_fingerprint"})
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.Add(&core.TextField{Name: "fingerprint"})
+		return app.Save(runs)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("fingerprint_mode")
+		cases.Fields.RemoveByName("fingerprint_paths")
+		cases.Fields.RemoveByName("last_fingerprint")
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.RemoveByName("fingerprint")
+		return app.Save(runs)
+	})
+}
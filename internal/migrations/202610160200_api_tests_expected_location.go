@@ -0,0 +1,27 @@
+// api_test_cases 增加 expected_location 与 expected_location_regex（重定向 Location 头断言）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.Add(&core.TextField{Name: "expected_location", Max: 2048})
+		cases.Fields.Add(&core.BoolField{Name: "expected_location_regex"})
+		return app.Save(cases)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("expected_location")
+		cases.Fields.RemoveByName("expected_location_regex")
+		return app.Save(cases)
+	})
+}
@@ -0,0 +1,38 @@
+// api_test_cases 增加 alert_mode（告警模式：连续失败次数或时间窗口成功率）、
+// alert_success_rate（成功率阈值，百分比）与 alert_window_minutes（统计窗口）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		minRate := 0.0
+		maxRate := 100.0
+		minWindow := 0.0
+		maxWindow := float64(7 * 24 * 60)
+		cases.Fields.Add(&core.SelectField{
+			Name:      "alert_mode",
+			MaxSelect: 1,
+			Values:    []string{"consecutive", "success_rate"},
+		})
+		cases.Fields.Add(&core.NumberField{Name: "alert_success_rate", Min: &minRate, Max: &maxRate})
+		cases.Fields.Add(&core.NumberField{Name: "alert_window_minutes", OnlyInt: true, Min: &minWindow, Max: &maxWindow})
+		return app.Save(cases)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("alert_mode")
+		cases.Fields.RemoveByName("alert_success_rate")
+		cases.Fields.RemoveByName("alert_window_minutes")
+		return app.Save(cases)
+	})
+}
@@ -0,0 +1,54 @@
+// api_test_cases 增加 probe_type（http 或 tcp 连接延迟探测）与 max_latency_ms（延迟阈值），
+// api_test_case_stats 增加 p50_duration_ms 与 p99_duration_ms。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		minZero := 0.0
+		maxLatency := 120000.0
+		cases.Fields.Add(&core.SelectField{
+			Name:      "probe_type",
+			MaxSelect: 1,
+			Values:    []string{"http", "tcp"},
+		})
+		cases.Fields.Add(&core.NumberField{Name: "max_latency_ms", OnlyInt: true, Min: &minZero, Max: &maxLatency})
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		stats, err := app.FindCollectionByNameOrId("api_test_case_stats")
+		if err != nil {
+			return err
+		}
+		stats.Fields.Add(&core.NumberField{Name: "p50_duration_ms", OnlyInt: true, Min: &minZero})
+		stats.Fields.Add(&core.NumberField{Name: "p99_duration_ms", OnlyInt: true, Min: &minZero})
+		return app.Save(stats)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("probe_type")
+		cases.Fields.RemoveByName("max_latency_ms")
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		stats, err := app.FindCollectionByNameOrId("api_test_case_stats")
+		if err != nil {
+			return err
+		}
+		stats.Fields.RemoveByName("p50_duration_ms")
+		stats.Fields.RemoveByName("p99_duration_ms")
+		return app.Save(stats)
+	})
+}
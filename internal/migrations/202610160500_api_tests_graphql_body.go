@@ -0,0 +1,47 @@
+// api_test_cases 的 body_type 字段新增 graphql 选项。
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		return updateApiTestBodyTypeValues(app, true)
+	}, func(app core.App) error {
+		return updateApiTestBodyTypeValues(app, false)
+	})
+}
+
+func updateApiTestBodyTypeValues(app core.App, add bool) error {
+	collection, err := app.FindCollectionByNameOrId("api_test_cases")
+	if err != nil {
+		return err
+	}
+
+	field := collection.Fields.GetByName("body_type")
+	selectField, ok := field.(*core.SelectField)
+	if !ok {
+		return fmt.Errorf("api_test_cases.body_type field is not a select field")
+	}
+
+	values := selectField.Values
+	if add {
+		if !stringSliceContains(values, "graphql") {
+			selectField.Values = append(values, "graphql")
+		}
+		return app.Save(collection)
+	}
+
+	filtered := values[:0]
+	for _, value := range values {
+		if value != "graphql" {
+			filtered = append(filtered, value)
+		}
+	}
+	selectField.Values = filtered
+	return app.Save(collection)
+}
@@ -0,0 +1,29 @@
+// api_test_cases 增加 expected_body_contains（响应体包含断言）与 expected_json_path / expected_json_value（JSON 路径断言）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.Add(&core.TextField{Name: "expected_body_contains", Max: 4096})
+		cases.Fields.Add(&core.TextField{Name: "expected_json_path", Max: 512})
+		cases.Fields.Add(&core.TextField{Name: "expected_json_value", Max: 4096})
+		return app.Save(cases)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("expected_body_contains")
+		cases.Fields.RemoveByName("expected_json_path")
+		cases.Fields.RemoveByName("expected_json_value")
+		return app.Save(cases)
+	})
+}
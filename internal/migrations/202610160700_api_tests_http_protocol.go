@@ -0,0 +1,47 @@
+// api_test_cases 增加 http_protocol（强制使用的 HTTP 协议版本），api_test_runs 增加 protocol（实际协商的协议）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.Add(&core.SelectField{
+			Name:      "http_protocol",
+			MaxSelect: 1,
+			Values:    []string{"http1", "http2", "http3"},
+		})
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.Add(&core.TextField{Name: "protocol", Max: 32})
+		return app.Save(runs)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("http_protocol")
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.RemoveByName("protocol")
+		return app.Save(runs)
+	})
+}
@@ -0,0 +1,25 @@
+// api_test_cases 增加 response_snippet_bytes 字段，按用例覆盖响应摘要长度（优先于合集的 snippet_bytes，受全局硬上限约束）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.Add(&core.NumberField{Name: "response_snippet_bytes", OnlyInt: true})
+		return app.Save(cases)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("response_snippet_bytes")
+		return app.Save(cases)
+	})
+}
@@ -0,0 +1,25 @@
+// api_test_runs 增加 response_headers 字段，保存执行时响应头的受限子集，便于排查失败原因。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.Add(&core.JSONField{Name: "response_headers", MaxSize: 8192})
+		return app.Save(runs)
+	}, func(app core.App) error {
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.RemoveByName("response_headers")
+		return app.Save(runs)
+	})
+}
@@ -0,0 +1,30 @@
+// api_test_cases 增加 retry_count 与 retry_delay_ms，失败后按固定间隔重试，只记录最终结果。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		minZero := 0.0
+		maxRetries := 5.0
+		maxDelay := 60000.0
+		cases.Fields.Add(&core.NumberField{Name: "retry_count", OnlyInt: true, Min: &minZero, Max: &maxRetries})
+		cases.Fields.Add(&core.NumberField{Name: "retry_delay_ms", OnlyInt: true, Min: &minZero, Max: &maxDelay})
+		return app.Save(cases)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("retry_count")
+		cases.Fields.RemoveByName("retry_delay_ms")
+		return app.Save(cases)
+	})
+}
@@ -0,0 +1,53 @@
+// api_test_cases 增加 health_weight（合集健康度中的权重），
+// api_test_collections 增加 health_window_minutes、health_alert_threshold 与 health_alert_triggered（合集健康度统计窗口与告警）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		minZero := 0.0
+		maxWeight := 100.0
+		maxWindow := float64(7 * 24 * 60)
+		maxScore := 100.0
+
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.Add(&core.NumberField{Name: "health_weight", OnlyInt: true, Min: &minZero, Max: &maxWeight})
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		collections, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+		collections.Fields.Add(&core.NumberField{Name: "health_window_minutes", OnlyInt: true, Min: &minZero, Max: &maxWindow})
+		collections.Fields.Add(&core.NumberField{Name: "health_alert_threshold", Min: &minZero, Max: &maxScore})
+		collections.Fields.Add(&core.BoolField{Name: "health_alert_triggered"})
+		return app.Save(collections)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("health_weight")
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		collections, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+		collections.Fields.RemoveByName("health_window_minutes")
+		collections.Fields.RemoveByName("health_alert_threshold")
+		collections.Fields.RemoveByName("health_alert_triggered")
+		return app.Save(collections)
+	})
+}
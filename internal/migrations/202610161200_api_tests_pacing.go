@@ -0,0 +1,27 @@
+// api_test_collections 增加 pacing_ms（批量执行时同一主机相邻请求的最小间隔）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+		minZero := 0.0
+		maxPacing := 60000.0
+		collection.Fields.Add(&core.NumberField{Name: "pacing_ms", OnlyInt: true, Min: &minZero, Max: &maxPacing})
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+		collection.Fields.RemoveByName("pacing_ms")
+		return app.Save(collection)
+	})
+}
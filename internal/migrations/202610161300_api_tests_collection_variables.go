@@ -0,0 +1,25 @@
+// api_test_collections 增加 variables（合集内用例共用的 {{name}} 变量，格式同 headers 的 key/value 列表）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+		collection.Fields.Add(&core.JSONField{Name: "variables"})
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+		collection.Fields.RemoveByName("variables")
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,25 @@
+// api_test_cases 增加 auto_content_length（按组装后的请求体长度设置 Content-Length 请求头）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		collection.Fields.Add(&core.BoolField{Name: "auto_content_length"})
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		collection.Fields.RemoveByName("auto_content_length")
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,66 @@
+// api_test_runs 与 api_test_cases 增加 tls_expires_at（HTTPS 叶子证书到期时间），
+// api_test_cases 增加 tls_expiry_alert_triggered，api_test_schedule_config 增加 tls_expiry_alert_days（证书到期告警提前天数）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.Add(&core.DateField{Name: "tls_expires_at"})
+		if err := app.Save(runs); err != nil {
+			return err
+		}
+
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.Add(&core.DateField{Name: "tls_expires_at"})
+		cases.Fields.Add(&core.BoolField{Name: "tls_expiry_alert_triggered"})
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		config, err := app.FindCollectionByNameOrId("api_test_schedule_config")
+		if err != nil {
+			return err
+		}
+		minZero := 0.0
+		maxDays := 365.0
+		config.Fields.Add(&core.NumberField{Name: "tls_expiry_alert_days", OnlyInt: true, Min: &minZero, Max: &maxDays})
+		return app.Save(config)
+	}, func(app core.App) error {
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.RemoveByName("tls_expires_at")
+		if err := app.Save(runs); err != nil {
+			return err
+		}
+
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("tls_expires_at")
+		cases.Fields.RemoveByName("tls_expiry_alert_triggered")
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+
+		config, err := app.FindCollectionByNameOrId("api_test_schedule_config")
+		if err != nil {
+			return err
+		}
+		config.Fields.RemoveByName("tls_expiry_alert_days")
+		return app.Save(config)
+	})
+}
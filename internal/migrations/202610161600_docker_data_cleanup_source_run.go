@@ -0,0 +1,29 @@
+// docker_data_cleanup_runs 增加 source_run（按覆盖目标重新执行时关联的原运行）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("docker_data_cleanup_runs")
+		if err != nil {
+			return err
+		}
+		collection.Fields.Add(&core.RelationField{
+			Name:         "source_run",
+			CollectionId: collection.Id,
+			MaxSelect:    1,
+		})
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("docker_data_cleanup_runs")
+		if err != nil {
+			return err
+		}
+		collection.Fields.RemoveByName("source_run")
+		return app.Save(collection)
+	})
+}
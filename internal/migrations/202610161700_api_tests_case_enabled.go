@@ -0,0 +1,38 @@
+// api_test_cases 增加 enabled（停用的用例不参与合集执行、全部执行与定时巡检），已有用例默认启用。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		collection.Fields.Add(&core.BoolField{Name: "enabled"})
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+		records, err := app.FindAllRecords(collection)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			record.Set("enabled", true)
+			if err := app.SaveNoValidate(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		collection.Fields.RemoveByName("enabled")
+		return app.Save(collection)
+	})
+}
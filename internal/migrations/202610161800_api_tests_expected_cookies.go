@@ -0,0 +1,25 @@
+// api_test_cases 增加 expected_cookies（响应 Set-Cookie 的存在性与属性断言）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		collection.Fields.Add(&core.JSONField{Name: "expected_cookies"})
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		collection.Fields.RemoveByName("expected_cookies")
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,39 @@
+// api_test_cases 增加用例级认证配置（auth_type、auth_username、auth_password、auth_token）。
+// auth_password 与 auth_token 加密存储且不随接口返回。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.Add(&core.SelectField{
+			Name:      "auth_type",
+			MaxSelect: 1,
+			Values:    []string{"none", "basic", "bearer"},
+		})
+		collection.Fields.Add(&core.TextField{Name: "auth_username"})
+		collection.Fields.Add(&core.TextField{Name: "auth_password", Hidden: true})
+		collection.Fields.Add(&core.TextField{Name: "auth_token", Hidden: true})
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+
+		for _, name := range []string{"auth_type", "auth_username", "auth_password", "auth_token"} {
+			collection.Fields.RemoveByName(name)
+		}
+
+		return app.Save(collection)
+	})
+}
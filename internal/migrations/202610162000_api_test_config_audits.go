@@ -0,0 +1,63 @@
+// 新增 api_test_config_audits（接口用例与合集的配置变更记录：变更人、变更字段及新旧值，敏感值已脱敏），
+// api_test_schedule_config 增加 audit_retention_days。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		config, err := app.FindCollectionByNameOrId("api_test_schedule_config")
+		if err != nil {
+			return err
+		}
+		minZero := 0.0
+		maxDays := 3650.0
+		config.Fields.Add(&core.NumberField{Name: "audit_retention_days", OnlyInt: true, Min: &minZero, Max: &maxDays})
+		if err := app.Save(config); err != nil {
+			return err
+		}
+
+		// 仅通过 /api-tests/audits 接口读取，不开放集合 API
+		collection := core.NewBaseCollection("api_test_config_audits")
+		collection.Fields.Add(&core.SelectField{
+			Name:      "resource_type",
+			Required:  true,
+			MaxSelect: 1,
+			Values:    []string{"case", "collection"},
+		})
+		collection.Fields.Add(&core.TextField{Name: "resource_id", Required: true})
+		collection.Fields.Add(&core.TextField{Name: "resource_name"})
+		collection.Fields.Add(&core.SelectField{
+			Name:      "action",
+			Required:  true,
+			MaxSelect: 1,
+			Values:    []string{"create", "update", "delete"},
+		})
+		collection.Fields.Add(&core.RelationField{
+			Name:         "user",
+			CollectionId: "_pb_users_auth_",
+			MaxSelect:    1,
+		})
+		collection.Fields.Add(&core.JSONField{Name: "changes"})
+		collection.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+		collection.Fields.Add(&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true})
+
+		collection.AddIndex("idx_api_test_config_audits_resource", false, "resource_type,resource_id,created", "")
+		collection.AddIndex("idx_api_test_config_audits_created", false, "created", "")
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		if err := deleteCollection(app, "api_test_config_audits"); err != nil {
+			return err
+		}
+		config, err := app.FindCollectionByNameOrId("api_test_schedule_config")
+		if err != nil {
+			return err
+		}
+		config.Fields.RemoveByName("audit_retention_days")
+		return app.Save(config)
+	})
+}
@@ -0,0 +1,25 @@
+// api_test_schedule_config 增加 webhook_url（接口告警的通用 Webhook 地址，为空表示不推送）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		config, err := app.FindCollectionByNameOrId("api_test_schedule_config")
+		if err != nil {
+			return err
+		}
+		config.Fields.Add(&core.TextField{Name: "webhook_url", Max: 2048})
+		return app.Save(config)
+	}, func(app core.App) error {
+		config, err := app.FindCollectionByNameOrId("api_test_schedule_config")
+		if err != nil {
+			return err
+		}
+		config.Fields.RemoveByName("webhook_url")
+		return app.Save(config)
+	})
+}
@@ -0,0 +1,27 @@
+// api_test_cases 增加 total_timeout_ms：包含重试在内的总超时，0 表示不限制（仍受单次超时与重试次数约束）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		minZero := 0.0
+		maxTotal := 600000.0
+		cases.Fields.Add(&core.NumberField{Name: "total_timeout_ms", OnlyInt: true, Min: &minZero, Max: &maxTotal})
+		return app.Save(cases)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("total_timeout_ms")
+		return app.Save(cases)
+	})
+}
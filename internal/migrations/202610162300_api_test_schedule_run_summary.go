@@ -0,0 +1,25 @@
+// api_test_schedule_config 增加 last_run_summary（最近一次定时巡检的执行与跳过统计，含跳过的用例及原因）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		config, err := app.FindCollectionByNameOrId("api_test_schedule_config")
+		if err != nil {
+			return err
+		}
+		config.Fields.Add(&core.JSONField{Name: "last_run_summary"})
+		return app.Save(config)
+	}, func(app core.App) error {
+		config, err := app.FindCollectionByNameOrId("api_test_schedule_config")
+		if err != nil {
+			return err
+		}
+		config.Fields.RemoveByName("last_run_summary")
+		return app.Save(config)
+	})
+}
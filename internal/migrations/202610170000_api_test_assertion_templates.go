@@ -0,0 +1,66 @@
+// 新增 api_test_assertion_templates（可复用的断言模板：JSON 校验、包含/反向包含、JSON 路径、延迟与响应大小限制），
+// api_test_cases 增加 assertion_templates（引用的断言模板，执行时与用例自身断言合并）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		templates := core.NewBaseCollection("api_test_assertion_templates")
+		authRule := "@request.auth.id != \"\""
+
+		templates.ListRule = &authRule
+		templates.ViewRule = &authRule
+		templates.CreateRule = &authRule
+		templates.UpdateRule = &authRule
+		templates.DeleteRule = &authRule
+
+		minZero := 0.0
+		maxLatency := 120000.0
+		templates.Fields.Add(&core.TextField{Name: "name", Required: true})
+		templates.Fields.Add(&core.TextField{Name: "description"})
+		templates.Fields.Add(&core.BoolField{Name: "require_json"})
+		templates.Fields.Add(&core.TextField{Name: "expected_body_contains"})
+		templates.Fields.Add(&core.TextField{Name: "not_contains"})
+		templates.Fields.Add(&core.BoolField{Name: "not_contains_regex"})
+		templates.Fields.Add(&core.TextField{Name: "expected_json_path"})
+		templates.Fields.Add(&core.TextField{Name: "expected_json_value"})
+		templates.Fields.Add(&core.NumberField{Name: "max_latency_ms", OnlyInt: true, Min: &minZero, Max: &maxLatency})
+		templates.Fields.Add(&core.NumberField{Name: "min_response_bytes", OnlyInt: true, Min: &minZero})
+		templates.Fields.Add(&core.NumberField{Name: "max_response_bytes", OnlyInt: true, Min: &minZero})
+		templates.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+		templates.Fields.Add(&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true})
+
+		// 导入导出按名称引用模板
+		templates.AddIndex("idx_api_test_assertion_templates_name", true, "name", "")
+
+		if err := app.Save(templates); err != nil {
+			return err
+		}
+
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		// 非必填关联：删除模板时自动从引用它的用例中移除
+		cases.Fields.Add(&core.RelationField{
+			Name:         "assertion_templates",
+			CollectionId: templates.Id,
+			MaxSelect:    10,
+		})
+		return app.Save(cases)
+	}, func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("assertion_templates")
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+		return deleteCollection(app, "api_test_assertion_templates")
+	})
+}
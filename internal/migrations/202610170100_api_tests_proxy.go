@@ -0,0 +1,25 @@
+// api_test_collections 增加 proxy_url（合集用例经该 HTTP/SOCKS5 代理发出请求，为空时不使用代理）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+		collection.Fields.Add(&core.TextField{Name: "proxy_url"})
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+		collection.Fields.RemoveByName("proxy_url")
+		return app.Save(collection)
+	})
+}
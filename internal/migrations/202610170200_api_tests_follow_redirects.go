@@ -0,0 +1,53 @@
+// api_test_cases 增加 follow_redirects（是否跟随重定向，已有用例默认跟随），
+// api_test_runs 增加 final_url（跟随重定向后最终到达的地址，未发生重定向时为空）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.Add(&core.BoolField{Name: "follow_redirects"})
+		if err := app.Save(cases); err != nil {
+			return err
+		}
+		records, err := app.FindAllRecords(cases)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			record.Set("follow_redirects", true)
+			if err := app.SaveNoValidate(record); err != nil {
+				return err
+			}
+		}
+
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.Add(&core.TextField{Name: "final_url"})
+		return app.Save(runs)
+	}, func(app core.App) error {
+		runs, err := app.FindCollectionByNameOrId("api_test_runs")
+		if err != nil {
+			return err
+		}
+		runs.Fields.RemoveByName("final_url")
+		if err := app.Save(runs); err != nil {
+			return err
+		}
+		cases, err := app.FindCollectionByNameOrId("api_test_cases")
+		if err != nil {
+			return err
+		}
+		cases.Fields.RemoveByName("follow_redirects")
+		return app.Save(cases)
+	})
+}
@@ -0,0 +1,53 @@
+// 新增 docker_data_cleanup_schedules（按系统的数据清理定时配置，每个系统一条记录，仅通过接口写入）。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		runCollection, err := app.FindCollectionByNameOrId("docker_data_cleanup_runs")
+		if err != nil {
+			return err
+		}
+		collection := core.NewBaseCollection("docker_data_cleanup_schedules")
+		listRule := "@request.auth.id != \"\" && system.users.id ?= @request.auth.id"
+
+		collection.ListRule = &listRule
+		collection.ViewRule = &listRule
+
+		collection.Fields.Add(&core.RelationField{
+			Name:          "system",
+			CollectionId:  "2hz5ncl8tizk5nx",
+			Required:      true,
+			MaxSelect:     1,
+			CascadeDelete: true,
+		})
+		collection.Fields.Add(&core.BoolField{Name: "enabled"})
+		collection.Fields.Add(&core.NumberField{Name: "interval_minutes", OnlyInt: true})
+		collection.Fields.Add(&core.TextField{Name: "time_of_day", Max: 5})
+		collection.Fields.Add(&core.DateField{Name: "last_run_at"})
+		collection.Fields.Add(&core.DateField{Name: "next_run_at"})
+		collection.Fields.Add(&core.RelationField{
+			Name:         "last_run",
+			CollectionId: runCollection.Id,
+			MaxSelect:    1,
+		})
+		collection.Fields.Add(&core.TextField{Name: "last_error"})
+		collection.Fields.Add(&core.RelationField{
+			Name:         "updated_by",
+			CollectionId: "_pb_users_auth_",
+			MaxSelect:    1,
+		})
+		collection.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+		collection.Fields.Add(&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true})
+
+		collection.AddIndex("idx_docker_data_cleanup_schedules_system", true, "system", "")
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		return deleteCollection(app, "docker_data_cleanup_schedules")
+	})
+}
@@ -0,0 +1,50 @@
+// 放宽接口测试错误与响应摘要字段的长度上限。TextField 未设置 Max 时 PocketBase 限制为 5000 字符，
+// 而响应摘要可配置到 64KiB（按字节截断，字符数不超过字节数），错误信息也可能超过 5000 字符。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+const (
+	// apiTestErrorMaxChars 与 hub 中 apiTestMaxStoredErrorChars 一致
+	apiTestErrorMaxChars = 16 << 10
+	// apiTestSnippetMaxChars 与 hub 中 apiTestMaxStoredSnippetChars 一致，为摘要硬上限 64KiB 的两倍
+	apiTestSnippetMaxChars = 128 << 10
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		return setApiTestTextLimits(app, apiTestErrorMaxChars, apiTestSnippetMaxChars)
+	}, func(app core.App) error {
+		return setApiTestTextLimits(app, 0, 0)
+	})
+}
+
+// setApiTestTextLimits 设置错误与摘要字段的 Max，0 恢复为 PocketBase 的默认上限。
+func setApiTestTextLimits(app core.App, errorMax int, snippetMax int) error {
+	limits := []struct {
+		collection string
+		fields     map[string]int
+	}{
+		{"api_test_cases", map[string]int{"last_error": errorMax, "last_response_snippet": snippetMax}},
+		{"api_test_runs", map[string]int{"error": errorMax, "response_snippet": snippetMax}},
+		{"api_test_schedule_config", map[string]int{"last_error": errorMax}},
+	}
+	for _, limit := range limits {
+		collection, err := app.FindCollectionByNameOrId(limit.collection)
+		if err != nil {
+			return err
+		}
+		for name, maxChars := range limit.fields {
+			if field, ok := collection.Fields.GetByName(name).(*core.TextField); ok {
+				field.Max = maxChars
+			}
+		}
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+// api_test_collections 增加 proxy_password（代理密码加密存储且不随接口返回，proxy_url 只保留用户名）。
+// 旧版本保存在 proxy_url 中的明文密码由 hub 启动时加密移入该字段，迁移本身无法取得加密密钥。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+		collection.Fields.Add(&core.TextField{Name: "proxy_password", Hidden: true})
+		return app.Save(collection)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("api_test_collections")
+		if err != nil {
+			return err
+		}
+		collection.Fields.RemoveByName("proxy_password")
+		return app.Save(collection)
+	})
+}
@@ -0,0 +1,49 @@
+// docker_data_cleanup_runs 增加 triggered_by（发起运行的用户，定时运行为启用定时的用户）与 dry_run，
+// hub 重启或 agent 重连后接续的运行据此写入审计。docker_audits.user 改为可选，为空表示由系统发起。
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("docker_data_cleanup_runs")
+		if err != nil {
+			return err
+		}
+		collection.Fields.Add(&core.RelationField{
+			Name:         "triggered_by",
+			CollectionId: "_pb_users_auth_",
+			MaxSelect:    1,
+		})
+		collection.Fields.Add(&core.BoolField{Name: "dry_run"})
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+		return setDockerAuditUserRequired(app, false)
+	}, func(app core.App) error {
+		collection, err := app.FindCollectionByNameOrId("docker_data_cleanup_runs")
+		if err != nil {
+			return err
+		}
+		collection.Fields.RemoveByName("triggered_by")
+		collection.Fields.RemoveByName("dry_run")
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+		return setDockerAuditUserRequired(app, true)
+	})
+}
+
+func setDockerAuditUserRequired(app core.App, required bool) error {
+	collection, err := app.FindCollectionByNameOrId("docker_audits")
+	if err != nil {
+		return err
+	}
+	if field, ok := collection.Fields.GetByName("user").(*core.RelationField); ok {
+		field.Required = required
+	}
+	return app.Save(collection)
+}
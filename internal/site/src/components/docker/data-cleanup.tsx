@@ -771,7 +771,7 @@ export default memo(function DockerDataCleanupPanel({ systemId }: { systemId?: s
 				setConfirmOpen(false)
 				return
 			}
-			const res = await startDockerDataCleanupRun({ system: systemId })
+			const res = await startDockerDataCleanupRun({ system: systemId, confirm: true })
 			setRunId(res.runId)
 			setRunStatus("pending")
 			setRunProgress(0)
@@ -793,7 +793,7 @@ export default memo(function DockerDataCleanupPanel({ systemId }: { systemId?: s
 		if (!systemId) return
 		setRunLoading(true)
 		try {
-			const res = await retryDockerDataCleanupRun({ system: systemId })
+			const res = await retryDockerDataCleanupRun({ system: systemId, confirm: true })
 			setRunId(res.runId)
 			setRunStatus("pending")
 			setRunProgress(0)
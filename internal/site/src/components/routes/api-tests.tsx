@@ -103,7 +103,7 @@ type CaseDraft = {
 }
 
 const methodOptions: ApiTestMethod[] = ["GET", "POST", "PUT", "DELETE", "PATCH", "HEAD"]
-const bodyTypeOptions: ApiTestBodyType[] = ["json", "text", "form"]
+const bodyTypeOptions: ApiTestBodyType[] = ["json", "text", "form", "graphql"]
 const ALL_FILTER_VALUE = "__all__"
 
 const toFilterSelectValue = (value: string) => (value ? value : ALL_FILTER_VALUE)
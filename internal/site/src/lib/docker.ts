@@ -280,7 +280,7 @@ export const listDockerDataCleanupESIndices = (payload: DataCleanupListPayload)
 		body: payload,
 	})
 
-export const startDockerDataCleanupRun = (payload: { system: string }) =>
+export const startDockerDataCleanupRun = (payload: { system: string; confirm: boolean }) =>
 	pb.send<{ runId: string }>("/api/aether/docker/data-cleanup/run", {
 		method: "POST",
 		body: payload,
@@ -291,7 +291,7 @@ export const fetchDockerDataCleanupRun = (runId: string) =>
 		query: { id: runId },
 	})
 
-export const retryDockerDataCleanupRun = (payload: { system: string }) =>
+export const retryDockerDataCleanupRun = (payload: { system: string; confirm: boolean }) =>
 	pb.send<{ runId: string }>("/api/aether/docker/data-cleanup/retry", {
 		method: "POST",
 		body: payload,
@@ -913,7 +913,7 @@ export interface SystemdServiceDetails {
 }
 
 export type ApiTestMethod = "GET" | "POST" | "PUT" | "DELETE" | "PATCH" | "HEAD"
-export type ApiTestBodyType = "json" | "text" | "form"
+export type ApiTestBodyType = "json" | "text" | "form" | "graphql"
 
 export interface ApiTestKeyValue {
 	key: string